@@ -0,0 +1,5 @@
+// Package trackerv1 holds the generated code of the gRPC API defined in
+// tracker.proto
+package trackerv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative tracker/v1/tracker.proto
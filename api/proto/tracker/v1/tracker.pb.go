@@ -0,0 +1,2345 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.28.3
+// source: tracker/v1/tracker.proto
+
+// Wallet tracker gRPC API. Token amounts are decimal strings of the raw
+// on-chain integer; formatted_* fields are scaled by the token's decimals.
+
+package trackerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AddWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WalletAddress string                 `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	// The user's name for the wallet
+	Label         string              `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Filters       *NotificationFilter `protobuf:"bytes,4,opt,name=filters,proto3" json:"filters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWalletRequest) Reset() {
+	*x = AddWalletRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWalletRequest) ProtoMessage() {}
+
+func (x *AddWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWalletRequest.ProtoReflect.Descriptor instead.
+func (*AddWalletRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddWalletRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddWalletRequest) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *AddWalletRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *AddWalletRequest) GetFilters() *NotificationFilter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type AddWalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWalletResponse) Reset() {
+	*x = AddWalletResponse{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWalletResponse) ProtoMessage() {}
+
+func (x *AddWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWalletResponse.ProtoReflect.Descriptor instead.
+func (*AddWalletResponse) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{1}
+}
+
+type RemoveWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WalletAddress string                 `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWalletRequest) Reset() {
+	*x = RemoveWalletRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWalletRequest) ProtoMessage() {}
+
+func (x *RemoveWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWalletRequest.ProtoReflect.Descriptor instead.
+func (*RemoveWalletRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemoveWalletRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RemoveWalletRequest) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+type RemoveWalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWalletResponse) Reset() {
+	*x = RemoveWalletResponse{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWalletResponse) ProtoMessage() {}
+
+func (x *RemoveWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWalletResponse.ProtoReflect.Descriptor instead.
+func (*RemoveWalletResponse) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{3}
+}
+
+type ListWalletsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletsRequest) Reset() {
+	*x = ListWalletsRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletsRequest) ProtoMessage() {}
+
+func (x *ListWalletsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletsRequest.ProtoReflect.Descriptor instead.
+func (*ListWalletsRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListWalletsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type ListWalletsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*WalletSubscription  `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletsResponse) Reset() {
+	*x = ListWalletsResponse{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletsResponse) ProtoMessage() {}
+
+func (x *ListWalletsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletsResponse.ProtoReflect.Descriptor instead.
+func (*ListWalletsResponse) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListWalletsResponse) GetSubscriptions() []*WalletSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscribeRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// NotificationFilter narrows the transfers a subscription is notified about
+type NotificationFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "in", "out" or empty for both
+	Direction string `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"`
+	// Token contracts to report; empty reports every token. Native XPL is
+	// the zero address.
+	Tokens []string `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	// Smallest native transfer reported, in XPL, e.g. "0.5"
+	MinNativeAmount string `protobuf:"bytes,3,opt,name=min_native_amount,json=minNativeAmount,proto3" json:"min_native_amount,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NotificationFilter) Reset() {
+	*x = NotificationFilter{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationFilter) ProtoMessage() {}
+
+func (x *NotificationFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationFilter.ProtoReflect.Descriptor instead.
+func (*NotificationFilter) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NotificationFilter) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *NotificationFilter) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *NotificationFilter) GetMinNativeAmount() string {
+	if x != nil {
+		return x.MinNativeAmount
+	}
+	return ""
+}
+
+type WalletSubscription struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletAddress string                 `protobuf:"bytes,1,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Filters       *NotificationFilter    `protobuf:"bytes,3,opt,name=filters,proto3" json:"filters,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Notifications are batched into one digest per window if set
+	DigestWindow *durationpb.Duration `protobuf:"bytes,6,opt,name=digest_window,json=digestWindow,proto3" json:"digest_window,omitempty"`
+	Paused       bool                 `protobuf:"varint,7,opt,name=paused,proto3" json:"paused,omitempty"`
+	// Set if the pause ends by itself
+	PausedUntil   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=paused_until,json=pausedUntil,proto3" json:"paused_until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletSubscription) Reset() {
+	*x = WalletSubscription{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletSubscription) ProtoMessage() {}
+
+func (x *WalletSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletSubscription.ProtoReflect.Descriptor instead.
+func (*WalletSubscription) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WalletSubscription) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *WalletSubscription) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *WalletSubscription) GetFilters() *NotificationFilter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *WalletSubscription) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *WalletSubscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WalletSubscription) GetDigestWindow() *durationpb.Duration {
+	if x != nil {
+		return x.DigestWindow
+	}
+	return nil
+}
+
+func (x *WalletSubscription) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *WalletSubscription) GetPausedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PausedUntil
+	}
+	return nil
+}
+
+// WalletNotification reports activity of a watched wallet to one user
+type WalletNotification struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// transaction, reorg, approval, swap, bridge_in, bridge_out, digest,
+	// rate_limited or summary
+	Type          string       `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	WalletAddress string       `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	Transaction   *Transaction `protobuf:"bytes,3,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	// Only transfers involving the wallet
+	Transfers []*Transfer            `protobuf:"bytes,4,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The same for every delivery of the same notification
+	IdempotencyKey string `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// The user's name for the wallet and the groups they put it in
+	WalletLabel   string   `protobuf:"bytes,7,opt,name=wallet_label,json=walletLabel,proto3" json:"wallet_label,omitempty"`
+	WalletGroups  []string `protobuf:"bytes,8,rep,name=wallet_groups,json=walletGroups,proto3" json:"wallet_groups,omitempty"`
+	Confirmations uint64   `protobuf:"varint,9,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	// Sent before the configured confirmation depth
+	Unconfirmed bool `protobuf:"varint,10,opt,name=unconfirmed,proto3" json:"unconfirmed,omitempty"`
+	// Replayed by the backfill on add_wallet
+	Historical bool `protobuf:"varint,11,opt,name=historical,proto3" json:"historical,omitempty"`
+	// Reverted transaction
+	Failed bool `protobuf:"varint,12,opt,name=failed,proto3" json:"failed,omitempty"`
+	// The wallet didn't pay the fee
+	Gasless   bool              `protobuf:"varint,13,opt,name=gasless,proto3" json:"gasless,omitempty"`
+	Approvals []*Approval       `protobuf:"bytes,14,rep,name=approvals,proto3" json:"approvals,omitempty"`
+	Swaps     []*Swap           `protobuf:"bytes,15,rep,name=swaps,proto3" json:"swaps,omitempty"`
+	Bridges   []*BridgeTransfer `protobuf:"bytes,16,rep,name=bridges,proto3" json:"bridges,omitempty"`
+	// Set on rate_limited notifications: notifications the user didn't get
+	Suppressed int64 `protobuf:"varint,17,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+	// Set on digest notifications
+	Digest *Digest `protobuf:"bytes,18,opt,name=digest,proto3" json:"digest,omitempty"`
+	// Set on reorg notifications
+	Reorg          *Reorg   `protobuf:"bytes,19,opt,name=reorg,proto3" json:"reorg,omitempty"`
+	InvalidatedTxs []string `protobuf:"bytes,20,rep,name=invalidated_txs,json=invalidatedTxs,proto3" json:"invalidated_txs,omitempty"`
+	// Set on summary notifications
+	Summary       *Summary `protobuf:"bytes,21,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletNotification) Reset() {
+	*x = WalletNotification{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletNotification) ProtoMessage() {}
+
+func (x *WalletNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletNotification.ProtoReflect.Descriptor instead.
+func (*WalletNotification) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WalletNotification) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WalletNotification) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *WalletNotification) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetTransfers() []*Transfer {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *WalletNotification) GetWalletLabel() string {
+	if x != nil {
+		return x.WalletLabel
+	}
+	return ""
+}
+
+func (x *WalletNotification) GetWalletGroups() []string {
+	if x != nil {
+		return x.WalletGroups
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetConfirmations() uint64 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *WalletNotification) GetUnconfirmed() bool {
+	if x != nil {
+		return x.Unconfirmed
+	}
+	return false
+}
+
+func (x *WalletNotification) GetHistorical() bool {
+	if x != nil {
+		return x.Historical
+	}
+	return false
+}
+
+func (x *WalletNotification) GetFailed() bool {
+	if x != nil {
+		return x.Failed
+	}
+	return false
+}
+
+func (x *WalletNotification) GetGasless() bool {
+	if x != nil {
+		return x.Gasless
+	}
+	return false
+}
+
+func (x *WalletNotification) GetApprovals() []*Approval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetSwaps() []*Swap {
+	if x != nil {
+		return x.Swaps
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetBridges() []*BridgeTransfer {
+	if x != nil {
+		return x.Bridges
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetSuppressed() int64 {
+	if x != nil {
+		return x.Suppressed
+	}
+	return 0
+}
+
+func (x *WalletNotification) GetDigest() *Digest {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetReorg() *Reorg {
+	if x != nil {
+		return x.Reorg
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetInvalidatedTxs() []string {
+	if x != nil {
+		return x.InvalidatedTxs
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetSummary() *Summary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type Transaction struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Hash  string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// EIP-2718 envelope: legacy, access_list, dynamic_fee, blob or set_code
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// transfer, contract_call or contract_creation
+	Kind string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	// success or reverted
+	Status       string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	RevertReason string `protobuf:"bytes,5,opt,name=revert_reason,json=revertReason,proto3" json:"revert_reason,omitempty"`
+	From         string `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	To           string `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	// Deployed contract, for contract creations
+	Contract string `protobuf:"bytes,8,opt,name=contract,proto3" json:"contract,omitempty"`
+	// Invoked function, for contract calls
+	Method      *MethodCall            `protobuf:"bytes,9,opt,name=method,proto3" json:"method,omitempty"`
+	Nonce       uint64                 `protobuf:"varint,10,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	BlockNumber uint64                 `protobuf:"varint,11,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	GasUsed     uint64                 `protobuf:"varint,13,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	// Price bid; the fee cap of EIP-1559 transactions
+	GasPrice  string `protobuf:"bytes,14,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	Gasless   bool   `protobuf:"varint,15,opt,name=gasless,proto3" json:"gasless,omitempty"`
+	Paymaster string `protobuf:"bytes,16,opt,name=paymaster,proto3" json:"paymaster,omitempty"`
+	FeePayer  string `protobuf:"bytes,17,opt,name=fee_payer,json=feePayer,proto3" json:"fee_payer,omitempty"`
+	// Price charged, base fee plus tip under EIP-1559
+	GasPricePaid string `protobuf:"bytes,18,opt,name=gas_price_paid,json=gasPricePaid,proto3" json:"gas_price_paid,omitempty"`
+	// gas_used × gas_price_paid, in wei
+	Fee           string `protobuf:"bytes,19,opt,name=fee,proto3" json:"fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Transaction) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Transaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transaction) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Transaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transaction) GetRevertReason() string {
+	if x != nil {
+		return x.RevertReason
+	}
+	return ""
+}
+
+func (x *Transaction) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Transaction) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Transaction) GetContract() string {
+	if x != nil {
+		return x.Contract
+	}
+	return ""
+}
+
+func (x *Transaction) GetMethod() *MethodCall {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+func (x *Transaction) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Transaction) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Transaction) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Transaction) GetGasUsed() uint64 {
+	if x != nil {
+		return x.GasUsed
+	}
+	return 0
+}
+
+func (x *Transaction) GetGasPrice() string {
+	if x != nil {
+		return x.GasPrice
+	}
+	return ""
+}
+
+func (x *Transaction) GetGasless() bool {
+	if x != nil {
+		return x.Gasless
+	}
+	return false
+}
+
+func (x *Transaction) GetPaymaster() string {
+	if x != nil {
+		return x.Paymaster
+	}
+	return ""
+}
+
+func (x *Transaction) GetFeePayer() string {
+	if x != nil {
+		return x.FeePayer
+	}
+	return ""
+}
+
+func (x *Transaction) GetGasPricePaid() string {
+	if x != nil {
+		return x.GasPricePaid
+	}
+	return ""
+}
+
+func (x *Transaction) GetFee() string {
+	if x != nil {
+		return x.Fee
+	}
+	return ""
+}
+
+type MethodCall struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g. 0xa9059cbb
+	Selector string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// e.g. transfer(address,uint256), if known
+	Signature string `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Decoded arguments as a JSON object, when the contract's ABI is known
+	ArgsJson      string `protobuf:"bytes,3,opt,name=args_json,json=argsJson,proto3" json:"args_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MethodCall) Reset() {
+	*x = MethodCall{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodCall) ProtoMessage() {}
+
+func (x *MethodCall) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodCall.ProtoReflect.Descriptor instead.
+func (*MethodCall) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MethodCall) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *MethodCall) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *MethodCall) GetArgsJson() string {
+	if x != nil {
+		return x.ArgsJson
+	}
+	return ""
+}
+
+type Transfer struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TxHash         string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	From           string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To             string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Value          string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	FormattedValue string                 `protobuf:"bytes,5,opt,name=formatted_value,json=formattedValue,proto3" json:"formatted_value,omitempty"`
+	ValueUsd       string                 `protobuf:"bytes,6,opt,name=value_usd,json=valueUsd,proto3" json:"value_usd,omitempty"`
+	TokenSymbol    string                 `protobuf:"bytes,7,opt,name=token_symbol,json=tokenSymbol,proto3" json:"token_symbol,omitempty"`
+	TokenAddress   string                 `protobuf:"bytes,8,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	// native, erc20 or erc721
+	TokenStandard string `protobuf:"bytes,9,opt,name=token_standard,json=tokenStandard,proto3" json:"token_standard,omitempty"`
+	// ERC-721 only
+	TokenId  string `protobuf:"bytes,10,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	LogIndex int64  `protobuf:"varint,11,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	// Value moved by a contract call
+	Internal      bool   `protobuf:"varint,12,opt,name=internal,proto3" json:"internal,omitempty"`
+	FromLabel     string `protobuf:"bytes,13,opt,name=from_label,json=fromLabel,proto3" json:"from_label,omitempty"`
+	ToLabel       string `protobuf:"bytes,14,opt,name=to_label,json=toLabel,proto3" json:"to_label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transfer) Reset() {
+	*x = Transfer{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transfer) ProtoMessage() {}
+
+func (x *Transfer) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Transfer) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *Transfer) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Transfer) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Transfer) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Transfer) GetFormattedValue() string {
+	if x != nil {
+		return x.FormattedValue
+	}
+	return ""
+}
+
+func (x *Transfer) GetValueUsd() string {
+	if x != nil {
+		return x.ValueUsd
+	}
+	return ""
+}
+
+func (x *Transfer) GetTokenSymbol() string {
+	if x != nil {
+		return x.TokenSymbol
+	}
+	return ""
+}
+
+func (x *Transfer) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *Transfer) GetTokenStandard() string {
+	if x != nil {
+		return x.TokenStandard
+	}
+	return ""
+}
+
+func (x *Transfer) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *Transfer) GetLogIndex() int64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *Transfer) GetInternal() bool {
+	if x != nil {
+		return x.Internal
+	}
+	return false
+}
+
+func (x *Transfer) GetFromLabel() string {
+	if x != nil {
+		return x.FromLabel
+	}
+	return ""
+}
+
+func (x *Transfer) GetToLabel() string {
+	if x != nil {
+		return x.ToLabel
+	}
+	return ""
+}
+
+type Approval struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TxHash          string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Owner           string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Spender         string                 `protobuf:"bytes,3,opt,name=spender,proto3" json:"spender,omitempty"`
+	SpenderLabel    string                 `protobuf:"bytes,4,opt,name=spender_label,json=spenderLabel,proto3" json:"spender_label,omitempty"`
+	TokenAddress    string                 `protobuf:"bytes,5,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	TokenSymbol     string                 `protobuf:"bytes,6,opt,name=token_symbol,json=tokenSymbol,proto3" json:"token_symbol,omitempty"`
+	Amount          string                 `protobuf:"bytes,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	FormattedAmount string                 `protobuf:"bytes,8,opt,name=formatted_amount,json=formattedAmount,proto3" json:"formatted_amount,omitempty"`
+	Unlimited       bool                   `protobuf:"varint,9,opt,name=unlimited,proto3" json:"unlimited,omitempty"`
+	Revocation      bool                   `protobuf:"varint,10,opt,name=revocation,proto3" json:"revocation,omitempty"`
+	LogIndex        int64                  `protobuf:"varint,11,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Approval) Reset() {
+	*x = Approval{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Approval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Approval) ProtoMessage() {}
+
+func (x *Approval) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Approval.ProtoReflect.Descriptor instead.
+func (*Approval) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Approval) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *Approval) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Approval) GetSpender() string {
+	if x != nil {
+		return x.Spender
+	}
+	return ""
+}
+
+func (x *Approval) GetSpenderLabel() string {
+	if x != nil {
+		return x.SpenderLabel
+	}
+	return ""
+}
+
+func (x *Approval) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *Approval) GetTokenSymbol() string {
+	if x != nil {
+		return x.TokenSymbol
+	}
+	return ""
+}
+
+func (x *Approval) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Approval) GetFormattedAmount() string {
+	if x != nil {
+		return x.FormattedAmount
+	}
+	return ""
+}
+
+func (x *Approval) GetUnlimited() bool {
+	if x != nil {
+		return x.Unlimited
+	}
+	return false
+}
+
+func (x *Approval) GetRevocation() bool {
+	if x != nil {
+		return x.Revocation
+	}
+	return false
+}
+
+func (x *Approval) GetLogIndex() int64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+type Swap struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TxHash string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	// uniswap_v2 or uniswap_v3
+	Protocol           string `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Pool               string `protobuf:"bytes,3,opt,name=pool,proto3" json:"pool,omitempty"`
+	Sender             string `protobuf:"bytes,4,opt,name=sender,proto3" json:"sender,omitempty"`
+	Recipient          string `protobuf:"bytes,5,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	TokenIn            string `protobuf:"bytes,6,opt,name=token_in,json=tokenIn,proto3" json:"token_in,omitempty"`
+	TokenInSymbol      string `protobuf:"bytes,7,opt,name=token_in_symbol,json=tokenInSymbol,proto3" json:"token_in_symbol,omitempty"`
+	AmountIn           string `protobuf:"bytes,8,opt,name=amount_in,json=amountIn,proto3" json:"amount_in,omitempty"`
+	FormattedAmountIn  string `protobuf:"bytes,9,opt,name=formatted_amount_in,json=formattedAmountIn,proto3" json:"formatted_amount_in,omitempty"`
+	TokenOut           string `protobuf:"bytes,10,opt,name=token_out,json=tokenOut,proto3" json:"token_out,omitempty"`
+	TokenOutSymbol     string `protobuf:"bytes,11,opt,name=token_out_symbol,json=tokenOutSymbol,proto3" json:"token_out_symbol,omitempty"`
+	AmountOut          string `protobuf:"bytes,12,opt,name=amount_out,json=amountOut,proto3" json:"amount_out,omitempty"`
+	FormattedAmountOut string `protobuf:"bytes,13,opt,name=formatted_amount_out,json=formattedAmountOut,proto3" json:"formatted_amount_out,omitempty"`
+	LogIndex           int64  `protobuf:"varint,14,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Swap) Reset() {
+	*x = Swap{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Swap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Swap) ProtoMessage() {}
+
+func (x *Swap) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Swap.ProtoReflect.Descriptor instead.
+func (*Swap) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Swap) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *Swap) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *Swap) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *Swap) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *Swap) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Swap) GetTokenIn() string {
+	if x != nil {
+		return x.TokenIn
+	}
+	return ""
+}
+
+func (x *Swap) GetTokenInSymbol() string {
+	if x != nil {
+		return x.TokenInSymbol
+	}
+	return ""
+}
+
+func (x *Swap) GetAmountIn() string {
+	if x != nil {
+		return x.AmountIn
+	}
+	return ""
+}
+
+func (x *Swap) GetFormattedAmountIn() string {
+	if x != nil {
+		return x.FormattedAmountIn
+	}
+	return ""
+}
+
+func (x *Swap) GetTokenOut() string {
+	if x != nil {
+		return x.TokenOut
+	}
+	return ""
+}
+
+func (x *Swap) GetTokenOutSymbol() string {
+	if x != nil {
+		return x.TokenOutSymbol
+	}
+	return ""
+}
+
+func (x *Swap) GetAmountOut() string {
+	if x != nil {
+		return x.AmountOut
+	}
+	return ""
+}
+
+func (x *Swap) GetFormattedAmountOut() string {
+	if x != nil {
+		return x.FormattedAmountOut
+	}
+	return ""
+}
+
+func (x *Swap) GetLogIndex() int64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+type BridgeTransfer struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TxHash string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	// in or out
+	Direction        string `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	Bridge           string `protobuf:"bytes,3,opt,name=bridge,proto3" json:"bridge,omitempty"`
+	Account          string `protobuf:"bytes,4,opt,name=account,proto3" json:"account,omitempty"`
+	TokenAddress     string `protobuf:"bytes,5,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	TokenSymbol      string `protobuf:"bytes,6,opt,name=token_symbol,json=tokenSymbol,proto3" json:"token_symbol,omitempty"`
+	Amount           string `protobuf:"bytes,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	FormattedAmount  string `protobuf:"bytes,8,opt,name=formatted_amount,json=formattedAmount,proto3" json:"formatted_amount,omitempty"`
+	SourceChain      string `protobuf:"bytes,9,opt,name=source_chain,json=sourceChain,proto3" json:"source_chain,omitempty"`
+	DestinationChain string `protobuf:"bytes,10,opt,name=destination_chain,json=destinationChain,proto3" json:"destination_chain,omitempty"`
+	// Cross-chain message ID, the same on both chains
+	TransferId    string `protobuf:"bytes,11,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	LogIndex      int64  `protobuf:"varint,12,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BridgeTransfer) Reset() {
+	*x = BridgeTransfer{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BridgeTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BridgeTransfer) ProtoMessage() {}
+
+func (x *BridgeTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BridgeTransfer.ProtoReflect.Descriptor instead.
+func (*BridgeTransfer) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BridgeTransfer) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetBridge() string {
+	if x != nil {
+		return x.Bridge
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetTokenSymbol() string {
+	if x != nil {
+		return x.TokenSymbol
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetFormattedAmount() string {
+	if x != nil {
+		return x.FormattedAmount
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetSourceChain() string {
+	if x != nil {
+		return x.SourceChain
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetDestinationChain() string {
+	if x != nil {
+		return x.DestinationChain
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *BridgeTransfer) GetLogIndex() int64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+type Digest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Transactions  int64                  `protobuf:"varint,3,opt,name=transactions,proto3" json:"transactions,omitempty"`
+	Failed        int64                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	Tokens        []*DigestTokenTotal    `protobuf:"bytes,5,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Digest) Reset() {
+	*x = Digest{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Digest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Digest) ProtoMessage() {}
+
+func (x *Digest) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Digest.ProtoReflect.Descriptor instead.
+func (*Digest) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Digest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Digest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Digest) GetTransactions() int64 {
+	if x != nil {
+		return x.Transactions
+	}
+	return 0
+}
+
+func (x *Digest) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *Digest) GetTokens() []*DigestTokenTotal {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type DigestTokenTotal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenAddress  string                 `protobuf:"bytes,1,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	TokenSymbol   string                 `protobuf:"bytes,2,opt,name=token_symbol,json=tokenSymbol,proto3" json:"token_symbol,omitempty"`
+	TokenStandard string                 `protobuf:"bytes,3,opt,name=token_standard,json=tokenStandard,proto3" json:"token_standard,omitempty"`
+	Incoming      int64                  `protobuf:"varint,4,opt,name=incoming,proto3" json:"incoming,omitempty"`
+	Outgoing      int64                  `protobuf:"varint,5,opt,name=outgoing,proto3" json:"outgoing,omitempty"`
+	ValueIn       string                 `protobuf:"bytes,6,opt,name=value_in,json=valueIn,proto3" json:"value_in,omitempty"`
+	ValueOut      string                 `protobuf:"bytes,7,opt,name=value_out,json=valueOut,proto3" json:"value_out,omitempty"`
+	ValueInUsd    string                 `protobuf:"bytes,8,opt,name=value_in_usd,json=valueInUsd,proto3" json:"value_in_usd,omitempty"`
+	ValueOutUsd   string                 `protobuf:"bytes,9,opt,name=value_out_usd,json=valueOutUsd,proto3" json:"value_out_usd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DigestTokenTotal) Reset() {
+	*x = DigestTokenTotal{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DigestTokenTotal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DigestTokenTotal) ProtoMessage() {}
+
+func (x *DigestTokenTotal) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DigestTokenTotal.ProtoReflect.Descriptor instead.
+func (*DigestTokenTotal) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DigestTokenTotal) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *DigestTokenTotal) GetTokenSymbol() string {
+	if x != nil {
+		return x.TokenSymbol
+	}
+	return ""
+}
+
+func (x *DigestTokenTotal) GetTokenStandard() string {
+	if x != nil {
+		return x.TokenStandard
+	}
+	return ""
+}
+
+func (x *DigestTokenTotal) GetIncoming() int64 {
+	if x != nil {
+		return x.Incoming
+	}
+	return 0
+}
+
+func (x *DigestTokenTotal) GetOutgoing() int64 {
+	if x != nil {
+		return x.Outgoing
+	}
+	return 0
+}
+
+func (x *DigestTokenTotal) GetValueIn() string {
+	if x != nil {
+		return x.ValueIn
+	}
+	return ""
+}
+
+func (x *DigestTokenTotal) GetValueOut() string {
+	if x != nil {
+		return x.ValueOut
+	}
+	return ""
+}
+
+func (x *DigestTokenTotal) GetValueInUsd() string {
+	if x != nil {
+		return x.ValueInUsd
+	}
+	return ""
+}
+
+func (x *DigestTokenTotal) GetValueOutUsd() string {
+	if x != nil {
+		return x.ValueOutUsd
+	}
+	return ""
+}
+
+type Summary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// daily or weekly
+	Period string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	From   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Wallets with activity, busiest first
+	Wallets       []*WalletSummary `protobuf:"bytes,4,rep,name=wallets,proto3" json:"wallets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Summary) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *Summary) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Summary) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Summary) GetWallets() []*WalletSummary {
+	if x != nil {
+		return x.Wallets
+	}
+	return nil
+}
+
+type WalletSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletAddress string                 `protobuf:"bytes,1,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Transactions  int64                  `protobuf:"varint,3,opt,name=transactions,proto3" json:"transactions,omitempty"`
+	Failed        int64                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	Flows         []*TokenFlow           `protobuf:"bytes,5,rep,name=flows,proto3" json:"flows,omitempty"`
+	// Largest in USD first, unpriced ones after them by raw amount
+	LargestTransfers []*Transfer `protobuf:"bytes,6,rep,name=largest_transfers,json=largestTransfers,proto3" json:"largest_transfers,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WalletSummary) Reset() {
+	*x = WalletSummary{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletSummary) ProtoMessage() {}
+
+func (x *WalletSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletSummary.ProtoReflect.Descriptor instead.
+func (*WalletSummary) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WalletSummary) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *WalletSummary) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *WalletSummary) GetTransactions() int64 {
+	if x != nil {
+		return x.Transactions
+	}
+	return 0
+}
+
+func (x *WalletSummary) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *WalletSummary) GetFlows() []*TokenFlow {
+	if x != nil {
+		return x.Flows
+	}
+	return nil
+}
+
+func (x *WalletSummary) GetLargestTransfers() []*Transfer {
+	if x != nil {
+		return x.LargestTransfers
+	}
+	return nil
+}
+
+// TokenFlow is the net amount of a token that moved into the wallet,
+// negative if more left it
+type TokenFlow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenAddress  string                 `protobuf:"bytes,1,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	TokenSymbol   string                 `protobuf:"bytes,2,opt,name=token_symbol,json=tokenSymbol,proto3" json:"token_symbol,omitempty"`
+	TokenStandard string                 `protobuf:"bytes,3,opt,name=token_standard,json=tokenStandard,proto3" json:"token_standard,omitempty"`
+	Net           string                 `protobuf:"bytes,4,opt,name=net,proto3" json:"net,omitempty"`
+	NetUsd        string                 `protobuf:"bytes,5,opt,name=net_usd,json=netUsd,proto3" json:"net_usd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenFlow) Reset() {
+	*x = TokenFlow{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenFlow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenFlow) ProtoMessage() {}
+
+func (x *TokenFlow) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenFlow.ProtoReflect.Descriptor instead.
+func (*TokenFlow) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TokenFlow) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *TokenFlow) GetTokenSymbol() string {
+	if x != nil {
+		return x.TokenSymbol
+	}
+	return ""
+}
+
+func (x *TokenFlow) GetTokenStandard() string {
+	if x != nil {
+		return x.TokenStandard
+	}
+	return ""
+}
+
+func (x *TokenFlow) GetNet() string {
+	if x != nil {
+		return x.Net
+	}
+	return ""
+}
+
+func (x *TokenFlow) GetNetUsd() string {
+	if x != nil {
+		return x.NetUsd
+	}
+	return ""
+}
+
+type Reorg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromBlock     uint64                 `protobuf:"varint,1,opt,name=from_block,json=fromBlock,proto3" json:"from_block,omitempty"`
+	ToBlock       uint64                 `protobuf:"varint,2,opt,name=to_block,json=toBlock,proto3" json:"to_block,omitempty"`
+	NewHead       uint64                 `protobuf:"varint,3,opt,name=new_head,json=newHead,proto3" json:"new_head,omitempty"`
+	DetectedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reorg) Reset() {
+	*x = Reorg{}
+	mi := &file_tracker_v1_tracker_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reorg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reorg) ProtoMessage() {}
+
+func (x *Reorg) ProtoReflect() protoreflect.Message {
+	mi := &file_tracker_v1_tracker_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reorg.ProtoReflect.Descriptor instead.
+func (*Reorg) Descriptor() ([]byte, []int) {
+	return file_tracker_v1_tracker_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Reorg) GetFromBlock() uint64 {
+	if x != nil {
+		return x.FromBlock
+	}
+	return 0
+}
+
+func (x *Reorg) GetToBlock() uint64 {
+	if x != nil {
+		return x.ToBlock
+	}
+	return 0
+}
+
+func (x *Reorg) GetNewHead() uint64 {
+	if x != nil {
+		return x.NewHead
+	}
+	return 0
+}
+
+func (x *Reorg) GetDetectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return nil
+}
+
+var File_tracker_v1_tracker_proto protoreflect.FileDescriptor
+
+const file_tracker_v1_tracker_proto_rawDesc = "" +
+	"\n" +
+	"\x18tracker/v1/tracker.proto\x12\n" +
+	"tracker.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x01\n" +
+	"\x10AddWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x128\n" +
+	"\afilters\x18\x04 \x01(\v2\x1e.tracker.v1.NotificationFilterR\afilters\"\x13\n" +
+	"\x11AddWalletResponse\"U\n" +
+	"\x13RemoveWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\"\x16\n" +
+	"\x14RemoveWalletResponse\"-\n" +
+	"\x12ListWalletsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"[\n" +
+	"\x13ListWalletsResponse\x12D\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1e.tracker.v1.WalletSubscriptionR\rsubscriptions\"+\n" +
+	"\x10SubscribeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"v\n" +
+	"\x12NotificationFilter\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\x12\x16\n" +
+	"\x06tokens\x18\x02 \x03(\tR\x06tokens\x12*\n" +
+	"\x11min_native_amount\x18\x03 \x01(\tR\x0fminNativeAmount\"\xf6\x02\n" +
+	"\x12WalletSubscription\x12%\n" +
+	"\x0ewallet_address\x18\x01 \x01(\tR\rwalletAddress\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x128\n" +
+	"\afilters\x18\x03 \x01(\v2\x1e.tracker.v1.NotificationFilterR\afilters\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12>\n" +
+	"\rdigest_window\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\fdigestWindow\x12\x16\n" +
+	"\x06paused\x18\a \x01(\bR\x06paused\x12=\n" +
+	"\fpaused_until\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vpausedUntil\"\xe2\x06\n" +
+	"\x12WalletNotification\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\x129\n" +
+	"\vtransaction\x18\x03 \x01(\v2\x17.tracker.v1.TransactionR\vtransaction\x122\n" +
+	"\ttransfers\x18\x04 \x03(\v2\x14.tracker.v1.TransferR\ttransfers\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12!\n" +
+	"\fwallet_label\x18\a \x01(\tR\vwalletLabel\x12#\n" +
+	"\rwallet_groups\x18\b \x03(\tR\fwalletGroups\x12$\n" +
+	"\rconfirmations\x18\t \x01(\x04R\rconfirmations\x12 \n" +
+	"\vunconfirmed\x18\n" +
+	" \x01(\bR\vunconfirmed\x12\x1e\n" +
+	"\n" +
+	"historical\x18\v \x01(\bR\n" +
+	"historical\x12\x16\n" +
+	"\x06failed\x18\f \x01(\bR\x06failed\x12\x18\n" +
+	"\agasless\x18\r \x01(\bR\agasless\x122\n" +
+	"\tapprovals\x18\x0e \x03(\v2\x14.tracker.v1.ApprovalR\tapprovals\x12&\n" +
+	"\x05swaps\x18\x0f \x03(\v2\x10.tracker.v1.SwapR\x05swaps\x124\n" +
+	"\abridges\x18\x10 \x03(\v2\x1a.tracker.v1.BridgeTransferR\abridges\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\x11 \x01(\x03R\n" +
+	"suppressed\x12*\n" +
+	"\x06digest\x18\x12 \x01(\v2\x12.tracker.v1.DigestR\x06digest\x12'\n" +
+	"\x05reorg\x18\x13 \x01(\v2\x11.tracker.v1.ReorgR\x05reorg\x12'\n" +
+	"\x0finvalidated_txs\x18\x14 \x03(\tR\x0einvalidatedTxs\x12-\n" +
+	"\asummary\x18\x15 \x01(\v2\x13.tracker.v1.SummaryR\asummary\"\xae\x04\n" +
+	"\vTransaction\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\rrevert_reason\x18\x05 \x01(\tR\frevertReason\x12\x12\n" +
+	"\x04from\x18\x06 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\a \x01(\tR\x02to\x12\x1a\n" +
+	"\bcontract\x18\b \x01(\tR\bcontract\x12.\n" +
+	"\x06method\x18\t \x01(\v2\x16.tracker.v1.MethodCallR\x06method\x12\x14\n" +
+	"\x05nonce\x18\n" +
+	" \x01(\x04R\x05nonce\x12!\n" +
+	"\fblock_number\x18\v \x01(\x04R\vblockNumber\x128\n" +
+	"\ttimestamp\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x19\n" +
+	"\bgas_used\x18\r \x01(\x04R\agasUsed\x12\x1b\n" +
+	"\tgas_price\x18\x0e \x01(\tR\bgasPrice\x12\x18\n" +
+	"\agasless\x18\x0f \x01(\bR\agasless\x12\x1c\n" +
+	"\tpaymaster\x18\x10 \x01(\tR\tpaymaster\x12\x1b\n" +
+	"\tfee_payer\x18\x11 \x01(\tR\bfeePayer\x12$\n" +
+	"\x0egas_price_paid\x18\x12 \x01(\tR\fgasPricePaid\x12\x10\n" +
+	"\x03fee\x18\x13 \x01(\tR\x03fee\"c\n" +
+	"\n" +
+	"MethodCall\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\tR\tsignature\x12\x1b\n" +
+	"\targs_json\x18\x03 \x01(\tR\bargsJson\"\xa0\x03\n" +
+	"\bTransfer\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12'\n" +
+	"\x0fformatted_value\x18\x05 \x01(\tR\x0eformattedValue\x12\x1b\n" +
+	"\tvalue_usd\x18\x06 \x01(\tR\bvalueUsd\x12!\n" +
+	"\ftoken_symbol\x18\a \x01(\tR\vtokenSymbol\x12#\n" +
+	"\rtoken_address\x18\b \x01(\tR\ftokenAddress\x12%\n" +
+	"\x0etoken_standard\x18\t \x01(\tR\rtokenStandard\x12\x19\n" +
+	"\btoken_id\x18\n" +
+	" \x01(\tR\atokenId\x12\x1b\n" +
+	"\tlog_index\x18\v \x01(\x03R\blogIndex\x12\x1a\n" +
+	"\binternal\x18\f \x01(\bR\binternal\x12\x1d\n" +
+	"\n" +
+	"from_label\x18\r \x01(\tR\tfromLabel\x12\x19\n" +
+	"\bto_label\x18\x0e \x01(\tR\atoLabel\"\xde\x02\n" +
+	"\bApproval\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x18\n" +
+	"\aspender\x18\x03 \x01(\tR\aspender\x12#\n" +
+	"\rspender_label\x18\x04 \x01(\tR\fspenderLabel\x12#\n" +
+	"\rtoken_address\x18\x05 \x01(\tR\ftokenAddress\x12!\n" +
+	"\ftoken_symbol\x18\x06 \x01(\tR\vtokenSymbol\x12\x16\n" +
+	"\x06amount\x18\a \x01(\tR\x06amount\x12)\n" +
+	"\x10formatted_amount\x18\b \x01(\tR\x0fformattedAmount\x12\x1c\n" +
+	"\tunlimited\x18\t \x01(\bR\tunlimited\x12\x1e\n" +
+	"\n" +
+	"revocation\x18\n" +
+	" \x01(\bR\n" +
+	"revocation\x12\x1b\n" +
+	"\tlog_index\x18\v \x01(\x03R\blogIndex\"\xca\x03\n" +
+	"\x04Swap\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x12\n" +
+	"\x04pool\x18\x03 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06sender\x18\x04 \x01(\tR\x06sender\x12\x1c\n" +
+	"\trecipient\x18\x05 \x01(\tR\trecipient\x12\x19\n" +
+	"\btoken_in\x18\x06 \x01(\tR\atokenIn\x12&\n" +
+	"\x0ftoken_in_symbol\x18\a \x01(\tR\rtokenInSymbol\x12\x1b\n" +
+	"\tamount_in\x18\b \x01(\tR\bamountIn\x12.\n" +
+	"\x13formatted_amount_in\x18\t \x01(\tR\x11formattedAmountIn\x12\x1b\n" +
+	"\ttoken_out\x18\n" +
+	" \x01(\tR\btokenOut\x12(\n" +
+	"\x10token_out_symbol\x18\v \x01(\tR\x0etokenOutSymbol\x12\x1d\n" +
+	"\n" +
+	"amount_out\x18\f \x01(\tR\tamountOut\x120\n" +
+	"\x14formatted_amount_out\x18\r \x01(\tR\x12formattedAmountOut\x12\x1b\n" +
+	"\tlog_index\x18\x0e \x01(\x03R\blogIndex\"\x92\x03\n" +
+	"\x0eBridgeTransfer\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x16\n" +
+	"\x06bridge\x18\x03 \x01(\tR\x06bridge\x12\x18\n" +
+	"\aaccount\x18\x04 \x01(\tR\aaccount\x12#\n" +
+	"\rtoken_address\x18\x05 \x01(\tR\ftokenAddress\x12!\n" +
+	"\ftoken_symbol\x18\x06 \x01(\tR\vtokenSymbol\x12\x16\n" +
+	"\x06amount\x18\a \x01(\tR\x06amount\x12)\n" +
+	"\x10formatted_amount\x18\b \x01(\tR\x0fformattedAmount\x12!\n" +
+	"\fsource_chain\x18\t \x01(\tR\vsourceChain\x12+\n" +
+	"\x11destination_chain\x18\n" +
+	" \x01(\tR\x10destinationChain\x12\x1f\n" +
+	"\vtransfer_id\x18\v \x01(\tR\n" +
+	"transferId\x12\x1b\n" +
+	"\tlog_index\x18\f \x01(\x03R\blogIndex\"\xd6\x01\n" +
+	"\x06Digest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\"\n" +
+	"\ftransactions\x18\x03 \x01(\x03R\ftransactions\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x03R\x06failed\x124\n" +
+	"\x06tokens\x18\x05 \x03(\v2\x1c.tracker.v1.DigestTokenTotalR\x06tokens\"\xb7\x02\n" +
+	"\x10DigestTokenTotal\x12#\n" +
+	"\rtoken_address\x18\x01 \x01(\tR\ftokenAddress\x12!\n" +
+	"\ftoken_symbol\x18\x02 \x01(\tR\vtokenSymbol\x12%\n" +
+	"\x0etoken_standard\x18\x03 \x01(\tR\rtokenStandard\x12\x1a\n" +
+	"\bincoming\x18\x04 \x01(\x03R\bincoming\x12\x1a\n" +
+	"\boutgoing\x18\x05 \x01(\x03R\boutgoing\x12\x19\n" +
+	"\bvalue_in\x18\x06 \x01(\tR\avalueIn\x12\x1b\n" +
+	"\tvalue_out\x18\a \x01(\tR\bvalueOut\x12 \n" +
+	"\fvalue_in_usd\x18\b \x01(\tR\n" +
+	"valueInUsd\x12\"\n" +
+	"\rvalue_out_usd\x18\t \x01(\tR\vvalueOutUsd\"\xb2\x01\n" +
+	"\aSummary\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x123\n" +
+	"\awallets\x18\x04 \x03(\v2\x19.tracker.v1.WalletSummaryR\awallets\"\xf8\x01\n" +
+	"\rWalletSummary\x12%\n" +
+	"\x0ewallet_address\x18\x01 \x01(\tR\rwalletAddress\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\"\n" +
+	"\ftransactions\x18\x03 \x01(\x03R\ftransactions\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x03R\x06failed\x12+\n" +
+	"\x05flows\x18\x05 \x03(\v2\x15.tracker.v1.TokenFlowR\x05flows\x12A\n" +
+	"\x11largest_transfers\x18\x06 \x03(\v2\x14.tracker.v1.TransferR\x10largestTransfers\"\xa5\x01\n" +
+	"\tTokenFlow\x12#\n" +
+	"\rtoken_address\x18\x01 \x01(\tR\ftokenAddress\x12!\n" +
+	"\ftoken_symbol\x18\x02 \x01(\tR\vtokenSymbol\x12%\n" +
+	"\x0etoken_standard\x18\x03 \x01(\tR\rtokenStandard\x12\x10\n" +
+	"\x03net\x18\x04 \x01(\tR\x03net\x12\x17\n" +
+	"\anet_usd\x18\x05 \x01(\tR\x06netUsd\"\x99\x01\n" +
+	"\x05Reorg\x12\x1d\n" +
+	"\n" +
+	"from_block\x18\x01 \x01(\x04R\tfromBlock\x12\x19\n" +
+	"\bto_block\x18\x02 \x01(\x04R\atoBlock\x12\x19\n" +
+	"\bnew_head\x18\x03 \x01(\x04R\anewHead\x12;\n" +
+	"\vdetected_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"detectedAt2\xc9\x02\n" +
+	"\rWalletTracker\x12H\n" +
+	"\tAddWallet\x12\x1c.tracker.v1.AddWalletRequest\x1a\x1d.tracker.v1.AddWalletResponse\x12Q\n" +
+	"\fRemoveWallet\x12\x1f.tracker.v1.RemoveWalletRequest\x1a .tracker.v1.RemoveWalletResponse\x12N\n" +
+	"\vListWallets\x12\x1e.tracker.v1.ListWalletsRequest\x1a\x1f.tracker.v1.ListWalletsResponse\x12K\n" +
+	"\tSubscribe\x12\x1c.tracker.v1.SubscribeRequest\x1a\x1e.tracker.v1.WalletNotification0\x01BHZFgithub.com/say8hi/plasma-wallet-tracker/api/proto/tracker/v1;trackerv1b\x06proto3"
+
+var (
+	file_tracker_v1_tracker_proto_rawDescOnce sync.Once
+	file_tracker_v1_tracker_proto_rawDescData []byte
+)
+
+func file_tracker_v1_tracker_proto_rawDescGZIP() []byte {
+	file_tracker_v1_tracker_proto_rawDescOnce.Do(func() {
+		file_tracker_v1_tracker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tracker_v1_tracker_proto_rawDesc), len(file_tracker_v1_tracker_proto_rawDesc)))
+	})
+	return file_tracker_v1_tracker_proto_rawDescData
+}
+
+var file_tracker_v1_tracker_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_tracker_v1_tracker_proto_goTypes = []any{
+	(*AddWalletRequest)(nil),      // 0: tracker.v1.AddWalletRequest
+	(*AddWalletResponse)(nil),     // 1: tracker.v1.AddWalletResponse
+	(*RemoveWalletRequest)(nil),   // 2: tracker.v1.RemoveWalletRequest
+	(*RemoveWalletResponse)(nil),  // 3: tracker.v1.RemoveWalletResponse
+	(*ListWalletsRequest)(nil),    // 4: tracker.v1.ListWalletsRequest
+	(*ListWalletsResponse)(nil),   // 5: tracker.v1.ListWalletsResponse
+	(*SubscribeRequest)(nil),      // 6: tracker.v1.SubscribeRequest
+	(*NotificationFilter)(nil),    // 7: tracker.v1.NotificationFilter
+	(*WalletSubscription)(nil),    // 8: tracker.v1.WalletSubscription
+	(*WalletNotification)(nil),    // 9: tracker.v1.WalletNotification
+	(*Transaction)(nil),           // 10: tracker.v1.Transaction
+	(*MethodCall)(nil),            // 11: tracker.v1.MethodCall
+	(*Transfer)(nil),              // 12: tracker.v1.Transfer
+	(*Approval)(nil),              // 13: tracker.v1.Approval
+	(*Swap)(nil),                  // 14: tracker.v1.Swap
+	(*BridgeTransfer)(nil),        // 15: tracker.v1.BridgeTransfer
+	(*Digest)(nil),                // 16: tracker.v1.Digest
+	(*DigestTokenTotal)(nil),      // 17: tracker.v1.DigestTokenTotal
+	(*Summary)(nil),               // 18: tracker.v1.Summary
+	(*WalletSummary)(nil),         // 19: tracker.v1.WalletSummary
+	(*TokenFlow)(nil),             // 20: tracker.v1.TokenFlow
+	(*Reorg)(nil),                 // 21: tracker.v1.Reorg
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 23: google.protobuf.Duration
+}
+var file_tracker_v1_tracker_proto_depIdxs = []int32{
+	7,  // 0: tracker.v1.AddWalletRequest.filters:type_name -> tracker.v1.NotificationFilter
+	8,  // 1: tracker.v1.ListWalletsResponse.subscriptions:type_name -> tracker.v1.WalletSubscription
+	7,  // 2: tracker.v1.WalletSubscription.filters:type_name -> tracker.v1.NotificationFilter
+	22, // 3: tracker.v1.WalletSubscription.created_at:type_name -> google.protobuf.Timestamp
+	23, // 4: tracker.v1.WalletSubscription.digest_window:type_name -> google.protobuf.Duration
+	22, // 5: tracker.v1.WalletSubscription.paused_until:type_name -> google.protobuf.Timestamp
+	10, // 6: tracker.v1.WalletNotification.transaction:type_name -> tracker.v1.Transaction
+	12, // 7: tracker.v1.WalletNotification.transfers:type_name -> tracker.v1.Transfer
+	22, // 8: tracker.v1.WalletNotification.timestamp:type_name -> google.protobuf.Timestamp
+	13, // 9: tracker.v1.WalletNotification.approvals:type_name -> tracker.v1.Approval
+	14, // 10: tracker.v1.WalletNotification.swaps:type_name -> tracker.v1.Swap
+	15, // 11: tracker.v1.WalletNotification.bridges:type_name -> tracker.v1.BridgeTransfer
+	16, // 12: tracker.v1.WalletNotification.digest:type_name -> tracker.v1.Digest
+	21, // 13: tracker.v1.WalletNotification.reorg:type_name -> tracker.v1.Reorg
+	18, // 14: tracker.v1.WalletNotification.summary:type_name -> tracker.v1.Summary
+	11, // 15: tracker.v1.Transaction.method:type_name -> tracker.v1.MethodCall
+	22, // 16: tracker.v1.Transaction.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 17: tracker.v1.Digest.from:type_name -> google.protobuf.Timestamp
+	22, // 18: tracker.v1.Digest.to:type_name -> google.protobuf.Timestamp
+	17, // 19: tracker.v1.Digest.tokens:type_name -> tracker.v1.DigestTokenTotal
+	22, // 20: tracker.v1.Summary.from:type_name -> google.protobuf.Timestamp
+	22, // 21: tracker.v1.Summary.to:type_name -> google.protobuf.Timestamp
+	19, // 22: tracker.v1.Summary.wallets:type_name -> tracker.v1.WalletSummary
+	20, // 23: tracker.v1.WalletSummary.flows:type_name -> tracker.v1.TokenFlow
+	12, // 24: tracker.v1.WalletSummary.largest_transfers:type_name -> tracker.v1.Transfer
+	22, // 25: tracker.v1.Reorg.detected_at:type_name -> google.protobuf.Timestamp
+	0,  // 26: tracker.v1.WalletTracker.AddWallet:input_type -> tracker.v1.AddWalletRequest
+	2,  // 27: tracker.v1.WalletTracker.RemoveWallet:input_type -> tracker.v1.RemoveWalletRequest
+	4,  // 28: tracker.v1.WalletTracker.ListWallets:input_type -> tracker.v1.ListWalletsRequest
+	6,  // 29: tracker.v1.WalletTracker.Subscribe:input_type -> tracker.v1.SubscribeRequest
+	1,  // 30: tracker.v1.WalletTracker.AddWallet:output_type -> tracker.v1.AddWalletResponse
+	3,  // 31: tracker.v1.WalletTracker.RemoveWallet:output_type -> tracker.v1.RemoveWalletResponse
+	5,  // 32: tracker.v1.WalletTracker.ListWallets:output_type -> tracker.v1.ListWalletsResponse
+	9,  // 33: tracker.v1.WalletTracker.Subscribe:output_type -> tracker.v1.WalletNotification
+	30, // [30:34] is the sub-list for method output_type
+	26, // [26:30] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_tracker_v1_tracker_proto_init() }
+func file_tracker_v1_tracker_proto_init() {
+	if File_tracker_v1_tracker_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tracker_v1_tracker_proto_rawDesc), len(file_tracker_v1_tracker_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tracker_v1_tracker_proto_goTypes,
+		DependencyIndexes: file_tracker_v1_tracker_proto_depIdxs,
+		MessageInfos:      file_tracker_v1_tracker_proto_msgTypes,
+	}.Build()
+	File_tracker_v1_tracker_proto = out.File
+	file_tracker_v1_tracker_proto_goTypes = nil
+	file_tracker_v1_tracker_proto_depIdxs = nil
+}
@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: tracker/v1/tracker.proto
+
+// Wallet tracker gRPC API. Token amounts are decimal strings of the raw
+// on-chain integer; formatted_* fields are scaled by the token's decimals.
+
+package trackerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WalletTracker_AddWallet_FullMethodName    = "/tracker.v1.WalletTracker/AddWallet"
+	WalletTracker_RemoveWallet_FullMethodName = "/tracker.v1.WalletTracker/RemoveWallet"
+	WalletTracker_ListWallets_FullMethodName  = "/tracker.v1.WalletTracker/ListWallets"
+	WalletTracker_Subscribe_FullMethodName    = "/tracker.v1.WalletTracker/Subscribe"
+)
+
+// WalletTrackerClient is the client API for WalletTracker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WalletTracker manages wallet subscriptions and streams their activity.
+// Calls authenticate with an "authorization: Bearer <token>" metadata entry.
+type WalletTrackerClient interface {
+	// AddWallet subscribes the user to a wallet
+	AddWallet(ctx context.Context, in *AddWalletRequest, opts ...grpc.CallOption) (*AddWalletResponse, error)
+	// RemoveWallet unsubscribes the user from a wallet
+	RemoveWallet(ctx context.Context, in *RemoveWalletRequest, opts ...grpc.CallOption) (*RemoveWalletResponse, error)
+	// ListWallets returns the user's subscriptions
+	ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error)
+	// Subscribe streams the user's wallet notifications as they are published.
+	// Streams falling too far behind end with RESOURCE_EXHAUSTED.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WalletNotification], error)
+}
+
+type walletTrackerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWalletTrackerClient(cc grpc.ClientConnInterface) WalletTrackerClient {
+	return &walletTrackerClient{cc}
+}
+
+func (c *walletTrackerClient) AddWallet(ctx context.Context, in *AddWalletRequest, opts ...grpc.CallOption) (*AddWalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWalletResponse)
+	err := c.cc.Invoke(ctx, WalletTracker_AddWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletTrackerClient) RemoveWallet(ctx context.Context, in *RemoveWalletRequest, opts ...grpc.CallOption) (*RemoveWalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveWalletResponse)
+	err := c.cc.Invoke(ctx, WalletTracker_RemoveWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletTrackerClient) ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWalletsResponse)
+	err := c.cc.Invoke(ctx, WalletTracker_ListWallets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletTrackerClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WalletNotification], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WalletTracker_ServiceDesc.Streams[0], WalletTracker_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, WalletNotification]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WalletTracker_SubscribeClient = grpc.ServerStreamingClient[WalletNotification]
+
+// WalletTrackerServer is the server API for WalletTracker service.
+// All implementations must embed UnimplementedWalletTrackerServer
+// for forward compatibility.
+//
+// WalletTracker manages wallet subscriptions and streams their activity.
+// Calls authenticate with an "authorization: Bearer <token>" metadata entry.
+type WalletTrackerServer interface {
+	// AddWallet subscribes the user to a wallet
+	AddWallet(context.Context, *AddWalletRequest) (*AddWalletResponse, error)
+	// RemoveWallet unsubscribes the user from a wallet
+	RemoveWallet(context.Context, *RemoveWalletRequest) (*RemoveWalletResponse, error)
+	// ListWallets returns the user's subscriptions
+	ListWallets(context.Context, *ListWalletsRequest) (*ListWalletsResponse, error)
+	// Subscribe streams the user's wallet notifications as they are published.
+	// Streams falling too far behind end with RESOURCE_EXHAUSTED.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[WalletNotification]) error
+	mustEmbedUnimplementedWalletTrackerServer()
+}
+
+// UnimplementedWalletTrackerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWalletTrackerServer struct{}
+
+func (UnimplementedWalletTrackerServer) AddWallet(context.Context, *AddWalletRequest) (*AddWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWallet not implemented")
+}
+func (UnimplementedWalletTrackerServer) RemoveWallet(context.Context, *RemoveWalletRequest) (*RemoveWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWallet not implemented")
+}
+func (UnimplementedWalletTrackerServer) ListWallets(context.Context, *ListWalletsRequest) (*ListWalletsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWallets not implemented")
+}
+func (UnimplementedWalletTrackerServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[WalletNotification]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedWalletTrackerServer) mustEmbedUnimplementedWalletTrackerServer() {}
+func (UnimplementedWalletTrackerServer) testEmbeddedByValue()                       {}
+
+// UnsafeWalletTrackerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WalletTrackerServer will
+// result in compilation errors.
+type UnsafeWalletTrackerServer interface {
+	mustEmbedUnimplementedWalletTrackerServer()
+}
+
+func RegisterWalletTrackerServer(s grpc.ServiceRegistrar, srv WalletTrackerServer) {
+	// If the following call pancis, it indicates UnimplementedWalletTrackerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WalletTracker_ServiceDesc, srv)
+}
+
+func _WalletTracker_AddWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletTrackerServer).AddWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletTracker_AddWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletTrackerServer).AddWallet(ctx, req.(*AddWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletTracker_RemoveWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletTrackerServer).RemoveWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletTracker_RemoveWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletTrackerServer).RemoveWallet(ctx, req.(*RemoveWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletTracker_ListWallets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWalletsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletTrackerServer).ListWallets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletTracker_ListWallets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletTrackerServer).ListWallets(ctx, req.(*ListWalletsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletTracker_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletTrackerServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, WalletNotification]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WalletTracker_SubscribeServer = grpc.ServerStreamingServer[WalletNotification]
+
+// WalletTracker_ServiceDesc is the grpc.ServiceDesc for WalletTracker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WalletTracker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tracker.v1.WalletTracker",
+	HandlerType: (*WalletTrackerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddWallet",
+			Handler:    _WalletTracker_AddWallet_Handler,
+		},
+		{
+			MethodName: "RemoveWallet",
+			Handler:    _WalletTracker_RemoveWallet_Handler,
+		},
+		{
+			MethodName: "ListWallets",
+			Handler:    _WalletTracker_ListWallets_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _WalletTracker_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tracker/v1/tracker.proto",
+}
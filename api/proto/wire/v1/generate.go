@@ -0,0 +1,5 @@
+// Package wirev1 holds the generated code of the Redis and NATS messages
+// defined in wire.proto
+package wirev1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative wire/v1/wire.proto
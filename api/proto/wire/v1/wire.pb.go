@@ -0,0 +1,1534 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.28.3
+// source: wire/v1/wire.proto
+
+// Messages exchanged over Redis and NATS when the wire codec is
+// "protobuf". Each message is published on its own channel or subject, so
+// the channel tells which message type to decode. Token amounts are decimal
+// strings of the raw on-chain integer, as in the gRPC API.
+
+package wirev1
+
+import (
+	v1 "github.com/say8hi/plasma-wallet-tracker/api/proto/tracker/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Command is received on the commands channel
+type Command struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g. add_wallet, remove_wallet, get_balance
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	WalletAddress string                 `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Wallets of add_wallets and remove_wallets
+	WalletAddresses []string `protobuf:"bytes,5,rep,name=wallet_addresses,json=walletAddresses,proto3" json:"wallet_addresses,omitempty"`
+	// User whose limit set_wallet_limit changes; an unset limit restores the
+	// default, zero lifts the cap
+	TargetUserId int64  `protobuf:"varint,6,opt,name=target_user_id,json=targetUserId,proto3" json:"target_user_id,omitempty"`
+	WalletLimit  *int64 `protobuf:"varint,7,opt,name=wallet_limit,json=walletLimit,proto3,oneof" json:"wallet_limit,omitempty"`
+	// pause_wallet duration or set_digest window, e.g. "8h"
+	Duration string                 `protobuf:"bytes,8,opt,name=duration,proto3" json:"duration,omitempty"`
+	Label    string                 `protobuf:"bytes,9,opt,name=label,proto3" json:"label,omitempty"`
+	Group    string                 `protobuf:"bytes,10,opt,name=group,proto3" json:"group,omitempty"`
+	Filters  *v1.NotificationFilter `protobuf:"bytes,11,opt,name=filters,proto3" json:"filters,omitempty"`
+	// Address -> label mapping for import_labels
+	Labels       map[string]string `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TokenAddress string            `protobuf:"bytes,13,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	// In token units, e.g. "10000.5"
+	MinAmount       string        `protobuf:"bytes,14,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
+	ContractAddress string        `protobuf:"bytes,15,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	Abi             string        `protobuf:"bytes,16,opt,name=abi,proto3" json:"abi,omitempty"`
+	EventSignature  string        `protobuf:"bytes,17,opt,name=event_signature,json=eventSignature,proto3" json:"event_signature,omitempty"`
+	FromBlock       uint64        `protobuf:"varint,18,opt,name=from_block,json=fromBlock,proto3" json:"from_block,omitempty"`
+	ToBlock         uint64        `protobuf:"varint,19,opt,name=to_block,json=toBlock,proto3" json:"to_block,omitempty"`
+	Limit           int64         `protobuf:"varint,20,opt,name=limit,proto3" json:"limit,omitempty"`
+	Alert           *AlertRule    `protobuf:"bytes,21,opt,name=alert,proto3" json:"alert,omitempty"`
+	RuleId          string        `protobuf:"bytes,22,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	WebhookUrl      string        `protobuf:"bytes,23,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	WebhookId       string        `protobuf:"bytes,24,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	LogLevel        string        `protobuf:"bytes,25,opt,name=log_level,json=logLevel,proto3" json:"log_level,omitempty"`
+	SymbolPattern   string        `protobuf:"bytes,26,opt,name=symbol_pattern,json=symbolPattern,proto3" json:"symbol_pattern,omitempty"`
+	Trace           *TraceContext `protobuf:"bytes,27,opt,name=trace,proto3" json:"trace,omitempty"`
+	ReplyTo         string        `protobuf:"bytes,28,opt,name=reply_to,json=replyTo,proto3" json:"reply_to,omitempty"`
+	CorrelationId   string        `protobuf:"bytes,29,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// Dead letters requeue_dead_letters and purge_dead_letters act on
+	DeadLetterIds []string `protobuf:"bytes,30,rep,name=dead_letter_ids,json=deadLetterIds,proto3" json:"dead_letter_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Command) Reset() {
+	*x = Command{}
+	mi := &file_wire_v1_wire_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Command) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Command) ProtoMessage() {}
+
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Command) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Command) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *Command) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Command) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Command) GetWalletAddresses() []string {
+	if x != nil {
+		return x.WalletAddresses
+	}
+	return nil
+}
+
+func (x *Command) GetTargetUserId() int64 {
+	if x != nil {
+		return x.TargetUserId
+	}
+	return 0
+}
+
+func (x *Command) GetWalletLimit() int64 {
+	if x != nil && x.WalletLimit != nil {
+		return *x.WalletLimit
+	}
+	return 0
+}
+
+func (x *Command) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *Command) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Command) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Command) GetFilters() *v1.NotificationFilter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *Command) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Command) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *Command) GetMinAmount() string {
+	if x != nil {
+		return x.MinAmount
+	}
+	return ""
+}
+
+func (x *Command) GetContractAddress() string {
+	if x != nil {
+		return x.ContractAddress
+	}
+	return ""
+}
+
+func (x *Command) GetAbi() string {
+	if x != nil {
+		return x.Abi
+	}
+	return ""
+}
+
+func (x *Command) GetEventSignature() string {
+	if x != nil {
+		return x.EventSignature
+	}
+	return ""
+}
+
+func (x *Command) GetFromBlock() uint64 {
+	if x != nil {
+		return x.FromBlock
+	}
+	return 0
+}
+
+func (x *Command) GetToBlock() uint64 {
+	if x != nil {
+		return x.ToBlock
+	}
+	return 0
+}
+
+func (x *Command) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Command) GetAlert() *AlertRule {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *Command) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *Command) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *Command) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *Command) GetLogLevel() string {
+	if x != nil {
+		return x.LogLevel
+	}
+	return ""
+}
+
+func (x *Command) GetSymbolPattern() string {
+	if x != nil {
+		return x.SymbolPattern
+	}
+	return ""
+}
+
+func (x *Command) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *Command) GetReplyTo() string {
+	if x != nil {
+		return x.ReplyTo
+	}
+	return ""
+}
+
+func (x *Command) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *Command) GetDeadLetterIds() []string {
+	if x != nil {
+		return x.DeadLetterIds
+	}
+	return nil
+}
+
+// TraceContext carries W3C Trace Context headers
+type TraceContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Traceparent   string                 `protobuf:"bytes,1,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	Tracestate    string                 `protobuf:"bytes,2,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceContext) Reset() {
+	*x = TraceContext{}
+	mi := &file_wire_v1_wire_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceContext) ProtoMessage() {}
+
+func (x *TraceContext) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceContext.ProtoReflect.Descriptor instead.
+func (*TraceContext) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TraceContext) GetTraceparent() string {
+	if x != nil {
+		return x.Traceparent
+	}
+	return ""
+}
+
+func (x *TraceContext) GetTracestate() string {
+	if x != nil {
+		return x.Tracestate
+	}
+	return ""
+}
+
+type AlertRule struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// transfer, velocity, drain or dormant
+	Kind            string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	WalletAddress   string                 `protobuf:"bytes,4,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	TokenAddress    string                 `protobuf:"bytes,5,opt,name=token_address,json=tokenAddress,proto3" json:"token_address,omitempty"`
+	Direction       string                 `protobuf:"bytes,6,opt,name=direction,proto3" json:"direction,omitempty"`
+	MinAmount       string                 `protobuf:"bytes,7,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
+	MinValueUsd     float64                `protobuf:"fixed64,8,opt,name=min_value_usd,json=minValueUsd,proto3" json:"min_value_usd,omitempty"`
+	MaxTransactions int64                  `protobuf:"varint,9,opt,name=max_transactions,json=maxTransactions,proto3" json:"max_transactions,omitempty"`
+	Window          string                 `protobuf:"bytes,10,opt,name=window,proto3" json:"window,omitempty"`
+	DrainPercent    float64                `protobuf:"fixed64,11,opt,name=drain_percent,json=drainPercent,proto3" json:"drain_percent,omitempty"`
+	DormantFor      string                 `protobuf:"bytes,12,opt,name=dormant_for,json=dormantFor,proto3" json:"dormant_for,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_wire_v1_wire_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AlertRule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AlertRule) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AlertRule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AlertRule) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *AlertRule) GetTokenAddress() string {
+	if x != nil {
+		return x.TokenAddress
+	}
+	return ""
+}
+
+func (x *AlertRule) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *AlertRule) GetMinAmount() string {
+	if x != nil {
+		return x.MinAmount
+	}
+	return ""
+}
+
+func (x *AlertRule) GetMinValueUsd() float64 {
+	if x != nil {
+		return x.MinValueUsd
+	}
+	return 0
+}
+
+func (x *AlertRule) GetMaxTransactions() int64 {
+	if x != nil {
+		return x.MaxTransactions
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *AlertRule) GetDrainPercent() float64 {
+	if x != nil {
+		return x.DrainPercent
+	}
+	return 0
+}
+
+func (x *AlertRule) GetDormantFor() string {
+	if x != nil {
+		return x.DormantFor
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// WalletNotification is published on the notifications channel for all
+// subscribers of a wallet at once
+type WalletNotification struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The notification as the gRPC API streams it, without the per-user
+	// wallet_label and wallet_groups
+	Notification *v1.WalletNotification `protobuf:"bytes,1,opt,name=notification,proto3" json:"notification,omitempty"`
+	Subscribers  []int64                `protobuf:"varint,2,rep,packed,name=subscribers,proto3" json:"subscribers,omitempty"`
+	// Subscriber -> their name for the wallet and their groups holding it
+	WalletLabels  map[int64]string        `protobuf:"bytes,3,rep,name=wallet_labels,json=walletLabels,proto3" json:"wallet_labels,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WalletGroups  map[int64]*WalletGroups `protobuf:"bytes,4,rep,name=wallet_groups,json=walletGroups,proto3" json:"wallet_groups,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Trace         *TraceContext           `protobuf:"bytes,5,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletNotification) Reset() {
+	*x = WalletNotification{}
+	mi := &file_wire_v1_wire_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletNotification) ProtoMessage() {}
+
+func (x *WalletNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletNotification.ProtoReflect.Descriptor instead.
+func (*WalletNotification) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WalletNotification) GetNotification() *v1.WalletNotification {
+	if x != nil {
+		return x.Notification
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetSubscribers() []int64 {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetWalletLabels() map[int64]string {
+	if x != nil {
+		return x.WalletLabels
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetWalletGroups() map[int64]*WalletGroups {
+	if x != nil {
+		return x.WalletGroups
+	}
+	return nil
+}
+
+func (x *WalletNotification) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+type WalletGroups struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletGroups) Reset() {
+	*x = WalletGroups{}
+	mi := &file_wire_v1_wire_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletGroups) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletGroups) ProtoMessage() {}
+
+func (x *WalletGroups) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletGroups.ProtoReflect.Descriptor instead.
+func (*WalletGroups) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WalletGroups) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+// CommandResponse is published on the responses channel
+type CommandResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Type    string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	UserId  int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	Error   string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// e.g. invalid_address
+	ErrorCode string `protobuf:"bytes,5,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	// Command specific result as JSON
+	DataJson      []byte                 `protobuf:"bytes,6,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Trace         *TraceContext          `protobuf:"bytes,8,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	mi := &file_wire_v1_wire_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CommandResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CommandResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CommandResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *CommandResponse) GetDataJson() []byte {
+	if x != nil {
+		return x.DataJson
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *CommandResponse) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+// CommandResult is published on a command's reply_to channel
+type CommandResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// ok or error
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,5,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	DataJson      []byte                 `protobuf:"bytes,7,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	mi := &file_wire_v1_wire_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CommandResult) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *CommandResult) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CommandResult) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CommandResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CommandResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *CommandResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CommandResult) GetDataJson() []byte {
+	if x != nil {
+		return x.DataJson
+	}
+	return nil
+}
+
+func (x *CommandResult) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// ContractEventNotification is published on the contract events channel
+type ContractEventNotification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *ContractEvent         `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Subscribers   []int64                `protobuf:"varint,2,rep,packed,name=subscribers,proto3" json:"subscribers,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Trace         *TraceContext          `protobuf:"bytes,4,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContractEventNotification) Reset() {
+	*x = ContractEventNotification{}
+	mi := &file_wire_v1_wire_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractEventNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractEventNotification) ProtoMessage() {}
+
+func (x *ContractEventNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractEventNotification.ProtoReflect.Descriptor instead.
+func (*ContractEventNotification) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ContractEventNotification) GetEvent() *ContractEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ContractEventNotification) GetSubscribers() []int64 {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+func (x *ContractEventNotification) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ContractEventNotification) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+type ContractEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ContractAddress string                 `protobuf:"bytes,1,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	Event           string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	// Topic 0
+	Signature   string                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	TxHash      string                 `protobuf:"bytes,4,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	BlockNumber uint64                 `protobuf:"varint,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	LogIndex    uint64                 `protobuf:"varint,6,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Decoded arguments as a JSON object, when subscribed with an ABI
+	ArgsJson      string   `protobuf:"bytes,8,opt,name=args_json,json=argsJson,proto3" json:"args_json,omitempty"`
+	Topics        []string `protobuf:"bytes,9,rep,name=topics,proto3" json:"topics,omitempty"`
+	Data          string   `protobuf:"bytes,10,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContractEvent) Reset() {
+	*x = ContractEvent{}
+	mi := &file_wire_v1_wire_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractEvent) ProtoMessage() {}
+
+func (x *ContractEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractEvent.ProtoReflect.Descriptor instead.
+func (*ContractEvent) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ContractEvent) GetContractAddress() string {
+	if x != nil {
+		return x.ContractAddress
+	}
+	return ""
+}
+
+func (x *ContractEvent) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *ContractEvent) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *ContractEvent) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *ContractEvent) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *ContractEvent) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *ContractEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ContractEvent) GetArgsJson() string {
+	if x != nil {
+		return x.ArgsJson
+	}
+	return ""
+}
+
+func (x *ContractEvent) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *ContractEvent) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+// TokenTransferNotification is published on the token transfers channel
+type TokenTransferNotification struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Transfer       *v1.Transfer           `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	BlockNumber    uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	EventTimestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=event_timestamp,json=eventTimestamp,proto3" json:"event_timestamp,omitempty"`
+	Subscribers    []int64                `protobuf:"varint,4,rep,packed,name=subscribers,proto3" json:"subscribers,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Trace          *TraceContext          `protobuf:"bytes,6,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TokenTransferNotification) Reset() {
+	*x = TokenTransferNotification{}
+	mi := &file_wire_v1_wire_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenTransferNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenTransferNotification) ProtoMessage() {}
+
+func (x *TokenTransferNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenTransferNotification.ProtoReflect.Descriptor instead.
+func (*TokenTransferNotification) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TokenTransferNotification) GetTransfer() *v1.Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *TokenTransferNotification) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *TokenTransferNotification) GetEventTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTimestamp
+	}
+	return nil
+}
+
+func (x *TokenTransferNotification) GetSubscribers() []int64 {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+func (x *TokenTransferNotification) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TokenTransferNotification) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+// AlertNotification is published on the alerts channel
+type AlertNotification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *AlertRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	WalletAddress string                 `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	Transaction   *v1.Transaction        `protobuf:"bytes,3,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	// The transfers that matched
+	Transfers []*v1.Transfer `protobuf:"bytes,4,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	// Why a behavioral rule fired
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Trace         *TraceContext          `protobuf:"bytes,7,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertNotification) Reset() {
+	*x = AlertNotification{}
+	mi := &file_wire_v1_wire_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertNotification) ProtoMessage() {}
+
+func (x *AlertNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertNotification.ProtoReflect.Descriptor instead.
+func (*AlertNotification) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AlertNotification) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *AlertNotification) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *AlertNotification) GetTransaction() *v1.Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *AlertNotification) GetTransfers() []*v1.Transfer {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+func (x *AlertNotification) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AlertNotification) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AlertNotification) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+// FirehoseBlock is published on the firehose channel with every transfer of
+// a processed block
+type FirehoseBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlockNumber   uint64                 `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	BlockHash     string                 `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`
+	Transfers     []*v1.Transfer         `protobuf:"bytes,4,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Trace         *TraceContext          `protobuf:"bytes,6,opt,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FirehoseBlock) Reset() {
+	*x = FirehoseBlock{}
+	mi := &file_wire_v1_wire_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FirehoseBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirehoseBlock) ProtoMessage() {}
+
+func (x *FirehoseBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_v1_wire_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirehoseBlock.ProtoReflect.Descriptor instead.
+func (*FirehoseBlock) Descriptor() ([]byte, []int) {
+	return file_wire_v1_wire_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FirehoseBlock) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *FirehoseBlock) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *FirehoseBlock) GetBlockTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockTime
+	}
+	return nil
+}
+
+func (x *FirehoseBlock) GetTransfers() []*v1.Transfer {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+func (x *FirehoseBlock) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *FirehoseBlock) GetTrace() *TraceContext {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+var File_wire_v1_wire_proto protoreflect.FileDescriptor
+
+const file_wire_v1_wire_proto_rawDesc = "" +
+	"\n" +
+	"\x12wire/v1/wire.proto\x12\awire.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18tracker/v1/tracker.proto\"\xec\b\n" +
+	"\aCommand\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12)\n" +
+	"\x10wallet_addresses\x18\x05 \x03(\tR\x0fwalletAddresses\x12$\n" +
+	"\x0etarget_user_id\x18\x06 \x01(\x03R\ftargetUserId\x12&\n" +
+	"\fwallet_limit\x18\a \x01(\x03H\x00R\vwalletLimit\x88\x01\x01\x12\x1a\n" +
+	"\bduration\x18\b \x01(\tR\bduration\x12\x14\n" +
+	"\x05label\x18\t \x01(\tR\x05label\x12\x14\n" +
+	"\x05group\x18\n" +
+	" \x01(\tR\x05group\x128\n" +
+	"\afilters\x18\v \x01(\v2\x1e.tracker.v1.NotificationFilterR\afilters\x124\n" +
+	"\x06labels\x18\f \x03(\v2\x1c.wire.v1.Command.LabelsEntryR\x06labels\x12#\n" +
+	"\rtoken_address\x18\r \x01(\tR\ftokenAddress\x12\x1d\n" +
+	"\n" +
+	"min_amount\x18\x0e \x01(\tR\tminAmount\x12)\n" +
+	"\x10contract_address\x18\x0f \x01(\tR\x0fcontractAddress\x12\x10\n" +
+	"\x03abi\x18\x10 \x01(\tR\x03abi\x12'\n" +
+	"\x0fevent_signature\x18\x11 \x01(\tR\x0eeventSignature\x12\x1d\n" +
+	"\n" +
+	"from_block\x18\x12 \x01(\x04R\tfromBlock\x12\x19\n" +
+	"\bto_block\x18\x13 \x01(\x04R\atoBlock\x12\x14\n" +
+	"\x05limit\x18\x14 \x01(\x03R\x05limit\x12(\n" +
+	"\x05alert\x18\x15 \x01(\v2\x12.wire.v1.AlertRuleR\x05alert\x12\x17\n" +
+	"\arule_id\x18\x16 \x01(\tR\x06ruleId\x12\x1f\n" +
+	"\vwebhook_url\x18\x17 \x01(\tR\n" +
+	"webhookUrl\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x18 \x01(\tR\twebhookId\x12\x1b\n" +
+	"\tlog_level\x18\x19 \x01(\tR\blogLevel\x12%\n" +
+	"\x0esymbol_pattern\x18\x1a \x01(\tR\rsymbolPattern\x12+\n" +
+	"\x05trace\x18\x1b \x01(\v2\x15.wire.v1.TraceContextR\x05trace\x12\x19\n" +
+	"\breply_to\x18\x1c \x01(\tR\areplyTo\x12%\n" +
+	"\x0ecorrelation_id\x18\x1d \x01(\tR\rcorrelationId\x12&\n" +
+	"\x0fdead_letter_ids\x18\x1e \x03(\tR\rdeadLetterIds\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0f\n" +
+	"\r_wallet_limit\"P\n" +
+	"\fTraceContext\x12 \n" +
+	"\vtraceparent\x18\x01 \x01(\tR\vtraceparent\x12\x1e\n" +
+	"\n" +
+	"tracestate\x18\x02 \x01(\tR\n" +
+	"tracestate\"\xb9\x03\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12%\n" +
+	"\x0ewallet_address\x18\x04 \x01(\tR\rwalletAddress\x12#\n" +
+	"\rtoken_address\x18\x05 \x01(\tR\ftokenAddress\x12\x1c\n" +
+	"\tdirection\x18\x06 \x01(\tR\tdirection\x12\x1d\n" +
+	"\n" +
+	"min_amount\x18\a \x01(\tR\tminAmount\x12\"\n" +
+	"\rmin_value_usd\x18\b \x01(\x01R\vminValueUsd\x12)\n" +
+	"\x10max_transactions\x18\t \x01(\x03R\x0fmaxTransactions\x12\x16\n" +
+	"\x06window\x18\n" +
+	" \x01(\tR\x06window\x12#\n" +
+	"\rdrain_percent\x18\v \x01(\x01R\fdrainPercent\x12\x1f\n" +
+	"\vdormant_for\x18\f \x01(\tR\n" +
+	"dormantFor\x129\n" +
+	"\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xe8\x03\n" +
+	"\x12WalletNotification\x12B\n" +
+	"\fnotification\x18\x01 \x01(\v2\x1e.tracker.v1.WalletNotificationR\fnotification\x12 \n" +
+	"\vsubscribers\x18\x02 \x03(\x03R\vsubscribers\x12R\n" +
+	"\rwallet_labels\x18\x03 \x03(\v2-.wire.v1.WalletNotification.WalletLabelsEntryR\fwalletLabels\x12R\n" +
+	"\rwallet_groups\x18\x04 \x03(\v2-.wire.v1.WalletNotification.WalletGroupsEntryR\fwalletGroups\x12+\n" +
+	"\x05trace\x18\x05 \x01(\v2\x15.wire.v1.TraceContextR\x05trace\x1a?\n" +
+	"\x11WalletLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aV\n" +
+	"\x11WalletGroupsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.wire.v1.WalletGroupsR\x05value:\x028\x01\"$\n" +
+	"\fWalletGroups\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"\x91\x02\n" +
+	"\x0fCommandResponse\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x05 \x01(\tR\terrorCode\x12\x1b\n" +
+	"\tdata_json\x18\x06 \x01(\fR\bdataJson\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x05trace\x18\b \x01(\v2\x15.wire.v1.TraceContextR\x05trace\"\x87\x02\n" +
+	"\rCommandResult\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x05 \x01(\tR\terrorCode\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1b\n" +
+	"\tdata_json\x18\a \x01(\fR\bdataJson\x128\n" +
+	"\ttimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xd2\x01\n" +
+	"\x19ContractEventNotification\x12,\n" +
+	"\x05event\x18\x01 \x01(\v2\x16.wire.v1.ContractEventR\x05event\x12 \n" +
+	"\vsubscribers\x18\x02 \x03(\x03R\vsubscribers\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x05trace\x18\x04 \x01(\v2\x15.wire.v1.TraceContextR\x05trace\"\xca\x02\n" +
+	"\rContractEvent\x12)\n" +
+	"\x10contract_address\x18\x01 \x01(\tR\x0fcontractAddress\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\tR\tsignature\x12\x17\n" +
+	"\atx_hash\x18\x04 \x01(\tR\x06txHash\x12!\n" +
+	"\fblock_number\x18\x05 \x01(\x04R\vblockNumber\x12\x1b\n" +
+	"\tlog_index\x18\x06 \x01(\x04R\blogIndex\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1b\n" +
+	"\targs_json\x18\b \x01(\tR\bargsJson\x12\x16\n" +
+	"\x06topics\x18\t \x03(\tR\x06topics\x12\x12\n" +
+	"\x04data\x18\n" +
+	" \x01(\tR\x04data\"\xbe\x02\n" +
+	"\x19TokenTransferNotification\x120\n" +
+	"\btransfer\x18\x01 \x01(\v2\x14.tracker.v1.TransferR\btransfer\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\x12C\n" +
+	"\x0fevent_timestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0eeventTimestamp\x12 \n" +
+	"\vsubscribers\x18\x04 \x03(\x03R\vsubscribers\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x05trace\x18\x06 \x01(\v2\x15.wire.v1.TraceContextR\x05trace\"\xd0\x02\n" +
+	"\x11AlertNotification\x12&\n" +
+	"\x04rule\x18\x01 \x01(\v2\x12.wire.v1.AlertRuleR\x04rule\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\x129\n" +
+	"\vtransaction\x18\x03 \x01(\v2\x17.tracker.v1.TransactionR\vtransaction\x122\n" +
+	"\ttransfers\x18\x04 \x03(\v2\x14.tracker.v1.TransferR\ttransfers\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x05trace\x18\a \x01(\v2\x15.wire.v1.TraceContextR\x05trace\"\xa7\x02\n" +
+	"\rFirehoseBlock\x12!\n" +
+	"\fblock_number\x18\x01 \x01(\x04R\vblockNumber\x12\x1d\n" +
+	"\n" +
+	"block_hash\x18\x02 \x01(\tR\tblockHash\x129\n" +
+	"\n" +
+	"block_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tblockTime\x122\n" +
+	"\ttransfers\x18\x04 \x03(\v2\x14.tracker.v1.TransferR\ttransfers\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x05trace\x18\x06 \x01(\v2\x15.wire.v1.TraceContextR\x05traceBBZ@github.com/say8hi/plasma-wallet-tracker/api/proto/wire/v1;wirev1b\x06proto3"
+
+var (
+	file_wire_v1_wire_proto_rawDescOnce sync.Once
+	file_wire_v1_wire_proto_rawDescData []byte
+)
+
+func file_wire_v1_wire_proto_rawDescGZIP() []byte {
+	file_wire_v1_wire_proto_rawDescOnce.Do(func() {
+		file_wire_v1_wire_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_wire_v1_wire_proto_rawDesc), len(file_wire_v1_wire_proto_rawDesc)))
+	})
+	return file_wire_v1_wire_proto_rawDescData
+}
+
+var file_wire_v1_wire_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_wire_v1_wire_proto_goTypes = []any{
+	(*Command)(nil),                   // 0: wire.v1.Command
+	(*TraceContext)(nil),              // 1: wire.v1.TraceContext
+	(*AlertRule)(nil),                 // 2: wire.v1.AlertRule
+	(*WalletNotification)(nil),        // 3: wire.v1.WalletNotification
+	(*WalletGroups)(nil),              // 4: wire.v1.WalletGroups
+	(*CommandResponse)(nil),           // 5: wire.v1.CommandResponse
+	(*CommandResult)(nil),             // 6: wire.v1.CommandResult
+	(*ContractEventNotification)(nil), // 7: wire.v1.ContractEventNotification
+	(*ContractEvent)(nil),             // 8: wire.v1.ContractEvent
+	(*TokenTransferNotification)(nil), // 9: wire.v1.TokenTransferNotification
+	(*AlertNotification)(nil),         // 10: wire.v1.AlertNotification
+	(*FirehoseBlock)(nil),             // 11: wire.v1.FirehoseBlock
+	nil,                               // 12: wire.v1.Command.LabelsEntry
+	nil,                               // 13: wire.v1.WalletNotification.WalletLabelsEntry
+	nil,                               // 14: wire.v1.WalletNotification.WalletGroupsEntry
+	(*timestamppb.Timestamp)(nil),     // 15: google.protobuf.Timestamp
+	(*v1.NotificationFilter)(nil),     // 16: tracker.v1.NotificationFilter
+	(*v1.WalletNotification)(nil),     // 17: tracker.v1.WalletNotification
+	(*v1.Transfer)(nil),               // 18: tracker.v1.Transfer
+	(*v1.Transaction)(nil),            // 19: tracker.v1.Transaction
+}
+var file_wire_v1_wire_proto_depIdxs = []int32{
+	15, // 0: wire.v1.Command.timestamp:type_name -> google.protobuf.Timestamp
+	16, // 1: wire.v1.Command.filters:type_name -> tracker.v1.NotificationFilter
+	12, // 2: wire.v1.Command.labels:type_name -> wire.v1.Command.LabelsEntry
+	2,  // 3: wire.v1.Command.alert:type_name -> wire.v1.AlertRule
+	1,  // 4: wire.v1.Command.trace:type_name -> wire.v1.TraceContext
+	15, // 5: wire.v1.AlertRule.created_at:type_name -> google.protobuf.Timestamp
+	17, // 6: wire.v1.WalletNotification.notification:type_name -> tracker.v1.WalletNotification
+	13, // 7: wire.v1.WalletNotification.wallet_labels:type_name -> wire.v1.WalletNotification.WalletLabelsEntry
+	14, // 8: wire.v1.WalletNotification.wallet_groups:type_name -> wire.v1.WalletNotification.WalletGroupsEntry
+	1,  // 9: wire.v1.WalletNotification.trace:type_name -> wire.v1.TraceContext
+	15, // 10: wire.v1.CommandResponse.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 11: wire.v1.CommandResponse.trace:type_name -> wire.v1.TraceContext
+	15, // 12: wire.v1.CommandResult.timestamp:type_name -> google.protobuf.Timestamp
+	8,  // 13: wire.v1.ContractEventNotification.event:type_name -> wire.v1.ContractEvent
+	15, // 14: wire.v1.ContractEventNotification.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 15: wire.v1.ContractEventNotification.trace:type_name -> wire.v1.TraceContext
+	15, // 16: wire.v1.ContractEvent.timestamp:type_name -> google.protobuf.Timestamp
+	18, // 17: wire.v1.TokenTransferNotification.transfer:type_name -> tracker.v1.Transfer
+	15, // 18: wire.v1.TokenTransferNotification.event_timestamp:type_name -> google.protobuf.Timestamp
+	15, // 19: wire.v1.TokenTransferNotification.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 20: wire.v1.TokenTransferNotification.trace:type_name -> wire.v1.TraceContext
+	2,  // 21: wire.v1.AlertNotification.rule:type_name -> wire.v1.AlertRule
+	19, // 22: wire.v1.AlertNotification.transaction:type_name -> tracker.v1.Transaction
+	18, // 23: wire.v1.AlertNotification.transfers:type_name -> tracker.v1.Transfer
+	15, // 24: wire.v1.AlertNotification.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 25: wire.v1.AlertNotification.trace:type_name -> wire.v1.TraceContext
+	15, // 26: wire.v1.FirehoseBlock.block_time:type_name -> google.protobuf.Timestamp
+	18, // 27: wire.v1.FirehoseBlock.transfers:type_name -> tracker.v1.Transfer
+	15, // 28: wire.v1.FirehoseBlock.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 29: wire.v1.FirehoseBlock.trace:type_name -> wire.v1.TraceContext
+	4,  // 30: wire.v1.WalletNotification.WalletGroupsEntry.value:type_name -> wire.v1.WalletGroups
+	31, // [31:31] is the sub-list for method output_type
+	31, // [31:31] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_wire_v1_wire_proto_init() }
+func file_wire_v1_wire_proto_init() {
+	if File_wire_v1_wire_proto != nil {
+		return
+	}
+	file_wire_v1_wire_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wire_v1_wire_proto_rawDesc), len(file_wire_v1_wire_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_wire_v1_wire_proto_goTypes,
+		DependencyIndexes: file_wire_v1_wire_proto_depIdxs,
+		MessageInfos:      file_wire_v1_wire_proto_msgTypes,
+	}.Build()
+	File_wire_v1_wire_proto = out.File
+	file_wire_v1_wire_proto_goTypes = nil
+	file_wire_v1_wire_proto_depIdxs = nil
+}
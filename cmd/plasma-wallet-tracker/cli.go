@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/config"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+const usage = `Usage: plasma-wallet-tracker [command] [flags]
+
+Commands:
+  serve            Track wallets and serve the APIs (default)
+  backfill         Publish a wallet's transactions in a block range to its subscribers
+  replay           Publish again every tracked wallet's transactions in a block range
+  validate-config  Check the configuration and exit
+
+Run "plasma-wallet-tracker <command> -h" for the flags of a command.
+`
+
+// task is a one-off job run against the wired services instead of serving
+type task func(ctx context.Context, walletTracker *usecase.WalletTracker) error
+
+func main() {
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serveCommand(args)
+	case "backfill":
+		backfillCommand(args)
+	case "replay":
+		replayCommand(args)
+	case "validate-config":
+		validateConfigCommand(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+// newFlagSet returns the flags of command with the -config flag they share
+func newFlagSet(command string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	configFile := flags.String("config", "", "YAML config file; env vars take precedence over it")
+	return flags, configFile
+}
+
+func serveCommand(args []string) {
+	flags, configFile := newFlagSet("serve")
+	flags.Parse(args)
+
+	serve(*configFile, nil)
+}
+
+func backfillCommand(args []string) {
+	flags, configFile := newFlagSet("backfill")
+	address := flags.String("address", "", "wallet whose transactions are published (required)")
+	from := flags.Uint64("from", 0, "first block of the range")
+	to := flags.Uint64("to", 0, "last block of the range (0 = latest)")
+	flags.Parse(args)
+
+	if *address == "" {
+		fmt.Fprintln(os.Stderr, "-address is required")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	serve(*configFile, func(ctx context.Context, walletTracker *usecase.WalletTracker) error {
+		if err := walletTracker.LoadSubscriptions(ctx); err != nil {
+			return fmt.Errorf("failed to load subscriptions: %w", err)
+		}
+		published, err := walletTracker.Backfill(ctx, domain.WalletAddress(*address), *from, *to)
+		fmt.Printf("published %d notifications\n", published)
+		return err
+	})
+}
+
+func replayCommand(args []string) {
+	flags, configFile := newFlagSet("replay")
+	blockRange := flags.String("block-range", "", "blocks to replay, FROM-TO or FROM for up to the latest (required)")
+	flags.Parse(args)
+
+	from, to, err := parseBlockRange(*blockRange)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	serve(*configFile, func(ctx context.Context, walletTracker *usecase.WalletTracker) error {
+		if err := walletTracker.LoadSubscriptions(ctx); err != nil {
+			return fmt.Errorf("failed to load subscriptions: %w", err)
+		}
+		published, err := walletTracker.Replay(ctx, from, to)
+		fmt.Printf("published %d notifications\n", published)
+		return err
+	})
+}
+
+func validateConfigCommand(args []string) {
+	flags, configFile := newFlagSet("validate-config")
+	flags.Parse(args)
+
+	if _, err := config.Load(*configFile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println("configuration is valid")
+}
+
+// parseBlockRange parses FROM-TO, or FROM for a range up to the latest
+// block (TO = 0)
+func parseBlockRange(s string) (from, to uint64, err error) {
+	if s == "" {
+		return 0, 0, errors.New("-block-range is required")
+	}
+
+	first, last, ranged := strings.Cut(s, "-")
+	if from, err = strconv.ParseUint(first, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid block range %q", s)
+	}
+	if ranged && last != "" {
+		if to, err = strconv.ParseUint(last, 10, 64); err != nil || to < from {
+			return 0, 0, fmt.Errorf("invalid block range %q", s)
+		}
+	}
+	return from, to, nil
+}
+
+// runTask runs task until it returns or the process is interrupted, then
+// waits up to timeout for webhook and Telegram deliveries it caused
+func runTask(
+	task task,
+	logger *zap.Logger,
+	timeout time.Duration,
+	walletTracker *usecase.WalletTracker,
+	webhookDispatcher *usecase.WebhookDispatcher,
+	chatNotifier *usecase.ChatNotifier,
+) error {
+	sinksCtx, stopSinks := context.WithCancel(context.Background())
+	defer stopSinks()
+	webhooksDone := run(func() { webhookDispatcher.Start(sinksCtx) })
+	chatsDone := run(func() {
+		if chatNotifier != nil {
+			chatNotifier.Start(sinksCtx)
+		}
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := task(ctx, walletTracker)
+	stop()
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), timeout)
+	defer cancelDeadline()
+	webhookDispatcher.Drain(deadline)
+	if chatNotifier != nil {
+		chatNotifier.Drain(deadline)
+	}
+	stopSinks()
+	awaitStage(deadline, logger, "webhook dispatcher", webhooksDone)
+	awaitStage(deadline, logger, "chat notifier", chatsDone)
+	return err
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/blockchain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+// debugState is the /debug/state response
+type debugState struct {
+	Tracker      domain.TrackerStatus   `json:"tracker"`
+	Blockchain   blockchain.ClientState `json:"blockchain"`
+	PublishQueue queueState             `json:"publish_queue"`
+
+	ContractListeners int `json:"contract_listeners"`
+	TokenListeners    int `json:"token_listeners"`
+
+	Runtime runtimeState `json:"runtime"`
+}
+
+type queueState struct {
+	Buffered int `json:"buffered"`
+	Capacity int `json:"capacity"`
+}
+
+type runtimeState struct {
+	Goroutines   int       `json:"goroutines"`
+	HeapAlloc    uint64    `json:"heap_alloc_bytes"`
+	HeapObjects  uint64    `json:"heap_objects"`
+	Sys          uint64    `json:"sys_bytes"`
+	NumGC        uint32    `json:"num_gc"`
+	LastGC       time.Time `json:"last_gc,omitzero"`
+	PauseTotalNs uint64    `json:"gc_pause_total_ns"`
+}
+
+// debugHandler serves the pprof profiles under /debug/pprof/ and a dump of
+// the tracker's in-memory state under /debug/state
+func debugHandler(
+	logger *zap.Logger,
+	blockchainClient *blockchain.PlasmaClient,
+	walletTracker *usecase.WalletTracker,
+	contractWatcher *usecase.ContractWatcher,
+	tokenWatcher *usecase.TokenWatcher,
+) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("GET /debug/state", func(w http.ResponseWriter, r *http.Request) {
+		dumpState(w, logger, blockchainClient, walletTracker, contractWatcher, tokenWatcher)
+	})
+
+	return mux
+}
+
+func dumpState(
+	w http.ResponseWriter,
+	logger *zap.Logger,
+	blockchainClient *blockchain.PlasmaClient,
+	walletTracker *usecase.WalletTracker,
+	contractWatcher *usecase.ContractWatcher,
+	tokenWatcher *usecase.TokenWatcher,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	state := debugState{
+		Tracker:           walletTracker.Status(),
+		Blockchain:        blockchainClient.DebugState(),
+		ContractListeners: contractWatcher.ListenerCount(),
+		TokenListeners:    tokenWatcher.ListenerCount(),
+		Runtime: runtimeState{
+			Goroutines:   runtime.NumGoroutine(),
+			HeapAlloc:    memStats.HeapAlloc,
+			HeapObjects:  memStats.HeapObjects,
+			Sys:          memStats.Sys,
+			NumGC:        memStats.NumGC,
+			PauseTotalNs: memStats.PauseTotalNs,
+		},
+	}
+	if memStats.LastGC > 0 {
+		state.Runtime.LastGC = time.Unix(0, int64(memStats.LastGC))
+	}
+
+	state.PublishQueue.Buffered, state.PublishQueue.Capacity = walletTracker.QueueOccupancy()
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(state); err != nil {
+		logger.Error("Failed to write debug state", zap.Error(err))
+	}
+}
+
+// requireToken rejects requests without the bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Comment lines sent this often keep proxies from closing idle streams
+	eventsKeepAlive = 30 * time.Second
+	// How long EventSource clients wait before reconnecting
+	eventsRetry = 3 * time.Second
+)
+
+// streamEvents sends the user_id user's wallet notifications as Server-Sent
+// Events. Clients reconnecting with Last-Event-ID (or the last_event_id
+// query parameter) first get what they missed from the replay buffer.
+func streamEvents(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	hub *usecase.NotificationHub,
+	token string,
+) {
+	if !validStreamToken(r, token) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+	if err != nil {
+		writeEventsError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	// Subscribe before replaying so nothing published in between is lost
+	stream := hub.Subscribe(domain.UserID(userID))
+	defer stream.Close()
+
+	var replayed []domain.NotificationEvent
+	if lastEventID != "" {
+		replayed, err = hub.Replay(r.Context(), domain.UserID(userID), lastEventID)
+		switch {
+		case errors.Is(err, domain.ErrInvalidEventID):
+			writeEventsError(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, domain.ErrReplayUnavailable):
+			// Resume isn't configured, only live events are sent
+		case err != nil:
+			logger.Error("Failed to replay notifications", zap.Int64("user_id", userID), zap.Error(err))
+			writeEventsError(w, http.StatusInternalServerError, "failed to replay notifications")
+			return
+		}
+	}
+
+	// The server's write timeout would cut the stream off, each write gets
+	// its own deadline instead
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Error("Failed to clear write deadline of event stream", zap.Error(err))
+		writeEventsError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(write func() error) bool {
+		rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+		if err := write(); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !send(func() error {
+		_, err := fmt.Fprintf(w, "retry: %d\n\n", eventsRetry.Milliseconds())
+		return err
+	}) {
+		return
+	}
+
+	// Events replayed may also arrive live
+	seen := make(map[string]struct{}, len(replayed))
+	for _, event := range replayed {
+		seen[event.ID] = struct{}{}
+		if !send(func() error { return writeEvent(w, event) }) {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-stream.C():
+			if !ok {
+				// Slow clients reconnect and resume from the replay buffer
+				return
+			}
+			if _, ok := seen[event.ID]; ok {
+				delete(seen, event.ID)
+				continue
+			}
+			if !send(func() error { return writeEvent(w, event) }) {
+				logger.Debug("Event stream client went away", zap.Int64("user_id", userID))
+				return
+			}
+		case <-keepAlive.C:
+			if !send(func() error {
+				_, err := fmt.Fprint(w, ": keep-alive\n\n")
+				return err
+			}) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEvent writes event as an SSE message with its replay ID, if any
+func writeEvent(w http.ResponseWriter, event domain.NotificationEvent) error {
+	data, err := json.Marshal(event.Notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func writeEventsError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -2,39 +2,75 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/say8hi/plasma-wallet-tracker/config"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
 	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/blockchain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/clickhouse"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/file"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/grpcserver"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/nats"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/postgres"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/pricing"
 	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/redis"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/routing"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/telegram"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/webhook"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
 	"github.com/say8hi/plasma-wallet-tracker/internal/usecase"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
-func main() {
+// serve runs the tracker until interrupted, or with a task only runs the
+// task against the wired services and exits
+func serve(configFile string, task task) {
 	// Load configuration first
-	cfg, err := config.Load()
+	cfg, err := config.Load(configFile)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
-	// Initialize logger based on config
-	var logger *zap.Logger
+	// Initialize logger based on config; the level can be changed at runtime
+	logLevel, err := zap.ParseAtomicLevel(cfg.Log.Level)
+	if err != nil {
+		log.Fatal("Invalid log level:", err)
+	}
+	logConfig := zap.NewProductionConfig()
 	if cfg.Log.Level == "debug" {
-		logger, err = zap.NewDevelopment()
-	} else {
-		logger, err = zap.NewProduction()
+		logConfig = zap.NewDevelopmentConfig()
 	}
+	logConfig.Level = logLevel
+	logger, err := logConfig.Build()
 	if err != nil {
 		log.Fatal("Failed to initialize logger:", err)
 	}
 	defer logger.Sync()
 
+	// Initialize tracing before anything starts spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+
 	// Initialize Redis client
 	redisClient := redis.NewClient(cfg.Redis)
 
@@ -43,80 +79,1154 @@ func main() {
 		logger.Fatal("Failed to connect to Redis", zap.Error(err))
 	}
 
+	// Only the elected leader follows the chain and publishes when running
+	// active/standby; tasks run regardless
+	var leader *usecase.LeaderElector
+	if cfg.HA.Enabled && task == nil {
+		leader = usecase.NewLeaderElector(
+			redis.NewLeaderLock(redisClient),
+			instanceID(cfg.HA.InstanceID),
+			cfg.HA.RenewInterval,
+			cfg.HA.LeaseTTL,
+			logger,
+		)
+	}
+
+	// Shard members each follow the chain for their own wallets, so they
+	// keep their own checkpoint and queues under keys suffixed with the
+	// instance ID; otherwise the keys are shared
+	instanceKey := func(key string) string {
+		if cfg.Shard.Enabled && task == nil {
+			return key + ":" + cfg.Shard.InstanceID
+		}
+		return key
+	}
+
+	// Connect to PostgreSQL only if configured
+	var postgresClient *postgres.Client
+	if cfg.Postgres.DSN != "" {
+		postgresClient, err = postgres.NewClient(context.Background(), cfg.Postgres)
+		if err != nil {
+			logger.Fatal("Failed to connect to PostgreSQL", zap.Error(err))
+		}
+	}
+
+	// Connect to NATS only if a transport uses it
+	var natsClient *nats.Client
+	if slices.Contains(cfg.Service.Transport, "nats") || cfg.Service.CommandTransport == "nats" {
+		natsClient, err = nats.NewClient(context.Background(), cfg.NATS, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to NATS", zap.Error(err))
+		}
+	}
+
+	wireCodec, err := codec.New(cfg.Service.Codec, cfg.Service.LegacyAmounts)
+	if err != nil {
+		logger.Fatal("Failed to initialize codec", zap.Error(err))
+	}
+	if cfg.Service.CloudEvents {
+		wireCodec = codec.NewCloudEvents(wireCodec, cfg.Service.CloudEventsSource, cfg.Service.CloudEventsTypePrefix)
+	}
+	channels := routing.Channels{
+		Commands:           cfg.Service.CommandChannel,
+		Notifications:      cfg.Service.NotificationChannel,
+		Responses:          cfg.Service.ResponseChannel,
+		ContractEvents:     cfg.Service.ContractChannel,
+		TokenTransfers:     cfg.Service.TokenChannel,
+		Alerts:             cfg.Service.AlertChannel,
+		Firehose:           cfg.Service.FirehoseChannel,
+		NotificationRoute:  cfg.Service.NotificationRoute,
+		NotificationShards: cfg.Service.NotificationShards,
+	}
+
+	// Messages are published to every sink whose filter they match: the
+	// transports first, then the in-process sinks below
+	sinks := usecase.NewSinkRegistry(logger)
+	registerSink := func(sink usecase.Sink) {
+		if err := sinks.Register(sink); err != nil {
+			logger.Fatal("Failed to register publishing sink", zap.Error(err))
+		}
+	}
+	if len(cfg.Service.Transport) == 0 {
+		logger.Fatal("No transport configured")
+	}
+	for _, transport := range cfg.Service.Transport {
+		switch transport {
+		case "pubsub":
+			registerSink(newSink(transport, redis.NewPublisher(redisClient, channels, wireCodec, logger), cfg.Sinks.PubSub, true))
+		case "streams":
+			registerSink(newSink(
+				transport,
+				redis.NewStreamPublisher(redisClient, channels, cfg.Service.StreamMaxLen, wireCodec, logger),
+				cfg.Sinks.Streams,
+				true,
+			))
+		case "nats":
+			registerSink(newSink(transport, nats.NewPublisher(natsClient, channels, wireCodec, logger), cfg.Sinks.NATS, true))
+		default:
+			logger.Fatal("Unknown transport", zap.String("transport", transport))
+		}
+	}
+
+	// Published notifications are also streamed to WebSocket, SSE and gRPC
+	// clients; SSE clients resume from the replay buffer
+	var notificationBuffer domain.NotificationBuffer
+	if cfg.Service.StreamToken != "" && cfg.Service.EventBufferLen > 0 {
+		notificationBuffer = redis.NewNotificationBuffer(
+			redisClient,
+			cfg.Service.EventBufferLen,
+			cfg.Service.EventBufferTTL,
+		)
+	}
+	notificationHub := usecase.NewNotificationHub(notificationBuffer, cfg.Service.StreamBuffer, logger)
+	registerSink(newSink("live", notificationHub, cfg.Sinks.Live, false))
+
+	// Published notifications are also POSTed to users' webhooks
+	webhookDispatcher := usecase.NewWebhookDispatcher(
+		redis.NewWebhookRepository(redisClient),
+		webhook.NewSender(cfg.Service.WebhookTimeout, cfg.Service.WebhookAllowPrivate),
+		usecase.WebhookConfig{
+			Workers:     cfg.Service.WebhookWorkers,
+			QueueSize:   cfg.Service.WebhookQueueSize,
+			MaxAttempts: cfg.Service.WebhookMaxAttempts,
+		},
+		logger,
+	)
+	if err := webhookDispatcher.Load(context.Background()); err != nil {
+		logger.Fatal("Failed to load webhooks", zap.Error(err))
+	}
+	registerSink(newSink("webhooks", webhookDispatcher, cfg.Sinks.Webhooks, false))
+
+	// Notifications and alerts are also sent to Telegram chats if configured
+	var chatNotifier *usecase.ChatNotifier
+	if cfg.Telegram.BotToken != "" {
+		bot, err := telegram.NewBot(cfg.Telegram, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize Telegram bot", zap.Error(err))
+		}
+		chatNotifier = usecase.NewChatNotifier(bot, cfg.Telegram.Workers, cfg.Telegram.QueueSize, logger)
+		registerSink(newSink("telegram", chatNotifier, cfg.Sinks.Telegram, false))
+	}
+	var publisher domain.Publisher = sinks
+
+	// Notifications that can't be published and commands that can't be
+	// handled are kept in a dead letter queue if configured
+	var deadLetterQueue domain.DeadLetterQueue
+	if cfg.Service.DeadLetterStream != "" {
+		deadLetterQueue = redis.NewDeadLetterQueue(redisClient, cfg.Service.DeadLetterStream, cfg.Service.DeadLetterMaxLen)
+	}
+	deadLetters := usecase.NewDeadLetters(deadLetterQueue, sinks, func(data []byte) (domain.Command, error) {
+		var cmd domain.Command
+		err := wireCodec.Unmarshal(data, &cmd)
+		return cmd, err
+	}, logger)
+
+	// Notifications go through an outbox if configured, so failed publishes
+	// are retried, and are dead-lettered as soon as they fail otherwise;
+	// tasks publish directly
+	var outboxPublisher *usecase.OutboxPublisher
+	if task == nil {
+		var outbox domain.Outbox
+		switch cfg.Service.Outbox {
+		case "":
+		case "redis":
+			outbox = redis.NewOutbox(redisClient, instanceKey("outbox"))
+		case "file":
+			fileOutbox, err := file.NewOutbox(cfg.Service.OutboxFile)
+			if err != nil {
+				logger.Fatal("Failed to open outbox", zap.Error(err))
+			}
+			defer fileOutbox.Close()
+			outbox = fileOutbox
+		default:
+			logger.Fatal("Unknown outbox", zap.String("outbox", cfg.Service.Outbox))
+		}
+		if outbox != nil {
+			outboxPublisher = usecase.NewOutboxPublisher(
+				publisher,
+				outbox,
+				channels.SplitNotification,
+				cfg.Service.OutboxMaxAttempts,
+				deadLetters,
+				logger,
+			)
+			publisher = outboxPublisher
+		} else {
+			publisher = deadLetters.Publisher(publisher)
+		}
+	}
+
+	// Batch transfers into ClickHouse only if configured
+	var transferAnalytics *usecase.TransferAnalytics
+	if cfg.ClickHouse.URL != "" {
+		transferWriter, err := clickhouse.NewTransferWriter(context.Background(), cfg.ClickHouse)
+		if err != nil {
+			logger.Fatal("Failed to connect to ClickHouse", zap.Error(err))
+		}
+		transferAnalytics, err = usecase.NewTransferAnalytics(transferWriter, usecase.TransferAnalyticsConfig{
+			BatchSize:     cfg.ClickHouse.BatchSize,
+			FlushInterval: cfg.ClickHouse.FlushInterval,
+			QueueSize:     cfg.ClickHouse.QueueSize,
+			Backpressure:  cfg.ClickHouse.Backpressure,
+			MaxAttempts:   cfg.ClickHouse.MaxAttempts,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize transfer analytics", zap.Error(err))
+		}
+	}
+
 	// Initialize blockchain client
-	blockchainClient, err := blockchain.NewPlasmaClient(cfg.Blockchain)
+	var checkpoints domain.CheckpointStore = redis.NewCheckpointStore(redisClient, instanceKey(cfg.Blockchain.CheckpointKey))
+	if leader != nil {
+		checkpoints = leader.FenceCheckpoints(checkpoints)
+	}
+	clientOpts := []blockchain.Option{
+		blockchain.WithCheckpointStore(checkpoints),
+		blockchain.WithDrainTimeout(cfg.Service.ShutdownTimeout),
+		blockchain.WithTokenMetadataStore(
+			redis.NewTokenMetadataStore(redisClient, cfg.Blockchain.TokenMetadataTTL),
+		),
+	}
+	if transferAnalytics != nil {
+		clientOpts = append(clientOpts, blockchain.WithTransferSink(transferAnalytics, cfg.ClickHouse.Firehose))
+	}
+	if cfg.Service.Firehose {
+		clientOpts = append(clientOpts, blockchain.WithTransferSink(usecase.NewFirehosePublisher(publisher, logger), true))
+	}
+	blockchainClient, err := blockchain.NewPlasmaClient(cfg.Blockchain, clientOpts...)
 	if err != nil {
 		logger.Fatal("Failed to initialize blockchain client", zap.Error(err))
 	}
 
-	// Initialize Redis publisher/subscriber
-	publisher := redis.NewPublisher(redisClient, logger)
-	subscriber := redis.NewSubscriber(redisClient, logger)
+	var subscriber domain.Subscriber
+	switch cfg.Service.CommandTransport {
+	case "pubsub":
+		subscriber = redis.NewSubscriber(
+			redisClient,
+			channels.Commands,
+			cfg.Service.CommandHealthInterval,
+			wireCodec,
+			deadLetters.Queue(),
+			logger,
+		)
+	case "streams":
+		subscriber = redis.NewStreamSubscriber(
+			redisClient,
+			channels.Commands,
+			cfg.Service.CommandGroup,
+			cfg.Service.CommandConsumer,
+			cfg.Service.CommandClaimIdle,
+			wireCodec,
+			deadLetters.Queue(),
+			logger,
+		)
+	case "nats":
+		subscriber = nats.NewSubscriber(natsClient, channels.Commands, wireCodec, deadLetters.Queue(), logger)
+	default:
+		logger.Fatal("Unknown command transport", zap.String("transport", cfg.Service.CommandTransport))
+	}
+
+	// Initialize address label registry
+	labelRegistry := usecase.NewLabelRegistry(redis.NewLabelRepository(redisClient), logger)
+	if err := labelRegistry.Load(context.Background()); err != nil {
+		logger.Fatal("Failed to load address labels", zap.Error(err))
+	}
+	if cfg.Service.LabelsFile != "" {
+		if err := labelRegistry.LoadFile(cfg.Service.LabelsFile); err != nil {
+			logger.Fatal("Failed to load address labels file", zap.Error(err))
+		}
+	}
+
+	// Initialize spam token filters
+	tokenFilters, err := usecase.NewTokenFilters(
+		redis.NewTokenFilterRepository(redisClient),
+		domain.TokenFilter{
+			Allow: cfg.Service.TokenAllowlist,
+			Deny:  cfg.Service.TokenDenylist,
+		},
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("Invalid token filter configuration", zap.Error(err))
+	}
+	if err := tokenFilters.Load(context.Background()); err != nil {
+		logger.Fatal("Failed to load token filters", zap.Error(err))
+	}
+
+	// Initialize USD price enrichment
+	var priceProviders []domain.PriceProvider
+	for _, name := range cfg.Pricing.Providers {
+		switch name {
+		case "coingecko":
+			priceProviders = append(priceProviders, pricing.NewCoinGeckoProvider(
+				cfg.Pricing.CoinGeckoURL,
+				cfg.Pricing.CoinGeckoAPIKey,
+				cfg.Pricing.CoinGeckoPlatform,
+				cfg.Pricing.CoinGeckoNativeID,
+			))
+		case "twap":
+			twap, err := blockchain.NewTWAPPriceProvider(
+				blockchainClient,
+				cfg.Pricing.TWAPPools,
+				cfg.Pricing.Stablecoins,
+				cfg.Pricing.WrappedNative,
+				cfg.Pricing.TWAPWindow,
+			)
+			if err != nil {
+				logger.Fatal("Invalid TWAP pricing configuration", zap.Error(err))
+			}
+			priceProviders = append(priceProviders, twap)
+		default:
+			logger.Fatal("Unknown price provider", zap.String("provider", name))
+		}
+	}
+	priceService := usecase.NewPriceService(
+		priceProviders,
+		cfg.Pricing.Stablecoins,
+		cfg.Pricing.CacheTTL,
+		cfg.Pricing.RateLimit,
+		logger,
+	)
+
+	// Initialize threshold and behavioral alert rules
+	alertEngine := usecase.NewAlertEngine(
+		blockchainClient,
+		redis.NewAlertRuleRepository(redisClient),
+		publisher,
+		logger,
+	)
+	if err := alertEngine.Load(context.Background()); err != nil {
+		logger.Fatal("Failed to load alert rules", zap.Error(err))
+	}
+
+	// Initialize gas usage analytics
+	gasAnalytics := usecase.NewGasAnalytics(redis.NewGasUsageRepository(redisClient), logger)
+
+	// Initialize tracking limits
+	listenerGuard := usecase.NewListenerGuard(
+		cfg.Service.MaxTrackedWallets,
+		cfg.Service.MaxMemoryMB,
+		cfg.Service.EvictionPolicy,
+	)
+	userLimits := usecase.NewUserLimits(
+		redis.NewWalletLimitRepository(redisClient),
+		cfg.Service.MaxWalletsPerUser,
+		cfg.Service.AdminUserIDs,
+		logger,
+	)
+	if err := userLimits.Load(context.Background()); err != nil {
+		logger.Fatal("Failed to load wallet limits", zap.Error(err))
+	}
+
+	walletGroups := usecase.NewWalletGroups(redis.NewWalletGroupRepository(redisClient), logger)
+	if err := walletGroups.Load(context.Background()); err != nil {
+		logger.Fatal("Failed to load wallet groups", zap.Error(err))
+	}
+
+	// Initialize audit trail of commands and subscription changes
+	var auditLog domain.AuditLog
+	switch cfg.Service.AuditLog {
+	case "":
+	case "redis":
+		auditLog = redis.NewAuditLog(redisClient, cfg.Service.AuditStream)
+	case "file":
+		fileLog, err := file.NewAuditLog(cfg.Service.AuditFile)
+		if err != nil {
+			logger.Fatal("Failed to open audit log", zap.Error(err))
+		}
+		defer fileLog.Close()
+		auditLog = fileLog
+	default:
+		logger.Fatal("Unknown audit log", zap.String("audit_log", cfg.Service.AuditLog))
+	}
+	auditTrail := usecase.NewAuditTrail(auditLog, logger)
+
+	// PostgreSQL, if configured, holds subscriptions instead of Redis and
+	// stores published transactions
+	var (
+		walletRepo      domain.WalletRepository = redis.NewWalletRepository(redisClient)
+		transactionRepo domain.TransactionRepository
+	)
+	if postgresClient != nil {
+		walletRepo = postgres.NewWalletRepository(postgresClient)
+		transactionRepo = postgres.NewTransactionRepository(postgresClient)
+	}
+	transactionArchive := usecase.NewTransactionArchive(transactionRepo, logger)
+
+	// Split the wallets with the other instances if sharding; tasks run
+	// alone
+	var shards *usecase.ShardCoordinator
+	if cfg.Shard.Enabled && task == nil {
+		shards = usecase.NewShardCoordinator(
+			redis.NewShardMembership(redisClient),
+			redis.NewSubscriptionChanges(
+				redisClient,
+				cfg.Shard.ChangesChannel,
+				cfg.Service.CommandHealthInterval,
+				logger,
+			),
+			cfg.Shard.InstanceID,
+			cfg.Shard.HeartbeatInterval,
+			cfg.Shard.MemberTTL,
+			logger,
+		)
+		if err := shards.Join(context.Background()); err != nil {
+			logger.Fatal("Failed to join shard ring", zap.Error(err))
+		}
+	}
 
 	// Initialize wallet tracker service
 	walletTracker := usecase.NewWalletTracker(
 		blockchainClient,
 		publisher,
+		walletRepo,
+		listenerGuard,
+		userLimits,
+		auditTrail,
+		usecase.WalletTrackerOptions{
+			Labels:       labelRegistry,
+			TokenFilters: tokenFilters,
+			Prices:       priceService,
+			Alerts:       alertEngine,
+			GasAnalytics: gasAnalytics,
+			Groups:       walletGroups,
+			Archive:      transactionArchive,
+			Shards:       shards,
+			PendingStore: redis.NewPendingStore(redisClient, instanceKey("pending_confirmations")),
+			Overflow:     redis.NewOverflowQueue(redisClient, instanceKey("publish_overflow")),
+			DedupStore:   redis.NewDedupStore(redisClient),
+			Confirmations: usecase.ConfirmationPolicy{
+				Confirmations:     cfg.Blockchain.Confirmations,
+				NotifyUnconfirmed: cfg.Blockchain.NotifyUnconfirmed,
+			},
+			Queue: usecase.PublishQueueConfig{
+				Workers:      cfg.Service.WorkerCount,
+				Size:         cfg.Service.PublishQueueSize,
+				DrainTimeout: cfg.Service.ShutdownTimeout,
+			},
+			RateLimit: usecase.NotificationRateLimit{
+				PerMinute: cfg.Service.NotificationRateLimit,
+				Burst:     cfg.Service.NotificationBurst,
+			},
+			BackfillBlocks: cfg.Blockchain.BackfillBlocks,
+			DedupTTL:       cfg.Service.DedupTTL,
+			SlimPayload:    cfg.Service.SlimNotifications,
+		},
+		logger,
+	)
+
+	// Initialize portfolio snapshot service
+	portfolioService := usecase.NewPortfolioService(
+		blockchainClient,
+		walletTracker,
+		tokenFilters,
+		cfg.Service.PortfolioTokens,
+		logger,
+	)
+
+	// Initialize periodic balance snapshots and portfolio valuation
+	var snapshotRepo domain.BalanceSnapshotRepository
+	if postgresClient != nil {
+		snapshotRepo = postgres.NewBalanceSnapshotRepository(postgresClient)
+	}
+	balanceSnapshots := usecase.NewBalanceSnapshots(
+		blockchainClient,
+		walletTracker,
+		priceService,
+		snapshotRepo,
+		cfg.Service.PortfolioTokens,
+		cfg.Service.SnapshotInterval,
+		cfg.Service.SnapshotRetention,
+		logger,
+	)
+
+	// Initialize address history lookups
+	historyService := usecase.NewHistoryService(
+		blockchainClient,
+		labelRegistry,
+		transactionArchive,
+		cfg.Service.HistoryMaxBlocks,
+		logger,
+	)
+
+	// Initialize scheduled activity summaries
+	var summarySchedules []usecase.SummarySchedule
+	if cfg.Service.SummaryDaily != "" {
+		summarySchedules = append(summarySchedules, usecase.SummarySchedule{
+			Period: domain.DailySummary,
+			Cron:   cfg.Service.SummaryDaily,
+		})
+	}
+	if cfg.Service.SummaryWeekly != "" {
+		summarySchedules = append(summarySchedules, usecase.SummarySchedule{
+			Period: domain.WeeklySummary,
+			Cron:   cfg.Service.SummaryWeekly,
+		})
+	}
+	summaryScheduler, err := usecase.NewSummaryScheduler(
+		walletTracker,
+		transactionArchive,
+		publisher,
+		summarySchedules,
+		cfg.Service.SummaryTransfers,
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("Failed to initialize activity summaries", zap.Error(err))
+	}
+
+	// Initialize custom contract event watcher
+	contractWatcher := usecase.NewContractWatcher(
+		blockchainClient,
+		publisher,
+		redis.NewContractRepository(redisClient),
+		auditTrail,
+		logger,
+	)
+
+	// Initialize token transfer watcher
+	tokenWatcher := usecase.NewTokenWatcher(
+		blockchainClient,
+		publisher,
+		redis.NewTokenSubscriptionRepository(redisClient),
+		labelRegistry,
+		auditTrail,
+		logger,
+	)
+
+	// Tunable settings are re-read from the environment and config file on
+	// SIGHUP or reload_config
+	configReloader := usecase.NewConfigReloader(
+		func() (usecase.Tunables, error) {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return usecase.Tunables{}, err
+			}
+			return tunables(cfg, sinks.Names()), nil
+		},
+		tunables(cfg, sinks.Names()),
+		logLevel,
+		tokenFilters,
+		walletTracker,
+		sinks,
 		logger,
 	)
 
 	// Initialize command handler
-	commandHandler := usecase.NewCommandHandler(walletTracker, logger)
+	commandHandler := usecase.NewCommandHandler(
+		walletTracker,
+		labelRegistry,
+		portfolioService,
+		balanceSnapshots,
+		gasAnalytics,
+		contractWatcher,
+		tokenWatcher,
+		tokenFilters,
+		historyService,
+		transactionArchive,
+		alertEngine,
+		webhookDispatcher,
+		userLimits,
+		walletGroups,
+		auditTrail,
+		logLevel,
+		configReloader,
+		deadLetters,
+		publisher,
+		logger,
+	)
+
+	// A task runs instead of the services: nothing follows the chain or
+	// takes commands
+	if task != nil {
+		err := runTask(task, logger, cfg.Service.ShutdownTimeout, walletTracker, webhookDispatcher, chatNotifier)
+		blockchainClient.Close()
+		redisClient.Close()
+		if natsClient != nil {
+			natsClient.Close()
+		}
+		if postgresClient != nil {
+			postgresClient.Close()
+		}
+		if err != nil {
+			logger.Fatal("Task failed", zap.Error(err))
+		}
+		return
+	}
 
+	// Components are stopped in stages on shutdown, each with its own context
+	commandCtx, stopCommands := context.WithCancel(context.Background())
+	chainCtx, stopChain := context.WithCancel(context.Background())
 	ctx, cancel := context.WithCancel(context.Background())
+	defer stopCommands()
+	defer stopChain()
 	defer cancel()
 
 	// Start HTTP server for health checks
-	go startHTTPServer(logger, redisClient, blockchainClient)
+	server, err := startHTTPServer(
+		logger,
+		logLevel,
+		cfg.Service,
+		redisClient,
+		postgresClient,
+		blockchainClient,
+		labelRegistry,
+		historyService,
+		transactionArchive,
+		balanceSnapshots,
+		gasAnalytics,
+		walletTracker,
+		contractWatcher,
+		tokenWatcher,
+		auditTrail,
+		notificationHub,
+		leader,
+	)
+	if err != nil {
+		logger.Fatal("Failed to start HTTP server", zap.Error(err))
+	}
 
-	// Start command subscriber
-	go subscriber.SubscribeCommands(ctx, commandHandler.HandleCommand)
+	// 3. and 4. of the shutdown below, also all a standby has to stop
+	var grpcServer *grpc.Server
+	stopServing := func(deadline context.Context) {
+		// 3. Let in-flight HTTP and gRPC requests complete; notification
+		// streams would never finish by themselves and are ended by closing
+		// the hub
+		notificationHub.Close()
+		if err := server.Shutdown(deadline); err != nil {
+			logger.Error("Failed to shut down HTTP server", zap.Error(err))
+		}
+		if grpcServer != nil {
+			stopGRPCServer(deadline, logger, grpcServer)
+		}
 
-	// Start wallet tracker
-	go walletTracker.Start(ctx)
+		// 4. Close clients last, publishing has stopped
+		blockchainClient.Close()
+		if err := redisClient.Close(); err != nil {
+			logger.Error("Failed to close Redis client", zap.Error(err))
+		}
+		if natsClient != nil {
+			if err := natsClient.Close(); err != nil {
+				logger.Error("Failed to close NATS client", zap.Error(err))
+			}
+		}
+		if postgresClient != nil {
+			postgresClient.Close()
+		}
+		if err := shutdownTracing(deadline); err != nil {
+			logger.Error("Failed to flush traces", zap.Error(err))
+		}
+	}
 
-	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
 
-	logger.Info("Shutting down gracefully...")
+	// SIGHUP stays caught until exit, so it never kills the process; the
+	// configuration is only reloaded once started and until shutdown
+	var reloadReady atomic.Bool
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func() {
+		for range reloadChan {
+			if !reloadReady.Load() {
+				logger.Info("Ignoring SIGHUP, configuration is only reloaded while running")
+				continue
+			}
+			if _, err := configReloader.Reload(); err != nil {
+				logger.Error("Failed to reload configuration", zap.Error(err))
+			}
+		}
+	}()
+
+	// Stand by until elected; nothing below runs on a standby
+	electionCtx, stopElection := context.WithCancel(context.Background())
+	defer stopElection()
+	electionDone := run(func() {
+		if leader != nil {
+			leader.Start(electionCtx)
+		}
+	})
+	var leaderLost <-chan struct{}
+	if leader != nil {
+		logger.Info("Standing by until elected leader")
+		select {
+		case <-sigChan:
+			logger.Info("Shutting down standby...")
+			stopElection()
+			deadline, cancelDeadline := context.WithTimeout(context.Background(), cfg.Service.ShutdownTimeout)
+			defer cancelDeadline()
+			awaitStage(deadline, logger, "leader election", electionDone)
+			stopServing(deadline)
+			logger.Info("Shutdown complete")
+			return
+		case <-leader.Elected():
+		}
+		leaderLost = leader.Lost()
+	}
+
+	// Start gRPC API if configured
+	if cfg.Service.GRPCAddr != "" {
+		grpcServer, err = startGRPCServer(logger, cfg.Service, commandHandler, notificationHub)
+		if err != nil {
+			logger.Fatal("Failed to start gRPC server", zap.Error(err))
+		}
+	}
+
+	// Start command subscriber
+	commandsDone := run(func() {
+		if err := subscriber.SubscribeCommands(commandCtx, commandHandler.HandleCommand); err != nil &&
+			!errors.Is(err, context.Canceled) {
+			logger.Error("Command subscriber stopped", zap.Error(err))
+		}
+	})
+
+	// Start wallet tracker, and heartbeats to the other instances if sharding
+	trackerDone := run(func() { walletTracker.Start(ctx) })
+	shardsDone := run(func() {
+		if shards != nil {
+			shards.Start(ctx)
+		}
+	})
+
+	// Start shared block follower once the watched wallets are restored;
+	// blocks followed before then would be checkpointed unmatched
+	chainDone := run(func() {
+		select {
+		case <-walletTracker.Restored():
+		case <-chainCtx.Done():
+			return
+		}
+		if err := blockchainClient.Start(chainCtx); err != nil {
+			logger.Error("Block follower stopped", zap.Error(err))
+		}
+	})
+
+	// Start activity summaries and balance snapshots
+	summariesDone := run(func() { summaryScheduler.Start(ctx) })
+	snapshotsDone := run(func() { balanceSnapshots.Start(ctx) })
+
+	// Start contract watcher
+	watcherDone := run(func() { contractWatcher.Start(ctx) })
+
+	// Start token transfer watcher
+	tokensDone := run(func() { tokenWatcher.Start(ctx) })
+
+	// Start webhook and Telegram delivery and analytics, stopped once
+	// publishing has
+	sinksCtx, stopSinks := context.WithCancel(context.Background())
+	defer stopSinks()
+	analyticsDone := run(func() {
+		if transferAnalytics != nil {
+			transferAnalytics.Start(sinksCtx)
+		}
+	})
+	webhooksDone := run(func() { webhookDispatcher.Start(sinksCtx) })
+	chatsDone := run(func() {
+		if chatNotifier != nil {
+			chatNotifier.Start(sinksCtx)
+		}
+	})
+
+	// Start publishing from the outbox, stopped once it is drained
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+	outboxDone := run(func() {
+		if outboxPublisher != nil {
+			outboxPublisher.Start(outboxCtx)
+		}
+	})
+
+	// Reload tunable settings on SIGHUP from now until shutdown
+	reloadReady.Store(true)
+
+	// Wait for interrupt signal, or for the lease to be lost to another
+	// instance; a deposed leader publishes until it has stopped, the dedup
+	// store dropping what its successor publishes again
+	deposed := false
+	select {
+	case <-sigChan:
+	case <-leaderLost:
+		deposed = true
+	}
+	reloadReady.Store(false)
+
+	logger.Info("Shutting down gracefully...", zap.Duration("timeout", cfg.Service.ShutdownTimeout))
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), cfg.Service.ShutdownTimeout)
+	defer cancelDeadline()
+
+	// 1. Stop accepting commands
+	stopCommands()
+	awaitStage(deadline, logger, "command subscriber", commandsDone)
+
+	// 2. Stop following heads and let the pipeline deliver the blocks in it to
+	// the wallet listeners; then the tracker closes the listeners, which
+	// hand what they hold to the publish queue before it drains
+	stopChain()
+	awaitStage(deadline, logger, "block follower", chainDone)
 	cancel()
+	awaitStage(deadline, logger, "wallet tracker", trackerDone)
+	awaitStage(deadline, logger, "shard coordinator", shardsDone)
+	awaitStage(deadline, logger, "activity summaries", summariesDone)
+	awaitStage(deadline, logger, "balance snapshots", snapshotsDone)
+	awaitStage(deadline, logger, "contract watcher", watcherDone)
+	awaitStage(deadline, logger, "token watcher", tokensDone)
+	if outboxPublisher != nil {
+		outboxPublisher.Drain(deadline)
+	}
+	stopOutbox()
+	awaitStage(deadline, logger, "outbox", outboxDone)
+	webhookDispatcher.Drain(deadline)
+	if chatNotifier != nil {
+		chatNotifier.Drain(deadline)
+	}
+	if transferAnalytics != nil {
+		transferAnalytics.Drain(deadline)
+	}
+	stopSinks()
+	awaitStage(deadline, logger, "webhook dispatcher", webhooksDone)
+	awaitStage(deadline, logger, "chat notifier", chatsDone)
+	awaitStage(deadline, logger, "transfer analytics", analyticsDone)
+
+	// Hand over to a standby, with the checkpoint at the last processed block
+	stopElection()
+	awaitStage(deadline, logger, "leader election", electionDone)
+	if leader != nil {
+		leader.Resign(deadline)
+	}
+
+	stopServing(deadline)
+
+	// Exit with an error so the deposed leader is restarted as a standby
+	if deposed {
+		logger.Fatal("Shut down after losing leadership")
+	}
+	logger.Info("Shutdown complete")
+}
+
+// instanceID returns the configured instance ID, or one made of the
+// hostname and process ID
+func instanceID(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// run calls fn in a goroutine and returns a channel closed when it returns
+func run(fn func()) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	return done
+}
+
+// awaitStage waits for a component to stop, giving up at the shutdown deadline
+func awaitStage(deadline context.Context, logger *zap.Logger, name string, done <-chan struct{}) {
+	select {
+	case <-done:
+		logger.Info("Stopped " + name)
+	case <-deadline.Done():
+		logger.Warn("Shutdown timeout reached, not waiting for "+name, zap.String("component", name))
+	}
+}
+
+// Message kinds the in-process sinks handle; transports handle all
+var sinkKinds = map[string][]string{
+	"live":     {"notification"},
+	"webhooks": {"notification"},
+	"telegram": {"notification", "alert"},
+}
+
+// newSink returns the publishing sink name configured by cfg
+func newSink(
+	name string,
+	publisher domain.Publisher,
+	cfg config.SinkConfig,
+	required bool,
+) usecase.Sink {
+	return usecase.Sink{
+		Name:      name,
+		Publisher: publisher,
+		Filter:    sinkFilter(name, cfg),
+		Retry: usecase.SinkRetry{
+			MaxAttempts: cfg.MaxAttempts,
+			MinBackoff:  cfg.MinBackoff,
+			MaxBackoff:  cfg.MaxBackoff,
+		},
+		Required: required && !cfg.Optional,
+	}
+}
+
+// sinkFilter returns the messages sink name receives: the kinds it handles
+// unless cfg narrows them
+func sinkFilter(name string, cfg config.SinkConfig) usecase.SinkFilter {
+	kinds := sinkKinds[name]
+	if len(cfg.Kinds) > 0 {
+		kinds = cfg.Kinds
+	}
+	types := make([]domain.NotificationType, len(cfg.NotificationTypes))
+	for i, notificationType := range cfg.NotificationTypes {
+		types[i] = domain.NotificationType(notificationType)
+	}
+
+	return usecase.SinkFilter{
+		Kinds:             kinds,
+		NotificationTypes: types,
+	}
+}
+
+// tunables returns the settings of cfg a configuration reload applies, with
+// the filters of the registered sinks
+func tunables(cfg *config.Config, sinks []string) usecase.Tunables {
+	sinkConfigs := map[string]config.SinkConfig{
+		"pubsub":   cfg.Sinks.PubSub,
+		"streams":  cfg.Sinks.Streams,
+		"nats":     cfg.Sinks.NATS,
+		"live":     cfg.Sinks.Live,
+		"webhooks": cfg.Sinks.Webhooks,
+		"telegram": cfg.Sinks.Telegram,
+	}
+	filters := make(map[string]usecase.SinkFilter, len(sinks))
+	for _, name := range sinks {
+		filters[name] = sinkFilter(name, sinkConfigs[name])
+	}
+
+	return usecase.Tunables{
+		LogLevel: cfg.Log.Level,
+		TokenFilter: domain.TokenFilter{
+			Allow: cfg.Service.TokenAllowlist,
+			Deny:  cfg.Service.TokenDenylist,
+		},
+		RateLimit: usecase.NotificationRateLimit{
+			PerMinute: cfg.Service.NotificationRateLimit,
+			Burst:     cfg.Service.NotificationBurst,
+		},
+		Confirmations: usecase.ConfirmationPolicy{
+			Confirmations:     cfg.Blockchain.Confirmations,
+			NotifyUnconfirmed: cfg.Blockchain.NotifyUnconfirmed,
+		},
+		SinkFilters: filters,
+	}
 }
 
 func startHTTPServer(
 	logger *zap.Logger,
+	logLevel zap.AtomicLevel,
+	serviceCfg config.ServiceConfig,
 	redisClient *redis.Client,
+	postgresClient *postgres.Client,
 	blockchainClient *blockchain.PlasmaClient,
-) {
+	labelRegistry *usecase.LabelRegistry,
+	historyService *usecase.HistoryService,
+	transactionArchive *usecase.TransactionArchive,
+	balanceSnapshots *usecase.BalanceSnapshots,
+	gasAnalytics *usecase.GasAnalytics,
+	walletTracker *usecase.WalletTracker,
+	contractWatcher *usecase.ContractWatcher,
+	tokenWatcher *usecase.TokenWatcher,
+	auditTrail *usecase.AuditTrail,
+	notificationHub *usecase.NotificationHub,
+	leader *usecase.LeaderElector,
+) (*http.Server, error) {
 	mux := http.NewServeMux()
 
 	// Health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		healthCheck(w, r, logger, redisClient, blockchainClient)
+		healthCheck(w, r, logger, redisClient, postgresClient, blockchainClient)
 	})
 
 	// Readiness check endpoint
 	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		readinessCheck(w, r, logger, redisClient, blockchainClient)
+		readinessCheck(w, r, logger, serviceCfg, redisClient, postgresClient, blockchainClient, walletTracker, leader)
 	})
 
-	// Metrics endpoint (placeholder)
-	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("# Metrics placeholder\n"))
-	})
+	// Live notifications of a user over WebSocket, or as Server-Sent Events
+	// resuming after Last-Event-ID
+	if serviceCfg.StreamToken != "" {
+		mux.HandleFunc("GET /v1/stream", func(w http.ResponseWriter, r *http.Request) {
+			streamNotifications(w, r, logger, notificationHub, serviceCfg.StreamToken)
+		})
+		mux.HandleFunc("GET /v1/events", func(w http.ResponseWriter, r *http.Request) {
+			streamEvents(w, r, logger, notificationHub, serviceCfg.StreamToken)
+		})
+	}
+
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", promhttp.Handler())
+
+	// Profiling, state dumps and admin endpoints, only with a token configured
+	if serviceCfg.DebugToken != "" {
+		mux.Handle("/debug/", requireToken(serviceCfg.DebugToken, debugHandler(
+			logger,
+			blockchainClient,
+			walletTracker,
+			contractWatcher,
+			tokenWatcher,
+		)))
+
+		// Current log level on GET, changed with PUT {"level":"debug"}
+		mux.Handle("/admin/loglevel", requireToken(serviceCfg.DebugToken, logLevel))
+
+		// Bulk address label import (CSV or JSON body)
+		mux.Handle("POST /labels/import", requireToken(serviceCfg.DebugToken, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				importLabels(w, r, logger, labelRegistry)
+			},
+		)))
+
+		// Audit entries of any user
+		mux.Handle("GET /audit", requireToken(serviceCfg.DebugToken, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				getAuditLog(w, r, logger, auditTrail)
+			},
+		)))
+	}
+
+	// Wallet and portfolio API, only with an API token configured
+	if serviceCfg.APIToken != "" {
+		// Paginated transaction history of any address
+		mux.Handle("GET /v1/wallets/{address}/history", requireToken(serviceCfg.APIToken, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				getHistory(w, r, logger, historyService)
+			},
+		)))
+
+		// Aggregates over the stored transactions of any tracked wallet
+		mux.Handle("GET /v1/wallets/{address}/stats", requireToken(serviceCfg.APIToken, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				getWalletStats(w, r, logger, transactionArchive)
+			},
+		)))
+
+		// Fees any wallet paid, in total or over a window such as 168h
+		mux.Handle("GET /v1/wallets/{address}/gas", requireToken(serviceCfg.APIToken, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				getWalletGasUsage(w, r, logger, gasAnalytics)
+			},
+		)))
+
+		// Value of any user's wallets by their balance snapshots
+		mux.Handle("GET /v1/users/{user_id}/portfolio", requireToken(serviceCfg.APIToken, http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				getPortfolioValue(w, r, logger, balanceSnapshots)
+			},
+		)))
+	}
+
+	tlsConfig, err := loadTLSConfig(serviceCfg)
+	if err != nil {
+		return nil, err
+	}
+	useTLS := tlsConfig != nil
 
 	server := &http.Server{
-		Addr:    ":8080",
-		Handler: mux,
+		Addr:              serviceCfg.HTTPAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: serviceCfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       serviceCfg.HTTPReadTimeout,
+		WriteTimeout:      serviceCfg.HTTPWriteTimeout,
+		IdleTimeout:       serviceCfg.HTTPIdleTimeout,
+		ErrorLog:          zap.NewStdLog(logger.Named("http")),
+		TLSConfig:         tlsConfig,
+	}
+
+	// Bind before returning so a taken port fails startup
+	listener, err := net.Listen("tcp", serviceCfg.HTTPAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", serviceCfg.HTTPAddr, err)
+	}
+
+	go func() {
+		logger.Info("Starting HTTP server",
+			zap.String("addr", listener.Addr().String()),
+			zap.Bool("tls", useTLS),
+		)
+
+		var err error
+		if useTLS {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("HTTP server failed", zap.Error(err))
+		}
+	}()
+
+	return server, nil
+}
+
+// startGRPCServer serves the gRPC API on serviceCfg.GRPCAddr
+func startGRPCServer(
+	logger *zap.Logger,
+	serviceCfg config.ServiceConfig,
+	commandHandler *usecase.CommandHandler,
+	notificationHub *usecase.NotificationHub,
+) (*grpc.Server, error) {
+	if serviceCfg.GRPCToken == "" {
+		return nil, errors.New("a gRPC token is required to serve the gRPC API")
 	}
 
-	logger.Info("Starting HTTP server on :8080")
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		logger.Error("HTTP server failed", zap.Error(err))
+	tlsConfig, err := loadTLSConfig(serviceCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", serviceCfg.GRPCAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", serviceCfg.GRPCAddr, err)
 	}
+
+	server := grpcserver.New(commandHandler, notificationHub, serviceCfg.GRPCToken, tlsConfig, logger)
+	go func() {
+		logger.Info("Starting gRPC server",
+			zap.String("addr", listener.Addr().String()),
+			zap.Bool("tls", tlsConfig != nil),
+		)
+		if err := server.Serve(listener); err != nil {
+			logger.Error("gRPC server failed", zap.Error(err))
+		}
+	}()
+
+	return server, nil
+}
+
+// stopGRPCServer lets in-flight calls finish, cutting them off at the
+// shutdown deadline
+func stopGRPCServer(deadline context.Context, logger *zap.Logger, server *grpc.Server) {
+	stopped := run(server.GracefulStop)
+	select {
+	case <-stopped:
+	case <-deadline.Done():
+		logger.Warn("Shutdown timeout reached, stopping gRPC server")
+		server.Stop()
+	}
+}
+
+// loadTLSConfig loads the configured certificate, so a bad one fails
+// startup. It returns nil if TLS isn't configured.
+func loadTLSConfig(serviceCfg config.ServiceConfig) (*tls.Config, error) {
+	if serviceCfg.TLSCertFile == "" && serviceCfg.TLSKeyFile == "" {
+		return nil, nil
+	}
+	if serviceCfg.TLSCertFile == "" || serviceCfg.TLSKeyFile == "" {
+		return nil, errors.New("both TLS certificate and key files must be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(serviceCfg.TLSCertFile, serviceCfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
 }
 
 func healthCheck(
@@ -124,6 +1234,7 @@ func healthCheck(
 	r *http.Request,
 	logger *zap.Logger,
 	redisClient *redis.Client,
+	postgresClient *postgres.Client,
 	blockchainClient *blockchain.PlasmaClient,
 ) {
 	w.Header().Set("Content-Type", "application/json")
@@ -136,8 +1247,18 @@ func healthCheck(
 		return
 	}
 
+	// Check PostgreSQL connection, if used
+	if postgresClient != nil {
+		if err := postgresClient.Ping(r.Context()); err != nil {
+			logger.Error("Health check failed: PostgreSQL unavailable", zap.Error(err))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":"unhealthy","error":"postgres_unavailable"}`))
+			return
+		}
+	}
+
 	// Check blockchain connection
-	if _, err := blockchainClient.GetLatestBlock(r.Context()); err != nil {
+	if err := blockchainClient.HealthCheck(r.Context()); err != nil {
 		logger.Error("Health check failed: Blockchain unavailable", zap.Error(err))
 		w.WriteHeader(http.StatusServiceUnavailable)
 		w.Write([]byte(`{"status":"unhealthy","error":"blockchain_unavailable"}`))
@@ -152,11 +1273,320 @@ func readinessCheck(
 	w http.ResponseWriter,
 	r *http.Request,
 	logger *zap.Logger,
+	serviceCfg config.ServiceConfig,
 	redisClient *redis.Client,
+	postgresClient *postgres.Client,
 	blockchainClient *blockchain.PlasmaClient,
+	walletTracker *usecase.WalletTracker,
+	leader *usecase.LeaderElector,
 ) {
 	w.Header().Set("Content-Type", "application/json")
 
+	// A standby tracks nothing until elected
+	if leader != nil && !leader.IsLeader() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"unready","error":"standby"}`))
+		return
+	}
+
+	// Wallets whose listeners stay dead aren't tracked
+	if health := walletTracker.ListenerHealth(); !health.Ready {
+		logger.Error("Readiness check failed: wallet listeners down", zap.Int("down", len(health.Down)))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":         "unready",
+			"error":          "listeners_down",
+			"down_listeners": health.Down,
+		})
+		return
+	}
+
+	// Too far behind the chain to notify in time
+	if lag, known := blockchainClient.BlockLag(); known && serviceCfg.MaxBlockLag > 0 && lag > serviceCfg.MaxBlockLag {
+		logger.Error("Readiness check failed: block lag too high", zap.Uint64("lag", lag))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":        "unready",
+			"error":         "block_lag",
+			"block_lag":     lag,
+			"max_block_lag": serviceCfg.MaxBlockLag,
+		})
+		return
+	}
+	if latency, known := walletTracker.NotificationLatency(); known &&
+		serviceCfg.MaxNotificationLatency > 0 && latency > serviceCfg.MaxNotificationLatency {
+		logger.Error("Readiness check failed: notification latency too high", zap.Duration("latency", latency))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":                   "unready",
+			"error":                    "notification_latency",
+			"notification_latency":     latency.String(),
+			"max_notification_latency": serviceCfg.MaxNotificationLatency.String(),
+		})
+		return
+	}
+
 	// Similar to health check but can include more comprehensive checks
-	healthCheck(w, r, logger, redisClient, blockchainClient)
+	healthCheck(w, r, logger, redisClient, postgresClient, blockchainClient)
+}
+
+func importLabels(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	labelRegistry *usecase.LabelRegistry,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var (
+		labels []domain.AddressLabel
+		err    error
+	)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		labels, err = usecase.ParseLabelsCSV(r.Body)
+	} else {
+		labels, err = usecase.ParseLabelsJSON(r.Body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	imported, err := labelRegistry.Import(r.Context(), labels)
+	if err != nil {
+		logger.Error("Failed to import address labels", zap.Error(err))
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidAddress) {
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]int{"imported": imported})
+}
+
+func getAuditLog(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	auditTrail *usecase.AuditTrail,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := r.URL.Query()
+	var (
+		query domain.AuditQuery
+		err   error
+	)
+	if v := params.Get("user_id"); v != "" {
+		var userID int64
+		userID, err = strconv.ParseInt(v, 10, 64)
+		query.UserID = domain.UserID(userID)
+	}
+	if v := params.Get("since"); v != "" && err == nil {
+		query.Since, err = time.Parse(time.RFC3339, v)
+	}
+	if v := params.Get("until"); v != "" && err == nil {
+		query.Until, err = time.Parse(time.RFC3339, v)
+	}
+	if v := params.Get("limit"); v != "" && err == nil {
+		query.Limit, err = strconv.Atoi(v)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	query.Address = domain.WalletAddress(params.Get("address"))
+
+	entries, err := auditTrail.Query(r.Context(), query)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrAuditLogDisabled):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrInvalidDuration):
+			status = http.StatusBadRequest
+		default:
+			logger.Error("Failed to query audit log", zap.Error(err))
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
+
+func getHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	historyService *usecase.HistoryService,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	var (
+		fromBlock, toBlock uint64
+		limit              int
+		err                error
+	)
+	if v := query.Get("from_block"); v != "" {
+		fromBlock, err = strconv.ParseUint(v, 10, 64)
+	}
+	if v := query.Get("to_block"); v != "" && err == nil {
+		toBlock, err = strconv.ParseUint(v, 10, 64)
+	}
+	if v := query.Get("limit"); v != "" && err == nil {
+		limit, err = strconv.Atoi(v)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	address := domain.WalletAddress(r.PathValue("address"))
+	history, err := historyService.GetHistory(r.Context(), address, fromBlock, toBlock, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidAddress) || errors.Is(err, domain.ErrInvalidBlockRange) {
+			status = http.StatusBadRequest
+		} else {
+			logger.Error("Failed to get address history", zap.Error(err))
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
+
+func getWalletGasUsage(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	gasAnalytics *usecase.GasAnalytics,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var window time.Duration
+	address, err := usecase.NormalizeAddress(domain.WalletAddress(r.PathValue("address")))
+	if v := r.URL.Query().Get("window"); v != "" && err == nil {
+		window, err = time.ParseDuration(v)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	usages, err := gasAnalytics.GetUsage(r.Context(), []domain.WalletAddress{address}, window)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidDuration) {
+			status = http.StatusBadRequest
+		} else {
+			logger.Error("Failed to get gas usage", zap.Error(err))
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(usages[0])
+}
+
+func getWalletStats(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	transactionArchive *usecase.TransactionArchive,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	var (
+		since, until time.Time
+		err          error
+	)
+	if v := query.Get("since"); v != "" {
+		since, err = time.Parse(time.RFC3339, v)
+	}
+	if v := query.Get("until"); v != "" && err == nil {
+		until, err = time.Parse(time.RFC3339, v)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	address := domain.WalletAddress(r.PathValue("address"))
+	stats, err := transactionArchive.Stats(r.Context(), address, since, until)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrStatsUnavailable):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrInvalidAddress), errors.Is(err, domain.ErrInvalidDuration):
+			status = http.StatusBadRequest
+		default:
+			logger.Error("Failed to get wallet stats", zap.Error(err))
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+func getPortfolioValue(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	balanceSnapshots *usecase.BalanceSnapshots,
+) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var window time.Duration
+	userID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
+	if v := r.URL.Query().Get("window"); v != "" && err == nil {
+		window, err = time.ParseDuration(v)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	value, err := balanceSnapshots.PortfolioValue(r.Context(), domain.UserID(userID), window)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrPortfolioUnvalued):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrInvalidDuration):
+			status = http.StatusBadRequest
+		default:
+			logger.Error("Failed to get portfolio value", zap.Error(err))
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(value)
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/usecase"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	// How long a write to a stream client may take
+	streamWriteWait = 10 * time.Second
+	// Clients not answering a ping within streamPongWait are disconnected
+	streamPongWait     = time.Minute
+	streamPingInterval = streamPongWait * 9 / 10
+	// Clients only send control frames, anything longer is rejected
+	streamMaxMessageSize = 512
+)
+
+// The stream token authenticates clients, so any origin may connect
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// streamNotifications upgrades to a WebSocket and sends the user_id user's
+// wallet notifications as JSON text messages until either side hangs up
+func streamNotifications(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	hub *usecase.NotificationHub,
+	token string,
+) {
+	if !validStreamToken(r, token) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid user_id"})
+		return
+	}
+
+	// Upgrade writes the error response itself
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Debug("Failed to upgrade stream connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	stream := hub.Subscribe(domain.UserID(userID))
+	defer stream.Close()
+
+	logger.Debug("Stream client connected",
+		zap.Int64("user_id", userID),
+		zap.String("remote", r.RemoteAddr),
+	)
+
+	// Reading is needed to process pongs and notice the client leaving
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+
+		conn.SetReadLimit(streamMaxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case event, ok := <-stream.C():
+			if !ok {
+				closeStream(conn, stream.Err())
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteJSON(event.Notification); err != nil {
+				logger.Debug("Failed to write to stream client", zap.Int64("user_id", userID), zap.Error(err))
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		case <-clientGone:
+			logger.Debug("Stream client disconnected", zap.Int64("user_id", userID))
+			return
+		}
+	}
+}
+
+// closeStream tells the client why the stream ended before hanging up
+func closeStream(conn *websocket.Conn, err error) {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	if errors.Is(err, domain.ErrSlowConsumer) {
+		message = websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error())
+	}
+	conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(streamWriteWait))
+}
+
+// validStreamToken checks the bearer token, also accepted as the token
+// query parameter since browsers can't set headers on WebSocket requests
+func validStreamToken(r *http.Request, token string) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		given = r.URL.Query().Get("token")
+	}
+	return given != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
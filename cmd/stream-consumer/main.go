@@ -0,0 +1,134 @@
+// Command stream-consumer is an example consumer of the notification
+// streams written when SERVICE_TRANSPORT=streams. It reads as part of a
+// consumer group, acknowledges each message after handling it, and on start
+// first re-handles messages it received but never acknowledged, then claims
+// those abandoned by consumers that went away.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/config"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func main() {
+	stream := flag.String("stream", "wallet_notifications", "stream to consume")
+	group := flag.String("group", "bot", "consumer group")
+	consumer := flag.String("consumer", hostname(), "consumer name within the group")
+	claimIdle := flag.Duration("claim-idle", time.Minute, "claim messages other consumers left unacknowledged this long")
+	configFile := flag.String("config", "", "YAML config file the tracker reads, for its Redis settings")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	client := redis.NewClient(cfg.Redis)
+	defer client.Close()
+	rdb := client.GetRedisClient()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	// Start from the beginning of the stream the first time the group is used
+	err = rdb.XGroupCreateMkStream(ctx, *stream, *group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		log.Fatal("Failed to create consumer group:", err)
+	}
+
+	// Own pending messages first ("0"), then new ones (">")
+	start := "0"
+	for ctx.Err() == nil {
+		if start == ">" {
+			claimAbandoned(ctx, rdb, *stream, *group, *consumer, *claimIdle)
+		}
+
+		streams, err := rdb.XReadGroup(ctx, &goredis.XReadGroupArgs{
+			Group:    *group,
+			Consumer: *consumer,
+			Streams:  []string{*stream, start},
+			Count:    100,
+			Block:    5 * time.Second,
+		}).Result()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Println("Failed to read stream:", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		handled := 0
+		for _, s := range streams {
+			for _, message := range s.Messages {
+				handle(ctx, rdb, *stream, *group, message)
+				handled++
+			}
+		}
+		if start == "0" && handled == 0 {
+			start = ">" // Caught up on our pending messages
+		}
+	}
+}
+
+// claimAbandoned takes over messages delivered to other consumers that were
+// not acknowledged within idle
+func claimAbandoned(
+	ctx context.Context,
+	rdb *goredis.Client,
+	stream, group, consumer string,
+	idle time.Duration,
+) {
+	messages, _, err := rdb.XAutoClaim(ctx, &goredis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  idle,
+		Start:    "0",
+		Count:    100,
+	}).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println("Failed to claim abandoned messages:", err)
+		}
+		return
+	}
+
+	for _, message := range messages {
+		handle(ctx, rdb, stream, group, message)
+	}
+}
+
+// handle prints the message and acknowledges it; a real consumer would
+// deliver it to users and skip repeated idempotency keys
+func handle(ctx context.Context, rdb *goredis.Client, stream, group string, message goredis.XMessage) {
+	fmt.Printf("%s %s\n", message.ID, message.Values["data"])
+
+	if err := rdb.XAck(ctx, stream, group, message.ID).Err(); err != nil {
+		log.Println("Failed to acknowledge message:", err)
+	}
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "consumer"
+	}
+	return name
+}
@@ -1,14 +1,27 @@
 package config
 
 import (
+	"fmt"
+	"reflect"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
 	Redis      RedisConfig      `envconfig:"REDIS"`
+	Postgres   PostgresConfig   `envconfig:"POSTGRES"`
 	Blockchain BlockchainConfig `envconfig:"BLOCKCHAIN"`
 	Service    ServiceConfig    `envconfig:"SERVICE"`
+	Pricing    PricingConfig    `envconfig:"PRICING"`
 	Log        LogConfig        `envconfig:"LOG"`
+	Tracing    TracingConfig    `envconfig:"TRACING"`
+	Telegram   TelegramConfig   `envconfig:"TELEGRAM"`
+	NATS       NATSConfig       `envconfig:"NATS"`
+	Sinks      SinksConfig      `envconfig:"SINK"`
+	ClickHouse ClickHouseConfig `envconfig:"CLICKHOUSE"`
+	Shard      ShardConfig      `envconfig:"SHARD"`
+	HA         HAConfig         `envconfig:"HA"`
 }
 
 type RedisConfig struct {
@@ -18,17 +31,341 @@ type RedisConfig struct {
 	DB       int    `envconfig:"DB"       default:"0"`
 }
 
+// PostgresConfig enables PostgreSQL storage (empty DSN = off). Wallet
+// subscriptions are then kept there instead of Redis, and every published
+// transaction is stored so history of tracked wallets is read from it.
+type PostgresConfig struct {
+	DSN      string `envconfig:"DSN"`
+	MaxConns int32  `envconfig:"MAX_CONNS" default:"10"`
+}
+
 type BlockchainConfig struct {
-	RPCURL    string `envconfig:"RPC_URL"    default:"https://rpc.plasma.network"`
-	WSURL     string `envconfig:"WS_URL"     default:"wss://ws.plasma.network"`
-	ChainID   int64  `envconfig:"CHAIN_ID"   default:"9745"`
-	BatchSize int    `envconfig:"BATCH_SIZE" default:"100"`
+	// Comma-separated endpoint lists; later entries are failover targets.
+	// Without a WebSocket endpoint new blocks are polled over HTTP.
+	RPCURLs   []string `envconfig:"RPC_URL"    default:"https://rpc.plasma.network"`
+	WSURLs    []string `envconfig:"WS_URL"     default:"wss://ws.plasma.network"`
+	ChainID   int64    `envconfig:"CHAIN_ID"   default:"9745"`
+	BatchSize int      `envconfig:"BATCH_SIZE" default:"100"`
+
+	// Spread read calls over all healthy RPC endpoints instead of failover
+	// only, and how often endpoints are health-checked
+	RPCLoadBalance         bool          `envconfig:"RPC_LOAD_BALANCE"          default:"false"`
+	RPCHealthCheckInterval time.Duration `envconfig:"RPC_HEALTH_CHECK_INTERVAL" default:"15s"`
+
+	// Per-endpoint request rate (0 = unlimited) and in-flight request cap
+	RPCRateLimit      float64 `envconfig:"RPC_RATE_LIMIT"      default:"50"`
+	RPCRateBurst      int     `envconfig:"RPC_RATE_BURST"      default:"10"`
+	RPCMaxConcurrency int     `envconfig:"RPC_MAX_CONCURRENCY" default:"16"`
+
+	// Retries per RPC call and the circuit breaker that stops calls after
+	// consecutive failures
+	RPCMaxRetries       int           `envconfig:"RPC_MAX_RETRIES"       default:"3"`
+	RPCRetryMinBackoff  time.Duration `envconfig:"RPC_RETRY_MIN_BACKOFF" default:"200ms"`
+	RPCRetryMaxBackoff  time.Duration `envconfig:"RPC_RETRY_MAX_BACKOFF" default:"5s"`
+	RPCBreakerThreshold int           `envconfig:"RPC_BREAKER_THRESHOLD" default:"10"`
+	RPCBreakerCooldown  time.Duration `envconfig:"RPC_BREAKER_COOLDOWN"  default:"30s"`
+
+	// Times a block that failed to process is resubmitted before giving up
+	MaxBlockRetries int `envconfig:"MAX_BLOCK_RETRIES" default:"10"`
+
+	// Block processing pipeline: queue capacity per stage and worker counts
+	PipelineQueueSize int `envconfig:"PIPELINE_QUEUE_SIZE" default:"64"`
+	FetchWorkers      int `envconfig:"FETCH_WORKERS"       default:"1"`
+	MatchWorkers      int `envconfig:"MATCH_WORKERS"       default:"4"`
+	DecodeWorkers     int `envconfig:"DECODE_WORKERS"      default:"2"`
+	EnrichWorkers     int `envconfig:"ENRICH_WORKERS"      default:"2"`
+
+	// Maximum number of tokens kept in memory and how long resolved
+	// metadata stays in Redis
+	TokenCacheSize   int           `envconfig:"TOKEN_CACHE_SIZE"   default:"10000"`
+	TokenMetadataTTL time.Duration `envconfig:"TOKEN_METADATA_TTL" default:"24h"`
+
+	// Maximum number of blocks and receipts cached in memory (0 = disabled),
+	// shared by all wallets matching the same block
+	BlockCacheSize   int `envconfig:"BLOCK_CACHE_SIZE"   default:"128"`
+	ReceiptCacheSize int `envconfig:"RECEIPT_CACHE_SIZE" default:"10000"`
+
+	// Multicall3 contract used to batch contract reads (empty = disabled)
+	// and the maximum number of calls per eth_call
+	MulticallAddress   string `envconfig:"MULTICALL_ADDRESS"    default:"0xcA11bde05977b3631167028862bE2a173976CA11"`
+	MulticallBatchSize int    `envconfig:"MULTICALL_BATCH_SIZE" default:"500"`
+
+	// LayerZero OFT bridge contracts (e.g. USDT0) whose deposit and
+	// withdrawal events are reported as bridge transfers
+	BridgeContracts []string `envconfig:"BRIDGE_CONTRACTS"`
+
+	// Relayers that submit gasless transactions on behalf of users; their
+	// transactions are flagged as sponsored
+	PaymasterAddresses []string `envconfig:"PAYMASTER_ADDRESSES"`
+
+	// Interval of HTTP polling for new blocks, used when no WebSocket
+	// endpoint is configured or reachable
+	PollInterval time.Duration `envconfig:"POLL_INTERVAL" default:"1s"`
+
+	// WebSocket reconnect backoff bounds
+	ReconnectMinBackoff time.Duration `envconfig:"RECONNECT_MIN_BACKOFF" default:"1s"`
+	ReconnectMaxBackoff time.Duration `envconfig:"RECONNECT_MAX_BACKOFF" default:"1m"`
+
+	// Expected block interval and how long without a new head before the
+	// subscription is considered stale and reconnected (0 = never)
+	BlockTime        time.Duration `envconfig:"BLOCK_TIME"         default:"1s"`
+	StaleHeadTimeout time.Duration `envconfig:"STALE_HEAD_TIMEOUT" default:"30s"`
+
+	// Redis key of the last processed block and how far back to replay
+	CheckpointKey    string `envconfig:"CHECKPOINT_KEY"      default:"checkpoint:last_block"`
+	MaxCatchUpBlocks int    `envconfig:"MAX_CATCH_UP_BLOCKS" default:"1000"`
+
+	// Block tag new heads are processed at: "latest", "safe" or "finalized".
+	// Safe and finalized blocks trade latency for reorg-proof notifications.
+	Finality string `envconfig:"FINALITY" default:"latest"`
+
+	// Number of recent block hashes kept for reorg detection
+	ReorgDepth int `envconfig:"REORG_DEPTH" default:"64"`
+
+	// Confirmations required before notifying, optionally with an early
+	// unconfirmed notification
+	Confirmations     uint64 `envconfig:"CONFIRMATIONS"      default:"1"`
+	NotifyUnconfirmed bool   `envconfig:"NOTIFY_UNCONFIRMED" default:"false"`
+
+	// Recent blocks replayed as historical notifications on add_wallet (0 = off)
+	BackfillBlocks uint64 `envconfig:"BACKFILL_BLOCKS" default:"500"`
+
+	// Internal transfer tracing: "" (off), "debug" (debug_trace* with the
+	// callTracer) or "parity" (trace_*), optionally on a dedicated trace node
+	TraceMode string `envconfig:"TRACE_MODE" default:""`
+	TraceURL  string `envconfig:"TRACE_URL"  default:""`
+}
+
+type PricingConfig struct {
+	// Price sources asked in order: "coingecko" and/or "twap" (empty = only
+	// stablecoins are priced)
+	Providers []string `envconfig:"PROVIDERS"`
+
+	// Tokens valued at exactly $1 without asking a provider
+	Stablecoins []string `envconfig:"STABLECOINS"`
+
+	// How long prices are cached and how many provider requests are made
+	// per minute at most
+	CacheTTL  time.Duration `envconfig:"CACHE_TTL"  default:"1m"`
+	RateLimit int           `envconfig:"RATE_LIMIT" default:"30"`
+
+	// CoinGecko API, with the chain's asset platform id and native coin id
+	CoinGeckoURL      string `envconfig:"COINGECKO_URL"       default:"https://api.coingecko.com/api/v3"`
+	CoinGeckoAPIKey   string `envconfig:"COINGECKO_API_KEY"   default:""`
+	CoinGeckoPlatform string `envconfig:"COINGECKO_PLATFORM"  default:"plasma"`
+	CoinGeckoNativeID string `envconfig:"COINGECKO_NATIVE_ID" default:"plasma"`
+
+	// Uniswap V3-style pools pairing tokens with a stablecoin, the TWAP
+	// window, and wrapped XPL whose pool prices native XPL
+	TWAPPools     []string      `envconfig:"TWAP_POOLS"`
+	TWAPWindow    time.Duration `envconfig:"TWAP_WINDOW"    default:"30m"`
+	WrappedNative string        `envconfig:"WRAPPED_NATIVE" default:""`
 }
 
 type ServiceConfig struct {
+	// Channels commands are read from and messages published on: Redis
+	// channels or streams, and NATS subjects under NATS_SUBJECT_PREFIX
 	CommandChannel      string `envconfig:"COMMAND_CHANNEL"      default:"wallet_commands"`
 	NotificationChannel string `envconfig:"NOTIFICATION_CHANNEL" default:"wallet_notifications"`
-	WorkerCount         int    `envconfig:"WORKER_COUNT"         default:"10"`
+	ResponseChannel     string `envconfig:"RESPONSE_CHANNEL"     default:"wallet_responses"`
+	ContractChannel     string `envconfig:"CONTRACT_CHANNEL"     default:"contract_events"`
+	TokenChannel        string `envconfig:"TOKEN_CHANNEL"        default:"token_transfers"`
+	AlertChannel        string `envconfig:"ALERT_CHANNEL"        default:"wallet_alerts"`
+	FirehoseChannel     string `envconfig:"FIREHOSE_CHANNEL"     default:"transfer_firehose"`
+
+	// Template of the channel each notification is published on instead of
+	// NotificationChannel, so large deployments can split consumers:
+	// {user_id} publishes a copy per subscriber (e.g.
+	// "wallet_notifications:{user_id}"), {shard} is the wallet's shard out
+	// of NotificationShards and {wallet} its address
+	NotificationRoute  string `envconfig:"NOTIFICATION_ROUTE"  default:""`
+	NotificationShards int    `envconfig:"NOTIFICATION_SHARDS" default:"16"`
+
+	WorkerCount int `envconfig:"WORKER_COUNT" default:"10"`
+
+	// How messages reach consumers, comma-separated to publish to several
+	// at once: "pubsub" (fire and forget), "streams" (Redis Streams capped
+	// at about StreamMaxLen entries, consumed with consumer groups at least
+	// once) and "nats" (a NATS JetStream stream)
+	Transport    []string `envconfig:"TRANSPORT"      default:"pubsub"`
+	StreamMaxLen int64    `envconfig:"STREAM_MAX_LEN" default:"100000"`
+
+	// Wire format of published messages and received commands: "json",
+	// "msgpack" or "protobuf" (the wire.v1 messages in api/proto/wire)
+	Codec string `envconfig:"CODEC" default:"json"`
+
+	// Publish every transfer of every block on FirehoseChannel, one
+	// message per block, whoever tracks the addresses
+	// involved. All receipts of each block are fetched for it.
+	Firehose bool `envconfig:"FIREHOSE" default:"false"`
+
+	// Encode token amounts in published JSON messages as numbers instead of
+	// decimal strings and publish notifications as schema version 1, for
+	// consumers that predate the change. Stored data keeps strings. Amounts
+	// above 2^53 lose precision in JavaScript.
+	LegacyAmounts bool `envconfig:"LEGACY_AMOUNTS" default:"false"`
+
+	// Wrap published messages in CloudEvents 1.0 envelopes (structured JSON
+	// mode, the encoded message as data) with this source and type prefix
+	CloudEvents           bool   `envconfig:"CLOUD_EVENTS"             default:"false"`
+	CloudEventsSource     string `envconfig:"CLOUD_EVENTS_SOURCE"      default:"/plasma-wallet-tracker"`
+	CloudEventsTypePrefix string `envconfig:"CLOUD_EVENTS_TYPE_PREFIX" default:"plasma.wallet_tracker"`
+
+	// How commands are received: "pubsub", "streams" (a consumer group
+	// that acknowledges commands once handled and reclaims commands left
+	// unacknowledged for CommandClaimIdle) or "nats" (a durable JetStream
+	// consumer, see NATSConfig)
+	CommandTransport string        `envconfig:"COMMAND_TRANSPORT"  default:"pubsub"`
+	CommandGroup     string        `envconfig:"COMMAND_GROUP"      default:"plasma-wallet-tracker"`
+	CommandConsumer  string        `envconfig:"COMMAND_CONSUMER"   default:"tracker"`
+	CommandClaimIdle time.Duration `envconfig:"COMMAND_CLAIM_IDLE" default:"1m"`
+
+	// Redis subscriptions (pubsub commands, shard subscription changes) that
+	// received nothing for this long are pinged, and renewed if the ping
+	// gets no reply within as long again
+	CommandHealthInterval time.Duration `envconfig:"COMMAND_HEALTH_INTERVAL" default:"15s"`
+
+	// How long shutdown waits for in-flight blocks and notifications
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"30s"`
+
+	// Listen address and timeouts of the health, metrics and admin HTTP
+	// server. WriteTimeout must outlast CPU profiles fetched from
+	// /debug/pprof/profile (30s by default); 0 disables a timeout.
+	HTTPAddr              string        `envconfig:"HTTP_ADDR"                default:":8080"`
+	HTTPReadHeaderTimeout time.Duration `envconfig:"HTTP_READ_HEADER_TIMEOUT" default:"5s"`
+	HTTPReadTimeout       time.Duration `envconfig:"HTTP_READ_TIMEOUT"        default:"30s"`
+	HTTPWriteTimeout      time.Duration `envconfig:"HTTP_WRITE_TIMEOUT"       default:"60s"`
+	HTTPIdleTimeout       time.Duration `envconfig:"HTTP_IDLE_TIMEOUT"        default:"2m"`
+
+	// Listen address of the gRPC API (empty = disabled); callers
+	// authenticate with GRPCToken as a bearer token
+	GRPCAddr  string `envconfig:"GRPC_ADDR"`
+	GRPCToken string `envconfig:"GRPC_TOKEN"`
+
+	// Serve HTTPS and gRPC over TLS with this certificate and key (both
+	// empty = plaintext)
+	TLSCertFile string `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile  string `envconfig:"TLS_KEY_FILE"`
+
+	// /ready fails while the last processed block trails the head by more
+	// than MaxBlockLag blocks, or the latest notification was published
+	// more than MaxNotificationLatency after its block (0 = not checked)
+	MaxBlockLag            uint64        `envconfig:"MAX_BLOCK_LAG"            default:"0"`
+	MaxNotificationLatency time.Duration `envconfig:"MAX_NOTIFICATION_LATENCY" default:"0"`
+
+	// Bearer token required by the /debug/pprof, /debug/state, /audit,
+	// /labels/import and /admin/loglevel endpoints (empty = endpoints
+	// disabled)
+	DebugToken string `envconfig:"DEBUG_TOKEN"`
+
+	// Bearer token required by the public /v1 wallet history, stats, gas
+	// and user portfolio endpoints (empty = endpoints disabled)
+	APIToken string `envconfig:"API_TOKEN"`
+
+	// Token clients of the /v1/stream WebSocket and /v1/events SSE
+	// endpoints authenticate with, and how many notifications a client may
+	// fall behind before it is disconnected (empty token = endpoints
+	// disabled)
+	StreamToken  string `envconfig:"STREAM_TOKEN"`
+	StreamBuffer int    `envconfig:"STREAM_BUFFER" default:"256"`
+
+	// Webhook deliveries: concurrent POSTs, deliveries waiting for one,
+	// attempts before a delivery is dead-lettered and the timeout of each.
+	// Endpoints on private networks are refused unless WebhookAllowPrivate.
+	WebhookWorkers      int           `envconfig:"WEBHOOK_WORKERS"       default:"4"`
+	WebhookQueueSize    int           `envconfig:"WEBHOOK_QUEUE_SIZE"    default:"1000"`
+	WebhookMaxAttempts  int           `envconfig:"WEBHOOK_MAX_ATTEMPTS"  default:"5"`
+	WebhookTimeout      time.Duration `envconfig:"WEBHOOK_TIMEOUT"       default:"10s"`
+	WebhookAllowPrivate bool          `envconfig:"WEBHOOK_ALLOW_PRIVATE" default:"false"`
+
+	// Notifications kept per user so SSE clients can resume after
+	// Last-Event-ID, for up to EventBufferTTL after the user's last one
+	// (0 = no resume)
+	EventBufferLen int64         `envconfig:"EVENT_BUFFER_LEN" default:"1000"`
+	EventBufferTTL time.Duration `envconfig:"EVENT_BUFFER_TTL" default:"1h"`
+
+	// Where commands and subscription changes are audited: "" (off),
+	// "redis" (the AuditStream stream) or "file" (JSON lines in AuditFile)
+	AuditLog    string `envconfig:"AUDIT_LOG"    default:""`
+	AuditStream string `envconfig:"AUDIT_STREAM" default:"audit_log"`
+	AuditFile   string `envconfig:"AUDIT_FILE"   default:"audit.log"`
+
+	// Outbox notifications are written to before being published, retried
+	// until the publish succeeds: "" (off, a failed publish is lost),
+	// "redis" or "file" (OutboxFile, synced on every write, which survives
+	// Redis outages)
+	Outbox     string `envconfig:"OUTBOX"      default:""`
+	OutboxFile string `envconfig:"OUTBOX_FILE" default:"outbox.log"`
+
+	// Attempts to publish an outbox notification before it is moved to the
+	// dead letter queue (0 = retry until published). Without a dead letter
+	// queue it is retried until published regardless.
+	OutboxMaxAttempts int `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"20"`
+
+	// Stream keeping notifications that couldn't be published and commands
+	// that couldn't be decoded or kept failing, capped at about
+	// DeadLetterMaxLen entries ("" = off, they are dropped)
+	DeadLetterStream string `envconfig:"DEAD_LETTER_STREAM"  default:"dead_letters"`
+	DeadLetterMaxLen int64  `envconfig:"DEAD_LETTER_MAX_LEN" default:"10000"`
+
+	// Transactions buffered in memory for the publishing workers; beyond
+	// that they spill to a Redis overflow queue
+	PublishQueueSize int `envconfig:"PUBLISH_QUEUE_SIZE" default:"1000"`
+
+	// Notifications each user may get per minute, with bursts of up to
+	// NotificationBurst (0 = unlimited). Excess notifications are dropped
+	// and summarized in a single rate_limited notification.
+	NotificationRateLimit int `envconfig:"NOTIFICATION_RATE_LIMIT" default:"0"`
+	NotificationBurst     int `envconfig:"NOTIFICATION_BURST"      default:"10"`
+
+	// Leave the transaction's full transfer list out of notifications; the
+	// transfers involving the wallet are still sent in their transfers field
+	SlimNotifications bool `envconfig:"SLIM_NOTIFICATIONS" default:"false"`
+
+	// How long delivered notifications are remembered to drop duplicates
+	// (0 = no deduplication)
+	DedupTTL time.Duration `envconfig:"DEDUP_TTL" default:"24h"`
+
+	// Token contracts whose balances are included in portfolio snapshots
+	PortfolioTokens []string `envconfig:"PORTFOLIO_TOKENS"`
+
+	// Global spam token filters: contract addresses or symbol patterns
+	// with * and ? wildcards. Allowlist entries win over denylist entries.
+	TokenAllowlist []string `envconfig:"TOKEN_ALLOWLIST"`
+	TokenDenylist  []string `envconfig:"TOKEN_DENYLIST"`
+
+	// CSV or JSON file of known addresses, e.g. exchange hot wallets,
+	// bridges and routers, used to label counterparties. Labels imported
+	// through Redis take precedence.
+	LabelsFile string `envconfig:"LABELS_FILE"`
+
+	// How often the balances of tracked wallets are snapshotted with their
+	// USD value (0 = off), and how long snapshots are kept (0 = forever).
+	// Snapshots are stored in PostgreSQL.
+	SnapshotInterval  time.Duration `envconfig:"SNAPSHOT_INTERVAL"  default:"0"`
+	SnapshotRetention time.Duration `envconfig:"SNAPSHOT_RETENTION" default:"720h"`
+
+	// Widest block range a single get_history request scans
+	HistoryMaxBlocks uint64 `envconfig:"HISTORY_MAX_BLOCKS" default:"10000"`
+
+	// Cron schedules of the daily and weekly activity summaries, e.g.
+	// "0 9 * * *" (empty = off), and the largest transfers each lists per
+	// wallet. Summaries are built from stored transactions, so they need
+	// PostgreSQL.
+	SummaryDaily     string `envconfig:"SUMMARY_DAILY"`
+	SummaryWeekly    string `envconfig:"SUMMARY_WEEKLY"`
+	SummaryTransfers int    `envconfig:"SUMMARY_TRANSFERS" default:"3"`
+
+	// Tracking limits (0 = unlimited) and what to do when they are hit:
+	// "reject" new wallets or "evict_lru" the least recently active one
+	MaxTrackedWallets int    `envconfig:"MAX_TRACKED_WALLETS" default:"0"`
+	MaxMemoryMB       int    `envconfig:"MAX_MEMORY_MB"       default:"0"`
+	EvictionPolicy    string `envconfig:"EVICTION_POLICY"     default:"reject"`
+
+	// Wallets each user may follow (0 = unlimited), and the users allowed
+	// to override it per user with set_wallet_limit
+	MaxWalletsPerUser int     `envconfig:"MAX_WALLETS_PER_USER" default:"0"`
+	AdminUserIDs      []int64 `envconfig:"ADMIN_USER_IDS"`
 }
 
 type LogConfig struct {
@@ -36,8 +373,172 @@ type LogConfig struct {
 	Format string `envconfig:"LOG_FORMAT" default:"json"`
 }
 
-func Load() (*Config, error) {
+type TracingConfig struct {
+	// Where spans are exported: "" (off), "otlp-grpc" or "otlp-http", to an
+	// OTLP collector at Endpoint (empty = the exporter's default)
+	Exporter string `envconfig:"EXPORTER" default:""`
+	Endpoint string `envconfig:"ENDPOINT" default:""`
+	Insecure bool   `envconfig:"INSECURE" default:"false"`
+
+	// Fraction of root traces sampled; traces continued from a command
+	// follow the caller's sampling decision
+	SampleRatio float64 `envconfig:"SAMPLE_RATIO" default:"1"`
+	ServiceName string  `envconfig:"SERVICE_NAME" default:"plasma-wallet-tracker"`
+}
+
+// TelegramConfig enables sending notifications and alerts straight to
+// subscribers' Telegram chats, without a separate consumer
+type TelegramConfig struct {
+	// Bot token from @BotFather (empty = disabled)
+	BotToken string `envconfig:"BOT_TOKEN"`
+	APIURL   string `envconfig:"API_URL"   default:"https://api.telegram.org"`
+
+	// File of MarkdownV2 templates replacing the built-in ones of the same
+	// name, and the block explorer transactions link to (empty = no links)
+	TemplatesFile string `envconfig:"TEMPLATES_FILE"`
+	ExplorerURL   string `envconfig:"EXPLORER_URL"`
+
+	// Concurrent senders (messages to one chat keep their order) and
+	// messages waiting for one; beyond that messages are dropped
+	Workers   int `envconfig:"WORKERS"    default:"4"`
+	QueueSize int `envconfig:"QUEUE_SIZE" default:"1000"`
+}
+
+// NATSConfig is used when the transport or command transport is "nats".
+// Messages go to subjects SubjectPrefix.<channel>, all kept in one stream.
+type NATSConfig struct {
+	// Comma-separated server URLs and an optional credentials file
+	URL       string `envconfig:"URL"        default:"nats://localhost:4222"`
+	CredsFile string `envconfig:"CREDS_FILE"`
+
+	// Stream created or updated on start, how long it keeps messages and
+	// on how many servers
+	Stream        string        `envconfig:"STREAM"         default:"PLASMA_WALLET_TRACKER"`
+	SubjectPrefix string        `envconfig:"SUBJECT_PREFIX" default:"plasma"`
+	MaxAge        time.Duration `envconfig:"MAX_AGE"        default:"24h"`
+	Replicas      int           `envconfig:"REPLICAS"       default:"1"`
+
+	// Durable consumer commands are read with, shared by all replicas. A
+	// command not acknowledged within AckWait is redelivered, at most
+	// MaxDeliver times.
+	Durable    string        `envconfig:"DURABLE"     default:"plasma-wallet-tracker"`
+	AckWait    time.Duration `envconfig:"ACK_WAIT"    default:"1m"`
+	MaxDeliver int           `envconfig:"MAX_DELIVER" default:"5"`
+}
+
+// ClickHouseConfig enables the analytics sink (empty URL = off): extracted
+// transfers are batched into Table over the ClickHouse HTTP interface, which
+// is created on start. Only transfers of transactions involving a tracked
+// address are written unless Firehose is set.
+type ClickHouseConfig struct {
+	URL      string `envconfig:"URL"`
+	Database string `envconfig:"DATABASE" default:"default"`
+	Table    string `envconfig:"TABLE"    default:"transfers"`
+	Username string `envconfig:"USERNAME" default:"default"`
+	Password string `envconfig:"PASSWORD"`
+
+	// Write every transfer of every block, fetching all their receipts
+	Firehose bool `envconfig:"FIREHOSE" default:"false"`
+
+	// Let the server buffer inserts from all replicas into larger parts.
+	// Inserts still wait until their data is written.
+	AsyncInsert bool          `envconfig:"ASYNC_INSERT" default:"true"`
+	Timeout     time.Duration `envconfig:"TIMEOUT"      default:"10s"`
+
+	// Transfers per insert at most, how long a partial batch waits, and
+	// how many wait to be batched. When the queue is full, "drop" loses
+	// transfers while "block" holds up block processing until there is room.
+	BatchSize     int           `envconfig:"BATCH_SIZE"     default:"10000"`
+	FlushInterval time.Duration `envconfig:"FLUSH_INTERVAL" default:"1s"`
+	QueueSize     int           `envconfig:"QUEUE_SIZE"     default:"100000"`
+	Backpressure  string        `envconfig:"BACKPRESSURE"   default:"drop"`
+
+	// Attempts per insert, including the first; the batch is lost after
+	MaxAttempts int `envconfig:"MAX_ATTEMPTS" default:"5"`
+}
+
+// ShardConfig splits the tracked wallets between the instances sharing a
+// Redis, each wallet being listened to by one of them. Members heartbeat
+// every HeartbeatInterval and are dropped after missing them for MemberTTL.
+type ShardConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+
+	// Name of the instance among the members, unique to each and stable
+	// across restarts. It namespaces the instance's block checkpoint,
+	// publish overflow and outbox keys, which every member would otherwise
+	// overwrite.
+	InstanceID        string        `envconfig:"INSTANCE_ID"`
+	HeartbeatInterval time.Duration `envconfig:"HEARTBEAT_INTERVAL" default:"5s"`
+	MemberTTL         time.Duration `envconfig:"MEMBER_TTL"         default:"15s"`
+
+	// Redis channel instances announce subscription changes on
+	ChangesChannel string `envconfig:"CHANGES_CHANNEL" default:"subscription_changes"`
+}
+
+// HAConfig runs instances sharing a Redis as active and standby: only the
+// elected leader follows the chain, takes commands and publishes. It
+// renews its lease every RenewInterval; a standby takes over, resuming
+// from the block checkpoint, once the lease is released or LeaseTTL passes
+// without a renewal.
+type HAConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+
+	// Name of the instance, unique to each (empty = hostname and process ID)
+	InstanceID    string        `envconfig:"INSTANCE_ID"`
+	RenewInterval time.Duration `envconfig:"RENEW_INTERVAL" default:"5s"`
+	LeaseTTL      time.Duration `envconfig:"LEASE_TTL"      default:"15s"`
+}
+
+// SinksConfig holds the settings of each publishing sink: the transports,
+// the live notification streams, webhooks and Telegram
+type SinksConfig struct {
+	PubSub   SinkConfig `envconfig:"PUBSUB"`
+	Streams  SinkConfig `envconfig:"STREAMS"`
+	NATS     SinkConfig `envconfig:"NATS"`
+	Live     SinkConfig `envconfig:"LIVE"`
+	Webhooks SinkConfig `envconfig:"WEBHOOKS"`
+	Telegram SinkConfig `envconfig:"TELEGRAM"`
+}
+
+// SinkConfig filters the messages one sink receives and sets how failed
+// publishes to it are retried
+type SinkConfig struct {
+	// Message kinds (notification, response, command_result,
+	// contract_event, token_transfer, alert, firehose) published to the
+	// sink (empty = all it handles) and the notification types among them
+	// (empty = all)
+	Kinds             []string `envconfig:"KINDS"`
+	NotificationTypes []string `envconfig:"NOTIFICATION_TYPES"`
+
+	// Attempts per message and the backoff bounds between them
+	MaxAttempts int           `envconfig:"MAX_ATTEMPTS" default:"3"`
+	MinBackoff  time.Duration `envconfig:"MIN_BACKOFF"  default:"100ms"`
+	MaxBackoff  time.Duration `envconfig:"MAX_BACKOFF"  default:"2s"`
+
+	// Transports only: a publish succeeds even if this transport failed.
+	// Live streams, webhooks and Telegram never fail a publish.
+	Optional bool `envconfig:"OPTIONAL" default:"false"`
+}
+
+// Load reads the configuration from env vars, over the YAML file at path
+// if one is given (see loadFile), and validates it. A setting in the file
+// replaces its default; a set env var replaces both.
+func Load(path string) (*Config, error) {
 	var cfg Config
-	err := envconfig.Process("", &cfg)
-	return &cfg, err
+	if err := envconfig.Process("", &cfg); err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		fromEnv := cfg
+		if err := loadFile(path, &cfg); err != nil {
+			return nil, err
+		}
+		overrideFromEnv(reflect.ValueOf(&cfg).Elem(), reflect.ValueOf(fromEnv), "")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return &cfg, nil
 }
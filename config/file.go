@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile sets the settings found in the YAML file at path. Sections and
+// keys are the env var names in lower case, without the section's prefix:
+//
+//	blockchain:
+//	  rpc_url: [https://rpc.plasma.network]
+//	  chain_id: 9745
+//	log:
+//	  level: debug
+//
+// Lists are YAML sequences and durations strings such as "30s". Unknown
+// keys are rejected so typos don't go unnoticed.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	if err := decodeSection(doc.Content[0], reflect.ValueOf(cfg).Elem(), "", ""); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// decodeSection sets the fields of section, whose env var prefix is tag,
+// from the mapping node. path is the section's position in the file.
+func decodeSection(node *yaml.Node, section reflect.Value, tag, path string) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: %s must be a mapping", node.Line, sectionName(path))
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := key.Value
+		if path != "" {
+			keyPath = path + "." + key.Value
+		}
+
+		field, fieldTag, ok := fieldByKey(section, tag, key.Value)
+		if !ok {
+			return fmt.Errorf("line %d: unknown key %s", key.Line, keyPath)
+		}
+		if field.Kind() == reflect.Struct {
+			if err := decodeSection(value, field, fieldTag, keyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := value.Decode(field.Addr().Interface()); err != nil {
+			var typeErr *yaml.TypeError
+			if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+				return fmt.Errorf("invalid %s: %s", keyPath, typeErr.Errors[0])
+			}
+			return fmt.Errorf("line %d: invalid %s: %w", value.Line, keyPath, err)
+		}
+	}
+	return nil
+}
+
+// fieldByKey returns the field of section named key in the file and its
+// env var tag
+func fieldByKey(section reflect.Value, tag, key string) (reflect.Value, string, bool) {
+	t := section.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldTag := t.Field(i).Tag.Get("envconfig")
+		if strings.ToLower(strings.TrimPrefix(fieldTag, tag+"_")) == key {
+			return section.Field(i), fieldTag, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+func sectionName(path string) string {
+	if path == "" {
+		return "the document"
+	}
+	return path
+}
+
+// overrideFromEnv copies into section the fields of fromEnv whose env var
+// is set, so the environment takes precedence over the file. Like
+// envconfig, a nested field's var is looked up with and without its
+// section's prefix.
+func overrideFromEnv(section, fromEnv reflect.Value, prefix string) {
+	t := section.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("envconfig")
+		key := tag
+		if prefix != "" {
+			key = prefix + "_" + tag
+		}
+
+		if section.Field(i).Kind() == reflect.Struct {
+			overrideFromEnv(section.Field(i), fromEnv.Field(i), key)
+			continue
+		}
+		_, set := os.LookupEnv(key)
+		if !set {
+			_, set = os.LookupEnv(tag)
+		}
+		if set {
+			section.Field(i).Set(fromEnv.Field(i))
+		}
+	}
+}
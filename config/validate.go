@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Validate rejects settings the service can't run with, reporting every
+// one found by its env var name
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+	oneOf := func(value, key string, allowed ...string) {
+		check(slices.Contains(allowed, value), key, "%q is not one of %s", value, quoteAll(allowed))
+	}
+
+	check(c.Redis.Port > 0 && c.Redis.Port <= 65535, "REDIS_PORT", "%d is not a valid port", c.Redis.Port)
+	check(c.Postgres.DSN == "" || c.Postgres.MaxConns > 0, "POSTGRES_MAX_CONNS", "must be positive")
+
+	b := c.Blockchain
+	check(len(b.RPCURLs) > 0, "BLOCKCHAIN_RPC_URL", "at least one RPC endpoint is required")
+	for _, u := range b.RPCURLs {
+		check(validURL(u, "http", "https"), "BLOCKCHAIN_RPC_URL", "%q is not an http(s) URL", u)
+	}
+	for _, u := range b.WSURLs {
+		check(validURL(u, "ws", "wss"), "BLOCKCHAIN_WS_URL", "%q is not a ws(s) URL", u)
+	}
+	check(b.ChainID > 0, "BLOCKCHAIN_CHAIN_ID", "must be positive")
+	check(b.BatchSize > 0, "BLOCKCHAIN_BATCH_SIZE", "must be positive")
+	check(b.PipelineQueueSize > 0, "BLOCKCHAIN_PIPELINE_QUEUE_SIZE", "must be positive")
+	check(b.FetchWorkers > 0, "BLOCKCHAIN_FETCH_WORKERS", "must be positive")
+	check(b.MatchWorkers > 0, "BLOCKCHAIN_MATCH_WORKERS", "must be positive")
+	check(b.DecodeWorkers > 0, "BLOCKCHAIN_DECODE_WORKERS", "must be positive")
+	check(b.EnrichWorkers > 0, "BLOCKCHAIN_ENRICH_WORKERS", "must be positive")
+	check(b.RPCMaxConcurrency > 0, "BLOCKCHAIN_RPC_MAX_CONCURRENCY", "must be positive")
+	check(b.RPCRateLimit >= 0, "BLOCKCHAIN_RPC_RATE_LIMIT", "must not be negative")
+	check(b.PollInterval > 0, "BLOCKCHAIN_POLL_INTERVAL", "must be positive")
+	check(b.RPCRetryMinBackoff <= b.RPCRetryMaxBackoff, "BLOCKCHAIN_RPC_RETRY_MIN_BACKOFF", "exceeds the max backoff")
+	check(b.ReconnectMinBackoff <= b.ReconnectMaxBackoff, "BLOCKCHAIN_RECONNECT_MIN_BACKOFF", "exceeds the max backoff")
+	check(b.ReorgDepth > 0, "BLOCKCHAIN_REORG_DEPTH", "must be positive")
+	oneOf(b.Finality, "BLOCKCHAIN_FINALITY", "latest", "safe", "finalized")
+	oneOf(b.TraceMode, "BLOCKCHAIN_TRACE_MODE", "", "debug", "parity")
+
+	for _, provider := range c.Pricing.Providers {
+		oneOf(provider, "PRICING_PROVIDERS", "coingecko", "twap")
+	}
+
+	s := c.Service
+	check(s.CommandChannel != "", "SERVICE_COMMAND_CHANNEL", "must not be empty")
+	check(s.NotificationChannel != "", "SERVICE_NOTIFICATION_CHANNEL", "must not be empty")
+	check(s.ResponseChannel != "", "SERVICE_RESPONSE_CHANNEL", "must not be empty")
+	check(s.ContractChannel != "", "SERVICE_CONTRACT_CHANNEL", "must not be empty")
+	check(s.TokenChannel != "", "SERVICE_TOKEN_CHANNEL", "must not be empty")
+	check(s.AlertChannel != "", "SERVICE_ALERT_CHANNEL", "must not be empty")
+	check(s.FirehoseChannel != "", "SERVICE_FIREHOSE_CHANNEL", "must not be empty")
+	for _, placeholder := range placeholders(s.NotificationRoute) {
+		oneOf(placeholder, "SERVICE_NOTIFICATION_ROUTE", "{user_id}", "{wallet}", "{shard}")
+	}
+	if strings.Contains(s.NotificationRoute, "{shard}") {
+		check(s.NotificationShards > 0, "SERVICE_NOTIFICATION_SHARDS", "must be positive")
+	}
+	check(s.WorkerCount > 0, "SERVICE_WORKER_COUNT", "must be positive")
+	check(len(s.Transport) > 0, "SERVICE_TRANSPORT", "at least one transport is required")
+	for _, transport := range s.Transport {
+		oneOf(transport, "SERVICE_TRANSPORT", "pubsub", "streams", "nats")
+	}
+	oneOf(s.CommandTransport, "SERVICE_COMMAND_TRANSPORT", "pubsub", "streams", "nats")
+	check(s.CommandHealthInterval > 0, "SERVICE_COMMAND_HEALTH_INTERVAL", "must be positive")
+	oneOf(s.Codec, "SERVICE_CODEC", "json", "msgpack", "protobuf")
+	oneOf(s.AuditLog, "SERVICE_AUDIT_LOG", "", "redis", "file")
+	oneOf(s.Outbox, "SERVICE_OUTBOX", "", "redis", "file")
+	check(s.OutboxMaxAttempts >= 0, "SERVICE_OUTBOX_MAX_ATTEMPTS", "must not be negative")
+	check(s.DeadLetterStream == "" || s.DeadLetterMaxLen > 0, "SERVICE_DEAD_LETTER_MAX_LEN", "must be positive")
+	oneOf(s.EvictionPolicy, "SERVICE_EVICTION_POLICY", "reject", "evict_lru")
+	check((s.TLSCertFile == "") == (s.TLSKeyFile == ""), "SERVICE_TLS_CERT_FILE", "must be set with SERVICE_TLS_KEY_FILE")
+	check(s.WebhookWorkers > 0, "SERVICE_WEBHOOK_WORKERS", "must be positive")
+	check(s.WebhookMaxAttempts > 0, "SERVICE_WEBHOOK_MAX_ATTEMPTS", "must be positive")
+	check(s.PublishQueueSize > 0, "SERVICE_PUBLISH_QUEUE_SIZE", "must be positive")
+
+	oneOf(c.Tracing.Exporter, "TRACING_EXPORTER", "", "otlp-grpc", "otlp-http")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+
+	if c.Telegram.BotToken != "" {
+		check(c.Telegram.Workers > 0, "TELEGRAM_WORKERS", "must be positive")
+	}
+
+	if ch := c.ClickHouse; ch.URL != "" {
+		check(validURL(ch.URL, "http", "https"), "CLICKHOUSE_URL", "%q is not an http(s) URL", ch.URL)
+		check(ch.BatchSize > 0, "CLICKHOUSE_BATCH_SIZE", "must be positive")
+		check(ch.FlushInterval > 0, "CLICKHOUSE_FLUSH_INTERVAL", "must be positive")
+		check(ch.MaxAttempts > 0, "CLICKHOUSE_MAX_ATTEMPTS", "must be positive")
+		oneOf(ch.Backpressure, "CLICKHOUSE_BACKPRESSURE", "drop", "block")
+	}
+
+	if sh := c.Shard; sh.Enabled {
+		check(sh.InstanceID != "", "SHARD_INSTANCE_ID", "must not be empty")
+		check(sh.HeartbeatInterval > 0, "SHARD_HEARTBEAT_INTERVAL", "must be positive")
+		check(sh.MemberTTL > sh.HeartbeatInterval, "SHARD_MEMBER_TTL", "must exceed SHARD_HEARTBEAT_INTERVAL")
+		check(!c.HA.Enabled, "SHARD_ENABLED", "can't be combined with HA_ENABLED")
+		check(sh.ChangesChannel != "", "SHARD_CHANGES_CHANNEL", "must not be empty")
+	}
+	if ha := c.HA; ha.Enabled {
+		check(ha.RenewInterval > 0, "HA_RENEW_INTERVAL", "must be positive")
+		check(ha.LeaseTTL >= 2*ha.RenewInterval, "HA_LEASE_TTL", "must be at least twice HA_RENEW_INTERVAL")
+	}
+
+	return errors.Join(errs...)
+}
+
+func validURL(raw string, schemes ...string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Host != "" && slices.Contains(schemes, u.Scheme)
+}
+
+// placeholders returns the {name} placeholders of template
+func placeholders(template string) []string {
+	var found []string
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			return found
+		}
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			return append(found, template[start:])
+		}
+		found = append(found, template[start:start+end+1])
+		template = template[start+end+1:]
+	}
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,62 @@
+package backoff
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff produces exponentially growing delays with full jitter
+type Backoff struct {
+	min     time.Duration
+	max     time.Duration
+	attempt int
+}
+
+func New(min, max time.Duration) *Backoff {
+	if min <= 0 {
+		min = time.Second
+	}
+	if max < min {
+		max = min
+	}
+
+	return &Backoff{min: min, max: max}
+}
+
+// Next returns the delay before the next attempt
+func (b *Backoff) Next() time.Duration {
+	ceiling := b.max
+	if b.attempt < 32 {
+		if d := b.min << b.attempt; d > 0 && d < b.max {
+			ceiling = d
+		}
+	}
+	b.attempt++
+
+	// Full jitter, but never shorter than min
+	return b.min + rand.N(ceiling-b.min+1)
+}
+
+// Attempt returns the number of delays handed out since the last reset
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+// Reset starts the sequence over after a success
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Wait sleeps for the next delay, returning early with false if ctx is done
+func (b *Backoff) Wait(ctx context.Context) bool {
+	timer := time.NewTimer(b.Next())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
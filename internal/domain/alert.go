@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AlertKind selects what an alert rule reacts to
+type AlertKind string
+
+const (
+	TransferAlert AlertKind = "transfer" // Large transfers; rules without a kind
+	VelocityAlert AlertKind = "velocity" // More than MaxTransactions within Window
+	DrainAlert    AlertKind = "drain"    // An outgoing transfer emptying DrainPercent of a balance
+	DormantAlert  AlertKind = "dormant"  // Activity after DormantFor without any
+)
+
+// AlertRule describes activity a user wants to hear about right away, such
+// as whale movements. Thresholds that are set must all be met.
+type AlertRule struct {
+	ID     string    `json:"id"`
+	UserID UserID    `json:"user_id"`
+	Kind   AlertKind `json:"kind,omitempty"`
+
+	// Wallet the rule watches; empty applies it to every wallet of the user
+	WalletAddress WalletAddress `json:"wallet_address,omitempty"`
+	// Token the rule watches; empty matches any token
+	TokenAddress string            `json:"token_address,omitempty"`
+	Direction    TransferDirection `json:"direction,omitempty"`
+
+	// MinAmount is in token units, e.g. "10000.5", and requires TokenAddress
+	MinAmount   string  `json:"min_amount,omitempty"`
+	MinValueUSD float64 `json:"min_value_usd,omitempty"`
+
+	// Velocity rules fire once a wallet has more than MaxTransactions
+	// transactions within Window, e.g. "10m"
+	MaxTransactions int    `json:"max_transactions,omitempty"`
+	Window          string `json:"window,omitempty"`
+
+	// Drain rules fire when an outgoing transfer takes at least DrainPercent
+	// of the wallet's balance of the token, of any token if TokenAddress is empty
+	DrainPercent float64 `json:"drain_percent,omitempty"`
+
+	// Dormant rules fire when a wallet is active after DormantFor of
+	// silence, e.g. "720h"
+	DormantFor string `json:"dormant_for,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlertNotification is published on the alerts channel when a transaction
+// matches a rule
+type AlertNotification struct {
+	Rule          AlertRule     `json:"rule"`
+	WalletAddress WalletAddress `json:"wallet_address"`
+	Transaction   Transaction   `json:"transaction"`
+	Transfers     []Transfer    `json:"transfers"`        // The transfers that matched
+	Reason        string        `json:"reason,omitempty"` // Why a behavioral rule fired
+	Timestamp     time.Time     `json:"timestamp"`
+	Trace         *TraceContext `json:"trace,omitempty"`
+}
+
+// AlertRuleRepository interface for alert rule persistence
+type AlertRuleRepository interface {
+	SaveAlertRule(ctx context.Context, rule AlertRule) error
+	DeleteAlertRule(ctx context.Context, id string) error
+	GetAllAlertRules(ctx context.Context) ([]AlertRule, error)
+}
@@ -0,0 +1,328 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Schema versions of WalletNotification: version 1 encodes token amounts
+// as JSON numbers, version 2 as decimal strings
+const (
+	LegacySchemaVersion  = 1
+	CurrentSchemaVersion = 2
+)
+
+// amountFields are the JSON keys jsonAmount encodes
+var amountFields = map[string]bool{
+	"value":          true,
+	"token_id":       true,
+	"amount":         true,
+	"amount_in":      true,
+	"amount_out":     true,
+	"gas_price":      true,
+	"gas_price_paid": true,
+	"fee":            true,
+	"balance":        true,
+	"value_in":       true,
+	"value_out":      true,
+	"net":            true,
+	"fees_paid":      true,
+	"total_supply":   true,
+}
+
+// LegacyAmounts rewrites the amounts of a JSON encoded message as numbers,
+// and its schema_version as LegacySchemaVersion, for consumers written
+// before amounts became strings. Numbers above 2^53 lose precision in
+// JavaScript and other float-based decoders.
+func LegacyAmounts(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var message any
+	if err := decoder.Decode(&message); err != nil {
+		return nil, err
+	}
+	if fields, ok := message.(map[string]any); ok {
+		if _, versioned := fields["schema_version"]; versioned {
+			fields["schema_version"] = LegacySchemaVersion
+		}
+	}
+	return json.Marshal(numberAmounts(message))
+}
+
+func numberAmounts(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if key == "args" {
+				continue // Decoded call and event arguments stay as they are
+			}
+			if text, ok := value.(string); ok && amountFields[key] {
+				if _, isAmount := new(big.Int).SetString(text, 10); isAmount {
+					v[key] = json.Number(text)
+				}
+				continue
+			}
+			v[key] = numberAmounts(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = numberAmounts(value)
+		}
+	}
+	return v
+}
+
+// jsonAmount encodes a *big.Int as a decimal string. Numbers decode as
+// well, so data stored before amounts became strings can be read back.
+type jsonAmount big.Int
+
+func amount(n *big.Int) *jsonAmount {
+	return (*jsonAmount)(n)
+}
+
+func (a *jsonAmount) bigInt() *big.Int {
+	return (*big.Int)(a)
+}
+
+func (a *jsonAmount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.bigInt().String())), nil
+}
+
+func (a *jsonAmount) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	if _, ok := a.bigInt().SetString(text, 10); !ok {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+	return nil
+}
+
+func (t Transfer) MarshalJSON() ([]byte, error) {
+	type plain Transfer
+	return json.Marshal(struct {
+		plain
+		Value   *jsonAmount `json:"value"`
+		TokenID *jsonAmount `json:"token_id,omitempty"`
+	}{plain(t), amount(t.Value), amount(t.TokenID)})
+}
+
+func (t *Transfer) UnmarshalJSON(data []byte) error {
+	type plain Transfer
+	aux := struct {
+		*plain
+		Value   *jsonAmount `json:"value"`
+		TokenID *jsonAmount `json:"token_id,omitempty"`
+	}{plain: (*plain)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	t.Value, t.TokenID = aux.Value.bigInt(), aux.TokenID.bigInt()
+	return nil
+}
+
+func (a Approval) MarshalJSON() ([]byte, error) {
+	type plain Approval
+	return json.Marshal(struct {
+		plain
+		Amount *jsonAmount `json:"amount"`
+	}{plain(a), amount(a.Amount)})
+}
+
+func (a *Approval) UnmarshalJSON(data []byte) error {
+	type plain Approval
+	aux := struct {
+		*plain
+		Amount *jsonAmount `json:"amount"`
+	}{plain: (*plain)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	a.Amount = aux.Amount.bigInt()
+	return nil
+}
+
+func (s Swap) MarshalJSON() ([]byte, error) {
+	type plain Swap
+	return json.Marshal(struct {
+		plain
+		AmountIn  *jsonAmount `json:"amount_in"`
+		AmountOut *jsonAmount `json:"amount_out"`
+	}{plain(s), amount(s.AmountIn), amount(s.AmountOut)})
+}
+
+func (s *Swap) UnmarshalJSON(data []byte) error {
+	type plain Swap
+	aux := struct {
+		*plain
+		AmountIn  *jsonAmount `json:"amount_in"`
+		AmountOut *jsonAmount `json:"amount_out"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.AmountIn, s.AmountOut = aux.AmountIn.bigInt(), aux.AmountOut.bigInt()
+	return nil
+}
+
+func (b BridgeTransfer) MarshalJSON() ([]byte, error) {
+	type plain BridgeTransfer
+	return json.Marshal(struct {
+		plain
+		Amount *jsonAmount `json:"amount"`
+	}{plain(b), amount(b.Amount)})
+}
+
+func (b *BridgeTransfer) UnmarshalJSON(data []byte) error {
+	type plain BridgeTransfer
+	aux := struct {
+		*plain
+		Amount *jsonAmount `json:"amount"`
+	}{plain: (*plain)(b)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	b.Amount = aux.Amount.bigInt()
+	return nil
+}
+
+func (tx Transaction) MarshalJSON() ([]byte, error) {
+	type plain Transaction
+	return json.Marshal(struct {
+		plain
+		GasPrice     *jsonAmount `json:"gas_price"`
+		GasPricePaid *jsonAmount `json:"gas_price_paid"`
+		Fee          *jsonAmount `json:"fee"`
+	}{plain(tx), amount(tx.GasPrice), amount(tx.GasPricePaid), amount(tx.Fee)})
+}
+
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	type plain Transaction
+	aux := struct {
+		*plain
+		GasPrice     *jsonAmount `json:"gas_price"`
+		GasPricePaid *jsonAmount `json:"gas_price_paid"`
+		Fee          *jsonAmount `json:"fee"`
+	}{plain: (*plain)(tx)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	tx.GasPrice, tx.GasPricePaid = aux.GasPrice.bigInt(), aux.GasPricePaid.bigInt()
+	tx.Fee = aux.Fee.bigInt()
+	return nil
+}
+
+func (b TokenBalance) MarshalJSON() ([]byte, error) {
+	type plain TokenBalance
+	return json.Marshal(struct {
+		plain
+		Balance *jsonAmount `json:"balance"`
+	}{plain(b), amount(b.Balance)})
+}
+
+func (b *TokenBalance) UnmarshalJSON(data []byte) error {
+	type plain TokenBalance
+	aux := struct {
+		*plain
+		Balance *jsonAmount `json:"balance"`
+	}{plain: (*plain)(b)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	b.Balance = aux.Balance.bigInt()
+	return nil
+}
+
+func (t DigestTokenTotal) MarshalJSON() ([]byte, error) {
+	type plain DigestTokenTotal
+	return json.Marshal(struct {
+		plain
+		ValueIn  *jsonAmount `json:"value_in"`
+		ValueOut *jsonAmount `json:"value_out"`
+	}{plain(t), amount(t.ValueIn), amount(t.ValueOut)})
+}
+
+func (t *DigestTokenTotal) UnmarshalJSON(data []byte) error {
+	type plain DigestTokenTotal
+	aux := struct {
+		*plain
+		ValueIn  *jsonAmount `json:"value_in"`
+		ValueOut *jsonAmount `json:"value_out"`
+	}{plain: (*plain)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	t.ValueIn, t.ValueOut = aux.ValueIn.bigInt(), aux.ValueOut.bigInt()
+	return nil
+}
+
+func (f TokenFlow) MarshalJSON() ([]byte, error) {
+	type plain TokenFlow
+	return json.Marshal(struct {
+		plain
+		Net *jsonAmount `json:"net"`
+	}{plain(f), amount(f.Net)})
+}
+
+func (f *TokenFlow) UnmarshalJSON(data []byte) error {
+	type plain TokenFlow
+	aux := struct {
+		*plain
+		Net *jsonAmount `json:"net"`
+	}{plain: (*plain)(f)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	f.Net = aux.Net.bigInt()
+	return nil
+}
+
+func (u GasUsage) MarshalJSON() ([]byte, error) {
+	type plain GasUsage
+	return json.Marshal(struct {
+		plain
+		FeesPaid *jsonAmount `json:"fees_paid"`
+	}{plain(u), amount(u.FeesPaid)})
+}
+
+func (u *GasUsage) UnmarshalJSON(data []byte) error {
+	type plain GasUsage
+	aux := struct {
+		*plain
+		FeesPaid *jsonAmount `json:"fees_paid"`
+	}{plain: (*plain)(u)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	u.FeesPaid = aux.FeesPaid.bigInt()
+	return nil
+}
+
+func (m TokenMetadata) MarshalJSON() ([]byte, error) {
+	type plain TokenMetadata
+	return json.Marshal(struct {
+		plain
+		TotalSupply *jsonAmount `json:"total_supply,omitempty"`
+	}{plain(m), amount(m.TotalSupply)})
+}
+
+func (m *TokenMetadata) UnmarshalJSON(data []byte) error {
+	type plain TokenMetadata
+	aux := struct {
+		*plain
+		TotalSupply *jsonAmount `json:"total_supply,omitempty"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.TotalSupply = aux.TotalSupply.bigInt()
+	return nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TransferRecord is a transfer extracted from a processed block, as kept
+// for analytics
+type TransferRecord struct {
+	BlockNumber uint64
+	BlockHash   string
+	BlockTime   time.Time
+	Position    int // Index among the transfers of its transaction
+	Transfer    Transfer
+}
+
+// FirehoseBlock carries every transfer of a processed block. It is
+// published on the firehose channel whoever tracks the addresses involved.
+type FirehoseBlock struct {
+	BlockNumber uint64        `json:"block_number"`
+	BlockHash   string        `json:"block_hash"`
+	BlockTime   time.Time     `json:"block_time"`
+	Transfers   []Transfer    `json:"transfers"`
+	Timestamp   time.Time     `json:"timestamp"`
+	Trace       *TraceContext `json:"trace,omitempty"`
+}
+
+// TransferSink receives the transfers extracted from processed blocks.
+// Receive is called from block processing and must not hold it up for long.
+type TransferSink interface {
+	Receive(ctx context.Context, records []TransferRecord)
+}
+
+// TransferWriter stores batches of transfer records in an analytics store
+type TransferWriter interface {
+	WriteTransfers(ctx context.Context, records []TransferRecord) error
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCommand             AuditAction = "command"
+	AuditSubscriptionAdded   AuditAction = "subscription_added"
+	AuditSubscriptionRemoved AuditAction = "subscription_removed"
+)
+
+type SubscriptionKind string
+
+const (
+	WalletSubscriptionKind   SubscriptionKind = "wallet"
+	ContractSubscriptionKind SubscriptionKind = "contract"
+	TokenSubscriptionKind    SubscriptionKind = "token"
+)
+
+// AuditEntry is one record of the append-only audit log: a received
+// command and its outcome, or a subscription that was added or removed
+type AuditEntry struct {
+	ID     string      `json:"id,omitempty"` // Assigned by the log, ordered by time
+	Action AuditAction `json:"action"`
+	UserID UserID      `json:"user_id"`
+
+	// Address is the wallet, contract or token the entry is about, if any
+	Address WalletAddress `json:"address,omitempty"`
+
+	// Command entries
+	Command       *Command `json:"command,omitempty"`
+	Result        string   `json:"result,omitempty"` // "ok" or an error code
+	Error         string   `json:"error,omitempty"`
+	CorrelationID string   `json:"correlation_id,omitempty"`
+
+	// Subscription entries, with why a subscription was removed if not by
+	// its user, e.g. "evicted"
+	Subscription SubscriptionKind `json:"subscription,omitempty"`
+	Reason       string           `json:"reason,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AuditQuery selects audit entries; zero fields match everything
+type AuditQuery struct {
+	UserID  UserID
+	Address WalletAddress
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+// AuditLog is an append-only store of audit entries
+type AuditLog interface {
+	Append(ctx context.Context, entry AuditEntry) error
+	// Query returns matching entries, newest first, at most query.Limit
+	Query(ctx context.Context, query AuditQuery) ([]AuditEntry, error)
+}
+
+// Matches reports whether entry is selected by the filters of q, ignoring
+// the limit
+func (q AuditQuery) Matches(entry AuditEntry) bool {
+	if q.UserID != 0 && entry.UserID != q.UserID {
+		return false
+	}
+	if q.Address != "" && !strings.EqualFold(string(entry.Address), string(q.Address)) {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.Timestamp.After(q.Until) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Messenger delivers notifications straight to users' chats, formatted for
+// reading
+type Messenger interface {
+	SendNotification(ctx context.Context, userID UserID, notification WalletNotification) error
+	SendAlert(ctx context.Context, userID UserID, alert AlertNotification) error
+}
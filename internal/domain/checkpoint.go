@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// CheckpointStore interface for persisting block processing progress
+type CheckpointStore interface {
+	// LoadCheckpoint returns the last fully processed block number
+	LoadCheckpoint(ctx context.Context) (blockNumber uint64, found bool, err error)
+
+	// SaveCheckpoint records blockNumber as fully processed
+	SaveCheckpoint(ctx context.Context, blockNumber uint64) error
+}
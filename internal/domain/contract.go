@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ContractSubscription is a user's request to follow events of a contract.
+// Either ABI or EventSignature selects the events.
+type ContractSubscription struct {
+	ContractAddress WalletAddress `json:"contract_address"`
+	UserID          UserID        `json:"user_id"`
+	ABI             string        `json:"abi,omitempty"`             // JSON ABI fragment; its events are decoded
+	EventSignature  string        `json:"event_signature,omitempty"` // e.g. "Staked(address,uint256)"; not decoded
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// ContractEvent is a log emitted by a watched contract
+type ContractEvent struct {
+	ContractAddress WalletAddress   `json:"contract_address"`
+	Event           string          `json:"event"`     // Event name
+	Signature       string          `json:"signature"` // Topic 0
+	TxHash          TransactionHash `json:"tx_hash"`
+	BlockNumber     uint64          `json:"block_number"`
+	LogIndex        uint            `json:"log_index"`
+	Timestamp       time.Time       `json:"timestamp"`
+	Args            map[string]any  `json:"args,omitempty"` // Only when subscribed with an ABI
+	Topics          []string        `json:"topics"`
+	Data            string          `json:"data"`
+}
+
+// ContractEventNotification delivers a contract event to its subscribers
+type ContractEventNotification struct {
+	Event       ContractEvent `json:"event"`
+	Subscribers []UserID      `json:"subscribers"`
+	Timestamp   time.Time     `json:"timestamp"`
+	Trace       *TraceContext `json:"trace,omitempty"`
+}
+
+// ContractEventSource streams events of watched contracts
+type ContractEventSource interface {
+	// SubscribeToContract returns a channel of events matching the
+	// subscription's ABI or event signature, closed once ctx is cancelled
+	SubscribeToContract(ctx context.Context, subscription ContractSubscription) (<-chan ContractEvent, error)
+}
+
+// ContractRepository interface for contract subscription persistence
+type ContractRepository interface {
+	AddContractSubscription(ctx context.Context, subscription ContractSubscription) error
+	RemoveContractSubscription(ctx context.Context, contractAddress WalletAddress, userID UserID) error
+	GetContractSubscriptions(ctx context.Context) ([]ContractSubscription, error)
+}
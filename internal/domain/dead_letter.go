@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+	"unicode/utf8"
+)
+
+// DeadLetter is a message that couldn't be published or handled, kept for
+// an admin to inspect, requeue or purge
+type DeadLetter struct {
+	ID        string         `json:"id"` // Assigned by the queue
+	Kind      DeadLetterKind `json:"kind"`
+	Payload   string         `json:"payload"`            // The notification as JSON, or the command as received
+	Encoding  string         `json:"encoding,omitempty"` // "base64" for binary payloads
+	Reason    string         `json:"reason"`             // Last error
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// NewDeadCommand returns the dead letter of a command received as payload,
+// base64 encoded unless it is text
+func NewDeadCommand(payload []byte, cause error) DeadLetter {
+	return newDeadLetter(DeadCommand, payload, cause)
+}
+
+// NewDeadNotification returns the dead letter of a notification stored as
+// payload, base64 encoded unless it is text
+func NewDeadNotification(payload []byte, cause error) DeadLetter {
+	return newDeadLetter(DeadNotification, payload, cause)
+}
+
+func newDeadLetter(kind DeadLetterKind, payload []byte, cause error) DeadLetter {
+	letter := DeadLetter{
+		Kind:      kind,
+		Payload:   string(payload),
+		Reason:    cause.Error(),
+		CreatedAt: time.Now(),
+	}
+	if !utf8.Valid(payload) {
+		letter.Payload = base64.StdEncoding.EncodeToString(payload)
+		letter.Encoding = "base64"
+	}
+	return letter
+}
+
+// Data returns the payload as received
+func (l DeadLetter) Data() ([]byte, error) {
+	if l.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(l.Payload)
+	}
+	return []byte(l.Payload), nil
+}
+
+type DeadLetterKind string
+
+const (
+	// A notification that failed to publish after all its attempts
+	DeadNotification DeadLetterKind = "notification"
+	// A command that can't be decoded or kept failing
+	DeadCommand DeadLetterKind = "command"
+)
+
+// DeadLetterQueue keeps dead letters, oldest first
+type DeadLetterQueue interface {
+	Add(ctx context.Context, letter DeadLetter) error
+
+	// List returns up to limit letters, oldest first
+	List(ctx context.Context, limit int) ([]DeadLetter, error)
+
+	// Get returns the letters among ids still queued
+	Get(ctx context.Context, ids []string) ([]DeadLetter, error)
+
+	// Delete removes the letters among ids, or all if ids is empty, and
+	// returns how many were removed
+	Delete(ctx context.Context, ids []string) (int64, error)
+
+	Len(ctx context.Context) (int64, error)
+}
+
+// DeadLetterPage answers list_dead_letters
+type DeadLetterPage struct {
+	Total   int64        `json:"total"`
+	Letters []DeadLetter `json:"letters"`
+}
+
+// DeadLetterRemoval answers requeue_dead_letters and purge_dead_letters
+type DeadLetterRemoval struct {
+	Removed []string `json:"removed"`          // Requeued or purged
+	Failed  []string `json:"failed,omitempty"` // Failed again, still queued
+	Purged  int64    `json:"purged,omitempty"` // Letters purged when no IDs were given
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DedupStore records which notifications were already delivered
+type DedupStore interface {
+	// Claim marks each key as delivered for ttl and reports, per key,
+	// whether it was not marked before
+	Claim(ctx context.Context, keys []string, ttl time.Duration) ([]bool, error)
+	// Release forgets keys, allowing them to be claimed again
+	Release(ctx context.Context, keys []string) error
+}
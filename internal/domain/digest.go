@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+)
+
+// Digest aggregates the confirmed transactions of a wallet over a window,
+// sent instead of individual notifications to subscriptions in digest mode
+type Digest struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Transactions int                `json:"transactions"`
+	Failed       int                `json:"failed"` // Reverted transactions
+	Tokens       []DigestTokenTotal `json:"tokens"`
+}
+
+// DigestTokenTotal sums the transfers of one token in a digest. Native XPL
+// has an empty TokenAddress.
+type DigestTokenTotal struct {
+	TokenAddress  string        `json:"token_address"`
+	TokenSymbol   string        `json:"token_symbol"`
+	TokenStandard TokenStandard `json:"token_standard"`
+	Incoming      int           `json:"incoming"`
+	Outgoing      int           `json:"outgoing"`
+	ValueIn       *big.Int      `json:"value_in"`
+	ValueOut      *big.Int      `json:"value_out"`
+	ValueInUSD    string        `json:"value_in_usd,omitempty"`  // Sum of priced incoming transfers
+	ValueOutUSD   string        `json:"value_out_usd,omitempty"` // Sum of priced outgoing transfers
+}
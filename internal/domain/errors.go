@@ -8,4 +8,30 @@ var (
 	ErrInvalidAddress      = errors.New("invalid wallet address")
 	ErrConnectionFailed    = errors.New("connection failed")
 	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrQuotaExceeded       = errors.New("quota exceeded")
+	ErrInvalidContractABI  = errors.New("invalid contract ABI")
+	ErrInvalidTokenFilter  = errors.New("invalid token filter")
+	ErrInvalidBlockRange   = errors.New("invalid block range")
+	ErrInvalidAlertRule    = errors.New("invalid alert rule")
+	ErrAlertRuleNotFound   = errors.New("alert rule not found")
+	ErrInvalidFilter       = errors.New("invalid notification filter")
+	ErrUnknownCommand      = errors.New("unknown command")
+	ErrInvalidDuration     = errors.New("invalid duration")
+	ErrLimitExceeded       = errors.New("wallet limit exceeded")
+	ErrInvalidLimit        = errors.New("invalid wallet limit")
+	ErrForbidden           = errors.New("forbidden")
+	ErrInvalidLabel        = errors.New("invalid wallet label")
+	ErrInvalidGroup        = errors.New("invalid wallet group")
+	ErrGroupNotFound       = errors.New("wallet group not found")
+	ErrAuditLogDisabled    = errors.New("audit log disabled")
+	ErrInvalidLogLevel     = errors.New("invalid log level")
+	ErrSlowConsumer        = errors.New("stream consumer too slow")
+	ErrInvalidEventID      = errors.New("invalid event id")
+	ErrReplayUnavailable   = errors.New("notification replay unavailable")
+	ErrInvalidWebhook      = errors.New("invalid webhook")
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrStatsUnavailable    = errors.New("wallet stats unavailable")
+	ErrPortfolioUnvalued   = errors.New("portfolio valuation unavailable")
+	ErrInvalidConfig       = errors.New("invalid configuration")
+	ErrDeadLettersDisabled = errors.New("dead letter queue disabled")
 )
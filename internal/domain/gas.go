@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// GasUsage represents cumulative fees paid by a watched wallet, since it
+// was first seen paying one or since Since
+type GasUsage struct {
+	WalletAddress WalletAddress `json:"wallet_address"`
+	Since         time.Time     `json:"since,omitzero"`
+	TxCount       uint64        `json:"tx_count"`
+	GasUsed       uint64        `json:"gas_used"`
+	FeesPaid      *big.Int      `json:"fees_paid"` // In wei
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// Daily gas usage is kept this long, the widest window it can be read over
+const GasUsageRetention = 90 * 24 * time.Hour
+
+// GasUsageRepository interface for gas usage persistence
+type GasUsageRepository interface {
+	// AddGasUsage adds a fee paid at the given time to the wallet's totals
+	// and returns them
+	AddGasUsage(
+		ctx context.Context,
+		walletAddress WalletAddress,
+		gasUsed uint64,
+		fee *big.Int,
+		at time.Time,
+	) (*GasUsage, error)
+	GetGasUsage(ctx context.Context, walletAddress WalletAddress) (*GasUsage, error)
+
+	// GetGasUsageSince sums the fees paid from the start of since's UTC day
+	GetGasUsageSince(ctx context.Context, walletAddress WalletAddress, since time.Time) (*GasUsage, error)
+}
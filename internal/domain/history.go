@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TransactionHistory is a page of a wallet's past transactions, oldest first
+type TransactionHistory struct {
+	WalletAddress WalletAddress `json:"wallet_address"`
+	FromBlock     uint64        `json:"from_block"`
+	ToBlock       uint64        `json:"to_block"` // Last block of the scanned range
+	Transactions  []Transaction `json:"transactions"`
+
+	// Set when the range may hold more transactions; pass NextFromBlock as
+	// from_block to fetch the next page
+	HasMore       bool   `json:"has_more"`
+	NextFromBlock uint64 `json:"next_from_block,omitempty"`
+}
+
+// WalletStats aggregates the stored transactions of a wallet between Since
+// and Until; zero bounds are open. Tokens are ordered by transfer count.
+type WalletStats struct {
+	WalletAddress  WalletAddress      `json:"wallet_address"`
+	Since          time.Time          `json:"since,omitzero"`
+	Until          time.Time          `json:"until,omitzero"`
+	Transactions   int                `json:"transactions"`
+	Failed         int                `json:"failed"` // Reverted transactions
+	Tokens         []DigestTokenTotal `json:"tokens"`
+	Counterparties int                `json:"counterparties"` // Distinct addresses the wallet dealt with
+	FirstActivity  time.Time          `json:"first_activity,omitzero"`
+	LastActivity   time.Time          `json:"last_activity,omitzero"`
+}
+
+// TransactionRepository stores the transactions published for tracked
+// wallets, and from which block on each wallet's are stored completely
+type TransactionRepository interface {
+	// SaveTransaction stores tx with its transfers involving walletAddress,
+	// replacing a copy saved before
+	SaveTransaction(ctx context.Context, walletAddress WalletAddress, tx Transaction) error
+	// DeleteTransactions removes transactions, e.g. ones undone by a reorg
+	DeleteTransactions(ctx context.Context, walletAddress WalletAddress, hashes []TransactionHash) error
+	// ListTransactions returns stored transactions of walletAddress in
+	// [fromBlock, toBlock], oldest first. A positive limit cuts the result
+	// after the block of the limit-th transaction.
+	ListTransactions(
+		ctx context.Context,
+		walletAddress WalletAddress,
+		fromBlock uint64,
+		toBlock uint64,
+		limit int,
+	) ([]Transaction, error)
+	// WalletStats aggregates the stored transactions of walletAddress with
+	// block times in [since, until]; zero bounds are open
+	WalletStats(ctx context.Context, walletAddress WalletAddress, since, until time.Time) (*WalletStats, error)
+	// LargestTransfers returns up to limit stored transfers of walletAddress
+	// with block times in [since, until], largest in USD first and the
+	// unpriced ones after them by raw amount
+	LargestTransfers(
+		ctx context.Context,
+		walletAddress WalletAddress,
+		since time.Time,
+		until time.Time,
+		limit int,
+	) ([]Transfer, error)
+
+	// StartCoverage records that all transactions of walletAddress are
+	// stored from fromBlock on, keeping an earlier start already recorded.
+	// StopCoverage forgets it once the wallet isn't tracked anymore.
+	StartCoverage(ctx context.Context, walletAddress WalletAddress, fromBlock uint64) error
+	StopCoverage(ctx context.Context, walletAddress WalletAddress) error
+	// Coverage returns the block from which transactions of walletAddress
+	// are stored; ok is false if they aren't being stored
+	Coverage(ctx context.Context, walletAddress WalletAddress) (fromBlock uint64, ok bool, err error)
+}
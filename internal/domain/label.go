@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+// AddressLabel represents a human readable name attached to an address
+type AddressLabel struct {
+	Address WalletAddress `json:"address"`
+	Label   string        `json:"label"`
+}
+
+// LabelRepository interface for address label persistence
+type LabelRepository interface {
+	SetLabels(ctx context.Context, labels []AddressLabel) error
+	GetAllLabels(ctx context.Context) ([]AddressLabel, error)
+	DeleteLabels(ctx context.Context, addresses []WalletAddress) error
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LeaderLock is a lease held by at most one instance at a time
+type LeaderLock interface {
+	// Acquire takes the lease for instanceID for ttl if nobody holds it,
+	// and reports whether it did
+	Acquire(ctx context.Context, instanceID string, ttl time.Duration) (bool, error)
+
+	// Renew extends the lease for ttl if instanceID still holds it, and
+	// reports whether it does
+	Renew(ctx context.Context, instanceID string, ttl time.Duration) (bool, error)
+
+	// Release gives the lease up if instanceID holds it
+	Release(ctx context.Context, instanceID string) error
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+// NotificationEvent is a notification as delivered to one user, with the ID
+// it was buffered under for replay (empty if it wasn't buffered)
+type NotificationEvent struct {
+	ID           string
+	UserID       UserID
+	Notification WalletNotification
+}
+
+// NotificationBuffer keeps the recent notifications of each user so
+// streaming clients can resume where they left off
+type NotificationBuffer interface {
+	// Append buffers events and returns the ID each got
+	Append(ctx context.Context, events []NotificationEvent) ([]string, error)
+	// After returns up to limit events of userID buffered after the one with
+	// id, oldest first. Events trimmed from the buffer are gone.
+	After(ctx context.Context, userID UserID, id string, limit int) ([]NotificationEvent, error)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OutboxEntry is a notification waiting in the outbox to be published
+type OutboxEntry struct {
+	ID           string             `json:"id"`
+	Notification WalletNotification `json:"notification"`
+	AddedAt      time.Time          `json:"added_at"`
+}
+
+// InvalidOutboxEntryError is returned by Outbox.Oldest for an entry that
+// can't be decoded, and so never published, for the caller to remove
+type InvalidOutboxEntryError struct {
+	ID   string
+	Data []byte
+	Err  error
+}
+
+func (e *InvalidOutboxEntryError) Error() string {
+	return fmt.Sprintf("invalid outbox entry %s: %v", e.ID, e.Err)
+}
+
+func (e *InvalidOutboxEntryError) Unwrap() error {
+	return e.Err
+}
+
+// Outbox durably holds notifications until they are published, oldest
+// first
+type Outbox interface {
+	// Add appends the entries, all of them or none
+	Add(ctx context.Context, entries ...OutboxEntry) error
+
+	// Oldest returns the oldest entry, nil if the outbox is empty. An entry
+	// that can't be decoded is reported as *InvalidOutboxEntryError.
+	Oldest(ctx context.Context) (*OutboxEntry, error)
+
+	// Remove drops a published entry; one already removed is no error
+	Remove(ctx context.Context, id string) error
+
+	Len(ctx context.Context) (int, error)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// PriceProvider resolves USD prices of tokens. Native XPL is requested by
+// the zero address.
+type PriceProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+
+	// GetPricesUSD returns the USD price of each token it knows, keyed by
+	// lowercase token address. Unknown tokens are left out.
+	GetPricesUSD(ctx context.Context, tokenAddresses []string) (map[string]float64, error)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "context"
+
+// QueuedTransaction is a transaction of a watched wallet waiting for a
+// publishing worker
+type QueuedTransaction struct {
+	WalletAddress WalletAddress `json:"wallet_address"`
+	Transaction   Transaction   `json:"transaction"`
+
+	// Confirmations is set once a held transaction is deep enough to
+	// publish; zero means it was just detected
+	Confirmations uint64 `json:"confirmations,omitempty"`
+}
+
+// OverflowQueue persists queued transactions the in-memory queue has no
+// room for, so they survive a backlog and restarts. It keeps a FIFO per
+// publishing worker, so each wallet's transactions stay in order.
+type OverflowQueue interface {
+	// Push appends item to the queue of worker
+	Push(ctx context.Context, worker int, item QueuedTransaction) error
+	// PushFront puts items back at the head of the queue of worker, in order
+	PushFront(ctx context.Context, worker int, items []QueuedTransaction) error
+	// Pop returns nil if the queue of worker is empty
+	Pop(ctx context.Context, worker int) (*QueuedTransaction, error)
+}
+
+// PendingStore persists transactions held until they are deep enough to
+// publish, so they survive restarts
+type PendingStore interface {
+	AddPending(ctx context.Context, item QueuedTransaction) error
+	RemovePending(ctx context.Context, items []QueuedTransaction) error
+	// LoadPending returns every held transaction, oldest block first
+	LoadPending(ctx context.Context) ([]QueuedTransaction, error)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ShardMembership keeps the set of tracker instances sharing the wallets;
+// an instance stays a member while it keeps sending heartbeats
+type ShardMembership interface {
+	// Heartbeat keeps instanceID a member for ttl and returns the live
+	// members, sorted
+	Heartbeat(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error)
+
+	// Leave removes instanceID right away, so the others take over its
+	// wallets without waiting for it to expire
+	Leave(ctx context.Context, instanceID string) error
+}
+
+// SubscriptionChange tells the instances sharing wallets that one of them
+// changed the persisted subscriptions of a wallet
+type SubscriptionChange struct {
+	InstanceID    string        `json:"instance_id"`
+	WalletAddress WalletAddress `json:"wallet_address"`
+}
+
+// SubscriptionChangeFeed broadcasts subscription changes to every instance
+type SubscriptionChangeFeed interface {
+	PublishSubscriptionChange(ctx context.Context, change SubscriptionChange) error
+
+	// SubscribeSubscriptionChanges calls handler with each change, in the
+	// order they were published, until ctx is done. Changes published
+	// while disconnected are lost.
+	SubscribeSubscriptionChanges(ctx context.Context, handler func(SubscriptionChange)) error
+}
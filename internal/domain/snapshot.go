@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BalanceSnapshot holds the balances of a wallet at a point in time, valued
+// at the prices of then
+type BalanceSnapshot struct {
+	WalletAddress WalletAddress  `json:"wallet_address"`
+	Balances      []TokenBalance `json:"balances"`
+	ValueUSD      string         `json:"value_usd"` // Sum of the priced balances
+	TakenAt       time.Time      `json:"taken_at"`
+}
+
+// PortfolioValue is what a user's tracked wallets are worth according to
+// their latest snapshots, and how that changed since ComparedTo. The change
+// only covers wallets that had a snapshot back then.
+type PortfolioValue struct {
+	UserID           UserID            `json:"user_id"`
+	ValueUSD         string            `json:"value_usd"`
+	ComparedTo       time.Time         `json:"compared_to"`
+	PreviousValueUSD string            `json:"previous_value_usd,omitempty"`
+	ChangeUSD        string            `json:"change_usd,omitempty"`
+	ChangePercent    string            `json:"change_percent,omitempty"` // e.g. "-3.12"
+	Wallets          []BalanceSnapshot `json:"wallets"`
+	GeneratedAt      time.Time         `json:"generated_at"`
+}
+
+// BalanceSnapshotRepository stores balance snapshots of tracked wallets
+type BalanceSnapshotRepository interface {
+	SaveSnapshots(ctx context.Context, snapshots []BalanceSnapshot) error
+	// SnapshotsAt returns the latest snapshot of each wallet taken at or
+	// before at, or the latest one if at is zero. Wallets without one are
+	// left out.
+	SnapshotsAt(
+		ctx context.Context,
+		wallets []WalletAddress,
+		at time.Time,
+	) (map[WalletAddress]BalanceSnapshot, error)
+	// DeleteSnapshots removes snapshots taken before the given time
+	DeleteSnapshots(ctx context.Context, before time.Time) error
+}
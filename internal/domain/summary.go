@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+)
+
+// SummaryPeriod is the span an activity summary covers
+type SummaryPeriod string
+
+const (
+	DailySummary  SummaryPeriod = "daily"
+	WeeklySummary SummaryPeriod = "weekly"
+)
+
+// Summary reports what a user's wallets did over a period, sent on a
+// schedule as a summary notification
+type Summary struct {
+	Period  SummaryPeriod   `json:"period"`
+	From    time.Time       `json:"from"`
+	To      time.Time       `json:"to"`
+	Wallets []WalletSummary `json:"wallets"` // Wallets with activity, busiest first
+}
+
+// WalletSummary is the activity of one wallet in a Summary
+type WalletSummary struct {
+	WalletAddress WalletAddress `json:"wallet_address"`
+	Label         string        `json:"label,omitempty"` // The user's name for the wallet
+	Transactions  int           `json:"transactions"`
+	Failed        int           `json:"failed"` // Reverted transactions
+	Flows         []TokenFlow   `json:"flows"`
+
+	// LargestTransfers are ranked by USD value; unpriced ones follow the
+	// priced ones, by raw amount
+	LargestTransfers []Transfer `json:"largest_transfers"`
+}
+
+// TokenFlow is the net amount of a token that moved into a wallet, negative
+// if more left it. Native XPL has an empty TokenAddress.
+type TokenFlow struct {
+	TokenAddress  string        `json:"token_address"`
+	TokenSymbol   string        `json:"token_symbol"`
+	TokenStandard TokenStandard `json:"token_standard"`
+	Net           *big.Int      `json:"net"`
+	NetUSD        string        `json:"net_usd,omitempty"` // Of priced transfers
+}
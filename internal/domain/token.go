@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// TokenMetadata describes a token contract
+type TokenMetadata struct {
+	Address     string    `json:"address"`
+	Name        string    `json:"name"`
+	Symbol      string    `json:"symbol"`
+	Decimals    uint8     `json:"decimals"`
+	HasDecimals bool      `json:"has_decimals"` // False if decimals could not be determined
+	TotalSupply *big.Int  `json:"total_supply,omitempty"`
+	ResolvedAt  time.Time `json:"resolved_at"`
+}
+
+// TokenRegistry resolves token metadata
+type TokenRegistry interface {
+	GetTokenMetadata(ctx context.Context, tokenAddress string) (*TokenMetadata, error)
+}
+
+// TokenMetadataStore interface for persisting resolved token metadata
+type TokenMetadataStore interface {
+	// LoadTokenMetadata returns nil if nothing is stored for the token
+	LoadTokenMetadata(ctx context.Context, tokenAddress string) (*TokenMetadata, error)
+	SaveTokenMetadata(ctx context.Context, metadata *TokenMetadata) error
+}
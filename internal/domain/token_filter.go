@@ -0,0 +1,31 @@
+package domain
+
+import "context"
+
+// TokenFilter lists tokens whose transfers are always (Allow) or never
+// (Deny) notified. Entries are contract addresses or case-insensitive
+// symbol patterns with * and ? wildcards, e.g. "*.com".
+type TokenFilter struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// TokenFilterList selects the allowlist or the denylist of a TokenFilter
+type TokenFilterList string
+
+const (
+	TokenAllowList TokenFilterList = "allow"
+	TokenDenyList  TokenFilterList = "deny"
+)
+
+// TokenFilterStatus is the response to get_token_filters
+type TokenFilterStatus struct {
+	Global TokenFilter `json:"global"` // From configuration, applies to everyone
+	User   TokenFilter `json:"user"`
+}
+
+// TokenFilterRepository interface for per-user token filter persistence
+type TokenFilterRepository interface {
+	SaveTokenFilter(ctx context.Context, userID UserID, filter TokenFilter) error
+	GetAllTokenFilters(ctx context.Context) (map[UserID]TokenFilter, error)
+}
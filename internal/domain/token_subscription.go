@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenSubscription is a user's request to follow every transfer of an
+// ERC-20 token, e.g. a new launch or a stablecoin
+type TokenSubscription struct {
+	TokenAddress WalletAddress `json:"token_address"`
+	UserID       UserID        `json:"user_id"`
+	MinAmount    string        `json:"min_amount,omitempty"` // In token units, e.g. "10000.5"
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// TokenTransferEvent is a transfer of a tracked token
+type TokenTransferEvent struct {
+	Transfer    Transfer  `json:"transfer"`
+	BlockNumber uint64    `json:"block_number"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// TokenTransferNotification delivers a token transfer to the users tracking
+// the token whose minimum amount it reaches
+type TokenTransferNotification struct {
+	Event       TokenTransferEvent `json:"event"`
+	Subscribers []UserID           `json:"subscribers"`
+	Timestamp   time.Time          `json:"timestamp"`
+	Trace       *TraceContext      `json:"trace,omitempty"`
+}
+
+// TokenTransferSource streams transfers of tracked tokens
+type TokenTransferSource interface {
+	// SubscribeToToken returns a channel of the token's transfers, closed
+	// once ctx is cancelled
+	SubscribeToToken(ctx context.Context, tokenAddress WalletAddress) (<-chan TokenTransferEvent, error)
+}
+
+// TokenSubscriptionRepository interface for token subscription persistence
+type TokenSubscriptionRepository interface {
+	AddTokenSubscription(ctx context.Context, subscription TokenSubscription) error
+	RemoveTokenSubscription(ctx context.Context, tokenAddress WalletAddress, userID UserID) error
+	GetTokenSubscriptions(ctx context.Context) ([]TokenSubscription, error)
+}
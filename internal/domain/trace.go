@@ -0,0 +1,7 @@
+package domain
+
+// TraceContext carries W3C Trace Context headers across the message bus
+type TraceContext struct {
+	Traceparent string `json:"traceparent"`
+	Tracestate  string `json:"tracestate,omitempty"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// WalletLimit is the number of wallets a user may follow (0 = unlimited)
+type WalletLimit struct {
+	UserID   UserID `json:"user_id"`
+	Limit    int    `json:"limit"`
+	Override bool   `json:"override"` // Set by an admin instead of the default
+}
+
+// WalletLimitRepository interface for per-user wallet limit overrides
+type WalletLimitRepository interface {
+	SetWalletLimit(ctx context.Context, userID UserID, limit int) error
+	DeleteWalletLimit(ctx context.Context, userID UserID) error
+	GetAllWalletLimits(ctx context.Context) (map[UserID]int, error)
+}
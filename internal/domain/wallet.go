@@ -14,41 +14,278 @@ type TransactionHash string
 
 // WalletSubscription represents a user's subscription to a wallet
 type WalletSubscription struct {
-	WalletAddress WalletAddress `json:"wallet_address"`
-	UserID        UserID        `json:"user_id"`
-	CreatedAt     time.Time     `json:"created_at"`
+	WalletAddress WalletAddress       `json:"wallet_address"`
+	UserID        UserID              `json:"user_id"`
+	Filters       *NotificationFilter `json:"filters,omitempty"`
+	Label         string              `json:"label,omitempty"` // The user's name for the wallet
+	CreatedAt     time.Time           `json:"created_at"`
+
+	// DigestWindow batches notifications into one digest per window (0 = off)
+	DigestWindow time.Duration `json:"digest_window,omitempty"`
+
+	// Paused subscriptions aren't notified until PausedUntil, or until
+	// resumed if it is nil
+	Paused      bool       `json:"paused,omitempty"`
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+}
+
+// TransferDirection is the side of a transfer the wallet is on
+type TransferDirection string
+
+const (
+	AnyDirection TransferDirection = ""
+	Incoming     TransferDirection = "in"
+	Outgoing     TransferDirection = "out"
+)
+
+// NotificationFilter narrows the transfers a subscriber is notified about.
+// Transactions left without anything to report are not sent at all.
+type NotificationFilter struct {
+	Direction TransferDirection `json:"direction,omitempty"`
+
+	// Token contracts to report; empty reports every token. Native XPL is
+	// the zero address.
+	Tokens []string `json:"tokens,omitempty"`
+
+	// Smallest native transfer reported, in XPL, e.g. "0.5"
+	MinNativeAmount string `json:"min_native_amount,omitempty"`
 }
 
 // Transfer represents a single token transfer within a transaction
 type Transfer struct {
-	TxHash       TransactionHash `json:"tx_hash"`
-	From         WalletAddress   `json:"from"`
-	To           WalletAddress   `json:"to"`
-	Value        *big.Int        `json:"value"`
-	TokenSymbol  string          `json:"token_symbol"`
-	TokenAddress string          `json:"token_address"`
-	LogIndex     int             `json:"log_index"`
+	TxHash         TransactionHash `json:"tx_hash"`
+	From           WalletAddress   `json:"from"`
+	To             WalletAddress   `json:"to"`
+	Value          *big.Int        `json:"value"`                     // Always 1 for ERC-721
+	FormattedValue string          `json:"formatted_value,omitempty"` // Value scaled by token decimals
+	ValueUSD       string          `json:"value_usd,omitempty"`       // Worth in USD at publish time, if priced
+	TokenSymbol    string          `json:"token_symbol"`
+	TokenAddress   string          `json:"token_address"`
+	TokenStandard  TokenStandard   `json:"token_standard"`
+	TokenID        *big.Int        `json:"token_id,omitempty"` // ERC-721 only
+	LogIndex       int             `json:"log_index"`
+	Internal       bool            `json:"internal,omitempty"`   // Value moved by a contract call, found via tracing
+	FromLabel      string          `json:"from_label,omitempty"` // Known name of the sender
+	ToLabel        string          `json:"to_label,omitempty"`   // Known name of the recipient
+}
+
+// Approval is an ERC-20 allowance granted by a wallet to a spender
+type Approval struct {
+	TxHash          TransactionHash `json:"tx_hash"`
+	Owner           WalletAddress   `json:"owner"`
+	Spender         WalletAddress   `json:"spender"`
+	SpenderLabel    string          `json:"spender_label,omitempty"` // Known name of the spender; empty if unknown
+	TokenAddress    string          `json:"token_address"`
+	TokenSymbol     string          `json:"token_symbol"`
+	Amount          *big.Int        `json:"amount"`
+	FormattedAmount string          `json:"formatted_amount,omitempty"`
+	Unlimited       bool            `json:"unlimited"`  // Amount is effectively infinite
+	Revocation      bool            `json:"revocation"` // Amount is zero
+	LogIndex        int             `json:"log_index"`
+}
+
+// Swap is a trade through a Uniswap V2 or V3-style pool
+type Swap struct {
+	TxHash             TransactionHash `json:"tx_hash"`
+	Protocol           string          `json:"protocol"` // "uniswap_v2" or "uniswap_v3"
+	Pool               string          `json:"pool"`
+	Sender             WalletAddress   `json:"sender"`
+	Recipient          WalletAddress   `json:"recipient"`
+	TokenIn            string          `json:"token_in"`
+	TokenInSymbol      string          `json:"token_in_symbol"`
+	AmountIn           *big.Int        `json:"amount_in"`
+	FormattedAmountIn  string          `json:"formatted_amount_in,omitempty"`
+	TokenOut           string          `json:"token_out"`
+	TokenOutSymbol     string          `json:"token_out_symbol"`
+	AmountOut          *big.Int        `json:"amount_out"`
+	FormattedAmountOut string          `json:"formatted_amount_out,omitempty"`
+	LogIndex           int             `json:"log_index"`
+}
+
+// BridgeDirection tells whether a bridge transfer enters or leaves Plasma
+type BridgeDirection string
+
+const (
+	BridgeIn  BridgeDirection = "in"
+	BridgeOut BridgeDirection = "out"
+)
+
+// BridgeTransfer is a cross-chain deposit to or withdrawal from Plasma
+type BridgeTransfer struct {
+	TxHash           TransactionHash `json:"tx_hash"`
+	Direction        BridgeDirection `json:"direction"`
+	Bridge           string          `json:"bridge"`  // Bridge contract that emitted the event
+	Account          WalletAddress   `json:"account"` // Sender on Plasma for outgoing, recipient for incoming
+	TokenAddress     string          `json:"token_address"`
+	TokenSymbol      string          `json:"token_symbol"`
+	Amount           *big.Int        `json:"amount"`
+	FormattedAmount  string          `json:"formatted_amount,omitempty"`
+	SourceChain      string          `json:"source_chain"`
+	DestinationChain string          `json:"destination_chain"`
+	TransferID       string          `json:"transfer_id"` // Cross-chain message ID, the same on both chains
+	LogIndex         int             `json:"log_index"`
 }
 
+// TokenStandard identifies what kind of asset a transfer moves
+type TokenStandard string
+
+const (
+	NativeToken TokenStandard = "native"
+	ERC20Token  TokenStandard = "erc20"
+	ERC721Token TokenStandard = "erc721"
+)
+
+// TransactionType is the EIP-2718 envelope type of a transaction
+type TransactionType string
+
+const (
+	LegacyTxType     TransactionType = "legacy"
+	AccessListTxType TransactionType = "access_list"
+	DynamicFeeTxType TransactionType = "dynamic_fee"
+	BlobTxType       TransactionType = "blob"
+	SetCodeTxType    TransactionType = "set_code"
+	UnknownTxType    TransactionType = "unknown"
+)
+
 // Transaction represents a blockchain transaction with multiple transfers
 type Transaction struct {
-	Hash        TransactionHash `json:"hash"`
-	From        WalletAddress   `json:"from"` // Transaction sender
-	To          WalletAddress   `json:"to"`   // Transaction recipient (contract)
-	BlockNumber uint64          `json:"block_number"`
-	Timestamp   time.Time       `json:"timestamp"`
-	GasUsed     uint64          `json:"gas_used"`
-	GasPrice    *big.Int        `json:"gas_price"`
-	Transfers   []Transfer      `json:"transfers"` // All transfers in this tx
+	Hash         TransactionHash   `json:"hash"`
+	Type         TransactionType   `json:"type"`
+	RawType      uint8             `json:"raw_type"` // EIP-2718 type byte, kept for unknown types
+	Kind         TransactionKind   `json:"kind"`
+	Status       TransactionStatus `json:"status"`
+	RevertReason string            `json:"revert_reason,omitempty"` // Only for reverted txs, if it could be decoded
+	// RevertReasonReplayed marks a RevertReason guessed by replaying the
+	// transaction against its parent block rather than read from its trace
+	RevertReasonReplayed bool             `json:"revert_reason_replayed,omitempty"`
+	From                 WalletAddress    `json:"from"`               // Transaction sender
+	To                   WalletAddress    `json:"to"`                 // Transaction recipient (contract)
+	Contract             WalletAddress    `json:"contract,omitempty"` // Deployed contract, for contract creations
+	Method               *MethodCall      `json:"method,omitempty"`   // Invoked function, for contract calls
+	Nonce                uint64           `json:"nonce"`
+	BlockNumber          uint64           `json:"block_number"`
+	Timestamp            time.Time        `json:"timestamp"`
+	GasUsed              uint64           `json:"gas_used"`
+	GasPrice             *big.Int         `json:"gas_price"`           // Price bid; the fee cap of EIP-1559 transactions
+	GasPricePaid         *big.Int         `json:"gas_price_paid"`      // Price charged, base fee plus tip under EIP-1559
+	Fee                  *big.Int         `json:"fee"`                 // GasUsed × GasPricePaid, in wei
+	Gasless              bool             `json:"gasless"`             // Fee sponsored by a paymaster or zero
+	Paymaster            WalletAddress    `json:"paymaster,omitempty"` // Relayer that sponsored the fee
+	FeePayer             WalletAddress    `json:"fee_payer"`           // Account charged the fee; empty for zero-fee txs
+	Transfers            []Transfer       `json:"transfers"`           // All transfers in this tx
+	Approvals            []Approval       `json:"approvals,omitempty"` // ERC-20 approvals in this tx
+	Swaps                []Swap           `json:"swaps,omitempty"`     // DEX swaps in this tx
+	Bridges              []BridgeTransfer `json:"bridges,omitempty"`   // Cross-chain transfers in this tx
+	Trace                *TraceContext    `json:"-"`                   // Ingestion span, for in-process tracing only
+}
+
+// TransactionStatus is the execution outcome of a mined transaction
+type TransactionStatus string
+
+const (
+	TxSuccess  TransactionStatus = "success"
+	TxReverted TransactionStatus = "reverted"
+)
+
+// TransactionKind tells plain value transfers apart from contract activity
+type TransactionKind string
+
+const (
+	TransferKind         TransactionKind = "transfer"          // No calldata
+	ContractCallKind     TransactionKind = "contract_call"     // Calldata sent to an existing account
+	ContractCreationKind TransactionKind = "contract_creation" // No recipient, deploys a contract
+)
+
+// MethodCall is the function a contract call invoked, as far as it could be
+// identified from its 4-byte selector
+type MethodCall struct {
+	Selector  string         `json:"selector"`            // e.g. 0xa9059cbb
+	Signature string         `json:"signature,omitempty"` // e.g. transfer(address,uint256), if known
+	Args      map[string]any `json:"args,omitempty"`      // Decoded when the contract's ABI is known
+}
+
+// NotificationType distinguishes the kinds of wallet notifications
+type NotificationType string
+
+const (
+	TransactionNotification NotificationType = "transaction"
+	ReorgNotification       NotificationType = "reorg"
+	ApprovalNotification    NotificationType = "approval"
+	SwapNotification        NotificationType = "swap"
+	BridgeInNotification    NotificationType = "bridge_in"
+	BridgeOutNotification   NotificationType = "bridge_out"
+	DigestNotification      NotificationType = "digest"
+	RateLimitedNotification NotificationType = "rate_limited"
+	SummaryNotification     NotificationType = "summary"
+)
+
+// Reorg describes a range of blocks replaced by a chain reorganization
+type Reorg struct {
+	FromBlock  uint64    `json:"from_block"` // First replaced block
+	ToBlock    uint64    `json:"to_block"`   // Last replaced block
+	NewHead    uint64    `json:"new_head"`
+	DetectedAt time.Time `json:"detected_at"`
 }
 
 // WalletNotification represents a notification to be sent
 type WalletNotification struct {
-	WalletAddress WalletAddress `json:"wallet_address"`
-	Transaction   Transaction   `json:"transaction"`
-	Transfers     []Transfer    `json:"transfers"` // Only transfers involving watched address
-	Subscribers   []UserID      `json:"subscribers"`
-	Timestamp     time.Time     `json:"timestamp"`
+	// SchemaVersion tells consumers how to read the notification, see
+	// CurrentSchemaVersion
+	SchemaVersion int `json:"schema_version"`
+
+	Type          NotificationType `json:"type"`
+	WalletAddress WalletAddress    `json:"wallet_address"`
+	Transaction   Transaction      `json:"transaction"`
+	Transfers     []Transfer       `json:"transfers"` // Only transfers involving watched address
+	Subscribers   []UserID         `json:"subscribers"`
+	Timestamp     time.Time        `json:"timestamp"`
+	Trace         *TraceContext    `json:"trace,omitempty"`
+
+	// WalletLabels holds the names subscribers gave the wallet on add_wallet
+	WalletLabels map[UserID]string `json:"wallet_labels,omitempty"`
+	// WalletGroups holds the names of the groups subscribers put the wallet in
+	WalletGroups map[UserID][]string `json:"wallet_groups,omitempty"`
+
+	// IdempotencyKey is the same for every delivery of the same notification,
+	// e.g. "0xabc…:0xdef…:transaction", so consumers can drop repeats
+	IdempotencyKey string `json:"idempotency_key"`
+
+	// Confirmations of the transaction when published; Unconfirmed marks early
+	// notifications sent before the configured confirmation depth
+	Confirmations uint64 `json:"confirmations"`
+	Unconfirmed   bool   `json:"unconfirmed,omitempty"`
+
+	// Historical marks transactions replayed by the backfill on add_wallet
+	Historical bool `json:"historical,omitempty"`
+
+	// Failed marks transactions that reverted
+	Failed bool `json:"failed,omitempty"`
+
+	// Gasless marks transactions whose fee the wallet didn't pay
+	Gasless bool `json:"gasless,omitempty"`
+
+	// Set on approval notifications: allowances granted by the wallet
+	Approvals []Approval `json:"approvals,omitempty"`
+
+	// Set on swap notifications: trades the wallet took part in
+	Swaps []Swap `json:"swaps,omitempty"`
+
+	// Set on bridge_in/bridge_out notifications: cross-chain transfers of the wallet
+	Bridges []BridgeTransfer `json:"bridges,omitempty"`
+
+	// Set on rate_limited notifications: how many notifications the
+	// subscriber didn't get because of the per-user rate limit
+	Suppressed int `json:"suppressed,omitempty"`
+
+	// Set on digest notifications: what the wallet did over the digest window
+	Digest *Digest `json:"digest,omitempty"`
+
+	// Set on summary notifications: what the user's wallets did over the period
+	Summary *Summary `json:"summary,omitempty"`
+
+	// Set on reorg notifications: previously delivered txs that are no longer canonical
+	Reorg          *Reorg            `json:"reorg,omitempty"`
+	InvalidatedTxs []TransactionHash `json:"invalidated_txs,omitempty"`
 }
 
 // Command represents a wallet management command
@@ -57,6 +294,84 @@ type Command struct {
 	WalletAddress WalletAddress `json:"wallet_address"`
 	UserID        UserID        `json:"user_id"`
 	Timestamp     time.Time     `json:"timestamp"`
+
+	// WalletAddresses are the wallets of add_wallets and remove_wallets
+	WalletAddresses []WalletAddress `json:"wallet_addresses,omitempty"`
+
+	// TargetUserID is the user whose limit set_wallet_limit changes. A nil
+	// WalletLimit restores the default, zero lifts the cap.
+	TargetUserID UserID `json:"target_user_id,omitempty"`
+	WalletLimit  *int   `json:"wallet_limit,omitempty"`
+
+	// Duration limits pause_wallet, e.g. "8h", where empty pauses until
+	// resumed. For set_digest it is the digest window, where empty turns
+	// digest mode off. For get_stats it is the window ending now, where
+	// empty covers the whole stored history, and for get_portfolio_value
+	// how far back the value is compared, where empty means a day. For
+	// gas_usage it is the window ending now, e.g. "168h" for the fees of
+	// the week, where empty reports the totals.
+	Duration string `json:"duration,omitempty"`
+
+	// Label names the wallet for the user in add_wallet, e.g. "cold wallet"
+	Label string `json:"label,omitempty"`
+
+	// Group is the wallet group the group commands refer to. add_to_group
+	// and remove_from_group take WalletAddress or WalletAddresses.
+	Group string `json:"group,omitempty"`
+
+	// Filters narrows what add_wallet and add_wallets notify the user about
+	Filters *NotificationFilter `json:"filters,omitempty"`
+
+	// Labels is the address -> label mapping for import_labels;
+	// remove_labels takes WalletAddresses
+	Labels map[WalletAddress]string `json:"labels,omitempty"`
+
+	// TokenAddress narrows get_balance to a single token, and is the token
+	// track_token and untrack_token refer to
+	TokenAddress string `json:"token_address,omitempty"`
+
+	// MinAmount is the smallest transfer track_token notifies about, in
+	// token units, e.g. "10000.5"
+	MinAmount string `json:"min_amount,omitempty"`
+
+	// Contract and the events to follow for watch_contract
+	ContractAddress WalletAddress `json:"contract_address,omitempty"`
+	ABI             string        `json:"abi,omitempty"`
+	EventSignature  string        `json:"event_signature,omitempty"`
+
+	// Block range and page size for get_history; zero values pick defaults
+	FromBlock uint64 `json:"from_block,omitempty"`
+	ToBlock   uint64 `json:"to_block,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+
+	// Rule to create for create_alert, and the rule to remove for delete_alert
+	Alert  *AlertRule `json:"alert,omitempty"`
+	RuleID string     `json:"rule_id,omitempty"`
+
+	// Callback URL for add_webhook, limited to WalletAddress if set, and the
+	// webhook to remove for delete_webhook
+	WebhookURL string `json:"webhook_url,omitempty"`
+	WebhookID  string `json:"webhook_id,omitempty"`
+
+	// LogLevel is the level set_log_level switches to, e.g. "debug"
+	LogLevel string `json:"log_level,omitempty"`
+
+	// SymbolPattern is the token filter entry for allow_token, deny_token and
+	// unlist_token when TokenAddress is not set
+	SymbolPattern string `json:"symbol_pattern,omitempty"`
+
+	// DeadLetterIDs are the dead letters requeue_dead_letters and
+	// purge_dead_letters act on. Without them, requeue_dead_letters takes
+	// the oldest Limit letters and purge_dead_letters empties the queue.
+	DeadLetterIDs []string `json:"dead_letter_ids,omitempty"`
+
+	// Trace is the caller's trace context, continued in the response
+	Trace *TraceContext `json:"trace,omitempty"`
+
+	// ReplyTo is the channel a CommandResult is published to once the
+	// command is handled, tagged with CorrelationID
+	ReplyTo       string `json:"reply_to,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 type CommandType string
@@ -64,17 +379,213 @@ type CommandType string
 const (
 	AddWalletCommand    CommandType = "add_wallet"
 	RemoveWalletCommand CommandType = "remove_wallet"
+	ImportLabelsCommand CommandType = "import_labels"
+	RemoveLabelsCommand CommandType = "remove_labels"
+	PortfolioCommand    CommandType = "portfolio_status"
+	GasUsageCommand     CommandType = "gas_usage"
+	GetBalanceCommand   CommandType = "get_balance"
+
+	GetPortfolioValueCommand CommandType = "get_portfolio_value"
+
+	WatchContractCommand   CommandType = "watch_contract"
+	UnwatchContractCommand CommandType = "unwatch_contract"
+
+	TrackTokenCommand   CommandType = "track_token"
+	UntrackTokenCommand CommandType = "untrack_token"
+
+	AllowTokenCommand      CommandType = "allow_token"
+	DenyTokenCommand       CommandType = "deny_token"
+	UnlistTokenCommand     CommandType = "unlist_token"
+	GetTokenFiltersCommand CommandType = "get_token_filters"
+
+	GetHistoryCommand CommandType = "get_history"
+	GetStatsCommand   CommandType = "get_stats"
+
+	AddWalletsCommand    CommandType = "add_wallets"
+	RemoveWalletsCommand CommandType = "remove_wallets"
+	// Removes every subscription of the user
+	RemoveAllWalletsCommand CommandType = "remove_all_wallets"
+
+	PauseWalletCommand  CommandType = "pause_wallet"
+	ResumeWalletCommand CommandType = "resume_wallet"
+	SetDigestCommand    CommandType = "set_digest"
+
+	CreateGroupCommand     CommandType = "create_group"
+	DeleteGroupCommand     CommandType = "delete_group"
+	AddToGroupCommand      CommandType = "add_to_group"
+	RemoveFromGroupCommand CommandType = "remove_from_group"
+	ListGroupsCommand      CommandType = "list_groups"
+	PauseGroupCommand      CommandType = "pause_group"
+	ResumeGroupCommand     CommandType = "resume_group"
+	GroupSummaryCommand    CommandType = "group_summary"
+
+	// Admin only
+	SetWalletLimitCommand CommandType = "set_wallet_limit"
+
+	ListWalletsCommand CommandType = "list_wallets"
+	GetStatusCommand   CommandType = "get_status"
+
+	CreateAlertCommand CommandType = "create_alert"
+	ListAlertsCommand  CommandType = "list_alerts"
+	DeleteAlertCommand CommandType = "delete_alert"
+
+	AddWebhookCommand          CommandType = "add_webhook"
+	DeleteWebhookCommand       CommandType = "delete_webhook"
+	ListWebhooksCommand        CommandType = "list_webhooks"
+	ListWebhookFailuresCommand CommandType = "list_webhook_failures"
+
+	// Admins may query other users with TargetUserID
+	GetAuditLogCommand CommandType = "get_audit_log"
+
+	// Admin only; an empty LogLevel only reports the current level
+	SetLogLevelCommand CommandType = "set_log_level"
+	// Admin only; re-reads the configuration of the instance handling it
+	ReloadConfigCommand CommandType = "reload_config"
+
+	// Admin only; list_dead_letters and requeue_dead_letters take Limit
+	ListDeadLettersCommand    CommandType = "list_dead_letters"
+	RequeueDeadLettersCommand CommandType = "requeue_dead_letters"
+	PurgeDeadLettersCommand   CommandType = "purge_dead_letters"
+)
+
+// CommandResponse represents the result of a command sent back to the bot
+type CommandResponse struct {
+	Type      CommandType   `json:"type"`
+	UserID    UserID        `json:"user_id"`
+	Success   bool          `json:"success"`
+	Error     string        `json:"error,omitempty"`
+	ErrorCode string        `json:"error_code,omitempty"` // e.g. "invalid_address"
+	Data      any           `json:"data,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+	Trace     *TraceContext `json:"trace,omitempty"`
+}
+
+type CommandStatus string
+
+const (
+	CommandSucceeded CommandStatus = "ok"
+	CommandFailed    CommandStatus = "error"
 )
 
+// CommandResult tells the sender of a command whether it was applied
+type CommandResult struct {
+	CorrelationID string        `json:"correlation_id,omitempty"`
+	Type          CommandType   `json:"type"`
+	UserID        UserID        `json:"user_id"`
+	Status        CommandStatus `json:"status"`
+	ErrorCode     string        `json:"error_code,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Data          any           `json:"data,omitempty"`
+	Timestamp     time.Time     `json:"timestamp"`
+}
+
+// TokenBalance represents the balance of a single token held by an address
+type TokenBalance struct {
+	TokenAddress     string   `json:"token_address"`
+	TokenSymbol      string   `json:"token_symbol"`
+	Balance          *big.Int `json:"balance"`
+	FormattedBalance string   `json:"formatted_balance,omitempty"` // Balance scaled by token decimals
+	ValueUSD         string   `json:"value_usd,omitempty"`         // Worth in USD, in balance snapshots
+}
+
+// WalletStatus represents the current state of a single tracked wallet
+type WalletStatus struct {
+	WalletAddress WalletAddress  `json:"wallet_address"`
+	Balances      []TokenBalance `json:"balances"`
+	LastActivity  *time.Time     `json:"last_activity,omitempty"`
+
+	// The user's subscription, in portfolio snapshots: its direction, token
+	// and min amount filters, and whether it is paused
+	Filters     *NotificationFilter `json:"filters,omitempty"`
+	Paused      bool                `json:"paused,omitempty"`
+	PausedUntil *time.Time          `json:"paused_until,omitempty"`
+}
+
+// PortfolioStatus represents a consolidated snapshot of a user's wallets
+type PortfolioStatus struct {
+	UserID       UserID            `json:"user_id"`
+	Wallets      []WalletStatus    `json:"wallets"`
+	TokenFilters TokenFilterStatus `json:"token_filters"` // Apply to all the user's wallets
+	GeneratedAt  time.Time         `json:"generated_at"`
+}
+
+// BulkSubscriptionResult summarizes add_wallets, remove_wallets and
+// remove_all_wallets
+type BulkSubscriptionResult struct {
+	Changed   []WalletAddress `json:"changed"`   // Added or removed
+	Unchanged []WalletAddress `json:"unchanged"` // Already subscribed, or not subscribed
+}
+
+// SubscriptionPause answers pause_wallet and resume_wallet
+type SubscriptionPause struct {
+	WalletAddress WalletAddress `json:"wallet_address"`
+	PausedUntil   *time.Time    `json:"paused_until,omitempty"`
+	Missed        int           `json:"missed"` // Transactions not notified while paused
+}
+
+// LogLevelStatus answers set_log_level
+type LogLevelStatus struct {
+	Level    string `json:"level"`
+	Previous string `json:"previous,omitempty"` // Set if the level changed
+}
+
+// ConfigReload answers reload_config
+type ConfigReload struct {
+	Changed    []string  `json:"changed"` // Settings that took a new value
+	ReloadedAt time.Time `json:"reloaded_at"`
+}
+
+// ListenerHealth reports wallet listeners that died and wait for a restart
+type ListenerHealth struct {
+	Active int             `json:"active"`
+	Down   []WalletAddress `json:"down"`
+	Ready  bool            `json:"ready"` // No listener has been down for long
+}
+
+// TrackerStatus is a snapshot of what the tracker is doing
+type TrackerStatus struct {
+	ActiveListeners int                   `json:"active_listeners"`
+	DownListeners   int                   `json:"down_listeners"`
+	Subscriptions   int                   `json:"subscriptions"`
+	Users           int                   `json:"users"`
+	Subscribers     map[WalletAddress]int `json:"subscribers"` // Subscriber count per wallet
+
+	// Highest block up to which every block has been processed
+	LastProcessedBlock uint64    `json:"last_processed_block,omitempty"`
+	GeneratedAt        time.Time `json:"generated_at"`
+}
+
 // BlockchainClient interface for blockchain operations
 type BlockchainClient interface {
 	// SubscribeToAddress monitors address and returns channel of transactions
-	// containing transfers that involve the specified address
+	// containing transfers that involve the specified address. Transactions
+	// keep all of their transfers.
 	SubscribeToAddress(ctx context.Context, address WalletAddress) (<-chan Transaction, error)
 
+	// SubscribeReorgs returns a channel of detected chain reorganizations
+	SubscribeReorgs(ctx context.Context) (<-chan Reorg, error)
+
+	// SubscribeHeads returns a channel of new head block numbers
+	SubscribeHeads(ctx context.Context) (<-chan uint64, error)
+
+	// GetAddressHistory returns past transactions with transfers involving
+	// address within the given block range, oldest first. A positive limit
+	// stops the scan once that many are found, cutting at a block boundary.
+	GetAddressHistory(
+		ctx context.Context,
+		address WalletAddress,
+		fromBlock uint64,
+		toBlock uint64,
+		limit int,
+	) ([]Transaction, error)
+
 	// GetLatestBlock returns the latest block number
 	GetLatestBlock(ctx context.Context) (uint64, error)
 
+	// LastProcessedBlock returns the block up to which every block has been
+	// processed, if known
+	LastProcessedBlock() (uint64, bool)
+
 	// GetTransaction returns transaction details with all transfers
 	GetTransaction(ctx context.Context, hash TransactionHash) (*Transaction, error)
 
@@ -84,22 +595,53 @@ type BlockchainClient interface {
 		txHash TransactionHash,
 		address WalletAddress,
 	) ([]Transfer, error)
+
+	// GetNativeBalance returns the native XPL balance of address
+	GetNativeBalance(ctx context.Context, address WalletAddress) (*TokenBalance, error)
+
+	// GetTokenBalance returns the ERC-20 balance of address for the given token
+	GetTokenBalance(
+		ctx context.Context,
+		address WalletAddress,
+		tokenAddress string,
+	) (*TokenBalance, error)
+
+	// GetBalances returns the native balance of address followed by its
+	// balance of each token, batched where possible
+	GetBalances(
+		ctx context.Context,
+		address WalletAddress,
+		tokenAddresses []string,
+	) ([]TokenBalance, error)
 }
 
 // Publisher interface for publishing notifications
 type Publisher interface {
 	PublishNotification(ctx context.Context, notification WalletNotification) error
+	PublishResponse(ctx context.Context, response CommandResponse) error
+	PublishCommandResult(ctx context.Context, channel string, result CommandResult) error
+	PublishContractEvent(ctx context.Context, notification ContractEventNotification) error
+	PublishTokenTransfer(ctx context.Context, notification TokenTransferNotification) error
+	PublishAlert(ctx context.Context, notification AlertNotification) error
+	PublishFirehose(ctx context.Context, block FirehoseBlock) error
 }
 
-// Subscriber interface for receiving commands
+// Subscriber interface for receiving commands. A handler error means the
+// command may succeed if delivered again.
 type Subscriber interface {
-	SubscribeCommands(ctx context.Context, handler func(Command)) error
+	SubscribeCommands(ctx context.Context, handler func(Command) error) error
 }
 
 // WalletRepository interface for wallet data persistence
 type WalletRepository interface {
 	AddSubscription(ctx context.Context, subscription WalletSubscription) error
 	RemoveSubscription(ctx context.Context, walletAddress WalletAddress, userID UserID) error
+
+	// AddSubscriptions and RemoveSubscriptions apply all changes or none
+	AddSubscriptions(ctx context.Context, subscriptions []WalletSubscription) error
+	RemoveSubscriptions(ctx context.Context, walletAddresses []WalletAddress, userID UserID) error
+
 	GetSubscribers(ctx context.Context, walletAddress WalletAddress) ([]UserID, error)
+	GetSubscriptions(ctx context.Context, walletAddress WalletAddress) ([]WalletSubscription, error)
 	GetAllWallets(ctx context.Context) ([]WalletAddress, error)
 }
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WalletGroup is a named set of a user's wallets, e.g. "exchange hot
+// wallets". Group names are unique per user, ignoring case.
+type WalletGroup struct {
+	Name      string          `json:"name"`
+	UserID    UserID          `json:"user_id"`
+	Wallets   []WalletAddress `json:"wallets"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// WalletGroupSummary is the response to group_summary
+type WalletGroupSummary struct {
+	Group       string          `json:"group"`
+	UserID      UserID          `json:"user_id"`
+	Wallets     []WalletStatus  `json:"wallets"`
+	Paused      []WalletAddress `json:"paused,omitempty"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// WalletGroupRepository interface for wallet group persistence
+type WalletGroupRepository interface {
+	SaveWalletGroup(ctx context.Context, group WalletGroup) error
+	DeleteWalletGroup(ctx context.Context, userID UserID, name string) error
+	GetAllWalletGroups(ctx context.Context) ([]WalletGroup, error)
+}
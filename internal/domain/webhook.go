@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Webhook is a callback URL a user's wallet notifications are POSTed to,
+// signed with Secret
+type Webhook struct {
+	ID     string `json:"id"`
+	UserID UserID `json:"user_id"`
+	URL    string `json:"url"`
+
+	// Wallet the webhook is for; empty sends every wallet of the user
+	WalletAddress WalletAddress `json:"wallet_address,omitempty"`
+
+	// HMAC-SHA256 key of the signature header, only shown when registered
+	Secret string `json:"secret,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeadLetter records a notification a webhook endpoint never
+// accepted, after the last retry or a permanent rejection
+type WebhookDeadLetter struct {
+	WebhookID    string             `json:"webhook_id"`
+	UserID       UserID             `json:"user_id"`
+	URL          string             `json:"url"`
+	Notification WalletNotification `json:"notification"`
+	Attempts     int                `json:"attempts"`
+	StatusCode   int                `json:"status_code,omitempty"` // Last response status, if any
+	Error        string             `json:"error"`
+	FailedAt     time.Time          `json:"failed_at"`
+}
+
+// WebhookRepository interface for webhook and dead letter persistence
+type WebhookRepository interface {
+	SaveWebhook(ctx context.Context, webhook Webhook) error
+	DeleteWebhook(ctx context.Context, id string) error
+	GetAllWebhooks(ctx context.Context) ([]Webhook, error)
+
+	// AddDeadLetter keeps a bounded number of dead letters per user
+	AddDeadLetter(ctx context.Context, deadLetter WebhookDeadLetter) error
+	// GetDeadLetters returns up to limit dead letters of userID, newest first
+	GetDeadLetters(ctx context.Context, userID UserID, limit int) ([]WebhookDeadLetter, error)
+}
+
+// WebhookSender POSTs signed payloads to webhook endpoints
+type WebhookSender interface {
+	// Send posts payload to webhook and returns the response status. An
+	// error means no response was received.
+	Send(ctx context.Context, webhook Webhook, payload []byte, idempotencyKey string) (int, error)
+}
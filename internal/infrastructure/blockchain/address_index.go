@@ -0,0 +1,132 @@
+package blockchain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// addressWatch is a single consumer of transactions for one address
+type addressWatch struct {
+	address common.Address
+	ch      chan domain.Transaction
+	// Closed once the consumer is gone, releasing a blocked delivery
+	done chan struct{}
+}
+
+func newAddressWatch(address common.Address, size int) *addressWatch {
+	return &addressWatch{
+		address: address,
+		ch:      make(chan domain.Transaction, size),
+		done:    make(chan struct{}),
+	}
+}
+
+// addressIndex maps watched addresses to their consumers
+type addressIndex struct {
+	watches map[common.Address][]*addressWatch
+	mu      sync.RWMutex
+}
+
+func newAddressIndex() *addressIndex {
+	return &addressIndex{
+		watches: make(map[common.Address][]*addressWatch),
+	}
+}
+
+func (ai *addressIndex) add(watch *addressWatch) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	ai.watches[watch.address] = append(ai.watches[watch.address], watch)
+}
+
+// remove unregisters watch and closes its channel
+func (ai *addressIndex) remove(watch *addressWatch) {
+	// Release a delivery blocked on the watch before waiting for the lock
+	close(watch.done)
+
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	watches := ai.watches[watch.address]
+	for i, w := range watches {
+		if w == watch {
+			watches = append(watches[:i], watches[i+1:]...)
+			break
+		}
+	}
+
+	if len(watches) == 0 {
+		delete(ai.watches, watch.address)
+	} else {
+		ai.watches[watch.address] = watches
+	}
+
+	close(watch.ch)
+}
+
+func (ai *addressIndex) contains(address common.Address) bool {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	_, ok := ai.watches[address]
+	return ok
+}
+
+// addresses returns a snapshot of all watched addresses
+func (ai *addressIndex) addresses() []common.Address {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	addresses := make([]common.Address, 0, len(ai.watches))
+	for address := range ai.watches {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+func (ai *addressIndex) len() int {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	return len(ai.watches)
+}
+
+// buffers reports the occupancy of all consumer channels
+func (ai *addressIndex) buffers() BufferState {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	var state BufferState
+	for _, watches := range ai.watches {
+		for _, watch := range watches {
+			state.observe(len(watch.ch), cap(watch.ch))
+		}
+	}
+	return state
+}
+
+// deliver hands tx to every watch of address, waiting while a consumer is
+// full. Consumers removed meanwhile are skipped. It returns false if ctx
+// is done before tx reached every consumer.
+func (ai *addressIndex) deliver(ctx context.Context, address common.Address, tx domain.Transaction) (int, bool) {
+	ai.mu.RLock()
+	defer ai.mu.RUnlock()
+
+	delivered := 0
+	for _, watch := range ai.watches[address] {
+		select {
+		case watch.ch <- tx:
+			delivered++
+		case <-watch.done:
+		case <-ctx.Done():
+			return delivered, false
+		}
+	}
+
+	return delivered, true
+}
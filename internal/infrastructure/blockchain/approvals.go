@@ -0,0 +1,70 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Allowances at or above 2^96-1 (the uint96 max some tokens use for
+// "infinite") are reported as unlimited
+var unlimitedAllowance = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+
+// extractApprovals decodes ERC-20 Approval events from the receipt. ERC-721
+// approvals index the token ID as a fourth topic and are not included.
+func extractApprovals(txHash common.Hash, receipt *types.Receipt) []domain.Approval {
+	var approvals []domain.Approval
+
+	for _, log := range receipt.Logs {
+		if len(log.Topics) != 3 || log.Topics[0] != approvalEventSignature {
+			continue
+		}
+
+		amount := new(big.Int).SetBytes(log.Data)
+		approvals = append(approvals, domain.Approval{
+			TxHash:       domain.TransactionHash(txHash.Hex()),
+			Owner:        domain.WalletAddress(common.BytesToAddress(log.Topics[1].Bytes()).Hex()),
+			Spender:      domain.WalletAddress(common.BytesToAddress(log.Topics[2].Bytes()).Hex()),
+			TokenAddress: log.Address.Hex(),
+			Amount:       amount,
+			Unlimited:    amount.Cmp(unlimitedAllowance) >= 0,
+			Revocation:   amount.Sign() == 0,
+			LogIndex:     int(log.Index),
+		})
+	}
+
+	return approvals
+}
+
+// enrichApprovals resolves token symbols and human-readable amounts
+func (pc *PlasmaClient) enrichApprovals(ctx context.Context, approvals []domain.Approval) {
+	tokens := make([]common.Address, 0, len(approvals))
+	for _, approval := range approvals {
+		tokens = append(tokens, common.HexToAddress(approval.TokenAddress))
+	}
+	pc.tokens.prefetch(ctx, tokens)
+
+	for i := range approvals {
+		approval := &approvals[i]
+
+		metadata := pc.tokens.resolve(ctx, common.HexToAddress(approval.TokenAddress))
+		approval.TokenSymbol = metadata.Symbol
+		if metadata.HasDecimals && !approval.Unlimited {
+			approval.FormattedAmount = formatUnits(approval.Amount, metadata.Decimals)
+		}
+	}
+}
+
+func filterApprovalsForOwner(approvals []domain.Approval, owner common.Address) []domain.Approval {
+	var relevant []domain.Approval
+	for _, approval := range approvals {
+		if common.HexToAddress(string(approval.Owner)) == owner {
+			relevant = append(relevant, approval)
+		}
+	}
+	return relevant
+}
@@ -0,0 +1,111 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// blockRetries holds blocks that failed with a transient error until they
+// are resubmitted with the next head
+type blockRetries struct {
+	max      int
+	attempts map[uint64]int
+	pending  []*types.Header
+	mu       sync.Mutex
+}
+
+func newBlockRetries(max int) *blockRetries {
+	return &blockRetries{
+		max:      max,
+		attempts: make(map[uint64]int),
+	}
+}
+
+// fail schedules header for another attempt. It returns false once the block
+// has used up its retries.
+func (br *blockRetries) fail(header *types.Header) bool {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	number := header.Number.Uint64()
+	br.attempts[number]++
+	if br.attempts[number] > br.max {
+		delete(br.attempts, number)
+		return false
+	}
+
+	br.pending = append(br.pending, header)
+	return true
+}
+
+// take returns and clears the blocks waiting to be resubmitted
+func (br *blockRetries) take() []*types.Header {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	pending := br.pending
+	br.pending = nil
+	return pending
+}
+
+// len returns the number of blocks with failed attempts
+func (br *blockRetries) len() int {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	return len(br.attempts)
+}
+
+func (br *blockRetries) clear(number uint64) {
+	br.mu.Lock()
+	delete(br.attempts, number)
+	br.mu.Unlock()
+}
+
+// fail handles a block that could not be processed. Transient failures keep
+// the block out of the checkpoint and queue it for resubmission; permanent
+// ones, or blocks out of retries, are given up on so progress isn't stalled.
+func (bp *blockPipeline) fail(ctx context.Context, stage string, header *types.Header, err error) {
+	number := header.Number.Uint64()
+	// Blocks after it no longer wait for it to be published
+	bp.reorder.drop(ctx, number)
+
+	transient := isRetryable(err) || errors.Is(err, errCircuitOpen)
+	if transient && bp.retries.fail(header) {
+		metrics.PipelineItemsTotal.WithLabelValues(stage, "retry_scheduled").Inc()
+		bp.inFlight.Done()
+		return
+	}
+
+	metrics.PipelineItemsTotal.WithLabelValues(stage, "gave_up").Inc()
+	bp.pc.logger.Error("Giving up on block, its transactions will not be notified",
+		zap.Uint64("block", number),
+		zap.String("hash", header.Hash().Hex()),
+		zap.Error(err))
+	bp.done(ctx, number)
+}
+
+// done marks the block as processed
+func (bp *blockPipeline) done(ctx context.Context, number uint64) {
+	bp.retries.clear(number)
+	bp.pc.checkpoint.markDone(ctx, number)
+	metrics.BlocksProcessedTotal.Inc()
+	bp.pc.observeBlockLag()
+	bp.inFlight.Done()
+}
+
+// resubmitFailed queues blocks that failed earlier for another attempt
+func (bp *blockPipeline) resubmitFailed(ctx context.Context) {
+	for _, header := range bp.retries.take() {
+		bp.pc.logger.Info("Retrying block", zap.Uint64("block", header.Number.Uint64()))
+		if !bp.Submit(ctx, header) {
+			return
+		}
+	}
+}
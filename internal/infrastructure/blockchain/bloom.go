@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// watchedTopicsInBloom returns the addresses that may appear as an indexed
+// topic of a Transfer or Approval log in the block. A bloom filter has no
+// false negatives, so addresses it rules out need not be queried.
+func watchedTopicsInBloom(bloom types.Bloom, addresses []common.Address) []common.Address {
+	if !bloom.Test(transferEventSignature.Bytes()) && !bloom.Test(approvalEventSignature.Bytes()) {
+		return nil
+	}
+
+	var candidates []common.Address
+	for _, address := range addresses {
+		if bloom.Test(common.BytesToHash(address.Bytes()).Bytes()) {
+			candidates = append(candidates, address)
+		}
+	}
+	return candidates
+}
+
+// emittersInBloom returns the contracts that may have emitted a log in the block
+func emittersInBloom(bloom types.Bloom, contracts []common.Address) []common.Address {
+	var candidates []common.Address
+	for _, contract := range contracts {
+		if bloom.Test(contract.Bytes()) {
+			candidates = append(candidates, contract)
+		}
+	}
+	return candidates
+}
+
+// blockMayInvolve tells from the logs bloom, the transaction list and traced
+// internal transfers whether the block can involve a watched address at all
+func (pc *PlasmaClient) blockMayInvolve(
+	block *decodedBlock,
+	index *addressIndex,
+	internal map[common.Hash][]domain.Transfer,
+) bool {
+	if len(internal) > 0 {
+		return true
+	}
+	if len(watchedTopicsInBloom(block.Bloom(), index.addresses())) > 0 {
+		return true
+	}
+
+	for _, tx := range block.Transactions() {
+		if from, err := pc.senderOf(tx); err == nil && index.contains(from) {
+			return true
+		}
+		if tx.To() != nil && index.contains(*tx.To()) {
+			return true
+		}
+	}
+	for _, otx := range block.opaque {
+		for _, address := range otx.directAddresses() {
+			if index.contains(address) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// LayerZero OFT events emitted by the bridge contracts of bridged assets
+// such as USDT0:
+// OFTSent(bytes32 indexed guid, uint32 dstEid, address indexed fromAddress, uint256 amountSentLD, uint256 amountReceivedLD)
+// OFTReceived(bytes32 indexed guid, uint32 srcEid, address indexed toAddress, uint256 amountReceivedLD)
+var (
+	oftSentEventSignature = common.HexToHash(
+		"0x85496b760a4b7f8d66384b9df21b381f5d1b1e79f229a47aaf4c232edc2fe59a",
+	)
+	oftReceivedEventSignature = common.HexToHash(
+		"0xefed6d3500546b29533b128a29e3a94d70788727f0507505ac12eaf2e578fd9c",
+	)
+)
+
+// token() selector; OFT adapters return the bridged token, native OFTs themselves
+var tokenSelector = common.FromHex("0xfc0c546a")
+
+// Chain this service runs on, the local end of every bridge transfer
+const localChain = "plasma"
+
+// LayerZero endpoint IDs of well-known chains; others are reported as "eid:<id>"
+var layerZeroChains = map[uint32]string{
+	30101: "ethereum",
+	30102: "bsc",
+	30106: "avalanche",
+	30109: "polygon",
+	30110: "arbitrum",
+	30111: "optimism",
+	30184: "base",
+	30383: localChain,
+}
+
+func layerZeroChain(eid uint32) string {
+	if name, ok := layerZeroChains[eid]; ok {
+		return name
+	}
+	return "eid:" + strconv.FormatUint(uint64(eid), 10)
+}
+
+// extractBridgeTransfers decodes deposit and withdrawal events of the
+// configured bridge contracts. Events from other contracts are ignored, as
+// anyone can emit them.
+func (pc *PlasmaClient) extractBridgeTransfers(ctx context.Context, receipt *types.Receipt) []domain.BridgeTransfer {
+	var bridges []domain.BridgeTransfer
+
+	for _, log := range receipt.Logs {
+		if len(log.Topics) != 3 || !pc.bridges[log.Address] {
+			continue
+		}
+
+		word := func(n int) *big.Int { return new(big.Int).SetBytes(log.Data[n*32 : (n+1)*32]) }
+
+		bridge := domain.BridgeTransfer{
+			TxHash:     domain.TransactionHash(receipt.TxHash.Hex()),
+			Bridge:     log.Address.Hex(),
+			Account:    domain.WalletAddress(common.BytesToAddress(log.Topics[2].Bytes()).Hex()),
+			TransferID: log.Topics[1].Hex(),
+			LogIndex:   int(log.Index),
+		}
+
+		switch log.Topics[0] {
+		case oftSentEventSignature:
+			if len(log.Data) != 3*32 {
+				continue
+			}
+			bridge.Direction = domain.BridgeOut
+			bridge.SourceChain = localChain
+			bridge.DestinationChain = layerZeroChain(uint32(word(0).Uint64()))
+			bridge.Amount = word(1)
+
+		case oftReceivedEventSignature:
+			if len(log.Data) != 2*32 {
+				continue
+			}
+			bridge.Direction = domain.BridgeIn
+			bridge.SourceChain = layerZeroChain(uint32(word(0).Uint64()))
+			bridge.DestinationChain = localChain
+			bridge.Amount = word(1)
+
+		default:
+			continue
+		}
+
+		bridge.TokenAddress = pc.bridgedToken(ctx, log.Address).Hex()
+		bridges = append(bridges, bridge)
+	}
+
+	return bridges
+}
+
+// bridgedToken returns the token moved by a bridge contract, falling back
+// to the contract itself
+func (pc *PlasmaClient) bridgedToken(ctx context.Context, bridge common.Address) common.Address {
+	result, err := pc.callContract(ctx, ethereum.CallMsg{To: &bridge, Data: tokenSelector})
+	if err != nil || len(result) != 32 {
+		pc.logger.Debug("Failed to get bridged token, using the bridge contract",
+			zap.String("bridge", bridge.Hex()),
+			zap.Error(err))
+		return bridge
+	}
+	return common.BytesToAddress(result)
+}
+
+// enrichBridgeTransfers resolves token symbols and human-readable amounts
+func (pc *PlasmaClient) enrichBridgeTransfers(ctx context.Context, bridges []domain.BridgeTransfer) {
+	for i := range bridges {
+		bridge := &bridges[i]
+
+		metadata := pc.tokens.resolve(ctx, common.HexToAddress(bridge.TokenAddress))
+		bridge.TokenSymbol = metadata.Symbol
+		if metadata.HasDecimals {
+			bridge.FormattedAmount = formatUnits(bridge.Amount, metadata.Decimals)
+		}
+	}
+}
+
+// filterBridgeTransfersFor keeps the bridge transfers of a wallet: those
+// credited to or debited from it, or all of a transaction it sent
+func filterBridgeTransfersFor(tx domain.Transaction, address common.Address) []domain.BridgeTransfer {
+	if common.HexToAddress(string(tx.From)) == address {
+		return tx.Bridges
+	}
+
+	var relevant []domain.BridgeTransfer
+	for _, bridge := range tx.Bridges {
+		if common.HexToAddress(string(bridge.Account)) == address {
+			relevant = append(relevant, bridge)
+		}
+	}
+	return relevant
+}
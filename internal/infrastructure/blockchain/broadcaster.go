@@ -0,0 +1,53 @@
+package blockchain
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// broadcaster fans values out to any number of subscribers without blocking
+type broadcaster[T any] struct {
+	size        int
+	subscribers []chan T
+	mu          sync.Mutex
+}
+
+func newBroadcaster[T any](size int) *broadcaster[T] {
+	return &broadcaster[T]{size: size}
+}
+
+// subscribe returns a channel that is closed once ctx is cancelled
+func (b *broadcaster[T]) subscribe(ctx context.Context) <-chan T {
+	ch := make(chan T, b.size)
+
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, ch)
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if i := slices.Index(b.subscribers, ch); i >= 0 {
+			b.subscribers = slices.Delete(b.subscribers, i, i+1)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// broadcast delivers value to every subscriber with room in its buffer
+func (b *broadcaster[T]) broadcast(value T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- value:
+		default:
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package blockchain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// checkpointer tracks the highest block below which every block has been
+// processed. Blocks finish out of order in the pipeline, so completions
+// above the watermark are held until the gap closes.
+type checkpointer struct {
+	store  domain.CheckpointStore
+	logger *zap.Logger
+
+	last  uint64
+	known bool
+	done  map[uint64]struct{}
+	mu    sync.Mutex
+}
+
+func newCheckpointer(store domain.CheckpointStore, logger *zap.Logger) *checkpointer {
+	return &checkpointer{
+		store:  store,
+		logger: logger,
+		done:   make(map[uint64]struct{}),
+	}
+}
+
+// load restores the persisted watermark, if any
+func (c *checkpointer) load(ctx context.Context) (uint64, bool) {
+	if c.store == nil {
+		return 0, false
+	}
+
+	blockNumber, found, err := c.store.LoadCheckpoint(ctx)
+	if err != nil {
+		c.logger.Error("Failed to load block checkpoint", zap.Error(err))
+		return 0, false
+	}
+	if !found {
+		return 0, false
+	}
+
+	c.mu.Lock()
+	c.last, c.known = blockNumber, true
+	c.mu.Unlock()
+
+	return blockNumber, true
+}
+
+// start sets the watermark just below the first block to be processed
+// when nothing was restored
+func (c *checkpointer) start(blockNumber uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.known && blockNumber > 0 {
+		c.last, c.known = blockNumber-1, true
+	}
+}
+
+// skipTo moves the watermark forward so blocks before blockNumber are no
+// longer waited for
+func (c *checkpointer) skipTo(blockNumber uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if blockNumber > 0 && blockNumber-1 > c.last {
+		c.last, c.known = blockNumber-1, true
+		for n := range c.done {
+			if n <= c.last {
+				delete(c.done, n)
+			}
+		}
+	}
+}
+
+// markDone records blockNumber as processed and persists the watermark
+// if it advanced
+func (c *checkpointer) markDone(ctx context.Context, blockNumber uint64) {
+	c.mu.Lock()
+	if blockNumber <= c.last {
+		c.mu.Unlock()
+		return
+	}
+
+	c.done[blockNumber] = struct{}{}
+	advanced := false
+	for {
+		if _, ok := c.done[c.last+1]; !ok {
+			break
+		}
+		delete(c.done, c.last+1)
+		c.last++
+		advanced = true
+	}
+	last := c.last
+	c.mu.Unlock()
+
+	if !advanced || c.store == nil {
+		return
+	}
+
+	if err := c.store.SaveCheckpoint(ctx, last); err != nil {
+		c.logger.Error("Failed to save block checkpoint",
+			zap.Uint64("block", last),
+			zap.Error(err))
+	}
+}
+
+// lastProcessed returns the current watermark
+func (c *checkpointer) lastProcessed() (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.last, c.known
+}
@@ -0,0 +1,118 @@
+package blockchain
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+var errCircuitOpen = errors.New("RPC circuit breaker is open")
+
+// Circuit breaker states, also exported as metric values
+const (
+	breakerClosed   = 0
+	breakerHalfOpen = 1
+	breakerOpen     = 2
+)
+
+var breakerStateNames = map[int]string{
+	breakerClosed:   "closed",
+	breakerHalfOpen: "half_open",
+	breakerOpen:     "open",
+}
+
+// circuitBreaker stops RPC calls after consecutive failures so an unhealthy
+// provider isn't hammered. After cooldown a single probe call is let through;
+// its outcome closes the breaker or opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	logger    *zap.Logger
+
+	state    int
+	failures int
+	openedAt time.Time
+	probing  bool
+	mu       sync.Mutex
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration, logger *zap.Logger) *circuitBreaker {
+	metrics.RPCCircuitState.Set(breakerClosed)
+
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger,
+	}
+}
+
+// allow reports whether a call may proceed
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return errCircuitOpen
+		}
+		cb.setState(breakerHalfOpen)
+		cb.probing = true
+		return nil
+	case breakerHalfOpen:
+		// Only one probe at a time
+		if cb.probing {
+			return errCircuitOpen
+		}
+		cb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record feeds the outcome of an allowed call back into the breaker
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+
+	if err == nil {
+		cb.failures = 0
+		if cb.state != breakerClosed {
+			cb.logger.Info("RPC circuit breaker closed")
+			cb.setState(breakerClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == breakerHalfOpen || (cb.threshold > 0 && cb.failures >= cb.threshold) {
+		if cb.state != breakerOpen {
+			cb.logger.Warn("RPC circuit breaker opened",
+				zap.Int("consecutive_failures", cb.failures),
+				zap.Duration("cooldown", cb.cooldown),
+				zap.Error(err))
+		}
+		cb.setState(breakerOpen)
+		cb.openedAt = time.Now()
+	}
+}
+
+// State returns the breaker state name
+func (cb *circuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return breakerStateNames[cb.state]
+}
+
+func (cb *circuitBreaker) setState(state int) {
+	cb.state = state
+	metrics.RPCCircuitState.Set(float64(state))
+}
@@ -0,0 +1,364 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// contractEventDef describes one event a contract watch is interested in
+type contractEventDef struct {
+	name  string
+	event *abi.Event // Nil for watches by signature, whose logs stay undecoded
+}
+
+// contractWatch is a single consumer of events emitted by one contract
+type contractWatch struct {
+	address common.Address
+	events  map[common.Hash]contractEventDef
+	methods map[[4]byte]*abi.Method // From the ABI, used to decode calls to the contract
+	ch      chan domain.ContractEvent
+}
+
+// newContractWatch builds a watch for the events selected by the
+// subscription's ABI fragment or, without one, its event signature
+func newContractWatch(subscription domain.ContractSubscription) (*contractWatch, error) {
+	if !common.IsHexAddress(string(subscription.ContractAddress)) {
+		return nil, domain.ErrInvalidAddress
+	}
+
+	watch := &contractWatch{
+		address: common.HexToAddress(string(subscription.ContractAddress)),
+		events:  make(map[common.Hash]contractEventDef),
+		methods: make(map[[4]byte]*abi.Method),
+		ch:      make(chan domain.ContractEvent, 100),
+	}
+
+	switch {
+	case subscription.ABI != "":
+		parsed, err := abi.JSON(strings.NewReader(subscription.ABI))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidContractABI, err)
+		}
+		for _, event := range parsed.Events {
+			if event.Anonymous {
+				continue // No signature topic to match on
+			}
+			for i := range event.Inputs {
+				if event.Inputs[i].Name == "" {
+					event.Inputs[i].Name = fmt.Sprintf("arg%d", i)
+				}
+			}
+			watch.events[event.ID] = contractEventDef{name: event.Name, event: &event}
+		}
+		for _, method := range parsed.Methods {
+			for i := range method.Inputs {
+				if method.Inputs[i].Name == "" {
+					method.Inputs[i].Name = fmt.Sprintf("arg%d", i)
+				}
+			}
+			watch.methods[[4]byte(method.ID)] = &method
+		}
+		if len(watch.events) == 0 {
+			return nil, fmt.Errorf("%w: no events in ABI", domain.ErrInvalidContractABI)
+		}
+
+	case subscription.EventSignature != "":
+		topic, name, err := parseEventSignature(subscription.EventSignature)
+		if err != nil {
+			return nil, err
+		}
+		watch.events[topic] = contractEventDef{name: name}
+
+	default:
+		return nil, fmt.Errorf("%w: an ABI or event signature is required", domain.ErrInvalidContractABI)
+	}
+
+	return watch, nil
+}
+
+// parseEventSignature accepts either a canonical signature such as
+// "Staked(address,uint256)" or its topic hash
+func parseEventSignature(signature string) (common.Hash, string, error) {
+	signature = strings.ReplaceAll(signature, " ", "")
+
+	if hash, err := hexutil.Decode(signature); err == nil && len(hash) == common.HashLength {
+		return common.BytesToHash(hash), "", nil
+	}
+
+	open := strings.IndexByte(signature, '(')
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return common.Hash{}, "", fmt.Errorf("%w: malformed event signature %q",
+			domain.ErrInvalidContractABI, signature)
+	}
+
+	return crypto.Keccak256Hash([]byte(signature)), signature[:open], nil
+}
+
+// decode converts log into a domain event, decoding its arguments when the
+// watch has the event's ABI
+func (cw *contractWatch) decode(log types.Log, blockTime uint64, logger *zap.Logger) domain.ContractEvent {
+	def := cw.events[log.Topics[0]]
+
+	event := domain.ContractEvent{
+		ContractAddress: domain.WalletAddress(log.Address.Hex()),
+		Event:           def.name,
+		Signature:       log.Topics[0].Hex(),
+		TxHash:          domain.TransactionHash(log.TxHash.Hex()),
+		BlockNumber:     log.BlockNumber,
+		LogIndex:        log.Index,
+		Timestamp:       time.Unix(int64(blockTime), 0),
+		Topics:          make([]string, 0, len(log.Topics)),
+		Data:            hexutil.Encode(log.Data),
+	}
+	for _, topic := range log.Topics {
+		event.Topics = append(event.Topics, topic.Hex())
+	}
+
+	if def.event != nil {
+		args, err := decodeEventArgs(def.event, log)
+		if err != nil {
+			// Still deliver the raw log, the ABI may not match the deployed contract
+			logger.Warn("Failed to decode contract event",
+				zap.String("contract", log.Address.Hex()),
+				zap.String("event", def.name),
+				zap.String("tx_hash", log.TxHash.Hex()),
+				zap.Error(err))
+		} else {
+			event.Args = args
+		}
+	}
+
+	return event
+}
+
+func decodeEventArgs(event *abi.Event, log types.Log) (map[string]any, error) {
+	args := make(map[string]any)
+
+	if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return nil, fmt.Errorf("failed to unpack data: %w", err)
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("failed to parse topics: %w", err)
+	}
+
+	for name, value := range args {
+		args[name] = formatEventArg(value)
+	}
+	return args, nil
+}
+
+// formatEventArg hex-encodes byte values, which would otherwise marshal as
+// base64 or arrays of numbers
+func formatEventArg(value any) any {
+	if b, ok := value.([]byte); ok {
+		return hexutil.Encode(b)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hexutil.Encode(b)
+	}
+
+	return value
+}
+
+// contractIndex holds all active contract watches
+type contractIndex struct {
+	watches []*contractWatch
+	mu      sync.RWMutex
+}
+
+func newContractIndex() *contractIndex {
+	return &contractIndex{}
+}
+
+func (ci *contractIndex) add(watch *contractWatch) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	ci.watches = append(ci.watches, watch)
+}
+
+// remove unregisters watch and closes its channel
+func (ci *contractIndex) remove(watch *contractWatch) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	for i, w := range ci.watches {
+		if w == watch {
+			ci.watches = append(ci.watches[:i], ci.watches[i+1:]...)
+			break
+		}
+	}
+
+	close(watch.ch)
+}
+
+func (ci *contractIndex) len() int {
+	ci.mu.RLock()
+	defer ci.mu.RUnlock()
+
+	return len(ci.watches)
+}
+
+// buffers reports the occupancy of all consumer channels
+func (ci *contractIndex) buffers() BufferState {
+	ci.mu.RLock()
+	defer ci.mu.RUnlock()
+
+	var state BufferState
+	for _, watch := range ci.watches {
+		state.observe(len(watch.ch), cap(watch.ch))
+	}
+	return state
+}
+
+// filter returns the distinct contracts and event topics of all watches
+func (ci *contractIndex) filter() ([]common.Address, []common.Hash) {
+	ci.mu.RLock()
+	defer ci.mu.RUnlock()
+
+	addresses := make(map[common.Address]struct{})
+	topics := make(map[common.Hash]struct{})
+	for _, watch := range ci.watches {
+		addresses[watch.address] = struct{}{}
+		for topic := range watch.events {
+			topics[topic] = struct{}{}
+		}
+	}
+
+	return mapKeys(addresses), mapKeys(topics)
+}
+
+// method returns the ABI of the function with selector on contract, if a
+// watch of the contract has one
+func (ci *contractIndex) method(contract common.Address, selector [4]byte) (*abi.Method, bool) {
+	ci.mu.RLock()
+	defer ci.mu.RUnlock()
+
+	for _, watch := range ci.watches {
+		if watch.address != contract {
+			continue
+		}
+		if method, ok := watch.methods[selector]; ok {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+// deliver hands the log to every watch interested in it without blocking
+func (ci *contractIndex) deliver(log types.Log, blockTime uint64, logger *zap.Logger) {
+	ci.mu.RLock()
+	defer ci.mu.RUnlock()
+
+	for _, watch := range ci.watches {
+		if watch.address != log.Address {
+			continue
+		}
+		if _, ok := watch.events[log.Topics[0]]; !ok {
+			continue
+		}
+
+		select {
+		case watch.ch <- watch.decode(log, blockTime, logger):
+		default:
+			metrics.NotificationsDroppedTotal.WithLabelValues("consumer_full").Inc()
+			logger.Warn("Contract event consumer is full, dropping event",
+				zap.String("contract", log.Address.Hex()),
+				zap.String("tx_hash", log.TxHash.Hex()))
+		}
+	}
+}
+
+func mapKeys[K comparable](m map[K]struct{}) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SubscribeToContract registers a contract watch. The returned channel is
+// closed once ctx is cancelled.
+func (pc *PlasmaClient) SubscribeToContract(
+	ctx context.Context,
+	subscription domain.ContractSubscription,
+) (<-chan domain.ContractEvent, error) {
+	watch, err := newContractWatch(subscription)
+	if err != nil {
+		return nil, err
+	}
+	pc.contracts.add(watch)
+
+	pc.logger.Info("Started monitoring contract",
+		zap.String("contract", watch.address.Hex()),
+		zap.Int("events", len(watch.events)))
+
+	go func() {
+		<-ctx.Done()
+		pc.contracts.remove(watch)
+
+		pc.logger.Info("Stopped monitoring contract",
+			zap.String("contract", watch.address.Hex()))
+	}()
+
+	return watch.ch, nil
+}
+
+// deliverContractEvents fetches the block's logs of all watched contracts
+// and hands them to their watches
+func (pc *PlasmaClient) deliverContractEvents(ctx context.Context, header *types.Header) error {
+	addresses, topics := pc.contracts.filter()
+	addresses = emittersInBloom(header.Bloom, addresses)
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	blockHash := header.Hash()
+	for start := 0; start < len(addresses); start += maxTopicAddresses {
+		end := min(start+maxTopicAddresses, len(addresses))
+
+		logs, err := pc.filterLogs(ctx, ethereum.FilterQuery{
+			BlockHash: &blockHash,
+			Addresses: addresses[start:end],
+			Topics:    [][]common.Hash{topics},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to filter contract logs: %w", err)
+		}
+
+		for _, log := range logs {
+			if len(log.Topics) == 0 || log.Removed {
+				continue
+			}
+			pc.contracts.deliver(log, header.Time, pc.logger)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package blockchain
+
+// BufferState is the combined occupancy of a group of buffered channels
+type BufferState struct {
+	Channels int `json:"channels"`
+	Buffered int `json:"buffered"`
+	Capacity int `json:"capacity"`
+	Full     int `json:"full"` // Channels with no free slot, dropping or blocking
+}
+
+func (bs *BufferState) observe(length, capacity int) {
+	bs.Channels++
+	bs.Buffered += length
+	bs.Capacity += capacity
+	if length == capacity {
+		bs.Full++
+	}
+}
+
+// ClientState is a snapshot of the client's in-memory state for debugging
+type ClientState struct {
+	WatchedAddresses   int    `json:"watched_addresses"`
+	ContractWatches    int    `json:"contract_watches"`
+	Head               uint64 `json:"head"`
+	LastProcessedBlock uint64 `json:"last_processed_block"`
+	FailedBlocks       int    `json:"failed_blocks"`
+	BlocksInFlight     int    `json:"blocks_in_flight"`
+
+	// Consumer channels by kind and pipeline queues by stage
+	Consumers map[string]BufferState `json:"consumers"`
+	Pipeline  map[string]BufferState `json:"pipeline"`
+
+	TokenCache   int `json:"token_cache"`
+	BlockCache   int `json:"block_cache"`
+	ReceiptCache int `json:"receipt_cache"`
+}
+
+// DebugState returns a snapshot of watches, buffers and caches
+func (pc *PlasmaClient) DebugState() ClientState {
+	state := ClientState{
+		WatchedAddresses: pc.index.len(),
+		ContractWatches:  pc.contracts.len(),
+		Head:             pc.head.Load(),
+		FailedBlocks:     pc.pipeline.retries.len(),
+		BlocksInFlight:   pc.pipeline.reorder.len(),
+		Consumers: map[string]BufferState{
+			"address":  pc.index.buffers(),
+			"contract": pc.contracts.buffers(),
+			"token":    pc.tracked.buffers(),
+		},
+		Pipeline: map[string]BufferState{
+			stageFetch:   pc.pipeline.fetch.buffers(),
+			stageMatch:   pc.pipeline.match.buffers(),
+			stageDecode:  pc.pipeline.decode.buffers(),
+			stageEnrich:  pc.pipeline.enrich.buffers(),
+			stageFilter:  pc.pipeline.filter.buffers(),
+			stagePublish: pc.pipeline.publish.buffers(),
+		},
+		TokenCache:   pc.tokens.cacheSize(),
+		BlockCache:   pc.blocks.len(),
+		ReceiptCache: pc.receipts.len(),
+	}
+	if block, ok := pc.LastProcessedBlock(); ok {
+		state.LastProcessedBlock = block
+	}
+	return state
+}
@@ -1,15 +1,19 @@
 package blockchain
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // ERC20 Transfer event signature
@@ -17,8 +21,27 @@ var transferEventSignature = common.HexToHash(
 	"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
 )
 
+// ERC20 Approval event signature
+var approvalEventSignature = common.HexToHash(
+	"0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+)
+
+// Decimals assumed for tokens that don't implement decimals()
+const defaultTokenDecimals = 18
+
+// errBadReturnData marks a call the contract answered with data that
+// doesn't decode as the ERC-20 return value
+var errBadReturnData = errors.New("unexpected return data")
+
 // ERC20 ABI for basic operations
 const ERC20ABI = `[
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "name",
+		"outputs": [{"name": "", "type": "string"}],
+		"type": "function"
+	},
 	{
 		"constant": true,
 		"inputs": [],
@@ -33,6 +56,20 @@ const ERC20ABI = `[
 		"outputs": [{"name": "", "type": "uint8"}],
 		"type": "function"
 	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "totalSupply",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "owner", "type": "address"}],
+		"name": "balanceOf",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"type": "function"
+	},
 	{
 		"anonymous": false,
 		"inputs": [
@@ -62,11 +99,11 @@ func NewERC20Helper(client *PlasmaClient) (*ERC20Helper, error) {
 	}, nil
 }
 
-func (e *ERC20Helper) GetTokenSymbol(
+func (e *ERC20Helper) GetTokenName(
 	ctx context.Context,
 	tokenAddress common.Address,
 ) (string, error) {
-	data, err := e.abi.Pack("symbol")
+	data, err := e.abi.Pack("name")
 	if err != nil {
 		return "", err
 	}
@@ -76,18 +113,34 @@ func (e *ERC20Helper) GetTokenSymbol(
 		Data: data,
 	}
 
-	result, err := e.client.rpcClient.CallContract(ctx, msg, nil)
+	result, err := e.client.callContract(ctx, msg)
 	if err != nil {
 		return "", err
 	}
 
-	var symbol string
-	err = e.abi.UnpackIntoInterface(&symbol, "symbol", result)
+	return unpackString(e.abi, "name", result)
+}
+
+func (e *ERC20Helper) GetTokenSymbol(
+	ctx context.Context,
+	tokenAddress common.Address,
+) (string, error) {
+	data, err := e.abi.Pack("symbol")
 	if err != nil {
 		return "", err
 	}
 
-	return symbol, nil
+	msg := ethereum.CallMsg{
+		To:   &tokenAddress,
+		Data: data,
+	}
+
+	result, err := e.client.callContract(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+
+	return unpackString(e.abi, "symbol", result)
 }
 
 func (e *ERC20Helper) GetTokenDecimals(
@@ -104,20 +157,85 @@ func (e *ERC20Helper) GetTokenDecimals(
 		Data: data,
 	}
 
-	result, err := e.client.rpcClient.CallContract(ctx, msg, nil)
+	result, err := e.client.callContract(ctx, msg)
 	if err != nil {
+		if isExecutionReverted(err) {
+			return defaultTokenDecimals, nil
+		}
 		return 0, err
 	}
 
+	// Contracts without decimals() and without a fallback return nothing
+	if len(result) == 0 {
+		return defaultTokenDecimals, nil
+	}
+
 	var decimals uint8
 	err = e.abi.UnpackIntoInterface(&decimals, "decimals", result)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("%w: %w", errBadReturnData, err)
 	}
 
 	return decimals, nil
 }
 
+func (e *ERC20Helper) GetTotalSupply(
+	ctx context.Context,
+	tokenAddress common.Address,
+) (*big.Int, error) {
+	data, err := e.abi.Pack("totalSupply")
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &tokenAddress,
+		Data: data,
+	}
+
+	result, err := e.client.callContract(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalSupply *big.Int
+	err = e.abi.UnpackIntoInterface(&totalSupply, "totalSupply", result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errBadReturnData, err)
+	}
+
+	return totalSupply, nil
+}
+
+func (e *ERC20Helper) GetTokenBalance(
+	ctx context.Context,
+	tokenAddress common.Address,
+	owner common.Address,
+) (*big.Int, error) {
+	data, err := e.abi.Pack("balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &tokenAddress,
+		Data: data,
+	}
+
+	result, err := e.client.callContract(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance *big.Int
+	err = e.abi.UnpackIntoInterface(&balance, "balanceOf", result)
+	if err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
 func (e *ERC20Helper) ParseTransferEvent(
 	log *types.Log,
 ) (from, to common.Address, value *big.Int, err error) {
@@ -132,6 +250,44 @@ func (e *ERC20Helper) ParseTransferEvent(
 	return from, to, value, nil
 }
 
+// unpackString decodes a string return value, accepting the bytes32 that
+// older tokens (e.g. MKR) return instead
+func unpackString(contractABI abi.ABI, method string, result []byte) (string, error) {
+	var value string
+	err := contractABI.UnpackIntoInterface(&value, method, result)
+	if err != nil {
+		if value, ok := decodeBytes32String(result); ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("%w: %w", errBadReturnData, err)
+	}
+
+	return value, nil
+}
+
+// decodeBytes32String decodes a bytes32 return value as a NUL-padded string
+func decodeBytes32String(result []byte) (string, bool) {
+	if len(result) != 32 {
+		return "", false
+	}
+
+	value := string(bytes.TrimRight(result, "\x00"))
+	if value == "" || !utf8.ValidString(value) {
+		return "", false
+	}
+	return value, true
+}
+
+// isExecutionReverted reports whether a call failed because the contract
+// reverted rather than because of a transport error
+func isExecutionReverted(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
 func IsERC20Transfer(log *types.Log) bool {
 	return len(log.Topics) > 0 && log.Topics[0] == transferEventSignature
 }
@@ -0,0 +1,30 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Block tags blocks can be processed at
+const (
+	finalityLatest    = "latest"
+	finalitySafe      = "safe"
+	finalityFinalized = "finalized"
+)
+
+// finalityTag maps the configured finality to the block number argument of
+// header requests; nil stands for the latest block
+func finalityTag(finality string) (*big.Int, error) {
+	switch finality {
+	case "", finalityLatest:
+		return nil, nil
+	case finalitySafe:
+		return big.NewInt(int64(rpc.SafeBlockNumber)), nil
+	case finalityFinalized:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber)), nil
+	default:
+		return nil, fmt.Errorf("unknown finality %q", finality)
+	}
+}
@@ -0,0 +1,81 @@
+package blockchain
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// wsAvailable reports whether a WebSocket client is connected
+func (pc *PlasmaClient) wsAvailable() bool {
+	pc.wsMu.Lock()
+	defer pc.wsMu.Unlock()
+
+	return pc.wsClient != nil
+}
+
+// dropWS closes the WebSocket client so new heads are polled instead
+func (pc *PlasmaClient) dropWS() {
+	pc.wsMu.Lock()
+	defer pc.wsMu.Unlock()
+
+	if pc.wsClient != nil {
+		pc.wsClient.Close()
+		pc.wsClient = nil
+	}
+}
+
+// pollHeads follows the chain by polling eth_blockNumber and fetching new
+// headers, for providers without WebSocket support. If WebSocket
+// endpoints are configured, reconnecting is retried periodically and
+// polling stops with a nil error once it succeeds.
+func (pc *PlasmaClient) pollHeads(ctx context.Context) error {
+	pc.logger.Info("Started polling for new heads",
+		zap.Duration("interval", pc.cfg.PollInterval))
+
+	poll := time.NewTicker(max(pc.cfg.PollInterval, time.Millisecond))
+	defer poll.Stop()
+
+	var reconnect <-chan time.Time
+	if len(pc.cfg.WSURLs) > 0 {
+		ticker := time.NewTicker(max(pc.cfg.ReconnectMaxBackoff, time.Second))
+		defer ticker.Stop()
+		reconnect = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-poll.C:
+			// A cheap eth_blockNumber first; the header is only fetched for new blocks
+			if pc.finality == nil {
+				number, err := pc.GetLatestBlock(ctx)
+				if err != nil {
+					pc.logger.Warn("Failed to poll latest block number", zap.Error(err))
+					continue
+				}
+				if number <= pc.lastSubmitted {
+					continue
+				}
+			}
+
+			header, err := pc.headerByNumber(ctx, pc.finality)
+			if err != nil {
+				pc.logger.Warn("Failed to poll latest header", zap.Error(err))
+				continue
+			}
+			pc.submitHead(ctx, header)
+
+		case <-reconnect:
+			if err := pc.reconnectWS(ctx); err != nil {
+				pc.logger.Debug("WebSocket still unavailable", zap.Error(err))
+				continue
+			}
+			pc.logger.Info("WebSocket restored, stopped polling for new heads")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// errStaleHeads is returned when the node keeps producing blocks but the
+// head subscription stopped delivering them
+var errStaleHeads = errors.New("head subscription is stale")
+
+// newHeadWatchdog returns a timer that fires once no header arrived for the
+// configured timeout, or nil if the watchdog is disabled
+func (pc *PlasmaClient) newHeadWatchdog() *time.Timer {
+	if pc.cfg.StaleHeadTimeout <= 0 {
+		return nil
+	}
+	return time.NewTimer(pc.cfg.StaleHeadTimeout)
+}
+
+// checkStaleHeads is called when no header arrived in time. It tells a
+// silently dead subscription, which must be replaced, from a chain that
+// simply hasn't produced blocks.
+func (pc *PlasmaClient) checkStaleHeads(ctx context.Context, lastHead time.Time, lastNumber uint64) error {
+	silence := time.Since(lastHead)
+	expected := uint64(0)
+	if pc.cfg.BlockTime > 0 {
+		expected = uint64(silence / pc.cfg.BlockTime)
+	}
+
+	latest, err := pc.headerByNumber(ctx, nil)
+	if err != nil {
+		pc.logger.Warn("No new heads and the latest block is unavailable",
+			zap.Duration("silence", silence),
+			zap.Error(err))
+		return nil
+	}
+
+	number := latest.Number.Uint64()
+	if number <= lastNumber {
+		pc.logger.Warn("No new blocks produced",
+			zap.Duration("silence", silence),
+			zap.Uint64("expected_blocks", expected),
+			zap.Uint64("latest_block", number))
+		return nil
+	}
+
+	metrics.StaleHeadReconnectsTotal.Inc()
+	return fmt.Errorf("%w: no heads for %s (~%d blocks expected), node is at block %d, last head was %d",
+		errStaleHeads, silence.Round(time.Second), expected, number, lastNumber)
+}
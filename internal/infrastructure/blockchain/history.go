@@ -0,0 +1,243 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// GetAddressHistory returns transactions in [fromBlock, toBlock] that move
+// funds to or from address, oldest first. ERC-20 transfers are found via
+// eth_getLogs; native transfers require scanning every block in the range.
+// With a positive limit the range is scanned in windows of BatchSize blocks
+// until limit transactions are found, and the result is cut at a block
+// boundary so the caller can resume from the block after the last one.
+func (pc *PlasmaClient) GetAddressHistory(
+	ctx context.Context,
+	address domain.WalletAddress,
+	fromBlock uint64,
+	toBlock uint64,
+	limit int,
+) ([]domain.Transaction, error) {
+	watchedAddr := common.HexToAddress(string(address))
+	window := uint64(max(pc.cfg.BatchSize, 1))
+
+	var history []domain.Transaction
+	for start := fromBlock; start <= toBlock; start += window {
+		end := min(start+window-1, toBlock)
+
+		found, err := pc.historyIn(ctx, watchedAddr, start, end)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, found...)
+
+		if limit > 0 && len(history) >= limit {
+			return truncateAtBlock(history, limit), nil
+		}
+		if end == toBlock {
+			break // Avoid overflowing start at the top of the range
+		}
+	}
+
+	return history, nil
+}
+
+// historyIn returns transactions in [fromBlock, toBlock] that move funds to
+// or from address, oldest first
+func (pc *PlasmaClient) historyIn(
+	ctx context.Context,
+	watchedAddr common.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]domain.Transaction, error) {
+	hashes, err := pc.findTokenTransferTxs(ctx, watchedAddr, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	nativeHashes, err := pc.findNativeTransferTxs(ctx, watchedAddr, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	for _, hash := range nativeHashes {
+		hashes[hash] = struct{}{}
+	}
+
+	history := make([]domain.Transaction, 0, len(hashes))
+	for hash := range hashes {
+		tx, err := pc.GetTransaction(ctx, domain.TransactionHash(hash.Hex()))
+		if err != nil {
+			pc.logger.Warn("Failed to load historical transaction",
+				zap.String("tx_hash", hash.Hex()),
+				zap.Error(err))
+			continue
+		}
+
+		if len(pc.filterTransfersForAddress(tx.Transfers, watchedAddr)) > 0 {
+			history = append(history, *tx)
+		}
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].BlockNumber < history[j].BlockNumber
+	})
+
+	return history, nil
+}
+
+// truncateAtBlock cuts history to at most limit transactions without
+// splitting a block. A single block with more than limit transactions is
+// returned whole.
+func truncateAtBlock(history []domain.Transaction, limit int) []domain.Transaction {
+	if len(history) <= limit {
+		return history
+	}
+
+	cut := limit
+	for cut > 0 && history[cut].BlockNumber == history[cut-1].BlockNumber {
+		cut--
+	}
+	if cut == 0 {
+		for cut < len(history) && history[cut].BlockNumber == history[0].BlockNumber {
+			cut++
+		}
+	}
+
+	return history[:cut]
+}
+
+// findTokenTransferTxs returns hashes of transactions emitting a Transfer
+// event from or to address, querying logs in chunks of BatchSize blocks
+func (pc *PlasmaClient) findTokenTransferTxs(
+	ctx context.Context,
+	address common.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) (map[common.Hash]struct{}, error) {
+	addressTopic := common.BytesToHash(address.Bytes())
+	queries := [][][]common.Hash{
+		{{transferEventSignature}, {addressTopic}},      // Outgoing
+		{{transferEventSignature}, nil, {addressTopic}}, // Incoming
+	}
+
+	chunk := uint64(max(pc.cfg.BatchSize, 1))
+	hashes := make(map[common.Hash]struct{})
+
+	for start := fromBlock; start <= toBlock; start += chunk {
+		end := min(start+chunk-1, toBlock)
+
+		for _, topics := range queries {
+			logs, err := pc.filterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Topics:    topics,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to filter logs %d-%d: %w", start, end, err)
+			}
+
+			for _, log := range logs {
+				if !log.Removed {
+					hashes[log.TxHash] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return hashes, nil
+}
+
+// findNativeTransferTxs scans blocks for value transfers sent by or to
+// address. Blocks are fetched in JSON-RPC batches, reading only the
+// envelope of each transaction, so the sender comes from the node instead
+// of signature recovery and undecodable types are scanned too.
+func (pc *PlasmaClient) findNativeTransferTxs(
+	ctx context.Context,
+	address common.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]common.Hash, error) {
+	var hashes []common.Hash
+
+	for start := fromBlock; start <= toBlock; start += maxReceiptBatch {
+		end := min(start+maxReceiptBatch-1, toBlock)
+
+		blocks, err := withRetry(ctx, pc, "blocks", func(ctx context.Context, c *ethclient.Client) ([]envelopeBlock, error) {
+			return batchBlockEnvelopes(ctx, c, start, end)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blocks %d-%d: %w", start, end, err)
+		}
+
+		for _, block := range blocks {
+			for _, tx := range block.Transactions {
+				if tx.value().Sign() <= 0 {
+					continue
+				}
+				if tx.From == address || (tx.To != nil && *tx.To == address) {
+					hashes = append(hashes, tx.Hash)
+				}
+			}
+		}
+		if end == toBlock {
+			break // Avoid overflowing start at the top of the range
+		}
+	}
+
+	return hashes, nil
+}
+
+// envelopeBlock is a block with only the envelope of its transactions
+type envelopeBlock struct {
+	Transactions []opaqueTx `json:"transactions"`
+}
+
+// batchBlockEnvelopes fetches the blocks in [fromBlock, toBlock] in a
+// single batch
+func batchBlockEnvelopes(
+	ctx context.Context,
+	client *ethclient.Client,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]envelopeBlock, error) {
+	blocks := make([]*envelopeBlock, toBlock-fromBlock+1)
+	batch := make([]rpc.BatchElem, len(blocks))
+	for i := range batch {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []any{hexutil.EncodeUint64(fromBlock + uint64(i)), true},
+			Result: &blocks[i],
+		}
+	}
+
+	if err := client.Client().BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to batch block calls: %w", err)
+	}
+
+	result := make([]envelopeBlock, 0, len(blocks))
+	var errs []error
+	for i, elem := range batch {
+		switch {
+		case elem.Error != nil:
+			errs = append(errs, fmt.Errorf("block %d: %w", fromBlock+uint64(i), elem.Error))
+		case blocks[i] == nil:
+			errs = append(errs, fmt.Errorf("block %d: %w", fromBlock+uint64(i), ethereum.NotFound))
+		default:
+			result = append(result, *blocks[i])
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,221 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Supported tracing APIs for internal transfer detection
+const (
+	traceModeDebug  = "debug"  // debug_traceBlockByHash with the callTracer
+	traceModeParity = "parity" // trace_block / trace_transaction
+)
+
+// callFrame is a call as reported by geth's callTracer
+type callFrame struct {
+	Type  string         `json:"type"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+	Error string         `json:"error"`
+	Calls []callFrame    `json:"calls"`
+
+	Output       hexutil.Bytes `json:"output"`
+	RevertReason string        `json:"revertReason"`
+}
+
+// parityTrace is a single flattened trace_* entry
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType string         `json:"callType"`
+		From     common.Address `json:"from"`
+		To       common.Address `json:"to"`
+		Value    *hexutil.Big   `json:"value"`
+	} `json:"action"`
+	BlockHash       common.Hash `json:"blockHash"`
+	TransactionHash common.Hash `json:"transactionHash"`
+	TraceAddress    []int       `json:"traceAddress"`
+	Error           string      `json:"error"`
+}
+
+var callTracerConfig = map[string]any{"tracer": "callTracer"}
+
+// blockInternalTransfers returns native value moved by contract calls inside
+// each transaction of block, or nil if tracing is disabled
+func (pc *PlasmaClient) blockInternalTransfers(
+	ctx context.Context,
+	block *types.Block,
+) (map[common.Hash][]domain.Transfer, error) {
+	transfers := make(map[common.Hash][]domain.Transfer)
+
+	switch pc.cfg.TraceMode {
+	case traceModeDebug:
+		var results []struct {
+			TxHash common.Hash `json:"txHash"`
+			Result callFrame   `json:"result"`
+		}
+		if err := pc.traceCall(ctx, &results, "debug_traceBlockByHash", block.Hash(), callTracerConfig); err != nil {
+			return nil, fmt.Errorf("failed to trace block: %w", err)
+		}
+
+		txs := block.Transactions()
+		for i, result := range results {
+			// Older nodes don't report the hash, results follow block order
+			txHash := result.TxHash
+			if txHash == (common.Hash{}) && i < len(txs) {
+				txHash = txs[i].Hash()
+			}
+			transfers[txHash] = callFrameTransfers(txHash, result.Result)
+		}
+
+	case traceModeParity:
+		var traces []parityTrace
+		if err := pc.traceCall(ctx, &traces, "trace_block", hexutil.EncodeBig(block.Number())); err != nil {
+			return nil, fmt.Errorf("failed to trace block: %w", err)
+		}
+
+		for _, trace := range traces {
+			// trace_block goes by number, so ignore traces of a reorged block
+			if trace.BlockHash != block.Hash() {
+				continue
+			}
+			if transfer, ok := parityTraceTransfer(trace); ok {
+				transfers[trace.TransactionHash] = append(transfers[trace.TransactionHash], transfer)
+			}
+		}
+
+	default:
+		return nil, nil
+	}
+
+	return transfers, nil
+}
+
+// txInternalTransfers returns native value moved by contract calls inside a
+// single transaction, or nil if tracing is disabled
+func (pc *PlasmaClient) txInternalTransfers(ctx context.Context, txHash common.Hash) ([]domain.Transfer, error) {
+	switch pc.cfg.TraceMode {
+	case traceModeDebug:
+		var frame callFrame
+		if err := pc.traceCall(ctx, &frame, "debug_traceTransaction", txHash, callTracerConfig); err != nil {
+			return nil, fmt.Errorf("failed to trace transaction: %w", err)
+		}
+		return callFrameTransfers(txHash, frame), nil
+
+	case traceModeParity:
+		var traces []parityTrace
+		if err := pc.traceCall(ctx, &traces, "trace_transaction", txHash); err != nil {
+			return nil, fmt.Errorf("failed to trace transaction: %w", err)
+		}
+
+		var transfers []domain.Transfer
+		for _, trace := range traces {
+			if transfer, ok := parityTraceTransfer(trace); ok {
+				transfers = append(transfers, transfer)
+			}
+		}
+		return transfers, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// traceCall sends a tracing request to the dedicated trace node if one is
+// configured, otherwise through the regular RPC pool
+func (pc *PlasmaClient) traceCall(ctx context.Context, result any, method string, args ...any) error {
+	if pc.traceClient != nil {
+		return pc.traceClient.CallContext(ctx, result, method, args...)
+	}
+
+	_, err := withRetry(ctx, pc, "trace", func(ctx context.Context, c *ethclient.Client) (struct{}, error) {
+		return struct{}{}, c.Client().CallContext(ctx, result, method, args...)
+	})
+	return err
+}
+
+// callFrameTransfers collects value-carrying subcalls of a successful call tree
+func callFrameTransfers(txHash common.Hash, root callFrame) []domain.Transfer {
+	// A failed top-level call reverts everything beneath it
+	if root.Error != "" {
+		return nil
+	}
+
+	var transfers []domain.Transfer
+	var walk func(frames []callFrame)
+	walk = func(frames []callFrame) {
+		for _, frame := range frames {
+			if frame.Error != "" {
+				continue // Reverted along with its subcalls
+			}
+			if carriesValue(frame.Type, frame.Value) {
+				transfers = append(transfers, internalTransfer(txHash, frame.From, frame.To, frame.Value.ToInt()))
+			}
+			walk(frame.Calls)
+		}
+	}
+	walk(root.Calls)
+
+	return transfers
+}
+
+func parityTraceTransfer(trace parityTrace) (domain.Transfer, bool) {
+	// The top-level call is the transaction itself
+	if len(trace.TraceAddress) == 0 || trace.Error != "" {
+		return domain.Transfer{}, false
+	}
+
+	callType := trace.Type
+	if trace.Type == "call" {
+		callType = trace.Action.CallType
+	}
+	if !carriesValue(callType, trace.Action.Value) {
+		return domain.Transfer{}, false
+	}
+
+	action := trace.Action
+	return internalTransfer(trace.TransactionHash, action.From, action.To, action.Value.ToInt()), true
+}
+
+// carriesValue reports whether a call of the given type moves value;
+// DELEGATECALL and STATICCALL never do
+func carriesValue(callType string, value *hexutil.Big) bool {
+	switch callType {
+	case "CALL", "call", "CREATE", "create", "CREATE2", "create2":
+		return value != nil && value.ToInt().Sign() > 0
+	default:
+		return false
+	}
+}
+
+func internalTransfer(txHash common.Hash, from, to common.Address, value *big.Int) domain.Transfer {
+	return domain.Transfer{
+		TxHash:        domain.TransactionHash(txHash.Hex()),
+		From:          domain.WalletAddress(from.Hex()),
+		To:            domain.WalletAddress(to.Hex()),
+		Value:         value,
+		TokenSymbol:   "XPL",
+		TokenAddress:  "0x0000000000000000000000000000000000000000",
+		TokenStandard: domain.NativeToken,
+		LogIndex:      -1,
+		Internal:      true,
+	}
+}
+
+func dialTraceClient(ctx context.Context, url string) (*rpc.Client, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to trace node: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,132 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Maximum number of addresses OR-ed into a single topic filter
+const maxTopicAddresses = 500
+
+// watchedLogsFor returns Transfer logs in the block whose indexed from or
+// to topic is one of addresses, and Approval logs granted by them
+func (pc *PlasmaClient) watchedLogsFor(
+	ctx context.Context,
+	blockHash common.Hash,
+	addresses []common.Address,
+) ([]types.Log, error) {
+	var logs []types.Log
+
+	for start := 0; start < len(addresses); start += maxTopicAddresses {
+		end := min(start+maxTopicAddresses, len(addresses))
+
+		topics := make([]common.Hash, 0, end-start)
+		for _, address := range addresses[start:end] {
+			topics = append(topics, common.BytesToHash(address.Bytes()))
+		}
+
+		for _, filter := range [][][]common.Hash{
+			{{transferEventSignature, approvalEventSignature}, topics}, // Outgoing and approvals
+			{{transferEventSignature}, nil, topics},                    // Incoming
+		} {
+			found, err := pc.filterLogs(ctx, ethereum.FilterQuery{
+				BlockHash: &blockHash,
+				Topics:    filter,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to filter logs: %w", err)
+			}
+			logs = append(logs, found...)
+		}
+	}
+
+	return logs, nil
+}
+
+// matchBlockByLogs finds transactions in block involving watched addresses
+// without fetching every receipt: ERC-20 transfers and approvals come from a
+// topic-filtered eth_getLogs, native transfers and sent transactions from the block's
+// transaction list
+func (pc *PlasmaClient) matchBlockByLogs(
+	ctx context.Context,
+	block *decodedBlock,
+	index *addressIndex,
+	internal map[common.Hash][]domain.Transfer,
+) (map[common.Hash][]common.Address, error) {
+	matches := make(map[common.Hash][]common.Address)
+	add := func(txHash common.Hash, address common.Address) {
+		for _, known := range matches[txHash] {
+			if known == address {
+				return
+			}
+		}
+		matches[txHash] = append(matches[txHash], address)
+	}
+
+	// 1. ERC-20 transfers and approvals via log filter, only for addresses
+	// the block's logs bloom doesn't rule out
+	var logs []types.Log
+	if candidates := watchedTopicsInBloom(block.Bloom(), index.addresses()); len(candidates) > 0 {
+		var err error
+		if logs, err = pc.watchedLogsFor(ctx, block.Hash(), candidates); err != nil {
+			return nil, err
+		}
+	}
+	for _, log := range logs {
+		if len(log.Topics) < 3 {
+			continue
+		}
+
+		// Only the owner of an approval cares about it, not the spender
+		topics := log.Topics[1:3]
+		if log.Topics[0] == approvalEventSignature {
+			topics = log.Topics[1:2]
+		}
+		for _, topic := range topics {
+			if address := common.BytesToAddress(topic.Bytes()); index.contains(address) {
+				add(log.TxHash, address)
+			}
+		}
+	}
+
+	// 2. Native transfers need the transaction itself. Everything a watched
+	// address sends is matched so reverted transactions are reported too.
+	for _, tx := range block.Transactions() {
+		if from, err := pc.senderOf(tx); err == nil && index.contains(from) {
+			add(tx.Hash(), from)
+		}
+		if tx.Value().Sign() > 0 && tx.To() != nil && index.contains(*tx.To()) {
+			add(tx.Hash(), *tx.To())
+		}
+	}
+	for _, otx := range block.opaque {
+		if index.contains(otx.From) {
+			add(otx.Hash, otx.From)
+		}
+		if otx.value().Sign() > 0 && otx.To != nil && index.contains(*otx.To) {
+			add(otx.Hash, *otx.To)
+		}
+	}
+
+	// 3. Internal transfers found by tracing
+	for txHash, transfers := range internal {
+		for _, transfer := range transfers {
+			for _, address := range []common.Address{
+				common.HexToAddress(string(transfer.From)),
+				common.HexToAddress(string(transfer.To)),
+			} {
+				if index.contains(address) {
+					add(txHash, address)
+				}
+			}
+		}
+	}
+
+	return matches, nil
+}
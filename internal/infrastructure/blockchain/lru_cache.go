@@ -0,0 +1,88 @@
+package blockchain
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+)
+
+// lruCache is a size-bounded map evicting the least recently used entry.
+// A size of zero or less disables caching.
+type lruCache[K comparable, V any] struct {
+	name  string // Metric label
+	size  int
+	items map[K]*list.Element
+	order *list.List // Front is the most recently used
+	mu    sync.Mutex
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRUCache[K comparable, V any](name string, size int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		name:  name,
+		size:  size,
+		items: make(map[K]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		if c.size > 0 {
+			metrics.CacheRequestsTotal.WithLabelValues(c.name, "miss").Inc()
+		}
+		var zero V
+		return zero, false
+	}
+
+	metrics.CacheRequestsTotal.WithLabelValues(c.name, "hit").Inc()
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+func (c *lruCache[K, V]) add(key K, value V) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+func (c *lruCache[K, V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// purge drops every entry
+func (c *lruCache[K, V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+}
@@ -0,0 +1,122 @@
+package blockchain
+
+import (
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// Functions commonly called on Plasma, identified without an ABI
+var knownMethodSignatures = []string{
+	// ERC-20 and permits
+	"transfer(address,uint256)",
+	"transferFrom(address,address,uint256)",
+	"approve(address,uint256)",
+	"increaseAllowance(address,uint256)",
+	"decreaseAllowance(address,uint256)",
+	"permit(address,address,uint256,uint256,uint8,bytes32,bytes32)",
+
+	// EIP-3009 gasless transfers
+	"transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)",
+	"receiveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)",
+	"transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,bytes)",
+	"receiveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,bytes)",
+
+	// Wrapped XPL
+	"deposit()",
+	"withdraw(uint256)",
+
+	// Uniswap V2 style routers
+	"swapExactTokensForTokens(uint256,uint256,address[],address,uint256)",
+	"swapTokensForExactTokens(uint256,uint256,address[],address,uint256)",
+	"swapExactETHForTokens(uint256,address[],address,uint256)",
+	"swapExactTokensForETH(uint256,uint256,address[],address,uint256)",
+	"addLiquidity(address,address,uint256,uint256,uint256,uint256,address,uint256)",
+	"removeLiquidity(address,address,uint256,uint256,uint256,address,uint256)",
+
+	// Uniswap V3 style routers
+	"exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))",
+	"exactInput((bytes,address,uint256,uint256,uint256))",
+	"multicall(bytes[])",
+	"multicall(uint256,bytes[])",
+
+	// LayerZero OFT
+	"send((uint32,bytes32,uint256,uint256,bytes,bytes,bytes),(uint256,uint256),address)",
+}
+
+// knownMethods maps 4-byte selectors to knownMethodSignatures
+var knownMethods = func() map[[4]byte]string {
+	methods := make(map[[4]byte]string, len(knownMethodSignatures))
+	for _, signature := range knownMethodSignatures {
+		methods[[4]byte(crypto.Keccak256([]byte(signature))[:4])] = signature
+	}
+	return methods
+}()
+
+// txKindOf classifies a transaction by its recipient and calldata
+func txKindOf(to *common.Address, data []byte) domain.TransactionKind {
+	switch {
+	case to == nil:
+		return domain.ContractCreationKind
+	case len(data) == 0:
+		return domain.TransferKind
+	default:
+		return domain.ContractCallKind
+	}
+}
+
+// createdContract returns the address deployed by a successful contract
+// creation, or "" for anything else
+func createdContract(to *common.Address, receipt *types.Receipt) domain.WalletAddress {
+	if to != nil || receipt.Status != types.ReceiptStatusSuccessful ||
+		receipt.ContractAddress == (common.Address{}) {
+		return ""
+	}
+	return domain.WalletAddress(receipt.ContractAddress.Hex())
+}
+
+// methodOf identifies the function a contract call invokes. The ABIs of
+// watched contracts take precedence over the built-in signatures, and also
+// decode the call's arguments.
+func (pc *PlasmaClient) methodOf(txHash common.Hash, to *common.Address, data []byte) *domain.MethodCall {
+	if to == nil || len(data) < 4 {
+		return nil
+	}
+
+	selector := [4]byte(data[:4])
+	call := &domain.MethodCall{Selector: hexutil.Encode(selector[:])}
+
+	if method, ok := pc.contracts.method(*to, selector); ok {
+		call.Signature = method.Sig
+		args, err := decodeMethodArgs(method, data[4:])
+		if err != nil {
+			pc.logger.Debug("Failed to decode method arguments",
+				zap.String("tx_hash", txHash.Hex()),
+				zap.String("method", method.Sig),
+				zap.Error(err))
+		} else {
+			call.Args = args
+		}
+		return call
+	}
+
+	call.Signature = knownMethods[selector]
+	return call
+}
+
+func decodeMethodArgs(method *abi.Method, data []byte) (map[string]any, error) {
+	args := make(map[string]any)
+	if err := method.Inputs.UnpackIntoMap(args, data); err != nil {
+		return nil, err
+	}
+
+	for name, value := range args {
+		args[name] = formatEventArg(value)
+	}
+	return args, nil
+}
@@ -0,0 +1,236 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall3 ABI, limited to the functions we use
+const multicall3ABI = `[
+	{
+		"inputs": [{
+			"components": [
+				{"name": "target", "type": "address"},
+				{"name": "allowFailure", "type": "bool"},
+				{"name": "callData", "type": "bytes"}
+			],
+			"name": "calls",
+			"type": "tuple[]"
+		}],
+		"name": "aggregate3",
+		"outputs": [{
+			"components": [
+				{"name": "success", "type": "bool"},
+				{"name": "returnData", "type": "bytes"}
+			],
+			"name": "returnData",
+			"type": "tuple[]"
+		}],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "addr", "type": "address"}],
+		"name": "getEthBalance",
+		"outputs": [{"name": "balance", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// call3 is a single call inside an aggregate3 batch
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// result3 is the outcome of a single call3
+type result3 struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Multicall batches many read-only contract calls into one eth_call through
+// a deployed Multicall3 contract
+type Multicall struct {
+	client    *PlasmaClient
+	address   common.Address
+	abi       abi.ABI
+	erc20     abi.ABI
+	batchSize int
+}
+
+func newMulticall(client *PlasmaClient, address string, batchSize int) (*Multicall, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("multicall %q: %w", address, domain.ErrInvalidAddress)
+	}
+
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, err
+	}
+	erc20ABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Multicall{
+		client:    client,
+		address:   common.HexToAddress(address),
+		abi:       multicallABI,
+		erc20:     erc20ABI,
+		batchSize: max(batchSize, 1),
+	}, nil
+}
+
+// aggregate runs calls in as few eth_calls as the batch size allows. Results
+// are in call order; individual calls may fail without failing the batch.
+func (m *Multicall) aggregate(ctx context.Context, calls []call3) ([]result3, error) {
+	results := make([]result3, 0, len(calls))
+
+	for start := 0; start < len(calls); start += m.batchSize {
+		end := min(start+m.batchSize, len(calls))
+
+		data, err := m.abi.Pack("aggregate3", calls[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack multicall: %w", err)
+		}
+
+		output, err := m.client.callContract(ctx, ethereum.CallMsg{To: &m.address, Data: data})
+		if err != nil {
+			return nil, fmt.Errorf("failed to call multicall: %w", err)
+		}
+
+		unpacked, err := m.abi.Unpack("aggregate3", output)
+		if err != nil {
+			// An address without code answers with empty data
+			return nil, fmt.Errorf("failed to unpack multicall result (is Multicall3 deployed?): %w", err)
+		}
+
+		batch := *abi.ConvertType(unpacked[0], new([]result3)).(*[]result3)
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("multicall returned %d results for %d calls", len(batch), end-start)
+		}
+		results = append(results, batch...)
+	}
+
+	return results, nil
+}
+
+// tokenMetadata resolves name, symbol, decimals and total supply of every
+// token with four calls per token in a single batch
+func (m *Multicall) tokenMetadata(ctx context.Context, tokens []common.Address) ([]*domain.TokenMetadata, error) {
+	methods := []string{"name", "symbol", "decimals", "totalSupply"}
+
+	calls := make([]call3, 0, len(tokens)*len(methods))
+	for _, token := range tokens {
+		for _, method := range methods {
+			data, err := m.erc20.Pack(method)
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, call3{Target: token, AllowFailure: true, CallData: data})
+		}
+	}
+
+	results, err := m.aggregate(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make([]*domain.TokenMetadata, len(tokens))
+	for i, token := range tokens {
+		name, symbol, decimals, totalSupply := results[i*4], results[i*4+1], results[i*4+2], results[i*4+3]
+
+		md := &domain.TokenMetadata{
+			Address:    token.Hex(),
+			Symbol:     token.Hex()[:8],
+			ResolvedAt: time.Now(),
+		}
+		if name.Success {
+			if value, err := unpackString(m.erc20, "name", name.ReturnData); err == nil {
+				md.Name = value
+			}
+		}
+		if symbol.Success {
+			if value, err := unpackString(m.erc20, "symbol", symbol.ReturnData); err == nil {
+				md.Symbol = value
+			}
+		}
+
+		// Same fallback as ERC20Helper.GetTokenDecimals
+		md.Decimals, md.HasDecimals = defaultTokenDecimals, true
+		if decimals.Success && len(decimals.ReturnData) > 0 {
+			var value uint8
+			if err := m.erc20.UnpackIntoInterface(&value, "decimals", decimals.ReturnData); err == nil {
+				md.Decimals = value
+			}
+		}
+
+		if totalSupply.Success {
+			var value *big.Int
+			if err := m.erc20.UnpackIntoInterface(&value, "totalSupply", totalSupply.ReturnData); err == nil {
+				md.TotalSupply = value
+			}
+		}
+
+		metadata[i] = md
+	}
+
+	return metadata, nil
+}
+
+// balances returns the native balance of owner and its balance of every
+// token in a single batch. A nil token balance means the call failed.
+func (m *Multicall) balances(
+	ctx context.Context,
+	owner common.Address,
+	tokens []common.Address,
+) (*big.Int, []*big.Int, error) {
+	nativeData, err := m.abi.Pack("getEthBalance", owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	calls := []call3{{Target: m.address, CallData: nativeData}}
+
+	for _, token := range tokens {
+		data, err := m.erc20.Pack("balanceOf", owner)
+		if err != nil {
+			return nil, nil, err
+		}
+		calls = append(calls, call3{Target: token, AllowFailure: true, CallData: data})
+	}
+
+	results, err := m.aggregate(ctx, calls)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var native *big.Int
+	if err := m.abi.UnpackIntoInterface(&native, "getEthBalance", results[0].ReturnData); err != nil {
+		return nil, nil, fmt.Errorf("failed to unpack native balance: %w", err)
+	}
+
+	balances := make([]*big.Int, len(tokens))
+	for i, result := range results[1:] {
+		if !result.Success {
+			continue
+		}
+		var balance *big.Int
+		if err := m.erc20.UnpackIntoInterface(&balance, "balanceOf", result.ReturnData); err == nil {
+			balances[i] = balance
+		}
+	}
+
+	return native, balances, nil
+}
@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"bytes"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EIP-3009 entry points used by Plasma's gasless USDT0 transfers: the token
+// holder signs an authorization and a relayer submits it, paying the gas
+var authorizationSelectors = [][]byte{
+	common.FromHex("0xe3ee160e"), // transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)
+	common.FromHex("0xef55bec6"), // receiveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)
+	common.FromHex("0xcf092995"), // transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,bytes)
+	common.FromHex("0x88b7ab63"), // receiveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,bytes)
+}
+
+// authorizerOf returns the token holder of an EIP-3009 authorization call,
+// its first argument
+func authorizerOf(data []byte) (common.Address, bool) {
+	if len(data) < 4+32 {
+		return common.Address{}, false
+	}
+
+	for _, selector := range authorizationSelectors {
+		if bytes.Equal(data[:4], selector) {
+			return common.BytesToAddress(data[4:36]), true
+		}
+	}
+	return common.Address{}, false
+}
+
+// sponsorship describes who paid for a transaction
+type sponsorship struct {
+	gasless   bool
+	paymaster domain.WalletAddress
+	feePayer  domain.WalletAddress
+}
+
+// sponsorshipOf detects transactions whose fee was not paid by the account
+// acting in them: EIP-3009 authorizations relayed by someone else,
+// transactions sent by a configured paymaster, and zero-fee transactions.
+// from is empty if the sender couldn't be recovered.
+func (pc *PlasmaClient) sponsorshipOf(
+	data []byte,
+	receipt *types.Receipt,
+	from domain.WalletAddress,
+) sponsorship {
+	s := sponsorship{feePayer: from}
+	if from == "" {
+		return s
+	}
+	sender := common.HexToAddress(string(from))
+
+	if authorizer, ok := authorizerOf(data); ok && authorizer != sender {
+		s.gasless = true
+		s.paymaster = from
+	}
+	if pc.paymasters[sender] {
+		s.gasless = true
+		s.paymaster = from
+	}
+
+	// Nobody paid at all
+	if receipt.EffectiveGasPrice != nil && receipt.EffectiveGasPrice.Sign() == 0 {
+		s.gasless = true
+		s.feePayer = ""
+	}
+
+	return s
+}
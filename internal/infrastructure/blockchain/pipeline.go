@@ -0,0 +1,688 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Pipeline stage names, used as metric labels
+const (
+	stageFetch   = "fetch"
+	stageMatch   = "match"
+	stageDecode  = "decode"
+	stageEnrich  = "enrich"
+	stageFilter  = "filter"
+	stagePublish = "publish"
+)
+
+// stage is a bounded queue drained by a configurable number of workers
+type stage[T any] struct {
+	name   string
+	queue  chan T
+	logger *zap.Logger
+}
+
+func newStage[T any](name string, size int, logger *zap.Logger) *stage[T] {
+	return &stage[T]{
+		name:   name,
+		queue:  make(chan T, size),
+		logger: logger,
+	}
+}
+
+// push enqueues item, blocking while the stage is full
+func (s *stage[T]) push(ctx context.Context, item T) bool {
+	select {
+	case s.queue <- item:
+		metrics.PipelineQueueDepth.WithLabelValues(s.name).Inc()
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// run starts workers that apply handle to every queued item
+func (s *stage[T]) run(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	workers int,
+	handle func(context.Context, T),
+) {
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-s.queue:
+					metrics.PipelineQueueDepth.WithLabelValues(s.name).Dec()
+					s.process(ctx, item, handle)
+				}
+			}
+		}()
+	}
+}
+
+func (s *stage[T]) process(ctx context.Context, item T, handle func(context.Context, T)) {
+	// Continue the trace of the block the item came from
+	if traced, ok := any(item).(tracedItem); ok {
+		ctx = tracing.Link(ctx, traced.spanContext())
+	}
+	ctx, span := tracing.Start(ctx, "pipeline."+s.name)
+
+	start := time.Now()
+	defer func() {
+		span.End()
+		metrics.PipelineStageDuration.WithLabelValues(s.name).Observe(time.Since(start).Seconds())
+
+		// A single malformed item must not take down the stage
+		if r := recover(); r != nil {
+			metrics.PipelineItemsTotal.WithLabelValues(s.name, "panic").Inc()
+			s.logger.Error("Recovered from panic in pipeline stage",
+				zap.String("stage", s.name),
+				zap.Any("panic", r))
+
+			// The item is given up on; don't hold its block back forever
+			if held, ok := any(item).(heldItem); ok {
+				held.abandon(ctx)
+			}
+		}
+	}()
+
+	handle(ctx, item)
+}
+
+// buffers reports the occupancy of the stage's queue
+func (s *stage[T]) buffers() BufferState {
+	var state BufferState
+	state.observe(len(s.queue), cap(s.queue))
+	return state
+}
+
+// discard drops whatever is left in the queue once workers have exited
+func (s *stage[T]) discard() {
+	metrics.PipelineQueueDepth.WithLabelValues(s.name).Sub(float64(len(s.queue)))
+}
+
+// tracedItem is a pipeline item carrying the span of the stage that queued it
+type tracedItem interface {
+	spanContext() trace.SpanContext
+}
+
+// blockRef counts the items of a block still in the pipeline. The block is
+// checkpointed once the last of them has been delivered, so a crash
+// before then replays it.
+type blockRef struct {
+	number   uint64
+	pipeline *blockPipeline
+	pending  atomic.Int64
+}
+
+func newBlockRef(bp *blockPipeline, number uint64) *blockRef {
+	ref := &blockRef{number: number, pipeline: bp}
+	ref.pending.Store(1) // Held by the stage creating it
+	return ref
+}
+
+// hold accounts for one more item of the block
+func (r *blockRef) hold() {
+	r.pending.Add(1)
+}
+
+// release accounts for an item having left the pipeline and marks the
+// block done after the last one
+func (r *blockRef) release(ctx context.Context) {
+	if r.pending.Add(-1) == 0 {
+		r.pipeline.done(ctx, r.number)
+	}
+}
+
+// fetchedBlock is a block whose transactions are yet to be matched
+type fetchedBlock struct {
+	block *decodedBlock
+	ref   *blockRef
+	span  trace.SpanContext
+}
+
+// matchedTx is a transaction that involves at least one watched address
+type matchedTx struct {
+	tx        *types.Transaction
+	opaque    *opaqueTx // Set instead of tx if it could not be decoded
+	receipt   *types.Receipt
+	blockTime uint64
+	baseFee   *big.Int // Nil before EIP-1559
+	addresses []common.Address
+	internal  []domain.Transfer // Found by tracing, if enabled
+	position  int               // Index in the block
+	ref       *blockRef
+	span      trace.SpanContext
+}
+
+// decodedTx is a domain transaction with the watched addresses it involves
+type decodedTx struct {
+	tx        domain.Transaction
+	source    *types.Transaction // Nil if it could not be decoded
+	receipt   *types.Receipt
+	addresses []common.Address
+	position  int
+	ref       *blockRef
+	span      trace.SpanContext
+}
+
+// addressedTx is a transaction narrowed down to a single watched address
+type addressedTx struct {
+	address  common.Address
+	tx       domain.Transaction
+	position int
+	ref      *blockRef
+	span     trace.SpanContext
+}
+
+// heldItem is a pipeline item holding its block back from the checkpoint
+// and from being published
+type heldItem interface {
+	// abandon lets the block go on without the item
+	abandon(ctx context.Context)
+}
+
+func (b fetchedBlock) abandon(ctx context.Context) {
+	b.ref.pipeline.reorder.drop(ctx, b.ref.number)
+	b.ref.release(ctx)
+}
+
+func (m matchedTx) abandon(ctx context.Context) {
+	m.ref.pipeline.reorder.add(ctx, m.ref.number, nil)
+	m.ref.release(ctx)
+}
+
+func (d decodedTx) abandon(ctx context.Context) {
+	d.ref.pipeline.reorder.add(ctx, d.ref.number, nil)
+	d.ref.release(ctx)
+}
+
+func (a addressedTx) abandon(ctx context.Context) {
+	a.ref.release(ctx)
+}
+
+func (b fetchedBlock) spanContext() trace.SpanContext { return b.span }
+func (m matchedTx) spanContext() trace.SpanContext    { return m.span }
+func (d decodedTx) spanContext() trace.SpanContext    { return d.span }
+func (a addressedTx) spanContext() trace.SpanContext  { return a.span }
+
+// blockPipeline turns new heads into domain transactions for all watched
+// addresses: fetch -> match -> decode -> enrich -> filter -> publish
+type blockPipeline struct {
+	pc    *PlasmaClient
+	index *addressIndex
+
+	fetch   *stage[*types.Header]
+	match   *stage[fetchedBlock]
+	decode  *stage[matchedTx]
+	enrich  *stage[decodedTx]
+	filter  *stage[decodedTx]
+	publish *stage[addressedTx]
+
+	// Puts filtered transactions back in chain order before publishing
+	reorder *reorderBuffer
+	retries *blockRetries
+
+	// Blocks submitted and not done or scheduled for a retry yet
+	inFlight sync.WaitGroup
+	wg       sync.WaitGroup
+}
+
+func newBlockPipeline(pc *PlasmaClient, index *addressIndex) *blockPipeline {
+	size := pc.cfg.PipelineQueueSize
+	publish := newStage[addressedTx](stagePublish, size, pc.logger)
+
+	return &blockPipeline{
+		pc:      pc,
+		index:   index,
+		fetch:   newStage[*types.Header](stageFetch, size, pc.logger),
+		match:   newStage[fetchedBlock](stageMatch, size, pc.logger),
+		decode:  newStage[matchedTx](stageDecode, size, pc.logger),
+		enrich:  newStage[decodedTx](stageEnrich, size, pc.logger),
+		filter:  newStage[decodedTx](stageFilter, size, pc.logger),
+		publish: publish,
+		reorder: newReorderBuffer(publish),
+		retries: newBlockRetries(pc.cfg.MaxBlockRetries),
+	}
+}
+
+// Start launches all stage workers; they stop when ctx is cancelled
+func (bp *blockPipeline) Start(ctx context.Context) {
+	cfg := bp.pc.cfg
+
+	bp.fetch.run(ctx, &bp.wg, cfg.FetchWorkers, bp.fetchBlock)
+	bp.match.run(ctx, &bp.wg, cfg.MatchWorkers, bp.matchBlock)
+	bp.decode.run(ctx, &bp.wg, cfg.DecodeWorkers, bp.decodeTransaction)
+	bp.enrich.run(ctx, &bp.wg, cfg.EnrichWorkers, bp.enrichTransaction)
+	bp.filter.run(ctx, &bp.wg, 1, bp.filterTransaction)
+	bp.publish.run(ctx, &bp.wg, 1, bp.publishTransaction)
+}
+
+// Submit queues a new head for processing
+func (bp *blockPipeline) Submit(ctx context.Context, header *types.Header) bool {
+	bp.inFlight.Add(1)
+	bp.reorder.enter(header.Number.Uint64())
+	if !bp.fetch.push(ctx, header) {
+		// Blocks after it are published without it
+		bp.reorder.drop(context.WithoutCancel(ctx), header.Number.Uint64())
+		bp.inFlight.Done()
+		return false
+	}
+	return true
+}
+
+// drain waits for the submitted blocks to be delivered, and reports false
+// if timeout passes first. Nothing may be submitted meanwhile.
+func (bp *blockPipeline) drain(timeout time.Duration) bool {
+	drained := make(chan struct{})
+	go func() {
+		bp.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Wait blocks until all workers have exited and releases queued items.
+// Blocks left unfinished aren't checkpointed, so they are replayed after a
+// restart.
+func (bp *blockPipeline) Wait() {
+	bp.wg.Wait()
+
+	bp.fetch.discard()
+	bp.match.discard()
+	bp.decode.discard()
+	bp.enrich.discard()
+	bp.filter.discard()
+	bp.publish.discard()
+}
+
+func (bp *blockPipeline) fetchBlock(ctx context.Context, header *types.Header) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int64("block.number", header.Number.Int64()),
+		attribute.String("block.hash", header.Hash().Hex()),
+	)
+
+	// An empty block has neither transactions nor logs to match
+	if header.TxHash == types.EmptyTxsHash {
+		metrics.PipelineItemsTotal.WithLabelValues(stageFetch, "skipped").Inc()
+		bp.reorder.drop(ctx, header.Number.Uint64())
+		bp.done(ctx, header.Number.Uint64())
+		return
+	}
+
+	if err := bp.pc.deliverContractEvents(ctx, header); err != nil {
+		metrics.PipelineItemsTotal.WithLabelValues(stageFetch, "error").Inc()
+		bp.pc.logger.Error("Failed to get contract events",
+			zap.String("hash", header.Hash().Hex()),
+			zap.Error(err))
+		bp.fail(ctx, stageFetch, header, err)
+		return
+	}
+
+	if err := bp.pc.deliverTokenTransfers(ctx, header); err != nil {
+		metrics.PipelineItemsTotal.WithLabelValues(stageFetch, "error").Inc()
+		bp.pc.logger.Error("Failed to get token transfers",
+			zap.String("hash", header.Hash().Hex()),
+			zap.Error(err))
+		bp.fail(ctx, stageFetch, header, err)
+		return
+	}
+
+	// Nothing to match against, skip the block entirely
+	if bp.index.len() == 0 && !bp.pc.firehose {
+		metrics.PipelineItemsTotal.WithLabelValues(stageFetch, "skipped").Inc()
+		bp.reorder.drop(ctx, header.Number.Uint64())
+		bp.done(ctx, header.Number.Uint64())
+		return
+	}
+
+	block, err := bp.pc.getBlock(ctx, header.Hash())
+	if err != nil {
+		metrics.PipelineItemsTotal.WithLabelValues(stageFetch, "error").Inc()
+		bp.pc.logger.Error("Failed to get block",
+			zap.String("hash", header.Hash().Hex()),
+			zap.Error(err))
+		bp.fail(ctx, stageFetch, header, err)
+		return
+	}
+
+	metrics.PipelineItemsTotal.WithLabelValues(stageFetch, "ok").Inc()
+	bp.match.push(ctx, fetchedBlock{
+		block: block,
+		ref:   newBlockRef(bp, block.NumberU64()),
+		span:  trace.SpanContextFromContext(ctx),
+	})
+}
+
+func (bp *blockPipeline) matchBlock(ctx context.Context, fetched fetchedBlock) {
+	block := fetched.block
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int64("block.number", int64(block.NumberU64())),
+		attribute.Int("block.transactions", len(block.Transactions())),
+	)
+
+	ref := fetched.ref
+	matched, err := bp.matchTransactions(ctx, block, ref)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "error").Inc()
+		bp.pc.logger.Error("Failed to match block",
+			zap.Uint64("block", block.NumberU64()),
+			zap.Error(err))
+		bp.fail(ctx, stageMatch, block.Header(), err)
+		return
+	}
+
+	// The block is done once its matched transactions are delivered
+	bp.reorder.matched(ctx, block.NumberU64(), matched)
+	ref.release(ctx)
+}
+
+// matchTransactions queues every transaction of block that involves a
+// watched address, each holding ref, and returns how many it queued.
+// Nothing is queued if it fails, so the block can be retried as a whole.
+func (bp *blockPipeline) matchTransactions(ctx context.Context, block *decodedBlock, ref *blockRef) (int, error) {
+	// Tracing is best effort; without it only top-level value is seen
+	internal, err := bp.pc.blockInternalTransfers(ctx, block.Block)
+	if err != nil {
+		metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "trace_error").Inc()
+		bp.pc.logger.Warn("Failed to trace internal transfers",
+			zap.Uint64("block", block.NumberU64()),
+			zap.Error(err))
+	}
+
+	// The firehose takes the whole block before matching narrows it down
+	if bp.pc.firehose {
+		if err := bp.pc.deliverFirehose(ctx, block.Block, internal); err != nil {
+			return 0, fmt.Errorf("failed to deliver block transfers: %w", err)
+		}
+	}
+
+	matches, err := bp.pc.matchBlockByLogs(ctx, block, bp.index, internal)
+	if err != nil {
+		metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "log_filter_error").Inc()
+		bp.pc.logger.Warn("Log filter failed, scanning all receipts",
+			zap.Uint64("block", block.NumberU64()),
+			zap.Error(err))
+		return bp.scanReceipts(ctx, block, internal, ref)
+	}
+
+	hashes := make([]common.Hash, 0, len(matches))
+	for _, tx := range block.Transactions() {
+		if _, ok := matches[tx.Hash()]; ok {
+			hashes = append(hashes, tx.Hash())
+		}
+	}
+	for _, otx := range block.opaque {
+		if _, ok := matches[otx.Hash]; ok {
+			hashes = append(hashes, otx.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+
+	// Receipts are only fetched for matched transactions, in a single batch
+	receipts, err := bp.pc.receiptsFor(ctx, hashes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get receipts for matched transactions: %w", err)
+	}
+
+	var items []matchedTx
+	for _, tx := range block.Transactions() {
+		addresses, ok := matches[tx.Hash()]
+		if !ok {
+			continue
+		}
+		items = append(items, matchedTx{
+			tx:        tx,
+			receipt:   receipts[tx.Hash()],
+			blockTime: block.Time(),
+			baseFee:   block.BaseFee(),
+			addresses: addresses,
+			internal:  internal[tx.Hash()],
+			ref:       ref,
+			span:      trace.SpanContextFromContext(ctx),
+		})
+	}
+	for i := range block.opaque {
+		otx := &block.opaque[i]
+		addresses, ok := matches[otx.Hash]
+		if !ok {
+			continue
+		}
+		items = append(items, matchedTx{
+			opaque:    otx,
+			receipt:   receipts[otx.Hash],
+			blockTime: block.Time(),
+			addresses: addresses,
+			internal:  internal[otx.Hash],
+			ref:       ref,
+			span:      trace.SpanContextFromContext(ctx),
+		})
+	}
+
+	return bp.queueMatched(ctx, items)
+}
+
+// scanReceipts matches the block by fetching every receipt, used when the
+// node cannot serve filtered logs. It returns how many transactions it queued.
+func (bp *blockPipeline) scanReceipts(
+	ctx context.Context,
+	block *decodedBlock,
+	internal map[common.Hash][]domain.Transfer,
+	ref *blockRef,
+) (int, error) {
+	if !bp.pc.blockMayInvolve(block, bp.index, internal) {
+		metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "bloom_skipped").Inc()
+		return 0, nil
+	}
+
+	receipts, err := bp.pc.blockReceipts(ctx, block.Block)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block receipts: %w", err)
+	}
+
+	// Only eth_getBlockReceipts covers the transactions left out of the block
+	var missing []common.Hash
+	for _, otx := range block.opaque {
+		if _, ok := receipts[otx.Hash]; !ok {
+			missing = append(missing, otx.Hash)
+		}
+	}
+	if len(missing) > 0 {
+		opaqueReceipts, err := bp.pc.receiptsFor(ctx, missing)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get receipts for undecodable transactions: %w", err)
+		}
+		maps.Copy(receipts, opaqueReceipts)
+	}
+
+	var items []matchedTx
+	for _, tx := range block.Transactions() {
+		receipt, ok := receipts[tx.Hash()]
+		if !ok {
+			return 0, fmt.Errorf("missing receipt for transaction %s", tx.Hash().Hex())
+		}
+
+		// Check if any watched address is involved in the transaction
+		addresses := bp.pc.watchedAddressesIn(bp.pc.directAddressesOf(tx), receipt, internal[tx.Hash()], bp.index)
+		if len(addresses) == 0 {
+			metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "skipped").Inc()
+			continue
+		}
+
+		items = append(items, matchedTx{
+			tx:        tx,
+			receipt:   receipt,
+			blockTime: block.Time(),
+			baseFee:   block.BaseFee(),
+			addresses: addresses,
+			internal:  internal[tx.Hash()],
+			ref:       ref,
+			span:      trace.SpanContextFromContext(ctx),
+		})
+	}
+	for i := range block.opaque {
+		otx := &block.opaque[i]
+		receipt := receipts[otx.Hash]
+
+		addresses := bp.pc.watchedAddressesIn(otx.directAddresses(), receipt, internal[otx.Hash], bp.index)
+		if len(addresses) == 0 {
+			metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "skipped").Inc()
+			continue
+		}
+
+		items = append(items, matchedTx{
+			opaque:    otx,
+			receipt:   receipt,
+			blockTime: block.Time(),
+			addresses: addresses,
+			internal:  internal[otx.Hash],
+			ref:       ref,
+			span:      trace.SpanContextFromContext(ctx),
+		})
+	}
+
+	return bp.queueMatched(ctx, items)
+}
+
+// queueMatched queues the matched transactions of a block in block order,
+// each holding its block, and returns how many it queued
+func (bp *blockPipeline) queueMatched(ctx context.Context, items []matchedTx) (int, error) {
+	for i := range items {
+		items[i].position = int(items[i].receipt.TransactionIndex)
+	}
+	slices.SortFunc(items, func(a, b matchedTx) int {
+		return a.position - b.position
+	})
+
+	for i, item := range items {
+		metrics.PipelineItemsTotal.WithLabelValues(stageMatch, "ok").Inc()
+		item.ref.hold()
+		if !bp.decode.push(ctx, item) {
+			return i, ctx.Err()
+		}
+	}
+	return len(items), nil
+}
+
+func (bp *blockPipeline) decodeTransaction(ctx context.Context, matched matchedTx) {
+	var domainTx domain.Transaction
+	if matched.opaque != nil {
+		domainTx = bp.pc.createOpaqueTransaction(matched.opaque, matched.receipt, matched.blockTime)
+	} else {
+		domainTx = bp.pc.createDomainTransaction(matched.tx, matched.receipt, matched.blockTime, matched.baseFee)
+	}
+	if domainTx.Status == domain.TxSuccess {
+		domainTx.Transfers = append(domainTx.Transfers, matched.internal...)
+	}
+
+	metrics.PipelineItemsTotal.WithLabelValues(stageDecode, "ok").Inc()
+	bp.enrich.push(ctx, decodedTx{
+		tx:        domainTx,
+		source:    matched.tx,
+		receipt:   matched.receipt,
+		addresses: matched.addresses,
+		position:  matched.position,
+		ref:       matched.ref,
+		span:      trace.SpanContextFromContext(ctx),
+	})
+}
+
+func (bp *blockPipeline) enrichTransaction(ctx context.Context, decoded decodedTx) {
+	bp.pc.enrichTransaction(ctx, &decoded.tx, decoded.source, decoded.receipt)
+	bp.pc.sinkTransaction(ctx, decoded.tx, decoded.receipt.BlockHash)
+
+	metrics.PipelineItemsTotal.WithLabelValues(stageEnrich, "ok").Inc()
+	decoded.span = trace.SpanContextFromContext(ctx)
+	bp.filter.push(ctx, decoded)
+}
+
+func (bp *blockPipeline) filterTransaction(ctx context.Context, decoded decodedTx) {
+	var items []addressedTx
+	for _, address := range decoded.addresses {
+		// Transfers stay whole; the tracker picks the address's own
+		relevantTransfers := bp.pc.filterTransfersForAddress(decoded.tx.Transfers, address)
+
+		relevantApprovals := filterApprovalsForOwner(decoded.tx.Approvals, address)
+
+		// Reverted txs move nothing, but their sender wants to know
+		sentAndReverted := decoded.tx.Status == domain.TxReverted &&
+			common.HexToAddress(string(decoded.tx.From)) == address
+		if len(relevantTransfers) == 0 && len(relevantApprovals) == 0 && !sentAndReverted {
+			metrics.PipelineItemsTotal.WithLabelValues(stageFilter, "skipped").Inc()
+			continue
+		}
+
+		tx := decoded.tx
+		tx.Approvals = relevantApprovals
+		tx.Swaps = filterSwapsFor(decoded.tx, address)
+		tx.Bridges = filterBridgeTransfersFor(decoded.tx, address)
+
+		metrics.PipelineItemsTotal.WithLabelValues(stageFilter, "ok").Inc()
+		decoded.ref.hold()
+		items = append(items, addressedTx{
+			address:  address,
+			tx:       tx,
+			position: decoded.position,
+			ref:      decoded.ref,
+			span:     trace.SpanContextFromContext(ctx),
+		})
+	}
+
+	// Published once every transaction before it is
+	bp.reorder.add(ctx, decoded.ref.number, items)
+	decoded.ref.release(ctx)
+}
+
+func (bp *blockPipeline) publishTransaction(ctx context.Context, item addressedTx) {
+	// Subscribers continue the trace up to the notification
+	item.tx.Trace = tracing.Inject(ctx)
+
+	// Consumers that fall behind hold up the pipeline rather than miss it
+	delivered, ok := bp.index.deliver(ctx, item.address, item.tx)
+	if !ok {
+		// Not checkpointed, so the block is replayed after a restart
+		return
+	}
+
+	if delivered > 0 {
+		metrics.PipelineItemsTotal.WithLabelValues(stagePublish, "ok").Add(float64(delivered))
+		bp.pc.logger.Info("Detected transaction with transfers",
+			zap.String("tx_hash", string(item.tx.Hash)),
+			zap.String("type", string(item.tx.Type)),
+			zap.Int("transfers", len(item.tx.Transfers)),
+			zap.String("address", item.address.Hex()))
+	}
+	item.ref.release(ctx)
+}
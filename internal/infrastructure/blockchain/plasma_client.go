@@ -2,182 +2,560 @@ package blockchain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/say8hi/plasma-wallet-tracker/config"
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
 	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go.uber.org/zap"
 )
 
 type PlasmaClient struct {
-	rpcClient  *ethclient.Client
-	wsClient   *ethclient.Client
-	chainID    *big.Int
-	logger     *zap.Logger
-	tokenCache map[common.Address]string
-	mu         sync.RWMutex
+	cfg         config.BlockchainConfig
+	pool        *rpcPool
+	breaker     *circuitBreaker
+	traceClient *rpc.Client // Dedicated trace node, nil to trace through the pool
+	wsClient    *ethclient.Client
+	wsURL       int // Index into cfg.WSURLs of the current WebSocket endpoint
+	wsMu        sync.Mutex
+	chainID     *big.Int
+	signer      types.Signer
+	index       *addressIndex
+	contracts   *contractIndex
+	tracked     *tokenIndex // Tokens whose every transfer is followed
+	pipeline    *blockPipeline
+	checkpoint  *checkpointer
+	reorgs      *reorgDetector
+	heads       *broadcaster[uint64]
+	tokens      *TokenRegistry
+	blocks      *lruCache[common.Hash, *decodedBlock]  // By block hash
+	receipts    *lruCache[common.Hash, *types.Receipt] // By tx hash, purged on reorgs
+	pools       *poolTokens
+	bridges     map[common.Address]bool // Contracts whose bridge events are trusted
+	paymasters  map[common.Address]bool // Relayers that sponsor gas for their users
+	multicall   *Multicall              // Nil if disabled
+	finality    *big.Int                // Block tag heads are processed at, nil for latest
+	sinks       []transferSink          // Receive extracted transfers
+	firehose    bool                    // Some sink gets every transfer, not just matched ones
+	logger      *zap.Logger
+
+	// How long blocks in the pipeline may take to finish on shutdown
+	drainTimeout time.Duration
+
+	// Set once the RPC node rejects eth_getBlockReceipts
+	noBlockReceipts atomic.Bool
+
+	// Highest block handed to the pipeline, only used by the head follower
+	lastSubmitted uint64
+	// Latest head received, for the block lag metric
+	head atomic.Uint64
 }
 
-func NewPlasmaClient(cfg config.BlockchainConfig) (*PlasmaClient, error) {
-	// Initialize RPC client
-	rpcClient, err := ethclient.Dial(cfg.RPCURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
+// Drain timeout used when none is configured
+const defaultDrainTimeout = 10 * time.Second
+
+// Option configures optional PlasmaClient dependencies
+type Option func(*PlasmaClient)
+
+// WithCheckpointStore persists block progress so missed blocks are replayed
+// after downtime
+func WithCheckpointStore(store domain.CheckpointStore) Option {
+	return func(pc *PlasmaClient) {
+		pc.checkpoint.store = store
 	}
+}
 
-	// Initialize WebSocket client
-	wsClient, err := ethclient.Dial(cfg.WSURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
+// WithTokenMetadataStore persists resolved token metadata across restarts
+func WithTokenMetadataStore(store domain.TokenMetadataStore) Option {
+	return func(pc *PlasmaClient) {
+		pc.tokens.store = store
 	}
+}
 
+// WithDrainTimeout bounds how long Start waits on shutdown for blocks in
+// the pipeline to be delivered to the wallet listeners
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(pc *PlasmaClient) {
+		pc.drainTimeout = timeout
+	}
+}
+
+func NewPlasmaClient(cfg config.BlockchainConfig, opts ...Option) (*PlasmaClient, error) {
 	// Initialize logger
 	logger, _ := zap.NewProduction()
 
-	return &PlasmaClient{
-		rpcClient:  rpcClient,
-		wsClient:   wsClient,
-		chainID:    big.NewInt(cfg.ChainID),
-		logger:     logger,
-		tokenCache: make(map[common.Address]string),
-	}, nil
+	// Initialize RPC endpoints
+	pool, err := newRPCPool(context.Background(), cfg.RPCURLs, cfg.RPCLoadBalance, rpcLimits{
+		rate:        cfg.RPCRateLimit,
+		burst:       cfg.RPCRateBurst,
+		concurrency: cfg.RPCMaxConcurrency,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := &PlasmaClient{
+		cfg:       cfg,
+		pool:      pool,
+		chainID:   big.NewInt(cfg.ChainID),
+		signer:    types.LatestSignerForChainID(big.NewInt(cfg.ChainID)),
+		index:     newAddressIndex(),
+		contracts: newContractIndex(),
+		tracked:   newTokenIndex(),
+		logger:    logger,
+	}
+	pc.breaker = newCircuitBreaker(cfg.RPCBreakerThreshold, cfg.RPCBreakerCooldown, logger)
+	pc.tokens = newTokenRegistry(pc, cfg.TokenCacheSize, logger)
+	pc.pools = newPoolTokens(cfg.TokenCacheSize)
+	pc.blocks = newLRUCache[common.Hash, *decodedBlock]("blocks", cfg.BlockCacheSize)
+	pc.receipts = newLRUCache[common.Hash, *types.Receipt]("receipts", cfg.ReceiptCacheSize)
+	pc.bridges = make(map[common.Address]bool, len(cfg.BridgeContracts))
+	for _, bridge := range cfg.BridgeContracts {
+		if !common.IsHexAddress(bridge) {
+			pool.close()
+			return nil, fmt.Errorf("invalid bridge contract address %q", bridge)
+		}
+		pc.bridges[common.HexToAddress(bridge)] = true
+	}
+	pc.paymasters = make(map[common.Address]bool, len(cfg.PaymasterAddresses))
+	for _, paymaster := range cfg.PaymasterAddresses {
+		if !common.IsHexAddress(paymaster) {
+			pool.close()
+			return nil, fmt.Errorf("invalid paymaster address %q", paymaster)
+		}
+		pc.paymasters[common.HexToAddress(paymaster)] = true
+	}
+	pc.checkpoint = newCheckpointer(nil, logger)
+	pc.reorgs = newReorgDetector(cfg.ReorgDepth)
+	pc.heads = newBroadcaster[uint64](16)
+	pc.pipeline = newBlockPipeline(pc, pc.index)
+
+	if cfg.MulticallAddress != "" {
+		if pc.multicall, err = newMulticall(pc, cfg.MulticallAddress, cfg.MulticallBatchSize); err != nil {
+			pool.close()
+			return nil, err
+		}
+	}
+
+	if pc.finality, err = finalityTag(cfg.Finality); err != nil {
+		pool.close()
+		return nil, err
+	}
+
+	switch cfg.TraceMode {
+	case "", traceModeDebug, traceModeParity:
+	default:
+		pool.close()
+		return nil, fmt.Errorf("unknown trace mode %q", cfg.TraceMode)
+	}
+	if cfg.TraceMode != "" && cfg.TraceURL != "" {
+		if pc.traceClient, err = dialTraceClient(context.Background(), cfg.TraceURL); err != nil {
+			pool.close()
+			return nil, err
+		}
+	}
+
+	// Initialize WebSocket client, polling over HTTP without one
+	if len(cfg.WSURLs) == 0 {
+		logger.Info("No WebSocket endpoint configured, polling for new blocks",
+			zap.Duration("interval", cfg.PollInterval))
+	} else if err := pc.reconnectWS(context.Background()); err != nil {
+		logger.Warn("WebSocket unavailable, polling for new blocks",
+			zap.Duration("interval", cfg.PollInterval),
+			zap.Error(err))
+	}
+
+	for _, opt := range opts {
+		opt(pc)
+	}
+	if pc.drainTimeout <= 0 {
+		pc.drainTimeout = defaultDrainTimeout
+	}
+
+	return pc, nil
 }
 
-func (pc *PlasmaClient) SubscribeToAddress(
-	ctx context.Context,
-	address domain.WalletAddress,
-) (<-chan domain.Transaction, error) {
-	txChan := make(chan domain.Transaction, 100)
-	walletAddr := common.HexToAddress(string(address))
+// Start follows new heads with a single shared subscription and runs every
+// block through the processing pipeline until ctx is cancelled. Dropped
+// WebSocket connections are re-established with exponential backoff; while
+// no WebSocket is available new blocks are polled over HTTP. Once ctx is
+// cancelled no more blocks are submitted, and Start returns after those in
+// the pipeline are delivered, or the drain timeout passes.
+func (pc *PlasmaClient) Start(ctx context.Context) error {
+	pipelineCtx, stopPipeline := context.WithCancel(context.WithoutCancel(ctx))
+	defer func() {
+		if !pc.pipeline.drain(pc.drainTimeout) {
+			pc.logger.Warn("Blocks still in the pipeline at shutdown, they are replayed on the next start",
+				zap.Duration("timeout", pc.drainTimeout))
+		}
+		stopPipeline()
+		pc.pipeline.Wait()
+	}()
+	pc.pipeline.Start(pipelineCtx)
+	go pc.pool.run(ctx, pc.cfg.RPCHealthCheckInterval)
+
+	// Resume right after the last checkpointed block
+	if last, ok := pc.checkpoint.load(ctx); ok {
+		pc.lastSubmitted = last
+		pc.logger.Info("Resuming from block checkpoint", zap.Uint64("block", last))
+	}
+
+	retry := backoff.New(pc.cfg.ReconnectMinBackoff, pc.cfg.ReconnectMaxBackoff)
+	for {
+		var err error
+		if pc.wsAvailable() {
+			err = pc.followHeads(ctx, retry)
+		} else {
+			err = pc.pollHeads(ctx)
+		}
+		if ctx.Err() != nil {
+			pc.logger.Info("Stopped following new heads")
+			return nil
+		}
+		if err == nil {
+			continue // Polling ended because the WebSocket is back
+		}
+
+		delay := retry.Next()
+		pc.logger.Warn("Head subscription lost, reconnecting",
+			zap.Int("attempt", retry.Attempt()),
+			zap.Duration("backoff", delay),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		if err := pc.reconnectWS(ctx); err != nil {
+			pc.logger.Error("Failed to reconnect WebSocket, polling for new blocks", zap.Error(err))
+			pc.dropWS()
+		}
+	}
+}
+
+// followHeads subscribes to new heads and feeds them to the pipeline until
+// the subscription fails or ctx is cancelled
+func (pc *PlasmaClient) followHeads(ctx context.Context, retry *backoff.Backoff) error {
+	pc.wsMu.Lock()
+	wsClient := pc.wsClient
+	pc.wsMu.Unlock()
 
-	// Subscribe to new heads to get new blocks
 	headers := make(chan *types.Header)
-	sub, err := pc.wsClient.SubscribeNewHead(ctx, headers)
+	sub, err := wsClient.SubscribeNewHead(ctx, headers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe to new heads: %w", err)
+		return fmt.Errorf("failed to subscribe to new heads: %w", err)
 	}
+	defer sub.Unsubscribe()
+
+	// Watches live in the shared index, so they resume with the new subscription
+	if retry.Attempt() > 0 {
+		pc.logger.Info("Resubscribed to new heads",
+			zap.Int("watched_addresses", pc.index.len()))
+	} else {
+		pc.logger.Info("Started following new heads")
+	}
+	retry.Reset()
 
-	go func() {
-		defer close(txChan)
-		defer sub.Unsubscribe()
+	// Replay anything missed while disconnected without waiting for a new head
+	if latest, err := pc.headerByNumber(ctx, pc.finality); err == nil {
+		pc.submitHead(ctx, latest)
+	} else {
+		pc.logger.Warn("Failed to fetch latest header for catch-up", zap.Error(err))
+	}
 
-		pc.logger.Info("Started monitoring wallet",
-			zap.String("address", string(address)))
+	// A subscription can go quiet without failing; the watchdog replaces it
+	// and the reconnect catches up from the checkpoint
+	var stale <-chan time.Time
+	watchdog := pc.newHeadWatchdog()
+	if watchdog != nil {
+		defer watchdog.Stop()
+		stale = watchdog.C
+	}
+	lastHead, lastNumber := time.Now(), pc.lastSubmitted
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return fmt.Errorf("head subscription failed: %w", err)
+		case header := <-headers:
+			lastHead, lastNumber = time.Now(), header.Number.Uint64()
+			if watchdog != nil {
+				watchdog.Reset(pc.cfg.StaleHeadTimeout)
+			}
 
-		for {
-			select {
-			case <-ctx.Done():
-				pc.logger.Info("Stopped monitoring wallet",
-					zap.String("address", string(address)))
-				return
-			case err := <-sub.Err():
-				pc.logger.Error("Subscription error",
-					zap.String("address", string(address)),
-					zap.Error(err))
-				return
-			case header := <-headers:
-				// Check transactions in the new block
-				pc.processBlockForAddress(ctx, header, walletAddr, txChan)
+			// Hand the new block, or the newest one final enough, to the
+			// processing pipeline
+			if pc.finality != nil {
+				final, err := pc.headerByNumber(ctx, pc.finality)
+				if err != nil {
+					pc.logger.Warn("Failed to fetch header at finality tag",
+						zap.String("finality", pc.cfg.Finality),
+						zap.Error(err))
+					break
+				}
+				header = final
 			}
+			pc.submitHead(ctx, header)
+		case <-stale:
+			if err := pc.checkStaleHeads(ctx, lastHead, lastNumber); err != nil {
+				return err
+			}
+			watchdog.Reset(pc.cfg.StaleHeadTimeout)
 		}
-	}()
+	}
+}
+
+// submitHead hands header to the pipeline, first replaying any blocks
+// between the last submitted block and header
+func (pc *PlasmaClient) submitHead(ctx context.Context, header *types.Header) {
+	number := header.Number.Uint64()
+	pc.head.Store(number)
+	metrics.HeadBlock.Set(float64(number))
+	pc.observeBlockLag()
+
+	if pc.lastSubmitted == 0 {
+		pc.checkpoint.start(number)
+		pc.lastSubmitted = number - 1
+	}
+	if ancestor, chain, reorged := pc.checkReorg(ctx, header); reorged {
+		pc.handleReorg(ctx, header, ancestor, chain)
+	}
+	if number <= pc.lastSubmitted {
+		return // Already processed
+	}
+
+	// Blocks that failed transiently get another chance with each new head
+	pc.pipeline.resubmitFailed(ctx)
+
+	from := pc.lastSubmitted + 1
+	if missed := number - from; missed > 0 {
+		if limit := uint64(pc.cfg.MaxCatchUpBlocks); missed > limit {
+			pc.logger.Warn("Too many missed blocks, skipping the oldest",
+				zap.Uint64("missed", missed),
+				zap.Uint64("replayed", limit))
+			from = number - limit
+			pc.checkpoint.skipTo(from)
+		}
+
+		pc.logger.Info("Catching up on missed blocks",
+			zap.Uint64("from", from),
+			zap.Uint64("to", number-1))
+	}
+
+	for n := from; n < number; n++ {
+		missed, err := pc.headerByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			// Leave the rest for the next head
+			pc.logger.Error("Failed to fetch missed block header",
+				zap.Uint64("block", n),
+				zap.Error(err))
+			return
+		}
+		if !pc.pipeline.Submit(ctx, missed) {
+			return
+		}
+		pc.reorgs.record(missed)
+		pc.lastSubmitted = n
+	}
 
-	return txChan, nil
+	if pc.pipeline.Submit(ctx, header) {
+		pc.reorgs.record(header)
+		pc.lastSubmitted = number
+		pc.heads.broadcast(number)
+	}
 }
 
-func (pc *PlasmaClient) processBlockForAddress(
-	ctx context.Context,
-	header *types.Header,
-	address common.Address,
-	txChan chan<- domain.Transaction,
-) {
-	block, err := pc.rpcClient.BlockByHash(ctx, header.Hash())
-	if err != nil {
-		pc.logger.Error("Failed to get block",
-			zap.String("hash", header.Hash().Hex()),
-			zap.Error(err))
-		return
+// observeBlockLag updates the processed block and block lag metrics from
+// the latest head and the checkpoint
+func (pc *PlasmaClient) observeBlockLag() {
+	if last, known := pc.checkpoint.lastProcessed(); known {
+		metrics.ProcessedBlock.Set(float64(last))
+	}
+	if lag, known := pc.BlockLag(); known {
+		metrics.BlockLag.Set(float64(lag))
 	}
+}
 
-	// Check each transaction in the block
-	for _, tx := range block.Transactions() {
-		// Get receipt to analyze logs
-		receipt, err := pc.rpcClient.TransactionReceipt(ctx, tx.Hash())
+// BlockLag returns how many blocks the last processed block trails the
+// latest head, if both are known
+func (pc *PlasmaClient) BlockLag() (uint64, bool) {
+	head := pc.head.Load()
+	last, known := pc.checkpoint.lastProcessed()
+	if !known || head == 0 {
+		return 0, false
+	}
+	if head < last {
+		return 0, true
+	}
+	return head - last, true
+}
+
+// SubscribeHeads returns a channel of new head block numbers, closed once
+// ctx is cancelled
+func (pc *PlasmaClient) SubscribeHeads(ctx context.Context) (<-chan uint64, error) {
+	return pc.heads.subscribe(ctx), nil
+}
+
+// reconnectWS replaces the WebSocket client with a freshly dialed one. The
+// endpoint that just failed is tried last.
+func (pc *PlasmaClient) reconnectWS(ctx context.Context) error {
+	pc.wsMu.Lock()
+	defer pc.wsMu.Unlock()
+
+	urls := pc.cfg.WSURLs
+	if len(urls) == 0 {
+		return errors.New("no WebSocket endpoints configured")
+	}
+
+	// Only rotate away from an endpoint we were connected to
+	start := pc.wsURL
+	if pc.wsClient != nil {
+		start++
+	}
+
+	var errs []error
+	for i := range urls {
+		n := (start + i) % len(urls)
+
+		wsClient, err := ethclient.DialContext(ctx, urls[n])
 		if err != nil {
-			continue // Skip if we can't get receipt
-		}
-
-		// Check if our address is involved in the transaction
-		if pc.isAddressInvolved(tx, receipt, address) {
-			// Extract all transfers and create domain transaction
-			domainTx := pc.createDomainTransaction(tx, receipt, block.Time())
-
-			// Filter transfers for the watched address
-			relevantTransfers := pc.filterTransfersForAddress(domainTx.Transfers, address)
-
-			if len(relevantTransfers) > 0 {
-				domainTx.Transfers = relevantTransfers
-
-				select {
-				case txChan <- domainTx:
-					pc.logger.Info("Detected transaction with transfers",
-						zap.String("tx_hash", tx.Hash().Hex()),
-						zap.Int("transfers", len(relevantTransfers)),
-						zap.String("address", address.Hex()))
-				case <-ctx.Done():
-					return
-				default:
-					pc.logger.Warn("Channel full, dropping transaction",
-						zap.String("hash", tx.Hash().Hex()))
-				}
-			}
+			errs = append(errs, fmt.Errorf("%s: %w", urls[n], err))
+			continue
+		}
+
+		if pc.wsClient != nil {
+			pc.wsClient.Close()
 		}
+		pc.wsClient = wsClient
+		pc.wsURL = n
+
+		if len(urls) > 1 {
+			pc.logger.Info("Connected to WebSocket endpoint", zap.String("endpoint", urls[n]))
+		}
+		return nil
 	}
+
+	return errors.Join(errs...)
 }
 
-func (pc *PlasmaClient) isAddressInvolved(
-	tx *types.Transaction,
+// SubscribeToAddress registers address in the shared watch index. The
+// returned channel is closed once ctx is cancelled.
+func (pc *PlasmaClient) SubscribeToAddress(
+	ctx context.Context,
+	address domain.WalletAddress,
+) (<-chan domain.Transaction, error) {
+	watch := newAddressWatch(common.HexToAddress(string(address)), 100)
+	pc.index.add(watch)
+
+	pc.logger.Info("Started monitoring wallet",
+		zap.String("address", string(address)))
+
+	go func() {
+		<-ctx.Done()
+		pc.index.remove(watch)
+
+		pc.logger.Info("Stopped monitoring wallet",
+			zap.String("address", string(address)))
+	}()
+
+	return watch.ch, nil
+}
+
+// directAddressesOf returns the sender, if it can be recovered, and the
+// recipient of tx
+func (pc *PlasmaClient) directAddressesOf(tx *types.Transaction) []common.Address {
+	var addresses []common.Address
+	if from, err := pc.senderOf(tx); err == nil {
+		addresses = append(addresses, from)
+	}
+	if tx.To() != nil {
+		addresses = append(addresses, *tx.To())
+	}
+	return addresses
+}
+
+// watchedAddressesIn returns every watched address involved in a
+// transaction, either directly (from/to) or through a Transfer event
+func (pc *PlasmaClient) watchedAddressesIn(
+	direct []common.Address,
 	receipt *types.Receipt,
-	address common.Address,
-) bool {
-	// 1. Check direct involvement (from/to)
-	if from, err := types.Sender(types.NewEIP155Signer(pc.chainID), tx); err == nil &&
-		from == address {
-		return true
+	internal []domain.Transfer,
+	index *addressIndex,
+) []common.Address {
+	var addresses []common.Address
+	seen := make(map[common.Address]bool)
+
+	check := func(address common.Address) {
+		if !seen[address] {
+			seen[address] = true
+			if index.contains(address) {
+				addresses = append(addresses, address)
+			}
+		}
 	}
-	if tx.To() != nil && *tx.To() == address {
-		return true
+
+	// 1. Check direct involvement (from/to)
+	for _, address := range direct {
+		check(address)
 	}
 
 	// 2. Check involvement in Transfer events
 	for _, log := range receipt.Logs {
 		if len(log.Topics) >= 3 && log.Topics[0] == transferEventSignature {
-			from := common.HexToAddress(log.Topics[1].Hex())
-			to := common.HexToAddress(log.Topics[2].Hex())
+			check(common.HexToAddress(log.Topics[1].Hex()))
+			check(common.HexToAddress(log.Topics[2].Hex()))
+		}
+	}
 
-			if from == address || to == address {
-				return true
-			}
+	// 3. Check approvals granted by a watched owner
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 3 && log.Topics[0] == approvalEventSignature {
+			check(common.BytesToAddress(log.Topics[1].Bytes()))
 		}
 	}
 
-	return false
+	// 4. Check involvement in internal transfers
+	for _, transfer := range internal {
+		check(common.HexToAddress(string(transfer.From)))
+		check(common.HexToAddress(string(transfer.To)))
+	}
+
+	return addresses
 }
 
 func (pc *PlasmaClient) createDomainTransaction(
 	tx *types.Transaction,
 	receipt *types.Receipt,
 	blockTime uint64,
+	baseFee *big.Int,
 ) domain.Transaction {
-	// Get sender address
-	fromAddr, _ := types.Sender(types.NewEIP155Signer(pc.chainID), tx)
+	// Get sender address; left empty rather than reported as the zero
+	// address if it can't be recovered
+	from := ""
+	fromAddr, err := pc.senderOf(tx)
+	if err != nil {
+		pc.logger.Warn("Failed to recover transaction sender",
+			zap.String("tx_hash", tx.Hash().Hex()),
+			zap.Uint8("type", tx.Type()),
+			zap.Error(err))
+	} else {
+		from = fromAddr.Hex()
+	}
 
 	// Get recipient address
 	toAddr := ""
@@ -186,66 +564,99 @@ func (pc *PlasmaClient) createDomainTransaction(
 	}
 
 	// Extract all transfers
-	transfers := pc.extractAllTransfers(tx, receipt)
+	transfers := pc.extractAllTransfers(tx.Hash(), tx.To(), tx.Value(), receipt, domain.WalletAddress(from))
+
+	sponsor := pc.sponsorshipOf(tx.Data(), receipt, domain.WalletAddress(from))
+	pricePaid := effectiveGasPrice(tx, receipt, baseFee)
 
 	return domain.Transaction{
-		Hash:        domain.TransactionHash(tx.Hash().Hex()),
-		From:        domain.WalletAddress(fromAddr.Hex()),
-		To:          domain.WalletAddress(toAddr),
-		BlockNumber: receipt.BlockNumber.Uint64(),
-		Timestamp:   time.Unix(int64(blockTime), 0),
-		GasUsed:     receipt.GasUsed,
-		GasPrice:    tx.GasPrice(),
-		Transfers:   transfers,
+		Hash:         domain.TransactionHash(tx.Hash().Hex()),
+		Type:         txTypeOf(tx),
+		RawType:      tx.Type(),
+		Kind:         txKindOf(tx.To(), tx.Data()),
+		Status:       txStatusOf(receipt),
+		From:         domain.WalletAddress(from),
+		To:           domain.WalletAddress(toAddr),
+		Contract:     createdContract(tx.To(), receipt),
+		Method:       pc.methodOf(tx.Hash(), tx.To(), tx.Data()),
+		Nonce:        tx.Nonce(),
+		BlockNumber:  receipt.BlockNumber.Uint64(),
+		Timestamp:    time.Unix(int64(blockTime), 0),
+		GasUsed:      receipt.GasUsed,
+		GasPrice:     tx.GasPrice(),
+		GasPricePaid: pricePaid,
+		Fee:          new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), pricePaid),
+		Gasless:      sponsor.gasless,
+		Paymaster:    sponsor.paymaster,
+		FeePayer:     sponsor.feePayer,
+		Transfers:    transfers,
+		Approvals:    extractApprovals(tx.Hash(), receipt),
 	}
 }
 
+// extractAllTransfers returns the native transfer of a transaction sending
+// value to, followed by the token transfers its receipt logs
 func (pc *PlasmaClient) extractAllTransfers(
-	tx *types.Transaction,
+	txHash common.Hash,
+	to *common.Address,
+	value *big.Int,
 	receipt *types.Receipt,
+	from domain.WalletAddress,
 ) []domain.Transfer {
 	var transfers []domain.Transfer
 
-	// 1. Native transfer (if value > 0)
-	if tx.Value().Cmp(big.NewInt(0)) > 0 {
-		fromAddr, _ := types.Sender(types.NewEIP155Signer(pc.chainID), tx)
+	// 1. Native transfer (if value > 0); a reverted tx moves no value
+	if value.Cmp(big.NewInt(0)) > 0 && receipt.Status == types.ReceiptStatusSuccessful {
 		toAddr := ""
-		if tx.To() != nil {
-			toAddr = tx.To().Hex()
+		if to != nil {
+			toAddr = to.Hex()
 		}
 
 		transfer := domain.Transfer{
-			TxHash:       domain.TransactionHash(tx.Hash().Hex()),
-			From:         domain.WalletAddress(fromAddr.Hex()),
-			To:           domain.WalletAddress(toAddr),
-			Value:        tx.Value(),
-			TokenSymbol:  "XPL",
-			TokenAddress: "0x0000000000000000000000000000000000000000",
-			LogIndex:     -1, // Native transfer doesn't have log index
+			TxHash:        domain.TransactionHash(txHash.Hex()),
+			From:          from,
+			To:            domain.WalletAddress(toAddr),
+			Value:         value,
+			TokenSymbol:   "XPL",
+			TokenAddress:  "0x0000000000000000000000000000000000000000",
+			TokenStandard: domain.NativeToken,
+			LogIndex:      -1, // Native transfer doesn't have log index
 		}
 		transfers = append(transfers, transfer)
 	}
 
-	// 2. ERC-20 transfers from logs
-	for i, log := range receipt.Logs {
-		if len(log.Topics) >= 3 && log.Topics[0] == transferEventSignature {
-			from := common.HexToAddress(log.Topics[1].Hex())
-			to := common.HexToAddress(log.Topics[2].Hex())
-			value := new(big.Int).SetBytes(log.Data)
-
-			tokenSymbol := pc.getTokenSymbol(context.Background(), log.Address)
-
-			transfer := domain.Transfer{
-				TxHash:       domain.TransactionHash(tx.Hash().Hex()),
-				From:         domain.WalletAddress(from.Hex()),
-				To:           domain.WalletAddress(to.Hex()),
-				Value:        value,
-				TokenSymbol:  tokenSymbol,
-				TokenAddress: log.Address.Hex(),
-				LogIndex:     i,
-			}
-			transfers = append(transfers, transfer)
+	// 2. Token transfers from logs. ERC-20 and ERC-721 share the Transfer
+	// signature; ERC-721 also indexes the token ID, giving it a fourth topic.
+	for _, log := range receipt.Logs {
+		if len(log.Topics) < 3 || log.Topics[0] != transferEventSignature {
+			continue
 		}
+
+		from := common.HexToAddress(log.Topics[1].Hex())
+		to := common.HexToAddress(log.Topics[2].Hex())
+
+		// Token symbol is resolved separately by enrichTransfers
+		transfer := domain.Transfer{
+			TxHash:       domain.TransactionHash(txHash.Hex()),
+			From:         domain.WalletAddress(from.Hex()),
+			To:           domain.WalletAddress(to.Hex()),
+			TokenAddress: log.Address.Hex(),
+			LogIndex:     int(log.Index),
+		}
+
+		switch len(log.Topics) {
+		case 3:
+			transfer.TokenStandard = domain.ERC20Token
+			transfer.Value = new(big.Int).SetBytes(log.Data)
+		case 4:
+			transfer.TokenStandard = domain.ERC721Token
+			transfer.TokenID = new(big.Int).SetBytes(log.Topics[3].Bytes())
+			transfer.Value = big.NewInt(1)
+		default:
+			continue
+		}
+
+		transfers = append(transfers, transfer)
 	}
 
 	return transfers
@@ -269,47 +680,23 @@ func (pc *PlasmaClient) filterTransfersForAddress(
 	return relevantTransfers
 }
 
-func (pc *PlasmaClient) getTokenSymbol(ctx context.Context, tokenAddress common.Address) string {
-	pc.mu.RLock()
-	if symbol, exists := pc.tokenCache[tokenAddress]; exists {
-		pc.mu.RUnlock()
-		return symbol
-	}
-	pc.mu.RUnlock()
-
-	// Special cases for known tokens
-	switch tokenAddress.Hex() {
-	case "0x0000000000000000000000000000000000000000":
-		return "XPL"
-	case "0xa0b86a33e6ba0c74d75c9abfd35e5e0b1bcceb83": // Example WXPL
-		return "WXPL"
-	}
-
-	// Try to get symbol via ERC-20
-	helper, err := NewERC20Helper(pc)
-	if err != nil {
-		return tokenAddress.Hex()[:8]
-	}
-
-	symbol, err := helper.GetTokenSymbol(ctx, tokenAddress)
-	if err != nil {
-		symbol = tokenAddress.Hex()[:8]
-	}
-
-	// Cache the result
-	pc.mu.Lock()
-	pc.tokenCache[tokenAddress] = symbol
-	pc.mu.Unlock()
-
-	return symbol
+// TokenRegistry returns the token metadata registry backing transfer enrichment
+func (pc *PlasmaClient) TokenRegistry() *TokenRegistry {
+	return pc.tokens
 }
 
 func (pc *PlasmaClient) GetLatestBlock(ctx context.Context) (uint64, error) {
-	block, err := pc.rpcClient.BlockByNumber(ctx, nil)
+	number, err := withRetry(ctx, pc, "block_number", func(ctx context.Context, c *ethclient.Client) (uint64, error) {
+		return c.BlockNumber(ctx)
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get latest block: %w", err)
 	}
-	return block.NumberU64(), nil
+	return number, nil
+}
+
+func (pc *PlasmaClient) LastProcessedBlock() (uint64, bool) {
+	return pc.checkpoint.lastProcessed()
 }
 
 func (pc *PlasmaClient) GetTransaction(
@@ -318,7 +705,12 @@ func (pc *PlasmaClient) GetTransaction(
 ) (*domain.Transaction, error) {
 	txHash := common.HexToHash(string(hash))
 
-	tx, isPending, err := pc.rpcClient.TransactionByHash(ctx, txHash)
+	var isPending bool
+	tx, err := withRetry(ctx, pc, "transaction", func(ctx context.Context, c *ethclient.Client) (*types.Transaction, error) {
+		tx, pending, err := c.TransactionByHash(ctx, txHash)
+		isPending = pending
+		return tx, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
@@ -327,17 +719,33 @@ func (pc *PlasmaClient) GetTransaction(
 		return nil, fmt.Errorf("transaction is pending")
 	}
 
-	receipt, err := pc.rpcClient.TransactionReceipt(ctx, txHash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+	receipt, cached := pc.receipts.get(txHash)
+	if !cached {
+		receipt, err = withRetry(ctx, pc, "receipt", func(ctx context.Context, c *ethclient.Client) (*types.Receipt, error) {
+			return c.TransactionReceipt(ctx, txHash)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+		}
+		pc.receipts.add(txHash, receipt)
 	}
 
-	block, err := pc.rpcClient.BlockByHash(ctx, receipt.BlockHash)
+	header, err := pc.headerByHash(ctx, receipt.BlockHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block: %w", err)
 	}
 
-	domainTx := pc.createDomainTransaction(tx, receipt, block.Time())
+	domainTx := pc.createDomainTransaction(tx, receipt, header.Time, header.BaseFee)
+	if domainTx.Status == domain.TxSuccess {
+		internal, err := pc.txInternalTransfers(ctx, txHash)
+		if err != nil {
+			pc.logger.Warn("Failed to trace internal transfers",
+				zap.String("tx_hash", txHash.Hex()),
+				zap.Error(err))
+		}
+		domainTx.Transfers = append(domainTx.Transfers, internal...)
+	}
+	pc.enrichTransaction(ctx, &domainTx, tx, receipt)
 	return &domainTx, nil
 }
 
@@ -355,15 +763,165 @@ func (pc *PlasmaClient) GetTransfersForAddress(
 	return pc.filterTransfersForAddress(tx.Transfers, watchedAddr), nil
 }
 
+func (pc *PlasmaClient) GetNativeBalance(
+	ctx context.Context,
+	address domain.WalletAddress,
+) (*domain.TokenBalance, error) {
+	balance, err := withRetry(ctx, pc, "balance", func(ctx context.Context, c *ethclient.Client) (*big.Int, error) {
+		return c.BalanceAt(ctx, common.HexToAddress(string(address)), nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get native balance: %w", err)
+	}
+
+	return &domain.TokenBalance{
+		TokenAddress:     "0x0000000000000000000000000000000000000000",
+		TokenSymbol:      "XPL",
+		Balance:          balance,
+		FormattedBalance: formatUnits(balance, nativeDecimals),
+	}, nil
+}
+
+func (pc *PlasmaClient) GetTokenBalance(
+	ctx context.Context,
+	address domain.WalletAddress,
+	tokenAddress string,
+) (*domain.TokenBalance, error) {
+	if !common.IsHexAddress(tokenAddress) {
+		return nil, fmt.Errorf("token %q: %w", tokenAddress, domain.ErrInvalidAddress)
+	}
+
+	helper, err := NewERC20Helper(pc)
+	if err != nil {
+		return nil, err
+	}
+
+	token := common.HexToAddress(tokenAddress)
+	balance, err := helper.GetTokenBalance(ctx, token, common.HexToAddress(string(address)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token balance: %w", err)
+	}
+
+	metadata := pc.tokens.resolve(ctx, token)
+	tokenBalance := &domain.TokenBalance{
+		TokenAddress: token.Hex(),
+		TokenSymbol:  metadata.Symbol,
+		Balance:      balance,
+	}
+	if metadata.HasDecimals {
+		tokenBalance.FormattedBalance = formatUnits(balance, metadata.Decimals)
+	}
+
+	return tokenBalance, nil
+}
+
+// GetBalances returns the native balance of address followed by its balance
+// of each token, using a single multicall when available. Tokens whose
+// balance can't be read are left out.
+func (pc *PlasmaClient) GetBalances(
+	ctx context.Context,
+	address domain.WalletAddress,
+	tokenAddresses []string,
+) ([]domain.TokenBalance, error) {
+	owner := common.HexToAddress(string(address))
+
+	tokens := make([]common.Address, 0, len(tokenAddresses))
+	for _, tokenAddress := range tokenAddresses {
+		if !common.IsHexAddress(tokenAddress) {
+			return nil, fmt.Errorf("token %q: %w", tokenAddress, domain.ErrInvalidAddress)
+		}
+		tokens = append(tokens, common.HexToAddress(tokenAddress))
+	}
+
+	if pc.multicall == nil {
+		return pc.getBalancesOneByOne(ctx, address, tokenAddresses)
+	}
+
+	native, balances, err := pc.multicall.balances(ctx, owner, tokens)
+	if err != nil {
+		pc.logger.Warn("Multicall balance query failed, querying one by one", zap.Error(err))
+		return pc.getBalancesOneByOne(ctx, address, tokenAddresses)
+	}
+
+	pc.tokens.prefetch(ctx, tokens)
+
+	result := make([]domain.TokenBalance, 0, len(tokens)+1)
+	result = append(result, domain.TokenBalance{
+		TokenAddress:     "0x0000000000000000000000000000000000000000",
+		TokenSymbol:      "XPL",
+		Balance:          native,
+		FormattedBalance: formatUnits(native, nativeDecimals),
+	})
+
+	for i, token := range tokens {
+		if balances[i] == nil {
+			continue
+		}
+
+		metadata := pc.tokens.resolve(ctx, token)
+		balance := domain.TokenBalance{
+			TokenAddress: token.Hex(),
+			TokenSymbol:  metadata.Symbol,
+			Balance:      balances[i],
+		}
+		if metadata.HasDecimals {
+			balance.FormattedBalance = formatUnits(balances[i], metadata.Decimals)
+		}
+		result = append(result, balance)
+	}
+
+	return result, nil
+}
+
+func (pc *PlasmaClient) getBalancesOneByOne(
+	ctx context.Context,
+	address domain.WalletAddress,
+	tokenAddresses []string,
+) ([]domain.TokenBalance, error) {
+	native, err := pc.GetNativeBalance(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []domain.TokenBalance{*native}
+	for _, tokenAddress := range tokenAddresses {
+		balance, err := pc.GetTokenBalance(ctx, address, tokenAddress)
+		if err != nil {
+			pc.logger.Warn("Failed to get token balance",
+				zap.String("wallet", string(address)),
+				zap.String("token", tokenAddress),
+				zap.Error(err))
+			continue
+		}
+		result = append(result, *balance)
+	}
+
+	return result, nil
+}
+
+// HealthCheck fails while the RPC circuit breaker is open or the node
+// can't be reached
 func (pc *PlasmaClient) HealthCheck(ctx context.Context) error {
+	if state := pc.breaker.State(); state != breakerStateNames[breakerClosed] {
+		return fmt.Errorf("%w (%s)", errCircuitOpen, state)
+	}
+
 	_, err := pc.GetLatestBlock(ctx)
 	return err
 }
 
+// rpc returns the RPC client that should serve the next call
+func (pc *PlasmaClient) rpc() *ethclient.Client {
+	return pc.pool.client()
+}
+
 func (pc *PlasmaClient) Close() {
-	if pc.rpcClient != nil {
-		pc.rpcClient.Close()
+	pc.pool.close()
+	if pc.traceClient != nil {
+		pc.traceClient.Close()
 	}
+	pc.wsMu.Lock()
+	defer pc.wsMu.Unlock()
 	if pc.wsClient != nil {
 		pc.wsClient.Close()
 	}
@@ -0,0 +1,162 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// Maximum number of calls sent in a single JSON-RPC batch
+const maxReceiptBatch = 100
+
+// blockReceipts fetches all receipts of block in one round trip using
+// eth_getBlockReceipts, falling back to batched eth_getTransactionReceipt
+// on nodes that do not support it
+func (pc *PlasmaClient) blockReceipts(
+	ctx context.Context,
+	block *types.Block,
+) (map[common.Hash]*types.Receipt, error) {
+	hashes := make([]common.Hash, 0, block.Transactions().Len())
+	for _, tx := range block.Transactions() {
+		hashes = append(hashes, tx.Hash())
+	}
+
+	// Skip the node entirely if earlier matches already fetched everything
+	if byHash, missing := pc.cachedReceipts(hashes); len(missing) == 0 {
+		return byHash, nil
+	}
+
+	if !pc.noBlockReceipts.Load() {
+		receipts, err := withRetry(ctx, pc, "block_receipts", func(ctx context.Context, c *ethclient.Client) ([]*types.Receipt, error) {
+			return c.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+		})
+		if err == nil {
+			metrics.ReceiptsFetchedTotal.WithLabelValues("block_receipts").Add(float64(len(receipts)))
+			byHash := make(map[common.Hash]*types.Receipt, len(receipts))
+			for _, receipt := range receipts {
+				byHash[receipt.TxHash] = receipt
+				pc.receipts.add(receipt.TxHash, receipt)
+			}
+			return byHash, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Don't keep paying for a method the node doesn't have
+		if isMethodNotFound(err) {
+			pc.noBlockReceipts.Store(true)
+			pc.logger.Warn("eth_getBlockReceipts not supported, using batched receipt calls")
+		} else {
+			pc.logger.Warn("Failed to get block receipts, using batched receipt calls",
+				zap.Uint64("block", block.NumberU64()),
+				zap.Error(err))
+		}
+	}
+
+	return pc.receiptsFor(ctx, hashes)
+}
+
+// receiptsFor fetches receipts for the given transactions with batched
+// eth_getTransactionReceipt calls, skipping cached ones. Receipts missing
+// from a batch are retried and an error is returned if any are still
+// missing, so no transaction is silently dropped.
+func (pc *PlasmaClient) receiptsFor(
+	ctx context.Context,
+	hashes []common.Hash,
+) (map[common.Hash]*types.Receipt, error) {
+	byHash, missing := pc.cachedReceipts(hashes)
+
+	for start := 0; start < len(missing); start += maxReceiptBatch {
+		end := min(start+maxReceiptBatch, len(missing))
+
+		_, err := withRetry(ctx, pc, "receipts", func(ctx context.Context, c *ethclient.Client) (struct{}, error) {
+			return struct{}{}, batchReceipts(ctx, c, missing[start:end], byHash)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, hash := range missing {
+		pc.receipts.add(hash, byHash[hash])
+	}
+	metrics.ReceiptsFetchedTotal.WithLabelValues("receipt").Add(float64(len(missing)))
+
+	return byHash, nil
+}
+
+// cachedReceipts returns the cached receipts among hashes and the hashes
+// that still have to be fetched
+func (pc *PlasmaClient) cachedReceipts(
+	hashes []common.Hash,
+) (map[common.Hash]*types.Receipt, []common.Hash) {
+	byHash := make(map[common.Hash]*types.Receipt, len(hashes))
+
+	var missing []common.Hash
+	for _, hash := range hashes {
+		if receipt, ok := pc.receipts.get(hash); ok {
+			byHash[hash] = receipt
+		} else {
+			missing = append(missing, hash)
+		}
+	}
+	return byHash, missing
+}
+
+// batchReceipts fetches receipts not yet in byHash in a single batch
+func batchReceipts(
+	ctx context.Context,
+	client *ethclient.Client,
+	hashes []common.Hash,
+	byHash map[common.Hash]*types.Receipt,
+) error {
+	var pending []common.Hash
+	for _, hash := range hashes {
+		if _, ok := byHash[hash]; !ok {
+			pending = append(pending, hash)
+		}
+	}
+
+	receipts := make([]*types.Receipt, len(pending))
+	batch := make([]rpc.BatchElem, len(pending))
+	for i, hash := range pending {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []any{hash},
+			Result: &receipts[i],
+		}
+	}
+
+	if err := client.Client().BatchCallContext(ctx, batch); err != nil {
+		return fmt.Errorf("failed to batch receipt calls: %w", err)
+	}
+
+	var errs []error
+	for i, elem := range batch {
+		switch {
+		case elem.Error != nil:
+			errs = append(errs, fmt.Errorf("receipt %s: %w", pending[i].Hex(), elem.Error))
+		case receipts[i] == nil:
+			// Node hasn't indexed the transaction yet
+			errs = append(errs, fmt.Errorf("receipt %s not available yet", pending[i].Hex()))
+		default:
+			byHash[pending[i]] = receipts[i]
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601
+}
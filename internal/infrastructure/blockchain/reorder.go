@@ -0,0 +1,119 @@
+package blockchain
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// reorderBuffer holds filtered transactions back until every lower block in
+// flight has been published. The stages before it run several workers and
+// finish out of order, so without it a wallet could be notified of a later
+// transaction first.
+type reorderBuffer struct {
+	publish *stage[addressedTx]
+	blocks  map[uint64]*reorderBlock
+	mu      sync.Mutex
+}
+
+// reorderBlock collects the filtered transactions of a block in flight
+type reorderBlock struct {
+	matching int // Submissions of the block not matched yet
+	expected int // Transactions matched
+	arrived  int // Transactions filtered
+	items    []addressedTx
+}
+
+func newReorderBuffer(publish *stage[addressedTx]) *reorderBuffer {
+	return &reorderBuffer{
+		publish: publish,
+		blocks:  make(map[uint64]*reorderBlock),
+	}
+}
+
+// enter registers a block submitted to the pipeline
+func (rb *reorderBuffer) enter(number uint64) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	block, ok := rb.blocks[number]
+	if !ok {
+		block = &reorderBlock{}
+		rb.blocks[number] = block
+	}
+	block.matching++
+}
+
+// matched records how many transactions of the block are on their way
+func (rb *reorderBuffer) matched(ctx context.Context, number uint64, count int) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if block, ok := rb.blocks[number]; ok {
+		block.matching--
+		block.expected += count
+	}
+	rb.flush(ctx)
+}
+
+// drop records that a submission of the block has nothing to publish,
+// because it was skipped or failed before matching finished
+func (rb *reorderBuffer) drop(ctx context.Context, number uint64) {
+	rb.matched(ctx, number, 0)
+}
+
+// add records a filtered transaction of the block, with the items to
+// publish for it, if any
+func (rb *reorderBuffer) add(ctx context.Context, number uint64, items []addressedTx) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	block, ok := rb.blocks[number]
+	if !ok {
+		// Given up on while its transactions were in flight
+		rb.push(ctx, items)
+		return
+	}
+	block.arrived++
+	block.items = append(block.items, items...)
+	rb.flush(ctx)
+}
+
+// flush publishes blocks in order, for as long as the lowest one in flight
+// is complete. Publishing blocks while the stage is full, holding up the
+// stages before it.
+func (rb *reorderBuffer) flush(ctx context.Context) {
+	for len(rb.blocks) > 0 {
+		lowest := slices.Min(slices.Collect(maps.Keys(rb.blocks)))
+		block := rb.blocks[lowest]
+		if block.matching > 0 || block.arrived < block.expected {
+			return
+		}
+
+		delete(rb.blocks, lowest)
+		slices.SortStableFunc(block.items, func(a, b addressedTx) int {
+			return a.position - b.position
+		})
+		if !rb.push(ctx, block.items) {
+			return
+		}
+	}
+}
+
+func (rb *reorderBuffer) push(ctx context.Context, items []addressedTx) bool {
+	for _, item := range items {
+		if !rb.publish.push(ctx, item) {
+			return false
+		}
+	}
+	return true
+}
+
+// len returns the number of blocks in flight
+func (rb *reorderBuffer) len() int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	return len(rb.blocks)
+}
@@ -0,0 +1,144 @@
+package blockchain
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// reorgDetector remembers the hashes of recently submitted blocks so a new
+// head that does not build on them can be recognized as a reorg
+type reorgDetector struct {
+	depth  uint64
+	recent map[uint64]common.Hash
+	feed   *broadcaster[domain.Reorg]
+}
+
+func newReorgDetector(depth int) *reorgDetector {
+	return &reorgDetector{
+		depth:  uint64(max(depth, 1)),
+		recent: make(map[uint64]common.Hash),
+		feed:   newBroadcaster[domain.Reorg](16),
+	}
+}
+
+// record stores the hash of a submitted block and forgets old ones
+func (rd *reorgDetector) record(header *types.Header) {
+	number := header.Number.Uint64()
+	rd.recent[number] = header.Hash()
+
+	if number > rd.depth {
+		for n := range rd.recent {
+			if n < number-rd.depth {
+				delete(rd.recent, n)
+			}
+		}
+	}
+}
+
+// forgetAbove drops hashes of blocks replaced by a reorg
+func (rd *reorgDetector) forgetAbove(number uint64) {
+	for n := range rd.recent {
+		if n > number {
+			delete(rd.recent, n)
+		}
+	}
+}
+
+// SubscribeReorgs returns a channel of detected chain reorganizations,
+// closed once ctx is cancelled
+func (pc *PlasmaClient) SubscribeReorgs(ctx context.Context) (<-chan domain.Reorg, error) {
+	return pc.reorgs.feed.subscribe(ctx), nil
+}
+
+// checkReorg reports whether header replaces already submitted blocks.
+// On a reorg it returns the common ancestor and the new canonical headers
+// between the ancestor and header, oldest first.
+func (pc *PlasmaClient) checkReorg(
+	ctx context.Context,
+	header *types.Header,
+) (ancestor uint64, chain []*types.Header, reorged bool) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return 0, nil, false
+	}
+
+	// The same block delivered twice is not a reorg
+	if hash, ok := pc.reorgs.recent[number]; ok && hash == header.Hash() {
+		return 0, nil, false
+	}
+
+	cur := header
+	for depth := uint64(0); depth < pc.reorgs.depth && cur.Number.Uint64() > 0; depth++ {
+		parentNumber := cur.Number.Uint64() - 1
+
+		hash, ok := pc.reorgs.recent[parentNumber]
+		if !ok {
+			// Parent was never seen, nothing to compare against
+			return 0, nil, false
+		}
+		if hash == cur.ParentHash {
+			if cur == header && number > pc.lastSubmitted {
+				return 0, nil, false // Extends the chain we know
+			}
+			slices.Reverse(chain)
+			return parentNumber, chain, true
+		}
+
+		parent, err := pc.headerByHash(ctx, cur.ParentHash)
+		if err != nil {
+			pc.logger.Error("Failed to walk back reorged chain",
+				zap.String("hash", cur.ParentHash.Hex()),
+				zap.Error(err))
+			return 0, nil, false
+		}
+		chain = append(chain, parent)
+		cur = parent
+	}
+
+	pc.logger.Warn("Reorg deeper than tracked history",
+		zap.Uint64("head", number),
+		zap.Uint64("depth", pc.reorgs.depth))
+	return 0, nil, false
+}
+
+// handleReorg rewinds to the common ancestor, announces the replaced range
+// and resubmits the new canonical blocks
+func (pc *PlasmaClient) handleReorg(
+	ctx context.Context,
+	header *types.Header,
+	ancestor uint64,
+	chain []*types.Header,
+) {
+	reorg := domain.Reorg{
+		FromBlock:  ancestor + 1,
+		ToBlock:    pc.lastSubmitted,
+		NewHead:    header.Number.Uint64(),
+		DetectedAt: time.Now(),
+	}
+
+	pc.logger.Warn("Chain reorg detected",
+		zap.Uint64("from_block", reorg.FromBlock),
+		zap.Uint64("to_block", reorg.ToBlock),
+		zap.Uint64("new_head", reorg.NewHead))
+
+	pc.reorgs.forgetAbove(ancestor)
+	// Replaced transactions may be mined again with different receipts
+	pc.receipts.purge()
+	pc.reorgs.feed.broadcast(reorg)
+	pc.lastSubmitted = ancestor
+
+	for _, h := range chain {
+		if !pc.pipeline.Submit(ctx, h) {
+			return
+		}
+		pc.reorgs.record(h)
+		pc.lastSubmitted = h.Number.Uint64()
+	}
+}
@@ -0,0 +1,105 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// withRetry runs call against the RPC pool, retrying transient failures with
+// backoff. Each attempt picks a client again, so retries fail over to other
+// endpoints. Calls are refused while the circuit breaker is open.
+func withRetry[T any](
+	ctx context.Context,
+	pc *PlasmaClient,
+	op string,
+	call func(context.Context, *ethclient.Client) (T, error),
+) (T, error) {
+	var zero T
+	retry := backoff.New(pc.cfg.RPCRetryMinBackoff, pc.cfg.RPCRetryMaxBackoff)
+
+	for attempt := 0; ; attempt++ {
+		if err := pc.breaker.allow(); err != nil {
+			metrics.RPCCallsTotal.WithLabelValues(op, "rejected").Inc()
+			return zero, err
+		}
+
+		result, err := call(ctx, pc.rpc())
+		if err == nil || !isRetryable(err) {
+			// Application-level errors mean the endpoint itself is fine
+			pc.breaker.record(nil)
+			metrics.RPCCallsTotal.WithLabelValues(op, callResult(err)).Inc()
+			return result, err
+		}
+		if ctx.Err() != nil {
+			pc.breaker.record(nil)
+			return zero, err
+		}
+
+		pc.breaker.record(err)
+		if attempt >= pc.cfg.RPCMaxRetries || !retry.Wait(ctx) {
+			metrics.RPCCallsTotal.WithLabelValues(op, "failed").Inc()
+			return zero, err
+		}
+		metrics.RPCCallsTotal.WithLabelValues(op, "retried").Inc()
+	}
+}
+
+// isRetryable reports whether err may succeed on another attempt
+func isRetryable(err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, ethereum.NotFound),
+		errors.Is(err, types.ErrTxTypeNotSupported),
+		errors.Is(err, errCircuitOpen):
+		return false
+	}
+	return !isExecutionReverted(err) && !isMethodNotFound(err)
+}
+
+func callResult(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func (pc *PlasmaClient) headerByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return withRetry(ctx, pc, "header", func(ctx context.Context, c *ethclient.Client) (*types.Header, error) {
+		return c.HeaderByNumber(ctx, number)
+	})
+}
+
+func (pc *PlasmaClient) headerByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return withRetry(ctx, pc, "header", func(ctx context.Context, c *ethclient.Client) (*types.Header, error) {
+		return c.HeaderByHash(ctx, hash)
+	})
+}
+
+func (pc *PlasmaClient) filterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return withRetry(ctx, pc, "logs", func(ctx context.Context, c *ethclient.Client) ([]types.Log, error) {
+		return c.FilterLogs(ctx, query)
+	})
+}
+
+func (pc *PlasmaClient) callContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return withRetry(ctx, pc, "call", func(ctx context.Context, c *ethclient.Client) ([]byte, error) {
+		return c.CallContract(ctx, msg, nil)
+	})
+}
+
+// callContractAt runs the call against the state at the given block
+func (pc *PlasmaClient) callContractAt(ctx context.Context, msg ethereum.CallMsg, number *big.Int) ([]byte, error) {
+	return withRetry(ctx, pc, "call", func(ctx context.Context, c *ethclient.Client) ([]byte, error) {
+		return c.CallContract(ctx, msg, number)
+	})
+}
@@ -0,0 +1,227 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// rpcEndpoint is a single RPC provider in the pool
+type rpcEndpoint struct {
+	url     string
+	client  *ethclient.Client
+	healthy atomic.Bool
+}
+
+// rpcPool spreads read calls over several RPC providers. By default the
+// first healthy endpoint in configuration order serves every call; with
+// load balancing enabled calls rotate over all healthy endpoints. Failing
+// endpoints are taken out of rotation until a health check passes again.
+type rpcPool struct {
+	endpoints []*rpcEndpoint
+	balance   bool
+	next      atomic.Uint64
+	logger    *zap.Logger
+}
+
+// rpcLimits bounds the request rate and concurrency per endpoint
+type rpcLimits struct {
+	rate        float64 // Requests per second, 0 = unlimited
+	burst       int
+	concurrency int // Maximum in-flight requests, 0 = unlimited
+}
+
+func newRPCPool(
+	ctx context.Context,
+	urls []string,
+	balance bool,
+	limits rpcLimits,
+	logger *zap.Logger,
+) (*rpcPool, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no RPC endpoints configured")
+	}
+
+	pool := &rpcPool{
+		balance: balance,
+		logger:  logger,
+	}
+
+	for _, url := range urls {
+		endpoint := &rpcEndpoint{url: url}
+
+		// Transport errors take the endpoint out of rotation immediately
+		httpClient := &http.Client{
+			Transport: newLimitedTransport(
+				&failureReportingTransport{
+					base:   http.DefaultTransport,
+					report: func(err error) { pool.fail(endpoint, err) },
+				},
+				limits,
+			),
+		}
+
+		rpcClient, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(httpClient))
+		if err != nil {
+			pool.close()
+			return nil, fmt.Errorf("failed to connect to RPC %s: %w", url, err)
+		}
+
+		endpoint.client = ethclient.NewClient(rpcClient)
+		endpoint.healthy.Store(true)
+		pool.endpoints = append(pool.endpoints, endpoint)
+	}
+
+	return pool, nil
+}
+
+// client returns the endpoint that should serve the next call. If every
+// endpoint is unhealthy the first one is used anyway.
+func (p *rpcPool) client() *ethclient.Client {
+	if p.balance {
+		start := p.next.Add(1)
+		for i := range p.endpoints {
+			endpoint := p.endpoints[(start+uint64(i))%uint64(len(p.endpoints))]
+			if endpoint.healthy.Load() {
+				return endpoint.client
+			}
+		}
+	} else {
+		for _, endpoint := range p.endpoints {
+			if endpoint.healthy.Load() {
+				return endpoint.client
+			}
+		}
+	}
+
+	return p.endpoints[0].client
+}
+
+func (p *rpcPool) fail(endpoint *rpcEndpoint, err error) {
+	// Only log the transition, not every failed call
+	if endpoint.healthy.Swap(false) && len(p.endpoints) > 1 {
+		p.logger.Warn("RPC endpoint failed, rotating to next",
+			zap.String("endpoint", endpoint.url),
+			zap.Error(err))
+	}
+}
+
+// run health-checks every endpoint at the given interval until ctx is
+// cancelled, returning recovered endpoints to rotation
+func (p *rpcPool) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, endpoint := range p.endpoints {
+				p.check(ctx, endpoint, interval)
+			}
+		}
+	}
+}
+
+func (p *rpcPool) check(ctx context.Context, endpoint *rpcEndpoint, timeout time.Duration) {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err := endpoint.client.BlockNumber(checkCtx); err != nil {
+		if ctx.Err() == nil {
+			p.fail(endpoint, err)
+		}
+		return
+	}
+
+	if !endpoint.healthy.Swap(true) {
+		p.logger.Info("RPC endpoint recovered", zap.String("endpoint", endpoint.url))
+	}
+}
+
+func (p *rpcPool) close() {
+	for _, endpoint := range p.endpoints {
+		endpoint.client.Close()
+	}
+}
+
+// failureReportingTransport reports network errors and server-side HTTP
+// failures (5xx, 429) of an endpoint
+type failureReportingTransport struct {
+	base   http.RoundTripper
+	report func(error)
+}
+
+func (t *failureReportingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		if req.Context().Err() == nil {
+			t.report(err)
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		t.report(fmt.Errorf("HTTP %s", resp.Status))
+	}
+	return resp, nil
+}
+
+// limitedTransport throttles requests with a token bucket and caps how many
+// are in flight, so busy blocks don't exceed provider rate limits. A JSON-RPC
+// batch counts as a single request.
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+func newLimitedTransport(base http.RoundTripper, limits rpcLimits) http.RoundTripper {
+	if limits.rate <= 0 && limits.concurrency <= 0 {
+		return base
+	}
+
+	t := &limitedTransport{
+		base:    base,
+		limiter: rate.NewLimiter(rate.Inf, 0),
+	}
+	if limits.rate > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(limits.rate), max(limits.burst, 1))
+	}
+	if limits.concurrency > 0 {
+		t.slots = make(chan struct{}, limits.concurrency)
+	}
+	return t
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+			defer func() { <-t.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := t.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	metrics.RPCThrottleSeconds.Observe(time.Since(start).Seconds())
+
+	return t.base.RoundTrip(req)
+}
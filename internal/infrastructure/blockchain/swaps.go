@@ -0,0 +1,221 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// Uniswap V2-style Swap(address,uint256,uint256,uint256,uint256,address)
+var swapV2EventSignature = common.HexToHash(
+	"0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822",
+)
+
+// Uniswap V3-style Swap(address,address,int256,int256,uint160,uint128,int24)
+var swapV3EventSignature = common.HexToHash(
+	"0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67",
+)
+
+// token0() and token1() selectors shared by V2 pairs and V3 pools
+var (
+	token0Selector = common.FromHex("0x0dfe1681")
+	token1Selector = common.FromHex("0xd21220a7")
+)
+
+// 2^256, for decoding two's complement int256 words
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// poolTokens caches the token pair of each pool; pairs never change
+type poolTokens struct {
+	size  int
+	pairs map[common.Address][2]common.Address
+	mu    sync.RWMutex
+}
+
+func newPoolTokens(size int) *poolTokens {
+	return &poolTokens{
+		size:  size,
+		pairs: make(map[common.Address][2]common.Address),
+	}
+}
+
+// rawSwap is a decoded Swap event before its pool tokens are known.
+// Amounts are from the pool's point of view: positive in, negative out.
+type rawSwap struct {
+	protocol  string
+	pool      common.Address
+	sender    common.Address
+	recipient common.Address
+	amount0   *big.Int
+	amount1   *big.Int
+	logIndex  int
+}
+
+// decodeSwapLog recognises V2 and V3-style Swap events
+func decodeSwapLog(log *types.Log) (rawSwap, bool) {
+	if len(log.Topics) != 3 {
+		return rawSwap{}, false
+	}
+
+	swap := rawSwap{
+		pool:      log.Address,
+		sender:    common.BytesToAddress(log.Topics[1].Bytes()),
+		recipient: common.BytesToAddress(log.Topics[2].Bytes()),
+		logIndex:  int(log.Index),
+	}
+
+	switch log.Topics[0] {
+	case swapV2EventSignature:
+		if len(log.Data) != 4*32 {
+			return rawSwap{}, false
+		}
+		word := func(i int) *big.Int { return new(big.Int).SetBytes(log.Data[i*32 : (i+1)*32]) }
+		swap.protocol = "uniswap_v2"
+		swap.amount0 = new(big.Int).Sub(word(0), word(2))
+		swap.amount1 = new(big.Int).Sub(word(1), word(3))
+
+	case swapV3EventSignature:
+		if len(log.Data) != 5*32 {
+			return rawSwap{}, false
+		}
+		swap.protocol = "uniswap_v3"
+		swap.amount0 = signedWord(log.Data[0:32])
+		swap.amount1 = signedWord(log.Data[32:64])
+
+	default:
+		return rawSwap{}, false
+	}
+
+	return swap, true
+}
+
+func signedWord(word []byte) *big.Int {
+	value := new(big.Int).SetBytes(word)
+	if word[0]&0x80 != 0 {
+		value.Sub(value, twoTo256)
+	}
+	return value
+}
+
+// extractSwaps turns the receipt's Swap events into swaps with resolved
+// tokens. Logs from contracts that don't expose a token pair are skipped.
+func (pc *PlasmaClient) extractSwaps(ctx context.Context, receipt *types.Receipt) []domain.Swap {
+	var swaps []domain.Swap
+
+	for _, log := range receipt.Logs {
+		raw, ok := decodeSwapLog(log)
+		if !ok {
+			continue
+		}
+
+		pair, err := pc.poolPair(ctx, raw.pool)
+		if err != nil {
+			pc.logger.Debug("Skipping swap from unrecognised pool",
+				zap.String("pool", raw.pool.Hex()),
+				zap.String("tx_hash", receipt.TxHash.Hex()),
+				zap.Error(err))
+			continue
+		}
+
+		// The token the pool received went in, the other came out
+		tokenIn, tokenOut := pair[0], pair[1]
+		amountIn, amountOut := raw.amount0, new(big.Int).Neg(raw.amount1)
+		if raw.amount0.Sign() <= 0 {
+			tokenIn, tokenOut = pair[1], pair[0]
+			amountIn, amountOut = raw.amount1, new(big.Int).Neg(raw.amount0)
+		}
+
+		swaps = append(swaps, domain.Swap{
+			TxHash:    domain.TransactionHash(receipt.TxHash.Hex()),
+			Protocol:  raw.protocol,
+			Pool:      raw.pool.Hex(),
+			Sender:    domain.WalletAddress(raw.sender.Hex()),
+			Recipient: domain.WalletAddress(raw.recipient.Hex()),
+			TokenIn:   tokenIn.Hex(),
+			AmountIn:  amountIn,
+			TokenOut:  tokenOut.Hex(),
+			AmountOut: amountOut,
+			LogIndex:  raw.logIndex,
+		})
+	}
+
+	return swaps
+}
+
+// poolPair returns token0 and token1 of pool
+func (pc *PlasmaClient) poolPair(ctx context.Context, pool common.Address) ([2]common.Address, error) {
+	pc.pools.mu.RLock()
+	pair, ok := pc.pools.pairs[pool]
+	pc.pools.mu.RUnlock()
+	if ok {
+		return pair, nil
+	}
+
+	for i, selector := range [][]byte{token0Selector, token1Selector} {
+		result, err := pc.callContract(ctx, ethereum.CallMsg{To: &pool, Data: selector})
+		if err != nil {
+			return pair, err
+		}
+		if len(result) != 32 {
+			return pair, fmt.Errorf("unexpected token%d() result of %d bytes", i, len(result))
+		}
+		pair[i] = common.BytesToAddress(result)
+	}
+
+	pc.pools.mu.Lock()
+	if len(pc.pools.pairs) < pc.pools.size {
+		pc.pools.pairs[pool] = pair
+	}
+	pc.pools.mu.Unlock()
+
+	return pair, nil
+}
+
+// enrichSwaps resolves token symbols and human-readable amounts
+func (pc *PlasmaClient) enrichSwaps(ctx context.Context, swaps []domain.Swap) {
+	tokens := make([]common.Address, 0, 2*len(swaps))
+	for _, swap := range swaps {
+		tokens = append(tokens, common.HexToAddress(swap.TokenIn), common.HexToAddress(swap.TokenOut))
+	}
+	pc.tokens.prefetch(ctx, tokens)
+
+	for i := range swaps {
+		swap := &swaps[i]
+
+		in := pc.tokens.resolve(ctx, common.HexToAddress(swap.TokenIn))
+		swap.TokenInSymbol = in.Symbol
+		if in.HasDecimals {
+			swap.FormattedAmountIn = formatUnits(swap.AmountIn, in.Decimals)
+		}
+
+		out := pc.tokens.resolve(ctx, common.HexToAddress(swap.TokenOut))
+		swap.TokenOutSymbol = out.Symbol
+		if out.HasDecimals {
+			swap.FormattedAmountOut = formatUnits(swap.AmountOut, out.Decimals)
+		}
+	}
+}
+
+// filterSwapsFor keeps the swaps a wallet takes part in: all swaps of a
+// transaction it sent, otherwise those paying out to it
+func filterSwapsFor(tx domain.Transaction, address common.Address) []domain.Swap {
+	if common.HexToAddress(string(tx.From)) == address {
+		return tx.Swaps
+	}
+
+	var relevant []domain.Swap
+	for _, swap := range tx.Swaps {
+		if common.HexToAddress(string(swap.Recipient)) == address {
+			relevant = append(relevant, swap)
+		}
+	}
+	return relevant
+}
@@ -0,0 +1,100 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Native XPL uses the same precision as ether
+const nativeDecimals = 18
+
+// enrichTransaction fills in everything that needs extra RPC calls: token
+// metadata, swaps with their pool tokens, bridged tokens and the revert
+// reason of failed transactions
+func (pc *PlasmaClient) enrichTransaction(
+	ctx context.Context,
+	tx *domain.Transaction,
+	source *types.Transaction,
+	receipt *types.Receipt,
+) {
+	tx.Swaps = pc.extractSwaps(ctx, receipt)
+	tx.Bridges = pc.extractBridgeTransfers(ctx, receipt)
+
+	pc.enrichTransfers(ctx, tx.Transfers)
+	pc.enrichApprovals(ctx, tx.Approvals)
+	pc.enrichSwaps(ctx, tx.Swaps)
+	pc.enrichBridgeTransfers(ctx, tx.Bridges)
+
+	if tx.Status == domain.TxReverted && tx.From != "" && source != nil {
+		tx.RevertReason, tx.RevertReasonReplayed = pc.revertReason(ctx, source, common.HexToAddress(string(tx.From)), tx.BlockNumber)
+	}
+}
+
+// enrichTransfers resolves token symbols and human-readable amounts
+func (pc *PlasmaClient) enrichTransfers(ctx context.Context, transfers []domain.Transfer) {
+	// Resolve all unknown tokens of the batch in one go
+	tokens := make([]common.Address, 0, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.TokenStandard != domain.NativeToken {
+			tokens = append(tokens, common.HexToAddress(transfer.TokenAddress))
+		}
+	}
+	pc.tokens.prefetch(ctx, tokens)
+
+	for i := range transfers {
+		transfer := &transfers[i]
+
+		switch transfer.TokenStandard {
+		case domain.NativeToken:
+			transfer.FormattedValue = formatUnits(transfer.Value, nativeDecimals)
+		case domain.ERC721Token:
+			if transfer.TokenSymbol == "" {
+				transfer.TokenSymbol = pc.tokens.resolve(ctx, common.HexToAddress(transfer.TokenAddress)).Symbol
+			}
+		default:
+			metadata := pc.tokens.resolve(ctx, common.HexToAddress(transfer.TokenAddress))
+			if transfer.TokenSymbol == "" {
+				transfer.TokenSymbol = metadata.Symbol
+			}
+			if metadata.HasDecimals {
+				transfer.FormattedValue = formatUnits(transfer.Value, metadata.Decimals)
+			}
+		}
+	}
+}
+
+// formatUnits renders value scaled down by decimals as a decimal string,
+// e.g. 1500000 with 6 decimals becomes "1.5"
+func formatUnits(value *big.Int, decimals uint8) string {
+	if value == nil {
+		return ""
+	}
+
+	digits := new(big.Int).Abs(value).String()
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+
+	if decimals == 0 {
+		return sign + digits
+	}
+
+	scale := int(decimals)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	whole := digits[:len(digits)-scale]
+	fraction := strings.TrimRight(digits[len(digits)-scale:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
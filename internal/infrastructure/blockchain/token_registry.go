@@ -0,0 +1,209 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// TokenRegistry resolves token metadata from the contracts themselves,
+// caching it in memory and, if a store is configured, in Redis
+type TokenRegistry struct {
+	client *PlasmaClient
+	store  domain.TokenMetadataStore
+	logger *zap.Logger
+
+	cache *lruCache[common.Address, *domain.TokenMetadata]
+}
+
+func newTokenRegistry(client *PlasmaClient, size int, logger *zap.Logger) *TokenRegistry {
+	return &TokenRegistry{
+		client: client,
+		logger: logger,
+		cache:  newLRUCache[common.Address, *domain.TokenMetadata]("tokens", size),
+	}
+}
+
+// cacheSize returns the number of tokens held in memory
+func (tr *TokenRegistry) cacheSize() int {
+	return tr.cache.len()
+}
+
+// GetTokenMetadata returns metadata for the token, querying the contract
+// only if neither cache has it
+func (tr *TokenRegistry) GetTokenMetadata(
+	ctx context.Context,
+	tokenAddress string,
+) (*domain.TokenMetadata, error) {
+	if !common.IsHexAddress(tokenAddress) {
+		return nil, fmt.Errorf("token %q: %w", tokenAddress, domain.ErrInvalidAddress)
+	}
+
+	return tr.resolve(ctx, common.HexToAddress(tokenAddress)), nil
+}
+
+// resolve never fails: fields the contract doesn't expose are left empty and
+// the symbol falls back to a shortened address. Metadata is only cached once
+// the contract answered every call, successfully or by reverting; a
+// fallback caused by the node failing is used for this call alone.
+func (tr *TokenRegistry) resolve(ctx context.Context, address common.Address) *domain.TokenMetadata {
+	if metadata, exists := tr.cache.get(address); exists {
+		return metadata
+	}
+
+	// Special cases for known tokens
+	if known, ok := knownTokens[address]; ok {
+		return knownToken(address, known[0], known[1])
+	}
+
+	if tr.store != nil {
+		stored, err := tr.store.LoadTokenMetadata(ctx, address.Hex())
+		if err != nil {
+			tr.logger.Warn("Failed to load token metadata",
+				zap.String("token", address.Hex()),
+				zap.Error(err))
+		}
+		if stored != nil {
+			tr.cache.add(address, stored)
+			return stored
+		}
+	}
+
+	metadata, err := tr.query(ctx, address)
+	if err != nil {
+		tr.logger.Warn("Failed to query token metadata, using a fallback",
+			zap.String("token", address.Hex()),
+			zap.Error(err))
+		return metadata
+	}
+
+	if tr.store != nil {
+		if err := tr.store.SaveTokenMetadata(ctx, metadata); err != nil {
+			tr.logger.Warn("Failed to save token metadata",
+				zap.String("token", address.Hex()),
+				zap.Error(err))
+		}
+	}
+	tr.cache.add(address, metadata)
+
+	return metadata
+}
+
+// prefetch resolves every token not cached yet with a single multicall, so
+// the resolve calls that follow don't query contracts one by one
+func (tr *TokenRegistry) prefetch(ctx context.Context, addresses []common.Address) {
+	if tr.client.multicall == nil {
+		return
+	}
+
+	var missing []common.Address
+	seen := make(map[common.Address]bool)
+	for _, address := range addresses {
+		if seen[address] || isKnownToken(address) {
+			continue
+		}
+		seen[address] = true
+
+		if _, cached := tr.cache.get(address); cached {
+			continue
+		}
+
+		if tr.store != nil {
+			if stored, err := tr.store.LoadTokenMetadata(ctx, address.Hex()); err == nil && stored != nil {
+				tr.cache.add(address, stored)
+				continue
+			}
+		}
+		missing = append(missing, address)
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	resolved, err := tr.client.multicall.tokenMetadata(ctx, missing)
+	if err != nil {
+		tr.logger.Warn("Failed to prefetch token metadata", zap.Error(err))
+		return
+	}
+
+	// Calls that failed inside the multicall were reverts, so the result is
+	// definitive either way
+	for i, metadata := range resolved {
+		if tr.store != nil {
+			if err := tr.store.SaveTokenMetadata(ctx, metadata); err != nil {
+				tr.logger.Warn("Failed to save token metadata",
+					zap.String("token", metadata.Address),
+					zap.Error(err))
+			}
+		}
+		tr.cache.add(missing[i], metadata)
+	}
+}
+
+// query reads the token's metadata from its contract. Fields the contract
+// doesn't expose are left empty; the metadata is returned with an error if
+// a call failed for another reason than the contract reverting or
+// answering garbage, as it doesn't say what the contract exposes then.
+func (tr *TokenRegistry) query(ctx context.Context, address common.Address) (*domain.TokenMetadata, error) {
+	metadata := &domain.TokenMetadata{
+		Address:    address.Hex(),
+		Symbol:     address.Hex()[:8],
+		ResolvedAt: time.Now(),
+	}
+
+	helper, err := NewERC20Helper(tr.client)
+	if err != nil {
+		return metadata, err
+	}
+
+	var errs []error
+	check := func(err error) bool {
+		if err != nil && !isExecutionReverted(err) && !errors.Is(err, errBadReturnData) {
+			errs = append(errs, err)
+		}
+		return err == nil
+	}
+
+	if name, err := helper.GetTokenName(ctx, address); check(err) {
+		metadata.Name = name
+	}
+	if symbol, err := helper.GetTokenSymbol(ctx, address); check(err) {
+		metadata.Symbol = symbol
+	}
+	if decimals, err := helper.GetTokenDecimals(ctx, address); check(err) {
+		metadata.Decimals = decimals
+		metadata.HasDecimals = true
+	}
+	if totalSupply, err := helper.GetTotalSupply(ctx, address); check(err) {
+		metadata.TotalSupply = totalSupply
+	}
+
+	return metadata, errors.Join(errs...)
+}
+
+// Tokens resolved without querying the contract: address -> name, symbol
+var knownTokens = map[common.Address][2]string{
+	common.HexToAddress("0x0000000000000000000000000000000000000000"): {"Plasma", "XPL"},
+	common.HexToAddress("0xa0b86a33e6ba0c74d75c9abfd35e5e0b1bcceb83"): {"Wrapped XPL", "WXPL"}, // Example WXPL
+}
+
+func isKnownToken(address common.Address) bool {
+	_, ok := knownTokens[address]
+	return ok
+}
+
+func knownToken(address common.Address, name, symbol string) *domain.TokenMetadata {
+	return &domain.TokenMetadata{
+		Address:     address.Hex(),
+		Name:        name,
+		Symbol:      symbol,
+		Decimals:    nativeDecimals,
+		HasDecimals: true,
+	}
+}
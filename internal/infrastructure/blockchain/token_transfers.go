@@ -0,0 +1,183 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// tokenWatch is a single consumer of transfers of one token
+type tokenWatch struct {
+	address common.Address
+	ch      chan domain.TokenTransferEvent
+}
+
+// tokenIndex holds all active token watches
+type tokenIndex struct {
+	watches []*tokenWatch
+	mu      sync.RWMutex
+}
+
+func newTokenIndex() *tokenIndex {
+	return &tokenIndex{}
+}
+
+func (ti *tokenIndex) add(watch *tokenWatch) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.watches = append(ti.watches, watch)
+}
+
+// remove unregisters watch and closes its channel
+func (ti *tokenIndex) remove(watch *tokenWatch) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	for i, w := range ti.watches {
+		if w == watch {
+			ti.watches = append(ti.watches[:i], ti.watches[i+1:]...)
+			break
+		}
+	}
+
+	close(watch.ch)
+}
+
+// tokens returns the distinct tracked token contracts
+func (ti *tokenIndex) tokens() []common.Address {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	addresses := make(map[common.Address]struct{})
+	for _, watch := range ti.watches {
+		addresses[watch.address] = struct{}{}
+	}
+	return mapKeys(addresses)
+}
+
+// buffers reports the occupancy of all consumer channels
+func (ti *tokenIndex) buffers() BufferState {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	var state BufferState
+	for _, watch := range ti.watches {
+		state.observe(len(watch.ch), cap(watch.ch))
+	}
+	return state
+}
+
+// deliver hands the transfer to every watch of its token without blocking
+func (ti *tokenIndex) deliver(event domain.TokenTransferEvent, logger *zap.Logger) {
+	ti.mu.RLock()
+	defer ti.mu.RUnlock()
+
+	token := common.HexToAddress(event.Transfer.TokenAddress)
+	for _, watch := range ti.watches {
+		if watch.address != token {
+			continue
+		}
+
+		select {
+		case watch.ch <- event:
+		default:
+			metrics.NotificationsDroppedTotal.WithLabelValues("consumer_full").Inc()
+			logger.Warn("Token transfer consumer is full, dropping transfer",
+				zap.String("token", token.Hex()),
+				zap.String("tx_hash", string(event.Transfer.TxHash)))
+		}
+	}
+}
+
+// SubscribeToToken registers a token watch. The returned channel is closed
+// once ctx is cancelled.
+func (pc *PlasmaClient) SubscribeToToken(
+	ctx context.Context,
+	tokenAddress domain.WalletAddress,
+) (<-chan domain.TokenTransferEvent, error) {
+	if !common.IsHexAddress(string(tokenAddress)) {
+		return nil, domain.ErrInvalidAddress
+	}
+
+	watch := &tokenWatch{
+		address: common.HexToAddress(string(tokenAddress)),
+		ch:      make(chan domain.TokenTransferEvent, 1000),
+	}
+	pc.tracked.add(watch)
+
+	pc.logger.Info("Started monitoring token transfers", zap.String("token", watch.address.Hex()))
+
+	go func() {
+		<-ctx.Done()
+		pc.tracked.remove(watch)
+
+		pc.logger.Info("Stopped monitoring token transfers", zap.String("token", watch.address.Hex()))
+	}()
+
+	return watch.ch, nil
+}
+
+// deliverTokenTransfers fetches the block's Transfer logs of all tracked
+// tokens and hands them to their watches
+func (pc *PlasmaClient) deliverTokenTransfers(ctx context.Context, header *types.Header) error {
+	if !header.Bloom.Test(transferEventSignature.Bytes()) {
+		return nil
+	}
+	tokens := emittersInBloom(header.Bloom, pc.tracked.tokens())
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	blockHash := header.Hash()
+	for start := 0; start < len(tokens); start += maxTopicAddresses {
+		end := min(start+maxTopicAddresses, len(tokens))
+
+		logs, err := pc.filterLogs(ctx, ethereum.FilterQuery{
+			BlockHash: &blockHash,
+			Addresses: tokens[start:end],
+			Topics:    [][]common.Hash{{transferEventSignature}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to filter token transfer logs: %w", err)
+		}
+
+		// ERC-721 transfers index the token ID as a fourth topic
+		transfers := make([]domain.Transfer, 0, len(logs))
+		for _, log := range logs {
+			if len(log.Topics) != 3 || log.Removed {
+				continue
+			}
+			transfers = append(transfers, domain.Transfer{
+				TxHash:        domain.TransactionHash(log.TxHash.Hex()),
+				From:          domain.WalletAddress(common.HexToAddress(log.Topics[1].Hex()).Hex()),
+				To:            domain.WalletAddress(common.HexToAddress(log.Topics[2].Hex()).Hex()),
+				Value:         new(big.Int).SetBytes(log.Data),
+				TokenAddress:  log.Address.Hex(),
+				TokenStandard: domain.ERC20Token,
+				LogIndex:      int(log.Index),
+			})
+		}
+		pc.enrichTransfers(ctx, transfers)
+
+		for _, transfer := range transfers {
+			pc.tracked.deliver(domain.TokenTransferEvent{
+				Transfer:    transfer,
+				BlockNumber: header.Number.Uint64(),
+				Timestamp:   time.Unix(int64(header.Time), 0),
+			}, pc.logger)
+		}
+	}
+
+	return nil
+}
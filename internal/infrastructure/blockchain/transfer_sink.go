@@ -0,0 +1,122 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// transferSink is a sink given to WithTransferSink
+type transferSink struct {
+	sink     domain.TransferSink
+	firehose bool
+}
+
+// WithTransferSink hands extracted transfers to sink: those of transactions
+// involving a watched address, or with firehose every transfer of every
+// block. The firehose fetches all receipts of each block, and tracing if
+// enabled covers every transaction either way. Several sinks may be given.
+func WithTransferSink(sink domain.TransferSink, firehose bool) Option {
+	return func(pc *PlasmaClient) {
+		pc.sinks = append(pc.sinks, transferSink{sink: sink, firehose: firehose})
+		pc.firehose = pc.firehose || firehose
+	}
+}
+
+// sinkTransaction hands the transfers of a matched transaction to the sinks
+// that don't get whole blocks
+func (pc *PlasmaClient) sinkTransaction(ctx context.Context, tx domain.Transaction, blockHash common.Hash) {
+	if len(tx.Transfers) == 0 {
+		return
+	}
+	var sinks []domain.TransferSink
+	for _, s := range pc.sinks {
+		if !s.firehose {
+			sinks = append(sinks, s.sink)
+		}
+	}
+	if len(sinks) == 0 {
+		return
+	}
+
+	records := make([]domain.TransferRecord, 0, len(tx.Transfers))
+	for i, transfer := range tx.Transfers {
+		records = append(records, domain.TransferRecord{
+			BlockNumber: tx.BlockNumber,
+			BlockHash:   blockHash.Hex(),
+			BlockTime:   tx.Timestamp,
+			Position:    i,
+			Transfer:    transfer,
+		})
+	}
+	for _, sink := range sinks {
+		sink.Receive(ctx, records)
+	}
+}
+
+// deliverFirehose hands every transfer of block to the firehose sinks,
+// extracted and enriched the way matched transactions are. Nothing is
+// handed over if it fails.
+func (pc *PlasmaClient) deliverFirehose(
+	ctx context.Context,
+	block *types.Block,
+	internal map[common.Hash][]domain.Transfer,
+) error {
+	receipts, err := pc.blockReceipts(ctx, block)
+	if err != nil {
+		return fmt.Errorf("failed to get block receipts: %w", err)
+	}
+
+	// Transfers of each transaction, flattened so tokens resolve in one go
+	var transfers []domain.Transfer
+	counts := make([]int, 0, block.Transactions().Len())
+	for _, tx := range block.Transactions() {
+		receipt, ok := receipts[tx.Hash()]
+		if !ok {
+			return fmt.Errorf("missing receipt for transaction %s", tx.Hash().Hex())
+		}
+
+		// Left empty if it can't be recovered, as for matched transactions
+		from := ""
+		if sender, err := pc.senderOf(tx); err == nil {
+			from = sender.Hex()
+		}
+
+		extracted := pc.extractAllTransfers(tx.Hash(), tx.To(), tx.Value(), receipt, domain.WalletAddress(from))
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			extracted = append(extracted, internal[tx.Hash()]...)
+		}
+		transfers = append(transfers, extracted...)
+		counts = append(counts, len(extracted))
+	}
+	if len(transfers) == 0 {
+		return nil
+	}
+	pc.enrichTransfers(ctx, transfers)
+
+	records := make([]domain.TransferRecord, 0, len(transfers))
+	blockTime := time.Unix(int64(block.Time()), 0)
+	for _, count := range counts {
+		for i, transfer := range transfers[:count] {
+			records = append(records, domain.TransferRecord{
+				BlockNumber: block.NumberU64(),
+				BlockHash:   block.Hash().Hex(),
+				BlockTime:   blockTime,
+				Position:    i,
+				Transfer:    transfer,
+			})
+		}
+		transfers = transfers[count:]
+	}
+	for _, s := range pc.sinks {
+		if s.firehose {
+			s.sink.Receive(ctx, records)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// observe(uint32[]) of Uniswap V3-style pools
+var observeSelector = crypto.Keccak256([]byte("observe(uint32[])"))[:4]
+
+// TWAPPriceProvider prices tokens from the time-weighted average tick of
+// Uniswap V3-style pools that pair them with a stablecoin, taken to be
+// worth exactly $1
+type TWAPPriceProvider struct {
+	client      *PlasmaClient
+	pools       []common.Address
+	stablecoins map[common.Address]bool
+	wrapped     common.Address // Priced in place of native XPL
+	window      uint32         // In seconds
+}
+
+func NewTWAPPriceProvider(
+	client *PlasmaClient,
+	pools []string,
+	stablecoins []string,
+	wrappedNative string,
+	window time.Duration,
+) (*TWAPPriceProvider, error) {
+	provider := &TWAPPriceProvider{
+		client:      client,
+		stablecoins: make(map[common.Address]bool),
+		window:      uint32(max(window/time.Second, 1)),
+	}
+
+	for _, pool := range pools {
+		if !common.IsHexAddress(pool) {
+			return nil, fmt.Errorf("invalid TWAP pool address %q", pool)
+		}
+		provider.pools = append(provider.pools, common.HexToAddress(pool))
+	}
+	for _, stablecoin := range stablecoins {
+		if !common.IsHexAddress(stablecoin) {
+			return nil, fmt.Errorf("invalid stablecoin address %q", stablecoin)
+		}
+		provider.stablecoins[common.HexToAddress(stablecoin)] = true
+	}
+	if wrappedNative != "" {
+		if !common.IsHexAddress(wrappedNative) {
+			return nil, fmt.Errorf("invalid wrapped native token address %q", wrappedNative)
+		}
+		provider.wrapped = common.HexToAddress(wrappedNative)
+	}
+
+	return provider, nil
+}
+
+func (p *TWAPPriceProvider) Name() string {
+	return "twap"
+}
+
+// GetPricesUSD prices each requested token that one of the pools pairs
+// with a stablecoin. Pools that can't be read are skipped.
+func (p *TWAPPriceProvider) GetPricesUSD(
+	ctx context.Context,
+	tokenAddresses []string,
+) (map[string]float64, error) {
+	// Token to price -> keys it is reported under
+	wanted := make(map[common.Address][]string)
+	for _, address := range tokenAddresses {
+		token := common.HexToAddress(address)
+		if token == (common.Address{}) {
+			if p.wrapped == (common.Address{}) {
+				continue
+			}
+			token = p.wrapped
+		}
+		wanted[token] = append(wanted[token], strings.ToLower(address))
+	}
+
+	prices := make(map[string]float64)
+	for _, pool := range p.pools {
+		price, token, err := p.poolPrice(ctx, pool, wanted)
+		if err != nil {
+			p.client.logger.Warn("Failed to read TWAP price",
+				zap.String("pool", pool.Hex()),
+				zap.Error(err))
+			continue
+		}
+		for _, key := range wanted[token] {
+			prices[key] = price
+		}
+	}
+
+	return prices, nil
+}
+
+// poolPrice returns the USD price of the pool's non-stablecoin token if it
+// is wanted; a zero token means the pool has nothing to contribute
+func (p *TWAPPriceProvider) poolPrice(
+	ctx context.Context,
+	pool common.Address,
+	wanted map[common.Address][]string,
+) (float64, common.Address, error) {
+	pair, err := p.client.poolPair(ctx, pool)
+	if err != nil {
+		return 0, common.Address{}, err
+	}
+
+	// Index of the priced token within the pair
+	var priced int
+	switch {
+	case p.stablecoins[pair[1]]:
+		priced = 0
+	case p.stablecoins[pair[0]]:
+		priced = 1
+	default:
+		return 0, common.Address{}, fmt.Errorf("pool doesn't pair a stablecoin")
+	}
+	token := pair[priced]
+	if _, ok := wanted[token]; !ok {
+		return 0, common.Address{}, nil
+	}
+
+	tick, err := p.averageTick(ctx, pool)
+	if err != nil {
+		return 0, common.Address{}, err
+	}
+
+	var decimals [2]int
+	for i, address := range pair {
+		metadata, err := p.client.tokens.GetTokenMetadata(ctx, address.Hex())
+		if err != nil {
+			return 0, common.Address{}, err
+		}
+		if !metadata.HasDecimals {
+			return 0, common.Address{}, fmt.Errorf("unknown decimals of %s", address.Hex())
+		}
+		decimals[i] = int(metadata.Decimals)
+	}
+
+	// Price of token0 in units of token1
+	price := math.Pow(1.0001, tick) * math.Pow10(decimals[0]-decimals[1])
+	if priced == 1 {
+		price = 1 / price
+	}
+	if math.IsInf(price, 0) || math.IsNaN(price) {
+		return 0, common.Address{}, fmt.Errorf("price out of range at tick %f", tick)
+	}
+
+	return price, token, nil
+}
+
+// averageTick returns the pool's mean tick over the provider's window
+func (p *TWAPPriceProvider) averageTick(ctx context.Context, pool common.Address) (float64, error) {
+	data := make([]byte, 0, 4+4*32)
+	data = append(data, observeSelector...)
+	data = append(data, common.LeftPadBytes(big.NewInt(32).Bytes(), 32)...) // Offset of the array
+	data = append(data, common.LeftPadBytes(big.NewInt(2).Bytes(), 32)...)  // Length
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(p.window)).Bytes(), 32)...)
+	data = append(data, make([]byte, 32)...) // Now
+
+	result, err := p.client.callContract(ctx, ethereum.CallMsg{To: &pool, Data: data})
+	if err != nil {
+		return 0, fmt.Errorf("failed to call observe: %w", err)
+	}
+
+	// Returns (int56[] tickCumulatives, uint160[] secondsPerLiquidityCumulativeX128s)
+	if len(result) < 64 {
+		return 0, fmt.Errorf("unexpected observe result of %d bytes", len(result))
+	}
+	offset := new(big.Int).SetBytes(result[:32])
+	if !offset.IsUint64() || offset.Uint64()+3*32 > uint64(len(result)) {
+		return 0, fmt.Errorf("malformed observe result")
+	}
+	start := offset.Uint64()
+	if new(big.Int).SetBytes(result[start:start+32]).Cmp(big.NewInt(2)) != 0 {
+		return 0, fmt.Errorf("malformed observe result")
+	}
+
+	past := signedWord(result[start+32 : start+64])
+	now := signedWord(result[start+64 : start+96])
+	delta, _ := new(big.Float).SetInt(new(big.Int).Sub(now, past)).Float64()
+
+	return delta / float64(p.window), nil
+}
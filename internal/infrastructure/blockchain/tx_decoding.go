@@ -0,0 +1,309 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// senderOf recovers the transaction sender with the latest signer, so every
+// transaction type known to go-ethereum resolves. It never panics.
+func (pc *PlasmaClient) senderOf(tx *types.Transaction) (from common.Address, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sender recovery panicked: %v", r)
+		}
+	}()
+
+	return types.Sender(pc.signer, tx)
+}
+
+// txTypeOf maps the envelope type of tx to its domain representation
+func txTypeOf(tx *types.Transaction) domain.TransactionType {
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return domain.LegacyTxType
+	case types.AccessListTxType:
+		return domain.AccessListTxType
+	case types.DynamicFeeTxType:
+		return domain.DynamicFeeTxType
+	case types.BlobTxType:
+		return domain.BlobTxType
+	case types.SetCodeTxType:
+		return domain.SetCodeTxType
+	default:
+		return domain.UnknownTxType
+	}
+}
+
+// txStatusOf maps the receipt status to its domain representation
+func txStatusOf(receipt *types.Receipt) domain.TransactionStatus {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return domain.TxSuccess
+	}
+	return domain.TxReverted
+}
+
+// effectiveGasPrice is what tx was charged per gas: as its receipt reports,
+// else for EIP-1559 transactions the base fee plus the tip their fee caps
+// leave room for, and the gas price of the others
+func effectiveGasPrice(tx *types.Transaction, receipt *types.Receipt, baseFee *big.Int) *big.Int {
+	if receipt.EffectiveGasPrice != nil {
+		return receipt.EffectiveGasPrice
+	}
+	if baseFee == nil {
+		return tx.GasPrice()
+	}
+	// A mined transaction's fee cap covers the base fee
+	tip, err := tx.EffectiveGasTip(baseFee)
+	if err != nil {
+		return tx.GasPrice()
+	}
+	return new(big.Int).Add(baseFee, tip)
+}
+
+// revertReason decodes why a reverted transaction failed. With debug tracing
+// it is taken from the transaction's own call trace. Otherwise the
+// transaction is replayed with eth_call against the parent block's state,
+// which misses the transactions before it in the block, so such a reason is
+// reported as replayed: a guess rather than the actual cause. Nodes without
+// historical state, or reverts without a message, give "".
+func (pc *PlasmaClient) revertReason(
+	ctx context.Context,
+	tx *types.Transaction,
+	from common.Address,
+	blockNumber uint64,
+) (reason string, replayed bool) {
+	if pc.cfg.TraceMode == traceModeDebug {
+		var frame callFrame
+		err := pc.traceCall(ctx, &frame, "debug_traceTransaction", tx.Hash(), callTracerConfig)
+		if err == nil {
+			return frameRevertReason(frame), false
+		}
+		pc.logger.Debug("Failed to trace reverted transaction, replaying it",
+			zap.String("tx_hash", tx.Hash().Hex()),
+			zap.Error(err))
+	}
+
+	if blockNumber == 0 {
+		return "", false
+	}
+
+	// Fee fields are left out so the replay isn't rejected over gas pricing
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+
+	_, err := pc.callContractAt(ctx, msg, new(big.Int).SetUint64(blockNumber-1))
+	if err == nil {
+		return "", false
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if reason, err := abi.UnpackRevert(common.FromHex(data)); err == nil {
+				return reason, true
+			}
+		}
+	}
+
+	reason, found := strings.CutPrefix(err.Error(), "execution reverted: ")
+	if !found {
+		return "", false
+	}
+	return reason, true
+}
+
+// frameRevertReason is the revert message of a traced top-level call: as the
+// tracer decoded it, else unpacked from the call's output
+func frameRevertReason(frame callFrame) string {
+	if frame.RevertReason != "" {
+		return frame.RevertReason
+	}
+	if reason, err := abi.UnpackRevert(frame.Output); err == nil {
+		return reason
+	}
+	return ""
+}
+
+// opaqueTx is a typed transaction the client cannot decode, kept with the
+// fields nodes report for every type so it is still matched and notified
+type opaqueTx struct {
+	Hash     common.Hash     `json:"hash"`
+	Type     hexutil.Uint64  `json:"type"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Value    *hexutil.Big    `json:"value"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Input    hexutil.Bytes   `json:"input"`
+}
+
+func (o *opaqueTx) value() *big.Int {
+	if o.Value == nil {
+		return new(big.Int)
+	}
+	return o.Value.ToInt()
+}
+
+// directAddresses returns the sender and recipient of the transaction
+func (o *opaqueTx) directAddresses() []common.Address {
+	addresses := []common.Address{o.From}
+	if o.To != nil {
+		addresses = append(addresses, *o.To)
+	}
+	return addresses
+}
+
+// createOpaqueTransaction builds the domain transaction of an undecodable
+// one from its envelope fields. Its type is unknown, with the raw type byte
+// kept.
+func (pc *PlasmaClient) createOpaqueTransaction(
+	otx *opaqueTx,
+	receipt *types.Receipt,
+	blockTime uint64,
+) domain.Transaction {
+	from := domain.WalletAddress(otx.From.Hex())
+	toAddr := ""
+	if otx.To != nil {
+		toAddr = otx.To.Hex()
+	}
+
+	var gasPrice *big.Int
+	if otx.GasPrice != nil {
+		gasPrice = otx.GasPrice.ToInt()
+	}
+	pricePaid := receipt.EffectiveGasPrice
+	if pricePaid == nil {
+		pricePaid = gasPrice
+	}
+	var fee *big.Int
+	if pricePaid != nil {
+		fee = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), pricePaid)
+	}
+	sponsor := pc.sponsorshipOf(otx.Input, receipt, from)
+
+	return domain.Transaction{
+		Hash:         domain.TransactionHash(otx.Hash.Hex()),
+		Type:         domain.UnknownTxType,
+		RawType:      uint8(otx.Type),
+		Kind:         txKindOf(otx.To, otx.Input),
+		Status:       txStatusOf(receipt),
+		From:         from,
+		To:           domain.WalletAddress(toAddr),
+		Contract:     createdContract(otx.To, receipt),
+		Method:       pc.methodOf(otx.Hash, otx.To, otx.Input),
+		Nonce:        uint64(otx.Nonce),
+		BlockNumber:  receipt.BlockNumber.Uint64(),
+		Timestamp:    time.Unix(int64(blockTime), 0),
+		GasUsed:      receipt.GasUsed,
+		GasPrice:     gasPrice,
+		GasPricePaid: pricePaid,
+		Fee:          fee,
+		Gasless:      sponsor.gasless,
+		Paymaster:    sponsor.paymaster,
+		FeePayer:     sponsor.feePayer,
+		Transfers:    pc.extractAllTransfers(otx.Hash, otx.To, otx.value(), receipt, from),
+		Approvals:    extractApprovals(otx.Hash, receipt),
+	}
+}
+
+// decodedBlock is a block with the transactions the client could not
+// decode kept aside
+type decodedBlock struct {
+	*types.Block
+	opaque []opaqueTx
+}
+
+// getBlock fetches a block by hash. If the block contains transaction types
+// the client cannot decode, it is re-fetched raw and the undecodable
+// transactions are kept aside instead of failing the whole block.
+func (pc *PlasmaClient) getBlock(ctx context.Context, hash common.Hash) (*decodedBlock, error) {
+	if block, ok := pc.blocks.get(hash); ok {
+		return block, nil
+	}
+
+	fetched, err := withRetry(ctx, pc, "block", func(ctx context.Context, c *ethclient.Client) (*types.Block, error) {
+		return c.BlockByHash(ctx, hash)
+	})
+	if err == nil {
+		block := &decodedBlock{Block: fetched}
+		pc.blocks.add(hash, block)
+		return block, nil
+	}
+	if !errors.Is(err, types.ErrTxTypeNotSupported) {
+		return nil, err
+	}
+
+	pc.logger.Warn("Block contains unsupported transaction types, decoding leniently",
+		zap.String("hash", hash.Hex()))
+
+	header, err := pc.headerByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	type rawBlock struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	raw, err := withRetry(ctx, pc, "block", func(ctx context.Context, c *ethclient.Client) (rawBlock, error) {
+		var raw rawBlock
+		err := c.Client().CallContext(ctx, &raw, "eth_getBlockByHash", hash, true)
+		return raw, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*types.Transaction, 0, len(raw.Transactions))
+	var opaque []opaqueTx
+	for _, data := range raw.Transactions {
+		tx := new(types.Transaction)
+		err := tx.UnmarshalJSON(data)
+		if err == nil {
+			txs = append(txs, tx)
+			continue
+		}
+
+		var otx opaqueTx
+		if envelopeErr := json.Unmarshal(data, &otx); envelopeErr != nil || otx.Hash == (common.Hash{}) {
+			pc.logger.Warn("Skipping undecodable transaction",
+				zap.String("tx_hash", otx.Hash.Hex()),
+				zap.Error(errors.Join(err, envelopeErr)))
+			continue
+		}
+		pc.logger.Warn("Keeping undecodable transaction as unknown type",
+			zap.String("tx_hash", otx.Hash.Hex()),
+			zap.Uint64("type", uint64(otx.Type)),
+			zap.Error(err))
+		opaque = append(opaque, otx)
+	}
+
+	block := &decodedBlock{
+		Block:  types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs}),
+		opaque: opaque,
+	}
+	pc.blocks.add(hash, block)
+	return block, nil
+}
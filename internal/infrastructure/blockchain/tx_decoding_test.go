@@ -0,0 +1,217 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+const testChainID = 9745
+
+func TestSenderOfRecoversEveryTransactionType(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	chainID := big.NewInt(testChainID)
+
+	tests := []struct {
+		name   string
+		data   types.TxData
+		txType domain.TransactionType
+	}{
+		{
+			name: "legacy",
+			data: &types.LegacyTx{
+				Nonce:    1,
+				GasPrice: big.NewInt(1e9),
+				Gas:      21000,
+				To:       &to,
+				Value:    big.NewInt(1),
+			},
+			txType: domain.LegacyTxType,
+		},
+		{
+			name: "access list",
+			data: &types.AccessListTx{
+				ChainID:  chainID,
+				Nonce:    2,
+				GasPrice: big.NewInt(1e9),
+				Gas:      30000,
+				To:       &to,
+				Value:    big.NewInt(1),
+				AccessList: types.AccessList{
+					{Address: to, StorageKeys: []common.Hash{{1}}},
+				},
+			},
+			txType: domain.AccessListTxType,
+		},
+		{
+			name: "dynamic fee",
+			data: &types.DynamicFeeTx{
+				ChainID:   chainID,
+				Nonce:     3,
+				GasTipCap: big.NewInt(1e9),
+				GasFeeCap: big.NewInt(2e9),
+				Gas:       21000,
+				To:        &to,
+				Value:     big.NewInt(1),
+			},
+			txType: domain.DynamicFeeTxType,
+		},
+		{
+			name: "blob",
+			data: &types.BlobTx{
+				ChainID:    uint256.NewInt(testChainID),
+				Nonce:      4,
+				GasTipCap:  uint256.NewInt(1e9),
+				GasFeeCap:  uint256.NewInt(2e9),
+				Gas:        21000,
+				To:         to,
+				Value:      uint256.NewInt(1),
+				BlobFeeCap: uint256.NewInt(1e9),
+				BlobHashes: []common.Hash{{0x01}}, // Version 1 hash
+			},
+			txType: domain.BlobTxType,
+		},
+		{
+			name: "set code",
+			data: &types.SetCodeTx{
+				ChainID:   uint256.NewInt(testChainID),
+				Nonce:     5,
+				GasTipCap: uint256.NewInt(1e9),
+				GasFeeCap: uint256.NewInt(2e9),
+				Gas:       50000,
+				To:        to,
+				Value:     uint256.NewInt(0),
+				AuthList:  []types.SetCodeAuthorization{{ChainID: *uint256.NewInt(testChainID), Address: to}},
+			},
+			txType: domain.SetCodeTxType,
+		},
+	}
+
+	pc := &PlasmaClient{signer: types.LatestSignerForChainID(chainID)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := types.SignNewTx(key, pc.signer, tt.data)
+			if err != nil {
+				t.Fatalf("failed to sign transaction: %v", err)
+			}
+
+			from, err := pc.senderOf(tx)
+			if err != nil {
+				t.Fatalf("senderOf() error = %v", err)
+			}
+			if from != sender {
+				t.Errorf("senderOf() = %s, want %s", from.Hex(), sender.Hex())
+			}
+			if got := txTypeOf(tx); got != tt.txType {
+				t.Errorf("txTypeOf() = %q, want %q", got, tt.txType)
+			}
+		})
+	}
+}
+
+func TestSenderOfFailsWithoutValidSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	pc := &PlasmaClient{signer: types.LatestSignerForChainID(big.NewInt(testChainID))}
+
+	// Signed for another chain
+	foreign, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       21000,
+		To:        &to,
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if _, err := pc.senderOf(foreign); err == nil {
+		t.Error("senderOf() of a transaction for another chain succeeded")
+	}
+
+	// Never signed
+	unsigned := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(testChainID),
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       21000,
+		To:        &to,
+	})
+	if _, err := pc.senderOf(unsigned); err == nil {
+		t.Error("senderOf() of an unsigned transaction succeeded")
+	}
+}
+
+func TestUnknownTransactionTypeIsNotDecoded(t *testing.T) {
+	var tx types.Transaction
+	err := tx.UnmarshalBinary([]byte{0x7f, 0xc0})
+	if !errors.Is(err, types.ErrTxTypeNotSupported) {
+		t.Fatalf("UnmarshalBinary() error = %v, want %v", err, types.ErrTxTypeNotSupported)
+	}
+}
+
+func TestUndecodableTransactionKeepsRawType(t *testing.T) {
+	data := []byte(`{
+		"hash": "0x1111111111111111111111111111111111111111111111111111111111111111",
+		"type": "0x7f",
+		"from": "0x52908400098527886E0F7030069857D2E4169EE7",
+		"to": "0x00000000000000000000000000000000000000aa",
+		"nonce": "0x2",
+		"value": "0x64",
+		"gasPrice": "0x3b9aca00",
+		"input": "0x"
+	}`)
+
+	var tx types.Transaction
+	if err := tx.UnmarshalJSON(data); err == nil {
+		t.Fatal("UnmarshalJSON() of an unknown type succeeded")
+	}
+
+	var otx opaqueTx
+	if err := json.Unmarshal(data, &otx); err != nil {
+		t.Fatalf("failed to decode envelope: %v", err)
+	}
+
+	pc := &PlasmaClient{}
+	receipt := &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		BlockNumber: big.NewInt(10),
+		GasUsed:     21000,
+	}
+	got := pc.createOpaqueTransaction(&otx, receipt, 0)
+
+	if got.Type != domain.UnknownTxType {
+		t.Errorf("Type = %q, want %q", got.Type, domain.UnknownTxType)
+	}
+	if got.RawType != 0x7f {
+		t.Errorf("RawType = %#x, want 0x7f", got.RawType)
+	}
+	if got.From != "0x52908400098527886E0F7030069857D2E4169EE7" {
+		t.Errorf("From = %s, want the envelope sender", got.From)
+	}
+	if got.Nonce != 2 {
+		t.Errorf("Nonce = %d, want 2", got.Nonce)
+	}
+	if len(got.Transfers) != 1 || got.Transfers[0].Value.Int64() != 100 {
+		t.Errorf("Transfers = %+v, want the native transfer of 100 wei", got.Transfers)
+	}
+	if got.Fee == nil || got.Fee.Cmp(big.NewInt(21000*1e9)) != 0 {
+		t.Errorf("Fee = %v, want %d", got.Fee, int64(21000*1e9))
+	}
+}
@@ -0,0 +1,188 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/config"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// Error responses are read up to this size into the returned error
+const maxErrorBody = 4 << 10
+
+// Database and table names are spliced into queries, so only plain
+// identifiers are accepted
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Rows of a block that was reorged out stay, told apart by block_hash.
+// Blocks retried after a failure write their rows again; they are merged
+// away in the background, FINAL collapses them at query time.
+const createTable = `CREATE TABLE IF NOT EXISTS %s (
+	block_number    UInt64,
+	block_hash      String,
+	block_time      DateTime,
+	tx_hash         String,
+	position        UInt32,
+	log_index       Int32,
+	internal        Bool,
+	from_address    String,
+	to_address      String,
+	token_address   LowCardinality(String),
+	token_symbol    LowCardinality(String),
+	token_standard  LowCardinality(String),
+	value           UInt256,
+	formatted_value String,
+	token_id        Nullable(UInt256)
+)
+ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(block_time)
+ORDER BY (block_number, block_hash, tx_hash, position)`
+
+// transferRow is a transfer record as a JSONEachRow line
+type transferRow struct {
+	BlockNumber    uint64       `json:"block_number"`
+	BlockHash      string       `json:"block_hash"`
+	BlockTime      int64        `json:"block_time"`
+	TxHash         string       `json:"tx_hash"`
+	Position       int          `json:"position"`
+	LogIndex       int          `json:"log_index"`
+	Internal       bool         `json:"internal"`
+	From           string       `json:"from_address"`
+	To             string       `json:"to_address"`
+	TokenAddress   string       `json:"token_address"`
+	TokenSymbol    string       `json:"token_symbol"`
+	TokenStandard  string       `json:"token_standard"`
+	Value          json.Number  `json:"value"`
+	FormattedValue string       `json:"formatted_value"`
+	TokenID        *json.Number `json:"token_id"`
+}
+
+// TransferWriter inserts transfer records into a ClickHouse table over the
+// HTTP interface
+type TransferWriter struct {
+	client   *http.Client
+	endpoint string
+	table    string
+	cfg      config.ClickHouseConfig
+}
+
+// NewTransferWriter checks the server is reachable and creates the table
+// if it doesn't exist
+func NewTransferWriter(ctx context.Context, cfg config.ClickHouseConfig) (*TransferWriter, error) {
+	endpoint, err := url.Parse(cfg.URL)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid clickhouse url %q", cfg.URL)
+	}
+	for _, name := range []string{cfg.Database, cfg.Table} {
+		if !identifier.MatchString(name) {
+			return nil, fmt.Errorf("invalid clickhouse identifier %q", name)
+		}
+	}
+
+	w := &TransferWriter{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		endpoint: strings.TrimSuffix(endpoint.String(), "/") + "/",
+		table:    fmt.Sprintf("`%s`.`%s`", cfg.Database, cfg.Table),
+		cfg:      cfg,
+	}
+
+	if err := w.exec(ctx, nil, strings.NewReader(fmt.Sprintf(createTable, w.table))); err != nil {
+		return nil, fmt.Errorf("failed to create clickhouse table: %w", err)
+	}
+	return w, nil
+}
+
+// WriteTransfers inserts records as one batch
+func (w *TransferWriter) WriteTransfers(ctx context.Context, records []domain.TransferRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, record := range records {
+		if err := encoder.Encode(newTransferRow(record)); err != nil {
+			return fmt.Errorf("failed to encode transfer: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("query", fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", w.table))
+	if w.cfg.AsyncInsert {
+		params.Set("async_insert", "1")
+		params.Set("wait_for_async_insert", "1")
+	}
+
+	if err := w.exec(ctx, params, &body); err != nil {
+		return fmt.Errorf("failed to insert transfers: %w", err)
+	}
+	return nil
+}
+
+// exec POSTs body to the server, which runs it as the query or as the data
+// of the query in params
+func (w *TransferWriter) exec(ctx context.Context, params url.Values, body io.Reader) error {
+	endpoint := w.endpoint
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-ClickHouse-User", w.cfg.Username)
+	if w.cfg.Password != "" {
+		req.Header.Set("X-ClickHouse-Key", w.cfg.Password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("clickhouse returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
+
+func newTransferRow(record domain.TransferRecord) transferRow {
+	transfer := record.Transfer
+
+	row := transferRow{
+		BlockNumber:    record.BlockNumber,
+		BlockHash:      record.BlockHash,
+		BlockTime:      record.BlockTime.Unix(),
+		TxHash:         string(transfer.TxHash),
+		Position:       record.Position,
+		LogIndex:       transfer.LogIndex,
+		Internal:       transfer.Internal,
+		From:           string(transfer.From),
+		To:             string(transfer.To),
+		TokenAddress:   transfer.TokenAddress,
+		TokenSymbol:    transfer.TokenSymbol,
+		TokenStandard:  string(transfer.TokenStandard),
+		Value:          "0",
+		FormattedValue: transfer.FormattedValue,
+	}
+	if transfer.Value != nil {
+		row.Value = json.Number(transfer.Value.String())
+	}
+	if transfer.TokenID != nil {
+		tokenID := json.Number(transfer.TokenID.String())
+		row.TokenID = &tokenID
+	}
+	return row
+}
@@ -0,0 +1,139 @@
+package codec
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// cloudEvent is a CloudEvents 1.0 event in structured JSON mode. Traceparent
+// and Tracestate are the distributed tracing extension.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      string          `json:"data_base64,omitempty"`
+	Traceparent     string          `json:"traceparent,omitempty"`
+	Tracestate      string          `json:"tracestate,omitempty"`
+}
+
+// CloudEvents wraps the messages the wrapped codec encodes in a CloudEvents
+// 1.0 envelope. The event type is typePrefix.<kind>, plus .<type> for
+// wallet notifications, e.g. "plasma.wallet_tracker.notification.swap",
+// and the subject is the wallet, contract or token the message is about.
+// Notification events keep their idempotency key as id, so redeliveries
+// can be dropped by id. Commands are decoded by the wrapped codec as is.
+type CloudEvents struct {
+	Codec
+
+	source     string
+	typePrefix string
+}
+
+func NewCloudEvents(codec Codec, source, typePrefix string) CloudEvents {
+	return CloudEvents{
+		Codec:      codec,
+		source:     source,
+		typePrefix: typePrefix,
+	}
+}
+
+func (c CloudEvents) Name() string        { return "cloudevents+" + c.Codec.Name() }
+func (c CloudEvents) ContentType() string { return "application/cloudevents+json" }
+
+func (c CloudEvents) Marshal(v any) ([]byte, error) {
+	data, err := c.Codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	event := cloudEvent{
+		SpecVersion:     "1.0",
+		Source:          c.source,
+		DataContentType: c.Codec.ContentType(),
+	}
+	if err := describeEvent(&event, v); err != nil {
+		return nil, err
+	}
+	event.Type = c.typePrefix + "." + event.Type
+	if event.ID == "" {
+		event.ID = randomID()
+	}
+	if _, isJSON := c.Codec.(JSON); isJSON {
+		event.Data = data
+	} else {
+		event.DataBase64 = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return json.Marshal(event)
+}
+
+// describeEvent sets the type, id, subject, time and trace of the event
+// carrying v
+func describeEvent(event *cloudEvent, v any) error {
+	var (
+		timestamp time.Time
+		trace     *domain.TraceContext
+	)
+	switch v := v.(type) {
+	case domain.WalletNotification:
+		event.Type = "notification." + string(v.Type)
+		event.ID = v.IdempotencyKey
+		event.Subject = string(v.WalletAddress)
+		timestamp, trace = v.Timestamp, v.Trace
+	case domain.AlertNotification:
+		event.Type = "alert"
+		event.Subject = string(v.WalletAddress)
+		timestamp, trace = v.Timestamp, v.Trace
+	case domain.ContractEventNotification:
+		event.Type = "contract_event"
+		event.Subject = string(v.Event.ContractAddress)
+		timestamp, trace = v.Timestamp, v.Trace
+	case domain.TokenTransferNotification:
+		event.Type = "token_transfer"
+		event.Subject = v.Event.Transfer.TokenAddress
+		timestamp, trace = v.Timestamp, v.Trace
+	case domain.FirehoseBlock:
+		event.Type = "firehose"
+		event.ID = v.BlockHash
+		event.Subject = strconv.FormatUint(v.BlockNumber, 10)
+		timestamp, trace = v.Timestamp, v.Trace
+	case domain.CommandResponse:
+		event.Type = "response"
+		event.Subject = strconv.FormatInt(int64(v.UserID), 10)
+		timestamp, trace = v.Timestamp, v.Trace
+	case domain.CommandResult:
+		event.Type = "command_result"
+		event.Subject = v.CorrelationID
+		timestamp = v.Timestamp
+	default:
+		return fmt.Errorf("no CloudEvents type for %T", v)
+	}
+
+	if !timestamp.IsZero() {
+		event.Time = &timestamp
+	}
+	if trace != nil {
+		event.Traceparent = trace.Traceparent
+		event.Tracestate = trace.Tracestate
+	}
+	return nil
+}
+
+// randomID returns a random event id
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
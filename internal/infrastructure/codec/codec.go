@@ -0,0 +1,31 @@
+// Package codec encodes the messages published to and decodes the commands
+// received from Redis and NATS
+package codec
+
+import "fmt"
+
+// Codec is a wire format of published messages and received commands
+type Codec interface {
+	// Name is the codec's configuration name, e.g. "json"
+	Name() string
+	// ContentType is the MIME type of encoded messages
+	ContentType() string
+
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// New returns the codec called name: "json", "msgpack" or "protobuf".
+// legacyAmounts only applies to JSON, see JSON.LegacyAmounts.
+func New(name string, legacyAmounts bool) (Codec, error) {
+	switch name {
+	case "json":
+		return JSON{LegacyAmounts: legacyAmounts}, nil
+	case "msgpack":
+		return MsgPack{}, nil
+	case "protobuf":
+		return Protobuf{}, nil
+	default:
+		return nil, fmt.Errorf("unknown codec %q", name)
+	}
+}
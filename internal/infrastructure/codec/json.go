@@ -0,0 +1,31 @@
+package codec
+
+import (
+	"encoding/json"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// JSON encodes messages as JSON. Token amounts are decimal strings, unless
+// LegacyAmounts is set.
+type JSON struct {
+	// LegacyAmounts encodes amounts as JSON numbers, which consumers
+	// parsing them as doubles may round, and notifications as schema
+	// version 1
+	LegacyAmounts bool
+}
+
+func (JSON) Name() string        { return "json" }
+func (JSON) ContentType() string { return "application/json" }
+
+func (c JSON) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || !c.LegacyAmounts {
+		return data, err
+	}
+	return domain.LegacyAmounts(data)
+}
+
+func (JSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
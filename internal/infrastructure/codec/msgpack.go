@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MsgPack encodes messages as MessagePack maps keyed like the JSON fields.
+// Token amounts are decimal strings, times MessagePack timestamps, and maps
+// keyed by user ID, such as wallet_labels, have integer keys.
+type MsgPack struct{}
+
+func (MsgPack) Name() string        { return "msgpack" }
+func (MsgPack) ContentType() string { return "application/msgpack" }
+
+func (MsgPack) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgPack) Unmarshal(data []byte, v any) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
@@ -0,0 +1,63 @@
+package codec
+
+import (
+	"fmt"
+
+	wirev1 "github.com/say8hi/plasma-wallet-tracker/api/proto/wire/v1"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/protoconv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Protobuf encodes messages as the wire.v1 messages of api/proto/wire.
+// It decodes commands only, the one message the tracker receives.
+type Protobuf struct{}
+
+func (Protobuf) Name() string        { return "protobuf" }
+func (Protobuf) ContentType() string { return "application/x-protobuf" }
+
+func (Protobuf) Marshal(v any) ([]byte, error) {
+	var (
+		message proto.Message
+		err     error
+	)
+	switch v := v.(type) {
+	case domain.WalletNotification:
+		message = protoconv.WireNotification(v)
+	case domain.CommandResponse:
+		message, err = protoconv.WireCommandResponse(v)
+	case domain.CommandResult:
+		message, err = protoconv.WireCommandResult(v)
+	case domain.ContractEventNotification:
+		message, err = protoconv.WireContractEvent(v)
+	case domain.TokenTransferNotification:
+		message = protoconv.WireTokenTransfer(v)
+	case domain.AlertNotification:
+		message = protoconv.WireAlert(v)
+	case domain.FirehoseBlock:
+		message = protoconv.WireFirehose(v)
+	case domain.Command:
+		message = protoconv.WireCommand(v)
+	default:
+		return nil, fmt.Errorf("protobuf codec can't encode %T", v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(message)
+}
+
+func (Protobuf) Unmarshal(data []byte, v any) error {
+	cmd, ok := v.(*domain.Command)
+	if !ok {
+		return fmt.Errorf("protobuf codec can't decode %T", v)
+	}
+
+	var message wirev1.Command
+	if err := proto.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	*cmd = protoconv.CommandFromWire(&message)
+	return nil
+}
@@ -0,0 +1,90 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// Longest audit entry line read back; import_labels commands can be large
+const maxAuditLineSize = 16 << 20
+
+// AuditLog appends audit entries to a file as JSON lines. Queries scan the
+// whole file, so it suits deployments without Redis persistence rather
+// than long histories.
+type AuditLog struct {
+	path string
+	file *os.File
+	seq  uint64
+	mu   sync.Mutex
+}
+
+// NewAuditLog opens path for appending, creating it if needed
+func NewAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLog{path: path, file: file}, nil
+}
+
+func (l *AuditLog) Append(ctx context.Context, entry domain.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	entry.ID = fmt.Sprintf("%d-%d", entry.Timestamp.UnixMilli(), l.seq)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+func (l *AuditLog) Query(ctx context.Context, query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []domain.AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxAuditLineSize)
+	for scanner.Scan() {
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if query.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	// Newest first
+	slices.Reverse(entries)
+	if len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
+
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
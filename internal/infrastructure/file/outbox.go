@@ -0,0 +1,168 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// Longest outbox record read back; notifications carry whole transactions
+const maxOutboxLineSize = 16 << 20
+
+// Outbox appends entries to a file as JSON lines, synced to disk before Add
+// returns, along with the IDs of removed ones. The file is rewritten with
+// the entries left when opened and emptied whenever the outbox is. Entries
+// are also held in memory, so the file belongs to one process.
+type Outbox struct {
+	file    *os.File
+	pending []domain.OutboxEntry
+	mu      sync.Mutex
+}
+
+// outboxRecord is a line of the file: an added entry or a removed ID
+type outboxRecord struct {
+	Entry   *domain.OutboxEntry `json:"entry,omitempty"`
+	Removed string              `json:"removed,omitempty"`
+}
+
+// NewOutbox opens the outbox at path, creating it if needed, with the
+// entries that weren't removed before a restart
+func NewOutbox(path string) (*Outbox, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %w", err)
+	}
+
+	o := &Outbox{file: file}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxOutboxLineSize)
+	for scanner.Scan() {
+		var record outboxRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // Torn by a crash while writing
+		}
+		if record.Entry != nil {
+			o.pending = append(o.pending, *record.Entry)
+		} else {
+			o.drop(record.Removed)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	if err := o.rewrite(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *Outbox) Add(ctx context.Context, entries ...domain.OutboxEntry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	records := make([]outboxRecord, len(entries))
+	for i := range entries {
+		records[i] = outboxRecord{Entry: &entries[i]}
+	}
+	if err := o.write(records...); err != nil {
+		return err
+	}
+	if err := o.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+	o.pending = append(o.pending, entries...)
+	return nil
+}
+
+func (o *Outbox) Oldest(ctx context.Context) (*domain.OutboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.pending) == 0 {
+		return nil, nil
+	}
+	entry := o.pending[0]
+	return &entry, nil
+}
+
+func (o *Outbox) Remove(ctx context.Context, id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.drop(id) {
+		return nil
+	}
+	if len(o.pending) == 0 {
+		if err := o.file.Truncate(0); err != nil {
+			return fmt.Errorf("failed to empty outbox: %w", err)
+		}
+		return nil
+	}
+	// Not synced: after a crash the entry is published again
+	return o.write(outboxRecord{Removed: id})
+}
+
+func (o *Outbox) Len(ctx context.Context) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.pending), nil
+}
+
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.file.Close()
+}
+
+// drop removes the pending entry with id and reports whether there was one
+func (o *Outbox) drop(id string) bool {
+	i := slices.IndexFunc(o.pending, func(entry domain.OutboxEntry) bool { return entry.ID == id })
+	if i < 0 {
+		return false
+	}
+	o.pending = slices.Delete(o.pending, i, i+1)
+	return true
+}
+
+// rewrite replaces the file's content with the pending entries
+func (o *Outbox) rewrite() error {
+	if err := o.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to rewrite outbox: %w", err)
+	}
+	for i := range o.pending {
+		if err := o.write(outboxRecord{Entry: &o.pending[i]}); err != nil {
+			return err
+		}
+	}
+	if err := o.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+	return nil
+}
+
+// write appends the records to the file in a single write
+func (o *Outbox) write(records ...outboxRecord) error {
+	var data []byte
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox record: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if _, err := o.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write outbox record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+
+	trackerv1 "github.com/say8hi/plasma-wallet-tracker/api/proto/tracker/v1"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/protoconv"
+	"github.com/say8hi/plasma-wallet-tracker/internal/usecase"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the WalletTracker gRPC service on top of the command
+// handler, so calls get the same validation, limits and auditing as
+// commands sent over Redis
+type Server struct {
+	trackerv1.UnimplementedWalletTrackerServer
+
+	commands *usecase.CommandHandler
+	hub      *usecase.NotificationHub
+	logger   *zap.Logger
+}
+
+// New returns a gRPC server serving the WalletTracker service to callers
+// presenting token. A nil tlsConfig serves plaintext.
+func New(
+	commands *usecase.CommandHandler,
+	hub *usecase.NotificationHub,
+	token string,
+	tlsConfig *tls.Config,
+	logger *zap.Logger,
+) *grpc.Server {
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(
+			ctx context.Context,
+			req any,
+			_ *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler,
+		) (any, error) {
+			if err := authenticate(ctx, token); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(
+			srv any,
+			ss grpc.ServerStream,
+			_ *grpc.StreamServerInfo,
+			handler grpc.StreamHandler,
+		) error {
+			if err := authenticate(ss.Context(), token); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(options...)
+	trackerv1.RegisterWalletTrackerServer(server, &Server{
+		commands: commands,
+		hub:      hub,
+		logger:   logger,
+	})
+	return server
+}
+
+func (s *Server) AddWallet(ctx context.Context, req *trackerv1.AddWalletRequest) (*trackerv1.AddWalletResponse, error) {
+	_, err := s.commands.Execute(ctx, domain.Command{
+		Type:          domain.AddWalletCommand,
+		UserID:        domain.UserID(req.GetUserId()),
+		WalletAddress: domain.WalletAddress(req.GetWalletAddress()),
+		Label:         req.GetLabel(),
+		Filters:       protoconv.FilterFromProto(req.GetFilters()),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &trackerv1.AddWalletResponse{}, nil
+}
+
+func (s *Server) RemoveWallet(ctx context.Context, req *trackerv1.RemoveWalletRequest) (*trackerv1.RemoveWalletResponse, error) {
+	_, err := s.commands.Execute(ctx, domain.Command{
+		Type:          domain.RemoveWalletCommand,
+		UserID:        domain.UserID(req.GetUserId()),
+		WalletAddress: domain.WalletAddress(req.GetWalletAddress()),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &trackerv1.RemoveWalletResponse{}, nil
+}
+
+func (s *Server) ListWallets(ctx context.Context, req *trackerv1.ListWalletsRequest) (*trackerv1.ListWalletsResponse, error) {
+	data, err := s.commands.Execute(ctx, domain.Command{
+		Type:   domain.ListWalletsCommand,
+		UserID: domain.UserID(req.GetUserId()),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	subscriptions, _ := data.([]domain.WalletSubscription)
+	response := &trackerv1.ListWalletsResponse{
+		Subscriptions: make([]*trackerv1.WalletSubscription, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		response.Subscriptions = append(response.Subscriptions, protoconv.Subscription(subscription))
+	}
+	return response, nil
+}
+
+// Subscribe sends the user's notifications until the client cancels, the
+// stream falls too far behind or the server shuts down
+func (s *Server) Subscribe(req *trackerv1.SubscribeRequest, stream trackerv1.WalletTracker_SubscribeServer) error {
+	userID := domain.UserID(req.GetUserId())
+	notifications := s.hub.Subscribe(userID)
+	defer notifications.Close()
+
+	for {
+		select {
+		case event, ok := <-notifications.C():
+			if !ok {
+				if err := notifications.Err(); errors.Is(err, domain.ErrSlowConsumer) {
+					return status.Error(codes.ResourceExhausted, err.Error())
+				}
+				return status.Error(codes.Unavailable, "server shutting down")
+			}
+			if err := stream.Send(protoconv.Notification(event.Notification, userID)); err != nil {
+				s.logger.Debug("Failed to send notification to gRPC stream",
+					zap.Int64("user_id", int64(userID)),
+					zap.Error(err),
+				)
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// authenticate checks the bearer token in the call's metadata
+func authenticate(ctx context.Context, token string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		given, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+}
+
+// statusError maps command errors to gRPC status codes
+func statusError(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, domain.ErrInvalidAddress),
+		errors.Is(err, domain.ErrInvalidFilter),
+		errors.Is(err, domain.ErrInvalidLabel),
+		errors.Is(err, domain.ErrInvalidDuration),
+		errors.Is(err, domain.ErrInvalidLimit):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrWalletNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrSubscriptionExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrQuotaExceeded), errors.Is(err, domain.ErrLimitExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, domain.ErrForbidden):
+		code = codes.PermissionDenied
+	default:
+		return status.Error(codes.Internal, fmt.Sprintf("internal error: %v", err))
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,84 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/config"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// Client is a NATS connection with JetStream and the stream all messages
+// are kept in
+type Client struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	cfg    config.NATSConfig
+	logger *zap.Logger
+}
+
+// NewClient connects to NATS and creates the stream or updates it to cfg.
+// The connection reconnects by itself for as long as the client is open.
+func NewClient(ctx context.Context, cfg config.NATSConfig, logger *zap.Logger) (*Client, error) {
+	options := []nats.Option{
+		nats.Name("plasma-wallet-tracker"),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil { // Nil when closed on purpose
+				logger.Warn("Disconnected from NATS", zap.Error(err))
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("Reconnected to NATS", zap.String("url", conn.ConnectedUrlRedacted()))
+		}),
+	}
+	if cfg.CredsFile != "" {
+		options = append(options, nats.UserCredentials(cfg.CredsFile))
+	}
+
+	conn, err := nats.Connect(cfg.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      cfg.Stream,
+		Subjects:  []string{cfg.SubjectPrefix + ".>"},
+		Retention: jetstream.LimitsPolicy,
+		Storage:   jetstream.FileStorage,
+		MaxAge:    cfg.MaxAge,
+		Replicas:  cfg.Replicas,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", cfg.Stream, err)
+	}
+
+	return &Client{
+		conn:   conn,
+		js:     js,
+		cfg:    cfg,
+		logger: logger,
+	}, nil
+}
+
+// subject is the subject messages for channel are published to
+func (c *Client) subject(channel string) string {
+	return c.cfg.SubjectPrefix + "." + channel
+}
+
+// Close flushes acknowledgements not yet sent, then closes the connection
+func (c *Client) Close() error {
+	err := c.conn.Flush()
+	c.conn.Close()
+	return err
+}
@@ -0,0 +1,208 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/routing"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Publisher publishes messages to JetStream. A publish succeeds only once
+// the stream has stored the message, so consumers receive it at least once
+// however long they were away, within the stream's MaxAge.
+type Publisher struct {
+	client          *Client
+	channels        routing.Channels
+	responseSubject string
+	contractSubject string
+	tokenSubject    string
+	alertSubject    string
+	firehoseSubject string
+	codec           codec.Codec
+	logger          *zap.Logger
+}
+
+// NewPublisher returns a publisher to the subjects of channels
+func NewPublisher(client *Client, channels routing.Channels, codec codec.Codec, logger *zap.Logger) *Publisher {
+	return &Publisher{
+		client:          client,
+		channels:        channels,
+		responseSubject: client.subject(channels.Responses),
+		contractSubject: client.subject(channels.ContractEvents),
+		tokenSubject:    client.subject(channels.TokenTransfers),
+		alertSubject:    client.subject(channels.Alerts),
+		firehoseSubject: client.subject(channels.Firehose),
+		codec:           codec,
+		logger:          logger,
+	}
+}
+
+// PublishNotification publishes notification to the subject of each
+// channel it is routed to, trying them all even if some fail
+func (p *Publisher) PublishNotification(
+	ctx context.Context,
+	notification domain.WalletNotification,
+) error {
+	var errs []error
+	for _, route := range p.channels.Notification(notification) {
+		subject := p.client.subject(route.Channel)
+		seq, err := p.publish(ctx, "notification", subject, route.Notification)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		p.logger.Debug("Published notification",
+			zap.String("subject", subject),
+			zap.Uint64("seq", seq),
+			zap.String("wallet", string(notification.WalletAddress)),
+			zap.Int("subscribers", len(route.Notification.Subscribers)),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Publisher) PublishResponse(ctx context.Context, response domain.CommandResponse) error {
+	seq, err := p.publish(ctx, "response", p.responseSubject, response)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Published response",
+		zap.String("subject", p.responseSubject),
+		zap.Uint64("seq", seq),
+		zap.String("type", string(response.Type)),
+		zap.Int64("user_id", int64(response.UserID)),
+	)
+	return nil
+}
+
+func (p *Publisher) PublishContractEvent(
+	ctx context.Context,
+	notification domain.ContractEventNotification,
+) error {
+	seq, err := p.publish(ctx, "contract_event", p.contractSubject, notification)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Published contract event",
+		zap.String("subject", p.contractSubject),
+		zap.Uint64("seq", seq),
+		zap.String("contract", string(notification.Event.ContractAddress)),
+		zap.String("event", notification.Event.Event),
+	)
+	return nil
+}
+
+func (p *Publisher) PublishTokenTransfer(
+	ctx context.Context,
+	notification domain.TokenTransferNotification,
+) error {
+	seq, err := p.publish(ctx, "token_transfer", p.tokenSubject, notification)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Published token transfer",
+		zap.String("subject", p.tokenSubject),
+		zap.Uint64("seq", seq),
+		zap.String("token", notification.Event.Transfer.TokenAddress),
+		zap.String("tx_hash", string(notification.Event.Transfer.TxHash)),
+	)
+	return nil
+}
+
+func (p *Publisher) PublishAlert(ctx context.Context, notification domain.AlertNotification) error {
+	seq, err := p.publish(ctx, "alert", p.alertSubject, notification)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Published alert",
+		zap.String("subject", p.alertSubject),
+		zap.Uint64("seq", seq),
+		zap.String("rule_id", notification.Rule.ID),
+	)
+	return nil
+}
+
+func (p *Publisher) PublishFirehose(ctx context.Context, block domain.FirehoseBlock) error {
+	seq, err := p.publish(ctx, "firehose", p.firehoseSubject, block)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Published firehose block",
+		zap.String("subject", p.firehoseSubject),
+		zap.Uint64("seq", seq),
+		zap.Uint64("block", block.BlockNumber),
+		zap.Int("transfers", len(block.Transfers)),
+	)
+	return nil
+}
+
+// PublishCommandResult publishes result to the subject of channel
+func (p *Publisher) PublishCommandResult(
+	ctx context.Context,
+	channel string,
+	result domain.CommandResult,
+) error {
+	subject := p.client.subject(channel)
+	seq, err := p.publish(ctx, "command_result", subject, result)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Published command result",
+		zap.String("subject", subject),
+		zap.Uint64("seq", seq),
+		zap.String("correlation_id", result.CorrelationID),
+		zap.String("status", string(result.Status)),
+	)
+	return nil
+}
+
+// publish publishes payload in the publisher's codec to subject, counted
+// under kind, and returns its stream sequence number. The Content-Type
+// header names the codec.
+func (p *Publisher) publish(ctx context.Context, kind, subject string, payload any) (uint64, error) {
+	data, err := p.codec.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	ctx, span := tracing.Start(ctx, "nats.publish",
+		attribute.String("messaging.system", "nats"),
+		attribute.String("messaging.destination.name", subject),
+		attribute.String("message.kind", kind),
+	)
+	msg := &nats.Msg{
+		Subject: subject,
+		Data:    data,
+		Header:  nats.Header{"Content-Type": []string{p.codec.ContentType()}},
+	}
+	ack, err := p.client.js.PublishMsg(ctx, msg, jetstream.WithExpectStream(p.client.cfg.Stream))
+	tracing.End(span, err)
+	if err != nil {
+		metrics.PublishFailuresTotal.WithLabelValues(kind).Inc()
+		p.logger.Error("Failed to publish message to NATS",
+			zap.String("subject", subject),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+	metrics.MessagesPublishedTotal.WithLabelValues(kind).Inc()
+
+	return ack.Sequence, nil
+}
@@ -0,0 +1,142 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// Subscriber reads commands through a durable JetStream consumer shared by
+// all replicas. Commands are acknowledged only once handled; the server
+// redelivers a command not acknowledged within AckWait, including ones a
+// crashed replica was handling.
+type Subscriber struct {
+	client      *Client
+	subject     string
+	codec       codec.Codec
+	deadLetters domain.DeadLetterQueue // nil = off
+	logger      *zap.Logger
+}
+
+// NewSubscriber returns a subscriber to the subject of channel
+func NewSubscriber(
+	client *Client,
+	channel string,
+	codec codec.Codec,
+	deadLetters domain.DeadLetterQueue,
+	logger *zap.Logger,
+) *Subscriber {
+	return &Subscriber{
+		client:      client,
+		subject:     client.subject(channel),
+		codec:       codec,
+		deadLetters: deadLetters,
+		logger:      logger,
+	}
+}
+
+func (s *Subscriber) SubscribeCommands(
+	ctx context.Context,
+	handler func(domain.Command) error,
+) error {
+	cfg := s.client.cfg
+
+	// A new consumer starts at the beginning of the stream so commands sent
+	// before the tracker first ran aren't skipped
+	consumer, err := s.client.js.CreateOrUpdateConsumer(ctx, cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       cfg.Durable,
+		FilterSubject: s.subject,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       cfg.AckWait,
+		MaxDeliver:    cfg.MaxDeliver,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", cfg.Durable, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		go s.handle(msg, handler)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume commands: %w", err)
+	}
+	defer consumeCtx.Stop()
+
+	s.logger.Info("Consuming commands subject",
+		zap.String("subject", s.subject),
+		zap.String("stream", cfg.Stream),
+		zap.String("durable", cfg.Durable),
+	)
+
+	<-ctx.Done()
+	s.logger.Info("Command subscriber stopped")
+	return ctx.Err()
+}
+
+// handle runs the command and acknowledges it unless it failed in a way
+// worth retrying, in which case it is redelivered after AckWait
+func (s *Subscriber) handle(msg jetstream.Msg, handler func(domain.Command) error) {
+	var deliveries uint64
+	if meta, err := msg.Metadata(); err == nil {
+		deliveries = meta.NumDelivered
+	}
+
+	var cmd domain.Command
+	if err := s.codec.Unmarshal(msg.Data(), &cmd); err != nil {
+		s.logger.Error("Failed to unmarshal command",
+			zap.Uint64("deliveries", deliveries),
+			zap.ByteString("payload", msg.Data()),
+			zap.Error(err),
+		)
+		s.deadLetter(msg, err)
+		s.settle(msg.Term) // Would never succeed
+		return
+	}
+
+	if err := handler(cmd); err != nil {
+		if deliveries >= uint64(s.client.cfg.MaxDeliver) {
+			s.logger.Error("Dropping command that keeps failing",
+				zap.String("type", string(cmd.Type)),
+				zap.Uint64("deliveries", deliveries),
+				zap.Error(err),
+			)
+			s.deadLetter(msg, err)
+			s.settle(msg.Term)
+			return
+		}
+
+		s.logger.Warn("Command left pending for retry",
+			zap.String("type", string(cmd.Type)),
+			zap.Uint64("deliveries", deliveries),
+			zap.Error(err),
+		)
+		s.settle(func() error { return msg.NakWithDelay(s.client.cfg.AckWait) })
+		return
+	}
+
+	s.settle(msg.Ack)
+}
+
+// settle acknowledges, rejects or returns a message with fn
+func (s *Subscriber) settle(fn func() error) {
+	if err := fn(); err != nil {
+		s.logger.Error("Failed to acknowledge command", zap.Error(err))
+	}
+}
+
+// deadLetter adds a command that can't be handled to the dead letter
+// queue, if set
+func (s *Subscriber) deadLetter(msg jetstream.Msg, cause error) {
+	if s.deadLetters == nil {
+		return
+	}
+	if err := s.deadLetters.Add(context.Background(), domain.NewDeadCommand(msg.Data(), cause)); err != nil {
+		s.logger.Error("Failed to dead-letter command", zap.Error(err))
+	}
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BalanceSnapshotRepository stores balance snapshots as rows of
+// balance_snapshots, one per wallet and time
+type BalanceSnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBalanceSnapshotRepository(client *Client) *BalanceSnapshotRepository {
+	return &BalanceSnapshotRepository{pool: client.pool}
+}
+
+func (r *BalanceSnapshotRepository) SaveSnapshots(ctx context.Context, snapshots []domain.BalanceSnapshot) error {
+	batch := &pgx.Batch{}
+	for _, snapshot := range snapshots {
+		data, err := json.Marshal(snapshot.Balances)
+		if err != nil {
+			return fmt.Errorf("failed to marshal balances: %w", err)
+		}
+		batch.Queue(`
+			INSERT INTO balance_snapshots (wallet_address, taken_at, balances, value_usd)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (wallet_address, taken_at) DO NOTHING`,
+			snapshot.WalletAddress, snapshot.TakenAt, data, decimal(snapshot.ValueUSD))
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
+
+func (r *BalanceSnapshotRepository) SnapshotsAt(
+	ctx context.Context,
+	wallets []domain.WalletAddress,
+	at time.Time,
+) (map[domain.WalletAddress]domain.BalanceSnapshot, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT DISTINCT ON (wallet_address) wallet_address, taken_at, balances, value_usd::text
+		FROM balance_snapshots
+		WHERE wallet_address = ANY($1) AND taken_at <= COALESCE($2::timestamptz, 'infinity')
+		ORDER BY wallet_address, taken_at DESC`,
+		wallets, timestamp(at))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make(map[domain.WalletAddress]domain.BalanceSnapshot)
+	for rows.Next() {
+		var (
+			snapshot domain.BalanceSnapshot
+			data     []byte
+		)
+		if err := rows.Scan(&snapshot.WalletAddress, &snapshot.TakenAt, &data, &snapshot.ValueUSD); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &snapshot.Balances); err != nil {
+			return nil, fmt.Errorf("invalid balance snapshot of %s: %w", snapshot.WalletAddress, err)
+		}
+		snapshots[snapshot.WalletAddress] = snapshot
+	}
+
+	return snapshots, rows.Err()
+}
+
+func (r *BalanceSnapshotRepository) DeleteSnapshots(ctx context.Context, before time.Time) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM balance_snapshots WHERE taken_at < $1", before)
+	return err
+}
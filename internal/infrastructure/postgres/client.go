@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Client is a PostgreSQL connection pool
+type Client struct {
+	pool *pgxpool.Pool
+}
+
+// NewClient connects to PostgreSQL and migrates the schema to the latest
+// version
+func NewClient(ctx context.Context, cfg config.PostgresConfig) (*Client, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postgres dsn: %w", err)
+	}
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	if err := migrate(ctx, pool); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &Client{pool: pool}, nil
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
+func (c *Client) Close() {
+	c.pool.Close()
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migrations are named <version>_<description>.sql and applied in version
+// order; a migration must never change once released
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Key of the advisory lock held while migrating, so replicas starting
+// together don't apply a migration twice
+const migrationLockKey = 0x706c61736d61 // "plasma"
+
+// migrate applies the migrations not yet recorded in schema_migrations,
+// each in its own transaction
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	type migration struct {
+		version int
+		file    string
+	}
+	pending := make([]migration, 0, len(files))
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(file, "migrations/"), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("invalid migration name %s", file)
+		}
+		pending = append(pending, migration{version, file})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].version < pending[j].version })
+
+	for _, m := range pending {
+		err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			var applied bool
+			err := tx.QueryRow(ctx,
+				"SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.version,
+			).Scan(&applied)
+			if err != nil || applied {
+				return err
+			}
+
+			script, err := migrations.ReadFile(m.file)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, string(script)); err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.file, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+-- Subscriptions of users to wallets; the subscription column holds the
+-- domain.WalletSubscription as JSON
+CREATE TABLE wallet_subscriptions (
+    wallet_address TEXT        NOT NULL,
+    user_id        BIGINT      NOT NULL,
+    subscription   JSONB       NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (wallet_address, user_id)
+);
+
+CREATE INDEX wallet_subscriptions_user_id ON wallet_subscriptions (user_id);
@@ -0,0 +1,54 @@
+-- Transactions published for tracked wallets; the transaction column holds
+-- the domain.Transaction as JSON with all of its transfers
+CREATE TABLE wallet_transactions (
+    wallet_address TEXT           NOT NULL,
+    tx_hash        TEXT           NOT NULL,
+    block_number   BIGINT         NOT NULL,
+    block_time     TIMESTAMPTZ    NOT NULL,
+    status         TEXT           NOT NULL,
+    kind           TEXT           NOT NULL,
+    from_address   TEXT           NOT NULL,
+    to_address     TEXT           NOT NULL,
+    gas_used       BIGINT         NOT NULL,
+    gas_price      NUMERIC(78, 0),
+    fee_payer      TEXT           NOT NULL,
+    transaction    JSONB          NOT NULL,
+    stored_at      TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    PRIMARY KEY (wallet_address, tx_hash)
+);
+
+CREATE INDEX wallet_transactions_block ON wallet_transactions (wallet_address, block_number);
+CREATE INDEX wallet_transactions_time ON wallet_transactions (wallet_address, block_time);
+
+-- Transfers of those transactions that involve the wallet, one row each
+-- for analytics. Position is the index in the transaction's transfers.
+CREATE TABLE wallet_transfers (
+    wallet_address TEXT           NOT NULL,
+    tx_hash        TEXT           NOT NULL,
+    position       INTEGER        NOT NULL,
+    log_index      INTEGER        NOT NULL,
+    internal       BOOLEAN        NOT NULL,
+    block_number   BIGINT         NOT NULL,
+    block_time     TIMESTAMPTZ    NOT NULL,
+    direction      TEXT           NOT NULL, -- in, out or self
+    from_address   TEXT           NOT NULL,
+    to_address     TEXT           NOT NULL,
+    token_address  TEXT           NOT NULL,
+    token_symbol   TEXT           NOT NULL,
+    token_standard TEXT           NOT NULL,
+    value          NUMERIC(78, 0),
+    token_id       NUMERIC(78, 0),
+    value_usd      NUMERIC,
+    PRIMARY KEY (wallet_address, tx_hash, position),
+    FOREIGN KEY (wallet_address, tx_hash)
+        REFERENCES wallet_transactions (wallet_address, tx_hash) ON DELETE CASCADE
+);
+
+CREATE INDEX wallet_transfers_token ON wallet_transfers (wallet_address, token_address, block_time);
+
+-- Block from which all transactions of a tracked wallet are stored
+CREATE TABLE wallet_coverage (
+    wallet_address TEXT        PRIMARY KEY,
+    from_block     BIGINT      NOT NULL,
+    started_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,11 @@
+-- Periodic balance snapshots of tracked wallets; the balances column holds
+-- the domain.TokenBalance list as JSON, valued at the time of the snapshot
+CREATE TABLE balance_snapshots (
+    wallet_address TEXT        NOT NULL,
+    taken_at       TIMESTAMPTZ NOT NULL,
+    balances       JSONB       NOT NULL,
+    value_usd      NUMERIC     NOT NULL,
+    PRIMARY KEY (wallet_address, taken_at)
+);
+
+CREATE INDEX balance_snapshots_taken_at ON balance_snapshots (taken_at);
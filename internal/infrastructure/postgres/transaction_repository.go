@@ -0,0 +1,355 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TransactionRepository stores published transactions in
+// wallet_transactions, their transfers involving the wallet in
+// wallet_transfers for analytics, and coverage in wallet_coverage
+type TransactionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTransactionRepository(client *Client) *TransactionRepository {
+	return &TransactionRepository{pool: client.pool}
+}
+
+func (r *TransactionRepository) SaveTransaction(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(`
+		INSERT INTO wallet_transactions (
+			wallet_address, tx_hash, block_number, block_time, status, kind,
+			from_address, to_address, gas_used, gas_price, fee_payer, transaction
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (wallet_address, tx_hash) DO UPDATE SET
+			block_number = EXCLUDED.block_number,
+			block_time = EXCLUDED.block_time,
+			status = EXCLUDED.status,
+			transaction = EXCLUDED.transaction,
+			stored_at = now()`,
+		walletAddress, tx.Hash, tx.BlockNumber, tx.Timestamp, tx.Status, tx.Kind,
+		tx.From, tx.To, tx.GasUsed, numeric(tx.GasPrice), tx.FeePayer, data,
+	)
+	batch.Queue("DELETE FROM wallet_transfers WHERE wallet_address = $1 AND tx_hash = $2",
+		walletAddress, tx.Hash)
+
+	for position, transfer := range tx.Transfers {
+		direction := transferDirection(walletAddress, transfer)
+		if direction == "" {
+			continue
+		}
+		batch.Queue(`
+			INSERT INTO wallet_transfers (
+				wallet_address, tx_hash, position, log_index, internal, block_number,
+				block_time, direction, from_address, to_address, token_address,
+				token_symbol, token_standard, value, token_id, value_usd
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			walletAddress, tx.Hash, position, transfer.LogIndex, transfer.Internal, tx.BlockNumber,
+			tx.Timestamp, direction, transfer.From, transfer.To, transfer.TokenAddress,
+			transfer.TokenSymbol, transfer.TokenStandard, numeric(transfer.Value),
+			numeric(transfer.TokenID), decimal(transfer.ValueUSD),
+		)
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(dbTx pgx.Tx) error {
+		return dbTx.SendBatch(ctx, batch).Close()
+	})
+}
+
+func (r *TransactionRepository) DeleteTransactions(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	hashes []domain.TransactionHash,
+) error {
+	_, err := r.pool.Exec(ctx,
+		"DELETE FROM wallet_transactions WHERE wallet_address = $1 AND tx_hash = ANY($2)",
+		walletAddress, hashes)
+	return err
+}
+
+func (r *TransactionRepository) ListTransactions(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	fromBlock uint64,
+	toBlock uint64,
+	limit int,
+) ([]domain.Transaction, error) {
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+
+	// Whole blocks up to the one holding the limit-th transaction
+	rows, err := r.pool.Query(ctx, `
+		SELECT transaction FROM wallet_transactions
+		WHERE wallet_address = $1 AND block_number BETWEEN $2 AND $3
+			AND block_number <= COALESCE((
+				SELECT block_number FROM wallet_transactions
+				WHERE wallet_address = $1 AND block_number BETWEEN $2 AND $3
+				ORDER BY block_number
+				OFFSET $4 - 1 LIMIT 1
+			), $3)
+		ORDER BY block_number, tx_hash`,
+		walletAddress, fromBlock, toBlock, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []domain.Transaction
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var tx domain.Transaction
+		if err := json.Unmarshal(data, &tx); err != nil {
+			return nil, fmt.Errorf("invalid stored transaction of %s: %w", walletAddress, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, rows.Err()
+}
+
+func (r *TransactionRepository) WalletStats(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	since time.Time,
+	until time.Time,
+) (*domain.WalletStats, error) {
+	stats := &domain.WalletStats{
+		WalletAddress: walletAddress,
+		Since:         since,
+		Until:         until,
+		Tokens:        []domain.DigestTokenTotal{},
+	}
+	from, to := timestamp(since), timestamp(until)
+
+	// One snapshot, so a transaction stored meanwhile can't show up in
+	// some of the aggregates only
+	err := pgx.BeginTxFunc(ctx, r.pool, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	}, func(dbTx pgx.Tx) error {
+		var first, last pgtype.Timestamptz
+		err := dbTx.QueryRow(ctx, `
+			SELECT count(*), count(*) FILTER (WHERE status = $4), min(block_time), max(block_time)
+			FROM wallet_transactions
+			WHERE wallet_address = $1
+				AND block_time >= COALESCE($2::timestamptz, '-infinity')
+				AND block_time <= COALESCE($3::timestamptz, 'infinity')`,
+			walletAddress, from, to, domain.TxReverted,
+		).Scan(&stats.Transactions, &stats.Failed, &first, &last)
+		if err != nil {
+			return fmt.Errorf("failed to count transactions: %w", err)
+		}
+		stats.FirstActivity, stats.LastActivity = first.Time, last.Time
+
+		// Self transfers count both ways, as in digests
+		rows, err := dbTx.Query(ctx, `
+			SELECT token_address, max(token_symbol), max(token_standard),
+				count(*) FILTER (WHERE direction <> 'out'),
+				count(*) FILTER (WHERE direction <> 'in'),
+				COALESCE(sum(value) FILTER (WHERE direction <> 'out'), 0)::text,
+				COALESCE(sum(value) FILTER (WHERE direction <> 'in'), 0)::text,
+				COALESCE(round(sum(value_usd) FILTER (WHERE direction <> 'out'), 2)::text, ''),
+				COALESCE(round(sum(value_usd) FILTER (WHERE direction <> 'in'), 2)::text, '')
+			FROM wallet_transfers
+			WHERE wallet_address = $1
+				AND block_time >= COALESCE($2::timestamptz, '-infinity')
+				AND block_time <= COALESCE($3::timestamptz, 'infinity')
+			GROUP BY token_address
+			ORDER BY count(*) DESC, token_address`,
+			walletAddress, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to sum transfers: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				total             domain.DigestTokenTotal
+				valueIn, valueOut string
+			)
+			err := rows.Scan(&total.TokenAddress, &total.TokenSymbol, &total.TokenStandard,
+				&total.Incoming, &total.Outgoing, &valueIn, &valueOut,
+				&total.ValueInUSD, &total.ValueOutUSD)
+			if err != nil {
+				return err
+			}
+			total.ValueIn, _ = new(big.Int).SetString(valueIn, 10)
+			total.ValueOut, _ = new(big.Int).SetString(valueOut, 10)
+			stats.Tokens = append(stats.Tokens, total)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// The other side of each transfer and of each transaction, which
+		// covers calls that moved no tokens
+		err = dbTx.QueryRow(ctx, `
+			SELECT count(DISTINCT lower(counterparty)) FROM (
+				SELECT CASE direction WHEN 'in' THEN from_address ELSE to_address END
+				FROM wallet_transfers
+				WHERE wallet_address = $1
+					AND block_time >= COALESCE($2::timestamptz, '-infinity')
+					AND block_time <= COALESCE($3::timestamptz, 'infinity')
+				UNION ALL
+				SELECT CASE WHEN lower(from_address) = lower($1) THEN to_address ELSE from_address END
+				FROM wallet_transactions
+				WHERE wallet_address = $1
+					AND block_time >= COALESCE($2::timestamptz, '-infinity')
+					AND block_time <= COALESCE($3::timestamptz, 'infinity')
+			) AS parties (counterparty)
+			WHERE counterparty <> '' AND lower(counterparty) <> lower($1)`,
+			walletAddress, from, to,
+		).Scan(&stats.Counterparties)
+		if err != nil {
+			return fmt.Errorf("failed to count counterparties: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *TransactionRepository) LargestTransfers(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	since time.Time,
+	until time.Time,
+	limit int,
+) ([]domain.Transfer, error) {
+	// The stored transaction has the transfer as published, with its
+	// formatted value and labels
+	rows, err := r.pool.Query(ctx, `
+		SELECT t.transaction -> 'transfers' -> f.position
+		FROM wallet_transfers f
+		JOIN wallet_transactions t USING (wallet_address, tx_hash)
+		WHERE f.wallet_address = $1
+			AND f.block_time >= COALESCE($2::timestamptz, '-infinity')
+			AND f.block_time <= COALESCE($3::timestamptz, 'infinity')
+		ORDER BY f.value_usd DESC NULLS LAST, f.value DESC NULLS LAST, f.block_time DESC
+		LIMIT $4`,
+		walletAddress, timestamp(since), timestamp(until), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []domain.Transfer
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var transfer domain.Transfer
+		if err := json.Unmarshal(data, &transfer); err != nil {
+			return nil, fmt.Errorf("invalid stored transfer of %s: %w", walletAddress, err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+func (r *TransactionRepository) StartCoverage(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	fromBlock uint64,
+) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO wallet_coverage (wallet_address, from_block) VALUES ($1, $2)
+		ON CONFLICT (wallet_address) DO NOTHING`,
+		walletAddress, fromBlock)
+	return err
+}
+
+func (r *TransactionRepository) StopCoverage(ctx context.Context, walletAddress domain.WalletAddress) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM wallet_coverage WHERE wallet_address = $1", walletAddress)
+	return err
+}
+
+func (r *TransactionRepository) Coverage(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) (uint64, bool, error) {
+	var fromBlock uint64
+	err := r.pool.QueryRow(ctx,
+		"SELECT from_block FROM wallet_coverage WHERE wallet_address = $1", walletAddress,
+	).Scan(&fromBlock)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return fromBlock, true, nil
+}
+
+// transferDirection is the side of transfer the wallet is on, or empty if
+// the transfer doesn't involve it
+func transferDirection(walletAddress domain.WalletAddress, transfer domain.Transfer) string {
+	incoming := strings.EqualFold(string(transfer.To), string(walletAddress))
+	outgoing := strings.EqualFold(string(transfer.From), string(walletAddress))
+	switch {
+	case incoming && outgoing:
+		return "self"
+	case incoming:
+		return string(domain.Incoming)
+	case outgoing:
+		return string(domain.Outgoing)
+	default:
+		return ""
+	}
+}
+
+// numeric converts an amount for a NUMERIC column; nil is NULL
+func numeric(n *big.Int) pgtype.Numeric {
+	if n == nil {
+		return pgtype.Numeric{}
+	}
+	return pgtype.Numeric{Int: n, Valid: true}
+}
+
+// timestamp converts a time bound for a TIMESTAMPTZ parameter; zero is NULL
+func timestamp(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+// decimal converts a decimal string such as a USD value for a NUMERIC
+// column; an empty or malformed one is NULL
+func decimal(s string) pgtype.Numeric {
+	var n pgtype.Numeric
+	if s != "" && n.Scan(s) != nil {
+		return pgtype.Numeric{}
+	}
+	return n
+}
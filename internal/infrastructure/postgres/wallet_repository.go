@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WalletRepository stores subscriptions as rows of wallet_subscriptions,
+// one per wallet and user
+type WalletRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWalletRepository(client *Client) *WalletRepository {
+	return &WalletRepository{pool: client.pool}
+}
+
+const upsertSubscription = `
+	INSERT INTO wallet_subscriptions (wallet_address, user_id, subscription, created_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (wallet_address, user_id) DO UPDATE SET subscription = EXCLUDED.subscription`
+
+func (r *WalletRepository) AddSubscription(
+	ctx context.Context,
+	subscription domain.WalletSubscription,
+) error {
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, upsertSubscription,
+		subscription.WalletAddress, subscription.UserID, data, subscription.CreatedAt)
+	return err
+}
+
+func (r *WalletRepository) RemoveSubscription(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	_, err := r.pool.Exec(ctx,
+		"DELETE FROM wallet_subscriptions WHERE wallet_address = $1 AND user_id = $2",
+		walletAddress, userID)
+	return err
+}
+
+func (r *WalletRepository) AddSubscriptions(
+	ctx context.Context,
+	subscriptions []domain.WalletSubscription,
+) error {
+	batch := &pgx.Batch{}
+	for _, subscription := range subscriptions {
+		data, err := json.Marshal(subscription)
+		if err != nil {
+			return err
+		}
+		batch.Queue(upsertSubscription,
+			subscription.WalletAddress, subscription.UserID, data, subscription.CreatedAt)
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+func (r *WalletRepository) RemoveSubscriptions(
+	ctx context.Context,
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	_, err := r.pool.Exec(ctx,
+		"DELETE FROM wallet_subscriptions WHERE user_id = $1 AND wallet_address = ANY($2)",
+		userID, walletAddresses)
+	return err
+}
+
+func (r *WalletRepository) GetSubscribers(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) ([]domain.UserID, error) {
+	rows, err := r.pool.Query(ctx,
+		"SELECT user_id FROM wallet_subscriptions WHERE wallet_address = $1 ORDER BY created_at",
+		walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[domain.UserID])
+}
+
+func (r *WalletRepository) GetSubscriptions(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) ([]domain.WalletSubscription, error) {
+	rows, err := r.pool.Query(ctx,
+		"SELECT user_id, subscription FROM wallet_subscriptions WHERE wallet_address = $1 ORDER BY created_at",
+		walletAddress)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []domain.WalletSubscription
+	for rows.Next() {
+		var (
+			userID int64
+			data   []byte
+		)
+		if err := rows.Scan(&userID, &data); err != nil {
+			return nil, err
+		}
+
+		var subscription domain.WalletSubscription
+		if err := json.Unmarshal(data, &subscription); err != nil {
+			return nil, fmt.Errorf("invalid subscription of %d to %s: %w", userID, walletAddress, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *WalletRepository) GetAllWallets(ctx context.Context) ([]domain.WalletAddress, error) {
+	rows, err := r.pool.Query(ctx, "SELECT DISTINCT wallet_address FROM wallet_subscriptions")
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[domain.WalletAddress])
+}
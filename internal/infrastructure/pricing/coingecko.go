@@ -0,0 +1,123 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Zero address used for native XPL
+const nativeToken = "0x0000000000000000000000000000000000000000"
+
+// CoinGeckoProvider prices tokens with the CoinGecko simple price API
+type CoinGeckoProvider struct {
+	client   *http.Client
+	baseURL  string
+	apiKey   string
+	platform string // Asset platform id of the chain, for token prices
+	nativeID string // Coin id of native XPL
+}
+
+func NewCoinGeckoProvider(baseURL, apiKey, platform, nativeID string) *CoinGeckoProvider {
+	return &CoinGeckoProvider{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiKey:   apiKey,
+		platform: platform,
+		nativeID: nativeID,
+	}
+}
+
+func (p *CoinGeckoProvider) Name() string {
+	return "coingecko"
+}
+
+// GetPricesUSD queries token prices by contract address in one request,
+// and native XPL by its coin id in another
+func (p *CoinGeckoProvider) GetPricesUSD(
+	ctx context.Context,
+	tokenAddresses []string,
+) (map[string]float64, error) {
+	prices := make(map[string]float64)
+
+	var contracts []string
+	for _, address := range tokenAddresses {
+		address = strings.ToLower(address)
+		if address == nativeToken {
+			if p.nativeID == "" {
+				continue
+			}
+			result, err := p.get(ctx, "/simple/price", url.Values{
+				"ids":           {p.nativeID},
+				"vs_currencies": {"usd"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if price, ok := result[p.nativeID]; ok {
+				prices[nativeToken] = price.USD
+			}
+			continue
+		}
+		contracts = append(contracts, address)
+	}
+
+	if len(contracts) > 0 {
+		result, err := p.get(ctx, "/simple/token_price/"+url.PathEscape(p.platform), url.Values{
+			"contract_addresses": {strings.Join(contracts, ",")},
+			"vs_currencies":      {"usd"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for address, price := range result {
+			prices[strings.ToLower(address)] = price.USD
+		}
+	}
+
+	return prices, nil
+}
+
+type coinGeckoPrice struct {
+	USD float64 `json:"usd"`
+}
+
+func (p *CoinGeckoProvider) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+) (map[string]coinGeckoPrice, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		// Pro and demo plans authenticate with different headers
+		if strings.Contains(p.baseURL, "pro-api.") {
+			req.Header.Set("x-cg-pro-api-key", p.apiKey)
+		} else {
+			req.Header.Set("x-cg-demo-api-key", p.apiKey)
+		}
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CoinGecko: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CoinGecko returned status %d", resp.StatusCode)
+	}
+
+	var result map[string]coinGeckoPrice
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode CoinGecko response: %w", err)
+	}
+	return result, nil
+}
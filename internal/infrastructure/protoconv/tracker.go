@@ -0,0 +1,260 @@
+// Package protoconv converts domain types to and from the generated
+// protobuf messages of the gRPC API and the wire format
+package protoconv
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	trackerv1 "github.com/say8hi/plasma-wallet-tracker/api/proto/tracker/v1"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func FilterFromProto(filter *trackerv1.NotificationFilter) *domain.NotificationFilter {
+	if filter == nil {
+		return nil
+	}
+	return &domain.NotificationFilter{
+		Direction:       domain.TransferDirection(filter.GetDirection()),
+		Tokens:          filter.GetTokens(),
+		MinNativeAmount: filter.GetMinNativeAmount(),
+	}
+}
+
+func Filter(filter *domain.NotificationFilter) *trackerv1.NotificationFilter {
+	if filter == nil {
+		return nil
+	}
+	return &trackerv1.NotificationFilter{
+		Direction:       string(filter.Direction),
+		Tokens:          filter.Tokens,
+		MinNativeAmount: filter.MinNativeAmount,
+	}
+}
+
+func Subscription(subscription domain.WalletSubscription) *trackerv1.WalletSubscription {
+	message := &trackerv1.WalletSubscription{
+		WalletAddress: string(subscription.WalletAddress),
+		UserId:        int64(subscription.UserID),
+		Filters:       Filter(subscription.Filters),
+		Label:         subscription.Label,
+		CreatedAt:     Timestamp(subscription.CreatedAt),
+		Paused:        subscription.Paused,
+	}
+	if subscription.DigestWindow > 0 {
+		message.DigestWindow = durationpb.New(subscription.DigestWindow)
+	}
+	if subscription.PausedUntil != nil {
+		message.PausedUntil = Timestamp(*subscription.PausedUntil)
+	}
+	return message
+}
+
+// Notification converts the share of notification userID may see
+func Notification(notification domain.WalletNotification, userID domain.UserID) *trackerv1.WalletNotification {
+	message := &trackerv1.WalletNotification{
+		Type:           string(notification.Type),
+		WalletAddress:  string(notification.WalletAddress),
+		Transaction:    Transaction(notification.Transaction),
+		Timestamp:      Timestamp(notification.Timestamp),
+		IdempotencyKey: notification.IdempotencyKey,
+		WalletLabel:    notification.WalletLabels[userID],
+		WalletGroups:   notification.WalletGroups[userID],
+		Confirmations:  notification.Confirmations,
+		Unconfirmed:    notification.Unconfirmed,
+		Historical:     notification.Historical,
+		Failed:         notification.Failed,
+		Gasless:        notification.Gasless,
+		Suppressed:     int64(notification.Suppressed),
+	}
+
+	for _, transfer := range notification.Transfers {
+		message.Transfers = append(message.Transfers, Transfer(transfer))
+	}
+	for _, approval := range notification.Approvals {
+		message.Approvals = append(message.Approvals, &trackerv1.Approval{
+			TxHash:          string(approval.TxHash),
+			Owner:           string(approval.Owner),
+			Spender:         string(approval.Spender),
+			SpenderLabel:    approval.SpenderLabel,
+			TokenAddress:    approval.TokenAddress,
+			TokenSymbol:     approval.TokenSymbol,
+			Amount:          bigString(approval.Amount),
+			FormattedAmount: approval.FormattedAmount,
+			Unlimited:       approval.Unlimited,
+			Revocation:      approval.Revocation,
+			LogIndex:        int64(approval.LogIndex),
+		})
+	}
+	for _, swap := range notification.Swaps {
+		message.Swaps = append(message.Swaps, &trackerv1.Swap{
+			TxHash:             string(swap.TxHash),
+			Protocol:           swap.Protocol,
+			Pool:               swap.Pool,
+			Sender:             string(swap.Sender),
+			Recipient:          string(swap.Recipient),
+			TokenIn:            swap.TokenIn,
+			TokenInSymbol:      swap.TokenInSymbol,
+			AmountIn:           bigString(swap.AmountIn),
+			FormattedAmountIn:  swap.FormattedAmountIn,
+			TokenOut:           swap.TokenOut,
+			TokenOutSymbol:     swap.TokenOutSymbol,
+			AmountOut:          bigString(swap.AmountOut),
+			FormattedAmountOut: swap.FormattedAmountOut,
+			LogIndex:           int64(swap.LogIndex),
+		})
+	}
+	for _, bridge := range notification.Bridges {
+		message.Bridges = append(message.Bridges, &trackerv1.BridgeTransfer{
+			TxHash:           string(bridge.TxHash),
+			Direction:        string(bridge.Direction),
+			Bridge:           bridge.Bridge,
+			Account:          string(bridge.Account),
+			TokenAddress:     bridge.TokenAddress,
+			TokenSymbol:      bridge.TokenSymbol,
+			Amount:           bigString(bridge.Amount),
+			FormattedAmount:  bridge.FormattedAmount,
+			SourceChain:      bridge.SourceChain,
+			DestinationChain: bridge.DestinationChain,
+			TransferId:       bridge.TransferID,
+			LogIndex:         int64(bridge.LogIndex),
+		})
+	}
+
+	if digest := notification.Digest; digest != nil {
+		message.Digest = &trackerv1.Digest{
+			From:         Timestamp(digest.From),
+			To:           Timestamp(digest.To),
+			Transactions: int64(digest.Transactions),
+			Failed:       int64(digest.Failed),
+		}
+		for _, total := range digest.Tokens {
+			message.Digest.Tokens = append(message.Digest.Tokens, &trackerv1.DigestTokenTotal{
+				TokenAddress:  total.TokenAddress,
+				TokenSymbol:   total.TokenSymbol,
+				TokenStandard: string(total.TokenStandard),
+				Incoming:      int64(total.Incoming),
+				Outgoing:      int64(total.Outgoing),
+				ValueIn:       bigString(total.ValueIn),
+				ValueOut:      bigString(total.ValueOut),
+				ValueInUsd:    total.ValueInUSD,
+				ValueOutUsd:   total.ValueOutUSD,
+			})
+		}
+	}
+	if summary := notification.Summary; summary != nil {
+		message.Summary = &trackerv1.Summary{
+			Period: string(summary.Period),
+			From:   Timestamp(summary.From),
+			To:     Timestamp(summary.To),
+		}
+		for _, wallet := range summary.Wallets {
+			walletSummary := &trackerv1.WalletSummary{
+				WalletAddress: string(wallet.WalletAddress),
+				Label:         wallet.Label,
+				Transactions:  int64(wallet.Transactions),
+				Failed:        int64(wallet.Failed),
+			}
+			for _, flow := range wallet.Flows {
+				walletSummary.Flows = append(walletSummary.Flows, &trackerv1.TokenFlow{
+					TokenAddress:  flow.TokenAddress,
+					TokenSymbol:   flow.TokenSymbol,
+					TokenStandard: string(flow.TokenStandard),
+					Net:           bigString(flow.Net),
+					NetUsd:        flow.NetUSD,
+				})
+			}
+			for _, transfer := range wallet.LargestTransfers {
+				walletSummary.LargestTransfers = append(walletSummary.LargestTransfers, Transfer(transfer))
+			}
+			message.Summary.Wallets = append(message.Summary.Wallets, walletSummary)
+		}
+	}
+	if reorg := notification.Reorg; reorg != nil {
+		message.Reorg = &trackerv1.Reorg{
+			FromBlock:  reorg.FromBlock,
+			ToBlock:    reorg.ToBlock,
+			NewHead:    reorg.NewHead,
+			DetectedAt: Timestamp(reorg.DetectedAt),
+		}
+	}
+	for _, hash := range notification.InvalidatedTxs {
+		message.InvalidatedTxs = append(message.InvalidatedTxs, string(hash))
+	}
+
+	return message
+}
+
+func Transaction(tx domain.Transaction) *trackerv1.Transaction {
+	message := &trackerv1.Transaction{
+		Hash:         string(tx.Hash),
+		Type:         string(tx.Type),
+		Kind:         string(tx.Kind),
+		Status:       string(tx.Status),
+		RevertReason: tx.RevertReason,
+		From:         string(tx.From),
+		To:           string(tx.To),
+		Contract:     string(tx.Contract),
+		Nonce:        tx.Nonce,
+		BlockNumber:  tx.BlockNumber,
+		Timestamp:    Timestamp(tx.Timestamp),
+		GasUsed:      tx.GasUsed,
+		GasPrice:     bigString(tx.GasPrice),
+		GasPricePaid: bigString(tx.GasPricePaid),
+		Fee:          bigString(tx.Fee),
+		Gasless:      tx.Gasless,
+		Paymaster:    string(tx.Paymaster),
+		FeePayer:     string(tx.FeePayer),
+	}
+	if method := tx.Method; method != nil {
+		message.Method = &trackerv1.MethodCall{
+			Selector:  method.Selector,
+			Signature: method.Signature,
+		}
+		if len(method.Args) > 0 {
+			if args, err := json.Marshal(method.Args); err == nil {
+				message.Method.ArgsJson = string(args)
+			}
+		}
+	}
+	return message
+}
+
+func Transfer(transfer domain.Transfer) *trackerv1.Transfer {
+	return &trackerv1.Transfer{
+		TxHash:         string(transfer.TxHash),
+		From:           string(transfer.From),
+		To:             string(transfer.To),
+		Value:          bigString(transfer.Value),
+		FormattedValue: transfer.FormattedValue,
+		ValueUsd:       transfer.ValueUSD,
+		TokenSymbol:    transfer.TokenSymbol,
+		TokenAddress:   transfer.TokenAddress,
+		TokenStandard:  string(transfer.TokenStandard),
+		TokenId:        bigString(transfer.TokenID),
+		LogIndex:       int64(transfer.LogIndex),
+		Internal:       transfer.Internal,
+		FromLabel:      transfer.FromLabel,
+		ToLabel:        transfer.ToLabel,
+	}
+}
+
+// bigString formats n in decimal; nil gives an empty string
+func bigString(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
+
+// Timestamp converts t, leaving zero times unset
+func Timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
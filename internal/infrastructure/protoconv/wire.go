@@ -0,0 +1,308 @@
+package protoconv
+
+import (
+	"encoding/json"
+	"fmt"
+
+	wirev1 "github.com/say8hi/plasma-wallet-tracker/api/proto/wire/v1"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+func WireCommand(cmd domain.Command) *wirev1.Command {
+	message := &wirev1.Command{
+		Type:            string(cmd.Type),
+		WalletAddress:   string(cmd.WalletAddress),
+		UserId:          int64(cmd.UserID),
+		Timestamp:       Timestamp(cmd.Timestamp),
+		TargetUserId:    int64(cmd.TargetUserID),
+		Duration:        cmd.Duration,
+		Label:           cmd.Label,
+		Group:           cmd.Group,
+		Filters:         Filter(cmd.Filters),
+		TokenAddress:    cmd.TokenAddress,
+		MinAmount:       cmd.MinAmount,
+		ContractAddress: string(cmd.ContractAddress),
+		Abi:             cmd.ABI,
+		EventSignature:  cmd.EventSignature,
+		FromBlock:       cmd.FromBlock,
+		ToBlock:         cmd.ToBlock,
+		Limit:           int64(cmd.Limit),
+		RuleId:          cmd.RuleID,
+		WebhookUrl:      cmd.WebhookURL,
+		WebhookId:       cmd.WebhookID,
+		LogLevel:        cmd.LogLevel,
+		SymbolPattern:   cmd.SymbolPattern,
+		Trace:           wireTrace(cmd.Trace),
+		DeadLetterIds:   cmd.DeadLetterIDs,
+		ReplyTo:         cmd.ReplyTo,
+		CorrelationId:   cmd.CorrelationID,
+	}
+	for _, address := range cmd.WalletAddresses {
+		message.WalletAddresses = append(message.WalletAddresses, string(address))
+	}
+	if cmd.WalletLimit != nil {
+		limit := int64(*cmd.WalletLimit)
+		message.WalletLimit = &limit
+	}
+	if len(cmd.Labels) > 0 {
+		message.Labels = make(map[string]string, len(cmd.Labels))
+		for address, label := range cmd.Labels {
+			message.Labels[string(address)] = label
+		}
+	}
+	if cmd.Alert != nil {
+		message.Alert = wireAlertRule(*cmd.Alert)
+	}
+	return message
+}
+
+func CommandFromWire(message *wirev1.Command) domain.Command {
+	cmd := domain.Command{
+		Type:            domain.CommandType(message.GetType()),
+		WalletAddress:   domain.WalletAddress(message.GetWalletAddress()),
+		UserID:          domain.UserID(message.GetUserId()),
+		TargetUserID:    domain.UserID(message.GetTargetUserId()),
+		Duration:        message.GetDuration(),
+		Label:           message.GetLabel(),
+		Group:           message.GetGroup(),
+		Filters:         FilterFromProto(message.GetFilters()),
+		TokenAddress:    message.GetTokenAddress(),
+		MinAmount:       message.GetMinAmount(),
+		ContractAddress: domain.WalletAddress(message.GetContractAddress()),
+		ABI:             message.GetAbi(),
+		EventSignature:  message.GetEventSignature(),
+		FromBlock:       message.GetFromBlock(),
+		ToBlock:         message.GetToBlock(),
+		Limit:           int(message.GetLimit()),
+		RuleID:          message.GetRuleId(),
+		WebhookURL:      message.GetWebhookUrl(),
+		WebhookID:       message.GetWebhookId(),
+		LogLevel:        message.GetLogLevel(),
+		SymbolPattern:   message.GetSymbolPattern(),
+		DeadLetterIDs:   message.GetDeadLetterIds(),
+		ReplyTo:         message.GetReplyTo(),
+		CorrelationID:   message.GetCorrelationId(),
+	}
+	if message.GetTimestamp() != nil {
+		cmd.Timestamp = message.GetTimestamp().AsTime()
+	}
+	for _, address := range message.GetWalletAddresses() {
+		cmd.WalletAddresses = append(cmd.WalletAddresses, domain.WalletAddress(address))
+	}
+	if message.WalletLimit != nil {
+		limit := int(message.GetWalletLimit())
+		cmd.WalletLimit = &limit
+	}
+	if len(message.GetLabels()) > 0 {
+		cmd.Labels = make(map[domain.WalletAddress]string, len(message.GetLabels()))
+		for address, label := range message.GetLabels() {
+			cmd.Labels[domain.WalletAddress(address)] = label
+		}
+	}
+	if alert := message.GetAlert(); alert != nil {
+		rule := alertRuleFromWire(alert)
+		cmd.Alert = &rule
+	}
+	if trace := message.GetTrace(); trace != nil {
+		cmd.Trace = &domain.TraceContext{
+			Traceparent: trace.GetTraceparent(),
+			Tracestate:  trace.GetTracestate(),
+		}
+	}
+	return cmd
+}
+
+// WireNotification converts notification with the labels and groups of
+// all its subscribers
+func WireNotification(notification domain.WalletNotification) *wirev1.WalletNotification {
+	message := &wirev1.WalletNotification{
+		Notification: Notification(notification, 0),
+		Subscribers:  wireUserIDs(notification.Subscribers),
+		Trace:        wireTrace(notification.Trace),
+	}
+	if len(notification.WalletLabels) > 0 {
+		message.WalletLabels = make(map[int64]string, len(notification.WalletLabels))
+		for userID, label := range notification.WalletLabels {
+			message.WalletLabels[int64(userID)] = label
+		}
+	}
+	if len(notification.WalletGroups) > 0 {
+		message.WalletGroups = make(map[int64]*wirev1.WalletGroups, len(notification.WalletGroups))
+		for userID, groups := range notification.WalletGroups {
+			message.WalletGroups[int64(userID)] = &wirev1.WalletGroups{Names: groups}
+		}
+	}
+	return message
+}
+
+// WireCommandResponse converts response, encoding its data as JSON
+func WireCommandResponse(response domain.CommandResponse) (*wirev1.CommandResponse, error) {
+	data, err := dataJSON(response.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &wirev1.CommandResponse{
+		Type:      string(response.Type),
+		UserId:    int64(response.UserID),
+		Success:   response.Success,
+		Error:     response.Error,
+		ErrorCode: response.ErrorCode,
+		DataJson:  data,
+		Timestamp: Timestamp(response.Timestamp),
+		Trace:     wireTrace(response.Trace),
+	}, nil
+}
+
+func WireCommandResult(result domain.CommandResult) (*wirev1.CommandResult, error) {
+	data, err := dataJSON(result.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &wirev1.CommandResult{
+		CorrelationId: result.CorrelationID,
+		Type:          string(result.Type),
+		UserId:        int64(result.UserID),
+		Status:        string(result.Status),
+		ErrorCode:     result.ErrorCode,
+		Error:         result.Error,
+		DataJson:      data,
+		Timestamp:     Timestamp(result.Timestamp),
+	}, nil
+}
+
+func WireContractEvent(notification domain.ContractEventNotification) (*wirev1.ContractEventNotification, error) {
+	event := notification.Event
+	message := &wirev1.ContractEventNotification{
+		Event: &wirev1.ContractEvent{
+			ContractAddress: string(event.ContractAddress),
+			Event:           event.Event,
+			Signature:       event.Signature,
+			TxHash:          string(event.TxHash),
+			BlockNumber:     event.BlockNumber,
+			LogIndex:        uint64(event.LogIndex),
+			Timestamp:       Timestamp(event.Timestamp),
+			Topics:          event.Topics,
+			Data:            event.Data,
+		},
+		Subscribers: wireUserIDs(notification.Subscribers),
+		Timestamp:   Timestamp(notification.Timestamp),
+		Trace:       wireTrace(notification.Trace),
+	}
+	if len(event.Args) > 0 {
+		args, err := json.Marshal(event.Args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event args: %w", err)
+		}
+		message.Event.ArgsJson = string(args)
+	}
+	return message, nil
+}
+
+func WireTokenTransfer(notification domain.TokenTransferNotification) *wirev1.TokenTransferNotification {
+	return &wirev1.TokenTransferNotification{
+		Transfer:       Transfer(notification.Event.Transfer),
+		BlockNumber:    notification.Event.BlockNumber,
+		EventTimestamp: Timestamp(notification.Event.Timestamp),
+		Subscribers:    wireUserIDs(notification.Subscribers),
+		Timestamp:      Timestamp(notification.Timestamp),
+		Trace:          wireTrace(notification.Trace),
+	}
+}
+
+func WireAlert(notification domain.AlertNotification) *wirev1.AlertNotification {
+	message := &wirev1.AlertNotification{
+		Rule:          wireAlertRule(notification.Rule),
+		WalletAddress: string(notification.WalletAddress),
+		Transaction:   Transaction(notification.Transaction),
+		Reason:        notification.Reason,
+		Timestamp:     Timestamp(notification.Timestamp),
+		Trace:         wireTrace(notification.Trace),
+	}
+	for _, transfer := range notification.Transfers {
+		message.Transfers = append(message.Transfers, Transfer(transfer))
+	}
+	return message
+}
+
+func WireFirehose(block domain.FirehoseBlock) *wirev1.FirehoseBlock {
+	message := &wirev1.FirehoseBlock{
+		BlockNumber: block.BlockNumber,
+		BlockHash:   block.BlockHash,
+		BlockTime:   Timestamp(block.BlockTime),
+		Timestamp:   Timestamp(block.Timestamp),
+		Trace:       wireTrace(block.Trace),
+	}
+	for _, transfer := range block.Transfers {
+		message.Transfers = append(message.Transfers, Transfer(transfer))
+	}
+	return message
+}
+
+func wireAlertRule(rule domain.AlertRule) *wirev1.AlertRule {
+	return &wirev1.AlertRule{
+		Id:              rule.ID,
+		UserId:          int64(rule.UserID),
+		Kind:            string(rule.Kind),
+		WalletAddress:   string(rule.WalletAddress),
+		TokenAddress:    rule.TokenAddress,
+		Direction:       string(rule.Direction),
+		MinAmount:       rule.MinAmount,
+		MinValueUsd:     rule.MinValueUSD,
+		MaxTransactions: int64(rule.MaxTransactions),
+		Window:          rule.Window,
+		DrainPercent:    rule.DrainPercent,
+		DormantFor:      rule.DormantFor,
+		CreatedAt:       Timestamp(rule.CreatedAt),
+	}
+}
+
+func alertRuleFromWire(rule *wirev1.AlertRule) domain.AlertRule {
+	alertRule := domain.AlertRule{
+		ID:              rule.GetId(),
+		UserID:          domain.UserID(rule.GetUserId()),
+		Kind:            domain.AlertKind(rule.GetKind()),
+		WalletAddress:   domain.WalletAddress(rule.GetWalletAddress()),
+		TokenAddress:    rule.GetTokenAddress(),
+		Direction:       domain.TransferDirection(rule.GetDirection()),
+		MinAmount:       rule.GetMinAmount(),
+		MinValueUSD:     rule.GetMinValueUsd(),
+		MaxTransactions: int(rule.GetMaxTransactions()),
+		Window:          rule.GetWindow(),
+		DrainPercent:    rule.GetDrainPercent(),
+		DormantFor:      rule.GetDormantFor(),
+	}
+	if rule.GetCreatedAt() != nil {
+		alertRule.CreatedAt = rule.GetCreatedAt().AsTime()
+	}
+	return alertRule
+}
+
+func wireTrace(trace *domain.TraceContext) *wirev1.TraceContext {
+	if trace == nil {
+		return nil
+	}
+	return &wirev1.TraceContext{
+		Traceparent: trace.Traceparent,
+		Tracestate:  trace.Tracestate,
+	}
+}
+
+func wireUserIDs(userIDs []domain.UserID) []int64 {
+	ids := make([]int64, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = int64(userID)
+	}
+	return ids
+}
+
+// dataJSON encodes a command's result data, leaving none empty
+func dataJSON(data any) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal command data: %w", err)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const alertRulesKey = "alert_rules"
+
+// AlertRuleRepository stores alert rules as JSON in a single hash keyed by
+// rule id
+type AlertRuleRepository struct {
+	client *redis.Client
+}
+
+func NewAlertRuleRepository(redisClient *Client) *AlertRuleRepository {
+	return &AlertRuleRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *AlertRuleRepository) SaveAlertRule(ctx context.Context, rule domain.AlertRule) error {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	return r.client.HSet(ctx, alertRulesKey, rule.ID, data).Err()
+}
+
+func (r *AlertRuleRepository) DeleteAlertRule(ctx context.Context, id string) error {
+	return r.client.HDel(ctx, alertRulesKey, id).Err()
+}
+
+func (r *AlertRuleRepository) GetAllAlertRules(ctx context.Context) ([]domain.AlertRule, error) {
+	entries, err := r.client.HGetAll(ctx, alertRulesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]domain.AlertRule, 0, len(entries))
+	for id, data := range entries {
+		var rule domain.AlertRule
+		if err := json.Unmarshal([]byte(data), &rule); err != nil {
+			return nil, fmt.Errorf("invalid alert rule %s: %w", id, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
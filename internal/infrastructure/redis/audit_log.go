@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Entries read per XREVRANGE while scanning for query matches
+const auditPageSize = 500
+
+// AuditLog appends audit entries to a Redis stream. Stream ids are
+// millisecond timestamps, so time-bounded queries only read that range.
+type AuditLog struct {
+	client *redis.Client
+	stream string
+}
+
+func NewAuditLog(redisClient *Client, stream string) *AuditLog {
+	return &AuditLog{
+		client: redisClient.GetRedisClient(),
+		stream: stream,
+	}
+}
+
+func (l *AuditLog) Append(ctx context.Context, entry domain.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	return l.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: l.stream,
+		Values: map[string]any{streamDataField: data},
+	}).Err()
+}
+
+func (l *AuditLog) Query(ctx context.Context, query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	start, end := "-", "+"
+	if !query.Since.IsZero() {
+		start = fmt.Sprintf("%d-0", query.Since.UnixMilli())
+	}
+	if !query.Until.IsZero() {
+		end = fmt.Sprintf("%d", query.Until.UnixMilli())
+	}
+
+	var entries []domain.AuditEntry
+	for len(entries) < query.Limit {
+		messages, err := l.client.XRevRangeN(ctx, l.stream, end, start, auditPageSize).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+
+		for _, message := range messages {
+			data, ok := message.Values[streamDataField].(string)
+			if !ok {
+				continue
+			}
+			var entry domain.AuditEntry
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				continue
+			}
+			entry.ID = message.ID
+
+			if query.Matches(entry) {
+				entries = append(entries, entry)
+				if len(entries) == query.Limit {
+					break
+				}
+			}
+		}
+
+		if len(messages) < auditPageSize {
+			break
+		}
+		end = "(" + messages[len(messages)-1].ID
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CheckpointStore struct {
+	client *redis.Client
+	key    string
+}
+
+func NewCheckpointStore(redisClient *Client, key string) *CheckpointStore {
+	return &CheckpointStore{
+		client: redisClient.GetRedisClient(),
+		key:    key,
+	}
+}
+
+func (s *CheckpointStore) LoadCheckpoint(ctx context.Context) (uint64, bool, error) {
+	blockNumber, err := s.client.Get(ctx, s.key).Uint64()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return blockNumber, true, nil
+}
+
+func (s *CheckpointStore) SaveCheckpoint(ctx context.Context, blockNumber uint64) error {
+	return s.client.Set(ctx, s.key, blockNumber, 0).Err()
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const contractSubscriptionsKey = "contract_subscriptions"
+
+// ContractRepository stores contract subscriptions in a single hash
+// ("contract:user id" -> subscription JSON)
+type ContractRepository struct {
+	client *redis.Client
+}
+
+func NewContractRepository(redisClient *Client) *ContractRepository {
+	return &ContractRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *ContractRepository) AddContractSubscription(
+	ctx context.Context,
+	subscription domain.ContractSubscription,
+) error {
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+
+	field := contractSubscriptionField(subscription.ContractAddress, subscription.UserID)
+	return r.client.HSet(ctx, contractSubscriptionsKey, field, data).Err()
+}
+
+func (r *ContractRepository) RemoveContractSubscription(
+	ctx context.Context,
+	contractAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	field := contractSubscriptionField(contractAddress, userID)
+	return r.client.HDel(ctx, contractSubscriptionsKey, field).Err()
+}
+
+func (r *ContractRepository) GetContractSubscriptions(
+	ctx context.Context,
+) ([]domain.ContractSubscription, error) {
+	entries, err := r.client.HGetAll(ctx, contractSubscriptionsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]domain.ContractSubscription, 0, len(entries))
+	for field, data := range entries {
+		var subscription domain.ContractSubscription
+		if err := json.Unmarshal([]byte(data), &subscription); err != nil {
+			return nil, fmt.Errorf("invalid contract subscription %q: %w", field, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func contractSubscriptionField(contractAddress domain.WalletAddress, userID domain.UserID) string {
+	return string(contractAddress) + ":" + strconv.FormatInt(int64(userID), 10)
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// DeadLetterQueue keeps dead letters in a Redis stream capped at about
+// maxLen entries, the oldest being dropped beyond
+type DeadLetterQueue struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewDeadLetterQueue(redisClient *Client, stream string, maxLen int64) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		client: redisClient.GetRedisClient(),
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (q *DeadLetterQueue) Add(ctx context.Context, letter domain.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	return q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream,
+		MaxLen: q.maxLen,
+		Approx: true,
+		Values: map[string]any{streamDataField: data},
+	}).Err()
+}
+
+func (q *DeadLetterQueue) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	messages, err := q.client.XRangeN(ctx, q.stream, "-", "+", int64(limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return deadLetters(messages), nil
+}
+
+func (q *DeadLetterQueue) Get(ctx context.Context, ids []string) ([]domain.DeadLetter, error) {
+	var letters []domain.DeadLetter
+	for _, id := range ids {
+		messages, err := q.client.XRange(ctx, q.stream, id, id).Result()
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, deadLetters(messages)...)
+	}
+	return letters, nil
+}
+
+func (q *DeadLetterQueue) Delete(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) > 0 {
+		return q.client.XDel(ctx, q.stream, ids...).Result()
+	}
+
+	n, err := q.client.XLen(ctx, q.stream).Result()
+	if err != nil {
+		return 0, err
+	}
+	if err := q.client.Del(ctx, q.stream).Err(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (q *DeadLetterQueue) Len(ctx context.Context) (int64, error) {
+	return q.client.XLen(ctx, q.stream).Result()
+}
+
+// deadLetters decodes stream messages, skipping malformed ones
+func deadLetters(messages []redis.XMessage) []domain.DeadLetter {
+	letters := make([]domain.DeadLetter, 0, len(messages))
+	for _, message := range messages {
+		data, ok := message.Values[streamDataField].(string)
+		if !ok {
+			continue
+		}
+		var letter domain.DeadLetter
+		if err := json.Unmarshal([]byte(data), &letter); err != nil {
+			continue
+		}
+		letter.ID = message.ID
+		letters = append(letters, letter)
+	}
+	return letters
+}
+
+// deadLetterCommand adds a command received as payload that can't be
+// handled to queue, if set
+func deadLetterCommand(
+	ctx context.Context,
+	queue domain.DeadLetterQueue,
+	payload []byte,
+	cause error,
+	logger *zap.Logger,
+) {
+	if queue == nil {
+		return
+	}
+	if err := queue.Add(context.WithoutCancel(ctx), domain.NewDeadCommand(payload, cause)); err != nil {
+		logger.Error("Failed to dead-letter command", zap.Error(err))
+	}
+}
@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const dedupPrefix = "notified:"
+
+// DedupStore marks delivered notifications with SETNX keys that expire
+type DedupStore struct {
+	client *redis.Client
+}
+
+func NewDedupStore(redisClient *Client) *DedupStore {
+	return &DedupStore{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (s *DedupStore) Claim(ctx context.Context, keys []string, ttl time.Duration) ([]bool, error) {
+	cmds := make([]*redis.BoolCmd, len(keys))
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.SetNX(ctx, dedupPrefix+key, 1, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]bool, len(keys))
+	for i, cmd := range cmds {
+		claimed[i] = cmd.Val()
+	}
+	return claimed, nil
+}
+
+func (s *DedupStore) Release(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = dedupPrefix + key
+	}
+	return s.client.Del(ctx, prefixed...).Err()
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	gasUsageKeyPrefix    = "gas_usage:"
+	gasUsageDayKeyPrefix = "gas_usage_day:"
+)
+
+// Maximum optimistic-lock retries for a single gas usage update
+const gasUsageMaxRetries = 5
+
+type GasUsageRepository struct {
+	client *redis.Client
+}
+
+func NewGasUsageRepository(redisClient *Client) *GasUsageRepository {
+	return &GasUsageRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *GasUsageRepository) AddGasUsage(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	gasUsed uint64,
+	fee *big.Int,
+	at time.Time,
+) (*domain.GasUsage, error) {
+	key := gasUsageKey(walletAddress)
+	dayKey := gasUsageDayKey(walletAddress, at)
+
+	var usage *domain.GasUsage
+	update := func(tx *redis.Tx) error {
+		fields, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		dayFields, err := tx.HGetAll(ctx, dayKey).Result()
+		if err != nil {
+			return err
+		}
+
+		usage, err = parseGasUsage(walletAddress, fields)
+		if err != nil {
+			return err
+		}
+		day, err := parseGasUsage(walletAddress, dayFields)
+		if err != nil {
+			return err
+		}
+
+		// Fees are stored as decimal strings since they overflow int64
+		now := time.Now()
+		for _, u := range []*domain.GasUsage{usage, day} {
+			u.TxCount++
+			u.GasUsed += gasUsed
+			u.FeesPaid.Add(u.FeesPaid, fee)
+			u.UpdatedAt = now
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, gasUsageFields(usage)...)
+			pipe.HSet(ctx, dayKey, gasUsageFields(day)...)
+			pipe.Expire(ctx, dayKey, domain.GasUsageRetention+24*time.Hour)
+			return nil
+		})
+		return err
+	}
+
+	for range gasUsageMaxRetries {
+		err := r.client.Watch(ctx, update, key, dayKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return usage, nil
+	}
+
+	return nil, fmt.Errorf("gas usage update for %s: too many concurrent writers", walletAddress)
+}
+
+func (r *GasUsageRepository) GetGasUsage(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) (*domain.GasUsage, error) {
+	fields, err := r.client.HGetAll(ctx, gasUsageKey(walletAddress)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGasUsage(walletAddress, fields)
+}
+
+// GetGasUsageSince sums the daily totals of the wallet from since's UTC day
+// on
+func (r *GasUsageRepository) GetGasUsageSince(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	since time.Time,
+) (*domain.GasUsage, error) {
+	since = since.UTC().Truncate(24 * time.Hour)
+
+	var days []*redis.MapStringStringCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for day := since; !day.After(time.Now()); day = day.Add(24 * time.Hour) {
+			days = append(days, pipe.HGetAll(ctx, gasUsageDayKey(walletAddress, day)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &domain.GasUsage{
+		WalletAddress: walletAddress,
+		Since:         since,
+		FeesPaid:      new(big.Int),
+	}
+	for _, cmd := range days {
+		day, err := parseGasUsage(walletAddress, cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+		usage.TxCount += day.TxCount
+		usage.GasUsed += day.GasUsed
+		usage.FeesPaid.Add(usage.FeesPaid, day.FeesPaid)
+		if day.UpdatedAt.After(usage.UpdatedAt) {
+			usage.UpdatedAt = day.UpdatedAt
+		}
+	}
+	return usage, nil
+}
+
+func gasUsageKey(walletAddress domain.WalletAddress) string {
+	return gasUsageKeyPrefix + strings.ToLower(string(walletAddress))
+}
+
+// gasUsageDayKey is the key of the wallet's totals of the UTC day of at
+func gasUsageDayKey(walletAddress domain.WalletAddress, at time.Time) string {
+	day := at.UTC().Format(time.DateOnly)
+	return gasUsageDayKeyPrefix + strings.ToLower(string(walletAddress)) + ":" + day
+}
+
+func gasUsageFields(usage *domain.GasUsage) []any {
+	return []any{
+		"tx_count", usage.TxCount,
+		"gas_used", usage.GasUsed,
+		"fees_paid", usage.FeesPaid.String(),
+		"updated_at", usage.UpdatedAt.Unix(),
+	}
+}
+
+func parseGasUsage(
+	walletAddress domain.WalletAddress,
+	fields map[string]string,
+) (*domain.GasUsage, error) {
+	usage := &domain.GasUsage{
+		WalletAddress: walletAddress,
+		FeesPaid:      new(big.Int),
+	}
+	if len(fields) == 0 {
+		return usage, nil
+	}
+
+	var err error
+	if usage.TxCount, err = strconv.ParseUint(fields["tx_count"], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid tx_count: %w", err)
+	}
+	if usage.GasUsed, err = strconv.ParseUint(fields["gas_used"], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid gas_used: %w", err)
+	}
+	if _, ok := usage.FeesPaid.SetString(fields["fees_paid"], 10); !ok {
+		return nil, fmt.Errorf("invalid fees_paid: %q", fields["fees_paid"])
+	}
+	updatedAt, err := strconv.ParseInt(fields["updated_at"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid updated_at: %w", err)
+	}
+	usage.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return usage, nil
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	labelsKey = "address_labels"
+
+	// Number of labels written per pipelined HSET
+	labelBatchSize = 1000
+)
+
+type LabelRepository struct {
+	client *redis.Client
+}
+
+func NewLabelRepository(redisClient *Client) *LabelRepository {
+	return &LabelRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *LabelRepository) SetLabels(ctx context.Context, labels []domain.AddressLabel) error {
+	pipe := r.client.Pipeline()
+
+	for start := 0; start < len(labels); start += labelBatchSize {
+		end := min(start+labelBatchSize, len(labels))
+
+		values := make([]any, 0, (end-start)*2)
+		for _, label := range labels[start:end] {
+			values = append(values, string(label.Address), label.Label)
+		}
+		pipe.HSet(ctx, labelsKey, values...)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *LabelRepository) DeleteLabels(ctx context.Context, addresses []domain.WalletAddress) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		fields = append(fields, string(address))
+	}
+	return r.client.HDel(ctx, labelsKey, fields...).Err()
+}
+
+func (r *LabelRepository) GetAllLabels(ctx context.Context) ([]domain.AddressLabel, error) {
+	entries, err := r.client.HGetAll(ctx, labelsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]domain.AddressLabel, 0, len(entries))
+	for address, label := range entries {
+		labels = append(labels, domain.AddressLabel{
+			Address: domain.WalletAddress(address),
+			Label:   label,
+		})
+	}
+
+	return labels, nil
+}
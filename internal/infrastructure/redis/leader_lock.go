@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const leaderKey = "leader"
+
+var (
+	// Extend or delete the key only while it still names the instance, so
+	// a lease that expired and was taken by another is left alone
+	renewLeaderScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseLeaderScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// LeaderLock is a lease kept in a key naming its holder, expiring with it
+type LeaderLock struct {
+	client *redis.Client
+}
+
+func NewLeaderLock(redisClient *Client) *LeaderLock {
+	return &LeaderLock{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (l *LeaderLock) Acquire(ctx context.Context, instanceID string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, leaderKey, instanceID, ttl).Result()
+}
+
+func (l *LeaderLock) Renew(ctx context.Context, instanceID string, ttl time.Duration) (bool, error) {
+	renewed, err := renewLeaderScript.Run(ctx, l.client, []string{leaderKey}, instanceID, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return renewed == 1, nil
+}
+
+func (l *LeaderLock) Release(ctx context.Context, instanceID string) error {
+	return releaseLeaderScript.Run(ctx, l.client, []string{leaderKey}, instanceID).Err()
+}
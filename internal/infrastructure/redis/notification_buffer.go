@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const notificationEventsPrefix = "notification_events:"
+
+// NotificationBuffer keeps each user's recent notifications in a capped
+// Redis stream that expires once the user gets no notifications for ttl.
+// Event IDs are the stream IDs.
+type NotificationBuffer struct {
+	client *redis.Client
+	maxLen int64
+	ttl    time.Duration
+}
+
+func NewNotificationBuffer(redisClient *Client, maxLen int64, ttl time.Duration) *NotificationBuffer {
+	return &NotificationBuffer{
+		client: redisClient.GetRedisClient(),
+		maxLen: maxLen,
+		ttl:    ttl,
+	}
+}
+
+func (b *NotificationBuffer) Append(ctx context.Context, events []domain.NotificationEvent) ([]string, error) {
+	cmds := make([]*redis.StringCmd, len(events))
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, event := range events {
+			data, err := json.Marshal(event.Notification)
+			if err != nil {
+				return fmt.Errorf("failed to marshal notification: %w", err)
+			}
+
+			key := notificationEventsKey(event.UserID)
+			cmds[i] = pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: key,
+				MaxLen: b.maxLen,
+				Approx: true,
+				Values: map[string]any{streamDataField: data},
+			})
+			pipe.Expire(ctx, key, b.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to buffer notifications: %w", err)
+	}
+
+	ids := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		ids[i] = cmd.Val()
+	}
+	return ids, nil
+}
+
+func (b *NotificationBuffer) After(
+	ctx context.Context,
+	userID domain.UserID,
+	id string,
+	limit int,
+) ([]domain.NotificationEvent, error) {
+	if !validStreamID(id) {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidEventID, id)
+	}
+
+	messages, err := b.client.XRangeN(ctx, notificationEventsKey(userID), "("+id, "+", int64(limit)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read buffered notifications: %w", err)
+	}
+
+	events := make([]domain.NotificationEvent, 0, len(messages))
+	for _, message := range messages {
+		data, ok := message.Values[streamDataField].(string)
+		if !ok {
+			continue
+		}
+		event := domain.NotificationEvent{ID: message.ID, UserID: userID}
+		if err := json.Unmarshal([]byte(data), &event.Notification); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func notificationEventsKey(userID domain.UserID) string {
+	return notificationEventsPrefix + strconv.FormatInt(int64(userID), 10)
+}
+
+// validStreamID reports whether id has the <milliseconds>-<sequence> form
+// of a Redis stream entry ID
+func validStreamID(id string) bool {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return false
+	}
+	_, errMs := strconv.ParseUint(ms, 10, 64)
+	_, errSeq := strconv.ParseUint(seq, 10, 64)
+	return errMs == nil && errSeq == nil
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Outbox orders entry IDs by a sequence number taken when they are added in
+// a sorted set and keeps the entries in a hash. Instances may share it: an
+// entry is removed by ID, so two of them publishing the same one only
+// duplicate it.
+type Outbox struct {
+	client     *redis.Client
+	key        string
+	entriesKey string
+	seqKey     string
+}
+
+func NewOutbox(redisClient *Client, key string) *Outbox {
+	return &Outbox{
+		client:     redisClient.GetRedisClient(),
+		key:        key,
+		entriesKey: key + "_entries",
+		seqKey:     key + "_seq",
+	}
+}
+
+func (o *Outbox) Add(ctx context.Context, entries ...domain.OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	values := make([][]byte, len(entries))
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		values[i] = data
+	}
+
+	// Sequence numbers stay exact as scores, unlike nanosecond timestamps,
+	// and don't depend on the clocks of the instances sharing the outbox
+	last, err := o.client.IncrBy(ctx, o.seqKey, int64(len(entries))).Result()
+	if err != nil {
+		return fmt.Errorf("failed to number outbox entries: %w", err)
+	}
+	first := last - int64(len(entries)) + 1
+
+	_, err = o.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, entry := range entries {
+			pipe.HSet(ctx, o.entriesKey, entry.ID, values[i])
+			pipe.ZAdd(ctx, o.key, redis.Z{Score: float64(first + int64(i)), Member: entry.ID})
+		}
+		return nil
+	})
+	return err
+}
+
+func (o *Outbox) Oldest(ctx context.Context) (*domain.OutboxEntry, error) {
+	for {
+		ids, err := o.client.ZRange(ctx, o.key, 0, 0).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, nil
+		}
+
+		data, err := o.client.HGet(ctx, o.entriesKey, ids[0]).Bytes()
+		if errors.Is(err, redis.Nil) {
+			// Removed by another instance in between
+			o.client.ZRem(ctx, o.key, ids[0])
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var entry domain.OutboxEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, &domain.InvalidOutboxEntryError{ID: ids[0], Data: data, Err: err}
+		}
+		return &entry, nil
+	}
+}
+
+func (o *Outbox) Remove(ctx context.Context, id string) error {
+	_, err := o.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, o.key, id)
+		pipe.HDel(ctx, o.entriesKey, id)
+		return nil
+	})
+	return err
+}
+
+func (o *Outbox) Len(ctx context.Context) (int, error) {
+	n, err := o.client.ZCard(ctx, o.key).Result()
+	return int(n), err
+}
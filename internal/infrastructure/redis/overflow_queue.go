@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OverflowQueue keeps a FIFO of queued transactions per worker in Redis
+// lists named after the key and the worker
+type OverflowQueue struct {
+	client *redis.Client
+	key    string
+}
+
+func NewOverflowQueue(redisClient *Client, key string) *OverflowQueue {
+	return &OverflowQueue{
+		client: redisClient.GetRedisClient(),
+		key:    key,
+	}
+}
+
+func (q *OverflowQueue) listKey(worker int) string {
+	return q.key + ":" + strconv.Itoa(worker)
+}
+
+func (q *OverflowQueue) Push(ctx context.Context, worker int, item domain.QueuedTransaction) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return q.client.RPush(ctx, q.listKey(worker), data).Err()
+}
+
+func (q *OverflowQueue) PushFront(ctx context.Context, worker int, items []domain.QueuedTransaction) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	// LPUSH prepends one by one, so the last item goes first
+	values := make([]any, len(items))
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		values[len(items)-1-i] = data
+	}
+	return q.client.LPush(ctx, q.listKey(worker), values...).Err()
+}
+
+func (q *OverflowQueue) Pop(ctx context.Context, worker int) (*domain.QueuedTransaction, error) {
+	data, err := q.client.LPop(ctx, q.listKey(worker)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var item domain.QueuedTransaction
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("invalid queued transaction: %w", err)
+	}
+	return &item, nil
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PendingStore keeps transactions held for confirmations in a hash, and
+// their IDs in a sorted set scored by block number
+type PendingStore struct {
+	client     *redis.Client
+	key        string
+	entriesKey string
+}
+
+func NewPendingStore(redisClient *Client, key string) *PendingStore {
+	return &PendingStore{
+		client:     redisClient.GetRedisClient(),
+		key:        key,
+		entriesKey: key + "_entries",
+	}
+}
+
+// pendingID identifies a held transaction of a wallet
+func pendingID(item domain.QueuedTransaction) string {
+	return string(item.WalletAddress) + ":" + string(item.Transaction.Hash)
+}
+
+func (s *PendingStore) AddPending(ctx context.Context, item domain.QueuedTransaction) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	id := pendingID(item)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.entriesKey, id, data)
+		pipe.ZAdd(ctx, s.key, redis.Z{Score: float64(item.Transaction.BlockNumber), Member: id})
+		return nil
+	})
+	return err
+}
+
+func (s *PendingStore) RemovePending(ctx context.Context, items []domain.QueuedTransaction) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(items))
+	members := make([]any, len(items))
+	for i, item := range items {
+		ids[i] = pendingID(item)
+		members[i] = ids[i]
+	}
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, s.key, members...)
+		pipe.HDel(ctx, s.entriesKey, ids...)
+		return nil
+	})
+	return err
+}
+
+func (s *PendingStore) LoadPending(ctx context.Context) ([]domain.QueuedTransaction, error) {
+	ids, err := s.client.ZRange(ctx, s.key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := s.client.HMGet(ctx, s.entriesKey, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.QueuedTransaction, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue // Removed in between
+		}
+
+		var item domain.QueuedTransaction
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("invalid pending transaction %s: %w", ids[i], err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
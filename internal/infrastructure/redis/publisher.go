@@ -2,52 +2,255 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 
 	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/routing"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type Publisher struct {
-	client  *redis.Client
-	channel string
-	logger  *zap.Logger
+	client   *redis.Client
+	channels routing.Channels
+	codec    codec.Codec
+	logger   *zap.Logger
 }
 
-func NewPublisher(redisClient *Client, logger *zap.Logger) *Publisher {
+func NewPublisher(redisClient *Client, channels routing.Channels, codec codec.Codec, logger *zap.Logger) *Publisher {
 	return &Publisher{
-		client:  redisClient.GetRedisClient(),
-		channel: "wallet_notifications", // TODO: get from config
-		logger:  logger,
+		client:   redisClient.GetRedisClient(),
+		channels: channels,
+		codec:    codec,
+		logger:   logger,
 	}
 }
 
+// PublishNotification publishes notification on each channel it is routed
+// to, trying them all even if some fail
 func (p *Publisher) PublishNotification(
 	ctx context.Context,
 	notification domain.WalletNotification,
 ) error {
-	data, err := json.Marshal(notification)
+	var errs []error
+	for _, route := range p.channels.Notification(notification) {
+		data, err := p.codec.Marshal(route.Notification)
+		if err != nil {
+			p.logger.Error("Failed to marshal notification", zap.Error(err))
+			return err
+		}
+
+		err = p.publish(ctx, "notification", route.Channel, data)
+		if err != nil {
+			p.logger.Error("Failed to publish notification to Redis",
+				zap.String("channel", route.Channel),
+				zap.Error(err),
+			)
+			errs = append(errs, err)
+			continue
+		}
+
+		p.logger.Debug("Published notification",
+			zap.String("channel", route.Channel),
+			zap.String("wallet", string(notification.WalletAddress)),
+			zap.Int("subscribers", len(route.Notification.Subscribers)),
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Publisher) PublishResponse(ctx context.Context, response domain.CommandResponse) error {
+	data, err := p.codec.Marshal(response)
 	if err != nil {
-		p.logger.Error("Failed to marshal notification", zap.Error(err))
+		p.logger.Error("Failed to marshal response", zap.Error(err))
 		return err
 	}
 
-	err = p.client.Publish(ctx, p.channel, data).Err()
+	err = p.publish(ctx, "response", p.channels.Responses, data)
 	if err != nil {
-		p.logger.Error("Failed to publish notification to Redis",
-			zap.String("channel", p.channel),
+		p.logger.Error("Failed to publish response to Redis",
+			zap.String("channel", p.channels.Responses),
 			zap.Error(err),
 		)
 		return err
 	}
 
-	p.logger.Debug("Published notification",
-		zap.String("channel", p.channel),
-		zap.String("wallet", string(notification.WalletAddress)),
+	p.logger.Debug("Published response",
+		zap.String("channel", p.channels.Responses),
+		zap.String("type", string(response.Type)),
+		zap.Int64("user_id", int64(response.UserID)),
+	)
+
+	return nil
+}
+
+func (p *Publisher) PublishContractEvent(
+	ctx context.Context,
+	notification domain.ContractEventNotification,
+) error {
+	data, err := p.codec.Marshal(notification)
+	if err != nil {
+		p.logger.Error("Failed to marshal contract event", zap.Error(err))
+		return err
+	}
+
+	err = p.publish(ctx, "contract_event", p.channels.ContractEvents, data)
+	if err != nil {
+		p.logger.Error("Failed to publish contract event to Redis",
+			zap.String("channel", p.channels.ContractEvents),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.logger.Debug("Published contract event",
+		zap.String("channel", p.channels.ContractEvents),
+		zap.String("contract", string(notification.Event.ContractAddress)),
+		zap.String("event", notification.Event.Event),
 		zap.Int("subscribers", len(notification.Subscribers)),
 	)
 
 	return nil
 }
+
+func (p *Publisher) PublishTokenTransfer(
+	ctx context.Context,
+	notification domain.TokenTransferNotification,
+) error {
+	data, err := p.codec.Marshal(notification)
+	if err != nil {
+		p.logger.Error("Failed to marshal token transfer", zap.Error(err))
+		return err
+	}
+
+	err = p.publish(ctx, "token_transfer", p.channels.TokenTransfers, data)
+	if err != nil {
+		p.logger.Error("Failed to publish token transfer to Redis",
+			zap.String("channel", p.channels.TokenTransfers),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.logger.Debug("Published token transfer",
+		zap.String("channel", p.channels.TokenTransfers),
+		zap.String("token", notification.Event.Transfer.TokenAddress),
+		zap.String("tx_hash", string(notification.Event.Transfer.TxHash)),
+		zap.Int("subscribers", len(notification.Subscribers)),
+	)
+
+	return nil
+}
+
+func (p *Publisher) PublishAlert(ctx context.Context, notification domain.AlertNotification) error {
+	data, err := p.codec.Marshal(notification)
+	if err != nil {
+		p.logger.Error("Failed to marshal alert", zap.Error(err))
+		return err
+	}
+
+	err = p.publish(ctx, "alert", p.channels.Alerts, data)
+	if err != nil {
+		p.logger.Error("Failed to publish alert to Redis",
+			zap.String("channel", p.channels.Alerts),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.logger.Debug("Published alert",
+		zap.String("channel", p.channels.Alerts),
+		zap.String("rule_id", notification.Rule.ID),
+		zap.Int64("user_id", int64(notification.Rule.UserID)),
+	)
+
+	return nil
+}
+
+func (p *Publisher) PublishFirehose(ctx context.Context, block domain.FirehoseBlock) error {
+	data, err := p.codec.Marshal(block)
+	if err != nil {
+		p.logger.Error("Failed to marshal firehose block", zap.Error(err))
+		return err
+	}
+
+	err = p.publish(ctx, "firehose", p.channels.Firehose, data)
+	if err != nil {
+		p.logger.Error("Failed to publish firehose block to Redis",
+			zap.String("channel", p.channels.Firehose),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.logger.Debug("Published firehose block",
+		zap.String("channel", p.channels.Firehose),
+		zap.Uint64("block", block.BlockNumber),
+		zap.Int("transfers", len(block.Transfers)),
+	)
+
+	return nil
+}
+
+func (p *Publisher) PublishCommandResult(
+	ctx context.Context,
+	channel string,
+	result domain.CommandResult,
+) error {
+	data, err := p.codec.Marshal(result)
+	if err != nil {
+		p.logger.Error("Failed to marshal command result", zap.Error(err))
+		return err
+	}
+
+	err = p.publish(ctx, "command_result", channel, data)
+	if err != nil {
+		p.logger.Error("Failed to publish command result to Redis",
+			zap.String("channel", channel),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	p.logger.Debug("Published command result",
+		zap.String("channel", channel),
+		zap.String("correlation_id", result.CorrelationID),
+		zap.String("status", string(result.Status)),
+	)
+
+	return nil
+}
+
+// publish sends data to channel and counts the outcome under kind
+func (p *Publisher) publish(ctx context.Context, kind, channel string, data []byte) error {
+	_, span := startPublishSpan(ctx, kind, channel)
+	err := p.client.Publish(ctx, channel, data).Err()
+	tracing.End(span, err)
+	observePublish(kind, err)
+	return err
+}
+
+// startPublishSpan starts the span of handing a message of kind to Redis
+func startPublishSpan(ctx context.Context, kind, destination string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "redis.publish",
+		attribute.String("messaging.system", "redis"),
+		attribute.String("messaging.destination.name", destination),
+		attribute.String("message.kind", kind),
+	)
+}
+
+// observePublish counts a message of kind as published or failed
+func observePublish(kind string, err error) {
+	if err != nil {
+		metrics.PublishFailuresTotal.WithLabelValues(kind).Inc()
+		return
+	}
+	metrics.MessagesPublishedTotal.WithLabelValues(kind).Inc()
+}
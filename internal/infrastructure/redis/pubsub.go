@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Delays between attempts to subscribe again after a subscription failed
+const (
+	resubscribeMinBackoff = 500 * time.Millisecond
+	resubscribeMaxBackoff = 30 * time.Second
+)
+
+// subscribe passes the messages published on channel to handle until ctx
+// is done, subscribing again with backoff whenever the subscription fails.
+// A connection that went away silently is caught by pinging it once
+// nothing was received for healthInterval: without a reply within another
+// healthInterval, the subscription is renewed.
+func subscribe(
+	ctx context.Context,
+	client *redis.Client,
+	channel string,
+	healthInterval time.Duration,
+	logger *zap.Logger,
+	handle func(*redis.Message),
+) error {
+	retry := backoff.New(resubscribeMinBackoff, resubscribeMaxBackoff)
+	for {
+		err := receive(ctx, client, channel, healthInterval, retry, logger, handle)
+		metrics.PubSubSubscribed.WithLabelValues(channel).Set(0)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		metrics.PubSubResubscribesTotal.WithLabelValues(channel).Inc()
+		logger.Warn("Redis subscription lost, resubscribing",
+			zap.String("channel", channel),
+			zap.Int("attempt", retry.Attempt()+1),
+			zap.Error(err),
+		)
+		if !retry.Wait(ctx) {
+			return ctx.Err()
+		}
+	}
+}
+
+// receive subscribes to channel and passes its messages to handle until
+// the subscription fails or ctx is done
+func receive(
+	ctx context.Context,
+	client *redis.Client,
+	channel string,
+	healthInterval time.Duration,
+	retry *backoff.Backoff,
+	logger *zap.Logger,
+	handle func(*redis.Message),
+) error {
+	pubsub := client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Reads only time out on their own; closing interrupts them on shutdown
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+
+	pinged := false
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, healthInterval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				return err
+			}
+			if pinged {
+				return fmt.Errorf("no reply to ping within %s", healthInterval)
+			}
+			if err := pubsub.Ping(ctx); err != nil {
+				return fmt.Errorf("failed to ping: %w", err)
+			}
+			pinged = true
+			continue
+		}
+		pinged = false
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			if msg.Kind != "subscribe" {
+				continue
+			}
+			metrics.PubSubSubscribed.WithLabelValues(channel).Set(1)
+			if retry.Attempt() > 0 {
+				logger.Info("Redis subscription recovered",
+					zap.String("channel", channel),
+					zap.Int("attempts", retry.Attempt()),
+				)
+				retry.Reset()
+			} else {
+				logger.Info("Subscribed to Redis channel", zap.String("channel", channel))
+			}
+		case *redis.Message:
+			handle(msg)
+		}
+	}
+}
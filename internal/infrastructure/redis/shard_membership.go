@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const shardMembersKey = "shard_members"
+
+// ShardMembership keeps live instances in a sorted set scored by when their
+// membership expires. Expiry is measured on the Redis clock so instances
+// don't need synchronized clocks.
+type ShardMembership struct {
+	client *redis.Client
+}
+
+func NewShardMembership(redisClient *Client) *ShardMembership {
+	return &ShardMembership{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (m *ShardMembership) Heartbeat(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error) {
+	now, err := m.client.Time(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var members *redis.StringSliceCmd
+	_, err = m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, shardMembersKey, redis.Z{
+			Score:  float64(now.Add(ttl).UnixMilli()),
+			Member: instanceID,
+		})
+		pipe.ZRemRangeByScore(ctx, shardMembersKey, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+		members = pipe.ZRange(ctx, shardMembersKey, 0, -1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	live := members.Val()
+	slices.Sort(live)
+	return live, nil
+}
+
+func (m *ShardMembership) Leave(ctx context.Context, instanceID string) error {
+	return m.client.ZRem(ctx, shardMembersKey, instanceID).Err()
+}
@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/routing"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Field of stream entries holding the JSON payload
+const streamDataField = "data"
+
+// StreamPublisher appends messages to Redis Streams instead of Pub/Sub, so
+// consumer groups receive them at least once and can replay them after a
+// restart. Streams are capped at roughly maxLen entries each.
+type StreamPublisher struct {
+	client  *redis.Client
+	streams routing.Channels
+	maxLen  int64
+	codec   codec.Codec
+	logger  *zap.Logger
+}
+
+func NewStreamPublisher(
+	redisClient *Client,
+	streams routing.Channels,
+	maxLen int64,
+	codec codec.Codec,
+	logger *zap.Logger,
+) *StreamPublisher {
+	return &StreamPublisher{
+		client:  redisClient.GetRedisClient(),
+		streams: streams,
+		maxLen:  maxLen,
+		codec:   codec,
+		logger:  logger,
+	}
+}
+
+// PublishNotification appends notification to each stream it is routed
+// to, trying them all even if some fail
+func (p *StreamPublisher) PublishNotification(
+	ctx context.Context,
+	notification domain.WalletNotification,
+) error {
+	var errs []error
+	for _, route := range p.streams.Notification(notification) {
+		id, err := p.add(ctx, "notification", route.Channel, route.Notification)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		p.logger.Debug("Appended notification",
+			zap.String("stream", route.Channel),
+			zap.String("id", id),
+			zap.String("wallet", string(notification.WalletAddress)),
+			zap.Int("subscribers", len(route.Notification.Subscribers)),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+func (p *StreamPublisher) PublishResponse(ctx context.Context, response domain.CommandResponse) error {
+	id, err := p.add(ctx, "response", p.streams.Responses, response)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Appended response",
+		zap.String("stream", p.streams.Responses),
+		zap.String("id", id),
+		zap.String("type", string(response.Type)),
+		zap.Int64("user_id", int64(response.UserID)),
+	)
+	return nil
+}
+
+func (p *StreamPublisher) PublishContractEvent(
+	ctx context.Context,
+	notification domain.ContractEventNotification,
+) error {
+	id, err := p.add(ctx, "contract_event", p.streams.ContractEvents, notification)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Appended contract event",
+		zap.String("stream", p.streams.ContractEvents),
+		zap.String("id", id),
+		zap.String("contract", string(notification.Event.ContractAddress)),
+		zap.String("event", notification.Event.Event),
+	)
+	return nil
+}
+
+func (p *StreamPublisher) PublishTokenTransfer(
+	ctx context.Context,
+	notification domain.TokenTransferNotification,
+) error {
+	id, err := p.add(ctx, "token_transfer", p.streams.TokenTransfers, notification)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Appended token transfer",
+		zap.String("stream", p.streams.TokenTransfers),
+		zap.String("id", id),
+		zap.String("token", notification.Event.Transfer.TokenAddress),
+		zap.String("tx_hash", string(notification.Event.Transfer.TxHash)),
+	)
+	return nil
+}
+
+func (p *StreamPublisher) PublishAlert(ctx context.Context, notification domain.AlertNotification) error {
+	id, err := p.add(ctx, "alert", p.streams.Alerts, notification)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Appended alert",
+		zap.String("stream", p.streams.Alerts),
+		zap.String("id", id),
+		zap.String("rule_id", notification.Rule.ID),
+	)
+	return nil
+}
+
+func (p *StreamPublisher) PublishFirehose(ctx context.Context, block domain.FirehoseBlock) error {
+	id, err := p.add(ctx, "firehose", p.streams.Firehose, block)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Appended firehose block",
+		zap.String("stream", p.streams.Firehose),
+		zap.String("id", id),
+		zap.Uint64("block", block.BlockNumber),
+		zap.Int("transfers", len(block.Transfers)),
+	)
+	return nil
+}
+
+// PublishCommandResult appends result to the stream named by channel
+func (p *StreamPublisher) PublishCommandResult(
+	ctx context.Context,
+	channel string,
+	result domain.CommandResult,
+) error {
+	id, err := p.add(ctx, "command_result", channel, result)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Debug("Appended command result",
+		zap.String("stream", channel),
+		zap.String("id", id),
+		zap.String("correlation_id", result.CorrelationID),
+		zap.String("status", string(result.Status)),
+	)
+	return nil
+}
+
+// add appends payload in the publisher's codec to stream, counted under kind, and returns
+// the entry id
+func (p *StreamPublisher) add(ctx context.Context, kind, stream string, payload any) (string, error) {
+	data, err := p.codec.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	_, span := startPublishSpan(ctx, kind, stream)
+	id, err := p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]any{streamDataField: data},
+	}).Result()
+	tracing.End(span, err)
+	observePublish(kind, err)
+	if err != nil {
+		p.logger.Error("Failed to append message to Redis stream",
+			zap.String("stream", stream),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	return id, nil
+}
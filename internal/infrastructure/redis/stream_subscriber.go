@@ -0,0 +1,243 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Times a command is delivered before it is given up on and dead-lettered
+const maxCommandDeliveries = 5
+
+// StreamSubscriber reads commands from a Redis Stream as a member of a
+// consumer group. Commands are acknowledged only once handled, and commands
+// left pending by a crashed or stuck consumer are reclaimed and retried.
+type StreamSubscriber struct {
+	client      *redis.Client
+	stream      string
+	group       string
+	consumer    string
+	claimIdle   time.Duration
+	codec       codec.Codec
+	deadLetters domain.DeadLetterQueue // nil = off
+	logger      *zap.Logger
+}
+
+func NewStreamSubscriber(
+	redisClient *Client,
+	stream string,
+	group string,
+	consumer string,
+	claimIdle time.Duration,
+	codec codec.Codec,
+	deadLetters domain.DeadLetterQueue,
+	logger *zap.Logger,
+) *StreamSubscriber {
+	return &StreamSubscriber{
+		client:      redisClient.GetRedisClient(),
+		stream:      stream,
+		group:       group,
+		consumer:    consumer,
+		claimIdle:   claimIdle,
+		codec:       codec,
+		deadLetters: deadLetters,
+		logger:      logger,
+	}
+}
+
+func (s *StreamSubscriber) SubscribeCommands(
+	ctx context.Context,
+	handler func(domain.Command) error,
+) error {
+	// A new group starts at the beginning so commands sent before the
+	// tracker first ran aren't skipped
+	err := s.client.XGroupCreateMkStream(ctx, s.stream, s.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	s.logger.Info("Consuming commands stream",
+		zap.String("stream", s.stream),
+		zap.String("group", s.group),
+		zap.String("consumer", s.consumer),
+	)
+
+	go s.reclaimLoop(ctx, handler)
+
+	// Commands this consumer received before a restart first, then new ones
+	start := "0"
+	retry := backoff.New(resubscribeMinBackoff, resubscribeMaxBackoff)
+	for {
+		streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    s.group,
+			Consumer: s.consumer,
+			Streams:  []string{s.stream, start},
+			Count:    100,
+			Block:    5 * time.Second,
+		}).Result()
+		if ctx.Err() != nil {
+			s.logger.Info("Command subscriber stopped")
+			return ctx.Err()
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			s.logger.Error("Failed to read commands stream",
+				zap.Int("attempt", retry.Attempt()+1),
+				zap.Error(err),
+			)
+			retry.Wait(ctx)
+			continue
+		}
+		if retry.Attempt() > 0 {
+			s.logger.Info("Commands stream recovered", zap.Int("attempts", retry.Attempt()))
+			retry.Reset()
+		}
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		received := 0
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				received++
+				if start == "0" {
+					s.handle(ctx, message, handler) // Keep replayed commands in order
+				} else {
+					go s.handle(ctx, message, handler)
+				}
+			}
+		}
+		if start == "0" && received == 0 {
+			start = ">"
+		}
+	}
+}
+
+// handle runs the command and acknowledges it unless it failed in a way
+// worth retrying
+func (s *StreamSubscriber) handle(
+	ctx context.Context,
+	message redis.XMessage,
+	handler func(domain.Command) error,
+) {
+	data, _ := message.Values[streamDataField].(string)
+
+	var cmd domain.Command
+	if err := s.codec.Unmarshal([]byte(data), &cmd); err != nil {
+		s.logger.Error("Failed to unmarshal command",
+			zap.String("id", message.ID),
+			zap.String("payload", data),
+			zap.Error(err),
+		)
+		deadLetterCommand(ctx, s.deadLetters, []byte(data), err, s.logger)
+		s.ack(ctx, message.ID) // Would never succeed
+		return
+	}
+
+	if err := handler(cmd); err != nil {
+		s.logger.Warn("Command left pending for retry",
+			zap.String("id", message.ID),
+			zap.String("type", string(cmd.Type)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	s.ack(ctx, message.ID)
+}
+
+func (s *StreamSubscriber) ack(ctx context.Context, id string) {
+	if err := s.client.XAck(context.WithoutCancel(ctx), s.stream, s.group, id).Err(); err != nil {
+		s.logger.Error("Failed to acknowledge command", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// reclaimLoop periodically takes over commands that stayed unacknowledged
+// for claimIdle, whichever consumer they were delivered to
+func (s *StreamSubscriber) reclaimLoop(ctx context.Context, handler func(domain.Command) error) {
+	ticker := time.NewTicker(max(s.claimIdle/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.reclaim(ctx, handler); err != nil && ctx.Err() == nil {
+				s.logger.Error("Failed to reclaim pending commands", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *StreamSubscriber) reclaim(ctx context.Context, handler func(domain.Command) error) error {
+	pending, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: s.stream,
+		Group:  s.group,
+		Idle:   s.claimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, entry := range pending {
+		if entry.RetryCount >= maxCommandDeliveries {
+			s.logger.Error("Dropping command that keeps failing",
+				zap.String("id", entry.ID),
+				zap.Int64("deliveries", entry.RetryCount),
+			)
+			s.deadLetter(ctx, entry)
+			s.ack(ctx, entry.ID)
+			continue
+		}
+		ids = append(ids, entry.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	messages, err := s.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   s.stream,
+		Group:    s.group,
+		Consumer: s.consumer,
+		MinIdle:  s.claimIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Reclaimed pending commands", zap.Int("count", len(messages)))
+	for _, message := range messages {
+		s.handle(ctx, message, handler)
+	}
+	return nil
+}
+
+// deadLetter adds the command of a pending entry to the dead letter queue
+func (s *StreamSubscriber) deadLetter(ctx context.Context, entry redis.XPendingExt) {
+	if s.deadLetters == nil {
+		return
+	}
+
+	messages, err := s.client.XRange(ctx, s.stream, entry.ID, entry.ID).Result()
+	if err != nil || len(messages) == 0 {
+		s.logger.Error("Failed to read command to dead-letter", zap.String("id", entry.ID), zap.Error(err))
+		return
+	}
+	data, _ := messages[0].Values[streamDataField].(string)
+	cause := fmt.Errorf("delivered %d times without success", entry.RetryCount)
+	deadLetterCommand(ctx, s.deadLetters, []byte(data), cause, s.logger)
+}
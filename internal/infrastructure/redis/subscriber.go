@@ -2,62 +2,74 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"time"
 
 	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/infrastructure/codec"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 type Subscriber struct {
-	client  *redis.Client
-	channel string
-	logger  *zap.Logger
+	client         *redis.Client
+	channel        string
+	healthInterval time.Duration
+	codec          codec.Codec
+	deadLetters    domain.DeadLetterQueue // nil = off
+	logger         *zap.Logger
 }
 
-func NewSubscriber(redisClient *Client, logger *zap.Logger) *Subscriber {
+// NewSubscriber returns a subscriber to channel checking its subscription
+// when no message came for healthInterval
+func NewSubscriber(
+	redisClient *Client,
+	channel string,
+	healthInterval time.Duration,
+	codec codec.Codec,
+	deadLetters domain.DeadLetterQueue,
+	logger *zap.Logger,
+) *Subscriber {
 	return &Subscriber{
-		client:  redisClient.GetRedisClient(),
-		channel: "wallet_commands", // TODO: get from config
-		logger:  logger,
+		client:         redisClient.GetRedisClient(),
+		channel:        channel,
+		healthInterval: healthInterval,
+		codec:          codec,
+		deadLetters:    deadLetters,
+		logger:         logger,
 	}
 }
 
-func (s *Subscriber) SubscribeCommands(ctx context.Context, handler func(domain.Command)) error {
-	pubsub := s.client.Subscribe(ctx, s.channel)
-	defer pubsub.Close()
-
-	s.logger.Info("Subscribed to commands channel", zap.String("channel", s.channel))
-
-	ch := pubsub.Channel()
-	for {
-		select {
-		case <-ctx.Done():
-			s.logger.Info("Command subscriber stopped")
-			return ctx.Err()
-		case msg := <-ch:
-			if msg == nil {
-				continue
-			}
+// SubscribeCommands delivers commands at most once; failed commands are
+// dead-lettered, or lost without a dead letter queue. Commands published
+// while the subscription is being renewed are lost too.
+func (s *Subscriber) SubscribeCommands(ctx context.Context, handler func(domain.Command) error) error {
+	err := subscribe(ctx, s.client, s.channel, s.healthInterval, s.logger, func(msg *redis.Message) {
+		var cmd domain.Command
+		if err := s.codec.Unmarshal([]byte(msg.Payload), &cmd); err != nil {
+			s.logger.Error("Failed to unmarshal command",
+				zap.String("payload", msg.Payload),
+				zap.Error(err),
+			)
+			deadLetterCommand(ctx, s.deadLetters, []byte(msg.Payload), err, s.logger)
+			return
+		}
 
-			var cmd domain.Command
-			if err := json.Unmarshal([]byte(msg.Payload), &cmd); err != nil {
-				s.logger.Error("Failed to unmarshal command",
-					zap.String("payload", msg.Payload),
-					zap.Error(err),
-				)
-				continue
-			}
+		s.logger.Debug("Received command",
+			zap.String("type", string(cmd.Type)),
+			zap.String("wallet", string(cmd.WalletAddress)),
+			zap.Int64("user_id", int64(cmd.UserID)),
+		)
 
-			s.logger.Debug("Received command",
-				zap.String("type", string(cmd.Type)),
-				zap.String("wallet", string(cmd.WalletAddress)),
-				zap.Int64("user_id", int64(cmd.UserID)),
-			)
+		// Handle command in separate goroutine to avoid blocking
+		go func() {
+			if err := handler(cmd); err != nil {
+				s.logger.Warn("Command failed", zap.String("type", string(cmd.Type)), zap.Error(err))
+				deadLetterCommand(ctx, s.deadLetters, []byte(msg.Payload), err, s.logger)
+			}
+		}()
+	})
 
-			// Handle command in separate goroutine to avoid blocking
-			go handler(cmd)
-		}
-	}
+	s.logger.Info("Command subscriber stopped")
+	return err
 }
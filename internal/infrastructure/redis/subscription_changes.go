@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// SubscriptionChanges broadcasts subscription changes over Redis pub/sub
+type SubscriptionChanges struct {
+	client         *redis.Client
+	channel        string
+	healthInterval time.Duration
+	logger         *zap.Logger
+}
+
+func NewSubscriptionChanges(
+	redisClient *Client,
+	channel string,
+	healthInterval time.Duration,
+	logger *zap.Logger,
+) *SubscriptionChanges {
+	return &SubscriptionChanges{
+		client:         redisClient.GetRedisClient(),
+		channel:        channel,
+		healthInterval: healthInterval,
+		logger:         logger,
+	}
+}
+
+func (s *SubscriptionChanges) PublishSubscriptionChange(ctx context.Context, change domain.SubscriptionChange) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, s.channel, data).Err()
+}
+
+func (s *SubscriptionChanges) SubscribeSubscriptionChanges(
+	ctx context.Context,
+	handler func(domain.SubscriptionChange),
+) error {
+	return subscribe(ctx, s.client, s.channel, s.healthInterval, s.logger, func(msg *redis.Message) {
+		var change domain.SubscriptionChange
+		if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+			s.logger.Error("Failed to unmarshal subscription change",
+				zap.String("payload", msg.Payload),
+				zap.Error(err),
+			)
+			return
+		}
+		handler(change)
+	})
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const tokenFiltersKey = "token_filters"
+
+// TokenFilterRepository stores each user's token filter as JSON in a single
+// hash keyed by user id
+type TokenFilterRepository struct {
+	client *redis.Client
+}
+
+func NewTokenFilterRepository(redisClient *Client) *TokenFilterRepository {
+	return &TokenFilterRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *TokenFilterRepository) SaveTokenFilter(
+	ctx context.Context,
+	userID domain.UserID,
+	filter domain.TokenFilter,
+) error {
+	field := strconv.FormatInt(int64(userID), 10)
+
+	// Don't keep empty filters around
+	if len(filter.Allow) == 0 && len(filter.Deny) == 0 {
+		return r.client.HDel(ctx, tokenFiltersKey, field).Err()
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	return r.client.HSet(ctx, tokenFiltersKey, field, data).Err()
+}
+
+func (r *TokenFilterRepository) GetAllTokenFilters(
+	ctx context.Context,
+) (map[domain.UserID]domain.TokenFilter, error) {
+	entries, err := r.client.HGetAll(ctx, tokenFiltersKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make(map[domain.UserID]domain.TokenFilter, len(entries))
+	for field, data := range entries {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token filter user id %q: %w", field, err)
+		}
+
+		var filter domain.TokenFilter
+		if err := json.Unmarshal([]byte(data), &filter); err != nil {
+			return nil, fmt.Errorf("invalid token filter for user %d: %w", id, err)
+		}
+		filters[domain.UserID(id)] = filter
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenMetadataStore keeps resolved token metadata with an expiry so
+// restarts don't re-query every contract
+type TokenMetadataStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewTokenMetadataStore(redisClient *Client, ttl time.Duration) *TokenMetadataStore {
+	return &TokenMetadataStore{
+		client: redisClient.GetRedisClient(),
+		ttl:    ttl,
+	}
+}
+
+func (s *TokenMetadataStore) LoadTokenMetadata(
+	ctx context.Context,
+	tokenAddress string,
+) (*domain.TokenMetadata, error) {
+	data, err := s.client.Get(ctx, tokenMetadataKey(tokenAddress)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata domain.TokenMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+func (s *TokenMetadataStore) SaveTokenMetadata(
+	ctx context.Context,
+	metadata *domain.TokenMetadata,
+) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token metadata: %w", err)
+	}
+
+	return s.client.Set(ctx, tokenMetadataKey(metadata.Address), data, s.ttl).Err()
+}
+
+func tokenMetadataKey(tokenAddress string) string {
+	return "token_metadata:" + strings.ToLower(tokenAddress)
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const tokenSubscriptionsKey = "token_subscriptions"
+
+// TokenSubscriptionRepository stores token subscriptions in a single hash
+// ("token:user id" -> subscription JSON)
+type TokenSubscriptionRepository struct {
+	client *redis.Client
+}
+
+func NewTokenSubscriptionRepository(redisClient *Client) *TokenSubscriptionRepository {
+	return &TokenSubscriptionRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *TokenSubscriptionRepository) AddTokenSubscription(
+	ctx context.Context,
+	subscription domain.TokenSubscription,
+) error {
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+
+	field := tokenSubscriptionField(subscription.TokenAddress, subscription.UserID)
+	return r.client.HSet(ctx, tokenSubscriptionsKey, field, data).Err()
+}
+
+func (r *TokenSubscriptionRepository) RemoveTokenSubscription(
+	ctx context.Context,
+	tokenAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	field := tokenSubscriptionField(tokenAddress, userID)
+	return r.client.HDel(ctx, tokenSubscriptionsKey, field).Err()
+}
+
+func (r *TokenSubscriptionRepository) GetTokenSubscriptions(
+	ctx context.Context,
+) ([]domain.TokenSubscription, error) {
+	entries, err := r.client.HGetAll(ctx, tokenSubscriptionsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]domain.TokenSubscription, 0, len(entries))
+	for field, data := range entries {
+		var subscription domain.TokenSubscription
+		if err := json.Unmarshal([]byte(data), &subscription); err != nil {
+			return nil, fmt.Errorf("invalid token subscription %q: %w", field, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func tokenSubscriptionField(tokenAddress domain.WalletAddress, userID domain.UserID) string {
+	return string(tokenAddress) + ":" + strconv.FormatInt(int64(userID), 10)
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const walletGroupsKey = "wallet_groups"
+
+// WalletGroupRepository stores wallet groups in a single hash
+// ("user id:lowercase name" -> group JSON)
+type WalletGroupRepository struct {
+	client *redis.Client
+}
+
+func NewWalletGroupRepository(redisClient *Client) *WalletGroupRepository {
+	return &WalletGroupRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *WalletGroupRepository) SaveWalletGroup(ctx context.Context, group domain.WalletGroup) error {
+	data, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	return r.client.HSet(ctx, walletGroupsKey, walletGroupField(group.UserID, group.Name), data).Err()
+}
+
+func (r *WalletGroupRepository) DeleteWalletGroup(
+	ctx context.Context,
+	userID domain.UserID,
+	name string,
+) error {
+	return r.client.HDel(ctx, walletGroupsKey, walletGroupField(userID, name)).Err()
+}
+
+func (r *WalletGroupRepository) GetAllWalletGroups(ctx context.Context) ([]domain.WalletGroup, error) {
+	entries, err := r.client.HGetAll(ctx, walletGroupsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]domain.WalletGroup, 0, len(entries))
+	for field, data := range entries {
+		var group domain.WalletGroup
+		if err := json.Unmarshal([]byte(data), &group); err != nil {
+			return nil, fmt.Errorf("invalid wallet group %q: %w", field, err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+func walletGroupField(userID domain.UserID, name string) string {
+	return strconv.FormatInt(int64(userID), 10) + ":" + strings.ToLower(name)
+}
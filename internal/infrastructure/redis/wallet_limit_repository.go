@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const walletLimitsKey = "wallet_limits"
+
+// WalletLimitRepository stores wallet limit overrides in a single hash
+// keyed by user id
+type WalletLimitRepository struct {
+	client *redis.Client
+}
+
+func NewWalletLimitRepository(redisClient *Client) *WalletLimitRepository {
+	return &WalletLimitRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *WalletLimitRepository) SetWalletLimit(
+	ctx context.Context,
+	userID domain.UserID,
+	limit int,
+) error {
+	return r.client.HSet(ctx, walletLimitsKey, strconv.FormatInt(int64(userID), 10), limit).Err()
+}
+
+func (r *WalletLimitRepository) DeleteWalletLimit(ctx context.Context, userID domain.UserID) error {
+	return r.client.HDel(ctx, walletLimitsKey, strconv.FormatInt(int64(userID), 10)).Err()
+}
+
+func (r *WalletLimitRepository) GetAllWalletLimits(ctx context.Context) (map[domain.UserID]int, error) {
+	entries, err := r.client.HGetAll(ctx, walletLimitsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[domain.UserID]int, len(entries))
+	for field, value := range entries {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user id %q in wallet limits: %w", field, err)
+		}
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wallet limit %q of user %d: %w", value, id, err)
+		}
+		limits[domain.UserID(id)] = limit
+	}
+
+	return limits, nil
+}
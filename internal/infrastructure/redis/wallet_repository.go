@@ -0,0 +1,194 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	trackedWalletsKey         = "tracked_wallets"
+	walletSubscriptionsPrefix = "wallet_subscriptions:"
+)
+
+// WalletRepository stores subscriptions as one hash per wallet
+// (user id -> subscription JSON) plus a set of all tracked wallets
+type WalletRepository struct {
+	client *redis.Client
+}
+
+func NewWalletRepository(redisClient *Client) *WalletRepository {
+	return &WalletRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *WalletRepository) AddSubscription(
+	ctx context.Context,
+	subscription domain.WalletSubscription,
+) error {
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx,
+			walletSubscriptionsKey(subscription.WalletAddress),
+			strconv.FormatInt(int64(subscription.UserID), 10),
+			data,
+		)
+		pipe.SAdd(ctx, trackedWalletsKey, string(subscription.WalletAddress))
+		return nil
+	})
+	return err
+}
+
+func (r *WalletRepository) RemoveSubscription(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	key := walletSubscriptionsKey(walletAddress)
+
+	var remaining *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, key, strconv.FormatInt(int64(userID), 10))
+		remaining = pipe.HLen(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Drop the wallet from the index once nobody is subscribed
+	if remaining.Val() == 0 {
+		return r.client.SRem(ctx, trackedWalletsKey, string(walletAddress)).Err()
+	}
+
+	return nil
+}
+
+func (r *WalletRepository) AddSubscriptions(
+	ctx context.Context,
+	subscriptions []domain.WalletSubscription,
+) error {
+	entries := make([][]byte, len(subscriptions))
+	for i, subscription := range subscriptions {
+		data, err := json.Marshal(subscription)
+		if err != nil {
+			return err
+		}
+		entries[i] = data
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, subscription := range subscriptions {
+			pipe.HSet(ctx,
+				walletSubscriptionsKey(subscription.WalletAddress),
+				strconv.FormatInt(int64(subscription.UserID), 10),
+				entries[i],
+			)
+			pipe.SAdd(ctx, trackedWalletsKey, string(subscription.WalletAddress))
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *WalletRepository) RemoveSubscriptions(
+	ctx context.Context,
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	remaining := make([]*redis.IntCmd, len(walletAddresses))
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, walletAddress := range walletAddresses {
+			key := walletSubscriptionsKey(walletAddress)
+			pipe.HDel(ctx, key, strconv.FormatInt(int64(userID), 10))
+			remaining[i] = pipe.HLen(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Drop wallets from the index once nobody is subscribed
+	var unused []any
+	for i, walletAddress := range walletAddresses {
+		if remaining[i].Val() == 0 {
+			unused = append(unused, string(walletAddress))
+		}
+	}
+	if len(unused) > 0 {
+		return r.client.SRem(ctx, trackedWalletsKey, unused...).Err()
+	}
+
+	return nil
+}
+
+func (r *WalletRepository) GetSubscribers(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) ([]domain.UserID, error) {
+	fields, err := r.client.HKeys(ctx, walletSubscriptionsKey(walletAddress)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]domain.UserID, 0, len(fields))
+	for _, field := range fields {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subscriber id %q for %s: %w", field, walletAddress, err)
+		}
+		userIDs = append(userIDs, domain.UserID(id))
+	}
+
+	return userIDs, nil
+}
+
+func (r *WalletRepository) GetSubscriptions(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) ([]domain.WalletSubscription, error) {
+	entries, err := r.client.HGetAll(ctx, walletSubscriptionsKey(walletAddress)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]domain.WalletSubscription, 0, len(entries))
+	for field, data := range entries {
+		var subscription domain.WalletSubscription
+		if err := json.Unmarshal([]byte(data), &subscription); err != nil {
+			return nil, fmt.Errorf("invalid subscription of %q to %s: %w", field, walletAddress, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *WalletRepository) GetAllWallets(ctx context.Context) ([]domain.WalletAddress, error) {
+	members, err := r.client.SMembers(ctx, trackedWalletsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make([]domain.WalletAddress, 0, len(members))
+	for _, member := range members {
+		wallets = append(wallets, domain.WalletAddress(member))
+	}
+
+	return wallets, nil
+}
+
+func walletSubscriptionsKey(walletAddress domain.WalletAddress) string {
+	return walletSubscriptionsPrefix + string(walletAddress)
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	webhooksKey          = "webhooks"
+	webhookDeadLetterKey = "webhook_dead_letters:"
+
+	// Dead letters kept per user, older ones are dropped
+	maxDeadLettersPerUser = 100
+)
+
+// WebhookRepository stores webhooks as JSON in a single hash keyed by
+// webhook id, and each user's dead letters in a capped list
+type WebhookRepository struct {
+	client *redis.Client
+}
+
+func NewWebhookRepository(redisClient *Client) *WebhookRepository {
+	return &WebhookRepository{
+		client: redisClient.GetRedisClient(),
+	}
+}
+
+func (r *WebhookRepository) SaveWebhook(ctx context.Context, webhook domain.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+	return r.client.HSet(ctx, webhooksKey, webhook.ID, data).Err()
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id string) error {
+	return r.client.HDel(ctx, webhooksKey, id).Err()
+}
+
+func (r *WebhookRepository) GetAllWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	entries, err := r.client.HGetAll(ctx, webhooksKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]domain.Webhook, 0, len(entries))
+	for id, data := range entries {
+		var webhook domain.Webhook
+		if err := json.Unmarshal([]byte(data), &webhook); err != nil {
+			return nil, fmt.Errorf("invalid webhook %s: %w", id, err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) AddDeadLetter(ctx context.Context, deadLetter domain.WebhookDeadLetter) error {
+	data, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+
+	key := deadLetterKey(deadLetter.UserID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, maxDeadLettersPerUser-1)
+		return nil
+	})
+	return err
+}
+
+func (r *WebhookRepository) GetDeadLetters(
+	ctx context.Context,
+	userID domain.UserID,
+	limit int,
+) ([]domain.WebhookDeadLetter, error) {
+	entries, err := r.client.LRange(ctx, deadLetterKey(userID), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]domain.WebhookDeadLetter, 0, len(entries))
+	for _, data := range entries {
+		var deadLetter domain.WebhookDeadLetter
+		if err := json.Unmarshal([]byte(data), &deadLetter); err != nil {
+			continue
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+func deadLetterKey(userID domain.UserID) string {
+	return webhookDeadLetterKey + strconv.FormatInt(int64(userID), 10)
+}
@@ -0,0 +1,100 @@
+// Package routing names the channels messages are published on, shared by
+// the Redis and NATS transports
+package routing
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// Channels are the Redis channels and streams, or NATS subjects under the
+// subject prefix, messages are published on and commands read from
+type Channels struct {
+	Commands       string
+	Notifications  string
+	Responses      string
+	ContractEvents string
+	TokenTransfers string
+	Alerts         string
+	Firehose       string
+
+	// NotificationRoute, if set, is the template of the channel each
+	// notification is published on instead of Notifications, with
+	// placeholders {user_id} (one copy per subscriber), {wallet} and
+	// {shard} (the wallet's hash modulo NotificationShards), e.g.
+	// "wallet_notifications:{user_id}"
+	NotificationRoute  string
+	NotificationShards int
+}
+
+// Route is a notification and the channel it is published on
+type Route struct {
+	Channel      string
+	Notification domain.WalletNotification
+}
+
+// Notification returns the channels notification is published on. Routed
+// by user, each subscriber gets a copy carrying only their labels and
+// groups.
+func (c Channels) Notification(notification domain.WalletNotification) []Route {
+	if c.NotificationRoute == "" {
+		return []Route{{Channel: c.Notifications, Notification: notification}}
+	}
+
+	wallet := strings.ToLower(string(notification.WalletAddress))
+	channel := strings.NewReplacer(
+		"{wallet}", wallet,
+		"{shard}", strconv.Itoa(c.shard(wallet)),
+	).Replace(c.NotificationRoute)
+	if !strings.Contains(channel, "{user_id}") {
+		return []Route{{Channel: channel, Notification: notification}}
+	}
+
+	routes := make([]Route, 0, len(notification.Subscribers))
+	for _, userID := range notification.Subscribers {
+		routes = append(routes, Route{
+			Channel:      strings.ReplaceAll(channel, "{user_id}", strconv.FormatInt(int64(userID), 10)),
+			Notification: forUser(notification, userID),
+		})
+	}
+	return routes
+}
+
+// SplitNotification returns the copies of notification published on
+// separate channels, each to be retried on its own if publishing fails
+func (c Channels) SplitNotification(notification domain.WalletNotification) []domain.WalletNotification {
+	routes := c.Notification(notification)
+	copies := make([]domain.WalletNotification, len(routes))
+	for i, route := range routes {
+		copies[i] = route.Notification
+	}
+	return copies
+}
+
+func (c Channels) shard(wallet string) int {
+	if c.NotificationShards <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(wallet))
+	return int(h.Sum32() % uint32(c.NotificationShards))
+}
+
+// forUser returns the copy of notification for one of its subscribers
+func forUser(notification domain.WalletNotification, userID domain.UserID) domain.WalletNotification {
+	notification.Subscribers = []domain.UserID{userID}
+	if label, ok := notification.WalletLabels[userID]; ok {
+		notification.WalletLabels = map[domain.UserID]string{userID: label}
+	} else {
+		notification.WalletLabels = nil
+	}
+	if groups, ok := notification.WalletGroups[userID]; ok {
+		notification.WalletGroups = map[domain.UserID][]string{userID: groups}
+	} else {
+		notification.WalletGroups = nil
+	}
+	return notification
+}
@@ -0,0 +1,200 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/config"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// Bot API limits: about 30 messages per second overall and one per second
+// per chat, with short bursts tolerated
+const (
+	globalRate = 30
+	chatBurst  = 3
+
+	// Attempts per message when Telegram answers 429 Too Many Requests
+	maxSendAttempts = 3
+	// Longest retry_after honored; beyond that the message is dropped
+	maxRetryAfter = time.Minute
+)
+
+// Bot sends notifications as MarkdownV2 messages through the Telegram Bot
+// API, keeping to its rate limits
+type Bot struct {
+	client      *http.Client
+	endpoint    string // sendMessage URL including the token
+	explorerURL string
+	templates   *template.Template
+	logger      *zap.Logger
+
+	global *rate.Limiter
+	chats  map[domain.UserID]*rate.Limiter
+	mu     sync.Mutex
+}
+
+// apiResponse is the envelope of Bot API responses
+type apiResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+func NewBot(cfg config.TelegramConfig, logger *zap.Logger) (*Bot, error) {
+	templates, err := parseTemplates(cfg.TemplatesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bot{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		endpoint:    strings.TrimRight(cfg.APIURL, "/") + "/bot" + cfg.BotToken + "/sendMessage",
+		explorerURL: strings.TrimRight(cfg.ExplorerURL, "/"),
+		templates:   templates,
+		logger:      logger,
+		global:      rate.NewLimiter(globalRate, globalRate),
+		chats:       make(map[domain.UserID]*rate.Limiter),
+	}, nil
+}
+
+// SendNotification sends notification to the chat of userID. The user's
+// private chat with the bot has the user's id.
+func (b *Bot) SendNotification(ctx context.Context, userID domain.UserID, notification domain.WalletNotification) error {
+	name := string(notification.Type)
+	if b.templates.Lookup(name) == nil {
+		name = "default"
+	}
+
+	text, err := b.render(name, notificationView{
+		WalletNotification: notification,
+		Label:              notification.WalletLabels[userID],
+		ExplorerURL:        b.explorerURL,
+	})
+	if err != nil {
+		return err
+	}
+	return b.send(ctx, userID, text)
+}
+
+// SendAlert sends alert to the chat of the rule's owner
+func (b *Bot) SendAlert(ctx context.Context, userID domain.UserID, alert domain.AlertNotification) error {
+	text, err := b.render("alert", alertView{
+		AlertNotification: alert,
+		ExplorerURL:       b.explorerURL,
+	})
+	if err != nil {
+		return err
+	}
+	return b.send(ctx, userID, text)
+}
+
+func (b *Bot) render(name string, data any) (string, error) {
+	var text bytes.Buffer
+	if err := b.templates.ExecuteTemplate(&text, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s message: %w", name, err)
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// send posts text to chatID, waiting out rate limits
+func (b *Bot) send(ctx context.Context, chatID domain.UserID, text string) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":              int64(chatID),
+		"text":                 text,
+		"parse_mode":           "MarkdownV2",
+		"link_preview_options": map[string]bool{"is_disabled": true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := b.global.Wait(ctx); err != nil {
+			return err
+		}
+		if err := b.chatLimiter(chatID).Wait(ctx); err != nil {
+			return err
+		}
+
+		response, err := b.post(ctx, body)
+		if err != nil {
+			return err
+		}
+		if response.OK {
+			return nil
+		}
+
+		retryAfter := time.Duration(response.Parameters.RetryAfter) * time.Second
+		if response.ErrorCode != http.StatusTooManyRequests || attempt >= maxSendAttempts || retryAfter > maxRetryAfter {
+			return fmt.Errorf("telegram rejected message: %d %s", response.ErrorCode, response.Description)
+		}
+
+		b.logger.Warn("Telegram rate limit hit, backing off",
+			zap.Int64("chat_id", int64(chatID)),
+			zap.Duration("retry_after", retryAfter),
+		)
+		select {
+		case <-time.After(retryAfter):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *Bot) post(ctx context.Context, body []byte) (*apiResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		// The error includes the URL, and with it the bot token
+		return nil, fmt.Errorf("failed to call telegram: %w", redactToken(err))
+	}
+	defer resp.Body.Close()
+
+	var response apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode telegram response (status %d): %w", resp.StatusCode, err)
+	}
+	return &response, nil
+}
+
+func (b *Bot) chatLimiter(chatID domain.UserID) *rate.Limiter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	limiter, ok := b.chats[chatID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Second), chatBurst)
+		b.chats[chatID] = limiter
+	}
+	return limiter
+}
+
+// redactToken strips the request URL from transport errors
+func redactToken(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package telegram
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+//go:embed templates.tmpl
+var defaultTemplates string
+
+// Characters MarkdownV2 requires escaped in text, in code spans and in
+// link targets
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+	codeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	urlEscaper  = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+// notificationView is what notification templates are executed with
+type notificationView struct {
+	domain.WalletNotification
+	Label       string // The user's name for the wallet, if any
+	ExplorerURL string
+}
+
+// alertView is what the alert template is executed with
+type alertView struct {
+	domain.AlertNotification
+	Label       string
+	ExplorerURL string
+}
+
+// parseTemplates parses the default templates, then the file at path if
+// set, whose definitions replace the defaults of the same name
+func parseTemplates(path string) (*template.Template, error) {
+	templates, err := template.New("telegram").Funcs(template.FuncMap{
+		"md":       markdownEscaper.Replace,
+		"code":     codeEscaper.Replace,
+		"url":      urlEscaper.Replace,
+		"short":    shortAddress,
+		"party":    party,
+		"outgoing": outgoing,
+		"amount":   amount,
+		"time":     func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+	}).Parse(defaultTemplates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default templates: %w", err)
+	}
+
+	if path != "" {
+		if templates, err = templates.ParseFiles(path); err != nil {
+			return nil, fmt.Errorf("failed to parse templates file: %w", err)
+		}
+	}
+	return templates, nil
+}
+
+// shortAddress abbreviates an address to its first and last characters
+func shortAddress(address domain.WalletAddress) string {
+	if len(address) <= 12 {
+		return string(address)
+	}
+	return string(address[:6]) + "…" + string(address[len(address)-4:])
+}
+
+// party renders the other side of a transfer in MarkdownV2: its known name,
+// or its abbreviated address
+func party(address domain.WalletAddress, label string) string {
+	if label != "" {
+		return markdownEscaper.Replace(label)
+	}
+	return "`" + codeEscaper.Replace(shortAddress(address)) + "`"
+}
+
+func outgoing(wallet, from domain.WalletAddress) bool {
+	return strings.EqualFold(string(wallet), string(from))
+}
+
+// amount prefers the amount scaled by token decimals over the raw one
+func amount(formatted string, raw *big.Int) string {
+	if formatted != "" {
+		return formatted
+	}
+	if raw == nil {
+		return "0"
+	}
+	return raw.String()
+}
@@ -0,0 +1,328 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "plasma_wallet_tracker"
+
+var (
+	// PipelineStageDuration measures how long a stage spends on one item
+	PipelineStageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "pipeline",
+		Name:      "stage_duration_seconds",
+		Help:      "Time spent processing a single item per block pipeline stage.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+	}, []string{"stage"})
+
+	// PipelineQueueDepth is the number of items waiting in front of a stage
+	PipelineQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "pipeline",
+		Name:      "queue_depth",
+		Help:      "Number of items queued in front of each block pipeline stage.",
+	}, []string{"stage"})
+
+	// PipelineItemsTotal counts items leaving a stage by outcome
+	PipelineItemsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pipeline",
+		Name:      "items_total",
+		Help:      "Items processed per block pipeline stage by result.",
+	}, []string{"stage", "result"})
+
+	// BlocksProcessedTotal counts blocks the pipeline finished with,
+	// including skipped and given up ones
+	BlocksProcessedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "blocks",
+		Name:      "processed_total",
+		Help:      "Blocks the pipeline finished processing.",
+	})
+
+	// HeadBlock is the number of the latest block received from the node
+	HeadBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "blocks",
+		Name:      "head",
+		Help:      "Latest block number received from the node.",
+	})
+
+	// ProcessedBlock is the checkpoint: every block up to it was processed
+	ProcessedBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "blocks",
+		Name:      "processed",
+		Help:      "Last block below which every block was processed.",
+	})
+
+	// BlockLag is how many blocks the checkpoint trails the chain head
+	BlockLag = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "blocks",
+		Name:      "lag",
+		Help:      "Blocks between the chain head and the last block below which every block was processed.",
+	})
+
+	// ReceiptsFetchedTotal counts receipts fetched from the node
+	ReceiptsFetchedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "receipts_fetched_total",
+		Help:      "Transaction receipts fetched from the node by method (block_receipts, receipt).",
+	}, []string{"method"})
+
+	// TrackedWallets is the number of wallets with an active listener
+	TrackedWallets = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tracked_wallets",
+		Help:      "Number of wallets with an active listener.",
+	})
+
+	// ListenersDown is the number of wallet listeners waiting for a restart
+	ListenersDown = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "listeners",
+		Name:      "down",
+		Help:      "Wallet listeners that died and are waiting to be restarted.",
+	})
+
+	// ListenerRestartsTotal counts wallet listeners restarted by the supervisor
+	ListenerRestartsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "listeners",
+		Name:      "restarts_total",
+		Help:      "Wallet listeners restarted after dying.",
+	})
+
+	// Leader is 1 while this instance is the elected leader
+	Leader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "leader",
+		Help:      "1 while this instance is the elected leader, 0 on standby.",
+	})
+
+	// ShardMembers is the number of live instances sharing the wallets
+	ShardMembers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "shard",
+		Name:      "members",
+		Help:      "Live tracker instances the wallets are sharded between.",
+	})
+
+	// ActiveListeners is the number of contract and token listeners
+	ActiveListeners = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "listeners",
+		Name:      "active",
+		Help:      "Active contract event and token transfer listeners by kind.",
+	}, []string{"kind"})
+
+	// NotificationsDroppedTotal counts notifications not sent to a
+	// subscriber by reason
+	NotificationsDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "dropped_total",
+		Help:      "Notifications withheld from subscribers by reason (duplicate, rate_limited, consumer_full, slow_stream).",
+	}, []string{"reason"})
+
+	// MessagesPublishedTotal counts messages handed to a transport by kind
+	MessagesPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publish",
+		Name:      "messages_total",
+		Help:      "Messages published to Redis or NATS by kind.",
+	}, []string{"kind"})
+
+	// PublishFailuresTotal counts messages a transport failed to accept by kind
+	PublishFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publish",
+		Name:      "failures_total",
+		Help:      "Messages that could not be published to Redis or NATS by kind.",
+	}, []string{"kind"})
+
+	// SinkPublishesTotal counts publishes to each sink by kind and result
+	SinkPublishesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publish",
+		Name:      "sink_publishes_total",
+		Help:      "Messages handed to a publishing sink by sink, kind and result (ok, retried, failed).",
+	}, []string{"sink", "kind", "result"})
+
+	// NotificationLatency measures the time from a transaction's block
+	// timestamp to its notification being published
+	NotificationLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "latency_seconds",
+		Help:      "Time between the block timestamp and publishing the notification, by type.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+	}, []string{"type"})
+
+	// CommandDuration measures how long commands take to handle
+	CommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "commands",
+		Name:      "duration_seconds",
+		Help:      "Time spent handling a command by type and result (ok or an error code).",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 9),
+	}, []string{"type", "result"})
+
+	// GuardActionsTotal counts wallets rejected or evicted by the listener guard
+	GuardActionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "guard",
+		Name:      "actions_total",
+		Help:      "Wallet subscriptions rejected or evicted because of tracking limits.",
+	}, []string{"action"})
+
+	// RPCThrottleSeconds tracks how long RPC requests wait for rate limit and
+	// concurrency slots
+	RPCThrottleSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "throttle_seconds",
+		Help:      "Time RPC requests spent waiting on client-side rate and concurrency limits.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+
+	// RPCCallsTotal counts RPC calls made through the retry layer by outcome
+	RPCCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "calls_total",
+		Help:      "RPC calls by operation and result (ok, error, retried, failed, rejected).",
+	}, []string{"op", "result"})
+
+	// StaleHeadReconnectsTotal counts head subscriptions replaced by the watchdog
+	StaleHeadReconnectsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "heads",
+		Name:      "stale_reconnects_total",
+		Help:      "Head subscriptions that stopped delivering blocks and were reconnected.",
+	})
+
+	// CacheRequestsTotal counts block, receipt and price cache lookups
+	CacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Block, receipt and price cache lookups by cache and result (hit, miss).",
+	}, []string{"cache", "result"})
+
+	// PublishQueueDepth is the number of transactions waiting for a publishing worker
+	PublishQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "publish",
+		Name:      "queue_depth",
+		Help:      "Transactions in the in-memory publish queue.",
+	})
+
+	// OutboxDepth is the number of notifications waiting in the outbox
+	OutboxDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "depth",
+		Help:      "Notifications in the outbox waiting to be published.",
+	})
+
+	// OutboxPublishFailuresTotal counts failed attempts to publish from the outbox
+	OutboxPublishFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "publish_failures_total",
+		Help:      "Failed attempts to publish the oldest outbox notification.",
+	})
+
+	// OutboxDelay observes how long notifications waited in the outbox
+	OutboxDelay = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "delay_seconds",
+		Help:      "Time between adding a notification to the outbox and publishing it.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 12),
+	})
+
+	// PubSubSubscribed is 1 while the Redis subscription to a channel is up
+	PubSubSubscribed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "pubsub",
+		Name:      "subscribed",
+		Help:      "Whether the Redis subscription to each channel is up.",
+	}, []string{"channel"})
+
+	// PubSubResubscribesTotal counts Redis subscriptions renewed after failing
+	PubSubResubscribesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pubsub",
+		Name:      "resubscribes_total",
+		Help:      "Attempts to subscribe again to a Redis channel after the subscription failed or stalled.",
+	}, []string{"channel"})
+
+	// DeadLettersTotal counts messages moved to the dead letter queue
+	DeadLettersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_letters_total",
+		Help:      "Messages moved to the dead letter queue, by kind.",
+	}, []string{"kind"})
+
+	// PublishOverflowTotal counts transactions spilled to the persistent overflow queue
+	PublishOverflowTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "publish",
+		Name:      "overflow_total",
+		Help:      "Transactions moved to the persistent overflow queue because the in-memory queue was full.",
+	})
+
+	// StreamClients is the number of clients streaming notifications over HTTP
+	StreamClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "stream",
+		Name:      "clients",
+		Help:      "Clients connected to the live notification stream.",
+	})
+
+	// WebhookDeliveriesTotal counts webhook delivery attempts by result
+	WebhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhooks",
+		Name:      "deliveries_total",
+		Help:      "Webhook deliveries by result (ok, retried, failed, queue_full).",
+	}, []string{"result"})
+
+	// AnalyticsTransfersTotal counts transfers handed to the analytics store by result
+	AnalyticsTransfersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "analytics",
+		Name:      "transfers_total",
+		Help:      "Transfers written to the analytics store by result (ok, failed, queue_full).",
+	}, []string{"result"})
+
+	// AnalyticsQueueDepth is the number of transfers waiting to be written to the analytics store
+	AnalyticsQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "analytics",
+		Name:      "queue_depth",
+		Help:      "Transfers waiting to be batched into the analytics store.",
+	})
+
+	// ChatMessagesTotal counts messages sent straight to users' chats by result
+	ChatMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "chat",
+		Name:      "messages_total",
+		Help:      "Notifications and alerts sent to users' chats by result (ok, failed, queue_full).",
+	}, []string{"result"})
+
+	// RPCCircuitState is the RPC circuit breaker state
+	RPCCircuitState = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "circuit_state",
+		Help:      "RPC circuit breaker state: 0 closed, 1 half-open, 2 open.",
+	})
+)
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NormalizeAddress validates a hex address and returns its EIP-55 checksum
+// form, the canonical key for wallets. Mixed-case input must carry a valid
+// checksum; all-lowercase or all-uppercase input is taken as unchecksummed.
+func NormalizeAddress(address domain.WalletAddress) (domain.WalletAddress, error) {
+	raw := strings.TrimSpace(string(address))
+	if !common.IsHexAddress(raw) {
+		return "", fmt.Errorf("%w: %q is not a 20-byte hex address", domain.ErrInvalidAddress, raw)
+	}
+
+	digits := strings.TrimPrefix(strings.TrimPrefix(raw, "0x"), "0X")
+	checksummed := common.HexToAddress(digits).Hex()
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && "0x"+digits != checksummed {
+		return "", fmt.Errorf("%w: %q has an invalid EIP-55 checksum", domain.ErrInvalidAddress, raw)
+	}
+
+	return domain.WalletAddress(checksummed), nil
+}
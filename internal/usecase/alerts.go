@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Maximum number of alert rules a single user can have
+const maxAlertRulesPerUser = 50
+
+// AlertEngine matches transactions of watched wallets against users' alert
+// rules and publishes matches to the high-priority alerts channel. Besides
+// single large transfers, rules can react to a wallet's behavior over time.
+type AlertEngine struct {
+	blockchainClient domain.BlockchainClient
+	repo             domain.AlertRuleRepository
+	publisher        domain.Publisher
+	logger           *zap.Logger
+
+	// Rules map: rule id -> rule
+	rules map[string]domain.AlertRule
+	mu    sync.RWMutex
+
+	// History behavioral rules are evaluated against
+	behavior *behaviorTracker
+}
+
+func NewAlertEngine(
+	blockchainClient domain.BlockchainClient,
+	repo domain.AlertRuleRepository,
+	publisher domain.Publisher,
+	logger *zap.Logger,
+) *AlertEngine {
+	return &AlertEngine{
+		blockchainClient: blockchainClient,
+		repo:             repo,
+		publisher:        publisher,
+		logger:           logger,
+		rules:            make(map[string]domain.AlertRule),
+		behavior:         newBehaviorTracker(),
+	}
+}
+
+// Load reads all persisted rules into memory
+func (ae *AlertEngine) Load(ctx context.Context) error {
+	rules, err := ae.repo.GetAllAlertRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	ae.mu.Lock()
+	defer ae.mu.Unlock()
+
+	for _, rule := range rules {
+		ae.rules[rule.ID] = rule
+	}
+
+	ae.logger.Info("Loaded alert rules", zap.Int("count", len(rules)))
+	return nil
+}
+
+// Create validates and stores a new rule for userID
+func (ae *AlertEngine) Create(
+	ctx context.Context,
+	userID domain.UserID,
+	rule domain.AlertRule,
+) (*domain.AlertRule, error) {
+	if err := normalizeAlertRule(&rule); err != nil {
+		return nil, err
+	}
+	rule.ID = randomHex(8)
+	rule.UserID = userID
+	rule.CreatedAt = time.Now()
+
+	ae.mu.Lock()
+	defer ae.mu.Unlock()
+
+	count := 0
+	for _, existing := range ae.rules {
+		if existing.UserID == userID {
+			count++
+		}
+	}
+	if count >= maxAlertRulesPerUser {
+		return nil, fmt.Errorf("%w: at most %d alert rules per user", domain.ErrQuotaExceeded, maxAlertRulesPerUser)
+	}
+
+	if err := ae.repo.SaveAlertRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to store alert rule: %w", err)
+	}
+	ae.rules[rule.ID] = rule
+
+	ae.logger.Info("Created alert rule",
+		zap.String("rule_id", rule.ID),
+		zap.Int64("user_id", int64(userID)),
+		zap.String("wallet", string(rule.WalletAddress)),
+	)
+	return &rule, nil
+}
+
+// List returns the rules of userID, oldest first
+func (ae *AlertEngine) List(userID domain.UserID) []domain.AlertRule {
+	ae.mu.RLock()
+	defer ae.mu.RUnlock()
+
+	rules := make([]domain.AlertRule, 0)
+	for _, rule := range ae.rules {
+		if rule.UserID == userID {
+			rules = append(rules, rule)
+		}
+	}
+
+	slices.SortFunc(rules, func(a, b domain.AlertRule) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return rules
+}
+
+// Delete removes a rule owned by userID
+func (ae *AlertEngine) Delete(ctx context.Context, userID domain.UserID, id string) error {
+	ae.mu.Lock()
+	defer ae.mu.Unlock()
+
+	rule, ok := ae.rules[id]
+	if !ok || rule.UserID != userID {
+		return fmt.Errorf("%w: %s", domain.ErrAlertRuleNotFound, id)
+	}
+
+	if err := ae.repo.DeleteAlertRule(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	delete(ae.rules, id)
+	ae.behavior.forget(id)
+
+	ae.logger.Info("Deleted alert rule",
+		zap.String("rule_id", id),
+		zap.Int64("user_id", int64(userID)),
+	)
+	return nil
+}
+
+// Evaluate publishes an alert for every rule of the wallet's subscribers
+// that tx meets. It must see every transaction of a wallet for behavioral
+// rules to work, including reverted ones.
+func (ae *AlertEngine) Evaluate(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+	subscribers []domain.UserID,
+) {
+	previous := ae.behavior.observe(walletAddress, tx)
+
+	for _, rule := range ae.rulesFor(walletAddress, subscribers) {
+		var (
+			matched []domain.Transfer
+			reason  string
+		)
+		switch rule.Kind {
+		case domain.VelocityAlert:
+			reason = ae.behavior.velocityReason(rule, walletAddress, tx)
+		case domain.DrainAlert:
+			matched, reason = ae.drainedTransfers(ctx, rule, walletAddress, tx)
+		case domain.DormantAlert:
+			reason = dormantReason(rule, previous, tx)
+		default:
+			matched = matchingTransfers(rule, walletAddress, tx)
+		}
+		if len(matched) == 0 && reason == "" {
+			continue
+		}
+
+		notification := domain.AlertNotification{
+			Rule:          rule,
+			WalletAddress: walletAddress,
+			Transaction:   tx,
+			Transfers:     matched,
+			Reason:        reason,
+			Timestamp:     time.Now(),
+			Trace:         traceContextFor(ctx),
+		}
+		if err := ae.publisher.PublishAlert(ctx, notification); err != nil {
+			ae.logger.Error("Failed to publish alert",
+				zap.String("rule_id", rule.ID),
+				zap.String("tx_hash", string(tx.Hash)),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		ae.logger.Info("Published alert",
+			zap.String("rule_id", rule.ID),
+			zap.Int64("user_id", int64(rule.UserID)),
+			zap.String("wallet", string(walletAddress)),
+			zap.String("tx_hash", string(tx.Hash)),
+		)
+	}
+}
+
+// rulesFor returns the rules of subscribers that apply to walletAddress
+func (ae *AlertEngine) rulesFor(
+	walletAddress domain.WalletAddress,
+	subscribers []domain.UserID,
+) []domain.AlertRule {
+	ae.mu.RLock()
+	defer ae.mu.RUnlock()
+
+	var rules []domain.AlertRule
+	for _, rule := range ae.rules {
+		if rule.WalletAddress != "" && !strings.EqualFold(string(rule.WalletAddress), string(walletAddress)) {
+			continue
+		}
+		if slices.Contains(subscribers, rule.UserID) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// matchingTransfers returns the transfers of tx that meet a transfer rule
+func matchingTransfers(
+	rule domain.AlertRule,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+) []domain.Transfer {
+	if tx.Status == domain.TxReverted {
+		return nil
+	}
+
+	var matched []domain.Transfer
+	for _, transfer := range tx.Transfers {
+		if alertMatches(rule, walletAddress, transfer) {
+			matched = append(matched, transfer)
+		}
+	}
+	return matched
+}
+
+// alertMatches reports whether transfer meets every condition of rule
+func alertMatches(
+	rule domain.AlertRule,
+	walletAddress domain.WalletAddress,
+	transfer domain.Transfer,
+) bool {
+	if rule.TokenAddress != "" && !strings.EqualFold(rule.TokenAddress, transfer.TokenAddress) {
+		return false
+	}
+
+	incoming := strings.EqualFold(string(transfer.To), string(walletAddress))
+	outgoing := strings.EqualFold(string(transfer.From), string(walletAddress))
+	switch rule.Direction {
+	case domain.Incoming:
+		if !incoming {
+			return false
+		}
+	case domain.Outgoing:
+		if !outgoing {
+			return false
+		}
+	default:
+		if !incoming && !outgoing {
+			return false
+		}
+	}
+
+	if rule.MinAmount != "" {
+		minAmount, _ := new(big.Float).SetString(rule.MinAmount)
+		amount, ok := new(big.Float).SetString(transfer.FormattedValue)
+		if !ok || amount.Cmp(minAmount) < 0 {
+			return false
+		}
+	}
+
+	if rule.MinValueUSD > 0 {
+		value, err := strconv.ParseFloat(transfer.ValueUSD, 64)
+		if err != nil || value < rule.MinValueUSD {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeAlertRule validates rule and checksums its addresses
+func normalizeAlertRule(rule *domain.AlertRule) error {
+	if rule.WalletAddress != "" {
+		address, err := NormalizeAddress(rule.WalletAddress)
+		if err != nil {
+			return err
+		}
+		rule.WalletAddress = address
+	}
+	if rule.TokenAddress != "" {
+		address, err := NormalizeAddress(domain.WalletAddress(rule.TokenAddress))
+		if err != nil {
+			return err
+		}
+		rule.TokenAddress = string(address)
+	}
+
+	switch rule.Direction {
+	case domain.AnyDirection, domain.Incoming, domain.Outgoing:
+	default:
+		return fmt.Errorf("%w: unknown direction %q", domain.ErrInvalidAlertRule, rule.Direction)
+	}
+
+	if rule.Kind == "" {
+		rule.Kind = domain.TransferAlert
+	}
+	if rule.Kind != domain.TransferAlert {
+		return normalizeBehaviorRule(rule)
+	}
+
+	if rule.MinAmount == "" && rule.MinValueUSD <= 0 {
+		return fmt.Errorf("%w: min_amount or min_value_usd is required", domain.ErrInvalidAlertRule)
+	}
+	if rule.MinAmount != "" {
+		if rule.TokenAddress == "" {
+			return fmt.Errorf("%w: min_amount requires token_address", domain.ErrInvalidAlertRule)
+		}
+		amount, ok := new(big.Float).SetString(rule.MinAmount)
+		if !ok || amount.Sign() < 0 {
+			return fmt.Errorf("%w: invalid min_amount %q", domain.ErrInvalidAlertRule, rule.MinAmount)
+		}
+	}
+	if rule.MinValueUSD < 0 {
+		return fmt.Errorf("%w: negative min_value_usd", domain.ErrInvalidAlertRule)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// audience is a group of subscribers that see the same version of a
+// transaction after filtering
+type audience struct {
+	tx          domain.Transaction
+	subscribers []domain.UserID
+}
+
+// audiencesFor applies each subscriber's token filters and subscription
+// filters to tx and groups subscribers by the outcome. Subscribers whose
+// filters removed everything worth reporting are left out.
+func (wt *WalletTracker) audiencesFor(
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+	subscribers []domain.UserID,
+) []audience {
+	wt.mu.RLock()
+	filters := make(map[domain.UserID]*domain.NotificationFilter, len(subscribers))
+	for _, userID := range subscribers {
+		filters[userID] = wt.filters[subscriptionKey{walletAddress, userID}]
+	}
+	wt.mu.RUnlock()
+
+	var (
+		audiences []audience
+		index     = make(map[string]int)
+	)
+
+	for _, userID := range subscribers {
+		filtered := wt.tokenFilters.Apply(userID, tx)
+		filtered = applyNotificationFilter(filters[userID], walletAddress, filtered)
+		if nothingLeft(tx, filtered) {
+			continue
+		}
+
+		key := keptKey(tx, filtered)
+		if i, ok := index[key]; ok {
+			audiences[i].subscribers = append(audiences[i].subscribers, userID)
+			continue
+		}
+		index[key] = len(audiences)
+		audiences = append(audiences, audience{tx: filtered, subscribers: []domain.UserID{userID}})
+	}
+
+	return audiences
+}
+
+// walletView returns tx with only the transfers to or from the wallet,
+// which are what notifications, digests and alerts are about. Approvals,
+// swaps and bridge transfers arrive narrowed to the wallet already.
+func walletView(walletAddress domain.WalletAddress, tx domain.Transaction) domain.Transaction {
+	var transfers []domain.Transfer
+	for _, transfer := range tx.Transfers {
+		if strings.EqualFold(string(transfer.From), string(walletAddress)) ||
+			strings.EqualFold(string(transfer.To), string(walletAddress)) {
+			transfers = append(transfers, transfer)
+		}
+	}
+	tx.Transfers = transfers
+	return tx
+}
+
+// keptKey identifies which transfers and approvals of original survived in
+// filtered, which keeps them in order
+func keptKey(original, filtered domain.Transaction) string {
+	var key strings.Builder
+
+	next := 0
+	for _, transfer := range original.Transfers {
+		if next < len(filtered.Transfers) && filtered.Transfers[next] == transfer {
+			key.WriteByte('t')
+			next++
+		} else {
+			key.WriteByte('-')
+		}
+	}
+
+	next = 0
+	for _, approval := range original.Approvals {
+		if next < len(filtered.Approvals) && filtered.Approvals[next] == approval {
+			key.WriteByte('a')
+			next++
+		} else {
+			key.WriteByte('-')
+		}
+	}
+
+	return key.String()
+}
+
+// nothingLeft reports whether filtering removed everything worth notifying
+func nothingLeft(original, filtered domain.Transaction) bool {
+	if len(original.Transfers) == len(filtered.Transfers) && len(original.Approvals) == len(filtered.Approvals) {
+		return false
+	}
+	return len(filtered.Transfers) == 0 &&
+		len(filtered.Approvals) == 0 &&
+		len(filtered.Swaps) == 0 &&
+		len(filtered.Bridges) == 0 &&
+		filtered.Status != domain.TxReverted
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Audit entries returned by one query by default and at most
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditTrail records commands and subscription changes to the audit log.
+// Recording is best effort: a failing log is reported but never fails the
+// change being recorded. A nil log disables the trail.
+type AuditTrail struct {
+	log    domain.AuditLog
+	logger *zap.Logger
+}
+
+func NewAuditTrail(log domain.AuditLog, logger *zap.Logger) *AuditTrail {
+	return &AuditTrail{
+		log:    log,
+		logger: logger,
+	}
+}
+
+// recordCommand records a received command and its outcome
+func (at *AuditTrail) recordCommand(ctx context.Context, cmd domain.Command, err error) {
+	entry := domain.AuditEntry{
+		Action:        domain.AuditCommand,
+		UserID:        cmd.UserID,
+		Address:       commandAddress(cmd),
+		Command:       &cmd,
+		Result:        "ok",
+		CorrelationID: cmd.CorrelationID,
+		Timestamp:     time.Now(),
+	}
+	if err != nil {
+		entry.Result = errorCode(err)
+		entry.Error = err.Error()
+	}
+	at.append(ctx, entry)
+}
+
+// recordSubscription records that a subscription of userID to address was
+// added or removed. Reason explains changes the user didn't ask for.
+func (at *AuditTrail) recordSubscription(
+	ctx context.Context,
+	action domain.AuditAction,
+	kind domain.SubscriptionKind,
+	address domain.WalletAddress,
+	userID domain.UserID,
+	reason string,
+) {
+	at.append(ctx, domain.AuditEntry{
+		Action:       action,
+		UserID:       userID,
+		Address:      address,
+		Subscription: kind,
+		Reason:       reason,
+		Timestamp:    time.Now(),
+	})
+}
+
+func (at *AuditTrail) append(ctx context.Context, entry domain.AuditEntry) {
+	if at == nil || at.log == nil {
+		return
+	}
+
+	if err := at.log.Append(ctx, entry); err != nil {
+		at.logger.Error("Failed to append audit entry",
+			zap.String("action", string(entry.Action)),
+			zap.Int64("user_id", int64(entry.UserID)),
+			zap.Error(err),
+		)
+	}
+}
+
+// Query returns matching audit entries, newest first. Callers check who may
+// see them.
+func (at *AuditTrail) Query(ctx context.Context, query domain.AuditQuery) ([]domain.AuditEntry, error) {
+	if at == nil || at.log == nil {
+		return nil, domain.ErrAuditLogDisabled
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		return nil, fmt.Errorf("%w: until is before since", domain.ErrInvalidDuration)
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = defaultAuditLimit
+	case query.Limit > maxAuditLimit:
+		query.Limit = maxAuditLimit
+	}
+
+	entries, err := at.log.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	if entries == nil {
+		entries = []domain.AuditEntry{}
+	}
+	return entries, nil
+}
+
+// commandAddress is the wallet, contract or token cmd is about, if any
+func commandAddress(cmd domain.Command) domain.WalletAddress {
+	switch {
+	case cmd.WalletAddress != "":
+		return cmd.WalletAddress
+	case cmd.ContractAddress != "":
+		return cmd.ContractAddress
+	default:
+		return domain.WalletAddress(cmd.TokenAddress)
+	}
+}
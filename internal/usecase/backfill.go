@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Backfills allowed to run at once, so adding many wallets doesn't flood
+// the node with history scans
+const maxConcurrentBackfills = 4
+
+// startBackfill sends the recent history of the wallet to a new subscriber
+// in the background, once a backfill slot is free. It runs in the tracker's
+// context, so shutdown cancels and waits for it. Caller must hold wt.mu.
+func (wt *WalletTracker) startBackfill(walletAddress domain.WalletAddress, userID domain.UserID) {
+	if wt.backfillBlocks == 0 {
+		return
+	}
+	if wt.ctx == nil {
+		wt.pendingBackfills = append(wt.pendingBackfills, subscriptionKey{walletAddress, userID})
+		return
+	}
+
+	ctx := wt.ctx
+	wt.listenerWG.Add(1)
+	go func() {
+		defer wt.listenerWG.Done()
+
+		select {
+		case wt.backfillSlots <- struct{}{}:
+			defer func() { <-wt.backfillSlots }()
+		case <-ctx.Done():
+			return
+		}
+		wt.backfill(ctx, walletAddress, userID)
+	}()
+}
+
+// backfill sends the recent history of the wallet to a new subscriber,
+// marked as historical
+func (wt *WalletTracker) backfill(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) {
+	latest, err := wt.blockchainClient.GetLatestBlock(ctx)
+	if err != nil {
+		wt.logger.Error("Failed to get latest block for backfill",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	fromBlock := uint64(0)
+	if latest > wt.backfillBlocks {
+		fromBlock = latest - wt.backfillBlocks + 1
+	}
+
+	history, err := wt.blockchainClient.GetAddressHistory(ctx, walletAddress, fromBlock, latest, 0)
+	if err != nil {
+		wt.logger.Error("Failed to backfill wallet history",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	published, err := wt.publishHistory(ctx, walletAddress, []domain.UserID{userID}, history, latest, historyDelivery{
+		dedup:    true,
+		throttle: true,
+	})
+	if err != nil {
+		wt.logger.Error("Failed to publish historical notification",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	wt.logger.Info("Backfilled wallet history",
+		zap.String("wallet", string(walletAddress)),
+		zap.Int64("user_id", int64(userID)),
+		zap.Uint64("from_block", fromBlock),
+		zap.Uint64("to_block", latest),
+		zap.Int("transactions", len(history)),
+		zap.Int("published", published),
+	)
+}
+
+// historyDelivery is how publishHistory treats past transactions
+type historyDelivery struct {
+	dedup    bool // Skip those already delivered and remember these as delivered
+	throttle bool // Count them against subscribers' rate limits
+}
+
+// publishHistory publishes past transactions of the wallet to subscribers
+// as historical notifications and archives them, stopping at the first
+// failed publish. It returns how many notifications were published.
+func (wt *WalletTracker) publishHistory(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	subscribers []domain.UserID,
+	history []domain.Transaction,
+	latest uint64,
+	delivery historyDelivery,
+) (int, error) {
+	published := 0
+	for _, tx := range history {
+		wt.labels.Annotate(tx.Transfers)
+		wt.prices.Enrich(ctx, tx.Transfers)
+
+		delivered := false
+		for _, audience := range wt.audiencesFor(walletAddress, walletView(walletAddress, tx), subscribers) {
+			view := audience.tx
+			wt.labels.AnnotateApprovals(view.Approvals)
+			key := idempotencyKey(walletAddress, view, domain.TransactionNotification, false)
+
+			recipients := audience.subscribers
+			if delivery.dedup {
+				recipients = wt.dedup.claim(ctx, key, recipients)
+			}
+			if delivery.throttle {
+				recipients = wt.throttle.allow(recipients)
+			}
+			if len(recipients) == 0 {
+				continue
+			}
+
+			notification := domain.WalletNotification{
+				Type:           domain.TransactionNotification,
+				SchemaVersion:  domain.CurrentSchemaVersion,
+				WalletAddress:  walletAddress,
+				Transaction:    wt.publishedTransaction(view, tx.Transfers),
+				Transfers:      view.Transfers,
+				Subscribers:    recipients,
+				WalletLabels:   wt.walletLabelsFor(walletAddress, recipients),
+				WalletGroups:   wt.groups.groupsFor(walletAddress, recipients),
+				Timestamp:      time.Now(),
+				Trace:          traceContextFor(ctx),
+				IdempotencyKey: key,
+				Confirmations:  confirmationsAt(latest, tx.BlockNumber),
+				Historical:     true,
+				Failed:         tx.Status == domain.TxReverted,
+				Gasless:        tx.Gasless,
+			}
+
+			if err := wt.publisher.PublishNotification(ctx, notification); err != nil {
+				if delivery.dedup {
+					wt.dedup.release(ctx, key, recipients)
+				}
+				return published, fmt.Errorf("failed to publish transaction %s: %w", tx.Hash, err)
+			}
+			published++
+			delivered = true
+		}
+		if delivered {
+			wt.archive.record(ctx, walletAddress, tx)
+		}
+	}
+	return published, nil
+}
+
+// LoadSubscriptions reads the persisted subscriptions into memory without
+// starting listeners, for one-off tasks run instead of Start
+func (wt *WalletTracker) LoadSubscriptions(ctx context.Context) error {
+	return wt.restoreSubscriptions(ctx)
+}
+
+// Backfill publishes the transactions of the wallet in [fromBlock, toBlock]
+// to its subscribers as historical notifications, skipping those already
+// delivered, and returns how many were published. A zero toBlock means the
+// latest block.
+func (wt *WalletTracker) Backfill(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	fromBlock uint64,
+	toBlock uint64,
+) (int, error) {
+	walletAddress, err := NormalizeAddress(walletAddress)
+	if err != nil {
+		return 0, err
+	}
+	subscribers := wt.subscribersOf(walletAddress)
+	if len(subscribers) == 0 {
+		return 0, fmt.Errorf("%w: %s has no subscribers", domain.ErrWalletNotFound, walletAddress)
+	}
+
+	return wt.publishRange(ctx, []domain.WalletAddress{walletAddress}, fromBlock, toBlock, true)
+}
+
+// Replay publishes again the transactions of every tracked wallet in
+// [fromBlock, toBlock] to its subscribers as historical notifications,
+// whether they were delivered before or not, and returns how many were
+// published. A zero toBlock means the latest block. Each wallet's history
+// is scanned separately, so keep the range short when many are tracked.
+func (wt *WalletTracker) Replay(ctx context.Context, fromBlock, toBlock uint64) (int, error) {
+	wallets := wt.trackedWallets()
+	slices.Sort(wallets)
+	return wt.publishRange(ctx, wallets, fromBlock, toBlock, false)
+}
+
+func (wt *WalletTracker) publishRange(
+	ctx context.Context,
+	wallets []domain.WalletAddress,
+	fromBlock uint64,
+	toBlock uint64,
+	dedup bool,
+) (int, error) {
+	latest, err := wt.blockchainClient.GetLatestBlock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if toBlock == 0 || toBlock > latest {
+		toBlock = latest
+	}
+	if fromBlock > toBlock {
+		return 0, fmt.Errorf("%w: from_block %d is after to_block %d",
+			domain.ErrInvalidBlockRange, fromBlock, toBlock)
+	}
+
+	published := 0
+	for _, walletAddress := range wallets {
+		history, err := wt.blockchainClient.GetAddressHistory(ctx, walletAddress, fromBlock, toBlock, 0)
+		if err != nil {
+			return published, fmt.Errorf("failed to get history of %s: %w", walletAddress, err)
+		}
+
+		// Rate limits aren't applied, a one-off task can't send their summaries
+		n, err := wt.publishHistory(ctx, walletAddress, wt.subscribersOf(walletAddress), history, latest,
+			historyDelivery{dedup: dedup})
+		published += n
+		if err != nil {
+			return published, err
+		}
+
+		wt.logger.Info("Published wallet history",
+			zap.String("wallet", string(walletAddress)),
+			zap.Uint64("from_block", fromBlock),
+			zap.Uint64("to_block", toBlock),
+			zap.Int("transactions", len(history)),
+			zap.Int("published", n),
+		)
+	}
+	return published, nil
+}
+
+// subscribersOf returns a copy of the wallet's subscribers
+func (wt *WalletTracker) subscribersOf(walletAddress domain.WalletAddress) []domain.UserID {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	return slices.Clone(wt.subscribers[walletAddress])
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Window a portfolio value is compared over unless the caller picks one
+const defaultValueChangeWindow = 24 * time.Hour
+
+// BalanceSnapshots periodically records the native and portfolio token
+// balances of every tracked wallet with their USD value, so users can see
+// what their wallets are worth and how that changed. A nil repository or a
+// zero interval disables it.
+type BalanceSnapshots struct {
+	blockchainClient domain.BlockchainClient
+	walletTracker    *WalletTracker
+	prices           *PriceService
+	repo             domain.BalanceSnapshotRepository
+	tokens           []string
+	interval         time.Duration
+	retention        time.Duration // Snapshots older than this are deleted; 0 keeps them
+	logger           *zap.Logger
+}
+
+func NewBalanceSnapshots(
+	blockchainClient domain.BlockchainClient,
+	walletTracker *WalletTracker,
+	prices *PriceService,
+	repo domain.BalanceSnapshotRepository,
+	tokens []string,
+	interval time.Duration,
+	retention time.Duration,
+	logger *zap.Logger,
+) *BalanceSnapshots {
+	return &BalanceSnapshots{
+		blockchainClient: blockchainClient,
+		walletTracker:    walletTracker,
+		prices:           prices,
+		repo:             repo,
+		tokens:           tokens,
+		interval:         interval,
+		retention:        retention,
+		logger:           logger,
+	}
+}
+
+func (bs *BalanceSnapshots) enabled() bool {
+	return bs.repo != nil && bs.interval > 0
+}
+
+// Start takes a snapshot every interval until ctx is done
+func (bs *BalanceSnapshots) Start(ctx context.Context) {
+	if bs.interval <= 0 {
+		return
+	}
+	if bs.repo == nil {
+		bs.logger.Warn("Balance snapshots need PostgreSQL, not taking them")
+		return
+	}
+
+	ticker := time.NewTicker(bs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			bs.snapshot(ctx, now)
+		}
+	}
+}
+
+// snapshot records the balances of every tracked wallet at now and deletes
+// snapshots past retention. Wallets whose balances can't be read are left
+// out of this round.
+func (bs *BalanceSnapshots) snapshot(ctx context.Context, now time.Time) {
+	wallets := bs.walletTracker.trackedWallets()
+	snapshots := make([]domain.BalanceSnapshot, 0, len(wallets))
+	for _, walletAddress := range wallets {
+		if ctx.Err() != nil {
+			return
+		}
+
+		balances, err := bs.blockchainClient.GetBalances(ctx, walletAddress, bs.tokens)
+		if err != nil {
+			bs.logger.Warn("Failed to get balances for snapshot",
+				zap.String("wallet", string(walletAddress)),
+				zap.Error(err),
+			)
+			continue
+		}
+		bs.prices.EnrichBalances(ctx, balances)
+
+		snapshots = append(snapshots, domain.BalanceSnapshot{
+			WalletAddress: walletAddress,
+			Balances:      balances,
+			ValueUSD:      formatUSD(balancesValue(balances)),
+			TakenAt:       now,
+		})
+	}
+
+	if len(snapshots) > 0 {
+		if err := bs.repo.SaveSnapshots(ctx, snapshots); err != nil {
+			bs.logger.Error("Failed to store balance snapshots", zap.Error(err))
+			return
+		}
+	}
+	if bs.retention > 0 {
+		if err := bs.repo.DeleteSnapshots(ctx, now.Add(-bs.retention)); err != nil {
+			bs.logger.Error("Failed to delete old balance snapshots", zap.Error(err))
+		}
+	}
+
+	bs.logger.Debug("Took balance snapshots",
+		zap.Int("wallets", len(snapshots)),
+		zap.Int("failed", len(wallets)-len(snapshots)),
+	)
+}
+
+// PortfolioValue returns what the user's wallets are worth by their latest
+// snapshots and the change since window ago, 24 hours if zero
+func (bs *BalanceSnapshots) PortfolioValue(
+	ctx context.Context,
+	userID domain.UserID,
+	window time.Duration,
+) (*domain.PortfolioValue, error) {
+	if !bs.enabled() {
+		return nil, fmt.Errorf("%w: balance snapshots are not configured", domain.ErrPortfolioUnvalued)
+	}
+	if window < 0 {
+		return nil, fmt.Errorf("%w: window must be positive", domain.ErrInvalidDuration)
+	}
+	if window == 0 {
+		window = defaultValueChangeWindow
+	}
+
+	now := time.Now()
+	value := &domain.PortfolioValue{
+		UserID:      userID,
+		ComparedTo:  now.Add(-window),
+		Wallets:     []domain.BalanceSnapshot{},
+		GeneratedAt: now,
+	}
+
+	wallets := bs.walletTracker.WalletsForUser(userID)
+	if len(wallets) == 0 {
+		value.ValueUSD = formatUSD(new(big.Float))
+		return value, nil
+	}
+
+	latest, err := bs.repo.SnapshotsAt(ctx, wallets, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance snapshots: %w", err)
+	}
+	previous, err := bs.repo.SnapshotsAt(ctx, wallets, value.ComparedTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance snapshots: %w", err)
+	}
+
+	// Wallets added since are left out of the change, or they would count
+	// as gains
+	total, compared, before := new(big.Float), new(big.Float), new(big.Float)
+	for _, walletAddress := range wallets {
+		snapshot, ok := latest[walletAddress]
+		if !ok {
+			continue
+		}
+		value.Wallets = append(value.Wallets, snapshot)
+
+		usd := parseUSD(snapshot.ValueUSD)
+		total.Add(total, usd)
+		if then, ok := previous[walletAddress]; ok {
+			compared.Add(compared, usd)
+			before.Add(before, parseUSD(then.ValueUSD))
+		}
+	}
+	slices.SortFunc(value.Wallets, func(a, b domain.BalanceSnapshot) int {
+		return strings.Compare(string(a.WalletAddress), string(b.WalletAddress))
+	})
+
+	value.ValueUSD = formatUSD(total)
+	if len(previous) > 0 {
+		change := new(big.Float).Sub(compared, before)
+		value.PreviousValueUSD = formatUSD(before)
+		value.ChangeUSD = formatUSD(change)
+		if before.Sign() > 0 {
+			percent := new(big.Float).Quo(change, before)
+			value.ChangePercent = percent.Mul(percent, big.NewFloat(100)).Text('f', 2)
+		}
+	}
+	return value, nil
+}
+
+// balancesValue sums the USD value of the priced balances
+func balancesValue(balances []domain.TokenBalance) *big.Float {
+	sum := new(big.Float)
+	for _, balance := range balances {
+		sum.Add(sum, parseUSD(balance.ValueUSD))
+	}
+	return sum
+}
+
+// parseUSD reads a USD amount such as "12.50"; empty or malformed is zero
+func parseUSD(s string) *big.Float {
+	value, ok := new(big.Float).SetString(s)
+	if !ok {
+		return new(big.Float)
+	}
+	return value
+}
+
+func formatUSD(value *big.Float) string {
+	return value.Text('f', 2)
+}
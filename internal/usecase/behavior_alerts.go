@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Maximum max_transactions of a velocity rule
+	maxVelocityTransactions = 1000
+	// Longest window of a velocity rule
+	maxVelocityWindow = 7 * 24 * time.Hour
+)
+
+// velocityKey identifies the transaction window of a rule for one wallet,
+// as rules without a wallet apply to each wallet of the user separately
+type velocityKey struct {
+	ruleID        string
+	walletAddress domain.WalletAddress
+}
+
+// velocityWindow holds the times of recent transactions of a wallet. A
+// rule fires once when its limit is exceeded and re-arms once the wallet
+// is back under it.
+type velocityWindow struct {
+	times []time.Time
+	fired bool
+}
+
+// behaviorTracker keeps the per-wallet history behavioral alert rules are
+// evaluated against. It lives in memory, so after a restart velocity
+// windows start empty and dormancy is only known for wallets seen since.
+type behaviorTracker struct {
+	// Last activity map: wallet address -> time of its latest transaction
+	lastActivity map[domain.WalletAddress]time.Time
+	velocity     map[velocityKey]*velocityWindow
+	mu           sync.Mutex
+}
+
+func newBehaviorTracker() *behaviorTracker {
+	return &behaviorTracker{
+		lastActivity: make(map[domain.WalletAddress]time.Time),
+		velocity:     make(map[velocityKey]*velocityWindow),
+	}
+}
+
+// observe records tx as the latest activity of the wallet and returns the
+// previous one, zero if unknown
+func (bt *behaviorTracker) observe(walletAddress domain.WalletAddress, tx domain.Transaction) time.Time {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+
+	previous := bt.lastActivity[walletAddress]
+	if at := activityTime(tx); at.After(previous) {
+		bt.lastActivity[walletAddress] = at
+	}
+	return previous
+}
+
+// velocityReason counts tx in the rule's window for the wallet and returns why
+// the rule fires, or "" if it doesn't
+func (bt *behaviorTracker) velocityReason(
+	rule domain.AlertRule,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+) string {
+	window, _ := time.ParseDuration(rule.Window) // Validated when the rule was created
+	at := activityTime(tx)
+
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+
+	key := velocityKey{rule.ID, walletAddress}
+	w, ok := bt.velocity[key]
+	if !ok {
+		w = &velocityWindow{}
+		bt.velocity[key] = w
+	}
+
+	// Drop transactions that left the window; only limit+1 are ever needed
+	cutoff := at.Add(-window)
+	kept := w.times[:0]
+	for _, t := range w.times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	w.times = append(kept, at)
+	if len(w.times) > rule.MaxTransactions+1 {
+		w.times = w.times[len(w.times)-rule.MaxTransactions-1:]
+	}
+
+	if len(w.times) <= rule.MaxTransactions {
+		w.fired = false
+		return ""
+	}
+	if w.fired {
+		return ""
+	}
+	w.fired = true
+	return fmt.Sprintf("more than %d transactions within %s", rule.MaxTransactions, window)
+}
+
+// forget drops the velocity windows of a deleted rule
+func (bt *behaviorTracker) forget(ruleID string) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+
+	for key := range bt.velocity {
+		if key.ruleID == ruleID {
+			delete(bt.velocity, key)
+		}
+	}
+}
+
+// dormantReason returns why the rule fires for a wallet whose previous
+// activity was at previous, or "" if it doesn't
+func dormantReason(rule domain.AlertRule, previous time.Time, tx domain.Transaction) string {
+	if previous.IsZero() {
+		return ""
+	}
+
+	dormantFor, _ := time.ParseDuration(rule.DormantFor) // Validated when the rule was created
+	idle := activityTime(tx).Sub(previous)
+	if idle < dormantFor {
+		return ""
+	}
+	return fmt.Sprintf("active after %s without activity", idle.Truncate(time.Minute))
+}
+
+// drainedTransfers returns the outgoing transfers of tx that took at least
+// the rule's share of the wallet's balance, and why the rule fires. The
+// balance before a transfer is its current balance plus the amount sent.
+func (ae *AlertEngine) drainedTransfers(
+	ctx context.Context,
+	rule domain.AlertRule,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+) ([]domain.Transfer, string) {
+	if tx.Status == domain.TxReverted {
+		return nil, ""
+	}
+
+	var (
+		matched []domain.Transfer
+		reasons []string
+	)
+	for _, transfer := range tx.Transfers {
+		if transfer.TokenStandard == domain.ERC721Token || transfer.Value == nil || transfer.Value.Sign() <= 0 {
+			continue
+		}
+		if !strings.EqualFold(string(transfer.From), string(walletAddress)) {
+			continue
+		}
+		if rule.TokenAddress != "" && !strings.EqualFold(rule.TokenAddress, transfer.TokenAddress) {
+			continue
+		}
+
+		balance, err := ae.balanceOf(ctx, walletAddress, transfer)
+		if err != nil {
+			ae.logger.Warn("Failed to get balance for drain alert",
+				zap.String("rule_id", rule.ID),
+				zap.String("wallet", string(walletAddress)),
+				zap.String("token", transfer.TokenAddress),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		before := new(big.Int).Add(balance, transfer.Value)
+		share, _ := new(big.Float).Quo(new(big.Float).SetInt(transfer.Value), new(big.Float).SetInt(before)).Float64()
+		if share*100 < rule.DrainPercent {
+			continue
+		}
+
+		matched = append(matched, transfer)
+		reasons = append(reasons, fmt.Sprintf("sent %.1f%% of %s balance", share*100, tokenName(transfer)))
+	}
+
+	return matched, strings.Join(reasons, "; ")
+}
+
+// balanceOf returns the wallet's current balance of the transferred token
+func (ae *AlertEngine) balanceOf(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	transfer domain.Transfer,
+) (*big.Int, error) {
+	var (
+		balance *domain.TokenBalance
+		err     error
+	)
+	if transfer.TokenStandard == domain.NativeToken {
+		balance, err = ae.blockchainClient.GetNativeBalance(ctx, walletAddress)
+	} else {
+		balance, err = ae.blockchainClient.GetTokenBalance(ctx, walletAddress, transfer.TokenAddress)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if balance.Balance == nil {
+		return new(big.Int), nil
+	}
+	return balance.Balance, nil
+}
+
+// tokenName returns the symbol of the transferred token, or its address
+func tokenName(transfer domain.Transfer) string {
+	if transfer.TokenSymbol != "" {
+		return transfer.TokenSymbol
+	}
+	return transfer.TokenAddress
+}
+
+// activityTime returns when tx happened, falling back to now for
+// transactions without a block time
+func activityTime(tx domain.Transaction) time.Time {
+	if tx.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return tx.Timestamp
+}
+
+// normalizeBehaviorRule validates the thresholds of a velocity, drain or
+// dormant rule
+func normalizeBehaviorRule(rule *domain.AlertRule) error {
+	switch rule.Kind {
+	case domain.VelocityAlert:
+		if rule.MaxTransactions <= 0 || rule.MaxTransactions > maxVelocityTransactions {
+			return fmt.Errorf("%w: max_transactions must be between 1 and %d",
+				domain.ErrInvalidAlertRule, maxVelocityTransactions)
+		}
+		window, err := time.ParseDuration(rule.Window)
+		if err != nil || window <= 0 || window > maxVelocityWindow {
+			return fmt.Errorf("%w: window must be a duration up to %s, got %q",
+				domain.ErrInvalidAlertRule, maxVelocityWindow, rule.Window)
+		}
+
+	case domain.DrainAlert:
+		if rule.DrainPercent <= 0 || rule.DrainPercent > 100 {
+			return fmt.Errorf("%w: drain_percent must be above 0 and at most 100", domain.ErrInvalidAlertRule)
+		}
+
+	case domain.DormantAlert:
+		dormantFor, err := time.ParseDuration(rule.DormantFor)
+		if err != nil || dormantFor <= 0 {
+			return fmt.Errorf("%w: invalid dormant_for %q", domain.ErrInvalidAlertRule, rule.DormantFor)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown kind %q", domain.ErrInvalidAlertRule, rule.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Maximum number of wallets in a single add_wallets or remove_wallets
+const maxBulkWallets = 1000
+
+// AddWallets subscribes the user to every wallet, or to none of them if
+// any is rejected. Wallets the user already follows are left as they are.
+func (wt *WalletTracker) AddWallets(
+	ctx context.Context,
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+	filters *domain.NotificationFilter,
+) (*domain.BulkSubscriptionResult, error) {
+	if filters != nil {
+		if err := normalizeNotificationFilter(filters); err != nil {
+			return nil, err
+		}
+	}
+
+	wt.mu.Lock()
+	result, subscriptions, err := wt.newBulkSubscriptions(walletAddresses, userID, filters)
+	wt.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist first, so a failure leaves every tracked wallet in place
+	if len(subscriptions) > 0 {
+		if err := wt.repo.AddSubscriptions(ctx, subscriptions); err != nil {
+			return nil, fmt.Errorf("failed to persist subscriptions: %w", err)
+		}
+	}
+
+	// Admit every new listener before subscribing to any
+	var evicted []*evictedWallet
+	wt.mu.Lock()
+	pending := 0
+	for _, subscription := range subscriptions {
+		if _, exists := wt.listeners[subscription.WalletAddress]; exists {
+			continue
+		}
+		var victim *evictedWallet
+		if victim, err = wt.admitWallet(subscription.WalletAddress, pending); err != nil {
+			break
+		}
+		if victim != nil {
+			evicted = append(evicted, victim)
+		}
+		pending++
+	}
+	var started []domain.WalletAddress
+	if err == nil {
+		for _, subscription := range subscriptions {
+			if wt.subscribe(subscription) {
+				started = append(started, subscription.WalletAddress)
+			}
+		}
+	}
+	wt.mu.Unlock()
+
+	// Wallets evicted before a rejection are gone either way
+	for _, victim := range evicted {
+		wt.removeEvicted(ctx, victim)
+	}
+	if err != nil {
+		wt.unpersist(ctx, result.Changed, userID)
+		return nil, err
+	}
+
+	wt.trackArchive(ctx, started...)
+	for _, subscription := range subscriptions {
+		wt.audit.recordSubscription(ctx, domain.AuditSubscriptionAdded, domain.WalletSubscriptionKind,
+			subscription.WalletAddress, userID, "")
+		wt.startBackfill(subscription.WalletAddress, userID)
+	}
+
+	wt.logger.Info("Added wallets in bulk",
+		zap.Int64("user_id", int64(userID)),
+		zap.Int("added", len(result.Changed)),
+		zap.Int("unchanged", len(result.Unchanged)),
+	)
+	return result, nil
+}
+
+// newBulkSubscriptions splits walletAddresses into the ones the user already
+// follows and new subscriptions, which it checks against the limits.
+// Caller must hold wt.mu.
+func (wt *WalletTracker) newBulkSubscriptions(
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+	filters *domain.NotificationFilter,
+) (*domain.BulkSubscriptionResult, []domain.WalletSubscription, error) {
+	result := &domain.BulkSubscriptionResult{}
+	var subscriptions []domain.WalletSubscription
+	for _, walletAddress := range walletAddresses {
+		if slices.Contains(wt.subscribers[walletAddress], userID) {
+			result.Unchanged = append(result.Unchanged, walletAddress)
+			continue
+		}
+		result.Changed = append(result.Changed, walletAddress)
+		subscriptions = append(subscriptions, domain.WalletSubscription{
+			WalletAddress: walletAddress,
+			UserID:        userID,
+			Filters:       filters,
+			CreatedAt:     time.Now(),
+		})
+	}
+
+	if err := wt.limits.Check(userID, wt.walletCount(userID), len(subscriptions)); err != nil {
+		return nil, nil, err
+	}
+
+	pending := 0
+	for _, subscription := range subscriptions {
+		if _, exists := wt.listeners[subscription.WalletAddress]; exists {
+			continue
+		}
+		if err := wt.checkAdmission(subscription.WalletAddress, pending); err != nil {
+			return nil, nil, err
+		}
+		pending++
+	}
+
+	return result, subscriptions, nil
+}
+
+// RemoveWallets unsubscribes the user from every wallet, or from none of
+// them if persisting fails. Wallets the user doesn't follow are skipped.
+func (wt *WalletTracker) RemoveWallets(
+	ctx context.Context,
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+) (*domain.BulkSubscriptionResult, error) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	result := &domain.BulkSubscriptionResult{}
+	for _, walletAddress := range walletAddresses {
+		if slices.Contains(wt.subscribers[walletAddress], userID) {
+			result.Changed = append(result.Changed, walletAddress)
+		} else {
+			result.Unchanged = append(result.Unchanged, walletAddress)
+		}
+	}
+
+	return wt.removeSubscriptions(ctx, userID, result)
+}
+
+// RemoveAllWallets unsubscribes the user from every wallet, for users who
+// blocked the bot or deleted their account
+func (wt *WalletTracker) RemoveAllWallets(
+	ctx context.Context,
+	userID domain.UserID,
+) (*domain.BulkSubscriptionResult, error) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	result := &domain.BulkSubscriptionResult{}
+	for walletAddress, subscribers := range wt.subscribers {
+		if slices.Contains(subscribers, userID) {
+			result.Changed = append(result.Changed, walletAddress)
+		}
+	}
+
+	return wt.removeSubscriptions(ctx, userID, result)
+}
+
+// removeSubscriptions unsubscribes the user from the changed wallets of
+// result. Caller must hold wt.mu.
+func (wt *WalletTracker) removeSubscriptions(
+	ctx context.Context,
+	userID domain.UserID,
+	result *domain.BulkSubscriptionResult,
+) (*domain.BulkSubscriptionResult, error) {
+	if len(result.Changed) > 0 {
+		if err := wt.repo.RemoveSubscriptions(ctx, result.Changed, userID); err != nil {
+			return nil, fmt.Errorf("failed to remove subscriptions: %w", err)
+		}
+	}
+
+	for _, walletAddress := range result.Changed {
+		wt.unsubscribe(walletAddress, userID)
+		wt.audit.recordSubscription(ctx, domain.AuditSubscriptionRemoved, domain.WalletSubscriptionKind,
+			walletAddress, userID, "")
+	}
+
+	wt.logger.Info("Removed wallets of user",
+		zap.Int64("user_id", int64(userID)),
+		zap.Int("removed", len(result.Changed)),
+		zap.Int("unchanged", len(result.Unchanged)),
+	)
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// chatMessage is a notification or an alert on its way to a user's chat
+type chatMessage struct {
+	userID       domain.UserID
+	notification *domain.WalletNotification
+	alert        *domain.AlertNotification
+}
+
+// ChatNotifier is a sink sending wallet notifications and alerts to the
+// chats of the users they are for. Each user's messages go through one
+// worker, keeping their order.
+type ChatNotifier struct {
+	discardPublisher
+
+	messenger domain.Messenger
+	shards    []chan chatMessage
+	logger    *zap.Logger
+
+	// Messages taken off a queue and not sent yet
+	inFlight atomic.Int64
+}
+
+func NewChatNotifier(
+	messenger domain.Messenger,
+	workers int,
+	queueSize int,
+	logger *zap.Logger,
+) *ChatNotifier {
+	shards := make([]chan chatMessage, max(workers, 1))
+	for i := range shards {
+		shards[i] = make(chan chatMessage, max(queueSize/len(shards), 1))
+	}
+
+	return &ChatNotifier{
+		messenger: messenger,
+		shards:    shards,
+		logger:    logger,
+	}
+}
+
+// Start runs one sender per shard until ctx is done
+func (cn *ChatNotifier) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, shard := range cn.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case message := <-shard:
+					cn.inFlight.Add(1)
+					cn.send(ctx, message)
+					cn.inFlight.Add(-1)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Drain waits until queued messages are sent or ctx is done. Call it once
+// nothing publishes anymore.
+func (cn *ChatNotifier) Drain(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for cn.queued() > 0 || cn.inFlight.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (cn *ChatNotifier) queued() int {
+	queued := 0
+	for _, shard := range cn.shards {
+		queued += len(shard)
+	}
+	return queued
+}
+
+// PublishNotification queues notification for the chat of each subscriber
+func (cn *ChatNotifier) PublishNotification(ctx context.Context, notification domain.WalletNotification) error {
+	for _, userID := range notification.Subscribers {
+		forUser := notificationFor(notification, userID)
+		cn.enqueue(chatMessage{userID: userID, notification: &forUser})
+	}
+	return nil
+}
+
+// PublishAlert queues alert for the chat of the rule's owner
+func (cn *ChatNotifier) PublishAlert(ctx context.Context, alert domain.AlertNotification) error {
+	cn.enqueue(chatMessage{userID: alert.Rule.UserID, alert: &alert})
+	return nil
+}
+
+func (cn *ChatNotifier) enqueue(message chatMessage) {
+	shard := cn.shards[uint64(message.userID)%uint64(len(cn.shards))]
+	select {
+	case shard <- message:
+	default:
+		metrics.ChatMessagesTotal.WithLabelValues("queue_full").Inc()
+		cn.logger.Warn("Chat message queue full, dropping message",
+			zap.Int64("user_id", int64(message.userID)),
+		)
+	}
+}
+
+func (cn *ChatNotifier) send(ctx context.Context, message chatMessage) {
+	var err error
+	if message.alert != nil {
+		err = cn.messenger.SendAlert(ctx, message.userID, *message.alert)
+	} else {
+		err = cn.messenger.SendNotification(ctx, message.userID, *message.notification)
+	}
+
+	if err != nil {
+		metrics.ChatMessagesTotal.WithLabelValues("failed").Inc()
+		cn.logger.Warn("Failed to send chat message",
+			zap.Int64("user_id", int64(message.userID)),
+			zap.Error(err),
+		)
+		return
+	}
+	metrics.ChatMessagesTotal.WithLabelValues("ok").Inc()
+}
@@ -1,44 +1,800 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
 	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type CommandHandler struct {
 	walletTracker *WalletTracker
+	labels        *LabelRegistry
+	portfolio     *PortfolioService
+	snapshots     *BalanceSnapshots
+	gasAnalytics  *GasAnalytics
+	contracts     *ContractWatcher
+	tokens        *TokenWatcher
+	tokenFilters  *TokenFilters
+	history       *HistoryService
+	archive       *TransactionArchive
+	alerts        *AlertEngine
+	webhooks      *WebhookDispatcher
+	limits        *UserLimits
+	groups        *WalletGroups
+	audit         *AuditTrail
+	logLevel      zap.AtomicLevel
+	reloader      *ConfigReloader
+	deadLetters   *DeadLetters
+	publisher     domain.Publisher
 	logger        *zap.Logger
 }
 
-func NewCommandHandler(walletTracker *WalletTracker, logger *zap.Logger) *CommandHandler {
+func NewCommandHandler(
+	walletTracker *WalletTracker,
+	labels *LabelRegistry,
+	portfolio *PortfolioService,
+	snapshots *BalanceSnapshots,
+	gasAnalytics *GasAnalytics,
+	contracts *ContractWatcher,
+	tokens *TokenWatcher,
+	tokenFilters *TokenFilters,
+	history *HistoryService,
+	archive *TransactionArchive,
+	alerts *AlertEngine,
+	webhooks *WebhookDispatcher,
+	limits *UserLimits,
+	groups *WalletGroups,
+	audit *AuditTrail,
+	logLevel zap.AtomicLevel,
+	reloader *ConfigReloader,
+	deadLetters *DeadLetters,
+	publisher domain.Publisher,
+	logger *zap.Logger,
+) *CommandHandler {
 	return &CommandHandler{
 		walletTracker: walletTracker,
+		labels:        labels,
+		portfolio:     portfolio,
+		snapshots:     snapshots,
+		gasAnalytics:  gasAnalytics,
+		contracts:     contracts,
+		tokens:        tokens,
+		tokenFilters:  tokenFilters,
+		history:       history,
+		archive:       archive,
+		alerts:        alerts,
+		webhooks:      webhooks,
+		limits:        limits,
+		groups:        groups,
+		audit:         audit,
+		logLevel:      logLevel,
+		reloader:      reloader,
+		deadLetters:   deadLetters,
+		publisher:     publisher,
 		logger:        logger,
 	}
 }
 
-func (ch *CommandHandler) HandleCommand(cmd domain.Command) {
+// HandleCommand runs cmd and answers the bot, and the cmd.ReplyTo channel
+// if set. It returns an error only if the command failed for a reason that
+// may go away on a retry.
+func (ch *CommandHandler) HandleCommand(cmd domain.Command) error {
 	ch.logger.Info("Received command",
 		zap.String("type", string(cmd.Type)),
 		zap.String("wallet", string(cmd.WalletAddress)),
 		zap.Int64("user_id", int64(cmd.UserID)),
+		zap.String("trace_id", traceID(cmd.Trace)),
 	)
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), cmd.Trace), "command.handle",
+		attribute.String("command.type", string(cmd.Type)),
+		attribute.Int64("user_id", int64(cmd.UserID)),
+	)
+	defer span.End()
+
+	// Reject malformed addresses up front instead of tracking them forever
+	if err := normalizeCommand(&cmd); err != nil {
+		ch.logger.Warn("Rejected command with invalid address",
+			zap.String("type", string(cmd.Type)),
+			zap.Error(err),
+		)
+		tracing.SetError(span, err)
+		ch.audit.recordCommand(ctx, cmd, err)
+		ch.reply(ctx, cmd, nil, err)
+		ch.sendResult(ctx, cmd, nil, err)
+		return nil
+	}
+
+	start := time.Now()
+	data, err := ch.dispatch(ctx, cmd)
+	observeCommand(cmd.Type, start, err)
+	tracing.SetError(span, err)
+	ch.audit.recordCommand(ctx, cmd, err)
+	ch.sendResult(ctx, cmd, data, err)
+
+	if errors.Is(err, domain.ErrUnknownCommand) {
+		ch.logger.Error("Unknown command type", zap.String("type", string(cmd.Type)))
+		return nil
+	}
+
+	if err != nil {
+		ch.logger.Error("Failed to handle command",
+			zap.String("type", string(cmd.Type)),
+			zap.Error(err),
+		)
+	}
+
+	if answered(cmd.Type, err) {
+		if replyErr := ch.reply(ctx, cmd, data, err); err == nil && replyErr != nil {
+			return replyErr
+		}
+	}
+	return retryable(err)
+}
+
+// Execute runs cmd for callers that answer it themselves, such as the gRPC
+// API, and returns the data HandleCommand would reply with. Nothing is
+// published on the bot or reply channels.
+func (ch *CommandHandler) Execute(ctx context.Context, cmd domain.Command) (any, error) {
+	ctx, span := tracing.Start(ctx, "command.execute",
+		attribute.String("command.type", string(cmd.Type)),
+		attribute.Int64("user_id", int64(cmd.UserID)),
+	)
+	defer span.End()
+
+	if err := normalizeCommand(&cmd); err != nil {
+		tracing.SetError(span, err)
+		ch.audit.recordCommand(ctx, cmd, err)
+		return nil, err
+	}
+
+	start := time.Now()
+	data, err := ch.dispatch(ctx, cmd)
+	observeCommand(cmd.Type, start, err)
+	tracing.SetError(span, err)
+	ch.audit.recordCommand(ctx, cmd, err)
 
-	var err error
+	if err != nil && retryable(err) != nil {
+		ch.logger.Error("Failed to execute command",
+			zap.String("type", string(cmd.Type)),
+			zap.Error(err),
+		)
+	}
+	return data, err
+}
+
+// dispatch runs cmd and returns the data to answer it with
+func (ch *CommandHandler) dispatch(ctx context.Context, cmd domain.Command) (any, error) {
 	switch cmd.Type {
 	case domain.AddWalletCommand:
-		err = ch.walletTracker.AddWallet(cmd.WalletAddress, cmd.UserID)
+		return nil, ch.walletTracker.AddWallet(ctx, cmd.WalletAddress, cmd.UserID, cmd.Filters, cmd.Label)
 	case domain.RemoveWalletCommand:
-		err = ch.walletTracker.RemoveWallet(cmd.WalletAddress, cmd.UserID)
+		return nil, ch.walletTracker.RemoveWallet(ctx, cmd.WalletAddress, cmd.UserID)
+	case domain.AddWalletsCommand:
+		return ch.walletTracker.AddWallets(ctx, cmd.WalletAddresses, cmd.UserID, cmd.Filters)
+	case domain.RemoveWalletsCommand:
+		return ch.walletTracker.RemoveWallets(ctx, cmd.WalletAddresses, cmd.UserID)
+	case domain.RemoveAllWalletsCommand:
+		return ch.walletTracker.RemoveAllWallets(ctx, cmd.UserID)
+	case domain.PauseWalletCommand:
+		return ch.handlePauseWallet(ctx, cmd)
+	case domain.ResumeWalletCommand:
+		return ch.walletTracker.ResumeWallet(ctx, cmd.WalletAddress, cmd.UserID)
+	case domain.SetDigestCommand:
+		return ch.handleSetDigest(ctx, cmd)
+	case domain.CreateGroupCommand:
+		return ch.groups.Create(ctx, cmd.UserID, cmd.Group)
+	case domain.DeleteGroupCommand:
+		return nil, ch.groups.Delete(ctx, cmd.UserID, cmd.Group)
+	case domain.AddToGroupCommand:
+		return ch.handleAddToGroup(ctx, cmd)
+	case domain.RemoveFromGroupCommand:
+		return ch.groups.RemoveWallets(ctx, cmd.UserID, cmd.Group, cmd.WalletAddresses)
+	case domain.ListGroupsCommand:
+		return ch.groups.List(cmd.UserID), nil
+	case domain.PauseGroupCommand:
+		return ch.handlePauseGroup(ctx, cmd)
+	case domain.ResumeGroupCommand:
+		return ch.handleResumeGroup(ctx, cmd)
+	case domain.GroupSummaryCommand:
+		return ch.handleGroupSummary(ctx, cmd)
+	case domain.SetWalletLimitCommand:
+		return ch.limits.Set(ctx, cmd.UserID, cmd.TargetUserID, cmd.WalletLimit)
+	case domain.ImportLabelsCommand:
+		_, err := ch.labels.Import(ctx, LabelsFromMap(cmd.Labels))
+		return nil, err
+	case domain.RemoveLabelsCommand:
+		_, err := ch.labels.Remove(ctx, cmd.WalletAddresses)
+		return nil, err
+	case domain.ListWalletsCommand:
+		return ch.walletTracker.SubscriptionsForUser(ctx, cmd.UserID)
+	case domain.GetStatusCommand:
+		return ch.walletTracker.Status(), nil
+	case domain.PortfolioCommand:
+		return ch.portfolio.GetPortfolioStatus(ctx, cmd.UserID)
+	case domain.GetPortfolioValueCommand:
+		return ch.handleGetPortfolioValue(ctx, cmd)
+	case domain.GasUsageCommand:
+		return ch.handleGasUsage(ctx, cmd)
+	case domain.GetBalanceCommand:
+		return ch.portfolio.GetWalletBalance(ctx, cmd.WalletAddress, cmd.TokenAddress)
+	case domain.WatchContractCommand:
+		return nil, ch.handleWatchContract(ctx, cmd)
+	case domain.UnwatchContractCommand:
+		return nil, ch.contracts.Unwatch(ctx, cmd.ContractAddress, cmd.UserID)
+	case domain.TrackTokenCommand:
+		return nil, ch.handleTrackToken(ctx, cmd)
+	case domain.UntrackTokenCommand:
+		return nil, ch.tokens.Untrack(ctx, domain.WalletAddress(cmd.TokenAddress), cmd.UserID)
+	case domain.AllowTokenCommand:
+		return ch.handleListToken(ctx, cmd, domain.TokenAllowList)
+	case domain.DenyTokenCommand:
+		return ch.handleListToken(ctx, cmd, domain.TokenDenyList)
+	case domain.UnlistTokenCommand:
+		return ch.handleUnlistToken(ctx, cmd)
+	case domain.GetTokenFiltersCommand:
+		return ch.tokenFilters.Status(cmd.UserID), nil
+	case domain.GetHistoryCommand:
+		return ch.history.GetHistory(ctx, cmd.WalletAddress, cmd.FromBlock, cmd.ToBlock, cmd.Limit)
+	case domain.GetStatsCommand:
+		return ch.handleGetStats(ctx, cmd)
+	case domain.CreateAlertCommand:
+		return ch.handleCreateAlert(ctx, cmd)
+	case domain.ListAlertsCommand:
+		return ch.alerts.List(cmd.UserID), nil
+	case domain.DeleteAlertCommand:
+		return nil, ch.alerts.Delete(ctx, cmd.UserID, cmd.RuleID)
+	case domain.AddWebhookCommand:
+		return ch.webhooks.Register(ctx, cmd.UserID, cmd.WebhookURL, cmd.WalletAddress)
+	case domain.DeleteWebhookCommand:
+		return nil, ch.webhooks.Delete(ctx, cmd.UserID, cmd.WebhookID)
+	case domain.ListWebhooksCommand:
+		return ch.webhooks.List(cmd.UserID), nil
+	case domain.ListWebhookFailuresCommand:
+		return ch.webhooks.Failures(ctx, cmd.UserID)
+	case domain.GetAuditLogCommand:
+		return ch.handleGetAuditLog(ctx, cmd)
+	case domain.SetLogLevelCommand:
+		return ch.handleSetLogLevel(cmd)
+	case domain.ReloadConfigCommand:
+		return ch.handleReloadConfig(cmd)
+	case domain.ListDeadLettersCommand, domain.RequeueDeadLettersCommand, domain.PurgeDeadLettersCommand:
+		return ch.handleDeadLetters(ctx, cmd)
 	default:
-		ch.logger.Error("Unknown command type", zap.String("type", string(cmd.Type)))
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownCommand, cmd.Type)
+	}
+}
+
+// answered reports whether the outcome of a command gets a response on the
+// bot channel. Subscription changes are only answered when rejected for a
+// reason the user can act on.
+func answered(commandType domain.CommandType, err error) bool {
+	switch commandType {
+	case domain.AddWalletCommand, domain.RemoveWalletCommand, domain.ImportLabelsCommand:
+		return errors.Is(err, domain.ErrQuotaExceeded) || errors.Is(err, domain.ErrLimitExceeded) ||
+			errors.Is(err, domain.ErrInvalidFilter) || errors.Is(err, domain.ErrInvalidLabel) ||
+			errors.Is(err, domain.ErrSubscriptionExists) ||
+			errors.Is(err, domain.ErrWalletNotFound)
+	default:
+		return true
+	}
+}
+
+// retryable returns err if the command may succeed when delivered again.
+// Rejections are final.
+func retryable(err error) error {
+	if err != nil && errorCode(err) == "internal_error" {
+		return err
+	}
+	return nil
+}
+
+func (ch *CommandHandler) handlePauseWallet(ctx context.Context, cmd domain.Command) (any, error) {
+	duration, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.walletTracker.PauseWallet(ctx, cmd.WalletAddress, cmd.UserID, duration)
+}
+
+func (ch *CommandHandler) handleSetDigest(ctx context.Context, cmd domain.Command) (any, error) {
+	window, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.walletTracker.SetDigest(ctx, cmd.WalletAddress, cmd.UserID, window)
+}
+
+// commandDuration parses cmd.Duration; empty gives zero
+func commandDuration(cmd domain.Command) (time.Duration, error) {
+	if cmd.Duration == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(cmd.Duration)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", domain.ErrInvalidDuration, cmd.Duration)
+	}
+	return duration, nil
+}
+
+// handleGetPortfolioValue returns what the user's wallets are worth and the
+// change over cmd.Duration
+func (ch *CommandHandler) handleGetPortfolioValue(ctx context.Context, cmd domain.Command) (any, error) {
+	window, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.snapshots.PortfolioValue(ctx, cmd.UserID, window)
+}
+
+// handleGetStats returns the wallet's stats over the last cmd.Duration, or
+// its whole stored history if empty
+func (ch *CommandHandler) handleGetStats(ctx context.Context, cmd domain.Command) (any, error) {
+	window, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if window > 0 {
+		since = time.Now().Add(-window)
+	}
+	return ch.archive.Stats(ctx, cmd.WalletAddress, since, time.Time{})
+}
+
+// handleGetAuditLog returns the user's audit entries of the last
+// cmd.Duration, optionally about cmd.WalletAddress. Admins may query
+// another user with cmd.TargetUserID.
+func (ch *CommandHandler) handleGetAuditLog(ctx context.Context, cmd domain.Command) (any, error) {
+	target := cmd.UserID
+	if cmd.TargetUserID != 0 && cmd.TargetUserID != cmd.UserID {
+		if !ch.limits.IsAdmin(cmd.UserID) {
+			return nil, fmt.Errorf("%w: user %d is not an admin", domain.ErrForbidden, cmd.UserID)
+		}
+		target = cmd.TargetUserID
+	}
+
+	window, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	query := domain.AuditQuery{
+		UserID:  target,
+		Address: cmd.WalletAddress,
+		Limit:   cmd.Limit,
+	}
+	if window > 0 {
+		query.Since = time.Now().Add(-window)
+	}
+	return ch.audit.Query(ctx, query)
+}
+
+// handleSetLogLevel switches the log level of the running instance, e.g. to
+// debug while chasing a missed notification
+func (ch *CommandHandler) handleSetLogLevel(cmd domain.Command) (any, error) {
+	if !ch.limits.IsAdmin(cmd.UserID) {
+		return nil, fmt.Errorf("%w: user %d is not an admin", domain.ErrForbidden, cmd.UserID)
+	}
+
+	current := ch.logLevel.Level()
+	if cmd.LogLevel == "" {
+		return domain.LogLevelStatus{Level: current.String()}, nil
+	}
+
+	level, err := zapcore.ParseLevel(cmd.LogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidLogLevel, cmd.LogLevel)
+	}
+	ch.logLevel.SetLevel(level)
+
+	ch.logger.Warn("Log level changed",
+		zap.String("from", current.String()),
+		zap.String("to", level.String()),
+		zap.Int64("user_id", int64(cmd.UserID)),
+	)
+	return domain.LogLevelStatus{Level: level.String(), Previous: current.String()}, nil
+}
+
+// handleReloadConfig applies changed tunable settings of the running
+// instance, as SIGHUP does
+func (ch *CommandHandler) handleReloadConfig(cmd domain.Command) (any, error) {
+	if !ch.limits.IsAdmin(cmd.UserID) {
+		return nil, fmt.Errorf("%w: user %d is not an admin", domain.ErrForbidden, cmd.UserID)
+	}
+
+	ch.logger.Warn("Configuration reload requested", zap.Int64("user_id", int64(cmd.UserID)))
+	return ch.reloader.Reload()
+}
+
+// handleDeadLetters lists, requeues or purges dead letters. Requeued
+// commands are handled again as if just received.
+func (ch *CommandHandler) handleDeadLetters(ctx context.Context, cmd domain.Command) (any, error) {
+	if !ch.limits.IsAdmin(cmd.UserID) {
+		return nil, fmt.Errorf("%w: user %d is not an admin", domain.ErrForbidden, cmd.UserID)
+	}
+
+	switch cmd.Type {
+	case domain.ListDeadLettersCommand:
+		return ch.deadLetters.List(ctx, cmd.Limit)
+	case domain.RequeueDeadLettersCommand:
+		ch.logger.Warn("Dead letter requeue requested", zap.Int64("user_id", int64(cmd.UserID)))
+		return ch.deadLetters.Requeue(ctx, cmd.DeadLetterIDs, cmd.Limit, ch.HandleCommand)
+	default:
+		ch.logger.Warn("Dead letter purge requested", zap.Int64("user_id", int64(cmd.UserID)))
+		return ch.deadLetters.Purge(ctx, cmd.DeadLetterIDs)
+	}
+}
+
+func (ch *CommandHandler) handleAddToGroup(ctx context.Context, cmd domain.Command) (any, error) {
+	followed := ch.walletTracker.WalletsForUser(cmd.UserID)
+	for _, wallet := range cmd.WalletAddresses {
+		if !slices.Contains(followed, wallet) {
+			return nil, fmt.Errorf("%w: user %d doesn't follow %s", domain.ErrWalletNotFound, cmd.UserID, wallet)
+		}
+	}
+
+	return ch.groups.AddWallets(ctx, cmd.UserID, cmd.Group, cmd.WalletAddresses)
+}
+
+// handlePauseGroup pauses every wallet of the group the user follows
+func (ch *CommandHandler) handlePauseGroup(ctx context.Context, cmd domain.Command) (any, error) {
+	duration, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.forGroupWallets(cmd, func(wallet domain.WalletAddress) (*domain.SubscriptionPause, error) {
+		return ch.walletTracker.PauseWallet(ctx, wallet, cmd.UserID, duration)
+	})
+}
+
+// handleResumeGroup resumes every wallet of the group the user follows
+func (ch *CommandHandler) handleResumeGroup(ctx context.Context, cmd domain.Command) (any, error) {
+	return ch.forGroupWallets(cmd, func(wallet domain.WalletAddress) (*domain.SubscriptionPause, error) {
+		return ch.walletTracker.ResumeWallet(ctx, wallet, cmd.UserID)
+	})
+}
+
+// forGroupWallets calls fn for each wallet of the group the user still
+// follows and collects the results
+func (ch *CommandHandler) forGroupWallets(
+	cmd domain.Command,
+	fn func(wallet domain.WalletAddress) (*domain.SubscriptionPause, error),
+) ([]domain.SubscriptionPause, error) {
+	group, err := ch.groups.Get(cmd.UserID, cmd.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	followed := ch.walletTracker.WalletsForUser(cmd.UserID)
+	results := make([]domain.SubscriptionPause, 0, len(group.Wallets))
+	for _, wallet := range group.Wallets {
+		if !slices.Contains(followed, wallet) {
+			continue
+		}
+		result, err := fn(wallet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+func (ch *CommandHandler) handleGroupSummary(ctx context.Context, cmd domain.Command) (any, error) {
+	group, err := ch.groups.Get(cmd.UserID, cmd.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.portfolio.GetGroupSummary(ctx, *group)
+}
+
+// handleGasUsage reports the fees paid over the last cmd.Duration, or in
+// total if empty
+func (ch *CommandHandler) handleGasUsage(ctx context.Context, cmd domain.Command) (any, error) {
+	window, err := commandDuration(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an explicit wallet report on every wallet of the user
+	followed := ch.walletTracker.WalletsForUser(cmd.UserID)
+	wallets := followed
+	if cmd.WalletAddress != "" {
+		if !slices.Contains(followed, cmd.WalletAddress) {
+			return nil, fmt.Errorf("%w: user %d doesn't follow %s", domain.ErrWalletNotFound, cmd.UserID, cmd.WalletAddress)
+		}
+		wallets = []domain.WalletAddress{cmd.WalletAddress}
+	}
+
+	return ch.gasAnalytics.GetUsage(ctx, wallets, window)
+}
+
+func (ch *CommandHandler) handleCreateAlert(ctx context.Context, cmd domain.Command) (any, error) {
+	if cmd.Alert == nil {
+		return nil, fmt.Errorf("%w: alert is required", domain.ErrInvalidAlertRule)
+	}
+
+	return ch.alerts.Create(ctx, cmd.UserID, *cmd.Alert)
+}
+
+func (ch *CommandHandler) handleWatchContract(ctx context.Context, cmd domain.Command) error {
+	subscription := domain.ContractSubscription{
+		ContractAddress: cmd.ContractAddress,
+		UserID:          cmd.UserID,
+		ABI:             cmd.ABI,
+		EventSignature:  cmd.EventSignature,
+	}
+
+	return ch.contracts.Watch(ctx, subscription)
+}
+
+func (ch *CommandHandler) handleTrackToken(ctx context.Context, cmd domain.Command) error {
+	subscription := domain.TokenSubscription{
+		TokenAddress: domain.WalletAddress(cmd.TokenAddress),
+		UserID:       cmd.UserID,
+		MinAmount:    cmd.MinAmount,
+	}
+
+	return ch.tokens.Track(ctx, subscription)
+}
+
+func (ch *CommandHandler) handleListToken(
+	ctx context.Context,
+	cmd domain.Command,
+	list domain.TokenFilterList,
+) (any, error) {
+	if err := ch.tokenFilters.Add(ctx, cmd.UserID, list, tokenFilterEntry(cmd)); err != nil {
+		return nil, err
+	}
+	return ch.tokenFilters.Status(cmd.UserID), nil
+}
+
+func (ch *CommandHandler) handleUnlistToken(ctx context.Context, cmd domain.Command) (any, error) {
+	entry := tokenFilterEntry(cmd)
+	if entry == "" {
+		return nil, fmt.Errorf("%w: token address or symbol pattern is required", domain.ErrInvalidTokenFilter)
+	}
+
+	if err := ch.tokenFilters.Remove(ctx, cmd.UserID, entry); err != nil {
+		return nil, err
+	}
+	return ch.tokenFilters.Status(cmd.UserID), nil
+}
+
+// tokenFilterEntry returns the token address of cmd, or its symbol pattern
+func tokenFilterEntry(cmd domain.Command) string {
+	if cmd.TokenAddress != "" {
+		return cmd.TokenAddress
+	}
+	return cmd.SymbolPattern
+}
+
+// normalizeCommand validates the addresses a command refers to and rewrites
+// them in checksum form. Addresses a command needs must be present.
+func normalizeCommand(cmd *domain.Command) error {
+	var required []*domain.WalletAddress
+	switch cmd.Type {
+	case domain.AddWalletCommand, domain.RemoveWalletCommand, domain.GetBalanceCommand,
+		domain.GetHistoryCommand, domain.PauseWalletCommand, domain.ResumeWalletCommand,
+		domain.SetDigestCommand, domain.GetStatsCommand:
+		required = append(required, &cmd.WalletAddress)
+	case domain.WatchContractCommand, domain.UnwatchContractCommand:
+		required = append(required, &cmd.ContractAddress)
+	}
+	for _, address := range required {
+		if *address == "" {
+			return fmt.Errorf("%w: address is required for %s", domain.ErrInvalidAddress, cmd.Type)
+		}
+	}
+	if (cmd.Type == domain.TrackTokenCommand || cmd.Type == domain.UntrackTokenCommand) && cmd.TokenAddress == "" {
+		return fmt.Errorf("%w: token address is required for %s", domain.ErrInvalidAddress, cmd.Type)
+	}
+
+	for _, address := range []*domain.WalletAddress{&cmd.WalletAddress, &cmd.ContractAddress} {
+		if *address == "" {
+			continue
+		}
+		normalized, err := NormalizeAddress(*address)
+		if err != nil {
+			return err
+		}
+		*address = normalized
+	}
+
+	if cmd.TokenAddress != "" {
+		normalized, err := NormalizeAddress(domain.WalletAddress(cmd.TokenAddress))
+		if err != nil {
+			return err
+		}
+		cmd.TokenAddress = string(normalized)
+	}
+
+	return normalizeWalletList(cmd)
+}
+
+// normalizeWalletList validates the wallets of a bulk command as a whole,
+// so a single bad address rejects the command. Duplicates are dropped.
+func normalizeWalletList(cmd *domain.Command) error {
+	switch cmd.Type {
+	case domain.AddWalletsCommand, domain.RemoveWalletsCommand, domain.RemoveLabelsCommand:
+	case domain.AddToGroupCommand, domain.RemoveFromGroupCommand:
+		if cmd.WalletAddress != "" {
+			cmd.WalletAddresses = append(cmd.WalletAddresses, cmd.WalletAddress)
+		}
+	default:
+		return nil
+	}
+	if len(cmd.WalletAddresses) == 0 {
+		return fmt.Errorf("%w: wallet addresses are required for %s", domain.ErrInvalidAddress, cmd.Type)
+	}
+	if len(cmd.WalletAddresses) > maxBulkWallets {
+		return fmt.Errorf("%w: at most %d wallets per %s", domain.ErrInvalidAddress, maxBulkWallets, cmd.Type)
+	}
+
+	wallets := make([]domain.WalletAddress, 0, len(cmd.WalletAddresses))
+	seen := make(map[domain.WalletAddress]struct{}, len(cmd.WalletAddresses))
+	for _, address := range cmd.WalletAddresses {
+		normalized, err := NormalizeAddress(address)
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		wallets = append(wallets, normalized)
+	}
+	cmd.WalletAddresses = wallets
+
+	return nil
+}
+
+// reply publishes the outcome of cmd, continuing the caller's trace
+func (ch *CommandHandler) reply(
+	ctx context.Context,
+	cmd domain.Command,
+	data any,
+	err error,
+) error {
+	response := domain.CommandResponse{
+		Type:      cmd.Type,
+		UserID:    cmd.UserID,
+		Timestamp: time.Now(),
+		Trace:     traceContextFor(ctx),
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+		response.ErrorCode = errorCode(err)
+	} else {
+		response.Success = true
+		response.Data = data
+	}
+
+	return ch.publisher.PublishResponse(ctx, response)
+}
+
+// sendResult reports the outcome of cmd to the channel it asked for
+func (ch *CommandHandler) sendResult(
+	ctx context.Context,
+	cmd domain.Command,
+	data any,
+	err error,
+) {
+	if cmd.ReplyTo == "" {
 		return
 	}
 
+	result := domain.CommandResult{
+		CorrelationID: cmd.CorrelationID,
+		Type:          cmd.Type,
+		UserID:        cmd.UserID,
+		Status:        domain.CommandSucceeded,
+		Timestamp:     time.Now(),
+	}
 	if err != nil {
-		ch.logger.Error("Failed to handle command",
-			zap.String("type", string(cmd.Type)),
+		result.Status = domain.CommandFailed
+		result.ErrorCode = errorCode(err)
+		result.Error = err.Error()
+	} else {
+		result.Data = data
+	}
+
+	if err := ch.publisher.PublishCommandResult(ctx, cmd.ReplyTo, result); err != nil {
+		ch.logger.Error("Failed to send command result",
+			zap.String("reply_to", cmd.ReplyTo),
+			zap.String("correlation_id", cmd.CorrelationID),
 			zap.Error(err),
 		)
 	}
 }
+
+// observeCommand records how long a command took. Unknown types share one
+// label so arbitrary input can't create new series.
+func observeCommand(commandType domain.CommandType, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = errorCode(err)
+	}
+	if errors.Is(err, domain.ErrUnknownCommand) {
+		commandType = "unknown"
+	}
+	metrics.CommandDuration.WithLabelValues(string(commandType), result).Observe(time.Since(start).Seconds())
+}
+
+// errorCode maps err to a stable code clients can branch on
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAddress):
+		return "invalid_address"
+	case errors.Is(err, domain.ErrInvalidContractABI):
+		return "invalid_contract_abi"
+	case errors.Is(err, domain.ErrInvalidTokenFilter):
+		return "invalid_token_filter"
+	case errors.Is(err, domain.ErrInvalidBlockRange):
+		return "invalid_block_range"
+	case errors.Is(err, domain.ErrInvalidAlertRule):
+		return "invalid_alert_rule"
+	case errors.Is(err, domain.ErrAlertRuleNotFound):
+		return "alert_rule_not_found"
+	case errors.Is(err, domain.ErrInvalidFilter):
+		return "invalid_filter"
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, domain.ErrSubscriptionExists):
+		return "subscription_exists"
+	case errors.Is(err, domain.ErrWalletNotFound):
+		return "wallet_not_found"
+	case errors.Is(err, domain.ErrUnknownCommand):
+		return "unknown_command"
+	case errors.Is(err, domain.ErrInvalidDuration):
+		return "invalid_duration"
+	case errors.Is(err, domain.ErrLimitExceeded):
+		return "limit_exceeded"
+	case errors.Is(err, domain.ErrInvalidLimit):
+		return "invalid_limit"
+	case errors.Is(err, domain.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, domain.ErrInvalidLabel):
+		return "invalid_label"
+	case errors.Is(err, domain.ErrInvalidGroup):
+		return "invalid_group"
+	case errors.Is(err, domain.ErrGroupNotFound):
+		return "group_not_found"
+	case errors.Is(err, domain.ErrAuditLogDisabled):
+		return "audit_log_disabled"
+	case errors.Is(err, domain.ErrDeadLettersDisabled):
+		return "dead_letters_disabled"
+	case errors.Is(err, domain.ErrInvalidLogLevel):
+		return "invalid_log_level"
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		return "invalid_webhook"
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		return "webhook_not_found"
+	case errors.Is(err, domain.ErrStatsUnavailable):
+		return "stats_unavailable"
+	case errors.Is(err, domain.ErrPortfolioUnvalued):
+		return "portfolio_unvalued"
+	case errors.Is(err, domain.ErrInvalidConfig):
+		return "invalid_config"
+	default:
+		return "internal_error"
+	}
+}
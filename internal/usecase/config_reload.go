@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Tunables are the settings a configuration reload applies to the running
+// services
+type Tunables struct {
+	LogLevel      string
+	TokenFilter   domain.TokenFilter // Global token allowlist and denylist
+	RateLimit     NotificationRateLimit
+	Confirmations ConfirmationPolicy
+	SinkFilters   map[string]SinkFilter // By sink name
+}
+
+// ConfigReloader re-reads the configuration and applies the tunable
+// settings that changed since it was last read, keeping subscriptions,
+// checkpoints and everything else in memory. A level set with
+// set_log_level stays until the configured level changes. Other settings
+// need a restart.
+type ConfigReloader struct {
+	load          func() (Tunables, error)
+	current       Tunables
+	logLevel      zap.AtomicLevel
+	tokenFilters  *TokenFilters
+	walletTracker *WalletTracker
+	sinks         *SinkRegistry
+	logger        *zap.Logger
+	mu            sync.Mutex
+}
+
+// NewConfigReloader returns a reloader calling load to read the settings,
+// current being those the services were started with
+func NewConfigReloader(
+	load func() (Tunables, error),
+	current Tunables,
+	logLevel zap.AtomicLevel,
+	tokenFilters *TokenFilters,
+	walletTracker *WalletTracker,
+	sinks *SinkRegistry,
+	logger *zap.Logger,
+) *ConfigReloader {
+	return &ConfigReloader{
+		load:          load,
+		current:       current,
+		logLevel:      logLevel,
+		tokenFilters:  tokenFilters,
+		walletTracker: walletTracker,
+		sinks:         sinks,
+		logger:        logger,
+	}
+}
+
+// Reload reads the configuration and applies what changed. Nothing is
+// applied if any of it is invalid.
+func (cr *ConfigReloader) Reload() (domain.ConfigReload, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	next, err := cr.load()
+	if err != nil {
+		return domain.ConfigReload{}, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+	}
+
+	// Check everything before changing anything; sink filters are checked
+	// and replaced in one go, last
+	level, err := zapcore.ParseLevel(next.LogLevel)
+	if err != nil {
+		return domain.ConfigReload{}, fmt.Errorf("%w: %q", domain.ErrInvalidLogLevel, next.LogLevel)
+	}
+	tokenFilter, err := compileTokenFilter(next.TokenFilter)
+	if err != nil {
+		return domain.ConfigReload{}, fmt.Errorf("%w: global token filter: %w", domain.ErrInvalidConfig, err)
+	}
+	sinkFilters := make(map[string]SinkFilter)
+	for name, filter := range next.SinkFilters {
+		if !filter.equal(cr.current.SinkFilters[name]) {
+			sinkFilters[name] = filter
+		}
+	}
+	if err := cr.sinks.setFilters(sinkFilters); err != nil {
+		return domain.ConfigReload{}, fmt.Errorf("%w: %w", domain.ErrInvalidConfig, err)
+	}
+
+	changed := []string{}
+	if next.LogLevel != cr.current.LogLevel {
+		cr.logLevel.SetLevel(level)
+		changed = append(changed, "log_level")
+	}
+	if !slices.Equal(next.TokenFilter.Allow, cr.current.TokenFilter.Allow) ||
+		!slices.Equal(next.TokenFilter.Deny, cr.current.TokenFilter.Deny) {
+		cr.tokenFilters.setGlobal(tokenFilter)
+		changed = append(changed, "token_filter")
+	}
+	if next.RateLimit != cr.current.RateLimit {
+		cr.walletTracker.throttle.setLimit(next.RateLimit)
+		changed = append(changed, "rate_limit")
+	}
+	if next.Confirmations != cr.current.Confirmations {
+		cr.walletTracker.setConfirmations(next.Confirmations)
+		changed = append(changed, "confirmations")
+	}
+	for _, name := range slices.Sorted(maps.Keys(sinkFilters)) {
+		changed = append(changed, "sink_filter:"+name)
+	}
+	cr.current = next
+
+	cr.logger.Info("Configuration reloaded", zap.Strings("changed", changed))
+	return domain.ConfigReload{Changed: changed, ReloadedAt: time.Now()}, nil
+}
+
+func (f SinkFilter) equal(other SinkFilter) bool {
+	return slices.Equal(f.Kinds, other.Kinds) && slices.Equal(f.NotificationTypes, other.NotificationTypes)
+}
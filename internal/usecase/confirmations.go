@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// ConfirmationPolicy controls when transaction notifications are published
+type ConfirmationPolicy struct {
+	// Confirmations required before publishing; 0 or 1 publishes immediately
+	Confirmations uint64
+	// NotifyUnconfirmed publishes an early notification flagged unconfirmed
+	NotifyUnconfirmed bool
+}
+
+func (p ConfirmationPolicy) gated() bool {
+	return p.Confirmations > 1
+}
+
+// pendingTx is a detected transaction waiting for confirmations
+type pendingTx struct {
+	walletAddress domain.WalletAddress
+	tx            domain.Transaction
+}
+
+func (p pendingTx) queued() domain.QueuedTransaction {
+	return domain.QueuedTransaction{WalletAddress: p.walletAddress, Transaction: p.tx}
+}
+
+// pendingBuffer holds transactions until they reach the confirmation depth.
+// They are persisted to the store, if any, since their blocks are already
+// checkpointed and wouldn't be detected again after a restart.
+type pendingBuffer struct {
+	store  domain.PendingStore
+	logger *zap.Logger
+
+	txs []pendingTx
+	mu  sync.Mutex
+}
+
+func newPendingBuffer(store domain.PendingStore, logger *zap.Logger) *pendingBuffer {
+	return &pendingBuffer{store: store, logger: logger}
+}
+
+// load restores the transactions held before a restart
+func (pb *pendingBuffer) load(ctx context.Context) error {
+	if pb.store == nil {
+		return nil
+	}
+
+	items, err := pb.store.LoadPending(ctx)
+	if err != nil {
+		return err
+	}
+
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	for _, item := range items {
+		pb.txs = append(pb.txs, pendingTx{walletAddress: item.WalletAddress, tx: item.Transaction})
+	}
+	return nil
+}
+
+func (pb *pendingBuffer) add(ctx context.Context, walletAddress domain.WalletAddress, tx domain.Transaction) {
+	pending := pendingTx{walletAddress: walletAddress, tx: tx}
+
+	pb.mu.Lock()
+	pb.txs = append(pb.txs, pending)
+	pb.mu.Unlock()
+
+	if pb.store == nil {
+		return
+	}
+	if err := pb.store.AddPending(ctx, pending.queued()); err != nil {
+		pb.logger.Error("Failed to persist pending transaction",
+			zap.String("wallet", string(walletAddress)),
+			zap.String("tx_hash", string(tx.Hash)),
+			zap.Error(err),
+		)
+	}
+}
+
+// release removes and returns transactions with enough confirmations at head
+func (pb *pendingBuffer) release(ctx context.Context, head, confirmations uint64) []pendingTx {
+	pb.mu.Lock()
+	var ready []pendingTx
+	remaining := pb.txs[:0]
+	for _, pending := range pb.txs {
+		if confirmationsAt(head, pending.tx.BlockNumber) >= confirmations {
+			ready = append(ready, pending)
+		} else {
+			remaining = append(remaining, pending)
+		}
+	}
+	pb.txs = remaining
+	pb.mu.Unlock()
+
+	pb.forget(ctx, ready)
+	return ready
+}
+
+// drop discards pending transactions from reorged blocks; if still
+// canonical they are detected again from the new chain
+func (pb *pendingBuffer) drop(ctx context.Context, reorg domain.Reorg) int {
+	pb.mu.Lock()
+	var dropped []pendingTx
+	remaining := pb.txs[:0]
+	for _, pending := range pb.txs {
+		if pending.tx.BlockNumber < reorg.FromBlock || pending.tx.BlockNumber > reorg.ToBlock {
+			remaining = append(remaining, pending)
+		} else {
+			dropped = append(dropped, pending)
+		}
+	}
+	pb.txs = remaining
+	pb.mu.Unlock()
+
+	pb.forget(ctx, dropped)
+	return len(dropped)
+}
+
+// forget removes transactions that are no longer held from the store
+func (pb *pendingBuffer) forget(ctx context.Context, txs []pendingTx) {
+	if pb.store == nil || len(txs) == 0 {
+		return
+	}
+
+	items := make([]domain.QueuedTransaction, len(txs))
+	for i, pending := range txs {
+		items[i] = pending.queued()
+	}
+	if err := pb.store.RemovePending(ctx, items); err != nil {
+		pb.logger.Error("Failed to remove released pending transactions",
+			zap.Int("transactions", len(items)),
+			zap.Error(err),
+		)
+	}
+}
+
+func (pb *pendingBuffer) len() int {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	return len(pb.txs)
+}
+
+func confirmationsAt(head, blockNumber uint64) uint64 {
+	if head < blockNumber {
+		return 0
+	}
+	return head - blockNumber + 1
+}
+
+// confirmationPolicy returns the policy in effect
+func (wt *WalletTracker) confirmationPolicy() ConfirmationPolicy {
+	return *wt.confirmations.Load()
+}
+
+// setConfirmations changes the policy of transactions detected from now
+// on. Pending transactions are released at the new depth, or on the next
+// head if the policy doesn't hold transactions anymore.
+func (wt *WalletTracker) setConfirmations(policy ConfirmationPolicy) {
+	wt.confirmations.Store(&policy)
+}
+
+// watchHeads publishes pending transactions once they are deep enough. It
+// runs even while nothing is held, as the policy may change at runtime.
+func (wt *WalletTracker) watchHeads(ctx context.Context) {
+	heads, err := wt.blockchainClient.SubscribeHeads(ctx)
+	if err != nil {
+		wt.logger.Error("Failed to subscribe to new heads", zap.Error(err))
+		return
+	}
+
+	for head := range heads {
+		for _, pending := range wt.pending.release(ctx, head, wt.confirmationPolicy().Confirmations) {
+			wt.queue.enqueue(ctx, domain.QueuedTransaction{
+				WalletAddress: pending.walletAddress,
+				Transaction:   pending.tx,
+				Confirmations: confirmationsAt(head, pending.tx.BlockNumber),
+			})
+		}
+	}
+}
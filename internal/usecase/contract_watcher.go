@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// contractKey identifies one user's watch of a contract
+type contractKey struct {
+	contractAddress domain.WalletAddress
+	userID          domain.UserID
+}
+
+// ContractWatcher follows user-registered contracts and publishes their
+// matching events. Each user's watch keeps its own ABI, so it runs as a
+// separate listener.
+type ContractWatcher struct {
+	source    domain.ContractEventSource
+	publisher domain.Publisher
+	repo      domain.ContractRepository
+	audit     *AuditTrail
+	logger    *zap.Logger
+
+	// Active listeners map: contract and user -> listener context
+	listeners map[contractKey]context.CancelFunc
+	mu        sync.Mutex
+}
+
+func NewContractWatcher(
+	source domain.ContractEventSource,
+	publisher domain.Publisher,
+	repo domain.ContractRepository,
+	audit *AuditTrail,
+	logger *zap.Logger,
+) *ContractWatcher {
+	return &ContractWatcher{
+		source:    source,
+		publisher: publisher,
+		repo:      repo,
+		audit:     audit,
+		logger:    logger,
+		listeners: make(map[contractKey]context.CancelFunc),
+	}
+}
+
+func (cw *ContractWatcher) Start(ctx context.Context) {
+	cw.logger.Info("Starting contract watcher")
+
+	if err := cw.restoreSubscriptions(ctx); err != nil {
+		cw.logger.Error("Failed to restore contract subscriptions", zap.Error(err))
+	}
+
+	<-ctx.Done()
+	cw.logger.Info("Stopping contract watcher")
+	cw.stopAllListeners()
+}
+
+// ListenerCount returns the number of running contract listeners
+func (cw *ContractWatcher) ListenerCount() int {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	return len(cw.listeners)
+}
+
+// Watch starts following the contract for the user, replacing any previous
+// watch of the same contract by that user
+func (cw *ContractWatcher) Watch(ctx context.Context, subscription domain.ContractSubscription) error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	subscription.CreatedAt = time.Now()
+
+	// Subscribing first validates the address and ABI before anything is stored
+	key := contractKey{subscription.ContractAddress, subscription.UserID}
+	previous := cw.listeners[key]
+	if err := cw.startListener(subscription); err != nil {
+		return err
+	}
+
+	if err := cw.repo.AddContractSubscription(ctx, subscription); err != nil {
+		cw.listeners[key]()
+		if previous != nil {
+			cw.listeners[key] = previous
+		} else {
+			delete(cw.listeners, key)
+		}
+		metrics.ActiveListeners.WithLabelValues("contract").Set(float64(len(cw.listeners)))
+		return fmt.Errorf("failed to persist contract subscription: %w", err)
+	}
+
+	if previous != nil {
+		previous()
+	} else {
+		cw.audit.recordSubscription(ctx, domain.AuditSubscriptionAdded, domain.ContractSubscriptionKind,
+			subscription.ContractAddress, subscription.UserID, "")
+	}
+	return nil
+}
+
+// Unwatch stops following the contract for the user
+func (cw *ContractWatcher) Unwatch(
+	ctx context.Context,
+	contractAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	if err := cw.repo.RemoveContractSubscription(ctx, contractAddress, userID); err != nil {
+		return fmt.Errorf("failed to remove contract subscription: %w", err)
+	}
+
+	key := contractKey{contractAddress, userID}
+	if cancel, exists := cw.listeners[key]; exists {
+		cancel()
+		delete(cw.listeners, key)
+		cw.audit.recordSubscription(ctx, domain.AuditSubscriptionRemoved, domain.ContractSubscriptionKind,
+			contractAddress, userID, "")
+	}
+	metrics.ActiveListeners.WithLabelValues("contract").Set(float64(len(cw.listeners)))
+
+	return nil
+}
+
+// restoreSubscriptions loads persisted contract subscriptions and restarts
+// their listeners
+func (cw *ContractWatcher) restoreSubscriptions(ctx context.Context) error {
+	subscriptions, err := cw.repo.GetContractSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		if err := cw.startListener(subscription); err != nil {
+			cw.logger.Error("Failed to restore contract subscription",
+				zap.String("contract", string(subscription.ContractAddress)),
+				zap.Int64("user_id", int64(subscription.UserID)),
+				zap.Error(err),
+			)
+		}
+	}
+
+	cw.logger.Info("Restored contract subscriptions", zap.Int("subscriptions", len(cw.listeners)))
+	return nil
+}
+
+// startListener subscribes to the contract's events and publishes them in
+// the background. Caller must hold cw.mu.
+func (cw *ContractWatcher) startListener(subscription domain.ContractSubscription) error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := cw.source.SubscribeToContract(ctx, subscription)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	cw.listeners[contractKey{subscription.ContractAddress, subscription.UserID}] = cancel
+	go cw.listen(ctx, subscription, events)
+	metrics.ActiveListeners.WithLabelValues("contract").Set(float64(len(cw.listeners)))
+
+	cw.logger.Info("Started listener for contract",
+		zap.String("contract", string(subscription.ContractAddress)),
+		zap.Int64("user_id", int64(subscription.UserID)),
+	)
+	return nil
+}
+
+func (cw *ContractWatcher) listen(
+	ctx context.Context,
+	subscription domain.ContractSubscription,
+	events <-chan domain.ContractEvent,
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			cw.publishEvent(ctx, subscription.UserID, event)
+		}
+	}
+}
+
+func (cw *ContractWatcher) publishEvent(ctx context.Context, userID domain.UserID, event domain.ContractEvent) {
+	notification := domain.ContractEventNotification{
+		Event:       event,
+		Subscribers: []domain.UserID{userID},
+		Timestamp:   time.Now(),
+		Trace:       traceContextFor(ctx),
+	}
+
+	if err := cw.publisher.PublishContractEvent(ctx, notification); err != nil {
+		cw.logger.Error("Failed to publish contract event",
+			zap.String("contract", string(event.ContractAddress)),
+			zap.String("tx_hash", string(event.TxHash)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	cw.logger.Info("Published contract event",
+		zap.String("contract", string(event.ContractAddress)),
+		zap.String("event", event.Event),
+		zap.String("tx_hash", string(event.TxHash)),
+		zap.Int64("user_id", int64(userID)),
+		zap.String("trace_id", traceID(notification.Trace)),
+	)
+}
+
+func (cw *ContractWatcher) stopAllListeners() {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	for _, cancel := range cw.listeners {
+		cancel()
+	}
+	cw.listeners = make(map[contractKey]context.CancelFunc)
+	metrics.ActiveListeners.WithLabelValues("contract").Set(float64(len(cw.listeners)))
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// Dead letters listed or requeued when the command sets no limit
+const defaultDeadLetterLimit = 50
+
+// DeadLetters keeps notifications that couldn't be published and commands
+// that couldn't be handled, for admins to inspect, requeue or purge. A nil
+// queue disables it.
+type DeadLetters struct {
+	queue     domain.DeadLetterQueue
+	publisher domain.Publisher
+	decode    func([]byte) (domain.Command, error)
+	logger    *zap.Logger
+}
+
+// NewDeadLetters returns dead letters requeued by publishing notifications
+// to publisher and decoding commands with decode
+func NewDeadLetters(
+	queue domain.DeadLetterQueue,
+	publisher domain.Publisher,
+	decode func([]byte) (domain.Command, error),
+	logger *zap.Logger,
+) *DeadLetters {
+	dl := &DeadLetters{
+		publisher: publisher,
+		decode:    decode,
+		logger:    logger,
+	}
+	if queue != nil {
+		dl.queue = countingDeadLetterQueue{queue}
+	}
+	return dl
+}
+
+// Queue returns the queue command subscribers dead-letter poison commands
+// to, nil if disabled
+func (dl *DeadLetters) Queue() domain.DeadLetterQueue {
+	if dl.queue == nil {
+		return nil
+	}
+	return dl.queue
+}
+
+// countingDeadLetterQueue counts the letters added to the queue
+type countingDeadLetterQueue struct {
+	domain.DeadLetterQueue
+}
+
+func (q countingDeadLetterQueue) Add(ctx context.Context, letter domain.DeadLetter) error {
+	if err := q.DeadLetterQueue.Add(ctx, letter); err != nil {
+		return err
+	}
+	metrics.DeadLettersTotal.WithLabelValues(string(letter.Kind)).Inc()
+	return nil
+}
+
+// Publisher returns publisher dead-lettering the notifications it fails to
+// publish; the failure is still returned
+func (dl *DeadLetters) Publisher(publisher domain.Publisher) domain.Publisher {
+	return deadLetterPublisher{publisher, dl}
+}
+
+type deadLetterPublisher struct {
+	domain.Publisher
+	deadLetters *DeadLetters
+}
+
+func (p deadLetterPublisher) PublishNotification(ctx context.Context, notification domain.WalletNotification) error {
+	err := p.Publisher.PublishNotification(ctx, notification)
+	if err != nil {
+		p.deadLetters.addNotification(ctx, notification, err)
+	}
+	return err
+}
+
+// addNotification dead-letters a notification that failed to publish for
+// good, and reports whether it was
+func (dl *DeadLetters) addNotification(
+	ctx context.Context,
+	notification domain.WalletNotification,
+	cause error,
+) bool {
+	if dl == nil || dl.queue == nil {
+		return false
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		dl.logger.Error("Failed to marshal dead notification", zap.Error(err))
+		return false
+	}
+	letter := domain.DeadLetter{
+		Kind:      domain.DeadNotification,
+		Payload:   string(payload),
+		Reason:    cause.Error(),
+		CreatedAt: time.Now(),
+	}
+	if err := dl.queue.Add(context.WithoutCancel(ctx), letter); err != nil {
+		dl.logger.Error("Failed to dead-letter notification",
+			zap.String("key", notification.IdempotencyKey),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	dl.logger.Warn("Dead-lettered notification",
+		zap.String("key", notification.IdempotencyKey),
+		zap.Error(cause),
+	)
+	return true
+}
+
+// addInvalidNotification dead-letters an outbox entry that can't be
+// decoded, stored as data, and reports whether it was
+func (dl *DeadLetters) addInvalidNotification(ctx context.Context, data []byte, cause error) bool {
+	if dl == nil || dl.queue == nil {
+		return false
+	}
+
+	if err := dl.queue.Add(context.WithoutCancel(ctx), domain.NewDeadNotification(data, cause)); err != nil {
+		dl.logger.Error("Failed to dead-letter invalid notification", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// List returns the oldest dead letters, up to limit (0 = default), and how
+// many there are
+func (dl *DeadLetters) List(ctx context.Context, limit int) (*domain.DeadLetterPage, error) {
+	if dl.queue == nil {
+		return nil, domain.ErrDeadLettersDisabled
+	}
+	if limit <= 0 {
+		limit = defaultDeadLetterLimit
+	}
+
+	total, err := dl.queue.Len(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+	letters, err := dl.queue.List(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return &domain.DeadLetterPage{Total: total, Letters: letters}, nil
+}
+
+// Requeue publishes the dead notifications among ids again and runs the
+// dead commands through handle, or the oldest limit letters if ids is
+// empty. Letters handled are removed; those failing again stay.
+func (dl *DeadLetters) Requeue(
+	ctx context.Context,
+	ids []string,
+	limit int,
+	handle func(domain.Command) error,
+) (*domain.DeadLetterRemoval, error) {
+	if dl.queue == nil {
+		return nil, domain.ErrDeadLettersDisabled
+	}
+
+	var (
+		letters []domain.DeadLetter
+		err     error
+	)
+	if len(ids) > 0 {
+		letters, err = dl.queue.Get(ctx, ids)
+	} else {
+		if limit <= 0 {
+			limit = defaultDeadLetterLimit
+		}
+		letters, err = dl.queue.List(ctx, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letters: %w", err)
+	}
+
+	result := &domain.DeadLetterRemoval{Removed: []string{}}
+	for _, letter := range letters {
+		if err := dl.requeue(ctx, letter, handle); err != nil {
+			dl.logger.Warn("Failed to requeue dead letter",
+				zap.String("id", letter.ID),
+				zap.String("kind", string(letter.Kind)),
+				zap.Error(err),
+			)
+			result.Failed = append(result.Failed, letter.ID)
+			continue
+		}
+		if _, err := dl.queue.Delete(ctx, []string{letter.ID}); err != nil {
+			return result, fmt.Errorf("failed to remove requeued dead letter: %w", err)
+		}
+		result.Removed = append(result.Removed, letter.ID)
+	}
+
+	dl.logger.Info("Requeued dead letters",
+		zap.Int("requeued", len(result.Removed)),
+		zap.Int("failed", len(result.Failed)),
+	)
+	return result, nil
+}
+
+func (dl *DeadLetters) requeue(ctx context.Context, letter domain.DeadLetter, handle func(domain.Command) error) error {
+	switch letter.Kind {
+	case domain.DeadNotification:
+		var notification domain.WalletNotification
+		if err := json.Unmarshal([]byte(letter.Payload), &notification); err != nil {
+			return fmt.Errorf("invalid dead notification: %w", err)
+		}
+		return dl.publisher.PublishNotification(ctx, notification)
+	case domain.DeadCommand:
+		data, err := letter.Data()
+		if err != nil {
+			return fmt.Errorf("invalid dead command: %w", err)
+		}
+		cmd, err := dl.decode(data)
+		if err != nil {
+			return fmt.Errorf("invalid dead command: %w", err)
+		}
+		return handle(cmd)
+	default:
+		return fmt.Errorf("unknown dead letter kind %q", letter.Kind)
+	}
+}
+
+// Purge removes the dead letters among ids, or all of them if ids is empty
+func (dl *DeadLetters) Purge(ctx context.Context, ids []string) (*domain.DeadLetterRemoval, error) {
+	if dl.queue == nil {
+		return nil, domain.ErrDeadLettersDisabled
+	}
+
+	removed, err := dl.queue.Delete(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge dead letters: %w", err)
+	}
+
+	dl.logger.Warn("Purged dead letters", zap.Int64("purged", removed))
+	if len(ids) == 0 {
+		return &domain.DeadLetterRemoval{Removed: []string{}, Purged: removed}, nil
+	}
+	return &domain.DeadLetterRemoval{Removed: ids, Purged: removed}, nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// notificationDedup makes sure each subscriber gets a notification once,
+// even when a listener reconnects or a backfill overlaps live tracking.
+// It fails open: if the store is unavailable, notifications go out.
+type notificationDedup struct {
+	store  domain.DedupStore
+	ttl    time.Duration // 0 disables deduplication
+	logger *zap.Logger
+}
+
+// idempotencyKey identifies a notification of tx, as the wallet's
+// subscribers see it, by the log indices of the events it reports:
+// wallet:txhash:logindex[,logindex...]:type. Two notifications of the same
+// transaction reporting different events are separate deliveries, as is
+// the early unconfirmed notification from the confirmed one.
+func idempotencyKey(
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+	notificationType domain.NotificationType,
+	unconfirmed bool,
+) string {
+	key := fmt.Sprintf("%s:%s:%s:%s",
+		strings.ToLower(string(walletAddress)),
+		strings.ToLower(string(tx.Hash)),
+		eventLogIndices(tx, notificationType),
+		notificationType,
+	)
+	if unconfirmed {
+		key += ":unconfirmed"
+	}
+	return key
+}
+
+// eventLogIndices lists the log indices of the events of tx a notification
+// of notificationType reports, "-" if there are none. Native transfers
+// have no log and count as -1.
+func eventLogIndices(tx domain.Transaction, notificationType domain.NotificationType) string {
+	var indices []string
+	switch notificationType {
+	case domain.ApprovalNotification:
+		for _, approval := range tx.Approvals {
+			indices = append(indices, strconv.Itoa(approval.LogIndex))
+		}
+	case domain.SwapNotification:
+		for _, swap := range tx.Swaps {
+			indices = append(indices, strconv.Itoa(swap.LogIndex))
+		}
+	case domain.BridgeInNotification:
+		for _, bridge := range bridgesInDirection(tx.Bridges, domain.BridgeIn) {
+			indices = append(indices, strconv.Itoa(bridge.LogIndex))
+		}
+	case domain.BridgeOutNotification:
+		for _, bridge := range bridgesInDirection(tx.Bridges, domain.BridgeOut) {
+			indices = append(indices, strconv.Itoa(bridge.LogIndex))
+		}
+	default:
+		for _, transfer := range tx.Transfers {
+			indices = append(indices, strconv.Itoa(transfer.LogIndex))
+		}
+	}
+	if len(indices) == 0 {
+		return "-"
+	}
+	return strings.Join(indices, ",")
+}
+
+func subscriberKey(userID domain.UserID, key string) string {
+	return fmt.Sprintf("%d:%s", userID, key)
+}
+
+// claim returns the subscribers that haven't received the notification yet
+// and marks it delivered for them
+func (d *notificationDedup) claim(
+	ctx context.Context,
+	key string,
+	subscribers []domain.UserID,
+) []domain.UserID {
+	if d.ttl <= 0 {
+		return subscribers
+	}
+
+	keys := make([]string, len(subscribers))
+	for i, userID := range subscribers {
+		keys[i] = subscriberKey(userID, key)
+	}
+
+	claimed, err := d.store.Claim(ctx, keys, d.ttl)
+	if err != nil {
+		d.logger.Warn("Failed to check notification duplicates, publishing anyway",
+			zap.String("key", key),
+			zap.Error(err))
+		return subscribers
+	}
+
+	fresh := make([]domain.UserID, 0, len(subscribers))
+	for i, userID := range subscribers {
+		if claimed[i] {
+			fresh = append(fresh, userID)
+		}
+	}
+	return fresh
+}
+
+// release forgets deliveries so they can be made again, e.g. after a
+// failed publish
+func (d *notificationDedup) release(ctx context.Context, key string, subscribers []domain.UserID) {
+	keys := make([]string, len(subscribers))
+	for i, userID := range subscribers {
+		keys[i] = subscriberKey(userID, key)
+	}
+	d.releaseKeys(ctx, keys)
+}
+
+// forget releases the delivered notifications, so they are delivered again
+// if their transactions are included in a new block after a reorg
+func (d *notificationDedup) forget(
+	ctx context.Context,
+	deliveries []delivery,
+	subscribers []domain.UserID,
+) {
+	var keys []string
+	for _, delivery := range deliveries {
+		for _, key := range delivery.keys {
+			for _, userID := range subscribers {
+				keys = append(keys, subscriberKey(userID, key))
+			}
+		}
+	}
+	d.releaseKeys(ctx, keys)
+}
+
+func (d *notificationDedup) releaseKeys(ctx context.Context, keys []string) {
+	if d.ttl <= 0 || len(keys) == 0 {
+		return
+	}
+
+	if err := d.store.Release(ctx, keys); err != nil {
+		d.logger.Warn("Failed to release notification keys", zap.Error(err))
+	}
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Digest windows users may choose
+const (
+	minDigestWindow = time.Minute
+	maxDigestWindow = 24 * time.Hour
+)
+
+// How often due digests are looked for
+const digestFlushInterval = 10 * time.Second
+
+// digestBuffer collects the transactions of subscriptions in digest mode
+// until their window ends. Digests are kept in memory only, so a restart
+// drops open windows.
+type digestBuffer struct {
+	entries map[subscriptionKey]*digestEntry
+	mu      sync.Mutex
+}
+
+type digestEntry struct {
+	window time.Duration
+	digest domain.Digest
+	tokens map[string]*digestTotal
+	order  []string // Token keys in first-seen order
+}
+
+type digestTotal struct {
+	domain.DigestTokenTotal
+	inUSD, outUSD float64
+}
+
+func newDigestBuffer() *digestBuffer {
+	return &digestBuffer{entries: make(map[subscriptionKey]*digestEntry)}
+}
+
+// add counts tx in the digest of the subscription, opening one if needed
+func (b *digestBuffer) add(key subscriptionKey, window time.Duration, tx domain.Transaction) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, ok := b.entries[key]
+	if !ok {
+		entry = &digestEntry{
+			window: window,
+			digest: domain.Digest{From: time.Now()},
+			tokens: make(map[string]*digestTotal),
+		}
+		b.entries[key] = entry
+	}
+
+	entry.digest.Transactions++
+	if tx.Status == domain.TxReverted {
+		entry.digest.Failed++
+	}
+
+	for _, transfer := range tx.Transfers {
+		tokenKey := strings.ToLower(transfer.TokenAddress)
+		total, ok := entry.tokens[tokenKey]
+		if !ok {
+			total = &digestTotal{DigestTokenTotal: domain.DigestTokenTotal{
+				TokenAddress:  transfer.TokenAddress,
+				TokenSymbol:   transfer.TokenSymbol,
+				TokenStandard: transfer.TokenStandard,
+			}}
+			entry.tokens[tokenKey] = total
+			entry.order = append(entry.order, tokenKey)
+		}
+
+		usd, _ := strconv.ParseFloat(transfer.ValueUSD, 64)
+		if strings.EqualFold(string(transfer.To), string(key.walletAddress)) {
+			total.Incoming++
+			total.ValueIn = addValue(total.ValueIn, transfer)
+			total.inUSD += usd
+		}
+		if strings.EqualFold(string(transfer.From), string(key.walletAddress)) {
+			total.Outgoing++
+			total.ValueOut = addValue(total.ValueOut, transfer)
+			total.outUSD += usd
+		}
+	}
+}
+
+// due removes and returns the digests whose window has ended at now, or
+// every open digest if all is set
+func (b *digestBuffer) due(now time.Time, all bool) map[subscriptionKey]domain.Digest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	due := make(map[subscriptionKey]domain.Digest)
+	for key, entry := range b.entries {
+		if !all && now.Sub(entry.digest.From) < entry.window {
+			continue
+		}
+		delete(b.entries, key)
+		due[key] = entry.finish(now)
+	}
+	return due
+}
+
+// drop discards the open digest of the subscription
+func (b *digestBuffer) drop(key subscriptionKey) {
+	b.mu.Lock()
+	delete(b.entries, key)
+	b.mu.Unlock()
+}
+
+// finish closes the digest at now with its token totals in first-seen order
+func (e *digestEntry) finish(now time.Time) domain.Digest {
+	digest := e.digest
+	digest.To = now
+	digest.Tokens = make([]domain.DigestTokenTotal, 0, len(e.order))
+	for _, tokenKey := range e.order {
+		total := e.tokens[tokenKey]
+		token := total.DigestTokenTotal
+		if total.inUSD > 0 {
+			token.ValueInUSD = strconv.FormatFloat(total.inUSD, 'f', 2, 64)
+		}
+		if total.outUSD > 0 {
+			token.ValueOutUSD = strconv.FormatFloat(total.outUSD, 'f', 2, 64)
+		}
+		digest.Tokens = append(digest.Tokens, token)
+	}
+	return digest
+}
+
+func addValue(sum *big.Int, transfer domain.Transfer) *big.Int {
+	if sum == nil {
+		sum = new(big.Int)
+	}
+	if transfer.Value != nil {
+		sum.Add(sum, transfer.Value)
+	}
+	return sum
+}
+
+// SetDigest batches the user's notifications about the wallet into one
+// digest per window. A zero window turns digest mode off; a digest already
+// open is still sent when its window ends.
+func (wt *WalletTracker) SetDigest(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+	window time.Duration,
+) (*domain.WalletSubscription, error) {
+	if window != 0 && (window < minDigestWindow || window > maxDigestWindow) {
+		return nil, fmt.Errorf("%w: digest window must be between %s and %s",
+			domain.ErrInvalidDuration, minDigestWindow, maxDigestWindow)
+	}
+
+	wt.mu.Lock()
+	subscription, err := wt.storedSubscription(ctx, walletAddress, userID)
+	if err != nil {
+		wt.mu.Unlock()
+		return nil, err
+	}
+
+	subscription.DigestWindow = window
+	if err := wt.repo.AddSubscription(ctx, subscription); err != nil {
+		wt.mu.Unlock()
+		return nil, fmt.Errorf("failed to persist subscription: %w", err)
+	}
+	started := wt.subscribe(subscription)
+	wt.mu.Unlock()
+
+	if started {
+		wt.trackArchive(ctx, walletAddress)
+	}
+
+	wt.logger.Info("Changed digest mode",
+		zap.String("wallet", string(walletAddress)),
+		zap.Int64("user_id", int64(userID)),
+		zap.Duration("window", window),
+	)
+	return &subscription, nil
+}
+
+// collectDigests adds tx to the digests of recipients in digest mode and
+// returns the recipients to notify right away. Digests only count confirmed
+// transactions, so unconfirmed ones are left out.
+func (wt *WalletTracker) collectDigests(
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+	recipients []domain.UserID,
+	confirmed bool,
+) []domain.UserID {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	if len(wt.digestWindows) == 0 {
+		return recipients
+	}
+
+	immediate := make([]domain.UserID, 0, len(recipients))
+	for _, userID := range recipients {
+		key := subscriptionKey{walletAddress, userID}
+		window, ok := wt.digestWindows[key]
+		if !ok {
+			immediate = append(immediate, userID)
+			continue
+		}
+		if confirmed {
+			wt.digests.add(key, window, tx)
+		}
+	}
+	return immediate
+}
+
+// flushDigests publishes digests as their windows end
+func (wt *WalletTracker) flushDigests(ctx context.Context) {
+	ticker := time.NewTicker(digestFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Open digests would be lost, send what they have so far
+			drain, cancel := context.WithTimeout(context.WithoutCancel(ctx), wt.queue.drainTimeout)
+			defer cancel()
+			for key, digest := range wt.digests.due(time.Now(), true) {
+				wt.publishDigest(drain, key, digest)
+			}
+			return
+		case now := <-ticker.C:
+			for key, digest := range wt.digests.due(now, false) {
+				wt.publishDigest(ctx, key, digest)
+			}
+		}
+	}
+}
+
+func (wt *WalletTracker) publishDigest(ctx context.Context, key subscriptionKey, digest domain.Digest) {
+	subscribers := []domain.UserID{key.userID}
+	notification := domain.WalletNotification{
+		Type:          domain.DigestNotification,
+		SchemaVersion: domain.CurrentSchemaVersion,
+		WalletAddress: key.walletAddress,
+		Subscribers:   subscribers,
+		WalletLabels:  wt.walletLabelsFor(key.walletAddress, subscribers),
+		WalletGroups:  wt.groups.groupsFor(key.walletAddress, subscribers),
+		Timestamp:     time.Now(),
+		Trace:         traceContextFor(ctx),
+		IdempotencyKey: fmt.Sprintf("%s:digest:%d:%d",
+			strings.ToLower(string(key.walletAddress)), key.userID, digest.From.UnixNano()),
+		Digest: &digest,
+	}
+
+	if err := wt.publisher.PublishNotification(ctx, notification); err != nil {
+		wt.logger.Error("Failed to publish digest",
+			zap.String("wallet", string(key.walletAddress)),
+			zap.Int64("user_id", int64(key.userID)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	wt.logger.Debug("Published digest",
+		zap.String("wallet", string(key.walletAddress)),
+		zap.Int64("user_id", int64(key.userID)),
+		zap.Int("transactions", digest.Transactions),
+	)
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// FirehosePublisher is a transfer sink publishing every transfer of every
+// processed block on the firehose channel, one message per block, whoever
+// tracks the addresses involved. Blocks wait for their message to be
+// published, so a slow transport slows down block processing. A block that
+// can't be published is logged and skipped.
+type FirehosePublisher struct {
+	publisher domain.Publisher
+	logger    *zap.Logger
+}
+
+func NewFirehosePublisher(publisher domain.Publisher, logger *zap.Logger) *FirehosePublisher {
+	return &FirehosePublisher{
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Receive publishes records, which come a block at a time
+func (fp *FirehosePublisher) Receive(ctx context.Context, records []domain.TransferRecord) {
+	for len(records) > 0 {
+		n := 1
+		for n < len(records) && records[n].BlockHash == records[0].BlockHash {
+			n++
+		}
+
+		block := domain.FirehoseBlock{
+			BlockNumber: records[0].BlockNumber,
+			BlockHash:   records[0].BlockHash,
+			BlockTime:   records[0].BlockTime,
+			Transfers:   make([]domain.Transfer, 0, n),
+			Timestamp:   time.Now(),
+			Trace:       traceContextFor(ctx),
+		}
+		for _, record := range records[:n] {
+			block.Transfers = append(block.Transfers, record.Transfer)
+		}
+		records = records[n:]
+
+		if err := fp.publisher.PublishFirehose(ctx, block); err != nil {
+			fp.logger.Error("Failed to publish block transfers to the firehose",
+				zap.Uint64("block", block.BlockNumber),
+				zap.Int("transfers", len(block.Transfers)),
+				zap.Error(err),
+			)
+		}
+	}
+}
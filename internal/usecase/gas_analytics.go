@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// GasAnalytics accumulates fees paid by watched wallets, in total and per
+// day for the last domain.GasUsageRetention
+type GasAnalytics struct {
+	repo   domain.GasUsageRepository
+	logger *zap.Logger
+}
+
+func NewGasAnalytics(repo domain.GasUsageRepository, logger *zap.Logger) *GasAnalytics {
+	return &GasAnalytics{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record adds the fee of tx to the wallet's totals if the wallet sent it
+func (ga *GasAnalytics) Record(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+) {
+	// Only the fee payer pays for gas, which excludes sponsored transactions
+	if !strings.EqualFold(string(tx.FeePayer), string(walletAddress)) || tx.Fee == nil {
+		return
+	}
+
+	usage, err := ga.repo.AddGasUsage(ctx, walletAddress, tx.GasUsed, tx.Fee, tx.Timestamp)
+	if err != nil {
+		ga.logger.Error("Failed to record gas usage",
+			zap.String("wallet", string(walletAddress)),
+			zap.String("tx_hash", string(tx.Hash)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	ga.logger.Debug("Recorded gas usage",
+		zap.String("wallet", string(walletAddress)),
+		zap.Uint64("tx_count", usage.TxCount),
+		zap.String("fees_paid", usage.FeesPaid.String()),
+	)
+}
+
+// GetUsage returns the accumulated gas usage for each of the given wallets,
+// over the last window if set. A window reaches back to the start of the
+// UTC day it begins in.
+func (ga *GasAnalytics) GetUsage(
+	ctx context.Context,
+	wallets []domain.WalletAddress,
+	window time.Duration,
+) ([]domain.GasUsage, error) {
+	if window < 0 || window > domain.GasUsageRetention {
+		return nil, fmt.Errorf("%w: window must be positive and at most %s",
+			domain.ErrInvalidDuration, domain.GasUsageRetention)
+	}
+
+	since := time.Now().Add(-window)
+	usages := make([]domain.GasUsage, 0, len(wallets))
+	for _, walletAddress := range wallets {
+		var (
+			usage *domain.GasUsage
+			err   error
+		)
+		if window > 0 {
+			usage, err = ga.repo.GetGasUsageSince(ctx, walletAddress, since)
+		} else {
+			usage, err = ga.repo.GetGasUsage(ctx, walletAddress)
+		}
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, *usage)
+	}
+
+	return usages, nil
+}
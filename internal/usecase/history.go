@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// HistoryService pages through the past transactions of any address. Ranges
+// the archive covers are read from it, others are scanned on chain.
+type HistoryService struct {
+	blockchainClient domain.BlockchainClient
+	labels           *LabelRegistry
+	archive          *TransactionArchive
+	maxBlocks        uint64 // Widest block range scanned per request
+	logger           *zap.Logger
+}
+
+func NewHistoryService(
+	blockchainClient domain.BlockchainClient,
+	labels *LabelRegistry,
+	archive *TransactionArchive,
+	maxBlocks uint64,
+	logger *zap.Logger,
+) *HistoryService {
+	return &HistoryService{
+		blockchainClient: blockchainClient,
+		labels:           labels,
+		archive:          archive,
+		maxBlocks:        max(maxBlocks, 1),
+		logger:           logger,
+	}
+}
+
+// GetHistory returns up to limit transactions of address in [fromBlock,
+// toBlock]. A zero toBlock means the latest block and a zero fromBlock the
+// most recent range the service scans per request; wider ranges are cut and
+// continue on the next page.
+func (hs *HistoryService) GetHistory(
+	ctx context.Context,
+	address domain.WalletAddress,
+	fromBlock uint64,
+	toBlock uint64,
+	limit int,
+) (*domain.TransactionHistory, error) {
+	address, err := NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case limit <= 0:
+		limit = defaultHistoryLimit
+	case limit > maxHistoryLimit:
+		limit = maxHistoryLimit
+	}
+
+	latest, err := hs.blockchainClient.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if toBlock == 0 || toBlock > latest {
+		toBlock = latest
+	}
+	if fromBlock == 0 && toBlock >= hs.maxBlocks {
+		fromBlock = toBlock - hs.maxBlocks + 1
+	}
+	if fromBlock > toBlock {
+		return nil, fmt.Errorf("%w: from_block %d is after to_block %d",
+			domain.ErrInvalidBlockRange, fromBlock, toBlock)
+	}
+
+	// Stored history needs no scan, otherwise cap the scan so a single
+	// request can't walk the whole chain
+	scanTo := toBlock
+	txs, stored := hs.archive.history(ctx, address, fromBlock, toBlock, limit)
+	if !stored {
+		scanTo = min(toBlock, fromBlock+hs.maxBlocks-1)
+
+		txs, err = hs.blockchainClient.GetAddressHistory(ctx, address, fromBlock, scanTo, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get address history: %w", err)
+		}
+	}
+
+	for _, tx := range txs {
+		hs.labels.Annotate(tx.Transfers)
+		hs.labels.AnnotateApprovals(tx.Approvals)
+	}
+
+	history := &domain.TransactionHistory{
+		WalletAddress: address,
+		FromBlock:     fromBlock,
+		ToBlock:       scanTo,
+		Transactions:  txs,
+	}
+
+	// Resume after the last returned block if the page filled up, otherwise
+	// after the scanned range if it was cut
+	next := scanTo + 1
+	if len(txs) >= limit {
+		next = txs[len(txs)-1].BlockNumber + 1
+	}
+	if next <= toBlock {
+		history.HasMore = true
+		history.NextFromBlock = next
+	}
+
+	hs.logger.Debug("Loaded address history",
+		zap.String("address", string(address)),
+		zap.Uint64("from_block", fromBlock),
+		zap.Uint64("to_block", scanTo),
+		zap.Int("transactions", len(txs)),
+		zap.Bool("stored", stored),
+	)
+
+	return history, nil
+}
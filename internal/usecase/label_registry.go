@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// LabelRegistry keeps known address labels, such as exchange hot wallets,
+// bridges and routers, in memory. Labels shipped in a file are read-only;
+// labels managed through Redis take precedence over them.
+type LabelRegistry struct {
+	repo   domain.LabelRepository
+	logger *zap.Logger
+
+	// Labels map: lowercase address -> label
+	labels map[domain.WalletAddress]string
+	// Labels from the labels file: lowercase address -> label
+	known map[domain.WalletAddress]string
+	mu    sync.RWMutex
+}
+
+func NewLabelRegistry(repo domain.LabelRepository, logger *zap.Logger) *LabelRegistry {
+	return &LabelRegistry{
+		repo:   repo,
+		logger: logger,
+		labels: make(map[domain.WalletAddress]string),
+		known:  make(map[domain.WalletAddress]string),
+	}
+}
+
+// LoadFile reads read-only labels from a CSV or JSON file, picked by its
+// extension, in the formats accepted by Import
+func (lr *LabelRegistry) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open labels file: %w", err)
+	}
+	defer file.Close()
+
+	var labels []domain.AddressLabel
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		labels, err = ParseLabelsCSV(file)
+	} else {
+		labels, err = ParseLabelsJSON(file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse labels file: %w", err)
+	}
+
+	known := make(map[domain.WalletAddress]string, len(labels))
+	for i, label := range labels {
+		if !common.IsHexAddress(string(label.Address)) {
+			return fmt.Errorf("labels file entry %d (%q): %w", i+1, label.Address, domain.ErrInvalidAddress)
+		}
+		if name := strings.TrimSpace(label.Label); name != "" {
+			known[labelKey(label.Address)] = name
+		}
+	}
+
+	lr.mu.Lock()
+	lr.known = known
+	lr.mu.Unlock()
+
+	lr.logger.Info("Loaded address labels file", zap.String("path", path), zap.Int("count", len(known)))
+	return nil
+}
+
+// Load reads all persisted labels into memory
+func (lr *LabelRegistry) Load(ctx context.Context) error {
+	labels, err := lr.repo.GetAllLabels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load labels: %w", err)
+	}
+
+	lr.mu.Lock()
+	for _, label := range labels {
+		lr.labels[labelKey(label.Address)] = label.Label
+	}
+	lr.mu.Unlock()
+
+	lr.logger.Info("Loaded address labels", zap.Int("count", len(labels)))
+	return nil
+}
+
+// Import validates and stores the given labels, returning the number imported
+func (lr *LabelRegistry) Import(ctx context.Context, labels []domain.AddressLabel) (int, error) {
+	// Deduplicate so the last entry for an address wins
+	unique := make(map[domain.WalletAddress]string, len(labels))
+	for i, label := range labels {
+		if !common.IsHexAddress(string(label.Address)) {
+			return 0, fmt.Errorf("entry %d (%q): %w", i+1, label.Address, domain.ErrInvalidAddress)
+		}
+
+		name := strings.TrimSpace(label.Label)
+		if name == "" {
+			continue
+		}
+		unique[labelKey(label.Address)] = name
+	}
+
+	normalized := make([]domain.AddressLabel, 0, len(unique))
+	for address, name := range unique {
+		normalized = append(normalized, domain.AddressLabel{Address: address, Label: name})
+	}
+
+	if err := lr.repo.SetLabels(ctx, normalized); err != nil {
+		return 0, fmt.Errorf("failed to store labels: %w", err)
+	}
+
+	lr.mu.Lock()
+	for address, name := range unique {
+		lr.labels[address] = name
+	}
+	lr.mu.Unlock()
+
+	lr.logger.Info("Imported address labels", zap.Int("count", len(normalized)))
+	return len(normalized), nil
+}
+
+// Remove deletes labels managed through Redis; labels from the labels
+// file stay. It returns the number removed.
+func (lr *LabelRegistry) Remove(ctx context.Context, addresses []domain.WalletAddress) (int, error) {
+	keys := make([]domain.WalletAddress, 0, len(addresses))
+	for _, address := range addresses {
+		keys = append(keys, labelKey(address))
+	}
+
+	if err := lr.repo.DeleteLabels(ctx, keys); err != nil {
+		return 0, fmt.Errorf("failed to delete labels: %w", err)
+	}
+
+	lr.mu.Lock()
+	removed := 0
+	for _, key := range keys {
+		if _, ok := lr.labels[key]; ok {
+			delete(lr.labels, key)
+			removed++
+		}
+	}
+	lr.mu.Unlock()
+
+	lr.logger.Info("Removed address labels", zap.Int("count", removed))
+	return removed, nil
+}
+
+// Lookup returns the label for address or an empty string if unknown
+func (lr *LabelRegistry) Lookup(address domain.WalletAddress) string {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	key := labelKey(address)
+	if label, ok := lr.labels[key]; ok {
+		return label
+	}
+	return lr.known[key]
+}
+
+// Annotate fills in counterparty labels on the given transfers
+func (lr *LabelRegistry) Annotate(transfers []domain.Transfer) {
+	for i := range transfers {
+		transfers[i].FromLabel = lr.Lookup(transfers[i].From)
+		transfers[i].ToLabel = lr.Lookup(transfers[i].To)
+	}
+}
+
+// AnnotateApprovals fills in spender labels on the given approvals
+func (lr *LabelRegistry) AnnotateApprovals(approvals []domain.Approval) {
+	for i := range approvals {
+		approvals[i].SpenderLabel = lr.Lookup(approvals[i].Spender)
+	}
+}
+
+// ParseLabelsCSV parses "address,label" rows; a header row is skipped
+func ParseLabelsCSV(r io.Reader) ([]domain.AddressLabel, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var labels []domain.AddressLabel
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		if len(record) < 2 {
+			return nil, fmt.Errorf("row %d: expected address and label columns", row)
+		}
+
+		address := strings.TrimSpace(record[0])
+		if row == 1 && !common.IsHexAddress(address) {
+			continue // Header row
+		}
+
+		labels = append(labels, domain.AddressLabel{
+			Address: domain.WalletAddress(address),
+			Label:   record[1],
+		})
+	}
+
+	return labels, nil
+}
+
+// ParseLabelsJSON accepts either an {"address": "label"} object
+// or an array of {"address": ..., "label": ...} entries
+func ParseLabelsJSON(r io.Reader) ([]domain.AddressLabel, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []domain.AddressLabel
+	if err := json.Unmarshal(data, &list); err == nil {
+		return list, nil
+	}
+
+	var mapping map[domain.WalletAddress]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse labels JSON: %w", err)
+	}
+
+	return LabelsFromMap(mapping), nil
+}
+
+// LabelsFromMap converts an address -> label mapping into a label list
+func LabelsFromMap(mapping map[domain.WalletAddress]string) []domain.AddressLabel {
+	labels := make([]domain.AddressLabel, 0, len(mapping))
+	for address, label := range mapping {
+		labels = append(labels, domain.AddressLabel{Address: address, Label: label})
+	}
+	return labels
+}
+
+// labelKey is the lowercase, 0x-prefixed key of address
+func labelKey(address domain.WalletAddress) domain.WalletAddress {
+	key := strings.ToLower(string(address))
+	if !strings.HasPrefix(key, "0x") {
+		key = "0x" + key
+	}
+	return domain.WalletAddress(key)
+}
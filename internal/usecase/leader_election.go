@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// errNotLeader rejects checkpoints saved by an instance that isn't leading
+var errNotLeader = errors.New("not the leader")
+
+// LeaderElector elects the one active instance among those sharing a
+// Redis; the others stand by until its lease expires or it resigns. The
+// leader gives its lease up shortly before it could have expired when it
+// can't renew it, so two instances never lead at once.
+type LeaderElector struct {
+	lock       domain.LeaderLock
+	instanceID string
+	interval   time.Duration
+	ttl        time.Duration
+	leader     atomic.Bool
+	elected    chan struct{}
+	lost       chan struct{}
+	logger     *zap.Logger
+}
+
+// NewLeaderElector returns an elector for instanceID, which must be unique
+// among the instances. It tries to take or renew a lease of ttl every
+// interval.
+func NewLeaderElector(
+	lock domain.LeaderLock,
+	instanceID string,
+	interval time.Duration,
+	ttl time.Duration,
+	logger *zap.Logger,
+) *LeaderElector {
+	return &LeaderElector{
+		lock:       lock,
+		instanceID: instanceID,
+		interval:   interval,
+		ttl:        ttl,
+		elected:    make(chan struct{}),
+		lost:       make(chan struct{}),
+		logger:     logger,
+	}
+}
+
+// Start campaigns until elected, then renews the lease until ctx is done
+// or the lease is lost
+func (le *LeaderElector) Start(ctx context.Context) {
+	ticker := time.NewTicker(le.interval)
+	defer ticker.Stop()
+
+	var renewed time.Time
+	for {
+		attempt := time.Now()
+		if !le.leader.Load() {
+			acquired, err := le.lock.Acquire(ctx, le.instanceID, le.ttl)
+			if err != nil && ctx.Err() == nil {
+				le.logger.Warn("Failed to campaign for leadership", zap.Error(err))
+			}
+			if acquired {
+				renewed = attempt
+				le.leader.Store(true)
+				metrics.Leader.Set(1)
+				close(le.elected)
+				le.logger.Info("Elected leader", zap.String("instance_id", le.instanceID))
+			}
+		} else {
+			held, err := le.lock.Renew(ctx, le.instanceID, le.ttl)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err == nil && held:
+				renewed = attempt
+			case err == nil || time.Since(renewed) >= le.ttl-le.interval:
+				// Taken by another instance, or about to expire before
+				// the next attempt
+				le.leader.Store(false)
+				metrics.Leader.Set(0)
+				close(le.lost)
+				le.logger.Error("Lost leadership", zap.Error(err))
+				return
+			default:
+				le.logger.Warn("Failed to renew leader lease", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Elected returns a channel closed when this instance becomes the leader
+func (le *LeaderElector) Elected() <-chan struct{} {
+	return le.elected
+}
+
+// Lost returns a channel closed when this instance stops being the leader
+// without resigning
+func (le *LeaderElector) Lost() <-chan struct{} {
+	return le.lost
+}
+
+// IsLeader reports whether this instance leads
+func (le *LeaderElector) IsLeader() bool {
+	return le.leader.Load()
+}
+
+// Resign gives the lease up so a standby takes over right away. Start
+// must have returned.
+func (le *LeaderElector) Resign(ctx context.Context) {
+	if !le.leader.Swap(false) {
+		return
+	}
+	metrics.Leader.Set(0)
+
+	if err := le.lock.Release(ctx, le.instanceID); err != nil {
+		le.logger.Error("Failed to release leader lease", zap.Error(err))
+		return
+	}
+	le.logger.Info("Resigned leadership")
+}
+
+// FenceCheckpoints returns store saving checkpoints only while this
+// instance leads, so a deposed leader can't move back the checkpoint its
+// successor resumes from
+func (le *LeaderElector) FenceCheckpoints(store domain.CheckpointStore) domain.CheckpointStore {
+	return leaderCheckpoints{store, le}
+}
+
+type leaderCheckpoints struct {
+	domain.CheckpointStore
+	leader *LeaderElector
+}
+
+func (c leaderCheckpoints) SaveCheckpoint(ctx context.Context, blockNumber uint64) error {
+	if !c.leader.IsLeader() {
+		return errNotLeader
+	}
+	return c.CheckpointStore.SaveCheckpoint(ctx, blockNumber)
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"fmt"
+	"runtime/metrics"
+)
+
+// Policies applied when the listener guard limits are reached
+const (
+	RejectPolicy   = "reject"
+	EvictLRUPolicy = "evict_lru"
+)
+
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// ListenerGuard caps the number of tracked wallets and the heap held by
+// caches and queues so overload is handled predictably
+type ListenerGuard struct {
+	maxWallets   int
+	maxHeapBytes uint64
+	policy       string
+}
+
+// NewListenerGuard creates a guard; zero limits disable the respective check
+func NewListenerGuard(maxWallets, maxMemoryMB int, policy string) *ListenerGuard {
+	if policy != EvictLRUPolicy {
+		policy = RejectPolicy
+	}
+
+	return &ListenerGuard{
+		maxWallets:   maxWallets,
+		maxHeapBytes: uint64(maxMemoryMB) << 20,
+		policy:       policy,
+	}
+}
+
+// Evicts reports whether limits are enforced by evicting old subscriptions
+func (g *ListenerGuard) Evicts() bool {
+	return g.policy == EvictLRUPolicy
+}
+
+// Check returns a reason if tracking one more wallet would exceed a limit
+func (g *ListenerGuard) Check(trackedWallets int) (string, bool) {
+	if g.maxWallets > 0 && trackedWallets >= g.maxWallets {
+		return fmt.Sprintf("tracked wallet limit of %d reached", g.maxWallets), true
+	}
+
+	if g.maxHeapBytes > 0 {
+		if heap := heapObjectBytes(); heap >= g.maxHeapBytes {
+			return fmt.Sprintf("memory limit of %d MB reached", g.maxHeapBytes>>20), true
+		}
+	}
+
+	return "", false
+}
+
+func heapObjectBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Delays between restarts of a listener that keeps dying
+	listenerRestartMin = time.Second
+	listenerRestartMax = time.Minute
+
+	// A listener that ran this long is considered healthy again
+	listenerHealthyAfter = time.Minute
+
+	// Listeners down this long make the tracker unready
+	listenerDownGrace = 2 * time.Minute
+)
+
+// superviseListener runs the wallet listener until ctx is done, restarting
+// it with backoff whenever it dies
+func (wt *WalletTracker) superviseListener(ctx context.Context, walletAddress domain.WalletAddress) {
+	retry := backoff.New(listenerRestartMin, listenerRestartMax)
+
+	for {
+		started := time.Now()
+		err := wt.listenWhileOwned(ctx, walletAddress)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) >= listenerHealthyAfter {
+			retry.Reset()
+		}
+		wt.markListenerDown(walletAddress)
+
+		delay := retry.Next()
+		wt.logger.Warn("Wallet listener died, restarting",
+			zap.String("wallet", string(walletAddress)),
+			zap.Int("attempt", retry.Attempt()),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		metrics.ListenerRestartsTotal.Inc()
+		wt.markListenerUp(walletAddress)
+	}
+}
+
+func (wt *WalletTracker) markListenerDown(walletAddress domain.WalletAddress) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	// The wallet may have been removed while its listener was dying
+	if _, exists := wt.listeners[walletAddress]; !exists {
+		return
+	}
+	if _, down := wt.downListeners[walletAddress]; !down {
+		wt.downListeners[walletAddress] = time.Now()
+	}
+	metrics.ListenersDown.Set(float64(len(wt.downListeners)))
+}
+
+func (wt *WalletTracker) markListenerUp(walletAddress domain.WalletAddress) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	delete(wt.downListeners, walletAddress)
+	metrics.ListenersDown.Set(float64(len(wt.downListeners)))
+}
+
+// ListenerHealth reports listeners that are down, and whether any has been
+// down long enough to consider the tracker unready
+func (wt *WalletTracker) ListenerHealth() domain.ListenerHealth {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	health := domain.ListenerHealth{
+		Active: len(wt.listeners) - len(wt.downListeners),
+		Down:   make([]domain.WalletAddress, 0, len(wt.downListeners)),
+		Ready:  true,
+	}
+	for walletAddress, since := range wt.downListeners {
+		health.Down = append(health.Down, walletAddress)
+		if time.Since(since) > listenerDownGrace {
+			health.Ready = false
+		}
+	}
+
+	return health
+}
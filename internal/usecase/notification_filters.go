@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+)
+
+// Decimals of native XPL
+const nativeDecimals = 18
+
+// normalizeNotificationFilter validates filter and checksums its tokens
+func normalizeNotificationFilter(filter *domain.NotificationFilter) error {
+	switch filter.Direction {
+	case domain.AnyDirection, domain.Incoming, domain.Outgoing:
+	default:
+		return fmt.Errorf("%w: unknown direction %q", domain.ErrInvalidFilter, filter.Direction)
+	}
+
+	for i, token := range filter.Tokens {
+		address, err := NormalizeAddress(domain.WalletAddress(token))
+		if err != nil {
+			return err
+		}
+		filter.Tokens[i] = string(address)
+	}
+
+	if filter.MinNativeAmount != "" {
+		if _, err := parseNativeAmount(filter.MinNativeAmount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyNotificationFilter returns tx with only the transfers and approvals
+// the filter lets through. A nil filter lets everything through.
+func applyNotificationFilter(
+	filter *domain.NotificationFilter,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+) domain.Transaction {
+	if filter == nil {
+		return tx
+	}
+
+	// Validated when the subscription was added
+	minNative, _ := parseNativeAmount(filter.MinNativeAmount)
+
+	transfers := make([]domain.Transfer, 0, len(tx.Transfers))
+	for _, transfer := range tx.Transfers {
+		if !wantsDirection(filter, walletAddress, transfer) || !wantsToken(filter, transfer.TokenAddress) {
+			continue
+		}
+		if transfer.TokenStandard == domain.NativeToken && minNative != nil &&
+			(transfer.Value == nil || transfer.Value.Cmp(minNative) < 0) {
+			continue
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	// Approvals are always granted by the wallet
+	approvals := make([]domain.Approval, 0, len(tx.Approvals))
+	if filter.Direction != domain.Incoming {
+		for _, approval := range tx.Approvals {
+			if wantsToken(filter, approval.TokenAddress) {
+				approvals = append(approvals, approval)
+			}
+		}
+	}
+
+	tx.Transfers = transfers
+	tx.Approvals = approvals
+	return tx
+}
+
+func wantsDirection(
+	filter *domain.NotificationFilter,
+	walletAddress domain.WalletAddress,
+	transfer domain.Transfer,
+) bool {
+	switch filter.Direction {
+	case domain.Incoming:
+		return strings.EqualFold(string(transfer.To), string(walletAddress))
+	case domain.Outgoing:
+		return strings.EqualFold(string(transfer.From), string(walletAddress))
+	default:
+		return true
+	}
+}
+
+func wantsToken(filter *domain.NotificationFilter, tokenAddress string) bool {
+	return len(filter.Tokens) == 0 || slices.ContainsFunc(filter.Tokens, func(token string) bool {
+		return strings.EqualFold(token, tokenAddress)
+	})
+}
+
+// parseNativeAmount converts an XPL amount to wei; "" gives nil
+func parseNativeAmount(amount string) (*big.Int, error) {
+	if amount == "" {
+		return nil, nil
+	}
+
+	value, ok := new(big.Float).SetPrec(256).SetString(amount)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: invalid native amount %q", domain.ErrInvalidFilter, amount)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(nativeDecimals), nil)
+	wei, _ := value.Mul(value, new(big.Float).SetInt(scale)).Int(nil)
+	return wei, nil
+}
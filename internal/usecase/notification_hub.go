@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// Buffered notifications read per call while replaying
+const replayPageSize = 500
+
+// NotificationHub is a sink fanning wallet notifications out to in-process
+// streams, e.g. WebSocket clients. Each stream only sees its user's share
+// of a notification. With a replay buffer, notifications are kept there too
+// so clients can resume.
+type NotificationHub struct {
+	discardPublisher
+
+	replay  domain.NotificationBuffer
+	buffer  int
+	streams map[domain.UserID]map[*NotificationStream]struct{}
+	closed  bool
+	mu      sync.Mutex
+	logger  *zap.Logger
+}
+
+// NotificationStream receives the notifications of one user. A stream that
+// falls more than the hub's buffer behind is closed with ErrSlowConsumer.
+type NotificationStream struct {
+	hub    *NotificationHub
+	userID domain.UserID
+	ch     chan domain.NotificationEvent
+	err    error
+}
+
+// NewNotificationHub returns a hub whose streams may fall buffer
+// notifications behind. A nil replay buffer disables Replay.
+func NewNotificationHub(
+	replay domain.NotificationBuffer,
+	buffer int,
+	logger *zap.Logger,
+) *NotificationHub {
+	return &NotificationHub{
+		replay:  replay,
+		buffer:  buffer,
+		streams: make(map[domain.UserID]map[*NotificationStream]struct{}),
+		logger:  logger,
+	}
+}
+
+// PublishNotification hands notification to the streams of its subscribers
+func (h *NotificationHub) PublishNotification(ctx context.Context, notification domain.WalletNotification) error {
+	events := make([]domain.NotificationEvent, 0, len(notification.Subscribers))
+	for _, userID := range notification.Subscribers {
+		events = append(events, domain.NotificationEvent{
+			UserID:       userID,
+			Notification: notificationFor(notification, userID),
+		})
+	}
+	h.bufferEvents(ctx, events)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, event := range events {
+		for stream := range h.streams[event.UserID] {
+			select {
+			case stream.ch <- event:
+			default:
+				metrics.NotificationsDroppedTotal.WithLabelValues("slow_stream").Inc()
+				h.logger.Warn("Closing slow notification stream",
+					zap.Int64("user_id", int64(event.UserID)),
+					zap.Int("buffer", h.buffer),
+				)
+				h.remove(stream, domain.ErrSlowConsumer)
+			}
+		}
+	}
+	return nil
+}
+
+// bufferEvents keeps events for replay and sets their IDs. Events that
+// can't be buffered still go to live streams, without an ID.
+func (h *NotificationHub) bufferEvents(ctx context.Context, events []domain.NotificationEvent) {
+	if h.replay == nil || len(events) == 0 {
+		return
+	}
+
+	ids, err := h.replay.Append(ctx, events)
+	if err != nil {
+		h.logger.Warn("Failed to buffer notifications for replay", zap.Error(err))
+		return
+	}
+	for i := range events {
+		events[i].ID = ids[i]
+	}
+}
+
+// Replay returns the buffered notifications of userID after the one with
+// id, oldest first. Subscribe before replaying so nothing published in
+// between is missed; the same event may then arrive both ways.
+func (h *NotificationHub) Replay(ctx context.Context, userID domain.UserID, id string) ([]domain.NotificationEvent, error) {
+	if h.replay == nil {
+		return nil, domain.ErrReplayUnavailable
+	}
+
+	var events []domain.NotificationEvent
+	for {
+		page, err := h.replay.After(ctx, userID, id, replayPageSize)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		if len(page) < replayPageSize {
+			return events, nil
+		}
+		id = page[len(page)-1].ID
+	}
+}
+
+// Subscribe opens a stream of userID's notifications. Callers must Close it.
+// Streams opened after Close are closed already.
+func (h *NotificationHub) Subscribe(userID domain.UserID) *NotificationStream {
+	stream := &NotificationStream{
+		hub:    h,
+		userID: userID,
+		ch:     make(chan domain.NotificationEvent, h.buffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(stream.ch)
+		return stream
+	}
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[*NotificationStream]struct{})
+	}
+	h.streams[userID][stream] = struct{}{}
+	metrics.StreamClients.Inc()
+	return stream
+}
+
+// Close ends all streams, e.g. on shutdown
+func (h *NotificationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, streams := range h.streams {
+		for stream := range streams {
+			h.remove(stream, nil)
+		}
+	}
+}
+
+// remove closes stream with err, reported by Err. Must hold mu.
+func (h *NotificationHub) remove(stream *NotificationStream, err error) {
+	streams, ok := h.streams[stream.userID]
+	if !ok {
+		return
+	}
+	if _, ok := streams[stream]; !ok {
+		return
+	}
+
+	delete(streams, stream)
+	if len(streams) == 0 {
+		delete(h.streams, stream.userID)
+	}
+	stream.err = err
+	close(stream.ch)
+	metrics.StreamClients.Dec()
+}
+
+// C delivers the notifications and is closed when the stream ends
+func (s *NotificationStream) C() <-chan domain.NotificationEvent {
+	return s.ch
+}
+
+// Err explains why the stream ended: ErrSlowConsumer, or nil if the hub
+// closed or the stream was closed by its owner. Only valid after C is closed.
+func (s *NotificationStream) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.err
+}
+
+// Close unsubscribes the stream
+func (s *NotificationStream) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s, nil)
+}
+
+// notificationFor strips notification down to what userID may see: other
+// subscribers and the names they gave the wallet are left out
+func notificationFor(notification domain.WalletNotification, userID domain.UserID) domain.WalletNotification {
+	notification.Subscribers = []domain.UserID{userID}
+	notification.WalletLabels = onlyUser(notification.WalletLabels, userID)
+	notification.WalletGroups = onlyUser(notification.WalletGroups, userID)
+	return notification
+}
+
+func onlyUser[V any](values map[domain.UserID]V, userID domain.UserID) map[domain.UserID]V {
+	value, ok := values[userID]
+	if !ok {
+		return nil
+	}
+	return map[domain.UserID]V{userID: value}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+)
+
+// How long the latest notification latency stays relevant. A quiet tracker
+// isn't reported as slow forever because of one late notification.
+const latencyObservationTTL = time.Minute
+
+// latestLatency remembers the most recent notification latency
+type latestLatency struct {
+	value time.Duration
+	at    time.Time
+	mu    sync.Mutex
+}
+
+func (l *latestLatency) observe(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.value = latency
+	l.at = time.Now()
+}
+
+func (l *latestLatency) get() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.at.IsZero() || time.Since(l.at) > latencyObservationTTL {
+		return 0, false
+	}
+	return l.value, true
+}
+
+// observeLatency records how long after its block a notification about tx
+// was published
+func (wt *WalletTracker) observeLatency(notificationType domain.NotificationType, tx domain.Transaction) {
+	if tx.Timestamp.IsZero() {
+		return
+	}
+
+	latency := max(time.Since(tx.Timestamp), 0)
+	metrics.NotificationLatency.WithLabelValues(string(notificationType)).Observe(latency.Seconds())
+	wt.latency.observe(latency)
+}
+
+// NotificationLatency returns the block-to-publish latency of the latest
+// notification, if one was published within the last minute
+func (wt *WalletTracker) NotificationLatency() (time.Duration, bool) {
+	return wt.latency.get()
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Delays between attempts to publish the oldest outbox entry
+	outboxRetryMin = 100 * time.Millisecond
+	outboxRetryMax = 30 * time.Second
+
+	// How often an empty outbox is checked for entries added by other
+	// instances sharing it
+	outboxPollInterval = time.Second
+)
+
+// OutboxPublisher publishes notifications at least once: they are added to
+// a durable outbox, then published from it oldest first, retrying with
+// backoff until the publish succeeds or, with maxAttempts set, the entry
+// is moved to the dead letter queue. Other messages are published
+// directly.
+type OutboxPublisher struct {
+	domain.Publisher
+	outbox      domain.Outbox
+	split       func(domain.WalletNotification) []domain.WalletNotification
+	maxAttempts int // 0 = retry until published
+	deadLetters *DeadLetters
+	wake        chan struct{}
+	logger      *zap.Logger
+}
+
+// NewOutboxPublisher returns a publisher relaying notifications to
+// publisher through outbox, dead-lettering those still failing after
+// maxAttempts (0 = never). Notifications are queued as the copies split
+// returns, one per channel they are routed to, so a retry only publishes
+// again the copies that failed; nil queues them whole.
+func NewOutboxPublisher(
+	publisher domain.Publisher,
+	outbox domain.Outbox,
+	split func(domain.WalletNotification) []domain.WalletNotification,
+	maxAttempts int,
+	deadLetters *DeadLetters,
+	logger *zap.Logger,
+) *OutboxPublisher {
+	return &OutboxPublisher{
+		Publisher:   publisher,
+		outbox:      outbox,
+		split:       split,
+		maxAttempts: maxAttempts,
+		deadLetters: deadLetters,
+		wake:        make(chan struct{}, 1),
+		logger:      logger,
+	}
+}
+
+// PublishNotification adds the notification to the outbox, one entry per
+// route; it is published by Start
+func (op *OutboxPublisher) PublishNotification(ctx context.Context, notification domain.WalletNotification) error {
+	copies := []domain.WalletNotification{notification}
+	if op.split != nil {
+		if split := op.split(notification); len(split) > 0 {
+			copies = split
+		}
+	}
+
+	entries := make([]domain.OutboxEntry, len(copies))
+	for i, routed := range copies {
+		entries[i] = domain.OutboxEntry{
+			ID:           fmt.Sprintf("%d-%s", time.Now().UnixNano(), randomHex(4)),
+			Notification: routed,
+			AddedAt:      time.Now(),
+		}
+	}
+	if err := op.outbox.Add(ctx, entries...); err != nil {
+		return fmt.Errorf("failed to add notification to outbox: %w", err)
+	}
+	metrics.OutboxDepth.Add(float64(len(entries)))
+
+	select {
+	case op.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start publishes outbox entries until ctx is done. Entries left are
+// published after a restart.
+func (op *OutboxPublisher) Start(ctx context.Context) {
+	op.updateDepth(ctx)
+	retry := backoff.New(outboxRetryMin, outboxRetryMax)
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		published, err := op.publishOldest(ctx, retry.Attempt()+1)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			metrics.OutboxPublishFailuresTotal.Inc()
+			op.logger.Warn("Failed to publish from outbox, retrying",
+				zap.Int("attempt", retry.Attempt()+1),
+				zap.Error(err),
+			)
+			if !retry.Wait(ctx) {
+				return
+			}
+			continue
+		}
+		if published {
+			retry.Reset()
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-op.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain waits until the outbox is empty or ctx is done. Call it once
+// nothing publishes notifications anymore.
+func (op *OutboxPublisher) Drain(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if n, err := op.outbox.Len(ctx); err != nil || n == 0 {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// publishOldest publishes the oldest entry and removes it from the outbox,
+// reporting false if there was none. Past maxAttempts, a failed entry is
+// removed once dead-lettered.
+func (op *OutboxPublisher) publishOldest(ctx context.Context, attempt int) (bool, error) {
+	entry, err := op.outbox.Oldest(ctx)
+	var invalid *domain.InvalidOutboxEntryError
+	if errors.As(err, &invalid) {
+		return op.removeInvalid(ctx, invalid)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	if entry == nil {
+		metrics.OutboxDepth.Set(0)
+		return false, nil
+	}
+
+	if err := op.Publisher.PublishNotification(ctx, entry.Notification); err != nil {
+		if op.maxAttempts == 0 || attempt < op.maxAttempts || ctx.Err() != nil ||
+			!op.deadLetters.addNotification(ctx, entry.Notification, err) {
+			return false, err
+		}
+		if err := op.outbox.Remove(ctx, entry.ID); err != nil {
+			return false, fmt.Errorf("failed to remove dead notification from outbox: %w", err)
+		}
+		op.updateDepth(ctx)
+		return true, nil
+	}
+	// Published again if this fails
+	if err := op.outbox.Remove(ctx, entry.ID); err != nil {
+		return false, fmt.Errorf("failed to remove published notification from outbox: %w", err)
+	}
+	metrics.OutboxDelay.Observe(time.Since(entry.AddedAt).Seconds())
+	op.updateDepth(ctx)
+	return true, nil
+}
+
+// removeInvalid takes an entry that can't be decoded out of the outbox,
+// dead-lettering it if possible, so it doesn't hold up the entries behind
+func (op *OutboxPublisher) removeInvalid(ctx context.Context, invalid *domain.InvalidOutboxEntryError) (bool, error) {
+	deadLettered := op.deadLetters.addInvalidNotification(ctx, invalid.Data, invalid)
+	if err := op.outbox.Remove(ctx, invalid.ID); err != nil {
+		return false, fmt.Errorf("failed to remove invalid entry from outbox: %w", err)
+	}
+
+	op.logger.Error("Removed invalid entry from outbox",
+		zap.String("id", invalid.ID),
+		zap.Bool("dead_lettered", deadLettered),
+		zap.Error(invalid.Err),
+	)
+	op.updateDepth(ctx)
+	return true, nil
+}
+
+func (op *OutboxPublisher) updateDepth(ctx context.Context) {
+	if n, err := op.outbox.Len(ctx); err == nil {
+		metrics.OutboxDepth.Set(float64(n))
+	}
+}
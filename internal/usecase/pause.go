@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// pause mutes a subscription until a time, or until resumed if until is
+// zero, and counts the transactions the subscriber missed meanwhile
+type pause struct {
+	until  time.Time
+	missed int
+}
+
+// active reports whether the pause still applies at now
+func (p *pause) active(now time.Time) bool {
+	return p.until.IsZero() || now.Before(p.until)
+}
+
+// PauseWallet stops notifying the user about the wallet for duration, or
+// until resumed if duration is zero, without removing the subscription
+func (wt *WalletTracker) PauseWallet(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+	duration time.Duration,
+) (*domain.SubscriptionPause, error) {
+	if duration < 0 {
+		return nil, fmt.Errorf("%w: negative pause duration %s", domain.ErrInvalidDuration, duration)
+	}
+
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	subscription, err := wt.storedSubscription(ctx, walletAddress, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := subscriptionKey{walletAddress, userID}
+	p := &pause{}
+	if existing, ok := wt.pauses[key]; ok && existing.active(time.Now()) {
+		p.missed = existing.missed // Extending a pause keeps the count
+	}
+	subscription.Paused = true
+	subscription.PausedUntil = nil
+	if duration > 0 {
+		p.until = time.Now().Add(duration)
+		subscription.PausedUntil = &p.until
+	}
+
+	if err := wt.repo.AddSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to persist subscription: %w", err)
+	}
+	wt.pauses[key] = p
+
+	wt.logger.Info("Paused wallet subscription",
+		zap.String("wallet", string(walletAddress)),
+		zap.Int64("user_id", int64(userID)),
+		zap.Duration("duration", duration),
+	)
+	return &domain.SubscriptionPause{
+		WalletAddress: walletAddress,
+		PausedUntil:   subscription.PausedUntil,
+		Missed:        p.missed,
+	}, nil
+}
+
+// ResumeWallet notifies the user about the wallet again and reports how
+// many transactions were missed while paused
+func (wt *WalletTracker) ResumeWallet(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) (*domain.SubscriptionPause, error) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	subscription, err := wt.storedSubscription(ctx, walletAddress, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Paused = false
+	subscription.PausedUntil = nil
+	if err := wt.repo.AddSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to persist subscription: %w", err)
+	}
+
+	key := subscriptionKey{walletAddress, userID}
+	missed := 0
+	if p, ok := wt.pauses[key]; ok {
+		missed = p.missed
+		delete(wt.pauses, key)
+	}
+
+	wt.logger.Info("Resumed wallet subscription",
+		zap.String("wallet", string(walletAddress)),
+		zap.Int64("user_id", int64(userID)),
+		zap.Int("missed", missed),
+	)
+	return &domain.SubscriptionPause{WalletAddress: walletAddress, Missed: missed}, nil
+}
+
+// Paused reports whether the user's subscription to the wallet is paused
+func (wt *WalletTracker) Paused(walletAddress domain.WalletAddress, userID domain.UserID) bool {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	p, ok := wt.pauses[subscriptionKey{walletAddress, userID}]
+	return ok && p.active(time.Now())
+}
+
+// storedSubscription returns the persisted subscription of the user to the
+// wallet. Caller must hold wt.mu.
+func (wt *WalletTracker) storedSubscription(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) (domain.WalletSubscription, error) {
+	if !slices.Contains(wt.subscribers[walletAddress], userID) {
+		return domain.WalletSubscription{}, fmt.Errorf("%w: user %d doesn't follow %s",
+			domain.ErrWalletNotFound, userID, walletAddress)
+	}
+
+	subscriptions, err := wt.repo.GetSubscriptions(ctx, walletAddress)
+	if err != nil {
+		return domain.WalletSubscription{}, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	for _, subscription := range subscriptions {
+		if subscription.UserID == userID {
+			return subscription, nil
+		}
+	}
+
+	return domain.WalletSubscription{}, fmt.Errorf("%w: subscription of user %d to %s is not persisted",
+		domain.ErrWalletNotFound, userID, walletAddress)
+}
+
+// restorePause mutes a restored subscription that was paused before a
+// restart. Caller must hold wt.mu.
+func (wt *WalletTracker) restorePause(subscription domain.WalletSubscription) {
+	if !subscription.Paused {
+		return
+	}
+
+	p := &pause{}
+	if subscription.PausedUntil != nil {
+		p.until = *subscription.PausedUntil
+	}
+	if p.active(time.Now()) {
+		wt.pauses[subscriptionKey{subscription.WalletAddress, subscription.UserID}] = p
+	}
+}
+
+// unpaused returns the subscribers of the wallet that aren't paused. When
+// count is set, each paused subscriber is charged one missed transaction.
+func (wt *WalletTracker) unpaused(
+	walletAddress domain.WalletAddress,
+	subscribers []domain.UserID,
+	count bool,
+) []domain.UserID {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	if len(wt.pauses) == 0 {
+		return subscribers
+	}
+
+	now := time.Now()
+	active := make([]domain.UserID, 0, len(subscribers))
+	for _, userID := range subscribers {
+		key := subscriptionKey{walletAddress, userID}
+		p, ok := wt.pauses[key]
+		if !ok {
+			active = append(active, userID)
+			continue
+		}
+		if !p.active(now) {
+			delete(wt.pauses, key) // Expired, the persisted pause is ignored on restore
+			active = append(active, userID)
+			continue
+		}
+		if count {
+			p.missed++
+		}
+	}
+
+	return active
+}
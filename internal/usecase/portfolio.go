@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// PortfolioService builds consolidated snapshots of a user's tracked wallets
+type PortfolioService struct {
+	blockchainClient domain.BlockchainClient
+	walletTracker    *WalletTracker
+	tokenFilters     *TokenFilters
+	tokens           []string
+	logger           *zap.Logger
+}
+
+func NewPortfolioService(
+	blockchainClient domain.BlockchainClient,
+	walletTracker *WalletTracker,
+	tokenFilters *TokenFilters,
+	tokens []string,
+	logger *zap.Logger,
+) *PortfolioService {
+	return &PortfolioService{
+		blockchainClient: blockchainClient,
+		walletTracker:    walletTracker,
+		tokenFilters:     tokenFilters,
+		tokens:           tokens,
+		logger:           logger,
+	}
+}
+
+// GetPortfolioStatus returns all wallets of the user with their native and
+// major token balances, last seen activity and active filters
+func (ps *PortfolioService) GetPortfolioStatus(
+	ctx context.Context,
+	userID domain.UserID,
+) (*domain.PortfolioStatus, error) {
+	subscriptions, err := ps.walletTracker.SubscriptionsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make([]domain.WalletAddress, len(subscriptions))
+	for i, subscription := range subscriptions {
+		wallets[i] = subscription.WalletAddress
+	}
+	statuses := ps.walletStatuses(ctx, wallets)
+	for i, subscription := range subscriptions {
+		statuses[i].Filters = subscription.Filters
+
+		// The stored pause outlives its expiry until the next change
+		if ps.walletTracker.Paused(subscription.WalletAddress, userID) {
+			statuses[i].Paused = true
+			statuses[i].PausedUntil = subscription.PausedUntil
+		}
+	}
+
+	return &domain.PortfolioStatus{
+		UserID:       userID,
+		Wallets:      statuses,
+		TokenFilters: ps.tokenFilters.Status(userID),
+		GeneratedAt:  time.Now(),
+	}, nil
+}
+
+// GetGroupSummary returns the balances and last seen activity of the
+// group's wallets the user still follows, and which of them are paused
+func (ps *PortfolioService) GetGroupSummary(
+	ctx context.Context,
+	group domain.WalletGroup,
+) (*domain.WalletGroupSummary, error) {
+	followed := ps.walletTracker.WalletsForUser(group.UserID)
+	wallets := slices.DeleteFunc(slices.Clone(group.Wallets), func(wallet domain.WalletAddress) bool {
+		return !slices.Contains(followed, wallet)
+	})
+
+	summary := &domain.WalletGroupSummary{
+		Group:       group.Name,
+		UserID:      group.UserID,
+		Wallets:     ps.walletStatuses(ctx, wallets),
+		GeneratedAt: time.Now(),
+	}
+	for _, wallet := range wallets {
+		if ps.walletTracker.Paused(wallet, group.UserID) {
+			summary.Paused = append(summary.Paused, wallet)
+		}
+	}
+
+	return summary, nil
+}
+
+// walletStatuses returns the native and major token balances and last seen
+// activity of each wallet
+func (ps *PortfolioService) walletStatuses(
+	ctx context.Context,
+	wallets []domain.WalletAddress,
+) []domain.WalletStatus {
+	statuses := make([]domain.WalletStatus, 0, len(wallets))
+	for _, walletAddress := range wallets {
+		walletStatus := domain.WalletStatus{
+			WalletAddress: walletAddress,
+			Balances:      ps.getBalances(ctx, walletAddress),
+		}
+
+		if ts, ok := ps.walletTracker.LastActivity(walletAddress); ok {
+			walletStatus.LastActivity = &ts
+		}
+
+		statuses = append(statuses, walletStatus)
+	}
+
+	return statuses
+}
+
+// GetWalletBalance returns the balance of a single token of the wallet, or
+// its native and major token balances if tokenAddress is empty
+func (ps *PortfolioService) GetWalletBalance(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	tokenAddress string,
+) (*domain.WalletStatus, error) {
+	if !common.IsHexAddress(string(walletAddress)) {
+		return nil, domain.ErrInvalidAddress
+	}
+
+	status := &domain.WalletStatus{WalletAddress: walletAddress}
+	if ts, ok := ps.walletTracker.LastActivity(walletAddress); ok {
+		status.LastActivity = &ts
+	}
+
+	if tokenAddress == "" {
+		status.Balances = ps.getBalances(ctx, walletAddress)
+		return status, nil
+	}
+
+	balance, err := ps.blockchainClient.GetTokenBalance(ctx, walletAddress, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	status.Balances = []domain.TokenBalance{*balance}
+
+	return status, nil
+}
+
+func (ps *PortfolioService) getBalances(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) []domain.TokenBalance {
+	balances, err := ps.blockchainClient.GetBalances(ctx, walletAddress, ps.tokens)
+	if err != nil {
+		ps.logger.Warn("Failed to get balances",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return []domain.TokenBalance{}
+	}
+
+	return balances
+}
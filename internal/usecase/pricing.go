@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// cachedPrice is a provider answer; unknown tokens are cached too so they
+// aren't requested again before the TTL expires
+type cachedPrice struct {
+	usd       float64
+	known     bool
+	expiresAt time.Time
+}
+
+// PriceService attaches USD values to transfers. Providers are asked in
+// order until every token is priced, within a shared request rate limit.
+type PriceService struct {
+	providers   []domain.PriceProvider
+	stablecoins map[string]bool
+	ttl         time.Duration
+	limiter     *rate.Limiter
+	logger      *zap.Logger
+
+	cache map[string]cachedPrice
+	mu    sync.Mutex
+}
+
+func NewPriceService(
+	providers []domain.PriceProvider,
+	stablecoins []string,
+	ttl time.Duration,
+	requestsPerMinute int,
+	logger *zap.Logger,
+) *PriceService {
+	limit := rate.Inf
+	if requestsPerMinute > 0 {
+		limit = rate.Every(time.Minute / time.Duration(requestsPerMinute))
+	}
+
+	ps := &PriceService{
+		providers:   providers,
+		stablecoins: make(map[string]bool),
+		ttl:         ttl,
+		limiter:     rate.NewLimiter(limit, max(requestsPerMinute/10, 1)),
+		logger:      logger,
+		cache:       make(map[string]cachedPrice),
+	}
+	for _, stablecoin := range stablecoins {
+		ps.stablecoins[strings.ToLower(stablecoin)] = true
+	}
+
+	return ps
+}
+
+// Enrich sets ValueUSD on every fungible transfer whose token has a price.
+// Tokens that can't be priced right away are left without a value rather
+// than delaying the notification.
+func (ps *PriceService) Enrich(ctx context.Context, transfers []domain.Transfer) {
+	if len(transfers) == 0 {
+		return
+	}
+
+	var tokens []string
+	for _, transfer := range transfers {
+		if priceable(transfer) {
+			tokens = append(tokens, strings.ToLower(transfer.TokenAddress))
+		}
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	prices := ps.prices(ctx, tokens)
+	for i := range transfers {
+		if !priceable(transfers[i]) {
+			continue
+		}
+		price, ok := prices[strings.ToLower(transfers[i].TokenAddress)]
+		if !ok {
+			continue
+		}
+		amount, ok := new(big.Float).SetString(transfers[i].FormattedValue)
+		if !ok {
+			continue
+		}
+		transfers[i].ValueUSD = amount.Mul(amount, big.NewFloat(price)).Text('f', 2)
+	}
+}
+
+// EnrichBalances sets ValueUSD on every balance whose token has a price
+func (ps *PriceService) EnrichBalances(ctx context.Context, balances []domain.TokenBalance) {
+	var tokens []string
+	for _, balance := range balances {
+		if balance.FormattedBalance != "" {
+			tokens = append(tokens, strings.ToLower(balance.TokenAddress))
+		}
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	prices := ps.prices(ctx, tokens)
+	for i := range balances {
+		price, ok := prices[strings.ToLower(balances[i].TokenAddress)]
+		if !ok {
+			continue
+		}
+		amount, ok := new(big.Float).SetString(balances[i].FormattedBalance)
+		if !ok {
+			continue
+		}
+		balances[i].ValueUSD = amount.Mul(amount, big.NewFloat(price)).Text('f', 2)
+	}
+}
+
+// priceable reports whether the transfer moves a fungible amount with
+// known decimals
+func priceable(transfer domain.Transfer) bool {
+	return transfer.TokenStandard != domain.ERC721Token && transfer.FormattedValue != ""
+}
+
+// prices returns the USD price of each token it could find, from the cache
+// or the providers
+func (ps *PriceService) prices(ctx context.Context, tokens []string) map[string]float64 {
+	prices := make(map[string]float64)
+	var missing []string
+
+	ps.mu.Lock()
+	now := time.Now()
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+
+		if ps.stablecoins[token] {
+			prices[token] = 1
+			continue
+		}
+		if cached, ok := ps.cache[token]; ok && now.Before(cached.expiresAt) {
+			metrics.CacheRequestsTotal.WithLabelValues("price", "hit").Inc()
+			if cached.known {
+				prices[token] = cached.usd
+			}
+			continue
+		}
+		metrics.CacheRequestsTotal.WithLabelValues("price", "miss").Inc()
+		missing = append(missing, token)
+	}
+	ps.mu.Unlock()
+
+	failed := false
+	for _, provider := range ps.providers {
+		if len(missing) == 0 {
+			break
+		}
+		if !ps.limiter.Allow() {
+			ps.logger.Debug("Price lookup rate limited, leaving tokens unpriced",
+				zap.Int("tokens", len(missing)))
+			return prices // Not cached, so they are retried next time
+		}
+
+		found, err := provider.GetPricesUSD(ctx, missing)
+		if err != nil {
+			ps.logger.Warn("Failed to get token prices",
+				zap.String("provider", provider.Name()),
+				zap.Error(err))
+			failed = true
+			continue
+		}
+
+		remaining := missing[:0]
+		for _, token := range missing {
+			if price, ok := found[token]; ok {
+				prices[token] = price
+				ps.remember(token, price, true)
+			} else {
+				remaining = append(remaining, token)
+			}
+		}
+		missing = remaining
+	}
+
+	// No provider knows them; after a failure they may be known next time
+	if !failed {
+		for _, token := range missing {
+			ps.remember(token, 0, false)
+		}
+	}
+
+	return prices
+}
+
+func (ps *PriceService) remember(token string, usd float64, known bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.cache[token] = cachedPrice{usd: usd, known: known, expiresAt: time.Now().Add(ps.ttl)}
+}
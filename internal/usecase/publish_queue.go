@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// PublishQueueConfig sizes the worker pool between detection and publishing
+type PublishQueueConfig struct {
+	// Workers publishing concurrently; each owns a shard of wallets so a
+	// wallet's transactions stay in order
+	Workers int
+	// Transactions buffered in memory before spilling to the overflow queue
+	Size int
+	// How long buffered transactions keep being published at shutdown;
+	// those left after it are persisted for the next start
+	DrainTimeout time.Duration
+}
+
+// Drain timeout used when none is configured
+const defaultDrainTimeout = 10 * time.Second
+
+// publishQueue hands transactions from wallet listeners to a fixed pool of
+// workers. When a worker falls behind, its transactions spill into the
+// persistent overflow queue and are fed back as it catches up, so slow
+// publishing never blocks chain ingestion.
+type publishQueue struct {
+	shards       []*queueShard
+	overflow     domain.OverflowQueue
+	drainTimeout time.Duration
+	logger       *zap.Logger
+}
+
+// queueShard buffers the transactions of one worker. Once it has spilled,
+// later transactions follow through the overflow queue until it is
+// drained, so each wallet's transactions keep the order they were detected
+// in.
+type queueShard struct {
+	index int
+	ch    chan domain.QueuedTransaction
+	wake  chan struct{} // Wakes the overflow drainer
+
+	// Transactions of the shard wait in the overflow queue; set on start
+	// for those left by the previous run
+	spilling bool
+	mu       sync.Mutex
+}
+
+func newPublishQueue(
+	config PublishQueueConfig,
+	overflow domain.OverflowQueue,
+	logger *zap.Logger,
+) *publishQueue {
+	workers := max(config.Workers, 1)
+	size := max(config.Size/workers, 1)
+
+	q := &publishQueue{
+		shards:   make([]*queueShard, workers),
+		overflow: overflow,
+		logger:   logger,
+
+		drainTimeout: config.DrainTimeout,
+	}
+	if q.drainTimeout <= 0 {
+		q.drainTimeout = defaultDrainTimeout
+	}
+	for i := range q.shards {
+		q.shards[i] = &queueShard{
+			index:    i,
+			ch:       make(chan domain.QueuedTransaction, size),
+			wake:     make(chan struct{}, 1),
+			spilling: true,
+		}
+	}
+
+	return q
+}
+
+func (q *publishQueue) shardFor(walletAddress domain.WalletAddress) *queueShard {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(string(walletAddress))))
+	return q.shards[h.Sum32()%uint32(len(q.shards))]
+}
+
+// occupancy returns the number of buffered transactions and the buffer size
+// across all shards
+func (q *publishQueue) occupancy() (buffered, capacity int) {
+	for _, shard := range q.shards {
+		buffered += len(shard.ch)
+		capacity += cap(shard.ch)
+	}
+	return buffered, capacity
+}
+
+// enqueue queues item without blocking, unless the overflow queue is
+// unavailable too
+func (q *publishQueue) enqueue(ctx context.Context, item domain.QueuedTransaction) {
+	shard := q.shardFor(item.WalletAddress)
+
+	shard.mu.Lock()
+	if !shard.spilling {
+		select {
+		case shard.ch <- item:
+			shard.mu.Unlock()
+			metrics.PublishQueueDepth.Inc()
+			return
+		default:
+		}
+	}
+	err := q.overflow.Push(ctx, shard.index, item)
+	if err == nil {
+		shard.spilling = true
+	}
+	shard.mu.Unlock()
+
+	if err == nil {
+		metrics.PublishOverflowTotal.Inc()
+		select {
+		case shard.wake <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	// Out of order if transactions of the shard are spilled already
+	q.logger.Error("Failed to spill to overflow queue, waiting for a worker",
+		zap.String("wallet", string(item.WalletAddress)),
+		zap.String("tx_hash", string(item.Transaction.Hash)),
+		zap.Error(err),
+	)
+	select {
+	case shard.ch <- item:
+		metrics.PublishQueueDepth.Inc()
+	case <-ctx.Done():
+	}
+}
+
+// run processes queued transactions with handle until ctx is done. Workers
+// then keep publishing what is buffered for up to drainTimeout, and what
+// is still left after that is persisted so it is processed after a
+// restart. Spilled transactions stay in the overflow queue.
+func (q *publishQueue) run(
+	ctx context.Context,
+	handle func(ctx context.Context, item domain.QueuedTransaction),
+) {
+	work, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+	stopDrain := context.AfterFunc(ctx, func() {
+		time.AfterFunc(q.drainTimeout, cancelWork)
+	})
+	defer stopDrain()
+
+	var workers sync.WaitGroup
+	for _, shard := range q.shards {
+		workers.Add(2)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					q.drainShard(work, shard, handle)
+					return
+				case item := <-shard.ch:
+					metrics.PublishQueueDepth.Dec()
+					handle(work, item)
+				}
+			}
+		}()
+		go func() {
+			defer workers.Done()
+			q.drainOverflow(ctx, shard)
+		}()
+	}
+
+	workers.Wait()
+	q.persistBuffered()
+}
+
+// drainShard publishes the transactions buffered in shard until none is
+// left or work is done
+func (q *publishQueue) drainShard(
+	work context.Context,
+	shard *queueShard,
+	handle func(ctx context.Context, item domain.QueuedTransaction),
+) {
+	for work.Err() == nil {
+		select {
+		case item := <-shard.ch:
+			metrics.PublishQueueDepth.Dec()
+			handle(work, item)
+		default:
+			return
+		}
+	}
+}
+
+// drainOverflow feeds the spilled transactions of shard back to its worker
+func (q *publishQueue) drainOverflow(ctx context.Context, shard *queueShard) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		item, err := q.popSpilled(ctx, shard)
+		if err != nil && ctx.Err() == nil {
+			q.logger.Error("Failed to read overflow queue", zap.Error(err))
+		}
+		if item == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-shard.wake:
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		select {
+		case shard.ch <- *item:
+			metrics.PublishQueueDepth.Inc()
+		case <-ctx.Done():
+			err := q.overflow.PushFront(context.WithoutCancel(ctx), shard.index, []domain.QueuedTransaction{*item})
+			if err != nil {
+				q.logger.Error("Failed to return transaction to overflow queue",
+					zap.String("tx_hash", string(item.Transaction.Hash)),
+					zap.Error(err),
+				)
+			}
+			return
+		}
+	}
+}
+
+// popSpilled takes the oldest spilled transaction of shard, nil once none
+// is left, at which point new transactions go to the worker directly again
+func (q *publishQueue) popSpilled(ctx context.Context, shard *queueShard) (*domain.QueuedTransaction, error) {
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if !shard.spilling {
+		return nil, nil
+	}
+	item, err := q.overflow.Pop(ctx, shard.index)
+	if err == nil && item == nil {
+		shard.spilling = false
+	}
+	return item, err
+}
+
+// persistBuffered moves transactions left in memory back to the head of
+// the overflow queue, ahead of those spilled after them
+func (q *publishQueue) persistBuffered() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	persisted := 0
+	for _, shard := range q.shards {
+		var items []domain.QueuedTransaction
+	drain:
+		for {
+			select {
+			case item := <-shard.ch:
+				metrics.PublishQueueDepth.Dec()
+				items = append(items, item)
+			default:
+				break drain
+			}
+		}
+
+		if err := q.overflow.PushFront(ctx, shard.index, items); err != nil {
+			q.logger.Error("Failed to persist queued transactions",
+				zap.Int("count", len(items)),
+				zap.Error(err),
+			)
+			continue
+		}
+		persisted += len(items)
+	}
+
+	if persisted > 0 {
+		q.logger.Info("Persisted queued transactions for the next start", zap.Int("count", persisted))
+	}
+}
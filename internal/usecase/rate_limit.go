@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// How often throttled users are checked for a summary to send
+	rateLimitSummaryInterval = 10 * time.Second
+	// Limiters of users idle this long are dropped
+	rateLimitIdleTimeout = 10 * time.Minute
+)
+
+// NotificationRateLimit caps notifications per user (0 = unlimited)
+type NotificationRateLimit struct {
+	PerMinute int
+	Burst     int
+}
+
+// notificationThrottle keeps a token bucket per user. Notifications beyond
+// the bucket are dropped and counted, and the user gets one summary of how
+// many were suppressed once tokens are available again.
+type notificationThrottle struct {
+	limit rate.Limit
+	burst int
+	users map[domain.UserID]*userThrottle
+	mu    sync.Mutex
+}
+
+type userThrottle struct {
+	limiter    *rate.Limiter
+	suppressed int
+	lastUsed   time.Time
+}
+
+func newNotificationThrottle(config NotificationRateLimit) *notificationThrottle {
+	t := &notificationThrottle{users: make(map[domain.UserID]*userThrottle)}
+	t.limit, t.burst = config.bucket()
+	return t
+}
+
+// bucket returns the token bucket parameters of the limit
+func (config NotificationRateLimit) bucket() (rate.Limit, int) {
+	if config.PerMinute <= 0 {
+		return rate.Inf, 0
+	}
+	return rate.Every(time.Minute / time.Duration(config.PerMinute)), max(config.Burst, 1)
+}
+
+// setLimit changes the limit, also of users already being tracked.
+// Summaries owed to throttled users are still sent if it is lifted.
+func (t *notificationThrottle) setLimit(config NotificationRateLimit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.limit, t.burst = config.bucket()
+	for _, user := range t.users {
+		user.limiter.SetLimit(t.limit)
+		user.limiter.SetBurst(t.burst)
+	}
+}
+
+// allow returns the subscribers that may receive one more notification
+// and counts it as suppressed for the others
+func (t *notificationThrottle) allow(subscribers []domain.UserID) []domain.UserID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.limit == rate.Inf {
+		return subscribers
+	}
+
+	now := time.Now()
+	allowed := make([]domain.UserID, 0, len(subscribers))
+	for _, userID := range subscribers {
+		user, ok := t.users[userID]
+		if !ok {
+			user = &userThrottle{limiter: rate.NewLimiter(t.limit, t.burst)}
+			t.users[userID] = user
+		}
+		user.lastUsed = now
+
+		// A pending summary goes out before anything else
+		if user.suppressed == 0 && user.limiter.AllowN(now, 1) {
+			allowed = append(allowed, userID)
+			continue
+		}
+		user.suppressed++
+	}
+
+	return allowed
+}
+
+// summaries takes a token for every throttled user who has one available
+// again and returns how many notifications each of them missed
+func (t *notificationThrottle) summaries(now time.Time) map[domain.UserID]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summaries := make(map[domain.UserID]int)
+	for userID, user := range t.users {
+		if user.suppressed > 0 && user.limiter.AllowN(now, 1) {
+			summaries[userID] = user.suppressed
+			user.suppressed = 0
+			continue
+		}
+		if user.suppressed == 0 && now.Sub(user.lastUsed) > rateLimitIdleTimeout {
+			delete(t.users, userID)
+		}
+	}
+
+	return summaries
+}
+
+// summarizeThrottled tells throttled users how many notifications they
+// missed. It runs even while nothing is limited, as the limit may change
+// at runtime.
+func (wt *WalletTracker) summarizeThrottled(ctx context.Context) {
+	ticker := time.NewTicker(rateLimitSummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for userID, suppressed := range wt.throttle.summaries(now) {
+				wt.publishRateLimited(ctx, userID, suppressed, now)
+			}
+		}
+	}
+}
+
+func (wt *WalletTracker) publishRateLimited(
+	ctx context.Context,
+	userID domain.UserID,
+	suppressed int,
+	now time.Time,
+) {
+	notification := domain.WalletNotification{
+		Type:           domain.RateLimitedNotification,
+		SchemaVersion:  domain.CurrentSchemaVersion,
+		Subscribers:    []domain.UserID{userID},
+		Timestamp:      now,
+		Trace:          traceContextFor(ctx),
+		IdempotencyKey: fmt.Sprintf("rate_limited:%d:%d", userID, now.UnixNano()),
+		Suppressed:     suppressed,
+	}
+
+	if err := wt.publisher.PublishNotification(ctx, notification); err != nil {
+		wt.logger.Error("Failed to publish rate limit summary",
+			zap.Int64("user_id", int64(userID)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	wt.logger.Info("User was rate limited",
+		zap.Int64("user_id", int64(userID)),
+		zap.Int("suppressed", suppressed),
+	)
+}
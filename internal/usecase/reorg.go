@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Number of recent blocks whose delivered transactions are remembered
+const deliveryLogDepth = 256
+
+// deliveryLog remembers which transactions were delivered for which wallet
+// per block, so a reorg can be translated into invalidations
+type deliveryLog struct {
+	blocks map[uint64]map[domain.WalletAddress][]delivery
+	latest uint64
+	mu     sync.Mutex
+}
+
+func newDeliveryLog() *deliveryLog {
+	return &deliveryLog{
+		blocks: make(map[uint64]map[domain.WalletAddress][]delivery),
+	}
+}
+
+// delivery is a transaction delivered for a wallet, with the idempotency
+// keys of its notifications
+type delivery struct {
+	hash domain.TransactionHash
+	keys []string
+}
+
+func (dl *deliveryLog) record(walletAddress domain.WalletAddress, tx domain.Transaction, keys []string) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	wallets, ok := dl.blocks[tx.BlockNumber]
+	if !ok {
+		wallets = make(map[domain.WalletAddress][]delivery)
+		dl.blocks[tx.BlockNumber] = wallets
+	}
+	wallets[walletAddress] = append(wallets[walletAddress], delivery{hash: tx.Hash, keys: keys})
+
+	if tx.BlockNumber > dl.latest {
+		dl.latest = tx.BlockNumber
+		for n := range dl.blocks {
+			if n+deliveryLogDepth < dl.latest {
+				delete(dl.blocks, n)
+			}
+		}
+	}
+}
+
+// invalidate removes and returns the deliveries within the reorged range
+func (dl *deliveryLog) invalidate(reorg domain.Reorg) map[domain.WalletAddress][]delivery {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	invalidated := make(map[domain.WalletAddress][]delivery)
+	for n, wallets := range dl.blocks {
+		if n < reorg.FromBlock || n > reorg.ToBlock {
+			continue
+		}
+		for walletAddress, deliveries := range wallets {
+			invalidated[walletAddress] = append(invalidated[walletAddress], deliveries...)
+		}
+		delete(dl.blocks, n)
+	}
+
+	return invalidated
+}
+
+// watchReorgs publishes a reorg notification for every wallet that had
+// transactions delivered from blocks that are no longer canonical
+func (wt *WalletTracker) watchReorgs(ctx context.Context) {
+	reorgs, err := wt.blockchainClient.SubscribeReorgs(ctx)
+	if err != nil {
+		wt.logger.Error("Failed to subscribe to reorgs", zap.Error(err))
+		return
+	}
+
+	for reorg := range reorgs {
+		if dropped := wt.pending.drop(ctx, reorg); dropped > 0 {
+			wt.logger.Info("Dropped pending transactions from reorged blocks",
+				zap.Int("dropped", dropped))
+		}
+
+		for walletAddress, deliveries := range wt.deliveries.invalidate(reorg) {
+			wt.publishReorg(ctx, walletAddress, reorg, deliveries)
+		}
+	}
+}
+
+func (wt *WalletTracker) publishReorg(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	reorg domain.Reorg,
+	deliveries []delivery,
+) {
+	hashes := make([]domain.TransactionHash, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		if !slices.Contains(hashes, delivery.hash) {
+			hashes = append(hashes, delivery.hash)
+		}
+	}
+	wt.archive.forget(ctx, walletAddress, hashes)
+
+	wt.mu.RLock()
+	subscribers := make([]domain.UserID, len(wt.subscribers[walletAddress]))
+	copy(subscribers, wt.subscribers[walletAddress])
+	wt.mu.RUnlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	// Transactions may be included again in the new chain and must be
+	// notified anew then
+	wt.dedup.forget(ctx, deliveries, subscribers)
+
+	key := fmt.Sprintf("%s:reorg:%d-%d", strings.ToLower(string(walletAddress)), reorg.FromBlock, reorg.ToBlock)
+	notification := domain.WalletNotification{
+		Type:           domain.ReorgNotification,
+		SchemaVersion:  domain.CurrentSchemaVersion,
+		WalletAddress:  walletAddress,
+		Subscribers:    subscribers,
+		WalletLabels:   wt.walletLabelsFor(walletAddress, subscribers),
+		WalletGroups:   wt.groups.groupsFor(walletAddress, subscribers),
+		Timestamp:      time.Now(),
+		Trace:          traceContextFor(ctx),
+		IdempotencyKey: key,
+		Reorg:          &reorg,
+		InvalidatedTxs: hashes,
+	}
+
+	if err := wt.publisher.PublishNotification(ctx, notification); err != nil {
+		wt.logger.Error("Failed to publish reorg notification",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	wt.logger.Info("Published reorg notification",
+		zap.String("wallet", string(walletAddress)),
+		zap.Uint64("from_block", reorg.FromBlock),
+		zap.Uint64("to_block", reorg.ToBlock),
+		zap.Int("invalidated_txs", len(hashes)),
+	)
+}
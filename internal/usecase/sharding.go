@@ -0,0 +1,386 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// Points of each instance on the hash ring; more even out the share of
+// wallets each one owns
+const shardVirtualNodes = 128
+
+// errHandedOver stops a listener whose wallet moved to another instance
+var errHandedOver = errors.New("handed over to another instance")
+
+// ShardCoordinator splits the tracked wallets between tracker instances
+// sharing a Redis. Instances heartbeat into a shared membership, and each
+// wallet is listened to by the one that owns it on a consistent hash ring,
+// so only the wallets of an instance joining or leaving change owner.
+// Every instance keeps all subscriptions in memory, kept in sync through a
+// change feed, so any of them can handle commands.
+type ShardCoordinator struct {
+	membership domain.ShardMembership
+	changes    domain.SubscriptionChangeFeed
+	instanceID string
+	interval   time.Duration
+	ttl        time.Duration
+	ring       atomic.Pointer[hashRing]
+	logger     *zap.Logger
+	mu         sync.Mutex
+}
+
+// NewShardCoordinator returns a coordinator for instanceID, which must be
+// unique among the instances. It heartbeats every interval and members
+// that missed heartbeats for ttl are dropped.
+func NewShardCoordinator(
+	membership domain.ShardMembership,
+	changes domain.SubscriptionChangeFeed,
+	instanceID string,
+	interval time.Duration,
+	ttl time.Duration,
+	logger *zap.Logger,
+) *ShardCoordinator {
+	sc := &ShardCoordinator{
+		membership: membership,
+		changes:    changes,
+		instanceID: instanceID,
+		interval:   interval,
+		ttl:        ttl,
+		logger:     logger,
+	}
+	sc.ring.Store(newHashRing([]string{instanceID}))
+	return sc
+}
+
+// Join registers the instance and learns the other members, so wallets
+// restored on start go to their owners right away
+func (sc *ShardCoordinator) Join(ctx context.Context) error {
+	if err := sc.heartbeat(ctx); err != nil {
+		return err
+	}
+
+	sc.logger.Info("Joined shard ring",
+		zap.String("instance_id", sc.instanceID),
+		zap.Strings("members", sc.ring.Load().members),
+	)
+	return nil
+}
+
+// Start heartbeats until ctx is done, then leaves so the other instances
+// take the wallets over without waiting for the membership to expire
+func (sc *ShardCoordinator) Start(ctx context.Context) {
+	ticker := time.NewTicker(sc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sc.interval)
+			defer cancel()
+			if err := sc.membership.Leave(leaveCtx, sc.instanceID); err != nil {
+				sc.logger.Error("Failed to leave shard ring", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			if err := sc.heartbeat(ctx); err != nil && ctx.Err() == nil {
+				// Keep the last known members; the others drop this
+				// instance if it can't heartbeat for the whole TTL
+				sc.logger.Warn("Shard heartbeat failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// heartbeat renews the membership and rebuilds the ring if the members
+// changed
+func (sc *ShardCoordinator) heartbeat(ctx context.Context) error {
+	members, err := sc.membership.Heartbeat(ctx, sc.instanceID, sc.ttl)
+	if err != nil {
+		return fmt.Errorf("failed to send shard heartbeat: %w", err)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	current := sc.ring.Load()
+	if slices.Equal(members, current.members) {
+		return nil
+	}
+	sc.ring.Store(newHashRing(members))
+	close(current.changed)
+	metrics.ShardMembers.Set(float64(len(members)))
+
+	sc.logger.Info("Shard members changed", zap.Strings("members", members))
+	return nil
+}
+
+// owns reports whether key belongs to this instance, and returns a channel
+// closed when the members next change
+func (sc *ShardCoordinator) owns(key string) (bool, <-chan struct{}) {
+	ring := sc.ring.Load()
+	return ring.owner(key) == sc.instanceID, ring.changed
+}
+
+// run calls listen while this instance owns key and waits while another
+// one does, until ctx is done. When key moves away, listen keeps running
+// for two heartbeat intervals so the new owner has started listening
+// before it stops; the dedup store drops notifications both publish.
+func (sc *ShardCoordinator) run(ctx context.Context, key string, listen func(context.Context) error) error {
+	for {
+		owned, changed := sc.owns(key)
+		if !owned {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-changed:
+				continue
+			}
+		}
+
+		listenCtx, stop := context.WithCancelCause(ctx)
+		go sc.handOver(listenCtx, stop, key, changed)
+		err := listen(listenCtx)
+		stop(nil)
+		if ctx.Err() != nil || !errors.Is(context.Cause(listenCtx), errHandedOver) {
+			return err
+		}
+	}
+}
+
+// handOver stops the listener of key once another instance has owned it
+// for the grace period
+func (sc *ShardCoordinator) handOver(
+	ctx context.Context,
+	stop context.CancelCauseFunc,
+	key string,
+	changed <-chan struct{},
+) {
+	var owned bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+		if owned, changed = sc.owns(key); owned {
+			continue
+		}
+
+		// Still handed over after the grace period, unless the key came
+		// back meanwhile
+		timer := time.NewTimer(2 * sc.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if owned, changed = sc.owns(key); !owned {
+			stop(errHandedOver)
+			return
+		}
+	}
+}
+
+// announce tells the other instances the wallet's subscriptions changed
+func (sc *ShardCoordinator) announce(ctx context.Context, walletAddress domain.WalletAddress) {
+	change := domain.SubscriptionChange{InstanceID: sc.instanceID, WalletAddress: walletAddress}
+	if err := sc.changes.PublishSubscriptionChange(ctx, change); err != nil {
+		sc.logger.Error("Failed to announce subscription change",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+	}
+}
+
+// followChanges calls sync with each wallet whose subscriptions another
+// instance changed, until ctx is done
+func (sc *ShardCoordinator) followChanges(ctx context.Context, sync func(domain.WalletAddress)) {
+	err := sc.changes.SubscribeSubscriptionChanges(ctx, func(change domain.SubscriptionChange) {
+		if change.InstanceID != sc.instanceID {
+			sync(change.WalletAddress)
+		}
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		sc.logger.Error("Subscription change feed stopped", zap.Error(err))
+	}
+}
+
+// hashRing assigns keys to members by consistent hashing
+type hashRing struct {
+	members []string
+	points  []ringPoint // Sorted by hash
+	// Closed when the ring is replaced after the members changed
+	changed chan struct{}
+}
+
+type ringPoint struct {
+	hash   uint64
+	member string
+}
+
+func newHashRing(members []string) *hashRing {
+	ring := &hashRing{
+		members: members,
+		points:  make([]ringPoint, 0, len(members)*shardVirtualNodes),
+		changed: make(chan struct{}),
+	}
+	for _, member := range members {
+		for i := range shardVirtualNodes {
+			ring.points = append(ring.points, ringPoint{ringHash(member + "#" + strconv.Itoa(i)), member})
+		}
+	}
+	slices.SortFunc(ring.points, func(a, b ringPoint) int { return cmp.Compare(a.hash, b.hash) })
+	return ring
+}
+
+// owner returns the member owning key: the first point at or after its hash
+func (r *hashRing) owner(key string) string {
+	hash := ringHash(strings.ToLower(key))
+	i, _ := slices.BinarySearchFunc(r.points, hash, func(p ringPoint, hash uint64) int {
+		return cmp.Compare(p.hash, hash)
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].member
+}
+
+func ringHash(s string) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// announcingRepository tells the other instances about every subscription
+// change it persists
+type announcingRepository struct {
+	domain.WalletRepository
+	shards *ShardCoordinator
+}
+
+func (r announcingRepository) AddSubscription(ctx context.Context, subscription domain.WalletSubscription) error {
+	if err := r.WalletRepository.AddSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	r.shards.announce(ctx, subscription.WalletAddress)
+	return nil
+}
+
+func (r announcingRepository) RemoveSubscription(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	if err := r.WalletRepository.RemoveSubscription(ctx, walletAddress, userID); err != nil {
+		return err
+	}
+	r.shards.announce(ctx, walletAddress)
+	return nil
+}
+
+func (r announcingRepository) AddSubscriptions(ctx context.Context, subscriptions []domain.WalletSubscription) error {
+	if err := r.WalletRepository.AddSubscriptions(ctx, subscriptions); err != nil {
+		return err
+	}
+	announced := make(map[domain.WalletAddress]bool, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if !announced[subscription.WalletAddress] {
+			r.shards.announce(ctx, subscription.WalletAddress)
+			announced[subscription.WalletAddress] = true
+		}
+	}
+	return nil
+}
+
+func (r announcingRepository) RemoveSubscriptions(
+	ctx context.Context,
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	if err := r.WalletRepository.RemoveSubscriptions(ctx, walletAddresses, userID); err != nil {
+		return err
+	}
+	for _, walletAddress := range walletAddresses {
+		r.shards.announce(ctx, walletAddress)
+	}
+	return nil
+}
+
+// listenWhileOwned runs the wallet listener while this instance owns the
+// wallet, all the time when not sharded
+func (wt *WalletTracker) listenWhileOwned(ctx context.Context, walletAddress domain.WalletAddress) error {
+	listen := func(ctx context.Context) error {
+		return wt.runWalletListener(ctx, walletAddress)
+	}
+	if wt.shards == nil {
+		return listen(ctx)
+	}
+	return wt.shards.run(ctx, string(walletAddress), listen)
+}
+
+// syncWallet reloads the wallet's subscriptions after another instance
+// changed them. Missed counts of pauses that still apply are kept.
+func (wt *WalletTracker) syncWallet(ctx context.Context, walletAddress domain.WalletAddress) {
+	subscriptions, err := wt.repo.GetSubscriptions(ctx, walletAddress)
+	if err != nil {
+		wt.logger.Error("Failed to sync wallet subscriptions",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	wt.mu.Lock()
+	started := false
+	stored := make(map[domain.UserID]bool, len(subscriptions))
+	for _, subscription := range subscriptions {
+		subscription.WalletAddress = walletAddress
+		stored[subscription.UserID] = true
+		if wt.subscribe(subscription) {
+			started = true
+		}
+
+		key := subscriptionKey{walletAddress, subscription.UserID}
+		missed := 0
+		if p, ok := wt.pauses[key]; ok {
+			missed = p.missed
+			delete(wt.pauses, key)
+		}
+		wt.restorePause(subscription)
+		if p, ok := wt.pauses[key]; ok {
+			p.missed = missed
+		}
+	}
+
+	for _, userID := range slices.Clone(wt.subscribers[walletAddress]) {
+		if !stored[userID] {
+			wt.unsubscribe(walletAddress, userID)
+		}
+	}
+	_, tracked := wt.listeners[walletAddress]
+	wt.mu.Unlock()
+
+	if started && tracked {
+		wt.trackArchive(ctx, walletAddress)
+	}
+
+	wt.logger.Debug("Synced wallet subscriptions",
+		zap.String("wallet", string(walletAddress)),
+		zap.Int("subscriptions", len(subscriptions)),
+	)
+}
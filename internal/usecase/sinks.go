@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// Kinds of published messages, as filtered by sinks and counted in metrics
+var SinkKinds = []string{
+	"notification",
+	"response",
+	"contract_event",
+	"token_transfer",
+	"alert",
+	"command_result",
+	"firehose",
+}
+
+// Sink is a named publisher messages are fanned out to
+type Sink struct {
+	Name      string
+	Publisher domain.Publisher
+	Filter    SinkFilter
+	Retry     SinkRetry
+
+	// Required sinks are published to first and their failure fails the
+	// publish; the others only get messages all required sinks took
+	Required bool
+}
+
+// SinkFilter selects the messages a sink receives
+type SinkFilter struct {
+	// Message kinds from SinkKinds (empty = all) and, for notifications,
+	// their types (empty = all)
+	Kinds             []string
+	NotificationTypes []domain.NotificationType
+}
+
+// check rejects kinds not in SinkKinds
+func (f SinkFilter) check(sink string) error {
+	for _, kind := range f.Kinds {
+		if !slices.Contains(SinkKinds, kind) {
+			return fmt.Errorf("unknown message kind %q for sink %s", kind, sink)
+		}
+	}
+	return nil
+}
+
+func (f SinkFilter) matches(kind string, notificationType domain.NotificationType) bool {
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, kind) {
+		return false
+	}
+	if notificationType != "" && len(f.NotificationTypes) > 0 &&
+		!slices.Contains(f.NotificationTypes, notificationType) {
+		return false
+	}
+	return true
+}
+
+// SinkRetry is how often and how patiently a failed publish to a sink is
+// retried before giving up on it
+type SinkRetry struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// SinkRegistry publishes every message to all registered sinks whose filter
+// it matches. Sinks are published to concurrently and retried on their own,
+// so a slow or failing sink doesn't hold the others back.
+type SinkRegistry struct {
+	sinks  []Sink
+	logger *zap.Logger
+	mu     sync.RWMutex
+}
+
+func NewSinkRegistry(logger *zap.Logger) *SinkRegistry {
+	return &SinkRegistry{logger: logger}
+}
+
+// Register adds sink. Register all sinks before publishing.
+func (r *SinkRegistry) Register(sink Sink) error {
+	if err := sink.Filter.check(sink.Name); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.sinks, func(s Sink) bool { return s.Name == sink.Name }) {
+		return fmt.Errorf("sink %s registered twice", sink.Name)
+	}
+
+	r.sinks = append(r.sinks, sink)
+	r.logger.Info("Registered publishing sink",
+		zap.String("sink", sink.Name),
+		zap.Strings("kinds", sink.Filter.Kinds),
+		zap.Bool("required", sink.Required),
+		zap.Int("max_attempts", sink.Retry.MaxAttempts),
+	)
+	return nil
+}
+
+// setFilters replaces the filters of the sinks named in filters, all or
+// none of them
+func (r *SinkRegistry) setFilters(filters map[string]SinkFilter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, filter := range filters {
+		if err := filter.check(name); err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(r.sinks, func(s Sink) bool { return s.Name == name }) {
+			return fmt.Errorf("no sink named %s", name)
+		}
+	}
+
+	// Publishes in progress keep the copy they took
+	sinks := slices.Clone(r.sinks)
+	for i, sink := range sinks {
+		if filter, ok := filters[sink.Name]; ok {
+			sinks[i].Filter = filter
+		}
+	}
+	r.sinks = sinks
+	return nil
+}
+
+// Names returns the names of the registered sinks
+func (r *SinkRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.sinks))
+	for i, sink := range r.sinks {
+		names[i] = sink.Name
+	}
+	return names
+}
+
+func (r *SinkRegistry) PublishNotification(ctx context.Context, notification domain.WalletNotification) error {
+	return r.publish(ctx, "notification", notification.Type, func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishNotification(ctx, notification)
+	})
+}
+
+func (r *SinkRegistry) PublishResponse(ctx context.Context, response domain.CommandResponse) error {
+	return r.publish(ctx, "response", "", func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishResponse(ctx, response)
+	})
+}
+
+func (r *SinkRegistry) PublishCommandResult(ctx context.Context, channel string, result domain.CommandResult) error {
+	return r.publish(ctx, "command_result", "", func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishCommandResult(ctx, channel, result)
+	})
+}
+
+func (r *SinkRegistry) PublishContractEvent(ctx context.Context, notification domain.ContractEventNotification) error {
+	return r.publish(ctx, "contract_event", "", func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishContractEvent(ctx, notification)
+	})
+}
+
+func (r *SinkRegistry) PublishTokenTransfer(ctx context.Context, notification domain.TokenTransferNotification) error {
+	return r.publish(ctx, "token_transfer", "", func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishTokenTransfer(ctx, notification)
+	})
+}
+
+func (r *SinkRegistry) PublishAlert(ctx context.Context, notification domain.AlertNotification) error {
+	return r.publish(ctx, "alert", "", func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishAlert(ctx, notification)
+	})
+}
+
+func (r *SinkRegistry) PublishFirehose(ctx context.Context, block domain.FirehoseBlock) error {
+	return r.publish(ctx, "firehose", "", func(ctx context.Context, p domain.Publisher) error {
+		return p.PublishFirehose(ctx, block)
+	})
+}
+
+// publish hands a message of kind to the matching required sinks, then if
+// they all took it to the matching optional ones
+func (r *SinkRegistry) publish(
+	ctx context.Context,
+	kind string,
+	notificationType domain.NotificationType,
+	fn func(context.Context, domain.Publisher) error,
+) error {
+	r.mu.RLock()
+	sinks := r.sinks
+	r.mu.RUnlock()
+
+	var required, optional []Sink
+	for _, sink := range sinks {
+		if !sink.Filter.matches(kind, notificationType) {
+			continue
+		}
+		if sink.Required {
+			required = append(required, sink)
+		} else {
+			optional = append(optional, sink)
+		}
+	}
+
+	if err := r.publishAll(ctx, kind, required, fn); err != nil {
+		return err
+	}
+	if err := r.publishAll(ctx, kind, optional, fn); err != nil {
+		r.logger.Warn("Failed to publish to optional sinks",
+			zap.String("kind", kind),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// publishAll publishes to sinks concurrently and joins their errors
+func (r *SinkRegistry) publishAll(
+	ctx context.Context,
+	kind string,
+	sinks []Sink,
+	fn func(context.Context, domain.Publisher) error,
+) error {
+	if len(sinks) == 1 {
+		return r.publishTo(ctx, kind, sinks[0], fn)
+	}
+
+	errs := make([]error, len(sinks))
+	var wg sync.WaitGroup
+	for i, sink := range sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = r.publishTo(ctx, kind, sink, fn)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// publishTo publishes to sink until it succeeds or the sink's attempts run
+// out
+func (r *SinkRegistry) publishTo(
+	ctx context.Context,
+	kind string,
+	sink Sink,
+	fn func(context.Context, domain.Publisher) error,
+) error {
+	retry := backoff.New(sink.Retry.MinBackoff, sink.Retry.MaxBackoff)
+	for {
+		err := fn(ctx, sink.Publisher)
+		if err == nil {
+			metrics.SinkPublishesTotal.WithLabelValues(sink.Name, kind, "ok").Inc()
+			return nil
+		}
+
+		if retry.Attempt()+1 >= sink.Retry.MaxAttempts || !retry.Wait(ctx) {
+			metrics.SinkPublishesTotal.WithLabelValues(sink.Name, kind, "failed").Inc()
+			return fmt.Errorf("sink %s: %w", sink.Name, err)
+		}
+		metrics.SinkPublishesTotal.WithLabelValues(sink.Name, kind, "retried").Inc()
+		r.logger.Debug("Retrying publish to sink",
+			zap.String("sink", sink.Name),
+			zap.String("kind", kind),
+			zap.Int("attempt", retry.Attempt()),
+			zap.Error(err),
+		)
+	}
+}
+
+// discardPublisher accepts and drops every message. In-process sinks embed
+// it and override the kinds they handle.
+type discardPublisher struct{}
+
+func (discardPublisher) PublishNotification(context.Context, domain.WalletNotification) error {
+	return nil
+}
+
+func (discardPublisher) PublishResponse(context.Context, domain.CommandResponse) error {
+	return nil
+}
+
+func (discardPublisher) PublishCommandResult(context.Context, string, domain.CommandResult) error {
+	return nil
+}
+
+func (discardPublisher) PublishContractEvent(context.Context, domain.ContractEventNotification) error {
+	return nil
+}
+
+func (discardPublisher) PublishTokenTransfer(context.Context, domain.TokenTransferNotification) error {
+	return nil
+}
+
+func (discardPublisher) PublishAlert(context.Context, domain.AlertNotification) error {
+	return nil
+}
+
+func (discardPublisher) PublishFirehose(context.Context, domain.FirehoseBlock) error {
+	return nil
+}
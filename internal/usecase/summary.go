@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// Span each summary period covers, ending when the summary is sent
+var summaryWindows = map[domain.SummaryPeriod]time.Duration{
+	domain.DailySummary:  24 * time.Hour,
+	domain.WeeklySummary: 7 * 24 * time.Hour,
+}
+
+// SummarySchedule sends summaries of Period when Cron fires. Cron is a
+// standard five-field expression, e.g. "0 9 * * 1", in the service's local
+// time zone unless prefixed with CRON_TZ=<zone>.
+type SummarySchedule struct {
+	Period domain.SummaryPeriod
+	Cron   string
+}
+
+type summarySchedule struct {
+	period   domain.SummaryPeriod
+	window   time.Duration
+	schedule cron.Schedule
+}
+
+// SummaryScheduler sends each user a summary of their wallets' activity on
+// a schedule: transactions, net token flows and the largest transfers of
+// every wallet that had any. It reads the transaction archive, so
+// activity from before a wallet was tracked is left out.
+type SummaryScheduler struct {
+	walletTracker *WalletTracker
+	archive       *TransactionArchive
+	publisher     domain.Publisher
+	schedules     []summarySchedule
+	topTransfers  int // Largest transfers listed per wallet
+	logger        *zap.Logger
+}
+
+func NewSummaryScheduler(
+	walletTracker *WalletTracker,
+	archive *TransactionArchive,
+	publisher domain.Publisher,
+	schedules []SummarySchedule,
+	topTransfers int,
+	logger *zap.Logger,
+) (*SummaryScheduler, error) {
+	ss := &SummaryScheduler{
+		walletTracker: walletTracker,
+		archive:       archive,
+		publisher:     publisher,
+		topTransfers:  max(topTransfers, 0),
+		logger:        logger,
+	}
+
+	for _, s := range schedules {
+		window, ok := summaryWindows[s.Period]
+		if !ok {
+			return nil, fmt.Errorf("unknown summary period %q", s.Period)
+		}
+		schedule, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s summary schedule %q: %w", s.Period, s.Cron, err)
+		}
+		ss.schedules = append(ss.schedules, summarySchedule{
+			period:   s.Period,
+			window:   window,
+			schedule: schedule,
+		})
+	}
+	return ss, nil
+}
+
+// Start sends summaries as their schedules fire until ctx is done
+func (ss *SummaryScheduler) Start(ctx context.Context) {
+	if len(ss.schedules) == 0 {
+		return
+	}
+	if !ss.archive.enabled() {
+		ss.logger.Warn("Activity summaries need transaction storage, not sending them")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range ss.schedules {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ss.run(ctx, s)
+		}()
+	}
+	wg.Wait()
+}
+
+func (ss *SummaryScheduler) run(ctx context.Context, s summarySchedule) {
+	for {
+		next := s.schedule.Next(time.Now())
+		ss.logger.Debug("Next activity summary",
+			zap.String("period", string(s.period)),
+			zap.Time("at", next),
+		)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			ss.send(ctx, s.period, next.Add(-s.window), next)
+		}
+	}
+}
+
+// send publishes the summary of [from, to] to every user whose wallets had
+// activity. Wallets followed by several users are read once.
+func (ss *SummaryScheduler) send(ctx context.Context, period domain.SummaryPeriod, from, to time.Time) {
+	walletsByUser := ss.walletTracker.walletsByUser()
+
+	activity := make(map[domain.WalletAddress]*domain.WalletSummary)
+	for _, wallets := range walletsByUser {
+		for _, walletAddress := range wallets {
+			if _, ok := activity[walletAddress]; ok {
+				continue
+			}
+			summary, err := ss.walletSummary(ctx, walletAddress, from, to)
+			if err != nil {
+				ss.logger.Error("Failed to generate activity summaries",
+					zap.String("period", string(period)),
+					zap.String("wallet", string(walletAddress)),
+					zap.Error(err),
+				)
+				return
+			}
+			activity[walletAddress] = summary
+		}
+	}
+
+	sent := 0
+	for userID, wallets := range walletsByUser {
+		summary := domain.Summary{Period: period, From: from, To: to}
+		for _, walletAddress := range wallets {
+			wallet := activity[walletAddress]
+			if wallet.Transactions == 0 {
+				continue
+			}
+			userWallet := *wallet
+			userWallet.Label = ss.walletTracker.walletLabelsFor(walletAddress, []domain.UserID{userID})[userID]
+			summary.Wallets = append(summary.Wallets, userWallet)
+		}
+		if len(summary.Wallets) == 0 {
+			continue
+		}
+		slices.SortFunc(summary.Wallets, func(a, b domain.WalletSummary) int {
+			return cmp.Or(
+				cmp.Compare(b.Transactions, a.Transactions),
+				cmp.Compare(a.WalletAddress, b.WalletAddress),
+			)
+		})
+
+		if ss.publish(ctx, userID, summary) {
+			sent++
+		}
+	}
+
+	ss.logger.Info("Sent activity summaries",
+		zap.String("period", string(period)),
+		zap.Int("users", sent),
+	)
+}
+
+// walletSummary aggregates the stored activity of the wallet in [from, to]
+func (ss *SummaryScheduler) walletSummary(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	from time.Time,
+	to time.Time,
+) (*domain.WalletSummary, error) {
+	stats, err := ss.archive.Stats(ctx, walletAddress, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.WalletSummary{
+		WalletAddress: walletAddress,
+		Transactions:  stats.Transactions,
+		Failed:        stats.Failed,
+		Flows:         make([]domain.TokenFlow, 0, len(stats.Tokens)),
+	}
+	if stats.Transactions == 0 {
+		return summary, nil
+	}
+
+	for _, total := range stats.Tokens {
+		summary.Flows = append(summary.Flows, tokenFlow(total))
+	}
+
+	summary.LargestTransfers = []domain.Transfer{}
+	if ss.topTransfers > 0 {
+		largest, err := ss.archive.largestTransfers(ctx, walletAddress, from, to, ss.topTransfers)
+		if err != nil {
+			return nil, err
+		}
+		summary.LargestTransfers = append(summary.LargestTransfers, largest...)
+	}
+	return summary, nil
+}
+
+func (ss *SummaryScheduler) publish(ctx context.Context, userID domain.UserID, summary domain.Summary) bool {
+	notification := domain.WalletNotification{
+		Type:          domain.SummaryNotification,
+		SchemaVersion: domain.CurrentSchemaVersion,
+		Subscribers:   []domain.UserID{userID},
+		Timestamp:     time.Now(),
+		Trace:         traceContextFor(ctx),
+		IdempotencyKey: fmt.Sprintf("summary:%d:%s:%d",
+			userID, summary.Period, summary.To.Unix()),
+		Summary: &summary,
+	}
+
+	if err := ss.publisher.PublishNotification(ctx, notification); err != nil {
+		ss.logger.Error("Failed to publish activity summary",
+			zap.Int64("user_id", int64(userID)),
+			zap.String("period", string(summary.Period)),
+			zap.Error(err),
+		)
+		return false
+	}
+	return true
+}
+
+// tokenFlow nets the incoming and outgoing totals of a token
+func tokenFlow(total domain.DigestTokenTotal) domain.TokenFlow {
+	flow := domain.TokenFlow{
+		TokenAddress:  total.TokenAddress,
+		TokenSymbol:   total.TokenSymbol,
+		TokenStandard: total.TokenStandard,
+		Net:           new(big.Int),
+	}
+	if total.ValueIn != nil {
+		flow.Net.Add(flow.Net, total.ValueIn)
+	}
+	if total.ValueOut != nil {
+		flow.Net.Sub(flow.Net, total.ValueOut)
+	}
+
+	if total.ValueInUSD != "" || total.ValueOutUSD != "" {
+		in, _ := strconv.ParseFloat(total.ValueInUSD, 64)
+		out, _ := strconv.ParseFloat(total.ValueOutUSD, 64)
+		flow.NetUSD = strconv.FormatFloat(in-out, 'f', 2, 64)
+	}
+	return flow
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// tokenRule is a compiled token filter entry
+type tokenRule struct {
+	address domain.WalletAddress // Set for address entries, lowercase
+	pattern *regexp.Regexp       // Set for symbol patterns
+}
+
+func (r tokenRule) matches(tokenAddress, symbol string) bool {
+	if r.pattern != nil {
+		return r.pattern.MatchString(symbol)
+	}
+	return labelKey(domain.WalletAddress(tokenAddress)) == r.address
+}
+
+// compiledFilter is a TokenFilter ready for matching
+type compiledFilter struct {
+	source domain.TokenFilter
+	allow  []tokenRule
+	deny   []tokenRule
+}
+
+// verdict returns whether the filter allows or denies the token, or neither
+func (f *compiledFilter) verdict(tokenAddress, symbol string) (allowed, decided bool) {
+	for _, rule := range f.allow {
+		if rule.matches(tokenAddress, symbol) {
+			return true, true
+		}
+	}
+	for _, rule := range f.deny {
+		if rule.matches(tokenAddress, symbol) {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// TokenFilters drops transfers of spam tokens before they are published.
+// Allowlist entries win over denylist entries, and a user's own lists win
+// over the global ones from configuration.
+type TokenFilters struct {
+	repo   domain.TokenFilterRepository
+	logger *zap.Logger
+
+	global *compiledFilter
+	users  map[domain.UserID]*compiledFilter
+	mu     sync.RWMutex
+}
+
+func NewTokenFilters(
+	repo domain.TokenFilterRepository,
+	global domain.TokenFilter,
+	logger *zap.Logger,
+) (*TokenFilters, error) {
+	compiled, err := compileTokenFilter(global)
+	if err != nil {
+		return nil, fmt.Errorf("global token filter: %w", err)
+	}
+
+	return &TokenFilters{
+		repo:   repo,
+		logger: logger,
+		global: compiled,
+		users:  make(map[domain.UserID]*compiledFilter),
+	}, nil
+}
+
+// Load reads all persisted per-user filters into memory
+func (tf *TokenFilters) Load(ctx context.Context) error {
+	filters, err := tf.repo.GetAllTokenFilters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load token filters: %w", err)
+	}
+
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	for userID, filter := range filters {
+		compiled, err := compileTokenFilter(filter)
+		if err != nil {
+			tf.logger.Warn("Skipping invalid token filter",
+				zap.Int64("user_id", int64(userID)),
+				zap.Error(err))
+			continue
+		}
+		tf.users[userID] = compiled
+	}
+
+	tf.logger.Info("Loaded token filters", zap.Int("users", len(tf.users)))
+	return nil
+}
+
+// Add puts entry on the user's allowlist or denylist, taking it off the other
+func (tf *TokenFilters) Add(
+	ctx context.Context,
+	userID domain.UserID,
+	list domain.TokenFilterList,
+	entry string,
+) error {
+	entry = strings.TrimSpace(entry)
+	if _, err := compileTokenRule(entry); err != nil {
+		return err
+	}
+
+	return tf.update(ctx, userID, func(filter *domain.TokenFilter) {
+		filter.Allow = removeEntry(filter.Allow, entry)
+		filter.Deny = removeEntry(filter.Deny, entry)
+
+		switch list {
+		case domain.TokenAllowList:
+			filter.Allow = append(filter.Allow, entry)
+		case domain.TokenDenyList:
+			filter.Deny = append(filter.Deny, entry)
+		}
+	})
+}
+
+// Remove takes entry off both of the user's lists
+func (tf *TokenFilters) Remove(ctx context.Context, userID domain.UserID, entry string) error {
+	entry = strings.TrimSpace(entry)
+	return tf.update(ctx, userID, func(filter *domain.TokenFilter) {
+		filter.Allow = removeEntry(filter.Allow, entry)
+		filter.Deny = removeEntry(filter.Deny, entry)
+	})
+}
+
+func (tf *TokenFilters) update(
+	ctx context.Context,
+	userID domain.UserID,
+	change func(*domain.TokenFilter),
+) error {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	var filter domain.TokenFilter
+	if current, ok := tf.users[userID]; ok {
+		filter.Allow = slices.Clone(current.source.Allow)
+		filter.Deny = slices.Clone(current.source.Deny)
+	}
+	change(&filter)
+
+	compiled, err := compileTokenFilter(filter)
+	if err != nil {
+		return err
+	}
+	if err := tf.repo.SaveTokenFilter(ctx, userID, filter); err != nil {
+		return fmt.Errorf("failed to store token filter: %w", err)
+	}
+
+	tf.users[userID] = compiled
+	return nil
+}
+
+// setGlobal replaces the global filter from configuration
+func (tf *TokenFilters) setGlobal(global *compiledFilter) {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	tf.global = global
+}
+
+// Status returns the global filter and the user's own
+func (tf *TokenFilters) Status(userID domain.UserID) domain.TokenFilterStatus {
+	tf.mu.RLock()
+	defer tf.mu.RUnlock()
+
+	status := domain.TokenFilterStatus{Global: tf.global.source}
+	if filter, ok := tf.users[userID]; ok {
+		status.User = filter.source
+	}
+	return status
+}
+
+// Allows reports whether transfers of the token should reach the user
+func (tf *TokenFilters) Allows(userID domain.UserID, tokenAddress, symbol string) bool {
+	tf.mu.RLock()
+	defer tf.mu.RUnlock()
+
+	if filter, ok := tf.users[userID]; ok {
+		if allowed, decided := filter.verdict(tokenAddress, symbol); decided {
+			return allowed
+		}
+	}
+	if allowed, decided := tf.global.verdict(tokenAddress, symbol); decided {
+		return allowed
+	}
+	return true
+}
+
+// Apply returns tx without the transfers and approvals of tokens the user
+// filters out. Native transfers are never filtered.
+func (tf *TokenFilters) Apply(userID domain.UserID, tx domain.Transaction) domain.Transaction {
+	transfers := make([]domain.Transfer, 0, len(tx.Transfers))
+	for _, transfer := range tx.Transfers {
+		if transfer.TokenStandard == domain.NativeToken ||
+			tf.Allows(userID, transfer.TokenAddress, transfer.TokenSymbol) {
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	approvals := make([]domain.Approval, 0, len(tx.Approvals))
+	for _, approval := range tx.Approvals {
+		if tf.Allows(userID, approval.TokenAddress, approval.TokenSymbol) {
+			approvals = append(approvals, approval)
+		}
+	}
+
+	tx.Transfers = transfers
+	tx.Approvals = approvals
+	return tx
+}
+
+func compileTokenFilter(filter domain.TokenFilter) (*compiledFilter, error) {
+	compiled := &compiledFilter{source: filter}
+
+	for _, entry := range filter.Allow {
+		rule, err := compileTokenRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		compiled.allow = append(compiled.allow, rule)
+	}
+	for _, entry := range filter.Deny {
+		rule, err := compileTokenRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		compiled.deny = append(compiled.deny, rule)
+	}
+
+	return compiled, nil
+}
+
+// compileTokenRule turns an address or symbol pattern into a rule
+func compileTokenRule(entry string) (tokenRule, error) {
+	if entry == "" {
+		return tokenRule{}, fmt.Errorf("%w: empty entry", domain.ErrInvalidTokenFilter)
+	}
+
+	if strings.HasPrefix(strings.ToLower(entry), "0x") {
+		if !common.IsHexAddress(entry) {
+			return tokenRule{}, fmt.Errorf("%w: %q", domain.ErrInvalidAddress, entry)
+		}
+		return tokenRule{address: labelKey(domain.WalletAddress(entry))}, nil
+	}
+
+	pattern := regexp.QuoteMeta(entry)
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	compiled, err := regexp.Compile("(?i)^" + pattern + "$")
+	if err != nil {
+		return tokenRule{}, fmt.Errorf("%w: %v", domain.ErrInvalidTokenFilter, err)
+	}
+	return tokenRule{pattern: compiled}, nil
+}
+
+func removeEntry(entries []string, entry string) []string {
+	return slices.DeleteFunc(entries, func(e string) bool {
+		return strings.EqualFold(e, entry)
+	})
+}
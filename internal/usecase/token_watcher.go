@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// TokenWatcher follows every transfer of tokens users track. Users tracking
+// the same token share its listener, each with their own minimum amount.
+type TokenWatcher struct {
+	source    domain.TokenTransferSource
+	publisher domain.Publisher
+	repo      domain.TokenSubscriptionRepository
+	labels    *LabelRegistry
+	audit     *AuditTrail
+	logger    *zap.Logger
+
+	// Token -> user -> subscription
+	subscriptions map[domain.WalletAddress]map[domain.UserID]domain.TokenSubscription
+	// Active listeners map: token -> listener context
+	listeners map[domain.WalletAddress]context.CancelFunc
+	mu        sync.Mutex
+}
+
+func NewTokenWatcher(
+	source domain.TokenTransferSource,
+	publisher domain.Publisher,
+	repo domain.TokenSubscriptionRepository,
+	labels *LabelRegistry,
+	audit *AuditTrail,
+	logger *zap.Logger,
+) *TokenWatcher {
+	return &TokenWatcher{
+		source:        source,
+		publisher:     publisher,
+		repo:          repo,
+		labels:        labels,
+		audit:         audit,
+		logger:        logger,
+		subscriptions: make(map[domain.WalletAddress]map[domain.UserID]domain.TokenSubscription),
+		listeners:     make(map[domain.WalletAddress]context.CancelFunc),
+	}
+}
+
+func (tw *TokenWatcher) Start(ctx context.Context) {
+	tw.logger.Info("Starting token watcher")
+
+	if err := tw.restoreSubscriptions(ctx); err != nil {
+		tw.logger.Error("Failed to restore token subscriptions", zap.Error(err))
+	}
+
+	<-ctx.Done()
+	tw.logger.Info("Stopping token watcher")
+	tw.stopAllListeners()
+}
+
+// ListenerCount returns the number of running token listeners
+func (tw *TokenWatcher) ListenerCount() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	return len(tw.listeners)
+}
+
+// Track starts following transfers of the token for the user, replacing the
+// user's previous minimum amount if the token is already tracked
+func (tw *TokenWatcher) Track(ctx context.Context, subscription domain.TokenSubscription) error {
+	if _, err := parseTokenAmount(subscription.MinAmount); err != nil {
+		return err
+	}
+	subscription.CreatedAt = time.Now()
+
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	token := subscription.TokenAddress
+	started := false
+	if _, exists := tw.listeners[token]; !exists {
+		if err := tw.startListener(token); err != nil {
+			return err
+		}
+		started = true
+	}
+
+	if err := tw.repo.AddTokenSubscription(ctx, subscription); err != nil {
+		if started {
+			tw.stopListener(token)
+		}
+		return fmt.Errorf("failed to persist token subscription: %w", err)
+	}
+
+	_, existed := tw.subscriptions[token][subscription.UserID]
+	tw.addSubscription(subscription)
+	if !existed {
+		tw.audit.recordSubscription(ctx, domain.AuditSubscriptionAdded, domain.TokenSubscriptionKind,
+			token, subscription.UserID, "")
+	}
+	return nil
+}
+
+// Untrack stops following the token for the user
+func (tw *TokenWatcher) Untrack(
+	ctx context.Context,
+	tokenAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if err := tw.repo.RemoveTokenSubscription(ctx, tokenAddress, userID); err != nil {
+		return fmt.Errorf("failed to remove token subscription: %w", err)
+	}
+
+	if _, existed := tw.subscriptions[tokenAddress][userID]; existed {
+		tw.audit.recordSubscription(ctx, domain.AuditSubscriptionRemoved, domain.TokenSubscriptionKind,
+			tokenAddress, userID, "")
+	}
+	delete(tw.subscriptions[tokenAddress], userID)
+	if len(tw.subscriptions[tokenAddress]) == 0 {
+		delete(tw.subscriptions, tokenAddress)
+		tw.stopListener(tokenAddress)
+	}
+
+	return nil
+}
+
+// restoreSubscriptions loads persisted token subscriptions and restarts
+// their listeners
+func (tw *TokenWatcher) restoreSubscriptions(ctx context.Context) error {
+	subscriptions, err := tw.repo.GetTokenSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		if _, exists := tw.listeners[subscription.TokenAddress]; !exists {
+			if err := tw.startListener(subscription.TokenAddress); err != nil {
+				tw.logger.Error("Failed to restore token subscription",
+					zap.String("token", string(subscription.TokenAddress)),
+					zap.Int64("user_id", int64(subscription.UserID)),
+					zap.Error(err),
+				)
+				continue
+			}
+		}
+		tw.addSubscription(subscription)
+	}
+
+	tw.logger.Info("Restored token subscriptions",
+		zap.Int("subscriptions", len(subscriptions)),
+		zap.Int("tokens", len(tw.listeners)),
+	)
+	return nil
+}
+
+// addSubscription records subscription. Caller must hold tw.mu.
+func (tw *TokenWatcher) addSubscription(subscription domain.TokenSubscription) {
+	users, exists := tw.subscriptions[subscription.TokenAddress]
+	if !exists {
+		users = make(map[domain.UserID]domain.TokenSubscription)
+		tw.subscriptions[subscription.TokenAddress] = users
+	}
+	users[subscription.UserID] = subscription
+}
+
+// startListener subscribes to transfers of the token and publishes them in
+// the background. Caller must hold tw.mu.
+func (tw *TokenWatcher) startListener(token domain.WalletAddress) error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := tw.source.SubscribeToToken(ctx, token)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	tw.listeners[token] = cancel
+	go tw.listen(ctx, events)
+	metrics.ActiveListeners.WithLabelValues("token").Set(float64(len(tw.listeners)))
+
+	tw.logger.Info("Started listener for token", zap.String("token", string(token)))
+	return nil
+}
+
+// stopListener cancels the token's listener. Caller must hold tw.mu.
+func (tw *TokenWatcher) stopListener(token domain.WalletAddress) {
+	if cancel, exists := tw.listeners[token]; exists {
+		cancel()
+		delete(tw.listeners, token)
+		metrics.ActiveListeners.WithLabelValues("token").Set(float64(len(tw.listeners)))
+		tw.logger.Info("Stopped listener for token", zap.String("token", string(token)))
+	}
+}
+
+func (tw *TokenWatcher) listen(ctx context.Context, events <-chan domain.TokenTransferEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			tw.publishTransfer(ctx, event)
+		}
+	}
+}
+
+func (tw *TokenWatcher) publishTransfer(ctx context.Context, event domain.TokenTransferEvent) {
+	subscribers := tw.subscribersFor(event.Transfer)
+	if len(subscribers) == 0 {
+		return
+	}
+
+	transfers := []domain.Transfer{event.Transfer}
+	tw.labels.Annotate(transfers)
+	event.Transfer = transfers[0]
+
+	notification := domain.TokenTransferNotification{
+		Event:       event,
+		Subscribers: subscribers,
+		Timestamp:   time.Now(),
+		Trace:       traceContextFor(ctx),
+	}
+
+	if err := tw.publisher.PublishTokenTransfer(ctx, notification); err != nil {
+		tw.logger.Error("Failed to publish token transfer",
+			zap.String("token", event.Transfer.TokenAddress),
+			zap.String("tx_hash", string(event.Transfer.TxHash)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	tw.logger.Info("Published token transfer",
+		zap.String("token", event.Transfer.TokenAddress),
+		zap.String("tx_hash", string(event.Transfer.TxHash)),
+		zap.Int("subscribers", len(subscribers)),
+		zap.String("trace_id", traceID(notification.Trace)),
+	)
+}
+
+// subscribersFor returns the users tracking the transfer's token whose
+// minimum amount it reaches, in ascending order
+func (tw *TokenWatcher) subscribersFor(transfer domain.Transfer) []domain.UserID {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	// Amounts are compared in token units, unknown without the decimals
+	amount, _ := parseTokenAmount(transfer.FormattedValue)
+
+	var subscribers []domain.UserID
+	for userID, subscription := range tw.subscriptions[domain.WalletAddress(transfer.TokenAddress)] {
+		// Validated when the subscription was added
+		minAmount, _ := parseTokenAmount(subscription.MinAmount)
+		if minAmount != nil && (amount == nil || amount.Cmp(minAmount) < 0) {
+			continue
+		}
+		subscribers = append(subscribers, userID)
+	}
+
+	slices.Sort(subscribers)
+	return subscribers
+}
+
+func (tw *TokenWatcher) stopAllListeners() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	for _, cancel := range tw.listeners {
+		cancel()
+	}
+	tw.listeners = make(map[domain.WalletAddress]context.CancelFunc)
+	metrics.ActiveListeners.WithLabelValues("token").Set(float64(len(tw.listeners)))
+}
+
+// parseTokenAmount parses an amount in token units; "" gives nil
+func parseTokenAmount(amount string) (*big.Float, error) {
+	if amount == "" {
+		return nil, nil
+	}
+
+	value, ok := new(big.Float).SetString(amount)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: invalid min_amount %q", domain.ErrInvalidFilter, amount)
+	}
+	return value, nil
+}
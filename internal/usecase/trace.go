@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceVersion = "00"
+	traceSampled = "01"
+)
+
+// newTraceContext starts a new sampled root trace
+func newTraceContext() *domain.TraceContext {
+	return &domain.TraceContext{
+		Traceparent: formatTraceparent(randomHex(16), randomHex(8), traceSampled),
+	}
+}
+
+// traceContextFor continues the span in ctx, starting a new root trace when
+// there is none
+func traceContextFor(ctx context.Context) *domain.TraceContext {
+	if tc := tracing.Inject(ctx); tc != nil {
+		return tc
+	}
+	return newTraceContext()
+}
+
+// traceID returns the trace id of tc or an empty string if it is invalid
+func traceID(tc *domain.TraceContext) string {
+	sc := trace.SpanContextFromContext(tracing.Extract(context.Background(), tc))
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+func formatTraceparent(traceID, spanID, flags string) string {
+	return traceVersion + "-" + traceID + "-" + spanID + "-" + flags
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// TransactionArchive stores the transactions published for tracked wallets
+// so their history is read without rescanning the chain. A wallet's
+// coverage starts at the block after the last processed one when its
+// listener starts and ends when it stops; transactions dropped under load
+// are missing from it as from notifications. Storing is best effort and a
+// nil repository disables the archive.
+type TransactionArchive struct {
+	repo   domain.TransactionRepository
+	logger *zap.Logger
+}
+
+func NewTransactionArchive(repo domain.TransactionRepository, logger *zap.Logger) *TransactionArchive {
+	return &TransactionArchive{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (ta *TransactionArchive) enabled() bool {
+	return ta != nil && ta.repo != nil
+}
+
+// record stores a transaction published for the wallet
+func (ta *TransactionArchive) record(ctx context.Context, walletAddress domain.WalletAddress, tx domain.Transaction) {
+	if !ta.enabled() {
+		return
+	}
+
+	if err := ta.repo.SaveTransaction(ctx, walletAddress, tx); err != nil {
+		ta.logger.Error("Failed to store transaction",
+			zap.String("wallet", string(walletAddress)),
+			zap.String("tx_hash", string(tx.Hash)),
+			zap.Error(err),
+		)
+	}
+}
+
+// forget removes transactions of the wallet undone by a reorg
+func (ta *TransactionArchive) forget(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	hashes []domain.TransactionHash,
+) {
+	if !ta.enabled() {
+		return
+	}
+
+	if err := ta.repo.DeleteTransactions(ctx, walletAddress, hashes); err != nil {
+		ta.logger.Error("Failed to delete reorged transactions",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+	}
+}
+
+// track starts the wallet's coverage at fromBlock unless it is covered
+// already
+func (ta *TransactionArchive) track(ctx context.Context, walletAddress domain.WalletAddress, fromBlock uint64) {
+	if !ta.enabled() {
+		return
+	}
+
+	if err := ta.repo.StartCoverage(ctx, walletAddress, fromBlock); err != nil {
+		ta.logger.Error("Failed to start storing wallet transactions",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+	}
+}
+
+// untrack ends the wallet's coverage; its stored transactions are kept
+func (ta *TransactionArchive) untrack(ctx context.Context, walletAddress domain.WalletAddress) {
+	if !ta.enabled() {
+		return
+	}
+
+	if err := ta.repo.StopCoverage(ctx, walletAddress); err != nil {
+		ta.logger.Error("Failed to stop storing wallet transactions",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+	}
+}
+
+// history returns the wallet's stored transactions in [fromBlock, toBlock]
+// like GetAddressHistory. It reports false if the archive doesn't cover the
+// whole range or can't be read, and the chain must be scanned instead.
+func (ta *TransactionArchive) history(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	fromBlock uint64,
+	toBlock uint64,
+	limit int,
+) ([]domain.Transaction, bool) {
+	if !ta.enabled() {
+		return nil, false
+	}
+
+	since, covered, err := ta.repo.Coverage(ctx, walletAddress)
+	if err != nil {
+		ta.logger.Warn("Failed to get stored transaction coverage",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return nil, false
+	}
+	if !covered || fromBlock < since {
+		return nil, false
+	}
+
+	txs, err := ta.repo.ListTransactions(ctx, walletAddress, fromBlock, toBlock, limit)
+	if err != nil {
+		ta.logger.Warn("Failed to read stored transactions",
+			zap.String("wallet", string(walletAddress)),
+			zap.Error(err),
+		)
+		return nil, false
+	}
+	return txs, true
+}
+
+// Stats aggregates the stored transactions of address with block times in
+// [since, until]; zero bounds are open. Transactions before the wallet was
+// tracked, or while it wasn't, are not counted.
+func (ta *TransactionArchive) Stats(
+	ctx context.Context,
+	address domain.WalletAddress,
+	since time.Time,
+	until time.Time,
+) (*domain.WalletStats, error) {
+	address, err := NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if !ta.enabled() {
+		return nil, fmt.Errorf("%w: transaction storage is not configured", domain.ErrStatsUnavailable)
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return nil, fmt.Errorf("%w: until is before since", domain.ErrInvalidDuration)
+	}
+
+	stats, err := ta.repo.WalletStats(ctx, address, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet stats: %w", err)
+	}
+	return stats, nil
+}
+
+// largestTransfers returns up to limit of the wallet's stored transfers
+// with block times in [since, until], largest first
+func (ta *TransactionArchive) largestTransfers(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	since time.Time,
+	until time.Time,
+	limit int,
+) ([]domain.Transfer, error) {
+	if !ta.enabled() {
+		return nil, domain.ErrStatsUnavailable
+	}
+
+	transfers, err := ta.repo.LargestTransfers(ctx, walletAddress, since, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get largest transfers: %w", err)
+	}
+	return transfers, nil
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/backoff"
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+// What TransferAnalytics does with transfers that don't fit in its queue
+const (
+	BackpressureDrop  = "drop"  // Lose them, block processing never waits
+	BackpressureBlock = "block" // Hold up block processing until there is room
+)
+
+// Delay before retrying a failed write, doubled for each one after up to
+// the max
+const (
+	analyticsRetryDelay    = time.Second
+	maxAnalyticsRetryDelay = 30 * time.Second
+)
+
+// TransferAnalyticsConfig tunes how transfers are batched into the
+// analytics store
+type TransferAnalyticsConfig struct {
+	BatchSize     int           // Transfers per write at most
+	FlushInterval time.Duration // How long a partial batch waits before it is written
+	QueueSize     int           // Transfers waiting to be batched
+	Backpressure  string        // BackpressureDrop or BackpressureBlock
+	MaxAttempts   int           // Attempts per write, including the first
+}
+
+// TransferAnalytics is a transfer sink batching the transfers of processed
+// blocks into an analytics store. A single writer keeps inserts few and
+// large; while it is slow or retrying, transfers queue up and past the
+// queue's size are dropped or hold up block processing, as configured.
+type TransferAnalytics struct {
+	writer domain.TransferWriter
+	config TransferAnalyticsConfig
+	queue  chan domain.TransferRecord
+	logger *zap.Logger
+
+	// Transfers taken off the queue and not written yet
+	pending atomic.Int64
+}
+
+func NewTransferAnalytics(
+	writer domain.TransferWriter,
+	config TransferAnalyticsConfig,
+	logger *zap.Logger,
+) (*TransferAnalytics, error) {
+	switch config.Backpressure {
+	case BackpressureDrop, BackpressureBlock:
+	default:
+		return nil, fmt.Errorf("unknown analytics backpressure %q", config.Backpressure)
+	}
+	if config.BatchSize <= 0 || config.FlushInterval <= 0 {
+		return nil, fmt.Errorf("analytics batch size and flush interval must be positive")
+	}
+
+	return &TransferAnalytics{
+		writer: writer,
+		config: config,
+		queue:  make(chan domain.TransferRecord, config.QueueSize),
+		logger: logger,
+	}, nil
+}
+
+// Receive queues records to be written. If the queue fills up, the rest
+// are dropped or waited for until ctx is done.
+func (ta *TransferAnalytics) Receive(ctx context.Context, records []domain.TransferRecord) {
+	for i, record := range records {
+		if ta.config.Backpressure == BackpressureBlock {
+			select {
+			case ta.queue <- record:
+			case <-ctx.Done():
+				ta.drop(records[i:])
+				return
+			}
+		} else {
+			select {
+			case ta.queue <- record:
+			default:
+				ta.drop(records[i:])
+				return
+			}
+		}
+		metrics.AnalyticsQueueDepth.Inc()
+	}
+}
+
+func (ta *TransferAnalytics) drop(records []domain.TransferRecord) {
+	metrics.AnalyticsTransfersTotal.WithLabelValues("queue_full").Add(float64(len(records)))
+	ta.logger.Warn("Analytics queue full, dropping transfers",
+		zap.Uint64("block", records[0].BlockNumber),
+		zap.Int("transfers", len(records)),
+	)
+}
+
+// Start writes queued transfers in batches until ctx is done. Transfers
+// not written by then are lost.
+func (ta *TransferAnalytics) Start(ctx context.Context) {
+	ticker := time.NewTicker(ta.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.TransferRecord, 0, ta.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ta.write(ctx, batch)
+		batch = make([]domain.TransferRecord, 0, ta.config.BatchSize)
+		ta.pending.Store(0)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if lost := len(batch) + len(ta.queue); lost > 0 {
+				metrics.AnalyticsTransfersTotal.WithLabelValues("failed").Add(float64(lost))
+				ta.logger.Warn("Transfers not written to analytics before shutdown", zap.Int("transfers", lost))
+			}
+			return
+		case record := <-ta.queue:
+			metrics.AnalyticsQueueDepth.Dec()
+			ta.pending.Add(1)
+			batch = append(batch, record)
+			if len(batch) >= ta.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Drain waits until queued transfers are written or ctx is done. Call it
+// once blocks aren't processed anymore.
+func (ta *TransferAnalytics) Drain(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for len(ta.queue) > 0 || ta.pending.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// write stores batch, retrying with exponential backoff up to the
+// configured attempts
+func (ta *TransferAnalytics) write(ctx context.Context, batch []domain.TransferRecord) {
+	wait := backoff.New(analyticsRetryDelay, maxAnalyticsRetryDelay)
+	for attempt := 1; ; attempt++ {
+		err := ta.writer.WriteTransfers(ctx, batch)
+		if err == nil {
+			metrics.AnalyticsTransfersTotal.WithLabelValues("ok").Add(float64(len(batch)))
+			return
+		}
+
+		if attempt < ta.config.MaxAttempts {
+			ta.logger.Warn("Failed to write transfers to analytics, retrying",
+				zap.Int("transfers", len(batch)),
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
+			if wait.Wait(ctx) {
+				continue
+			}
+		}
+
+		metrics.AnalyticsTransfersTotal.WithLabelValues("failed").Add(float64(len(batch)))
+		ta.logger.Error("Failed to write transfers to analytics",
+			zap.Int("transfers", len(batch)),
+			zap.Int("attempts", attempt),
+			zap.Error(err),
+		)
+		return
+	}
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// UserLimits caps how many wallets each user may follow. Admins can
+// override the default cap per user, e.g. for a paid tier.
+type UserLimits struct {
+	repo         domain.WalletLimitRepository
+	defaultLimit int
+	admins       map[domain.UserID]struct{}
+	overrides    map[domain.UserID]int
+	mu           sync.RWMutex
+	logger       *zap.Logger
+}
+
+// NewUserLimits creates the limits; a zero defaultLimit means unlimited
+func NewUserLimits(
+	repo domain.WalletLimitRepository,
+	defaultLimit int,
+	admins []int64,
+	logger *zap.Logger,
+) *UserLimits {
+	ul := &UserLimits{
+		repo:         repo,
+		defaultLimit: max(defaultLimit, 0),
+		admins:       make(map[domain.UserID]struct{}, len(admins)),
+		overrides:    make(map[domain.UserID]int),
+		logger:       logger,
+	}
+	for _, id := range admins {
+		ul.admins[domain.UserID(id)] = struct{}{}
+	}
+	return ul
+}
+
+// Load reads the persisted overrides into memory
+func (ul *UserLimits) Load(ctx context.Context) error {
+	overrides, err := ul.repo.GetAllWalletLimits(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet limits: %w", err)
+	}
+
+	ul.mu.Lock()
+	ul.overrides = overrides
+	ul.mu.Unlock()
+
+	ul.logger.Info("Loaded wallet limits",
+		zap.Int("default", ul.defaultLimit),
+		zap.Int("overrides", len(overrides)),
+	)
+	return nil
+}
+
+// Get returns the wallet limit that applies to the user
+func (ul *UserLimits) Get(userID domain.UserID) domain.WalletLimit {
+	ul.mu.RLock()
+	defer ul.mu.RUnlock()
+
+	if limit, ok := ul.overrides[userID]; ok {
+		return domain.WalletLimit{UserID: userID, Limit: limit, Override: true}
+	}
+	return domain.WalletLimit{UserID: userID, Limit: ul.defaultLimit}
+}
+
+// IsAdmin reports whether the user may run admin commands
+func (ul *UserLimits) IsAdmin(userID domain.UserID) bool {
+	_, ok := ul.admins[userID]
+	return ok
+}
+
+// Check returns domain.ErrLimitExceeded if a user following current
+// wallets may not follow adding more
+func (ul *UserLimits) Check(userID domain.UserID, current, adding int) error {
+	limit := ul.Get(userID).Limit
+	if limit == 0 || current+adding <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: user %d may follow at most %d wallets", domain.ErrLimitExceeded, userID, limit)
+}
+
+// Set overrides the limit of target on behalf of admin. A nil limit
+// restores the default; zero lifts the cap.
+func (ul *UserLimits) Set(
+	ctx context.Context,
+	admin domain.UserID,
+	target domain.UserID,
+	limit *int,
+) (domain.WalletLimit, error) {
+	if !ul.IsAdmin(admin) {
+		return domain.WalletLimit{}, fmt.Errorf("%w: user %d is not an admin", domain.ErrForbidden, admin)
+	}
+	if limit != nil && *limit < 0 {
+		return domain.WalletLimit{}, fmt.Errorf("%w: negative wallet limit %d", domain.ErrInvalidLimit, *limit)
+	}
+
+	ul.mu.Lock()
+	if limit == nil {
+		if err := ul.repo.DeleteWalletLimit(ctx, target); err != nil {
+			ul.mu.Unlock()
+			return domain.WalletLimit{}, fmt.Errorf("failed to delete wallet limit: %w", err)
+		}
+		delete(ul.overrides, target)
+	} else {
+		if err := ul.repo.SetWalletLimit(ctx, target, *limit); err != nil {
+			ul.mu.Unlock()
+			return domain.WalletLimit{}, fmt.Errorf("failed to save wallet limit: %w", err)
+		}
+		ul.overrides[target] = *limit
+	}
+	ul.mu.Unlock()
+
+	updated := ul.Get(target)
+	ul.logger.Info("Wallet limit changed",
+		zap.Int64("admin", int64(admin)),
+		zap.Int64("user_id", int64(target)),
+		zap.Int("limit", updated.Limit),
+		zap.Bool("override", updated.Override),
+	)
+	return updated, nil
+}
@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Maximum length of a group name
+	maxGroupNameLength = 64
+	// Maximum number of groups a user may create
+	maxGroupsPerUser = 50
+)
+
+// WalletGroups lets users organize their wallets into named groups, which
+// tag notifications and can be paused or summarized as a whole. Wallets
+// stay in their groups when unsubscribed, so re-adding one restores it.
+type WalletGroups struct {
+	repo   domain.WalletGroupRepository
+	logger *zap.Logger
+
+	// User -> lowercase group name -> group
+	groups map[domain.UserID]map[string]*domain.WalletGroup
+	mu     sync.RWMutex
+}
+
+func NewWalletGroups(repo domain.WalletGroupRepository, logger *zap.Logger) *WalletGroups {
+	return &WalletGroups{
+		repo:   repo,
+		logger: logger,
+		groups: make(map[domain.UserID]map[string]*domain.WalletGroup),
+	}
+}
+
+// Load reads all persisted groups into memory
+func (wg *WalletGroups) Load(ctx context.Context) error {
+	groups, err := wg.repo.GetAllWalletGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load wallet groups: %w", err)
+	}
+
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	for _, group := range groups {
+		wg.put(group)
+	}
+
+	wg.logger.Info("Loaded wallet groups", zap.Int("groups", len(groups)))
+	return nil
+}
+
+// Create adds an empty group for the user
+func (wg *WalletGroups) Create(ctx context.Context, userID domain.UserID, name string) (*domain.WalletGroup, error) {
+	name, err := normalizeGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	if _, exists := wg.groups[userID][strings.ToLower(name)]; exists {
+		return nil, fmt.Errorf("%w: group %q already exists", domain.ErrInvalidGroup, name)
+	}
+	if len(wg.groups[userID]) >= maxGroupsPerUser {
+		return nil, fmt.Errorf("%w: at most %d groups per user", domain.ErrInvalidGroup, maxGroupsPerUser)
+	}
+
+	group := domain.WalletGroup{
+		Name:      name,
+		UserID:    userID,
+		Wallets:   []domain.WalletAddress{},
+		CreatedAt: time.Now(),
+	}
+	if err := wg.repo.SaveWalletGroup(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to persist wallet group: %w", err)
+	}
+	wg.put(group)
+
+	return &group, nil
+}
+
+// Delete removes the user's group; its wallets stay subscribed
+func (wg *WalletGroups) Delete(ctx context.Context, userID domain.UserID, name string) error {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	group, err := wg.find(userID, name)
+	if err != nil {
+		return err
+	}
+
+	if err := wg.repo.DeleteWalletGroup(ctx, userID, group.Name); err != nil {
+		return fmt.Errorf("failed to delete wallet group: %w", err)
+	}
+	delete(wg.groups[userID], strings.ToLower(group.Name))
+	if len(wg.groups[userID]) == 0 {
+		delete(wg.groups, userID)
+	}
+
+	return nil
+}
+
+// AddWallets puts wallets into the user's group, ignoring those already in it
+func (wg *WalletGroups) AddWallets(
+	ctx context.Context,
+	userID domain.UserID,
+	name string,
+	wallets []domain.WalletAddress,
+) (*domain.WalletGroup, error) {
+	return wg.update(ctx, userID, name, func(group *domain.WalletGroup) {
+		for _, wallet := range wallets {
+			if !slices.Contains(group.Wallets, wallet) {
+				group.Wallets = append(group.Wallets, wallet)
+			}
+		}
+	})
+}
+
+// RemoveWallets takes wallets out of the user's group
+func (wg *WalletGroups) RemoveWallets(
+	ctx context.Context,
+	userID domain.UserID,
+	name string,
+	wallets []domain.WalletAddress,
+) (*domain.WalletGroup, error) {
+	return wg.update(ctx, userID, name, func(group *domain.WalletGroup) {
+		group.Wallets = slices.DeleteFunc(group.Wallets, func(wallet domain.WalletAddress) bool {
+			return slices.Contains(wallets, wallet)
+		})
+	})
+}
+
+// Get returns a copy of the user's group
+func (wg *WalletGroups) Get(userID domain.UserID, name string) (*domain.WalletGroup, error) {
+	wg.mu.RLock()
+	defer wg.mu.RUnlock()
+
+	group, err := wg.find(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := *group
+	copied.Wallets = slices.Clone(group.Wallets)
+	return &copied, nil
+}
+
+// List returns the user's groups sorted by name
+func (wg *WalletGroups) List(userID domain.UserID) []domain.WalletGroup {
+	wg.mu.RLock()
+	defer wg.mu.RUnlock()
+
+	groups := make([]domain.WalletGroup, 0, len(wg.groups[userID]))
+	for _, group := range wg.groups[userID] {
+		copied := *group
+		copied.Wallets = slices.Clone(group.Wallets)
+		groups = append(groups, copied)
+	}
+	slices.SortFunc(groups, func(a, b domain.WalletGroup) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return groups
+}
+
+// groupsFor returns the names of the groups each subscriber put the wallet
+// in, or nil if none did
+func (wg *WalletGroups) groupsFor(
+	walletAddress domain.WalletAddress,
+	subscribers []domain.UserID,
+) map[domain.UserID][]string {
+	wg.mu.RLock()
+	defer wg.mu.RUnlock()
+
+	var names map[domain.UserID][]string
+	for _, userID := range subscribers {
+		for _, group := range wg.groups[userID] {
+			if !slices.Contains(group.Wallets, walletAddress) {
+				continue
+			}
+			if names == nil {
+				names = make(map[domain.UserID][]string)
+			}
+			names[userID] = append(names[userID], group.Name)
+		}
+	}
+	for _, groups := range names {
+		slices.Sort(groups)
+	}
+
+	return names
+}
+
+// update applies change to a copy of the user's group, persists it and then
+// swaps it in
+func (wg *WalletGroups) update(
+	ctx context.Context,
+	userID domain.UserID,
+	name string,
+	change func(group *domain.WalletGroup),
+) (*domain.WalletGroup, error) {
+	wg.mu.Lock()
+	defer wg.mu.Unlock()
+
+	group, err := wg.find(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *group
+	updated.Wallets = slices.Clone(group.Wallets)
+	change(&updated)
+
+	if err := wg.repo.SaveWalletGroup(ctx, updated); err != nil {
+		return nil, fmt.Errorf("failed to persist wallet group: %w", err)
+	}
+	wg.put(updated)
+
+	result := updated
+	result.Wallets = slices.Clone(updated.Wallets)
+	return &result, nil
+}
+
+// find looks up the user's group by name, ignoring case. Caller must hold
+// wg.mu.
+func (wg *WalletGroups) find(userID domain.UserID, name string) (*domain.WalletGroup, error) {
+	group, exists := wg.groups[userID][strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", domain.ErrGroupNotFound, name)
+	}
+	return group, nil
+}
+
+// put stores group in memory. Caller must hold wg.mu.
+func (wg *WalletGroups) put(group domain.WalletGroup) {
+	groups, exists := wg.groups[group.UserID]
+	if !exists {
+		groups = make(map[string]*domain.WalletGroup)
+		wg.groups[group.UserID] = groups
+	}
+	groups[strings.ToLower(group.Name)] = &group
+}
+
+// normalizeGroupName trims name and checks it is usable
+func normalizeGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", domain.ErrInvalidGroup)
+	}
+	if utf8.RuneCountInString(name) > maxGroupNameLength {
+		return "", fmt.Errorf("%w: name longer than %d characters", domain.ErrInvalidGroup, maxGroupNameLength)
+	}
+	return name, nil
+}
@@ -2,78 +2,358 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+	"github.com/say8hi/plasma-wallet-tracker/internal/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
+// Longest name a user may give a wallet
+const maxWalletLabelLength = 64
+
 type WalletTracker struct {
 	blockchainClient domain.BlockchainClient
 	publisher        domain.Publisher
-	logger           *zap.Logger
+	repo             domain.WalletRepository
+	labels           *LabelRegistry
+	tokenFilters     *TokenFilters
+	prices           *PriceService
+	alerts           *AlertEngine
+	gasAnalytics     *GasAnalytics
+	guard            *ListenerGuard
+	limits           *UserLimits
+	groups           *WalletGroups
+	audit            *AuditTrail
+	archive          *TransactionArchive
+	confirmations    atomic.Pointer[ConfirmationPolicy]
+	backfillBlocks   uint64
+	// Instances the wallets are sharded between; nil when not sharded
+	shards *ShardCoordinator
+	logger *zap.Logger
 
+	// Context of the running tracker, parent of every listener context;
+	// nil until Start
+	ctx context.Context
+	// Closed once persisted subscriptions are restored on Start
+	restored chan struct{}
 	// Active listeners map: wallet address -> listener context
 	listeners map[domain.WalletAddress]context.CancelFunc
+	// Running listener and backfill goroutines, waited for on shutdown
+	listenerWG sync.WaitGroup
+	// Slots of backfills allowed to run at once
+	backfillSlots chan struct{}
+	// Backfills requested before Start, run once it begins
+	pendingBackfills []subscriptionKey
 	// Subscribers map: wallet address -> list of user IDs
 	subscribers map[domain.WalletAddress][]domain.UserID
-	mu          sync.RWMutex
+	// Filters map: subscription -> what the user wants to be notified about
+	filters map[subscriptionKey]*domain.NotificationFilter
+	// Labels map: subscription -> the user's name for the wallet
+	walletLabels map[subscriptionKey]string
+	// Digest windows map: subscription -> how long notifications are batched
+	digestWindows map[subscriptionKey]time.Duration
+	// Pauses map: subscription -> muted until, and what was missed meanwhile
+	pauses map[subscriptionKey]*pause
+	// Last activity map: wallet address -> timestamp of the latest transaction
+	lastActivity map[domain.WalletAddress]time.Time
+	// Last seen map: wallet address -> time it was added or last had activity
+	lastSeen map[domain.WalletAddress]time.Time
+	// Down listeners map: wallet address -> when its listener died
+	downListeners map[domain.WalletAddress]time.Time
+	mu            sync.RWMutex
+
+	// Recently delivered transactions, used to invalidate them on reorgs
+	deliveries *deliveryLog
+	// Detected transactions waiting for enough confirmations
+	pending *pendingBuffer
+	// Transactions waiting for a publishing worker
+	queue *publishQueue
+	// Notifications already delivered to each subscriber
+	dedup *notificationDedup
+	// Open digests of subscriptions in digest mode
+	digests *digestBuffer
+	// Per-user notification rate limit
+	throttle *notificationThrottle
+	// Leave the transaction's transfers out of notifications
+	slimPayload bool
+	// Block-to-publish latency of the latest notification
+	latency latestLatency
+}
+
+// subscriptionKey identifies the subscription of a user to a wallet
+type subscriptionKey struct {
+	walletAddress domain.WalletAddress
+	userID        domain.UserID
+}
+
+// WalletTrackerOptions holds the optional collaborators and settings of a
+// WalletTracker. Nil collaborators turn the respective feature off.
+type WalletTrackerOptions struct {
+	Labels        *LabelRegistry
+	TokenFilters  *TokenFilters
+	Prices        *PriceService
+	Alerts        *AlertEngine
+	GasAnalytics  *GasAnalytics
+	Groups        *WalletGroups
+	Archive       *TransactionArchive
+	Shards        *ShardCoordinator // Set when several instances share the wallets
+	PendingStore  domain.PendingStore
+	Overflow      domain.OverflowQueue
+	DedupStore    domain.DedupStore
+	Confirmations ConfirmationPolicy
+	Queue         PublishQueueConfig
+	RateLimit     NotificationRateLimit
+
+	BackfillBlocks uint64        // Blocks replayed for a new subscriber
+	DedupTTL       time.Duration // How long sent notifications are remembered
+	SlimPayload    bool          // Leave transfers out of notifications
 }
 
 func NewWalletTracker(
 	blockchainClient domain.BlockchainClient,
 	publisher domain.Publisher,
+	repo domain.WalletRepository,
+	guard *ListenerGuard,
+	limits *UserLimits,
+	audit *AuditTrail,
+	opts WalletTrackerOptions,
 	logger *zap.Logger,
 ) *WalletTracker {
-	return &WalletTracker{
+	// Other instances learn about subscriptions changed here
+	if opts.Shards != nil {
+		repo = announcingRepository{repo, opts.Shards}
+	}
+
+	wt := &WalletTracker{
 		blockchainClient: blockchainClient,
 		publisher:        publisher,
+		repo:             repo,
+		labels:           opts.Labels,
+		tokenFilters:     opts.TokenFilters,
+		prices:           opts.Prices,
+		alerts:           opts.Alerts,
+		gasAnalytics:     opts.GasAnalytics,
+		guard:            guard,
+		limits:           limits,
+		groups:           opts.Groups,
+		audit:            audit,
+		archive:          opts.Archive,
+		backfillBlocks:   opts.BackfillBlocks,
+		shards:           opts.Shards,
 		logger:           logger,
+		restored:         make(chan struct{}),
+		backfillSlots:    make(chan struct{}, maxConcurrentBackfills),
 		listeners:        make(map[domain.WalletAddress]context.CancelFunc),
 		subscribers:      make(map[domain.WalletAddress][]domain.UserID),
+		filters:          make(map[subscriptionKey]*domain.NotificationFilter),
+		pauses:           make(map[subscriptionKey]*pause),
+		walletLabels:     make(map[subscriptionKey]string),
+		digestWindows:    make(map[subscriptionKey]time.Duration),
+		lastActivity:     make(map[domain.WalletAddress]time.Time),
+		lastSeen:         make(map[domain.WalletAddress]time.Time),
+		downListeners:    make(map[domain.WalletAddress]time.Time),
+		deliveries:       newDeliveryLog(),
+		pending:          newPendingBuffer(opts.PendingStore, logger),
+		queue:            newPublishQueue(opts.Queue, opts.Overflow, logger),
+		dedup:            &notificationDedup{store: opts.DedupStore, ttl: opts.DedupTTL, logger: logger},
+		digests:          newDigestBuffer(),
+		throttle:         newNotificationThrottle(opts.RateLimit),
+		slimPayload:      opts.SlimPayload,
 	}
+	confirmations := opts.Confirmations
+	wt.confirmations.Store(&confirmations)
+	return wt
 }
 
+// Start runs the tracker until ctx is done. Wallet listeners derive from
+// ctx; on shutdown they are stopped and transactions being published are
+// finished before Start returns.
 func (wt *WalletTracker) Start(ctx context.Context) {
 	wt.logger.Info("Starting wallet tracker service")
-	<-ctx.Done()
-	wt.logger.Info("Stopping wallet tracker service")
-	wt.stopAllListeners()
+
+	// Wallets added by commands before Start get their listeners now
+	wt.mu.Lock()
+	wt.ctx = ctx
+	for walletAddress := range wt.listeners {
+		wt.startListener(walletAddress)
+	}
+	for _, key := range wt.pendingBackfills {
+		wt.startBackfill(key.walletAddress, key.userID)
+	}
+	wt.pendingBackfills = nil
+	wt.mu.Unlock()
+
+	// Follow subscriptions changed by other instances, from before they're
+	// restored so none is missed
+	if wt.shards != nil {
+		go wt.shards.followChanges(ctx, func(walletAddress domain.WalletAddress) {
+			wt.syncWallet(ctx, walletAddress)
+		})
+	}
+
+	if err := wt.restoreSubscriptions(ctx); err != nil {
+		wt.logger.Error("Failed to restore subscriptions", zap.Error(err))
+	}
+	if err := wt.pending.load(ctx); err != nil {
+		wt.logger.Error("Failed to restore pending transactions", zap.Error(err))
+	}
+	close(wt.restored)
+
+	go wt.watchReorgs(ctx)
+
+	var digests sync.WaitGroup
+	digests.Add(1)
+	go func() {
+		defer digests.Done()
+		wt.flushDigests(ctx)
+	}()
+	go wt.summarizeThrottled(ctx)
+	go wt.watchHeads(ctx)
+
+	// Listeners hand what they still hold to the queue before it stops
+	queueCtx, stopQueue := context.WithCancel(context.WithoutCancel(ctx))
+	defer context.AfterFunc(ctx, func() {
+		wt.logger.Info("Stopping wallet tracker service")
+		wt.stopAllListeners()
+		wt.listenerWG.Wait()
+		stopQueue()
+	})()
+
+	wt.queue.run(queueCtx, wt.process)
+	digests.Wait()
+	wt.logger.Info("Wallet tracker service stopped")
+}
+
+// Restored is closed once Start has restored the persisted subscriptions,
+// so blocks aren't followed before their wallets are watched
+func (wt *WalletTracker) Restored() <-chan struct{} {
+	return wt.restored
+}
+
+// process handles a transaction taken off the publish queue
+func (wt *WalletTracker) process(ctx context.Context, item domain.QueuedTransaction) {
+	ctx, span := tracing.Start(tracing.Extract(ctx, item.Transaction.Trace), "wallet.process",
+		attribute.String("wallet", string(item.WalletAddress)),
+		attribute.String("tx.hash", string(item.Transaction.Hash)),
+		attribute.Int64("confirmations", int64(item.Confirmations)),
+	)
+	defer span.End()
+
+	if item.Confirmations == 0 {
+		wt.handleTransaction(ctx, item.WalletAddress, item.Transaction)
+		return
+	}
+	wt.publishTransaction(ctx, item.WalletAddress, item.Transaction, item.Confirmations)
 }
 
-func (wt *WalletTracker) AddWallet(walletAddress domain.WalletAddress, userID domain.UserID) error {
+func (wt *WalletTracker) AddWallet(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+	filters *domain.NotificationFilter,
+	label string,
+) error {
+	if filters != nil {
+		if err := normalizeNotificationFilter(filters); err != nil {
+			return err
+		}
+	}
+	label, err := normalizeWalletLabel(label)
+	if err != nil {
+		return err
+	}
+
 	wt.mu.Lock()
-	defer wt.mu.Unlock()
+	err = wt.checkNewSubscription(walletAddress, userID)
+	wt.mu.Unlock()
+	if err != nil {
+		return err
+	}
 
-	// Add user to subscribers list
-	wt.subscribers[walletAddress] = append(wt.subscribers[walletAddress], userID)
+	// Persist first, so a failure leaves every tracked wallet in place
+	subscription := domain.WalletSubscription{
+		WalletAddress: walletAddress,
+		UserID:        userID,
+		Filters:       filters,
+		Label:         label,
+		CreatedAt:     time.Now(),
+	}
+	if err := wt.repo.AddSubscription(ctx, subscription); err != nil {
+		return fmt.Errorf("failed to persist subscription: %w", err)
+	}
 
-	// Start listener if it doesn't exist
+	// Enforce tracking limits before starting another listener
+	var (
+		evicted *evictedWallet
+		started bool
+	)
+	wt.mu.Lock()
 	if _, exists := wt.listeners[walletAddress]; !exists {
-		ctx, cancel := context.WithCancel(context.Background())
-		wt.listeners[walletAddress] = cancel
+		evicted, err = wt.admitWallet(walletAddress, 0)
+	}
+	if err == nil {
+		started = wt.subscribe(subscription)
+	}
+	wt.mu.Unlock()
 
-		go wt.startWalletListener(ctx, walletAddress)
+	if err != nil {
+		wt.unpersist(ctx, []domain.WalletAddress{walletAddress}, userID)
+		return err
+	}
 
-		wt.logger.Info("Started listener for wallet",
-			zap.String("wallet", string(walletAddress)),
-			zap.Int64("user_id", int64(userID)),
-		)
+	wt.removeEvicted(ctx, evicted)
+	if started {
+		wt.trackArchive(ctx, walletAddress)
 	}
+	wt.audit.recordSubscription(ctx, domain.AuditSubscriptionAdded, domain.WalletSubscriptionKind, walletAddress, userID, "")
+
+	// Show the new subscriber recent activity without blocking the command
+	wt.startBackfill(walletAddress, userID)
 
 	return nil
 }
 
 func (wt *WalletTracker) RemoveWallet(
+	ctx context.Context,
 	walletAddress domain.WalletAddress,
 	userID domain.UserID,
 ) error {
 	wt.mu.Lock()
 	defer wt.mu.Unlock()
 
+	if !slices.Contains(wt.subscribers[walletAddress], userID) {
+		return fmt.Errorf("%w: user %d doesn't follow %s", domain.ErrWalletNotFound, userID, walletAddress)
+	}
+
+	if err := wt.repo.RemoveSubscription(ctx, walletAddress, userID); err != nil {
+		return fmt.Errorf("failed to remove subscription: %w", err)
+	}
+
+	wt.unsubscribe(walletAddress, userID)
+	wt.audit.recordSubscription(ctx, domain.AuditSubscriptionRemoved, domain.WalletSubscriptionKind, walletAddress, userID, "")
+	return nil
+}
+
+// unsubscribe removes userID from the wallet in memory and stops the wallet
+// listener once nobody is left. Caller must hold wt.mu.
+func (wt *WalletTracker) unsubscribe(walletAddress domain.WalletAddress, userID domain.UserID) {
 	// Remove user from subscribers list
+	delete(wt.filters, subscriptionKey{walletAddress, userID})
+	delete(wt.pauses, subscriptionKey{walletAddress, userID})
+	delete(wt.walletLabels, subscriptionKey{walletAddress, userID})
+	delete(wt.digestWindows, subscriptionKey{walletAddress, userID})
+	wt.digests.drop(subscriptionKey{walletAddress, userID})
 	subscribers := wt.subscribers[walletAddress]
 	for i, id := range subscribers {
 		if id == userID {
@@ -84,50 +364,587 @@ func (wt *WalletTracker) RemoveWallet(
 
 	// Stop listener if no subscribers left
 	if len(wt.subscribers[walletAddress]) == 0 {
-		if cancel, exists := wt.listeners[walletAddress]; exists {
-			cancel()
-			delete(wt.listeners, walletAddress)
-			delete(wt.subscribers, walletAddress)
+		wt.stopListener(walletAddress)
+	}
+}
 
-			wt.logger.Info("Stopped listener for wallet",
-				zap.String("wallet", string(walletAddress)),
+// restoreSubscriptions loads persisted subscriptions and restarts listeners.
+// Everything is read, and migrated, before wt.mu is taken. Subscriptions
+// over the tracking or per-user limits are left out, but kept persisted so
+// they come back once the limits are raised.
+func (wt *WalletTracker) restoreSubscriptions(ctx context.Context) error {
+	wallets, err := wt.repo.GetAllWallets(ctx)
+	if err != nil {
+		return err
+	}
+
+	var loaded []domain.WalletSubscription
+	for _, stored := range wallets {
+		walletAddress, err := NormalizeAddress(stored)
+		if err != nil {
+			wt.logger.Warn("Skipping invalid persisted wallet", zap.Error(err))
+			continue
+		}
+
+		subscriptions, err := wt.repo.GetSubscriptions(ctx, stored)
+		if err != nil {
+			wt.logger.Error("Failed to load wallet subscribers",
+				zap.String("wallet", string(stored)),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		for _, subscription := range subscriptions {
+			if walletAddress != stored {
+				wt.migrateSubscription(ctx, stored, walletAddress, subscription)
+			}
+			subscription.WalletAddress = walletAddress
+			loaded = append(loaded, subscription)
+		}
+	}
+
+	wt.mu.Lock()
+	restored := 0
+	var started []domain.WalletAddress
+	for _, subscription := range loaded {
+		if err := wt.admitRestored(subscription); err != nil {
+			metrics.GuardActionsTotal.WithLabelValues("rejected").Inc()
+			wt.logger.Warn("Skipping persisted subscription over limits",
+				zap.String("wallet", string(subscription.WalletAddress)),
+				zap.Int64("user_id", int64(subscription.UserID)),
+				zap.Error(err),
 			)
+			continue
 		}
+		if wt.subscribe(subscription) {
+			started = append(started, subscription.WalletAddress)
+		}
+		wt.restorePause(subscription)
+		restored++
 	}
+	tracked := len(wt.listeners)
+	wt.mu.Unlock()
+
+	wt.trackArchive(ctx, started...)
+
+	wt.logger.Info("Restored subscriptions",
+		zap.Int("wallets", tracked),
+		zap.Int("subscriptions", restored),
+		zap.Int("skipped", len(loaded)-restored),
+	)
+	return nil
+}
 
+// admitRestored checks a persisted subscription against the same limits
+// as a new one. Nothing is evicted for it, restored wallets are all equally
+// old. Caller must hold wt.mu.
+func (wt *WalletTracker) admitRestored(subscription domain.WalletSubscription) error {
+	walletAddress, userID := subscription.WalletAddress, subscription.UserID
+	if slices.Contains(wt.subscribers[walletAddress], userID) {
+		return nil
+	}
+	if err := wt.limits.Check(userID, wt.walletCount(userID), 1); err != nil {
+		return err
+	}
+	if _, exists := wt.listeners[walletAddress]; !exists {
+		if reason, exceeded := wt.guard.Check(len(wt.listeners)); exceeded {
+			return fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, reason)
+		}
+	}
 	return nil
 }
 
-func (wt *WalletTracker) startWalletListener(
+// migrateSubscription re-keys a subscription persisted before addresses
+// were normalized to checksum form
+func (wt *WalletTracker) migrateSubscription(
 	ctx context.Context,
+	stored domain.WalletAddress,
 	walletAddress domain.WalletAddress,
+	subscription domain.WalletSubscription,
 ) {
-	wt.logger.Info("Starting wallet listener", zap.String("wallet", string(walletAddress)))
+	userID := subscription.UserID
+	subscription.WalletAddress = walletAddress
+	if err := wt.repo.AddSubscription(ctx, subscription); err != nil {
+		wt.logger.Error("Failed to migrate subscription",
+			zap.String("wallet", string(stored)),
+			zap.Int64("user_id", int64(userID)),
+			zap.Error(err),
+		)
+		return
+	}
+	if err := wt.repo.RemoveSubscription(ctx, stored, userID); err != nil {
+		wt.logger.Error("Failed to remove migrated subscription",
+			zap.String("wallet", string(stored)),
+			zap.Int64("user_id", int64(userID)),
+			zap.Error(err),
+		)
+	}
+}
 
-	txChan, err := wt.blockchainClient.SubscribeToAddress(ctx, walletAddress)
-	if err != nil {
-		wt.logger.Error("Failed to subscribe to wallet",
+// subscribe registers the subscription in memory and starts the wallet
+// listener if needed, reporting whether it did so the caller can archive
+// the wallet with trackArchive. Caller must hold wt.mu.
+func (wt *WalletTracker) subscribe(subscription domain.WalletSubscription) bool {
+	walletAddress, userID := subscription.WalletAddress, subscription.UserID
+	key := subscriptionKey{walletAddress, userID}
+
+	// Add user to subscribers list, once
+	if !slices.Contains(wt.subscribers[walletAddress], userID) {
+		wt.subscribers[walletAddress] = append(wt.subscribers[walletAddress], userID)
+	}
+	if subscription.Filters != nil {
+		wt.filters[key] = subscription.Filters
+	} else {
+		delete(wt.filters, key)
+	}
+	if subscription.Label != "" {
+		wt.walletLabels[key] = subscription.Label
+	} else {
+		delete(wt.walletLabels, key)
+	}
+	if subscription.DigestWindow > 0 {
+		wt.digestWindows[key] = subscription.DigestWindow
+	} else {
+		delete(wt.digestWindows, key)
+	}
+
+	// Start listener if it doesn't exist
+	if _, exists := wt.listeners[walletAddress]; !exists {
+		wt.startListener(walletAddress)
+		wt.lastSeen[walletAddress] = time.Now()
+		metrics.TrackedWallets.Set(float64(len(wt.listeners)))
+
+		wt.logger.Info("Started listener for wallet",
 			zap.String("wallet", string(walletAddress)),
-			zap.Error(err),
+			zap.Int64("user_id", int64(userID)),
 		)
+		return true
+	}
+	return false
+}
+
+// trackArchive starts archiving the transactions of wallets whose listeners
+// subscribe just started, from the block after the last processed one. It
+// does repository I/O, so the caller must not hold wt.mu.
+func (wt *WalletTracker) trackArchive(ctx context.Context, walletAddresses ...domain.WalletAddress) {
+	if len(walletAddresses) == 0 {
+		return
+	}
+	block, ok := wt.blockchainClient.LastProcessedBlock()
+	if !ok {
+		return
+	}
+	for _, walletAddress := range walletAddresses {
+		wt.archive.track(ctx, walletAddress, block+1)
+	}
+}
+
+// startListener runs the supervised wallet listener under the tracker
+// context. Before Start it only reserves the slot. Caller must hold wt.mu.
+func (wt *WalletTracker) startListener(walletAddress domain.WalletAddress) {
+	if wt.ctx == nil {
+		wt.listeners[walletAddress] = func() {}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(wt.ctx)
+	wt.listeners[walletAddress] = cancel
+
+	wt.listenerWG.Add(1)
+	go func() {
+		defer wt.listenerWG.Done()
+		wt.superviseListener(ctx, walletAddress)
+	}()
+}
+
+// checkNewSubscription rejects a subscription the user already has or that
+// would exceed the user's or the tracker's limits. Caller must hold wt.mu.
+func (wt *WalletTracker) checkNewSubscription(walletAddress domain.WalletAddress, userID domain.UserID) error {
+	if slices.Contains(wt.subscribers[walletAddress], userID) {
+		return fmt.Errorf("%w: user %d already follows %s", domain.ErrSubscriptionExists, userID, walletAddress)
+	}
+	if err := wt.limits.Check(userID, wt.walletCount(userID), 1); err != nil {
+		return err
+	}
+	if _, exists := wt.listeners[walletAddress]; !exists {
+		return wt.checkAdmission(walletAddress, 0)
+	}
+	return nil
+}
+
+// admitWallet checks the guard limits for a new wallet, evicting the least
+// recently active wallet when the policy allows it. pending counts wallets
+// already admitted whose listeners aren't started yet. An evicted wallet is
+// returned for removeEvicted once wt.mu is released. Caller must hold wt.mu.
+func (wt *WalletTracker) admitWallet(
+	walletAddress domain.WalletAddress,
+	pending int,
+) (*evictedWallet, error) {
+	reason, exceeded := wt.guard.Check(len(wt.listeners) + pending)
+	if !exceeded {
+		return nil, nil
+	}
+
+	if wt.guard.Evicts() {
+		if victim, ok := wt.leastRecentlyActive(); ok {
+			metrics.GuardActionsTotal.WithLabelValues("evicted").Inc()
+			wt.logger.Warn("Evicting least recently active wallet",
+				zap.String("wallet", string(victim)),
+				zap.Int("subscribers", len(wt.subscribers[victim])),
+				zap.String("reason", reason),
+			)
+			return wt.evict(victim), nil
+		}
+	}
+
+	return nil, wt.rejectWallet(walletAddress, reason)
+}
+
+// checkAdmission fails for a new wallet that admitWallet would reject,
+// without evicting anything. Caller must hold wt.mu.
+func (wt *WalletTracker) checkAdmission(walletAddress domain.WalletAddress, pending int) error {
+	reason, exceeded := wt.guard.Check(len(wt.listeners) + pending)
+	if !exceeded || (wt.guard.Evicts() && len(wt.listeners) > 0) {
+		return nil
+	}
+	return wt.rejectWallet(walletAddress, reason)
+}
+
+// rejectWallet reports a wallet turned away by the guard
+func (wt *WalletTracker) rejectWallet(walletAddress domain.WalletAddress, reason string) error {
+	metrics.GuardActionsTotal.WithLabelValues("rejected").Inc()
+	wt.logger.Warn("Rejected wallet subscription",
+		zap.String("wallet", string(walletAddress)),
+		zap.String("reason", reason),
+	)
+	return fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, reason)
+}
+
+// leastRecentlyActive returns the tracked wallet that was seen longest ago.
+// Caller must hold wt.mu.
+func (wt *WalletTracker) leastRecentlyActive() (domain.WalletAddress, bool) {
+	var (
+		victim domain.WalletAddress
+		oldest time.Time
+		found  bool
+	)
+	for walletAddress := range wt.listeners {
+		seen := wt.lastSeen[walletAddress]
+		if !found || seen.Before(oldest) {
+			victim, oldest, found = walletAddress, seen, true
+		}
+	}
+
+	return victim, found
+}
+
+// evictedWallet is a wallet dropped to make room for another, with the
+// users whose persisted subscriptions still have to be removed
+type evictedWallet struct {
+	walletAddress domain.WalletAddress
+	userIDs       []domain.UserID
+}
+
+// evict stops tracking the wallet for all of its subscribers. Caller must
+// hold wt.mu.
+func (wt *WalletTracker) evict(walletAddress domain.WalletAddress) *evictedWallet {
+	evicted := &evictedWallet{
+		walletAddress: walletAddress,
+		userIDs:       slices.Clone(wt.subscribers[walletAddress]),
+	}
+	wt.stopListener(walletAddress)
+	return evicted
+}
+
+// removeEvicted removes the persisted subscriptions of an evicted wallet.
+// It does repository I/O, so the caller must not hold wt.mu.
+func (wt *WalletTracker) removeEvicted(ctx context.Context, evicted *evictedWallet) {
+	if evicted == nil {
 		return
 	}
+	for _, userID := range evicted.userIDs {
+		if err := wt.repo.RemoveSubscription(ctx, evicted.walletAddress, userID); err != nil {
+			wt.logger.Error("Failed to remove evicted subscription",
+				zap.String("wallet", string(evicted.walletAddress)),
+				zap.Int64("user_id", int64(userID)),
+				zap.Error(err),
+			)
+		}
+		wt.audit.recordSubscription(ctx, domain.AuditSubscriptionRemoved, domain.WalletSubscriptionKind,
+			evicted.walletAddress, userID, "evicted")
+	}
+}
+
+// unpersist removes subscriptions persisted for wallets the guard then
+// turned away. The caller must not hold wt.mu.
+func (wt *WalletTracker) unpersist(ctx context.Context, walletAddresses []domain.WalletAddress, userID domain.UserID) {
+	if err := wt.repo.RemoveSubscriptions(ctx, walletAddresses, userID); err != nil {
+		wt.logger.Error("Failed to remove rejected subscriptions",
+			zap.Int64("user_id", int64(userID)),
+			zap.Int("wallets", len(walletAddresses)),
+			zap.Error(err),
+		)
+	}
+}
+
+// stopListener cancels the wallet listener and drops all of its state.
+// Caller must hold wt.mu.
+func (wt *WalletTracker) stopListener(walletAddress domain.WalletAddress) {
+	if cancel, exists := wt.listeners[walletAddress]; exists {
+		cancel()
+		delete(wt.listeners, walletAddress)
+		wt.archive.untrack(context.Background(), walletAddress)
+		for _, userID := range wt.subscribers[walletAddress] {
+			delete(wt.filters, subscriptionKey{walletAddress, userID})
+			delete(wt.pauses, subscriptionKey{walletAddress, userID})
+			delete(wt.walletLabels, subscriptionKey{walletAddress, userID})
+			delete(wt.digestWindows, subscriptionKey{walletAddress, userID})
+			wt.digests.drop(subscriptionKey{walletAddress, userID})
+		}
+		delete(wt.subscribers, walletAddress)
+		delete(wt.lastActivity, walletAddress)
+		delete(wt.lastSeen, walletAddress)
+		delete(wt.downListeners, walletAddress)
+		metrics.TrackedWallets.Set(float64(len(wt.listeners)))
+		metrics.ListenersDown.Set(float64(len(wt.downListeners)))
+
+		wt.logger.Info("Stopped listener for wallet",
+			zap.String("wallet", string(walletAddress)),
+		)
+	}
+}
+
+// WalletsForUser returns all wallets the user is subscribed to
+func (wt *WalletTracker) WalletsForUser(userID domain.UserID) []domain.WalletAddress {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	var wallets []domain.WalletAddress
+	for walletAddress, subscribers := range wt.subscribers {
+		for _, id := range subscribers {
+			if id == userID {
+				wallets = append(wallets, walletAddress)
+				break
+			}
+		}
+	}
+
+	return wallets
+}
+
+// trackedWallets returns every wallet with subscribers
+func (wt *WalletTracker) trackedWallets() []domain.WalletAddress {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	wallets := make([]domain.WalletAddress, 0, len(wt.subscribers))
+	for walletAddress := range wt.subscribers {
+		wallets = append(wallets, walletAddress)
+	}
+	return wallets
+}
+
+// walletsByUser returns the wallets of every subscribed user
+func (wt *WalletTracker) walletsByUser() map[domain.UserID][]domain.WalletAddress {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	wallets := make(map[domain.UserID][]domain.WalletAddress)
+	for walletAddress, subscribers := range wt.subscribers {
+		for _, userID := range subscribers {
+			wallets[userID] = append(wallets[userID], walletAddress)
+		}
+	}
+	return wallets
+}
+
+// SubscriptionsForUser returns the subscriptions of the user, with their
+// filters and creation times
+func (wt *WalletTracker) SubscriptionsForUser(
+	ctx context.Context,
+	userID domain.UserID,
+) ([]domain.WalletSubscription, error) {
+	subscriptions := []domain.WalletSubscription{}
+	for _, walletAddress := range wt.WalletsForUser(userID) {
+		stored, err := wt.repo.GetSubscriptions(ctx, walletAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+		}
+		for _, subscription := range stored {
+			if subscription.UserID == userID {
+				subscriptions = append(subscriptions, subscription)
+			}
+		}
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			wt.logger.Info("Wallet listener stopped", zap.String("wallet", string(walletAddress)))
-			return
-		case tx := <-txChan:
-			wt.handleTransaction(ctx, walletAddress, tx)
+	slices.SortFunc(subscriptions, func(a, b domain.WalletSubscription) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+// Status returns listener and subscriber counts and block progress
+func (wt *WalletTracker) Status() domain.TrackerStatus {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	status := domain.TrackerStatus{
+		ActiveListeners: len(wt.listeners) - len(wt.downListeners),
+		DownListeners:   len(wt.downListeners),
+		Subscribers:     make(map[domain.WalletAddress]int, len(wt.subscribers)),
+		GeneratedAt:     time.Now(),
+	}
+
+	users := make(map[domain.UserID]struct{})
+	for walletAddress, subscribers := range wt.subscribers {
+		status.Subscribers[walletAddress] = len(subscribers)
+		status.Subscriptions += len(subscribers)
+		for _, userID := range subscribers {
+			users[userID] = struct{}{}
 		}
 	}
+	status.Users = len(users)
+
+	if block, ok := wt.blockchainClient.LastProcessedBlock(); ok {
+		status.LastProcessedBlock = block
+	}
+
+	return status
+}
+
+// QueueOccupancy returns how many transactions wait in memory for the
+// publishing workers and how many fit before spilling to Redis
+func (wt *WalletTracker) QueueOccupancy() (buffered, capacity int) {
+	return wt.queue.occupancy()
+}
+
+// walletLabelsFor returns the names subscribers gave the wallet, or nil if
+// none of them named it
+func (wt *WalletTracker) walletLabelsFor(
+	walletAddress domain.WalletAddress,
+	subscribers []domain.UserID,
+) map[domain.UserID]string {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	var labels map[domain.UserID]string
+	for _, userID := range subscribers {
+		if label, ok := wt.walletLabels[subscriptionKey{walletAddress, userID}]; ok {
+			if labels == nil {
+				labels = make(map[domain.UserID]string)
+			}
+			labels[userID] = label
+		}
+	}
+	return labels
+}
+
+// normalizeWalletLabel trims label and checks it fits in a notification
+func normalizeWalletLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	if utf8.RuneCountInString(label) > maxWalletLabelLength {
+		return "", fmt.Errorf("%w: longer than %d characters", domain.ErrInvalidLabel, maxWalletLabelLength)
+	}
+	return label, nil
+}
+
+// walletCount returns how many wallets the user follows. Caller must hold
+// wt.mu.
+func (wt *WalletTracker) walletCount(userID domain.UserID) int {
+	count := 0
+	for _, subscribers := range wt.subscribers {
+		if slices.Contains(subscribers, userID) {
+			count++
+		}
+	}
+	return count
+}
+
+// LastActivity returns the timestamp of the latest transaction seen for wallet
+func (wt *WalletTracker) LastActivity(walletAddress domain.WalletAddress) (time.Time, bool) {
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+
+	ts, ok := wt.lastActivity[walletAddress]
+	return ts, ok
+}
+
+// runWalletListener forwards the wallet's transactions to the publish
+// queue, or returns why it stopped early. Cancelling ctx ends the
+// subscription, which closes its channel; transactions already delivered
+// to it are forwarded before the listener stops.
+func (wt *WalletTracker) runWalletListener(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+) error {
+	wt.logger.Info("Starting wallet listener", zap.String("wallet", string(walletAddress)))
+
+	txChan, err := wt.blockchainClient.SubscribeToAddress(ctx, walletAddress)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to wallet: %w", err)
+	}
+
+	// Still forwarding after ctx is done
+	enqueueCtx := context.WithoutCancel(ctx)
+	for tx := range txChan {
+		wt.queue.enqueue(enqueueCtx, domain.QueuedTransaction{
+			WalletAddress: walletAddress,
+			Transaction:   tx,
+		})
+	}
+
+	if ctx.Err() == nil {
+		return errors.New("wallet listener channel closed")
+	}
+	wt.logger.Info("Wallet listener stopped", zap.String("wallet", string(walletAddress)))
+	return nil
 }
 
 func (wt *WalletTracker) handleTransaction(
 	ctx context.Context,
 	walletAddress domain.WalletAddress,
 	tx domain.Transaction,
+) {
+	wt.mu.Lock()
+	subscribers := append([]domain.UserID(nil), wt.subscribers[walletAddress]...)
+	if tx.Timestamp.After(wt.lastActivity[walletAddress]) {
+		wt.lastActivity[walletAddress] = tx.Timestamp
+	}
+	if _, tracked := wt.listeners[walletAddress]; tracked {
+		wt.lastSeen[walletAddress] = time.Now()
+	}
+	wt.mu.Unlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	// Alerts are high priority and don't wait for confirmations
+	wt.prices.Enrich(ctx, tx.Transfers)
+	wt.labels.Annotate(tx.Transfers)
+	wt.alerts.Evaluate(ctx, walletAddress, walletView(walletAddress, tx), subscribers)
+
+	policy := wt.confirmationPolicy()
+	if !policy.gated() {
+		wt.publishTransaction(ctx, walletAddress, tx, 1)
+		return
+	}
+
+	// Hold the transaction until it is deep enough, optionally announcing it early
+	if policy.NotifyUnconfirmed {
+		wt.publishTransaction(ctx, walletAddress, tx, 0)
+	}
+	wt.pending.add(ctx, walletAddress, tx)
+}
+
+// publishTransaction notifies the current subscribers of the wallet. Zero
+// confirmations marks the notification as unconfirmed.
+func (wt *WalletTracker) publishTransaction(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+	confirmations uint64,
 ) {
 	wt.mu.RLock()
 	subscribers := make([]domain.UserID, len(wt.subscribers[walletAddress]))
@@ -138,26 +955,169 @@ func (wt *WalletTracker) handleTransaction(
 		return
 	}
 
-	notification := domain.WalletNotification{
-		WalletAddress: walletAddress,
-		Transaction:   tx,
-		Subscribers:   subscribers,
-		Timestamp:     time.Now(),
+	// Fees are only counted once the transaction is confirmed, so a reorged
+	// one isn't
+	if confirmations > 0 {
+		wt.gasAnalytics.Record(ctx, walletAddress, tx)
 	}
 
-	if err := wt.publisher.PublishNotification(ctx, notification); err != nil {
-		wt.logger.Error("Failed to publish notification",
-			zap.String("wallet", string(walletAddress)),
-			zap.String("tx_hash", string(tx.Hash)),
-			zap.Error(err),
-		)
-	} else {
+	wt.labels.Annotate(tx.Transfers)
+	wt.labels.AnnotateApprovals(tx.Approvals)
+
+	// Subscribers with different filters see different transfers. Paused
+	// subscribers are counted once per transaction, when it is confirmed.
+	var keys []string
+	for _, audience := range wt.audiencesFor(walletAddress, walletView(walletAddress, tx), subscribers) {
+		recipients := wt.unpaused(walletAddress, audience.subscribers, confirmations > 0)
+		recipients = wt.collectDigests(walletAddress, audience.tx, recipients, confirmations > 0)
+		if len(recipients) == 0 {
+			continue
+		}
+		keys = append(keys, wt.notify(ctx, walletAddress, audience.tx, tx.Transfers, recipients, confirmations)...)
+	}
+
+	if len(keys) > 0 {
+		wt.deliveries.record(walletAddress, tx, keys)
+		wt.archive.record(ctx, walletAddress, tx)
+	}
+}
+
+// notify publishes a notification of every type tx, the wallet's view of a
+// transaction with all transfers, qualifies for and returns the idempotency
+// keys of those published
+func (wt *WalletTracker) notify(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	tx domain.Transaction,
+	transfers []domain.Transfer,
+	subscribers []domain.UserID,
+	confirmations uint64,
+) []string {
+	var published []string
+	for _, notificationType := range notificationTypesFor(tx) {
+		key := idempotencyKey(walletAddress, tx, notificationType, confirmations == 0)
+		recipients := wt.dedup.claim(ctx, key, subscribers)
+		if duplicates := len(subscribers) - len(recipients); duplicates > 0 {
+			metrics.NotificationsDroppedTotal.WithLabelValues("duplicate").Add(float64(duplicates))
+		}
+		if len(recipients) == 0 {
+			wt.logger.Debug("Skipping already delivered notification", zap.String("key", key))
+			continue
+		}
+
+		// Throttled subscribers are told how much they missed later
+		allowed := wt.throttle.allow(recipients)
+		if throttled := len(recipients) - len(allowed); throttled > 0 {
+			metrics.NotificationsDroppedTotal.WithLabelValues("rate_limited").Add(float64(throttled))
+		}
+		if recipients = allowed; len(recipients) == 0 {
+			continue
+		}
+
+		notification := domain.WalletNotification{
+			Type:           notificationType,
+			SchemaVersion:  domain.CurrentSchemaVersion,
+			WalletAddress:  walletAddress,
+			Transaction:    wt.publishedTransaction(tx, transfers),
+			Transfers:      tx.Transfers,
+			Subscribers:    recipients,
+			WalletLabels:   wt.walletLabelsFor(walletAddress, recipients),
+			WalletGroups:   wt.groups.groupsFor(walletAddress, recipients),
+			Timestamp:      time.Now(),
+			Trace:          traceContextFor(ctx),
+			IdempotencyKey: key,
+			Confirmations:  confirmations,
+			Unconfirmed:    confirmations == 0,
+			Failed:         tx.Status == domain.TxReverted,
+			Gasless:        tx.Gasless,
+		}
+		switch notificationType {
+		case domain.ApprovalNotification:
+			notification.Approvals = tx.Approvals
+		case domain.SwapNotification:
+			notification.Swaps = tx.Swaps
+		case domain.BridgeInNotification:
+			notification.Bridges = bridgesInDirection(tx.Bridges, domain.BridgeIn)
+		case domain.BridgeOutNotification:
+			notification.Bridges = bridgesInDirection(tx.Bridges, domain.BridgeOut)
+		}
+
+		if err := wt.publisher.PublishNotification(ctx, notification); err != nil {
+			wt.logger.Error("Failed to publish notification",
+				zap.String("wallet", string(walletAddress)),
+				zap.String("tx_hash", string(tx.Hash)),
+				zap.String("type", string(notificationType)),
+				zap.Error(err),
+			)
+			wt.dedup.release(ctx, key, recipients)
+			continue
+		}
+
+		published = append(published, key)
+		wt.observeLatency(notificationType, tx)
 		wt.logger.Info("Published transaction notification",
 			zap.String("wallet", string(walletAddress)),
 			zap.String("tx_hash", string(tx.Hash)),
-			zap.Int("subscribers", len(subscribers)),
+			zap.String("type", string(notificationType)),
+			zap.Int("subscribers", len(recipients)),
+			zap.Uint64("confirmations", confirmations),
+			zap.String("trace_id", traceID(notification.Trace)),
 		)
 	}
+
+	return published
+}
+
+// publishedTransaction is tx as notifications carry it: with all of its
+// transfers, or none in slim mode, where only the wallet's own are sent in
+// the notification's Transfers
+func (wt *WalletTracker) publishedTransaction(tx domain.Transaction, transfers []domain.Transfer) domain.Transaction {
+	tx.Transfers = transfers
+	if wt.slimPayload {
+		tx.Transfers = nil
+	}
+	return tx
+}
+
+// notificationTypesFor picks the notifications a transaction produces. A
+// successful transaction is reported by its most meaningful event: a bridge
+// transfer, then a swap, which replace the raw transfers; otherwise a
+// transaction notification covers moved funds or a revert. Allowances
+// granted by a successful transaction get a separate approval notification.
+func notificationTypesFor(tx domain.Transaction) []domain.NotificationType {
+	succeeded := tx.Status != domain.TxReverted
+
+	var kinds []domain.NotificationType
+	switch {
+	case len(tx.Bridges) > 0 && succeeded:
+		if len(bridgesInDirection(tx.Bridges, domain.BridgeIn)) > 0 {
+			kinds = append(kinds, domain.BridgeInNotification)
+		}
+		if len(bridgesInDirection(tx.Bridges, domain.BridgeOut)) > 0 {
+			kinds = append(kinds, domain.BridgeOutNotification)
+		}
+	case len(tx.Swaps) > 0 && succeeded:
+		kinds = append(kinds, domain.SwapNotification)
+	case len(tx.Transfers) > 0 || !succeeded || len(tx.Approvals) == 0:
+		kinds = append(kinds, domain.TransactionNotification)
+	}
+	if len(tx.Approvals) > 0 && succeeded {
+		kinds = append(kinds, domain.ApprovalNotification)
+	}
+	return kinds
+}
+
+func bridgesInDirection(
+	bridges []domain.BridgeTransfer,
+	direction domain.BridgeDirection,
+) []domain.BridgeTransfer {
+	var matching []domain.BridgeTransfer
+	for _, bridge := range bridges {
+		if bridge.Direction == direction {
+			matching = append(matching, bridge)
+		}
+	}
+	return matching
 }
 
 func (wt *WalletTracker) stopAllListeners() {
@@ -171,4 +1131,7 @@ func (wt *WalletTracker) stopAllListeners() {
 
 	wt.listeners = make(map[domain.WalletAddress]context.CancelFunc)
 	wt.subscribers = make(map[domain.WalletAddress][]domain.UserID)
+	wt.lastActivity = make(map[domain.WalletAddress]time.Time)
+	wt.lastSeen = make(map[domain.WalletAddress]time.Time)
+	metrics.TrackedWallets.Set(0)
 }
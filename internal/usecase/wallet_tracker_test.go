@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	testWallet = domain.WalletAddress("0x52908400098527886E0F7030069857D2E4169EE7")
+	testUser   = domain.UserID(1)
+)
+
+// fakeWalletRepository keeps subscriptions in memory the way the Redis
+// repository stores them: per wallet, one per user
+type fakeWalletRepository struct {
+	subscriptions map[domain.WalletAddress]map[domain.UserID]domain.WalletSubscription
+	removals      int
+	addErr        error // Returned by every add, when set
+	mu            sync.Mutex
+}
+
+func newFakeWalletRepository() *fakeWalletRepository {
+	return &fakeWalletRepository{
+		subscriptions: make(map[domain.WalletAddress]map[domain.UserID]domain.WalletSubscription),
+	}
+}
+
+func (r *fakeWalletRepository) AddSubscription(ctx context.Context, subscription domain.WalletSubscription) error {
+	return r.AddSubscriptions(ctx, []domain.WalletSubscription{subscription})
+}
+
+func (r *fakeWalletRepository) RemoveSubscription(
+	ctx context.Context,
+	walletAddress domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	return r.RemoveSubscriptions(ctx, []domain.WalletAddress{walletAddress}, userID)
+}
+
+func (r *fakeWalletRepository) AddSubscriptions(_ context.Context, subscriptions []domain.WalletSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.addErr != nil {
+		return r.addErr
+	}
+	for _, subscription := range subscriptions {
+		if r.subscriptions[subscription.WalletAddress] == nil {
+			r.subscriptions[subscription.WalletAddress] = make(map[domain.UserID]domain.WalletSubscription)
+		}
+		r.subscriptions[subscription.WalletAddress][subscription.UserID] = subscription
+	}
+	return nil
+}
+
+func (r *fakeWalletRepository) RemoveSubscriptions(
+	_ context.Context,
+	walletAddresses []domain.WalletAddress,
+	userID domain.UserID,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, walletAddress := range walletAddresses {
+		r.removals++
+		delete(r.subscriptions[walletAddress], userID)
+		if len(r.subscriptions[walletAddress]) == 0 {
+			delete(r.subscriptions, walletAddress)
+		}
+	}
+	return nil
+}
+
+func (r *fakeWalletRepository) GetSubscribers(_ context.Context, walletAddress domain.WalletAddress) ([]domain.UserID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(r.subscriptions[walletAddress])), nil
+}
+
+func (r *fakeWalletRepository) GetSubscriptions(
+	_ context.Context,
+	walletAddress domain.WalletAddress,
+) ([]domain.WalletSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Collect(maps.Values(r.subscriptions[walletAddress])), nil
+}
+
+func (r *fakeWalletRepository) GetAllWallets(context.Context) ([]domain.WalletAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Collect(maps.Keys(r.subscriptions)), nil
+}
+
+// fakeBlockchainClient counts the address subscriptions still open
+type fakeBlockchainClient struct {
+	domain.BlockchainClient
+
+	open map[domain.WalletAddress]int
+	mu   sync.Mutex
+}
+
+func newFakeBlockchainClient() *fakeBlockchainClient {
+	return &fakeBlockchainClient{open: make(map[domain.WalletAddress]int)}
+}
+
+func (c *fakeBlockchainClient) SubscribeToAddress(
+	ctx context.Context,
+	address domain.WalletAddress,
+) (<-chan domain.Transaction, error) {
+	c.mu.Lock()
+	c.open[address]++
+	c.mu.Unlock()
+
+	ch := make(chan domain.Transaction)
+	go func() {
+		<-ctx.Done()
+		c.mu.Lock()
+		c.open[address]--
+		c.mu.Unlock()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (c *fakeBlockchainClient) LastProcessedBlock() (uint64, bool) {
+	return 0, false
+}
+
+func (c *fakeBlockchainClient) openSubscriptions(address domain.WalletAddress) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.open[address]
+}
+
+// newTestWalletTracker returns a tracker whose listeners run until the
+// test ends
+func newTestWalletTracker(t *testing.T) (*WalletTracker, *fakeWalletRepository, *fakeBlockchainClient) {
+	t.Helper()
+
+	logger := zap.NewNop()
+	repo := newFakeWalletRepository()
+	client := newFakeBlockchainClient()
+	wt := NewWalletTracker(
+		client,
+		nil,
+		repo,
+		NewListenerGuard(0, 0, RejectPolicy),
+		NewUserLimits(nil, 0, nil, logger),
+		NewAuditTrail(nil, logger),
+		WalletTrackerOptions{Queue: PublishQueueConfig{Workers: 1, Size: 1}},
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wt.mu.Lock()
+	wt.ctx = ctx
+	wt.mu.Unlock()
+	t.Cleanup(func() {
+		cancel()
+		wt.listenerWG.Wait()
+	})
+
+	return wt, repo, client
+}
+
+// waitForSubscriptions fails the test unless the wallet ends up with want
+// open address subscriptions
+func waitForSubscriptions(t *testing.T, client *fakeBlockchainClient, address domain.WalletAddress, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for client.openSubscriptions(address) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("open subscriptions to %s = %d, want %d", address, client.openSubscriptions(address), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAddWalletTwiceKeepsOneSubscription(t *testing.T) {
+	wt, repo, client := newTestWalletTracker(t)
+	ctx := context.Background()
+
+	if err := wt.AddWallet(ctx, testWallet, testUser, nil, "savings"); err != nil {
+		t.Fatalf("AddWallet() error = %v", err)
+	}
+	err := wt.AddWallet(ctx, testWallet, testUser, nil, "other")
+	if !errors.Is(err, domain.ErrSubscriptionExists) {
+		t.Fatalf("second AddWallet() error = %v, want %v", err, domain.ErrSubscriptionExists)
+	}
+
+	subscriptions, _ := repo.GetSubscriptions(ctx, testWallet)
+	if len(subscriptions) != 1 {
+		t.Fatalf("stored subscriptions = %d, want 1", len(subscriptions))
+	}
+	if subscriptions[0].Label != "savings" {
+		t.Errorf("stored label = %q, want the first one kept", subscriptions[0].Label)
+	}
+
+	status := wt.Status()
+	if status.ActiveListeners != 1 {
+		t.Errorf("active listeners = %d, want 1", status.ActiveListeners)
+	}
+	if status.Subscribers[testWallet] != 1 {
+		t.Errorf("subscribers = %d, want 1", status.Subscribers[testWallet])
+	}
+	waitForSubscriptions(t, client, testWallet, 1)
+}
+
+func TestAddWalletForAnotherUserSharesListener(t *testing.T) {
+	wt, repo, client := newTestWalletTracker(t)
+	ctx := context.Background()
+
+	for _, userID := range []domain.UserID{testUser, testUser + 1} {
+		if err := wt.AddWallet(ctx, testWallet, userID, nil, ""); err != nil {
+			t.Fatalf("AddWallet(user %d) error = %v", userID, err)
+		}
+	}
+
+	subscribers, _ := repo.GetSubscribers(ctx, testWallet)
+	if !slices.Equal(subscribers, []domain.UserID{testUser, testUser + 1}) {
+		t.Errorf("stored subscribers = %v, want both users", subscribers)
+	}
+	if got := wt.Status().ActiveListeners; got != 1 {
+		t.Errorf("active listeners = %d, want 1", got)
+	}
+	waitForSubscriptions(t, client, testWallet, 1)
+}
+
+func TestRemoveWalletNeverAdded(t *testing.T) {
+	wt, repo, client := newTestWalletTracker(t)
+	ctx := context.Background()
+
+	err := wt.RemoveWallet(ctx, testWallet, testUser)
+	if !errors.Is(err, domain.ErrWalletNotFound) {
+		t.Fatalf("RemoveWallet() error = %v, want %v", err, domain.ErrWalletNotFound)
+	}
+
+	if repo.removals != 0 {
+		t.Errorf("repository removals = %d, want none", repo.removals)
+	}
+	if wallets, _ := repo.GetAllWallets(ctx); len(wallets) != 0 {
+		t.Errorf("stored wallets = %v, want none", wallets)
+	}
+	if got := wt.Status().ActiveListeners; got != 0 {
+		t.Errorf("active listeners = %d, want 0", got)
+	}
+	if got := client.openSubscriptions(testWallet); got != 0 {
+		t.Errorf("open subscriptions = %d, want 0", got)
+	}
+}
+
+func TestRemoveWalletTwiceStopsListenerOnce(t *testing.T) {
+	wt, repo, client := newTestWalletTracker(t)
+	ctx := context.Background()
+
+	if err := wt.AddWallet(ctx, testWallet, testUser, nil, ""); err != nil {
+		t.Fatalf("AddWallet() error = %v", err)
+	}
+	waitForSubscriptions(t, client, testWallet, 1)
+
+	if err := wt.RemoveWallet(ctx, testWallet, testUser); err != nil {
+		t.Fatalf("RemoveWallet() error = %v", err)
+	}
+	err := wt.RemoveWallet(ctx, testWallet, testUser)
+	if !errors.Is(err, domain.ErrWalletNotFound) {
+		t.Fatalf("second RemoveWallet() error = %v, want %v", err, domain.ErrWalletNotFound)
+	}
+
+	if repo.removals != 1 {
+		t.Errorf("repository removals = %d, want 1", repo.removals)
+	}
+	if wallets, _ := repo.GetAllWallets(ctx); len(wallets) != 0 {
+		t.Errorf("stored wallets = %v, want none", wallets)
+	}
+	if got := wt.Status().ActiveListeners; got != 0 {
+		t.Errorf("active listeners = %d, want 0", got)
+	}
+	waitForSubscriptions(t, client, testWallet, 0)
+}
+
+func TestAddWalletFailingToPersistKeepsEvictionCandidate(t *testing.T) {
+	wt, repo, client := newTestWalletTracker(t)
+	wt.guard = NewListenerGuard(1, 0, EvictLRUPolicy)
+	ctx := context.Background()
+
+	if err := wt.AddWallet(ctx, testWallet, testUser, nil, ""); err != nil {
+		t.Fatalf("AddWallet() error = %v", err)
+	}
+	waitForSubscriptions(t, client, testWallet, 1)
+
+	repo.addErr = errors.New("repository down")
+	other := domain.WalletAddress("0x000000000000000000000000000000000000dEaD")
+	if err := wt.AddWallet(ctx, other, testUser, nil, ""); err == nil {
+		t.Fatal("AddWallet() error = nil, want the persist failure")
+	}
+
+	if repo.removals != 0 {
+		t.Errorf("repository removals = %d, want none", repo.removals)
+	}
+	if subscribers, _ := repo.GetSubscribers(ctx, testWallet); len(subscribers) != 1 {
+		t.Errorf("stored subscribers = %v, want the first wallet kept", subscribers)
+	}
+	if got := wt.Status().Subscribers[testWallet]; got != 1 {
+		t.Errorf("subscribers = %d, want 1", got)
+	}
+	waitForSubscriptions(t, client, testWallet, 1)
+}
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/say8hi/plasma-wallet-tracker/internal/domain"
+	"github.com/say8hi/plasma-wallet-tracker/internal/metrics"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Maximum number of webhooks a single user can have
+	maxWebhooksPerUser = 10
+	maxWebhookURLLen   = 2048
+
+	// Delay before the first retry, doubled for each one after up to the max
+	webhookRetryDelay    = time.Second
+	maxWebhookRetryDelay = time.Minute
+
+	// Dead letters returned by list_webhook_failures
+	webhookFailuresLimit = 50
+)
+
+// WebhookConfig tunes webhook delivery
+type WebhookConfig struct {
+	Workers     int // Concurrent deliveries
+	QueueSize   int // Deliveries waiting for a worker; beyond that they are dead-lettered
+	MaxAttempts int // Attempts per delivery, including the first
+}
+
+// webhookDelivery is a notification on its way to one webhook
+type webhookDelivery struct {
+	webhook      domain.Webhook
+	notification domain.WalletNotification
+}
+
+// WebhookDispatcher is a sink POSTing each wallet notification to the
+// matching webhooks of its subscribers. Failed deliveries are retried with
+// exponential backoff and dead-lettered once they run out of attempts or
+// are rejected for good.
+type WebhookDispatcher struct {
+	discardPublisher
+
+	repo   domain.WebhookRepository
+	sender domain.WebhookSender
+	config WebhookConfig
+	queue  chan webhookDelivery
+	logger *zap.Logger
+
+	// Deliveries taken off the queue and not finished yet
+	inFlight atomic.Int64
+
+	// Webhooks map: webhook id -> webhook
+	webhooks map[string]domain.Webhook
+	mu       sync.RWMutex
+}
+
+func NewWebhookDispatcher(
+	repo domain.WebhookRepository,
+	sender domain.WebhookSender,
+	config WebhookConfig,
+	logger *zap.Logger,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:     repo,
+		sender:   sender,
+		config:   config,
+		queue:    make(chan webhookDelivery, config.QueueSize),
+		logger:   logger,
+		webhooks: make(map[string]domain.Webhook),
+	}
+}
+
+// Load reads all persisted webhooks into memory
+func (wd *WebhookDispatcher) Load(ctx context.Context) error {
+	webhooks, err := wd.repo.GetAllWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load webhooks: %w", err)
+	}
+
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	for _, webhook := range webhooks {
+		wd.webhooks[webhook.ID] = webhook
+	}
+
+	wd.logger.Info("Loaded webhooks", zap.Int("count", len(webhooks)))
+	return nil
+}
+
+// Start runs the delivery workers until ctx is done. Deliveries still
+// queued or waiting for a retry then are dead-lettered.
+func (wd *WebhookDispatcher) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range wd.config.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case delivery := <-wd.queue:
+					wd.inFlight.Add(1)
+					wd.deliver(ctx, delivery)
+					wd.inFlight.Add(-1)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for {
+		select {
+		case delivery := <-wd.queue:
+			wd.deadLetter(delivery, 0, 0, errors.New("not delivered before shutdown"))
+		default:
+			return
+		}
+	}
+}
+
+// Drain waits until queued deliveries are done or ctx is done. Call it once
+// nothing publishes anymore.
+func (wd *WebhookDispatcher) Drain(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for len(wd.queue) > 0 || wd.inFlight.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Register adds a webhook for userID, for one of their wallets if
+// walletAddress is set. The returned webhook holds its signing secret.
+func (wd *WebhookDispatcher) Register(
+	ctx context.Context,
+	userID domain.UserID,
+	rawURL string,
+	walletAddress domain.WalletAddress,
+) (*domain.Webhook, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	webhook := domain.Webhook{
+		ID:            randomHex(8),
+		UserID:        userID,
+		URL:           rawURL,
+		WalletAddress: walletAddress,
+		Secret:        randomHex(32),
+		CreatedAt:     time.Now(),
+	}
+
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	count := 0
+	for _, existing := range wd.webhooks {
+		if existing.UserID == userID {
+			count++
+		}
+	}
+	if count >= maxWebhooksPerUser {
+		return nil, fmt.Errorf("%w: at most %d webhooks per user", domain.ErrQuotaExceeded, maxWebhooksPerUser)
+	}
+
+	if err := wd.repo.SaveWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to store webhook: %w", err)
+	}
+	wd.webhooks[webhook.ID] = webhook
+
+	wd.logger.Info("Registered webhook",
+		zap.String("webhook_id", webhook.ID),
+		zap.Int64("user_id", int64(userID)),
+		zap.String("wallet", string(walletAddress)),
+	)
+	return &webhook, nil
+}
+
+// List returns the webhooks of userID without their secrets, oldest first
+func (wd *WebhookDispatcher) List(userID domain.UserID) []domain.Webhook {
+	wd.mu.RLock()
+	defer wd.mu.RUnlock()
+
+	webhooks := make([]domain.Webhook, 0)
+	for _, webhook := range wd.webhooks {
+		if webhook.UserID == userID {
+			webhook.Secret = ""
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	slices.SortFunc(webhooks, func(a, b domain.Webhook) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return webhooks
+}
+
+// Delete removes a webhook owned by userID
+func (wd *WebhookDispatcher) Delete(ctx context.Context, userID domain.UserID, id string) error {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	webhook, ok := wd.webhooks[id]
+	if !ok || webhook.UserID != userID {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookNotFound, id)
+	}
+
+	if err := wd.repo.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	delete(wd.webhooks, id)
+
+	wd.logger.Info("Deleted webhook",
+		zap.String("webhook_id", id),
+		zap.Int64("user_id", int64(userID)),
+	)
+	return nil
+}
+
+// Failures returns the latest dead-lettered deliveries of userID
+func (wd *WebhookDispatcher) Failures(ctx context.Context, userID domain.UserID) ([]domain.WebhookDeadLetter, error) {
+	deadLetters, err := wd.repo.GetDeadLetters(ctx, userID, webhookFailuresLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook failures: %w", err)
+	}
+	return deadLetters, nil
+}
+
+// PublishNotification queues notification for the webhooks of its
+// subscribers
+func (wd *WebhookDispatcher) PublishNotification(ctx context.Context, notification domain.WalletNotification) error {
+	for _, webhook := range wd.webhooksFor(notification) {
+		delivery := webhookDelivery{
+			webhook:      webhook,
+			notification: notificationFor(notification, webhook.UserID),
+		}
+		select {
+		case wd.queue <- delivery:
+		default:
+			metrics.WebhookDeliveriesTotal.WithLabelValues("queue_full").Inc()
+			wd.deadLetter(delivery, 0, 0, errors.New("delivery queue full"))
+		}
+	}
+	return nil
+}
+
+// webhooksFor returns the webhooks notification should be sent to
+func (wd *WebhookDispatcher) webhooksFor(notification domain.WalletNotification) []domain.Webhook {
+	wd.mu.RLock()
+	defer wd.mu.RUnlock()
+
+	var webhooks []domain.Webhook
+	for _, webhook := range wd.webhooks {
+		if webhook.WalletAddress != "" && webhook.WalletAddress != notification.WalletAddress {
+			continue
+		}
+		if slices.Contains(notification.Subscribers, webhook.UserID) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks
+}
+
+// deliver posts delivery until the endpoint accepts it, rejects it for
+// good or the attempts run out
+func (wd *WebhookDispatcher) deliver(ctx context.Context, delivery webhookDelivery) {
+	payload, err := json.Marshal(delivery.notification)
+	if err != nil {
+		wd.deadLetter(delivery, 0, 0, fmt.Errorf("failed to marshal notification: %w", err))
+		return
+	}
+
+	delay := webhookRetryDelay
+	for attempt := 1; ; attempt++ {
+		status, err := wd.sender.Send(ctx, delivery.webhook, payload, delivery.notification.IdempotencyKey)
+		if err == nil && status >= 200 && status < 300 {
+			metrics.WebhookDeliveriesTotal.WithLabelValues("ok").Inc()
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("endpoint answered %d", status)
+		}
+
+		if !retryableWebhookStatus(status) || attempt >= wd.config.MaxAttempts {
+			metrics.WebhookDeliveriesTotal.WithLabelValues("failed").Inc()
+			wd.deadLetter(delivery, attempt, status, err)
+			return
+		}
+
+		metrics.WebhookDeliveriesTotal.WithLabelValues("retried").Inc()
+		wd.logger.Debug("Retrying webhook delivery",
+			zap.String("webhook_id", delivery.webhook.ID),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			wd.deadLetter(delivery, attempt, status, fmt.Errorf("shut down while retrying: %w", err))
+			return
+		}
+		delay = min(delay*2, maxWebhookRetryDelay)
+	}
+}
+
+// deadLetter records a delivery that won't be retried
+func (wd *WebhookDispatcher) deadLetter(delivery webhookDelivery, attempts, status int, cause error) {
+	wd.logger.Warn("Webhook delivery failed",
+		zap.String("webhook_id", delivery.webhook.ID),
+		zap.Int64("user_id", int64(delivery.webhook.UserID)),
+		zap.Int("attempts", attempts),
+		zap.Int("status", status),
+		zap.Error(cause),
+	)
+
+	// Recorded even when the delivery was cut short by shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := wd.repo.AddDeadLetter(ctx, domain.WebhookDeadLetter{
+		WebhookID:    delivery.webhook.ID,
+		UserID:       delivery.webhook.UserID,
+		URL:          delivery.webhook.URL,
+		Notification: delivery.notification,
+		Attempts:     attempts,
+		StatusCode:   status,
+		Error:        cause.Error(),
+		FailedAt:     time.Now(),
+	})
+	if err != nil {
+		wd.logger.Error("Failed to record webhook dead letter",
+			zap.String("webhook_id", delivery.webhook.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// retryableWebhookStatus reports whether a delivery answered with status,
+// or not answered at all (0), may succeed later
+func retryableWebhookStatus(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests || status >= 500
+}
+
+func validateWebhookURL(rawURL string) error {
+	if len(rawURL) > maxWebhookURLLen {
+		return fmt.Errorf("%w: url longer than %d characters", domain.ErrInvalidWebhook, maxWebhookURLLen)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidWebhook, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: %q is not an absolute http(s) url", domain.ErrInvalidWebhook, rawURL)
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("%w: credentials in url", domain.ErrInvalidWebhook)
+	}
+	return nil
+}